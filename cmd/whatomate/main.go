@@ -459,10 +459,18 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 		g.POST("/api/auth/refresh", withRateLimit(app.RefreshToken, middleware.RateLimitOpts{
 			Redis: rdb, Log: lo, Max: cfg.RateLimit.RefreshMaxAttempts, Window: window, KeyPrefix: "refresh", TrustProxy: cfg.RateLimit.TrustProxy,
 		}))
+		g.POST("/api/auth/password-reset/request", withRateLimit(app.RequestPasswordReset, middleware.RateLimitOpts{
+			Redis: rdb, Log: lo, Max: cfg.RateLimit.LoginMaxAttempts, Window: window, KeyPrefix: "password_reset_request", TrustProxy: cfg.RateLimit.TrustProxy,
+		}))
+		g.POST("/api/auth/password-reset", withRateLimit(app.ResetPassword, middleware.RateLimitOpts{
+			Redis: rdb, Log: lo, Max: cfg.RateLimit.LoginMaxAttempts, Window: window, KeyPrefix: "password_reset", TrustProxy: cfg.RateLimit.TrustProxy,
+		}))
 	} else {
 		g.POST("/api/auth/login", app.Login)
 		g.POST("/api/auth/register", app.Register)
 		g.POST("/api/auth/refresh", app.RefreshToken)
+		g.POST("/api/auth/password-reset/request", app.RequestPasswordReset)
+		g.POST("/api/auth/password-reset", app.ResetPassword)
 	}
 	g.POST("/api/auth/logout", app.Logout)
 	g.POST("/api/auth/switch-org", app.SwitchOrg)
@@ -501,6 +509,7 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 		// Skip auth for public routes
 		if path == "/health" || path == "/ready" ||
 			path == "/api/auth/login" || path == "/api/auth/register" || path == "/api/auth/refresh" ||
+			path == "/api/auth/password-reset/request" || path == "/api/auth/password-reset" ||
 			path == "/api/auth/logout" || path == "/api/webhook" || path == "/ws" {
 			return r
 		}
@@ -543,9 +552,13 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 	// User Management (admin only - enforced by middleware)
 	g.GET("/api/users", app.ListUsers)
 	g.POST("/api/users", app.CreateUser)
+	g.POST("/api/users/bulk", app.BulkCreateUsers)
 	g.GET("/api/users/{id}", app.GetUser)
 	g.PUT("/api/users/{id}", app.UpdateUser)
 	g.DELETE("/api/users/{id}", app.DeleteUser)
+	g.PUT("/api/users/{id}/password", app.SetUserPassword)
+	g.GET("/api/users/{id}/schedule", app.GetUserSchedule)
+	g.PUT("/api/users/{id}/schedule", app.UpdateUserSchedule)
 
 	// Roles & Permissions (admin only - enforced by middleware)
 	g.GET("/api/roles", app.ListRoles)
@@ -553,6 +566,7 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 	g.GET("/api/roles/{id}", app.GetRole)
 	g.PUT("/api/roles/{id}", app.UpdateRole)
 	g.DELETE("/api/roles/{id}", app.DeleteRole)
+	g.POST("/api/roles/{id}/clone", app.CloneRole)
 	g.GET("/api/permissions", app.ListPermissions)
 
 	// API Keys (admin only - enforced by middleware)
@@ -567,6 +581,7 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 	g.PUT("/api/accounts/{id}", app.UpdateAccount)
 	g.DELETE("/api/accounts/{id}", app.DeleteAccount)
 	g.POST("/api/accounts/{id}/test", app.TestAccountConnection)
+	g.POST("/api/accounts/{id}/access-token/rotate", app.RotateAccountToken)
 	g.POST("/api/accounts/{id}/subscribe", app.SubscribeApp)
 	g.GET("/api/accounts/{id}/business_profile", app.GetBusinessProfile)
 	g.PUT("/api/accounts/{id}/business_profile", app.UpdateBusinessProfile)
@@ -575,12 +590,29 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 	// Contacts
 	g.GET("/api/contacts", app.ListContacts)
 	g.POST("/api/contacts", app.CreateContact)
+	g.POST("/api/contacts/bulk-delete", app.BulkDeleteContacts)
+	g.POST("/api/contacts/bulk-tags", app.BulkTagContacts)
+	g.POST("/api/contacts/bulk-conversation-status", app.BulkUpdateConversationStatus)
+	g.GET("/api/contacts/tag-operations", app.ListContactTagOperations)
+	g.GET("/api/contacts/export", app.ExportContacts)
+	g.POST("/api/contacts/import", app.ImportContacts)
+	g.GET("/api/contacts/unread-summary", app.GetUnreadSummary)
+	g.GET("/api/contacts/opted-out", app.ListOptedOutContacts)
 	g.GET("/api/contacts/{id}", app.GetContact)
 	g.PUT("/api/contacts/{id}", app.UpdateContact)
 	g.DELETE("/api/contacts/{id}", app.DeleteContact)
 	g.PUT("/api/contacts/{id}/assign", app.AssignContact)
+	g.GET("/api/contacts/{id}/assignments", app.GetContactAssignmentHistory)
 	g.PUT("/api/contacts/{id}/tags", app.UpdateContactTags)
+	g.PUT("/api/contacts/{id}/custom-fields", app.UpdateContactCustomFields)
 	g.GET("/api/contacts/{id}/session-data", app.GetContactSessionData)
+	g.POST("/api/contacts/{id}/bot/pause", app.PauseChatbotForContact)
+	g.POST("/api/contacts/{id}/bot/resume", app.ResumeChatbotForContact)
+	g.PUT("/api/contacts/{id}/conversation-status", app.UpdateConversationStatus)
+	g.POST("/api/contacts/{id}/opt-in", app.RecordOptIn)
+	g.POST("/api/contacts/{id}/block", app.BlockContact)
+	g.POST("/api/contacts/{id}/unblock", app.UnblockContact)
+	g.DELETE("/api/contacts/{id}/flood-flag", app.ClearContactFloodFlag)
 
 	// Generic Import/Export
 	g.POST("/api/export", app.ExportData)
@@ -594,10 +626,28 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 	g.PUT("/api/tags/{name}", app.UpdateTag)
 	g.DELETE("/api/tags/{name}", app.DeleteTag)
 
+	// Contact Custom Fields
+	g.GET("/api/contact-fields", app.ListContactCustomFields)
+	g.POST("/api/contact-fields", app.CreateContactCustomField)
+	g.PUT("/api/contact-fields/{id}", app.UpdateContactCustomField)
+	g.DELETE("/api/contact-fields/{id}", app.DeleteContactCustomField)
+
+	// Phone blacklist
+	g.GET("/api/phone-blacklist", app.ListPhoneBlacklist)
+	g.POST("/api/phone-blacklist", app.CreatePhoneBlacklist)
+	g.DELETE("/api/phone-blacklist/{id}", app.DeletePhoneBlacklist)
+
 	// Messages
 	g.GET("/api/contacts/{id}/messages", app.GetMessages)
 	g.POST("/api/contacts/{id}/messages", app.SendMessage)
+	g.GET("/api/contacts/{id}/transcript", app.ExportConversation)
+	g.POST("/api/contacts/{id}/messages/interactive", app.SendInteractiveMessage)
+	g.POST("/api/contacts/{id}/typing", app.SendTypingIndicator)
+	g.GET("/api/contacts/{id}/scheduled-messages", app.ListScheduledMessages)
+	g.POST("/api/contacts/{id}/scheduled-messages", app.ScheduleMessage)
+	g.DELETE("/api/contacts/{id}/scheduled-messages/{scheduled_id}", app.CancelScheduledMessage)
 	g.POST("/api/contacts/{id}/messages/{message_id}/reaction", app.SendReaction)
+	g.DELETE("/api/contacts/{id}/messages/{message_id}", app.DeleteMessage)
 	g.POST("/api/messages", app.SendMessage) // Legacy route
 	g.POST("/api/messages/template", app.SendTemplateMessage)
 	g.POST("/api/messages/media", app.SendMediaMessage)
@@ -609,6 +659,13 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 	g.PUT("/api/contacts/{id}/notes/{note_id}", app.UpdateConversationNote)
 	g.DELETE("/api/contacts/{id}/notes/{note_id}", app.DeleteConversationNote)
 
+	// Saved inbox views (per user)
+	g.GET("/api/saved-views", app.ListSavedViews)
+	g.POST("/api/saved-views", app.CreateSavedView)
+	g.PUT("/api/saved-views/{id}", app.UpdateSavedView)
+	g.DELETE("/api/saved-views/{id}", app.DeleteSavedView)
+	g.GET("/api/saved-views/{id}/contacts", app.ApplySavedView)
+
 	// Media (serves media files for messages, auth-protected)
 	g.GET("/api/media/{message_id}", app.ServeMedia)
 
@@ -645,6 +702,7 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 	g.POST("/api/campaigns/{id}/cancel", app.CancelCampaign)
 	g.POST("/api/campaigns/{id}/retry-failed", app.RetryFailed)
 	g.GET("/api/campaigns/{id}/progress", app.GetCampaign)
+	g.GET("/api/campaigns/{id}/queue", app.GetCampaignQueueStatus)
 	g.POST("/api/campaigns/{id}/recipients/import", app.ImportRecipients)
 	g.GET("/api/campaigns/{id}/recipients", app.GetCampaignRecipients)
 	g.DELETE("/api/campaigns/{id}/recipients/{recipientId}", app.DeleteCampaignRecipient)
@@ -654,10 +712,16 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 	// Chatbot Settings
 	g.GET("/api/chatbot/settings", app.GetChatbotSettings)
 	g.PUT("/api/chatbot/settings", app.UpdateChatbotSettings)
+	g.POST("/api/chatbot/settings/ai-key/rotate", app.RotateAIKey)
+	g.GET("/api/chatbot/settings/accounts/{account}", app.GetChatbotAccountSettings)
+	g.PUT("/api/chatbot/settings/accounts/{account}", app.UpdateChatbotAccountSettings)
+	g.DELETE("/api/chatbot/settings/accounts/{account}", app.DeleteChatbotAccountSettings)
 
 	// Keyword Rules
 	g.GET("/api/chatbot/keywords", app.ListKeywordRules)
 	g.POST("/api/chatbot/keywords", app.CreateKeywordRule)
+	g.POST("/api/chatbot/keywords/import", app.ImportKeywordRulesCSV)
+	g.GET("/api/chatbot/keywords/stats", app.GetKeywordRuleStats)
 	g.GET("/api/chatbot/keywords/{id}", app.GetKeywordRule)
 	g.PUT("/api/chatbot/keywords/{id}", app.UpdateKeywordRule)
 	g.DELETE("/api/chatbot/keywords/{id}", app.DeleteKeywordRule)
@@ -665,16 +729,25 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 	// Chatbot Flows
 	g.GET("/api/chatbot/flows", app.ListChatbotFlows)
 	g.POST("/api/chatbot/flows", app.CreateChatbotFlow)
+	g.POST("/api/chatbot/flows/import", app.ImportChatbotFlow)
 	g.GET("/api/chatbot/flows/{id}", app.GetChatbotFlow)
+	g.GET("/api/chatbot/flows/{id}/export", app.ExportChatbotFlow)
 	g.PUT("/api/chatbot/flows/{id}", app.UpdateChatbotFlow)
+	g.PUT("/api/chatbot/flows/{id}/steps/{step_id}", app.UpdateChatbotFlowStep)
+	g.GET("/api/chatbot/flows/{id}/steps/{step_id}/answers", app.GetStepAnswerDistribution)
+	g.POST("/api/chatbot/flows/{id}/simulate", app.SimulateChatbotFlow)
+	g.GET("/api/chatbot/flows/{id}/webhook-deliveries", app.ListFlowWebhookDeliveries)
 	g.DELETE("/api/chatbot/flows/{id}", app.DeleteChatbotFlow)
+	g.POST("/api/chatbot/preview-send", app.PreviewSendToNumber)
 
 	// AI Contexts
 	g.GET("/api/chatbot/ai-contexts", app.ListAIContexts)
 	g.POST("/api/chatbot/ai-contexts", app.CreateAIContext)
+	g.POST("/api/chatbot/ai-contexts/preview", app.PreviewAIContext)
 	g.GET("/api/chatbot/ai-contexts/{id}", app.GetAIContext)
 	g.PUT("/api/chatbot/ai-contexts/{id}", app.UpdateAIContext)
 	g.DELETE("/api/chatbot/ai-contexts/{id}", app.DeleteAIContext)
+	g.POST("/api/chatbot/ai-contexts/{id}/test", app.TestAIContext)
 
 	// Agent Transfers
 	g.GET("/api/chatbot/transfers", app.ListAgentTransfers)
@@ -696,22 +769,33 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 	// Canned Responses
 	g.GET("/api/canned-responses", app.ListCannedResponses)
 	g.POST("/api/canned-responses", app.CreateCannedResponse)
+	g.POST("/api/canned-responses/bulk-recategorize", app.BulkRecategorizeCannedResponses)
+	g.GET("/api/canned-responses/by-shortcut", app.GetCannedResponseByShortcut)
 	g.GET("/api/canned-responses/{id}", app.GetCannedResponse)
 	g.PUT("/api/canned-responses/{id}", app.UpdateCannedResponse)
 	g.DELETE("/api/canned-responses/{id}", app.DeleteCannedResponse)
 	g.POST("/api/canned-responses/{id}/use", app.IncrementCannedResponseUsage)
+	g.POST("/api/canned-responses/{id}/render", app.RenderCannedResponse)
 
 	// Sessions (admin/debug)
 	g.GET("/api/chatbot/sessions", app.ListChatbotSessions)
+	g.POST("/api/chatbot/sessions/maintenance", app.RunSessionMaintenance)
 	g.GET("/api/chatbot/sessions/{id}", app.GetChatbotSession)
+	g.POST("/api/chatbot/sessions/{id}/transfer", app.TransferChatbotSession)
 
 	// Analytics
 	g.GET("/api/analytics/dashboard", app.GetDashboardStats)
 	g.GET("/api/analytics/messages", app.GetMessageAnalytics)
 	g.GET("/api/analytics/chatbot", app.GetChatbotAnalytics)
+	g.GET("/api/analytics/contacts/acquisition", app.GetContactAcquisitionStats)
+	g.GET("/api/analytics/automation/export", app.ExportAutomationReport)
+	g.GET("/api/analytics/flows", app.GetFlowAnalytics)
 	g.GET("/api/analytics/agents", app.GetAgentAnalytics)
 	g.GET("/api/analytics/agents/{id}", app.GetAgentDetails)
 	g.GET("/api/analytics/agents/comparison", app.GetAgentComparison)
+	g.GET("/api/analytics/agents/workload", app.GetAgentWorkload)
+	g.GET("/api/analytics/ai-usage", app.GetAIUsage)
+	g.GET("/api/analytics/sla-breaches", app.ListSLABreaches)
 
 	// Meta WhatsApp Analytics
 	g.GET("/api/analytics/meta", app.GetMetaAnalytics)
@@ -755,6 +839,8 @@ func setupRoutes(g *fastglue.Fastglue, app *handlers.App, lo logf.Logger, basePa
 	g.PUT("/api/webhooks/{id}", app.UpdateWebhook)
 	g.DELETE("/api/webhooks/{id}", app.DeleteWebhook)
 	g.POST("/api/webhooks/{id}/test", app.TestWebhook)
+	g.GET("/api/webhooks/{id}/deliveries", app.ListWebhookDeliveries)
+	g.POST("/api/webhooks/{id}/deliveries/{delivery_id}/redeliver", app.RedeliverWebhook)
 
 	// Custom Actions
 	g.GET("/api/custom-actions", app.ListCustomActions)
@@ -16,6 +16,8 @@ export interface Contact {
   service_window_open?: boolean
   unread_count: number
   assigned_user_id?: string
+  assigned_user_name?: string | null
+  assigned_user_available?: boolean | null
   whatsapp_account?: string
   created_at: string
   updated_at: string
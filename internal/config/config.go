@@ -24,6 +24,7 @@ type Config struct {
 	Cookie        CookieConfig        `koanf:"cookie"`
 	Calling       CallingConfig       `koanf:"calling"`
 	TTS           TTSConfig           `koanf:"tts"`
+	PasswordReset PasswordResetConfig `koanf:"password_reset"`
 }
 
 type TTSConfig struct {
@@ -57,6 +58,8 @@ type AppConfig struct {
 	Environment   string `koanf:"environment"` // development, staging, production
 	Debug         bool   `koanf:"debug"`
 	EncryptionKey string `koanf:"encryption_key"` // AES-256 key for encrypting secrets at rest
+	// Days outbound webhook delivery logs are kept (default: 30, negative keeps them forever)
+	WebhookDeliveryRetentionDays int `koanf:"webhook_delivery_retention_days"`
 }
 
 type ServerConfig struct {
@@ -97,12 +100,29 @@ type WhatsAppConfig struct {
 	WebhookVerifyToken string `koanf:"webhook_verify_token"`
 	APIVersion         string `koanf:"api_version"`
 	BaseURL            string `koanf:"base_url"` // Meta Graph API base URL
+
+	ReadReceiptMaxAttempts  int `koanf:"read_receipt_max_attempts"`   // Attempts per read receipt before giving up (default: 3)
+	ReadReceiptRetryDelayMs int `koanf:"read_receipt_retry_delay_ms"` // Initial backoff between attempts, doubled each retry (default: 1000)
+
+	// Region (ISO 3166 code, e.g. "IN") for phone numbers entered without a
+	// country code. Empty requires every number to include one.
+	DefaultPhoneRegion string `koanf:"default_phone_region"`
 }
 
 type AIConfig struct {
 	OpenAIKey    string `koanf:"openai_key"`
 	AnthropicKey string `koanf:"anthropic_key"`
 	GoogleKey    string `koanf:"google_key"`
+	// Prices used for AI usage cost estimates, overriding the built-in list prices
+	ModelPrices []AIModelPriceConfig `koanf:"model_prices"`
+}
+
+// AIModelPriceConfig is the USD price per million tokens for models whose
+// name starts with Model
+type AIModelPriceConfig struct {
+	Model      string  `koanf:"model"`
+	Prompt     float64 `koanf:"prompt"`
+	Completion float64 `koanf:"completion"`
 }
 
 type StorageConfig struct {
@@ -125,6 +145,14 @@ type CookieConfig struct {
 	Secure bool   `koanf:"secure"` // Set Secure flag. Auto-set true when environment=production.
 }
 
+// PasswordResetConfig sets where password reset tokens are delivered. The
+// receiver is run by the server operator and is responsible for emailing the
+// token to the user. Password reset is disabled while DeliveryURL is empty.
+type PasswordResetConfig struct {
+	DeliveryURL    string `koanf:"delivery_url"`
+	DeliverySecret string `koanf:"delivery_secret"` // Signs payloads in X-Webhook-Signature
+}
+
 type RateLimitConfig struct {
 	Enabled             bool `koanf:"enabled"`
 	LoginMaxAttempts    int  `koanf:"login_max_attempts"`
@@ -133,6 +161,12 @@ type RateLimitConfig struct {
 	SSOMaxAttempts      int  `koanf:"sso_max_attempts"`
 	WindowSeconds       int  `koanf:"window_seconds"`
 	TrustProxy          bool `koanf:"trust_proxy"`
+	// Failed logins for one email from one IP before lockout; an IP is locked
+	// out for every email after four times as many. Each further failure
+	// doubles the lockout, up to LoginLockoutMaxSeconds.
+	LoginLockoutThreshold  int `koanf:"login_lockout_threshold"`
+	LoginLockoutSeconds    int `koanf:"login_lockout_seconds"`
+	LoginLockoutMaxSeconds int `koanf:"login_lockout_max_seconds"`
 }
 
 // Load loads configuration from file and environment variables
@@ -172,6 +206,9 @@ func setDefaults(cfg *Config) {
 	if cfg.App.Environment == "" {
 		cfg.App.Environment = "development"
 	}
+	if cfg.App.WebhookDeliveryRetentionDays == 0 {
+		cfg.App.WebhookDeliveryRetentionDays = 30
+	}
 	if cfg.Server.Host == "" {
 		cfg.Server.Host = "0.0.0.0"
 	}
@@ -214,6 +251,12 @@ func setDefaults(cfg *Config) {
 	if cfg.WhatsApp.BaseURL == "" {
 		cfg.WhatsApp.BaseURL = "https://graph.facebook.com"
 	}
+	if cfg.WhatsApp.ReadReceiptMaxAttempts == 0 {
+		cfg.WhatsApp.ReadReceiptMaxAttempts = 3
+	}
+	if cfg.WhatsApp.ReadReceiptRetryDelayMs == 0 {
+		cfg.WhatsApp.ReadReceiptRetryDelayMs = 1000
+	}
 	if cfg.Storage.Type == "" {
 		cfg.Storage.Type = "local"
 	}
@@ -250,6 +293,15 @@ func setDefaults(cfg *Config) {
 	if cfg.RateLimit.WindowSeconds == 0 {
 		cfg.RateLimit.WindowSeconds = 60
 	}
+	if cfg.RateLimit.LoginLockoutThreshold == 0 {
+		cfg.RateLimit.LoginLockoutThreshold = 5
+	}
+	if cfg.RateLimit.LoginLockoutSeconds == 0 {
+		cfg.RateLimit.LoginLockoutSeconds = 30
+	}
+	if cfg.RateLimit.LoginLockoutMaxSeconds == 0 {
+		cfg.RateLimit.LoginLockoutMaxSeconds = 3600
+	}
 	// Calling defaults
 	if cfg.Calling.MaxCallDuration == 0 {
 		cfg.Calling.MaxCallDuration = 300
@@ -1,57 +1,173 @@
 package contactutil
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/google/uuid"
+	"github.com/nyaruka/phonenumbers"
 	"github.com/shridarpatil/whatomate/internal/models"
 	"gorm.io/gorm"
 )
 
+// PhoneDigitsExpr is the SQL equivalent of NormalizePhone for plain phone
+// numbers. It matches the expression index idx_contacts_org_phone_digits.
+const PhoneDigitsExpr = `regexp_replace(phone_number, '[^0-9]', '', 'g')`
+
+// Errors returned by ValidatePhone
+var (
+	ErrPhoneRequired      = errors.New("phone number is required")
+	ErrPhoneInvalidChars  = errors.New("phone number may only contain digits, a leading + and spaces, dashes, dots or parentheses")
+	ErrPhoneInvalid       = errors.New("phone number is not a valid number for its country")
+	ErrPhoneNoCountryCode = errors.New("phone number must start with a country code")
+)
+
+// phoneFormatChars are the formatting characters allowed in a phone number
+const phoneFormatChars = " -().\t"
+
+// NormalizePhone reduces a phone number to its digits, so "+1 (555) 010-2030"
+// and "15550102030" compare equal. Group JIDs (containing "@") are returned
+// unchanged apart from surrounding whitespace.
+func NormalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	if strings.Contains(phone, "@") {
+		return phone
+	}
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+// IsPhoneBlacklisted reports whether the organization has blacklisted the phone
+// number, however either side was formatted.
+func IsPhoneBlacklisted(db *gorm.DB, orgID uuid.UUID, phone string) (bool, error) {
+	phone = NormalizePhone(phone)
+	if phone == "" {
+		return false, nil
+	}
+	var count int64
+	err := db.Model(&models.PhoneBlacklist{}).
+		Where("organization_id = ? AND (phone_number = ? OR "+PhoneDigitsExpr+" = ?)", orgID, phone, phone).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ValidatePhone normalizes a user-supplied phone number to E.164 digits
+// (without the leading "+") and rejects numbers that aren't valid according
+// to libphonenumber's numbering plans. Numbers are read as international,
+// with or without the "+". When defaultRegion (an ISO 3166 code such as "IN")
+// is set, numbers without a "+" are first read as national numbers of that
+// region, so "09876 543210" becomes "919876543210".
+func ValidatePhone(phone, defaultRegion string) (string, error) {
+	phone = strings.TrimSpace(phone)
+	if phone == "" {
+		return "", ErrPhoneRequired
+	}
+	for i, r := range phone {
+		if (r >= '0' && r <= '9') || strings.ContainsRune(phoneFormatChars, r) || (r == '+' && i == 0) {
+			continue
+		}
+		return "", ErrPhoneInvalidChars
+	}
+
+	digits := NormalizePhone(phone)
+	if digits == "" {
+		return "", ErrPhoneInvalid
+	}
+	if defaultRegion != "" && !strings.HasPrefix(phone, "+") {
+		if e164, ok := parseValidPhone(digits, strings.ToUpper(defaultRegion)); ok {
+			return e164, nil
+		}
+	}
+	if e164, ok := parseValidPhone("+"+digits, ""); ok {
+		return e164, nil
+	}
+	if digits[0] == '0' && defaultRegion == "" {
+		return "", ErrPhoneNoCountryCode
+	}
+	return "", ErrPhoneInvalid
+}
+
+// parseValidPhone parses number in region and returns its E.164 digits if it is a valid number
+func parseValidPhone(number, region string) (string, bool) {
+	parsed, err := phonenumbers.Parse(number, region)
+	if err != nil || !phonenumbers.IsValidNumber(parsed) {
+		return "", false
+	}
+	return strings.TrimPrefix(phonenumbers.Format(parsed, phonenumbers.E164), "+"), true
+}
+
+// PhoneSearchDigits returns the digits of a search term that looks like (part
+// of) a phone number, so formatted searches like "+1 (555) 010" can be matched
+// against PhoneDigitsExpr. It returns "" for other search terms.
+func PhoneSearchDigits(search string) string {
+	search = strings.TrimSpace(search)
+	hasDigit := false
+	for i, r := range search {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case strings.ContainsRune(phoneFormatChars, r) || (r == '+' && i == 0):
+		default:
+			return ""
+		}
+	}
+	if !hasDigit {
+		return ""
+	}
+	return NormalizePhone(search)
+}
+
+// FindContactByPhone looks up a contact by phone number regardless of how it
+// was formatted when stored, including soft-deleted contacts. phone must
+// already be normalized with NormalizePhone.
+func FindContactByPhone(db *gorm.DB, orgID uuid.UUID, phone string) (*models.Contact, error) {
+	// Cheapest lookups first
+	var contact models.Contact
+	for _, candidate := range []string{phone, "+" + phone} {
+		if db.Unscoped().Where("organization_id = ? AND phone_number = ?", orgID, candidate).First(&contact).Error == nil {
+			return &contact, nil
+		}
+	}
+	if strings.Contains(phone, "@") {
+		return nil, gorm.ErrRecordNotFound
+	}
+	// Contacts created before numbers were normalized may keep spaces, dashes or brackets
+	if err := db.Unscoped().Where("organization_id = ? AND "+PhoneDigitsExpr+" = ?", orgID, phone).
+		Order("deleted_at IS NOT NULL, created_at").
+		First(&contact).Error; err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
+
 // GetOrCreateContact finds or creates a contact for the given phone number.
 // Merges behaviors from both handler and worker implementations:
-//   - Normalizes phone (strips leading "+")
-//   - Tries both normalized and +prefix forms
+//   - Normalizes phone to digits only (see NormalizePhone)
+//   - Tries the normalized and +prefix forms, then any stored format with the same digits
 //   - Updates profile name if changed
 //   - Handles race conditions on create by re-fetching
 //   - Restores soft-deleted contacts if found
 //
 // Returns the contact, whether it was newly created, and any error.
 func GetOrCreateContact(db *gorm.DB, orgID uuid.UUID, phoneNumber, profileName string) (*models.Contact, bool, error) {
-	// Normalize phone number (remove + prefix if present)
-	normalizedPhone := phoneNumber
-	if len(normalizedPhone) > 0 && normalizedPhone[0] == '+' {
-		normalizedPhone = normalizedPhone[1:]
-	}
+	normalizedPhone := NormalizePhone(phoneNumber)
 
-	// Try to find existing contact with normalized phone (including soft-deleted)
-	var contact models.Contact
-	if err := db.Unscoped().Where("organization_id = ? AND phone_number = ?", orgID, normalizedPhone).First(&contact).Error; err == nil {
-		// Restore if soft-deleted
-		if contact.DeletedAt.Valid {
-			db.Unscoped().Model(&contact).Update("deleted_at", nil)
-			contact.DeletedAt.Valid = false
-		}
+	// Try to find existing contact (including soft-deleted)
+	if existing, err := FindContactByPhone(db, orgID, normalizedPhone); err == nil {
+		restoreContact(db, existing)
 		// Update profile name if changed
-		if profileName != "" && contact.ProfileName != profileName {
-			db.Model(&contact).Update("profile_name", profileName)
-		}
-		return &contact, false, nil
-	}
-
-	// Also try with + prefix (contacts may have been stored with it)
-	if err := db.Unscoped().Where("organization_id = ? AND phone_number = ?", orgID, "+"+normalizedPhone).First(&contact).Error; err == nil {
-		// Restore if soft-deleted
-		if contact.DeletedAt.Valid {
-			db.Unscoped().Model(&contact).Update("deleted_at", nil)
-			contact.DeletedAt.Valid = false
-		}
-		if profileName != "" && contact.ProfileName != profileName {
-			db.Model(&contact).Update("profile_name", profileName)
+		if profileName != "" && existing.ProfileName != profileName {
+			db.Model(existing).Update("profile_name", profileName)
 		}
-		return &contact, false, nil
+		return existing, false, nil
 	}
 
 	// Create new contact
-	contact = models.Contact{
+	contact := models.Contact{
 		BaseModel:      models.BaseModel{ID: uuid.New()},
 		OrganizationID: orgID,
 		PhoneNumber:    normalizedPhone,
@@ -60,14 +176,18 @@ func GetOrCreateContact(db *gorm.DB, orgID uuid.UUID, phoneNumber, profileName s
 	if err := db.Create(&contact).Error; err != nil {
 		// Race condition: another goroutine may have created the contact
 		if err2 := db.Unscoped().Where("organization_id = ? AND phone_number = ?", orgID, normalizedPhone).First(&contact).Error; err2 == nil {
-			// Restore if soft-deleted
-			if contact.DeletedAt.Valid {
-				db.Unscoped().Model(&contact).Update("deleted_at", nil)
-				contact.DeletedAt.Valid = false
-			}
+			restoreContact(db, &contact)
 			return &contact, false, nil
 		}
 		return nil, false, err
 	}
 	return &contact, true, nil
 }
+
+// restoreContact clears the soft-delete marker on a contact found via an unscoped lookup
+func restoreContact(db *gorm.DB, contact *models.Contact) {
+	if contact.DeletedAt.Valid {
+		db.Unscoped().Model(contact).Update("deleted_at", nil)
+		contact.DeletedAt.Valid = false
+	}
+}
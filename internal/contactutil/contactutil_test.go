@@ -8,6 +8,7 @@ import (
 	"github.com/shridarpatil/whatomate/test/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestGetOrCreateContact_CreatesNew(t *testing.T) {
@@ -105,3 +106,140 @@ func TestGetOrCreateContact_UpdatesProfileName(t *testing.T) {
 	require.NoError(t, db.First(&reloaded, contact.ID).Error)
 	assert.Equal(t, "New Name", reloaded.ProfileName)
 }
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"15550102030", "15550102030"},
+		{"+15550102030", "15550102030"},
+		{" +1 (555) 010-2030 ", "15550102030"},
+		{"+44 20.7946.0958", "442079460958"},
+		{"120363422675615917@g.us", "120363422675615917@g.us"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NormalizePhone(tt.in), tt.in)
+	}
+}
+
+func TestGetOrCreateContact_MatchesFormattedNumber(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	uid := uuid.New().String()[:8]
+	org := models.Organization{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "test-" + uid, Slug: "test-" + uid}
+	require.NoError(t, db.Create(&org).Error)
+
+	// Stored the way it was typed into an import sheet
+	existing := models.Contact{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		PhoneNumber:    "+1 (555) 010-2030",
+		ProfileName:    "Dana",
+	}
+	require.NoError(t, db.Create(&existing).Error)
+
+	contact, isNew, err := GetOrCreateContact(db, org.ID, "15550102030", "Dana")
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, existing.ID, contact.ID)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Contact{}).Where("organization_id = ?", org.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestGetOrCreateContact_StoresDigitsOnly(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	uid := uuid.New().String()[:8]
+	org := models.Organization{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "test-" + uid, Slug: "test-" + uid}
+	require.NoError(t, db.Create(&org).Error)
+
+	contact, isNew, err := GetOrCreateContact(db, org.ID, "+1 555-010-2030", "Eve")
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	assert.Equal(t, "15550102030", contact.PhoneNumber)
+
+	again, isNew, err := GetOrCreateContact(db, org.ID, "15550102030", "Eve")
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, contact.ID, again.ID)
+}
+
+func TestValidatePhone(t *testing.T) {
+	tests := []struct {
+		in      string
+		region  string
+		want    string
+		wantErr error
+	}{
+		{"+1 (202) 555-0176", "", "12025550176", nil},
+		{"12025550176", "", "12025550176", nil},
+		{"+44 20.7946.0958", "", "442079460958", nil},
+		{"", "", "", ErrPhoneRequired},
+		{"1-800-FLOWERS", "", "", ErrPhoneInvalidChars},
+		{"1+2345678", "", "", ErrPhoneInvalidChars},
+		{"123456", "", "", ErrPhoneInvalid},
+		{"1234567890123456", "", "", ErrPhoneInvalid},
+		{"15550102030", "", "", ErrPhoneInvalid},
+		{"07946 0958 12", "", "", ErrPhoneNoCountryCode},
+		// National numbers are read in the default region
+		{"098765 43210", "IN", "919876543210", nil},
+		{"9876543210", "in", "919876543210", nil},
+		{"07946 095812", "GB", "447946095812", nil},
+		// International numbers still work with a default region
+		{"+44 20 7946 0958", "IN", "442079460958", nil},
+		{"12025550176", "IN", "12025550176", nil},
+	}
+	for _, tt := range tests {
+		got, err := ValidatePhone(tt.in, tt.region)
+		assert.Equal(t, tt.wantErr, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestPhoneSearchDigits(t *testing.T) {
+	assert.Equal(t, "1999888", PhoneSearchDigits("+1 (999) 888"))
+	assert.Equal(t, "7776", PhoneSearchDigits("7776"))
+	assert.Empty(t, PhoneSearchDigits("Alice"))
+	assert.Empty(t, PhoneSearchDigits("Agent 7"))
+	assert.Empty(t, PhoneSearchDigits("() -"))
+}
+
+func TestFindContactByPhone(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	uid := uuid.New().String()[:8]
+	org := models.Organization{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "test-" + uid, Slug: "test-" + uid}
+	require.NoError(t, db.Create(&org).Error)
+
+	existing := models.Contact{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		PhoneNumber:    "+1 (999) 888-7776",
+	}
+	require.NoError(t, db.Create(&existing).Error)
+
+	found, err := FindContactByPhone(db, org.ID, "19998887776")
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, found.ID)
+
+	_, err = FindContactByPhone(db, org.ID, "19998887777")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestIsPhoneBlacklisted_MatchesAnyFormat(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	uid := uuid.New().String()[:8]
+	org := models.Organization{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "test-" + uid, Slug: "test-" + uid}
+	require.NoError(t, db.Create(&org).Error)
+	require.NoError(t, db.Create(&models.PhoneBlacklist{OrganizationID: org.ID, PhoneNumber: "+1 (555) 030-0001"}).Error)
+
+	for _, phone := range []string{"15550300001", "+15550300001", "1 555 030 0001"} {
+		blacklisted, err := IsPhoneBlacklisted(db, org.ID, phone)
+		require.NoError(t, err)
+		assert.True(t, blacklisted, phone)
+	}
+
+	blacklisted, err := IsPhoneBlacklisted(db, org.ID, "15550300002")
+	require.NoError(t, err)
+	assert.False(t, blacklisted)
+}
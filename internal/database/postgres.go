@@ -66,10 +66,15 @@ func GetMigrationModels() []MigrationModel {
 		{"APIKey", &models.APIKey{}},
 		{"SSOProvider", &models.SSOProvider{}},
 		{"Webhook", &models.Webhook{}},
+		{"WebhookDelivery", &models.WebhookDelivery{}},
 		{"CustomAction", &models.CustomAction{}},
 		{"WhatsAppAccount", &models.WhatsAppAccount{}},
 		{"Contact", &models.Contact{}},
+		{"ContactAssignment", &models.ContactAssignment{}},
 		{"Tag", &models.Tag{}},
+		{"ContactTagOperation", &models.ContactTagOperation{}},
+		{"ContactCustomField", &models.ContactCustomField{}},
+		{"PhoneBlacklist", &models.PhoneBlacklist{}},
 		{"Message", &models.Message{}},
 		{"Template", &models.Template{}},
 		{"WhatsAppFlow", &models.WhatsAppFlow{}},
@@ -82,15 +87,20 @@ func GetMigrationModels() []MigrationModel {
 		// Chatbot models
 		{"ChatbotSettings", &models.ChatbotSettings{}},
 		{"KeywordRule", &models.KeywordRule{}},
+		{"KeywordRuleHit", &models.KeywordRuleHit{}},
 		{"ChatbotFlow", &models.ChatbotFlow{}},
 		{"ChatbotFlowStep", &models.ChatbotFlowStep{}},
 		{"ChatbotSession", &models.ChatbotSession{}},
 		{"ChatbotSessionMessage", &models.ChatbotSessionMessage{}},
+		{"FlowWebhookDelivery", &models.FlowWebhookDelivery{}},
 		{"AIContext", &models.AIContext{}},
+		{"AIUsage", &models.AIUsage{}},
 		{"AgentTransfer", &models.AgentTransfer{}},
 
 		// User tracking
 		{"UserAvailabilityLog", &models.UserAvailabilityLog{}},
+		{"UserSchedule", &models.UserSchedule{}},
+		{"PasswordResetToken", &models.PasswordResetToken{}},
 
 		// Canned responses
 		{"CannedResponse", &models.CannedResponse{}},
@@ -105,6 +115,12 @@ func GetMigrationModels() []MigrationModel {
 		// Conversation Notes
 		{"ConversationNote", &models.ConversationNote{}},
 
+		// Saved Views
+		{"SavedView", &models.SavedView{}},
+
+		// Scheduled Messages
+		{"ScheduledMessage", &models.ScheduledMessage{}},
+
 		// Calling / IVR
 		{"CallLog", &models.CallLog{}},
 		{"IVRFlow", &models.IVRFlow{}},
@@ -209,6 +225,12 @@ func RunMigrationWithProgress(db *gorm.DB, adminCfg *config.DefaultAdminConfig)
 		return err
 	}
 
+	// Backfill last_message_direction from existing messages
+	if err := BackfillLastMessageDirection(silentDB); err != nil {
+		fmt.Printf("\n  \033[31m✗ Failed to backfill last_message_direction\033[0m\n\n")
+		return err
+	}
+
 	printProgress(currentStep, totalSteps)
 	fmt.Printf("\n  \033[32m✓ Migration completed\033[0m\n\n")
 
@@ -236,9 +258,11 @@ func getIndexes() []string {
 		`CREATE INDEX IF NOT EXISTS idx_messages_contact_created ON messages(contact_id, created_at DESC)`,
 		`CREATE INDEX IF NOT EXISTS idx_messages_conversation ON messages(conversation_id)`,
 		`CREATE UNIQUE INDEX IF NOT EXISTS idx_contacts_org_phone ON contacts(organization_id, phone_number)`,
+		`CREATE INDEX IF NOT EXISTS idx_contacts_org_phone_digits ON contacts(organization_id, (regexp_replace(phone_number, '[^0-9]', '', 'g')))`,
 		`CREATE INDEX IF NOT EXISTS idx_contacts_assigned_read ON contacts(assigned_user_id, is_read)`,
 		`CREATE INDEX IF NOT EXISTS idx_sessions_phone_status ON chatbot_sessions(organization_id, phone_number, status)`,
 		`CREATE INDEX IF NOT EXISTS idx_keyword_rules_priority ON keyword_rules(organization_id, is_enabled, priority DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_keyword_rule_hits_rule_created ON keyword_rule_hits(keyword_rule_id, created_at)`,
 		`CREATE INDEX IF NOT EXISTS idx_agent_transfers_active ON agent_transfers(organization_id, phone_number, status)`,
 		`CREATE INDEX IF NOT EXISTS idx_agent_transfers_org_contact ON agent_transfers(organization_id, contact_id, status)`,
 		`CREATE INDEX IF NOT EXISTS idx_agent_transfers_agent_active ON agent_transfers(agent_id, status) WHERE status = 'active'`,
@@ -398,6 +422,23 @@ func BackfillLastInboundAt(db *gorm.DB) error {
 	`).Error
 }
 
+// BackfillLastMessageDirection sets last_message_direction for existing
+// contacts from their most recent message. Only updates contacts where the
+// field is empty.
+func BackfillLastMessageDirection(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE contacts c
+		SET last_message_direction = sub.direction
+		FROM (
+			SELECT DISTINCT ON (contact_id) contact_id, direction
+			FROM messages
+			WHERE deleted_at IS NULL
+			ORDER BY contact_id, created_at DESC
+		) sub
+		WHERE c.id = sub.contact_id AND COALESCE(c.last_message_direction, '') = '' AND c.deleted_at IS NULL
+	`).Error
+}
+
 // SeedPermissionsAndRoles seeds the default permissions and system roles
 func SeedPermissionsAndRoles(db *gorm.DB) error {
 	// Get all default permissions
@@ -8,12 +8,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shridarpatil/whatomate/internal/crypto"
 	"github.com/shridarpatil/whatomate/internal/models"
 	"github.com/valyala/fasthttp"
 	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
 )
 
 // AccountRequest represents the request body for creating/updating an account
@@ -33,31 +36,54 @@ type AccountRequest struct {
 
 // AccountResponse represents the response for an account (without sensitive data)
 type AccountResponse struct {
-	ID                 uuid.UUID `json:"id"`
-	Name               string    `json:"name"`
-	AppID              string    `json:"app_id"`
-	PhoneID            string    `json:"phone_id"`
-	BusinessID         string    `json:"business_id"`
-	WebhookVerifyToken string    `json:"webhook_verify_token"`
-	APIVersion         string    `json:"api_version"`
-	IsDefaultIncoming  bool      `json:"is_default_incoming"`
-	IsDefaultOutgoing  bool      `json:"is_default_outgoing"`
-	AutoReadReceipt    bool      `json:"auto_read_receipt"`
-	Status             string    `json:"status"`
-	HasAccessToken     bool      `json:"has_access_token"`
-	HasAppSecret       bool      `json:"has_app_secret"`
-	PhoneNumber        string    `json:"phone_number,omitempty"`
-	DisplayName        string    `json:"display_name,omitempty"`
-	CreatedAt          string    `json:"created_at"`
-	UpdatedAt          string    `json:"updated_at"`
+	ID                 uuid.UUID     `json:"id"`
+	Name               string        `json:"name"`
+	AppID              string        `json:"app_id"`
+	PhoneID            string        `json:"phone_id"`
+	BusinessID         string        `json:"business_id"`
+	WebhookVerifyToken string        `json:"webhook_verify_token"`
+	APIVersion         string        `json:"api_version"`
+	IsDefaultIncoming  bool          `json:"is_default_incoming"`
+	IsDefaultOutgoing  bool          `json:"is_default_outgoing"`
+	AutoReadReceipt    bool          `json:"auto_read_receipt"`
+	Status             string        `json:"status"`
+	HasAccessToken     bool          `json:"has_access_token"`
+	AccessTokenMasked  string        `json:"access_token_masked,omitempty"`
+	TokenUpdatedAt     *time.Time    `json:"access_token_updated_at,omitempty"`
+	HasAppSecret       bool          `json:"has_app_secret"`
+	PhoneNumber        string        `json:"phone_number,omitempty"`
+	DisplayName        string        `json:"display_name,omitempty"`
+	Health             AccountHealth `json:"health"`
+	CreatedAt          string        `json:"created_at"`
+	UpdatedAt          string        `json:"updated_at"`
+}
+
+// Account health statuses
+const (
+	AccountHealthUnknown   = "unknown"   // Never tested
+	AccountHealthHealthy   = "healthy"   // Last test succeeded
+	AccountHealthUnhealthy = "unhealthy" // Last test failed
+)
+
+// AccountHealth is an account's connection health, from its last connection
+// test and the last message it sent
+type AccountHealth struct {
+	Status        string     `json:"status"`
+	TokenValid    *bool      `json:"token_valid"`
+	LastCheckedAt *time.Time `json:"last_checked_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastSentAt    *time.Time `json:"last_sent_at"`
 }
 
 // ListAccounts returns all WhatsApp accounts for the organization
 func (a *App) ListAccounts(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
+	if err := a.requirePermission(r, userID, models.ResourceAccounts, models.ActionRead); err != nil {
+		return nil
+	}
 
 	var accounts []models.WhatsAppAccount
 	if err := a.DB.Where("organization_id = ?", orgID).Order("created_at DESC").Find(&accounts).Error; err != nil {
@@ -65,10 +91,15 @@ func (a *App) ListAccounts(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list accounts", nil, "")
 	}
 
+	lastSent := a.accountLastSentAt(orgID)
+
 	// Convert to response format (hide sensitive data)
 	response := make([]AccountResponse, len(accounts))
 	for i, acc := range accounts {
-		response[i] = accountToResponse(acc)
+		response[i] = a.accountToResponse(acc)
+		if sentAt, ok := lastSent[acc.Name]; ok {
+			response[i].Health.LastSentAt = &sentAt
+		}
 	}
 
 	return r.SendEnvelope(map[string]interface{}{
@@ -78,10 +109,13 @@ func (a *App) ListAccounts(r *fastglue.Request) error {
 
 // CreateAccount creates a new WhatsApp account
 func (a *App) CreateAccount(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
+	if err := a.requirePermission(r, userID, models.ResourceAccounts, models.ActionWrite); err != nil {
+		return nil
+	}
 
 	var req AccountRequest
 	if err := a.decodeRequest(r, &req); err != nil {
@@ -105,6 +139,11 @@ func (a *App) CreateAccount(r *fastglue.Request) error {
 		apiVersion = "v21.0"
 	}
 
+	if err := a.validateAccountCredentials(req.PhoneID, req.BusinessID, req.AccessToken, apiVersion); err != nil {
+		a.Log.Warn("Account credential validation failed", "error", err, "phone_id", req.PhoneID)
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Failed to validate credentials with WhatsApp: "+err.Error(), nil, "")
+	}
+
 	encKey := a.Config.App.EncryptionKey
 	encAccessToken, err := crypto.Encrypt(req.AccessToken, encKey)
 	if err != nil {
@@ -117,6 +156,8 @@ func (a *App) CreateAccount(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to create account", nil, "")
 	}
 
+	now := time.Now()
+	tokenValid := true
 	account := models.WhatsAppAccount{
 		OrganizationID:     orgID,
 		Name:               req.Name,
@@ -131,6 +172,9 @@ func (a *App) CreateAccount(r *fastglue.Request) error {
 		IsDefaultOutgoing:  req.IsDefaultOutgoing,
 		AutoReadReceipt:    req.AutoReadReceipt,
 		Status:             "active",
+		TokenValid:         &tokenValid,
+		TokenUpdatedAt:     &now,
+		LastCheckedAt:      &now,
 	}
 
 	// If this is set as default, unset other defaults
@@ -150,15 +194,18 @@ func (a *App) CreateAccount(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to create account", nil, "")
 	}
 
-	return r.SendEnvelope(accountToResponse(account))
+	return r.SendEnvelope(a.accountToResponse(account))
 }
 
 // GetAccount returns a single WhatsApp account
 func (a *App) GetAccount(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
+	if err := a.requirePermission(r, userID, models.ResourceAccounts, models.ActionRead); err != nil {
+		return nil
+	}
 
 	id, err := parsePathUUID(r, "id", "account")
 	if err != nil {
@@ -170,31 +217,70 @@ func (a *App) GetAccount(r *fastglue.Request) error {
 		return nil
 	}
 
-	return r.SendEnvelope(accountToResponse(*account))
+	response := a.accountToResponse(*account)
+	if sentAt, ok := a.accountLastSentAt(orgID)[account.Name]; ok {
+		response.Health.LastSentAt = &sentAt
+	}
+	return r.SendEnvelope(response)
 }
 
 // UpdateAccount updates a WhatsApp account
 func (a *App) UpdateAccount(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
+	if err := a.requirePermission(r, userID, models.ResourceAccounts, models.ActionWrite); err != nil {
+		return nil
+	}
 
 	id, err := parsePathUUID(r, "id", "account")
 	if err != nil {
 		return nil
 	}
 
-	account, err := a.resolveWhatsAppAccountByID(r, id, orgID)
+	// Loaded without decrypting so unchanged secrets are saved back as stored
+	account, err := findByIDAndOrg[models.WhatsAppAccount](a.DB, r, id, orgID, "Account")
 	if err != nil {
 		return nil
 	}
+	oldPhoneID := account.PhoneID
 
 	var req AccountRequest
 	if err := a.decodeRequest(r, &req); err != nil {
 		return nil
 	}
 
+	// Check changed credentials with Meta before storing them
+	if (req.PhoneID != "" && req.PhoneID != account.PhoneID) ||
+		(req.BusinessID != "" && req.BusinessID != account.BusinessID) ||
+		(req.APIVersion != "" && req.APIVersion != account.APIVersion) ||
+		req.AccessToken != "" {
+		check := *account
+		a.decryptAccountSecrets(&check)
+		if req.PhoneID != "" {
+			check.PhoneID = req.PhoneID
+		}
+		if req.BusinessID != "" {
+			check.BusinessID = req.BusinessID
+		}
+		if req.APIVersion != "" {
+			check.APIVersion = req.APIVersion
+		}
+		if req.AccessToken != "" {
+			check.AccessToken = req.AccessToken
+		}
+		if err := a.validateAccountCredentials(check.PhoneID, check.BusinessID, check.AccessToken, check.APIVersion); err != nil {
+			a.Log.Warn("Account credential validation failed", "error", err, "account", account.Name)
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Failed to validate credentials with WhatsApp: "+err.Error(), nil, "")
+		}
+		now := time.Now()
+		tokenValid := true
+		account.TokenValid = &tokenValid
+		account.LastCheckedAt = &now
+		account.LastCheckError = ""
+	}
+
 	// Update fields if provided
 	if req.Name != "" {
 		account.Name = req.Name
@@ -214,7 +300,9 @@ func (a *App) UpdateAccount(r *fastglue.Request) error {
 			a.Log.Error("Failed to encrypt access token", "error", err)
 			return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update account", nil, "")
 		}
+		now := time.Now()
 		account.AccessToken = enc
+		account.TokenUpdatedAt = &now
 	}
 	if req.AppSecret != "" {
 		enc, err := crypto.Encrypt(req.AppSecret, a.Config.App.EncryptionKey)
@@ -253,16 +341,22 @@ func (a *App) UpdateAccount(r *fastglue.Request) error {
 
 	// Invalidate cache
 	a.InvalidateWhatsAppAccountCache(account.PhoneID)
+	if oldPhoneID != account.PhoneID {
+		a.InvalidateWhatsAppAccountCache(oldPhoneID)
+	}
 
-	return r.SendEnvelope(accountToResponse(*account))
+	return r.SendEnvelope(a.accountToResponse(*account))
 }
 
 // DeleteAccount deletes a WhatsApp account
 func (a *App) DeleteAccount(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
+	if err := a.requirePermission(r, userID, models.ResourceAccounts, models.ActionDelete); err != nil {
+		return nil
+	}
 
 	id, err := parsePathUUID(r, "id", "account")
 	if err != nil {
@@ -280,19 +374,95 @@ func (a *App) DeleteAccount(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete account", nil, "")
 	}
 
+	// Stop sending from it by default
+	if err := a.DB.Model(&models.Organization{}).
+		Where("id = ? AND settings->>'default_whatsapp_account' = ?", orgID, account.Name).
+		Update("settings", gorm.Expr("settings - 'default_whatsapp_account'")).Error; err != nil {
+		a.Log.Error("Failed to clear default WhatsApp account", "error", err, "account", account.Name)
+	}
+
 	// Invalidate cache
 	a.InvalidateWhatsAppAccountCache(account.PhoneID)
 
 	return r.SendEnvelope(map[string]string{"message": "Account deleted successfully"})
 }
 
+// RotateAccountToken replaces a WhatsApp account's access token. The new
+// token is checked with Meta before it replaces the current one.
+func (a *App) RotateAccountToken(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	if err := a.requirePermission(r, userID, models.ResourceAccounts, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	id, err := parsePathUUID(r, "id", "account")
+	if err != nil {
+		return nil
+	}
+
+	var req struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	req.AccessToken = strings.TrimSpace(req.AccessToken)
+	if req.AccessToken == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "access_token is required", nil, "")
+	}
+
+	account, err := findByIDAndOrg[models.WhatsAppAccount](a.DB, r, id, orgID, "Account")
+	if err != nil {
+		return nil
+	}
+
+	if err := a.validateAccountCredentials(account.PhoneID, account.BusinessID, req.AccessToken, account.APIVersion); err != nil {
+		a.Log.Warn("Access token validation failed", "error", err, "account", account.Name)
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Failed to validate access token with WhatsApp: "+err.Error(), nil, "")
+	}
+
+	enc, err := crypto.Encrypt(req.AccessToken, a.Config.App.EncryptionKey)
+	if err != nil {
+		a.Log.Error("Failed to encrypt access token", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to rotate access token", nil, "")
+	}
+
+	now := time.Now()
+	tokenValid := true
+	account.AccessToken = enc
+	account.TokenUpdatedAt = &now
+	account.TokenValid = &tokenValid
+	account.LastCheckedAt = &now
+	account.LastCheckError = ""
+	if err := a.DB.Model(account).Updates(map[string]any{
+		"access_token":     account.AccessToken,
+		"token_updated_at": account.TokenUpdatedAt,
+		"token_valid":      true,
+		"last_checked_at":  now,
+		"last_check_error": "",
+	}).Error; err != nil {
+		a.Log.Error("Failed to rotate access token", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to rotate access token", nil, "")
+	}
+
+	a.InvalidateWhatsAppAccountCache(account.PhoneID)
+
+	return r.SendEnvelope(a.accountToResponse(*account))
+}
+
 // TestAccountConnection tests the WhatsApp API connection
 // This validates both PhoneID and BusinessID to ensure all credentials are correct
 func (a *App) TestAccountConnection(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
+	if err := a.requirePermission(r, userID, models.ResourceAccounts, models.ActionWrite); err != nil {
+		return nil
+	}
 
 	id, err := parsePathUUID(r, "id", "account")
 	if err != nil {
@@ -307,6 +477,7 @@ func (a *App) TestAccountConnection(r *fastglue.Request) error {
 	// Use the comprehensive validation function
 	if err := a.validateAccountCredentials(account.PhoneID, account.BusinessID, account.AccessToken, account.APIVersion); err != nil {
 		a.Log.Error("Account test failed", "error", err, "account", account.Name)
+		a.recordAccountCheck(account.ID, false, err.Error(), nil)
 		return r.SendEnvelope(map[string]interface{}{
 			"success": false,
 			"error":   "Account credential validation failed. Check your access token and phone ID.",
@@ -323,6 +494,7 @@ func (a *App) TestAccountConnection(r *fastglue.Request) error {
 	resp, err := a.HTTPClient.Do(req)
 	if err != nil {
 		a.Log.Error("Failed to connect to WhatsApp API", "error", err)
+		a.recordAccountCheck(account.ID, true, "Failed to connect to WhatsApp API", nil)
 		return r.SendEnvelope(map[string]interface{}{
 			"success": false,
 			"error":   "Failed to connect to WhatsApp API",
@@ -335,6 +507,7 @@ func (a *App) TestAccountConnection(r *fastglue.Request) error {
 	if resp.StatusCode != 200 {
 		var errorResp map[string]interface{}
 		_ = json.Unmarshal(body, &errorResp)
+		a.recordAccountCheck(account.ID, true, fmt.Sprintf("WhatsApp API returned status %d", resp.StatusCode), nil)
 		return r.SendEnvelope(map[string]interface{}{
 			"success": false,
 			"error":   "API error",
@@ -349,6 +522,8 @@ func (a *App) TestAccountConnection(r *fastglue.Request) error {
 	accountMode, _ := result["account_mode"].(string)
 	isTestNumber := accountMode == "SANDBOX"
 
+	a.recordAccountCheck(account.ID, true, "", result)
+
 	// Prepare response
 	response := map[string]interface{}{
 		"success":                  true,
@@ -373,7 +548,10 @@ func (a *App) TestAccountConnection(r *fastglue.Request) error {
 
 // Helper functions
 
-func accountToResponse(acc models.WhatsAppAccount) AccountResponse {
+// accountToResponse converts a stored account to its API response. Only a
+// masked suffix of the access token is included.
+func (a *App) accountToResponse(acc models.WhatsAppAccount) AccountResponse {
+	a.decryptAccountSecrets(&acc)
 	return AccountResponse{
 		ID:                 acc.ID,
 		Name:               acc.Name,
@@ -387,12 +565,80 @@ func accountToResponse(acc models.WhatsAppAccount) AccountResponse {
 		AutoReadReceipt:    acc.AutoReadReceipt,
 		Status:             acc.Status,
 		HasAccessToken:     acc.AccessToken != "",
+		AccessTokenMasked:  maskSecret(acc.AccessToken),
+		TokenUpdatedAt:     acc.TokenUpdatedAt,
 		HasAppSecret:       acc.AppSecret != "",
+		PhoneNumber:        acc.PhoneNumber,
+		DisplayName:        acc.DisplayName,
+		Health:             accountHealth(acc),
 		CreatedAt:          acc.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:          acc.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
+// accountHealth reports an account's health from its last connection test.
+// The last sent time is added by callers that look it up.
+func accountHealth(acc models.WhatsAppAccount) AccountHealth {
+	health := AccountHealth{
+		Status:        AccountHealthUnknown,
+		TokenValid:    acc.TokenValid,
+		LastCheckedAt: acc.LastCheckedAt,
+		LastError:     acc.LastCheckError,
+	}
+	if acc.LastCheckedAt != nil {
+		health.Status = AccountHealthHealthy
+		if acc.LastCheckError != "" {
+			health.Status = AccountHealthUnhealthy
+		}
+	}
+	return health
+}
+
+// recordAccountCheck stores the outcome of a connection test on the account.
+// errMsg is empty when the test succeeded, and details holds Meta's phone
+// number fields when they were fetched.
+func (a *App) recordAccountCheck(accountID uuid.UUID, tokenValid bool, errMsg string, details map[string]interface{}) {
+	updates := map[string]interface{}{
+		"token_valid":      tokenValid,
+		"last_checked_at":  time.Now(),
+		"last_check_error": errMsg,
+	}
+	if phone, ok := details["display_phone_number"].(string); ok {
+		updates["phone_number"] = phone
+	}
+	if name, ok := details["verified_name"].(string); ok {
+		updates["display_name"] = name
+	}
+	// Update by ID so the decrypted credentials on the loaded account aren't written back
+	if err := a.DB.Model(&models.WhatsAppAccount{}).Where("id = ?", accountID).Updates(updates).Error; err != nil {
+		a.Log.Error("Failed to record account health", "error", err, "account_id", accountID)
+	}
+}
+
+// accountLastSentAt returns when each of the organization's accounts last
+// sent a message that WhatsApp accepted, keyed by account name
+func (a *App) accountLastSentAt(orgID uuid.UUID) map[string]time.Time {
+	var rows []struct {
+		WhatsAppAccount string
+		LastSentAt      time.Time
+	}
+	if err := a.DB.Model(&models.Message{}).
+		Select("whats_app_account, MAX(created_at) AS last_sent_at").
+		Where("organization_id = ? AND direction = ? AND status IN ?", orgID, models.DirectionOutgoing,
+			[]models.MessageStatus{models.MessageStatusSent, models.MessageStatusDelivered, models.MessageStatusRead}).
+		Group("whats_app_account").
+		Scan(&rows).Error; err != nil {
+		a.Log.Error("Failed to load account last sent times", "error", err, "org_id", orgID)
+		return nil
+	}
+
+	lastSent := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		lastSent[row.WhatsAppAccount] = row.LastSentAt
+	}
+	return lastSent
+}
+
 func generateVerifyToken() string {
 	bytes := make([]byte, 32)
 	_, _ = rand.Read(bytes)
@@ -413,10 +659,13 @@ func (a *App) validateAccountCredentials(phoneID, businessID, accessToken, apiVe
 // SubscribeApp subscribes the app to webhooks for the WhatsApp Business Account.
 // This is required after phone number registration to receive incoming messages from Meta.
 func (a *App) SubscribeApp(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
+	if err := a.requirePermission(r, userID, models.ResourceAccounts, models.ActionWrite); err != nil {
+		return nil
+	}
 
 	id, err := parsePathUUID(r, "id", "account")
 	if err != nil {
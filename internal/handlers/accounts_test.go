@@ -2,15 +2,22 @@ package handlers_test
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/crypto"
 	"github.com/shridarpatil/whatomate/internal/handlers"
 	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/pkg/whatsapp"
 	"github.com/shridarpatil/whatomate/test/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
 )
 
 // --- ListAccounts Tests ---
@@ -20,7 +27,7 @@ func TestApp_ListAccounts_Success(t *testing.T) {
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 
 	// Create two accounts for this org
 	acc1 := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
@@ -55,7 +62,7 @@ func TestApp_ListAccounts_Empty(t *testing.T) {
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 
 	req := testutil.NewGETRequest(t)
 	testutil.SetAuthContext(req, org.ID, user.ID)
@@ -93,8 +100,8 @@ func TestApp_ListAccounts_OrgIsolation(t *testing.T) {
 	app := newTestApp(t)
 	org1 := testutil.CreateTestOrganization(t, app.DB)
 	org2 := testutil.CreateTestOrganization(t, app.DB)
-	user1 := testutil.CreateTestUser(t, app.DB, org1.ID)
-	user2 := testutil.CreateTestUser(t, app.DB, org2.ID)
+	user1 := createAdminUser(t, app, org1.ID)
+	user2 := createAdminUser(t, app, org2.ID)
 
 	// Create accounts for org1
 	testutil.CreateTestWhatsAppAccount(t, app.DB, org1.ID)
@@ -136,14 +143,62 @@ func TestApp_ListAccounts_OrgIsolation(t *testing.T) {
 	assert.Len(t, resp2.Data.Accounts, 1)
 }
 
+func TestApp_ListAccounts_Health(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := createAdminUser(t, app, org.ID)
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	sentAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, msg := range []struct {
+		status    models.MessageStatus
+		createdAt time.Time
+	}{
+		{models.MessageStatusDelivered, sentAt},
+		{models.MessageStatusFailed, time.Now()}, // Failed sends don't count
+	} {
+		require.NoError(t, app.DB.Create(&models.Message{
+			BaseModel:       models.BaseModel{ID: uuid.New(), CreatedAt: msg.createdAt},
+			OrganizationID:  org.ID,
+			WhatsAppAccount: account.Name,
+			ContactID:       contact.ID,
+			Direction:       models.DirectionOutgoing,
+			MessageType:     models.MessageTypeText,
+			Content:         "Hello",
+			Status:          msg.status,
+		}).Error)
+	}
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	require.NoError(t, app.ListAccounts(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data struct {
+			Accounts []handlers.AccountResponse `json:"accounts"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	require.Len(t, resp.Data.Accounts, 1)
+	health := resp.Data.Accounts[0].Health
+	assert.Equal(t, handlers.AccountHealthUnknown, health.Status, "untested accounts have unknown health")
+	assert.Nil(t, health.TokenValid)
+	require.NotNil(t, health.LastSentAt)
+	assert.WithinDuration(t, sentAt, *health.LastSentAt, time.Second)
+}
+
 // --- CreateAccount Tests ---
 
 func TestApp_CreateAccount_Success(t *testing.T) {
 	t.Parallel()
 
-	app := newTestApp(t)
+	app := newAccountsTestApp(t, "123456789")
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 
 	req := testutil.NewJSONRequest(t, map[string]interface{}{
 		"name":         "My WhatsApp Account",
@@ -175,9 +230,9 @@ func TestApp_CreateAccount_Success(t *testing.T) {
 func TestApp_CreateAccount_WithOptionalFields(t *testing.T) {
 	t.Parallel()
 
-	app := newTestApp(t)
+	app := newAccountsTestApp(t, "111222333")
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 
 	req := testutil.NewJSONRequest(t, map[string]interface{}{
 		"name":                 "Full Account",
@@ -219,7 +274,7 @@ func TestApp_CreateAccount_ValidationErrors(t *testing.T) {
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 
 	tests := []struct {
 		name string
@@ -300,7 +355,7 @@ func TestApp_GetAccount_Success(t *testing.T) {
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
 
 	req := testutil.NewGETRequest(t)
@@ -330,7 +385,7 @@ func TestApp_GetAccount_NotFound(t *testing.T) {
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 
 	req := testutil.NewGETRequest(t)
 	testutil.SetAuthContext(req, org.ID, user.ID)
@@ -346,7 +401,7 @@ func TestApp_GetAccount_InvalidID(t *testing.T) {
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 
 	req := testutil.NewGETRequest(t)
 	testutil.SetAuthContext(req, org.ID, user.ID)
@@ -366,7 +421,7 @@ func TestApp_GetAccount_CrossOrgIsolation(t *testing.T) {
 	org1 := testutil.CreateTestOrganization(t, app.DB)
 	org2 := testutil.CreateTestOrganization(t, app.DB)
 
-	user2 := testutil.CreateTestUser(t, app.DB, org2.ID)
+	user2 := createAdminUser(t, app, org2.ID)
 
 	// Create account in org1
 	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org1.ID)
@@ -386,9 +441,9 @@ func TestApp_GetAccount_CrossOrgIsolation(t *testing.T) {
 func TestApp_UpdateAccount_Success(t *testing.T) {
 	t.Parallel()
 
-	app := newTestApp(t)
+	app := newAccountsTestApp(t, "new-phone-id")
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
 
 	req := testutil.NewJSONRequest(t, map[string]interface{}{
@@ -432,7 +487,7 @@ func TestApp_UpdateAccount_PartialUpdate(t *testing.T) {
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
 
 	// Only update the name, leave other fields unchanged
@@ -463,7 +518,7 @@ func TestApp_UpdateAccount_NotFound(t *testing.T) {
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 
 	req := testutil.NewJSONRequest(t, map[string]interface{}{
 		"name": "Updated Name",
@@ -481,7 +536,7 @@ func TestApp_UpdateAccount_InvalidID(t *testing.T) {
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 
 	req := testutil.NewJSONRequest(t, map[string]interface{}{
 		"name": "Updated",
@@ -501,7 +556,7 @@ func TestApp_DeleteAccount_Success(t *testing.T) {
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
 
 	req := testutil.NewGETRequest(t)
@@ -527,12 +582,34 @@ func TestApp_DeleteAccount_Success(t *testing.T) {
 	assert.Equal(t, int64(0), count)
 }
 
+func TestApp_DeleteAccount_ClearsDefaultAccount(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := createAdminUser(t, app, org.ID)
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(&models.Organization{}).Where("id = ?", org.ID).
+		Update("settings", models.JSONB{"default_whatsapp_account": account.Name, "timezone": "UTC"}).Error)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", account.ID.String())
+	require.NoError(t, app.DeleteAccount(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var updated models.Organization
+	require.NoError(t, app.DB.Where("id = ?", org.ID).First(&updated).Error)
+	assert.Empty(t, updated.DefaultWhatsAppAccount())
+	assert.Equal(t, "UTC", updated.Settings["timezone"], "other settings are kept")
+}
+
 func TestApp_DeleteAccount_NotFound(t *testing.T) {
 	t.Parallel()
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 
 	req := testutil.NewGETRequest(t)
 	testutil.SetAuthContext(req, org.ID, user.ID)
@@ -548,7 +625,7 @@ func TestApp_DeleteAccount_InvalidID(t *testing.T) {
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	user := createAdminUser(t, app, org.ID)
 
 	req := testutil.NewGETRequest(t)
 	testutil.SetAuthContext(req, org.ID, user.ID)
@@ -567,7 +644,7 @@ func TestApp_DeleteAccount_CrossOrgIsolation(t *testing.T) {
 	org1 := testutil.CreateTestOrganization(t, app.DB)
 	org2 := testutil.CreateTestOrganization(t, app.DB)
 
-	user2 := testutil.CreateTestUser(t, app.DB, org2.ID)
+	user2 := createAdminUser(t, app, org2.ID)
 
 	// Create account in org1
 	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org1.ID)
@@ -586,3 +663,313 @@ func TestApp_DeleteAccount_CrossOrgIsolation(t *testing.T) {
 	app.DB.Model(&models.WhatsAppAccount{}).Where("id = ?", account.ID).Count(&count)
 	assert.Equal(t, int64(1), count)
 }
+
+// --- TestAccountConnection Tests ---
+
+// newAccountTestServer fakes the Graph API endpoints used to validate account
+// credentials, answering every request with status. The business account
+// owns the given phone IDs.
+func newAccountTestServer(t *testing.T, status int, phoneIDs ...string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			_, _ = w.Write([]byte(`{"error":{"message":"Invalid OAuth access token","code":190}}`))
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/phone_numbers") {
+			phones := make([]map[string]string, len(phoneIDs))
+			for i, id := range phoneIDs {
+				phones[i] = map[string]string{"id": id}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": phones})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"display_phone_number":     "+1 555 010 0000",
+			"verified_name":            "Acme Support",
+			"code_verification_status": "VERIFIED",
+			"account_mode":             "LIVE",
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newAccountsTestApp creates an App whose WhatsApp API accepts credentials
+// for the given phone IDs
+func newAccountsTestApp(t *testing.T, phoneIDs ...string) *handlers.App {
+	t.Helper()
+	server := newAccountTestServer(t, http.StatusOK, phoneIDs...)
+	app := newTestApp(t, withWhatsApp(whatsapp.NewWithBaseURL(testutil.NopLogger(), server.URL)))
+	app.Config.WhatsApp.BaseURL = server.URL
+	return app
+}
+
+func TestApp_TestAccountConnection_RecordsHealth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		status     int
+		wantHealth string
+		wantValid  bool
+		wantPhone  string
+	}{
+		{"success", http.StatusOK, handlers.AccountHealthHealthy, true, "+1 555 010 0000"},
+		{"invalid token", http.StatusUnauthorized, handlers.AccountHealthUnhealthy, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := newTestApp(t)
+			org := testutil.CreateTestOrganization(t, app.DB)
+			user := createAdminUser(t, app, org.ID)
+			account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+
+			server := newAccountTestServer(t, tt.status, account.PhoneID)
+			app.WhatsApp = whatsapp.NewWithBaseURL(testutil.NopLogger(), server.URL)
+			app.Config.WhatsApp.BaseURL = server.URL
+
+			req := testutil.NewRequest(t)
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			testutil.SetPathParam(req, "id", account.ID.String())
+			require.NoError(t, app.TestAccountConnection(req))
+			assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+			getReq := testutil.NewGETRequest(t)
+			testutil.SetAuthContext(getReq, org.ID, user.ID)
+			testutil.SetPathParam(getReq, "id", account.ID.String())
+			require.NoError(t, app.GetAccount(getReq))
+
+			var resp struct {
+				Data handlers.AccountResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(testutil.GetResponseBody(getReq), &resp))
+			assert.Equal(t, tt.wantHealth, resp.Data.Health.Status)
+			require.NotNil(t, resp.Data.Health.TokenValid)
+			assert.Equal(t, tt.wantValid, *resp.Data.Health.TokenValid)
+			assert.NotNil(t, resp.Data.Health.LastCheckedAt)
+			assert.Equal(t, tt.wantPhone, resp.Data.PhoneNumber)
+			if !tt.wantValid {
+				assert.NotEmpty(t, resp.Data.Health.LastError)
+			}
+
+			// The stored access token is untouched by recording the result
+			var stored models.WhatsAppAccount
+			require.NoError(t, app.DB.Where("id = ?", account.ID).First(&stored).Error)
+			assert.Equal(t, account.AccessToken, stored.AccessToken)
+		})
+	}
+}
+
+func TestApp_CreateAccount_InvalidCredentials(t *testing.T) {
+	t.Parallel()
+
+	server := newAccountTestServer(t, http.StatusUnauthorized)
+	app := newTestApp(t, withWhatsApp(whatsapp.NewWithBaseURL(testutil.NopLogger(), server.URL)))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := createAdminUser(t, app, org.ID)
+
+	req := testutil.NewJSONRequest(t, map[string]interface{}{
+		"name":         "Bad Token",
+		"phone_id":     "123456789",
+		"business_id":  "987654321",
+		"access_token": "expired-token",
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.CreateAccount(req))
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+	var count int64
+	app.DB.Model(&models.WhatsAppAccount{}).Where("organization_id = ?", org.ID).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestApp_CreateAccount_Forbidden(t *testing.T) {
+	t.Parallel()
+
+	app := newAccountsTestApp(t, "123456789")
+	org := testutil.CreateTestOrganization(t, app.DB)
+	role := testutil.CreateAgentRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+
+	req := testutil.NewJSONRequest(t, map[string]interface{}{
+		"name":         "Agent Account",
+		"phone_id":     "123456789",
+		"business_id":  "987654321",
+		"access_token": "tok",
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.CreateAccount(req))
+	assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+}
+
+func TestApp_AccountEndpoints_Forbidden(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	role := testutil.CreateAgentRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+
+	endpoints := map[string]func(*fastglue.Request) error{
+		"ListAccounts":          app.ListAccounts,
+		"GetAccount":            app.GetAccount,
+		"TestAccountConnection": app.TestAccountConnection,
+		"SubscribeApp":          app.SubscribeApp,
+		"GetBusinessProfile":    app.GetBusinessProfile,
+		"UpdateBusinessProfile": app.UpdateBusinessProfile,
+		"UpdateProfilePicture":  app.UpdateProfilePicture,
+	}
+	for name, handler := range endpoints {
+		t.Run(name, func(t *testing.T) {
+			req := testutil.NewJSONRequest(t, map[string]interface{}{"about": "changed"})
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			testutil.SetPathParam(req, "id", account.ID.String())
+
+			require.NoError(t, handler(req))
+			assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+		})
+	}
+}
+
+func TestApp_UpdateAccount_KeepsTokenEncrypted(t *testing.T) {
+	t.Parallel()
+
+	app := newAccountsTestApp(t, "123456789")
+	app.Config.App.EncryptionKey = testEncryptionKey
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := createAdminUser(t, app, org.ID)
+
+	createReq := testutil.NewJSONRequest(t, map[string]interface{}{
+		"name":         "Encrypted",
+		"phone_id":     "123456789",
+		"business_id":  "987654321",
+		"access_token": "EAAG-secret-token-1234",
+	})
+	testutil.SetAuthContext(createReq, org.ID, user.ID)
+	require.NoError(t, app.CreateAccount(createReq))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(createReq))
+
+	var created struct {
+		Data handlers.AccountResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(createReq), &created))
+	assert.Equal(t, "****1234", created.Data.AccessTokenMasked)
+	assert.NotContains(t, string(testutil.GetResponseBody(createReq)), "EAAG-secret-token-1234")
+
+	// Updating other fields leaves the stored token encrypted
+	req := testutil.NewJSONRequest(t, map[string]interface{}{"name": "Renamed"})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", created.Data.ID.String())
+	require.NoError(t, app.UpdateAccount(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var stored models.WhatsAppAccount
+	require.NoError(t, app.DB.Where("id = ?", created.Data.ID).First(&stored).Error)
+	assert.NotEqual(t, "EAAG-secret-token-1234", stored.AccessToken)
+	decrypted, err := crypto.Decrypt(stored.AccessToken, testEncryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, "EAAG-secret-token-1234", decrypted)
+}
+
+// --- RotateAccountToken Tests ---
+
+func TestApp_RotateAccountToken(t *testing.T) {
+	t.Parallel()
+
+	rotate := func(t *testing.T, app *handlers.App, orgID, userID, accountID uuid.UUID, token string) *fastglue.Request {
+		req := testutil.NewJSONRequest(t, map[string]interface{}{"access_token": token})
+		testutil.SetAuthContext(req, orgID, userID)
+		testutil.SetPathParam(req, "id", accountID.String())
+		require.NoError(t, app.RotateAccountToken(req))
+		return req
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		app.Config.App.EncryptionKey = testEncryptionKey
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := createAdminUser(t, app, org.ID)
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		server := newAccountTestServer(t, http.StatusOK, account.PhoneID)
+		app.WhatsApp = whatsapp.NewWithBaseURL(testutil.NopLogger(), server.URL)
+
+		req := rotate(t, app, org.ID, user.ID, account.ID, "EAAG-rotated-token-9876")
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.AccountResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, "****9876", resp.Data.AccessTokenMasked)
+		assert.NotNil(t, resp.Data.TokenUpdatedAt)
+		assert.Equal(t, handlers.AccountHealthHealthy, resp.Data.Health.Status)
+		assert.NotContains(t, string(testutil.GetResponseBody(req)), "EAAG-rotated-token-9876")
+
+		var stored models.WhatsAppAccount
+		require.NoError(t, app.DB.Where("id = ?", account.ID).First(&stored).Error)
+		decrypted, err := crypto.Decrypt(stored.AccessToken, testEncryptionKey)
+		require.NoError(t, err)
+		assert.Equal(t, "EAAG-rotated-token-9876", decrypted)
+	})
+
+	t.Run("rejected token keeps the current one", func(t *testing.T) {
+		t.Parallel()
+		server := newAccountTestServer(t, http.StatusUnauthorized)
+		app := newTestApp(t, withWhatsApp(whatsapp.NewWithBaseURL(testutil.NopLogger(), server.URL)))
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := createAdminUser(t, app, org.ID)
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+
+		req := rotate(t, app, org.ID, user.ID, account.ID, "bad-token")
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+		var stored models.WhatsAppAccount
+		require.NoError(t, app.DB.Where("id = ?", account.ID).First(&stored).Error)
+		assert.Equal(t, account.AccessToken, stored.AccessToken)
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := createAdminUser(t, app, org.ID)
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+
+		req := rotate(t, app, org.ID, user.ID, account.ID, "  ")
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("other organization's account", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		user := createAdminUser(t, app, org.ID)
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, otherOrg.ID)
+
+		req := rotate(t, app, org.ID, user.ID, account.ID, "new-token")
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("forbidden without accounts permission", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAgentRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+
+		req := rotate(t, app, org.ID, user.ID, account.ID, "new-token")
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
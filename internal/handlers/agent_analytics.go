@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -476,3 +477,100 @@ func (a *App) calculateTrendData(orgID uuid.UUID, start, end time.Time, groupBy
 
 	return trendData
 }
+
+// AgentWorkload is an agent's current load and recent responsiveness
+type AgentWorkload struct {
+	AgentID              uuid.UUID `json:"agent_id"`
+	AgentName            string    `json:"agent_name"`
+	IsAvailable          bool      `json:"is_available"`
+	OnShift              bool      `json:"on_shift"`
+	OpenConversations    int64     `json:"open_conversations"`
+	UnreadMessages       int64     `json:"unread_messages"`
+	AvgFirstResponseMins *float64  `json:"avg_first_response_mins"` // nil when nothing was answered in the window
+}
+
+// GetAgentWorkload returns each active member's open assigned conversations,
+// unread messages and average first response time in the window. Available,
+// on-shift agents come first, least loaded first.
+func (a *App) GetAgentWorkload(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceAnalytics, models.ActionRead); err != nil {
+		return nil
+	}
+
+	fromStr := string(r.RequestCtx.QueryArgs().Peek("from"))
+	toStr := string(r.RequestCtx.QueryArgs().Peek("to"))
+	availableOnly := string(r.RequestCtx.QueryArgs().Peek("available_only")) == "true"
+
+	now := time.Now()
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := now
+	if fromStr != "" && toStr != "" {
+		var errMsg string
+		periodStart, periodEnd, errMsg = parseDateRange(fromStr, toStr)
+		if errMsg != "" {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+		}
+	}
+
+	// One row per member: assigned open conversations and their unread
+	// incoming messages, plus first response time on transfers in the window
+	var workloads []AgentWorkload
+	if err := a.DB.Table("users").
+		Select(`users.id AS agent_id, users.full_name AS agent_name, users.is_available,
+			COUNT(DISTINCT contacts.id) AS open_conversations,
+			COUNT(messages.id) AS unread_messages,
+			(SELECT AVG(EXTRACT(EPOCH FROM (t.first_response_at - t.transferred_at))/60)
+				FROM agent_transfers t
+				WHERE t.agent_id = users.id AND t.organization_id = ? AND t.first_response_at IS NOT NULL
+					AND t.transferred_at >= ? AND t.transferred_at <= ?) AS avg_first_response_mins`,
+			orgID, periodStart, periodEnd).
+		Joins("JOIN user_organizations ON user_organizations.user_id = users.id AND user_organizations.organization_id = ? AND user_organizations.deleted_at IS NULL", orgID).
+		Joins(`LEFT JOIN contacts ON contacts.assigned_user_id = users.id AND contacts.organization_id = ? AND contacts.deleted_at IS NULL
+			AND (contacts.conversation_status IN (?, '') OR (contacts.conversation_status = ? AND (contacts.snooze_until IS NULL OR contacts.snooze_until <= ?)))`,
+			orgID, models.ConversationStatusOpen, models.ConversationStatusSnoozed, now).
+		Joins("LEFT JOIN messages ON messages.contact_id = contacts.id AND messages.direction = ? AND messages.status != ? AND messages.deleted_at IS NULL",
+			models.DirectionIncoming, models.MessageStatusRead).
+		Where("users.deleted_at IS NULL AND users.is_active = ?", true).
+		Group("users.id, users.full_name, users.is_available").
+		Scan(&workloads).Error; err != nil {
+		a.Log.Error("Failed to load agent workload", "error", err, "org_id", orgID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load agent workload", nil, "")
+	}
+
+	agentIDs := make([]uuid.UUID, len(workloads))
+	for i, w := range workloads {
+		agentIDs[i] = w.AgentID
+	}
+	offShift := a.offShiftUsers(orgID, agentIDs, now)
+
+	result := make([]AgentWorkload, 0, len(workloads))
+	for _, w := range workloads {
+		w.OnShift = !offShift[w.AgentID]
+		if availableOnly && !(w.IsAvailable && w.OnShift) {
+			continue
+		}
+		result = append(result, w)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		iReady := result[i].IsAvailable && result[i].OnShift
+		jReady := result[j].IsAvailable && result[j].OnShift
+		if iReady != jReady {
+			return iReady
+		}
+		if result[i].OpenConversations != result[j].OpenConversations {
+			return result[i].OpenConversations < result[j].OpenConversations
+		}
+		return result[i].AgentName < result[j].AgentName
+	})
+
+	return r.SendEnvelope(map[string]any{
+		"agents": result,
+		"from":   periodStart.Format(time.RFC3339),
+		"to":     periodEnd.Format(time.RFC3339),
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -463,7 +464,8 @@ func (a *App) CreateAgentTransfer(r *fastglue.Request) error {
 	} else if settings != nil && settings.AgentAssignment.AssignToSameAgent && contact.AssignedUserID != nil {
 		// Auto-assign to contact's existing assigned agent (if setting enabled and agent is available)
 		var assignedAgent models.User
-		if a.DB.Where("id = ?", contact.AssignedUserID).First(&assignedAgent).Error == nil && assignedAgent.IsAvailable {
+		if a.DB.Where("id = ?", contact.AssignedUserID).First(&assignedAgent).Error == nil && assignedAgent.IsAvailable &&
+			a.isOnShift(orgID, assignedAgent.ID) {
 			agentID = contact.AssignedUserID
 		}
 		// If agent is not available, falls through to queue (agentID remains nil)
@@ -1135,8 +1137,17 @@ func (a *App) broadcastTransferAssigned(transfer *models.AgentTransfer) {
 }
 
 // saveAndFinalizeTransfer handles the common post-creation steps for agent transfers:
-// sets SLA deadlines, saves to DB, updates contact assignment, optionally ends chatbot sessions, and broadcasts.
+// applies the assignment fallback, sets SLA deadlines, saves to DB, updates contact assignment,
+// optionally ends chatbot sessions, and broadcasts.
 func (a *App) saveAndFinalizeTransfer(transfer *models.AgentTransfer, account *models.WhatsAppAccount, contact *models.Contact, settings *models.ChatbotSettings, endChatbotSession bool) error {
+	// Hand general-queue transfers straight to an agent when auto-assignment is on
+	if transfer.AgentID == nil && transfer.TeamID == nil && settings != nil {
+		transfer.AgentID = a.assignToOrgAgent(transfer.OrganizationID, settings.AgentAssignment.Strategy)
+	}
+
+	// Route to the configured fallback if nobody is available to take it
+	a.applyAssignmentFallback(transfer, settings)
+
 	// Set SLA deadlines
 	if settings != nil {
 		a.SetSLADeadlines(transfer, settings)
@@ -1211,7 +1222,7 @@ func (a *App) createTransferFromKeyword(account *models.WhatsAppAccount, contact
 
 	// Check business hours - if outside hours, send out of hours message instead of transfer
 	if settings != nil && settings.BusinessHours.Enabled && len(settings.BusinessHours.Hours) > 0 {
-		if !a.isWithinBusinessHours(settings.BusinessHours.Hours) {
+		if !settings.BusinessHours.IsWithinBusinessHours(time.Now()) {
 			a.Log.Info("Outside business hours, sending out of hours message instead of transfer", "contact_id", contact.ID)
 			if settings.BusinessHours.OutOfHoursMessage != "" {
 				_ = a.sendAndSaveTextMessage(account, contact, settings.BusinessHours.OutOfHoursMessage)
@@ -1224,7 +1235,8 @@ func (a *App) createTransferFromKeyword(account *models.WhatsAppAccount, contact
 	var agentID *uuid.UUID
 	if settings != nil && settings.AgentAssignment.AssignToSameAgent && contact.AssignedUserID != nil {
 		var assignedAgent models.User
-		if a.DB.Where("id = ?", contact.AssignedUserID).First(&assignedAgent).Error == nil && assignedAgent.IsAvailable {
+		if a.DB.Where("id = ?", contact.AssignedUserID).First(&assignedAgent).Error == nil && assignedAgent.IsAvailable &&
+			a.isOnShift(account.OrganizationID, assignedAgent.ID) {
 			agentID = contact.AssignedUserID
 		}
 	}
@@ -1247,8 +1259,8 @@ func (a *App) createTransferFromKeyword(account *models.WhatsAppAccount, contact
 	}
 
 	var agentIDStr string
-	if agentID != nil {
-		agentIDStr = agentID.String()
+	if transfer.AgentID != nil {
+		agentIDStr = transfer.AgentID.String()
 	}
 	a.Log.Info("Agent transfer created from keyword rule",
 		"transfer_id", transfer.ID,
@@ -1257,6 +1269,72 @@ func (a *App) createTransferFromKeyword(account *models.WhatsAppAccount, contact
 	)
 }
 
+// applyAssignmentFallback routes an unassigned transfer to the organization's configured
+// fallback when no available agent could pick it up from its queue. Transfers that already
+// have an agent, or whose queue still has someone available, are left untouched.
+func (a *App) applyAssignmentFallback(transfer *models.AgentTransfer, settings *models.ChatbotSettings) {
+	if transfer.AgentID != nil || settings == nil || settings.AgentAssignment.FallbackType == "" {
+		return
+	}
+	if a.hasAvailableAgent(transfer.OrganizationID, transfer.TeamID) {
+		return
+	}
+
+	cfg := settings.AgentAssignment
+	switch cfg.FallbackType {
+	case models.AssignmentFallbackUser:
+		if cfg.FallbackUserID == nil {
+			return
+		}
+		// The fallback user owns the conversation even while away, but must still be active
+		var user models.User
+		if err := a.DB.Where("id = ? AND is_active = ?", *cfg.FallbackUserID, true).First(&user).Error; err != nil {
+			a.Log.Warn("Fallback assignee not found or inactive", "user_id", *cfg.FallbackUserID)
+			return
+		}
+		transfer.AgentID = &user.ID
+	case models.AssignmentFallbackTeam:
+		if cfg.FallbackTeamID == nil {
+			return
+		}
+		teamID := *cfg.FallbackTeamID
+		transfer.TeamID = &teamID
+		transfer.AgentID = a.assignToTeam(teamID, transfer.OrganizationID)
+	case models.AssignmentFallbackQueue:
+		transfer.TeamID = nil
+	default:
+		return
+	}
+
+	a.Log.Info("No agent available, applied assignment fallback",
+		"contact_id", transfer.ContactID,
+		"fallback", cfg.FallbackType,
+	)
+}
+
+// hasAvailableAgent reports whether anyone can pick up a transfer in the given queue:
+// an available agent of the team, or any available member of the organization for the general queue
+func (a *App) hasAvailableAgent(orgID uuid.UUID, teamID *uuid.UUID) bool {
+	var userIDs []uuid.UUID
+	if teamID != nil {
+		a.DB.Model(&models.TeamMember{}).
+			Joins("JOIN users ON users.id = team_members.user_id AND users.deleted_at IS NULL").
+			Where("team_members.team_id = ? AND team_members.role = ? AND users.is_available = ? AND users.is_active = ?",
+				*teamID, models.TeamRoleAgent, true, true).
+			Pluck("team_members.user_id", &userIDs)
+	} else {
+		a.DB.Table("user_organizations").
+			Joins("JOIN users ON users.id = user_organizations.user_id AND users.deleted_at IS NULL").
+			Where("user_organizations.organization_id = ? AND user_organizations.deleted_at IS NULL AND users.is_available = ? AND users.is_active = ?",
+				orgID, true, true).
+			Pluck("user_organizations.user_id", &userIDs)
+	}
+
+	// Agents outside their working hours count as away
+	offShift := a.offShiftUsers(orgID, userIDs, time.Now())
+	return len(userIDs) > len(offShift)
+}
+
 // assignToTeam applies the team's assignment strategy to select an agent
 // Returns nil if manual strategy or no available agents
 func (a *App) assignToTeam(teamID uuid.UUID, orgID uuid.UUID) *uuid.UUID {
@@ -1281,6 +1359,16 @@ func (a *App) assignToTeam(teamID uuid.UUID, orgID uuid.UUID) *uuid.UUID {
 	}
 }
 
+// dropOffShiftMembers removes team members who are outside their working hours
+func (a *App) dropOffShiftMembers(orgID uuid.UUID, members []models.TeamMember) []models.TeamMember {
+	userIDs := make([]uuid.UUID, len(members))
+	for i, m := range members {
+		userIDs[i] = m.UserID
+	}
+	offShift := a.offShiftUsers(orgID, userIDs, time.Now())
+	return slices.DeleteFunc(members, func(m models.TeamMember) bool { return offShift[m.UserID] })
+}
+
 // assignToTeamRoundRobin selects the next agent using round-robin
 func (a *App) assignToTeamRoundRobin(teamID uuid.UUID, orgID uuid.UUID) *uuid.UUID {
 	// Get team members who are available agents, ordered by last assigned time
@@ -1291,6 +1379,7 @@ func (a *App) assignToTeamRoundRobin(teamID uuid.UUID, orgID uuid.UUID) *uuid.UU
 			teamID, models.TeamRoleAgent, true, true).
 		Order("team_members.last_assigned_at ASC NULLS FIRST").
 		Find(&members).Error
+	members = a.dropOffShiftMembers(orgID, members)
 
 	if err != nil || len(members) == 0 {
 		a.Log.Debug("No available agents in team for round-robin", "team_id", teamID)
@@ -1317,6 +1406,7 @@ func (a *App) assignToTeamLoadBalanced(teamID uuid.UUID, orgID uuid.UUID) *uuid.
 		Where("team_members.team_id = ? AND team_members.role = ? AND users.is_available = ? AND users.is_active = ?",
 			teamID, models.TeamRoleAgent, true, true).
 		Find(&members).Error
+	members = a.dropOffShiftMembers(orgID, members)
 
 	if err != nil || len(members) == 0 {
 		a.Log.Debug("No available agents in team for load-balanced", "team_id", teamID)
@@ -1367,6 +1457,127 @@ func (a *App) assignToTeamLoadBalanced(teamID uuid.UUID, orgID uuid.UUID) *uuid.
 	return lowestUserID
 }
 
+// maxOrgAssignAttempts bounds how often assignToOrgAgent retries after
+// losing an agent to a concurrent assignment
+const maxOrgAssignAttempts = 3
+
+// assignToOrgAgent selects an available organization member using the chatbot's assignment strategy
+// Returns nil for manual strategy or when nobody is available
+func (a *App) assignToOrgAgent(orgID uuid.UUID, strategy models.AssignmentStrategy) *uuid.UUID {
+	if strategy != models.AssignmentStrategyRoundRobin && strategy != models.AssignmentStrategyLeastBusy {
+		return nil
+	}
+
+	for attempt := 0; attempt < maxOrgAssignAttempts; attempt++ {
+		selected, ok := a.pickOrgAgent(orgID, strategy)
+		if !ok {
+			a.Log.Debug("No available agents in organization for auto-assignment", "org_id", orgID, "strategy", strategy)
+			return nil
+		}
+
+		// Claim the pick only if nobody assigned to this member since it was read,
+		// so concurrent conversations don't all land on the same agent
+		result := a.DB.Model(&models.UserOrganization{}).
+			Where("id = ? AND last_assigned_at IS NOT DISTINCT FROM ?", selected.ID, selected.LastAssignedAt).
+			Update("last_assigned_at", time.Now())
+		if result.Error != nil {
+			a.Log.Error("Failed to record auto-assignment", "error", result.Error, "org_id", orgID, "user_id", selected.UserID)
+			return nil
+		}
+		if result.RowsAffected == 1 {
+			a.Log.Debug("Auto-assigned conversation to agent", "org_id", orgID, "user_id", selected.UserID, "strategy", strategy)
+			userID := selected.UserID
+			return &userID
+		}
+	}
+
+	a.Log.Warn("Gave up auto-assigning after concurrent assignments", "org_id", orgID, "strategy", strategy)
+	return nil
+}
+
+// pickOrgAgent returns the available organization member the strategy picks next
+func (a *App) pickOrgAgent(orgID uuid.UUID, strategy models.AssignmentStrategy) (models.UserOrganization, bool) {
+	// Available members, least recently assigned first so ties stay fair
+	var members []models.UserOrganization
+	err := a.DB.
+		Joins("JOIN users ON users.id = user_organizations.user_id AND users.deleted_at IS NULL").
+		Where("user_organizations.organization_id = ? AND users.is_available = ? AND users.is_active = ?", orgID, true, true).
+		Order("user_organizations.last_assigned_at ASC NULLS FIRST, user_organizations.created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		a.Log.Error("Failed to load agents for auto-assignment", "error", err, "org_id", orgID)
+		return models.UserOrganization{}, false
+	}
+	userIDs := make([]uuid.UUID, len(members))
+	for i, m := range members {
+		userIDs[i] = m.UserID
+	}
+	offShift := a.offShiftUsers(orgID, userIDs, time.Now())
+	members = slices.DeleteFunc(members, func(m models.UserOrganization) bool { return offShift[m.UserID] })
+	if len(members) == 0 {
+		return models.UserOrganization{}, false
+	}
+
+	selected := members[0]
+	if strategy == models.AssignmentStrategyLeastBusy {
+		memberIDs := make([]uuid.UUID, len(members))
+		for i, m := range members {
+			memberIDs[i] = m.UserID
+		}
+
+		type AgentLoad struct {
+			AgentID uuid.UUID `gorm:"column:agent_id"`
+			Count   int64     `gorm:"column:count"`
+		}
+		var loads []AgentLoad
+		a.DB.Model(&models.AgentTransfer{}).
+			Select("agent_id, COUNT(*) as count").
+			Where("organization_id = ? AND agent_id IN ? AND status = ?", orgID, memberIDs, models.TransferStatusActive).
+			Group("agent_id").
+			Scan(&loads)
+
+		loadMap := make(map[uuid.UUID]int64)
+		for _, l := range loads {
+			loadMap[l.AgentID] = l.Count
+		}
+
+		// Strictly lower load wins, so equal loads keep round-robin order
+		lowestCount := loadMap[selected.UserID]
+		for _, m := range members[1:] {
+			if count := loadMap[m.UserID]; count < lowestCount {
+				lowestCount = count
+				selected = m
+			}
+		}
+	}
+	return selected, true
+}
+
+// assignNewConversation hands an unassigned contact to an agent using the
+// chatbot's assignment strategy
+func (a *App) assignNewConversation(contact *models.Contact, settings *models.ChatbotSettings) {
+	if contact.AssignedUserID != nil {
+		return
+	}
+	agentID := a.assignToOrgAgent(contact.OrganizationID, settings.AgentAssignment.Strategy)
+	if agentID == nil {
+		return
+	}
+
+	// Leave the contact alone if someone assigned it in the meantime
+	result := a.DB.Model(&models.Contact{}).
+		Where("id = ? AND assigned_user_id IS NULL", contact.ID).
+		Update("assigned_user_id", agentID)
+	if result.Error != nil {
+		a.Log.Error("Failed to auto-assign contact", "error", result.Error, "contact_id", contact.ID)
+		return
+	}
+	if result.RowsAffected == 1 {
+		contact.AssignedUserID = agentID
+		a.Log.Info("New conversation auto-assigned", "contact_id", contact.ID, "user_id", *agentID)
+	}
+}
+
 // createTransferToTeam creates an agent transfer to a specific team with appropriate assignment
 func (a *App) createTransferToTeam(account *models.WhatsAppAccount, contact *models.Contact, teamID uuid.UUID, notes string, source models.TransferSource) {
 	if a.hasActiveAgentTransfer(account.OrganizationID, contact.ID) {
@@ -1398,8 +1609,8 @@ func (a *App) createTransferToTeam(account *models.WhatsAppAccount, contact *mod
 	}
 
 	var agentIDStrLog string
-	if agentID != nil {
-		agentIDStrLog = agentID.String()
+	if transfer.AgentID != nil {
+		agentIDStrLog = transfer.AgentID.String()
 	}
 	a.Log.Info("Agent transfer created to team",
 		"transfer_id", transfer.ID,
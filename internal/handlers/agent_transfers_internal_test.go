@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createFallbackTestAgent creates an agent in the org with the given availability.
+func createFallbackTestAgent(t *testing.T, app *App, orgID uuid.UUID, available bool) *models.User {
+	t.Helper()
+	user := testutil.CreateTestUser(t, app.DB, orgID, testutil.WithEmail(testutil.UniqueEmail("fallback-agent")))
+	// is_available defaults to true in the DB, so set it explicitly after create
+	require.NoError(t, app.DB.Model(user).Update("is_available", available).Error)
+	user.IsAvailable = available
+	return user
+}
+
+// createFallbackTestTeam creates an active round-robin team with the given agents.
+func createFallbackTestTeam(t *testing.T, app *App, orgID uuid.UUID, members ...uuid.UUID) *models.Team {
+	t.Helper()
+	team := &models.Team{
+		BaseModel:          models.BaseModel{ID: uuid.New()},
+		OrganizationID:     orgID,
+		Name:               "Fallback Team " + uuid.New().String()[:8],
+		IsActive:           true,
+		AssignmentStrategy: models.AssignmentStrategyRoundRobin,
+	}
+	require.NoError(t, app.DB.Create(team).Error)
+	for _, userID := range members {
+		require.NoError(t, app.DB.Create(&models.TeamMember{
+			TeamID: team.ID,
+			UserID: userID,
+			Role:   models.TeamRoleAgent,
+		}).Error)
+	}
+	return team
+}
+
+// newFallbackTestTransfer builds an unassigned, unsaved transfer for the contact.
+func newFallbackTestTransfer(account *models.WhatsAppAccount, contact *models.Contact, teamID *uuid.UUID) *models.AgentTransfer {
+	return &models.AgentTransfer{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  account.OrganizationID,
+		ContactID:       contact.ID,
+		WhatsAppAccount: account.Name,
+		PhoneNumber:     contact.PhoneNumber,
+		Status:          models.TransferStatusActive,
+		Source:          models.TransferSourceKeyword,
+		TeamID:          teamID,
+		TransferredAt:   time.Now(),
+	}
+}
+
+func TestAssignmentFallback_UserWhenNoAgentAvailable(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+	createFallbackTestAgent(t, app, org.ID, false)
+	supervisor := createFallbackTestAgent(t, app, org.ID, false)
+
+	settings := &models.ChatbotSettings{
+		OrganizationID: org.ID,
+		AgentAssignment: models.AgentAssignmentConfig{
+			FallbackType:   models.AssignmentFallbackUser,
+			FallbackUserID: &supervisor.ID,
+		},
+	}
+
+	transfer := newFallbackTestTransfer(account, contact, nil)
+	require.NoError(t, app.saveAndFinalizeTransfer(transfer, account, contact, settings, false))
+
+	var saved models.AgentTransfer
+	require.NoError(t, app.DB.First(&saved, transfer.ID).Error)
+	require.NotNil(t, saved.AgentID)
+	assert.Equal(t, supervisor.ID, *saved.AgentID)
+
+	var updated models.Contact
+	require.NoError(t, app.DB.First(&updated, contact.ID).Error)
+	require.NotNil(t, updated.AssignedUserID)
+	assert.Equal(t, supervisor.ID, *updated.AssignedUserID)
+}
+
+func TestAssignmentFallback_TeamWhenTeamHasNoAgentAvailable(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+	away := createFallbackTestAgent(t, app, org.ID, false)
+	onCall := createFallbackTestAgent(t, app, org.ID, true)
+	salesTeam := createFallbackTestTeam(t, app, org.ID, away.ID)
+	overflowTeam := createFallbackTestTeam(t, app, org.ID, onCall.ID)
+
+	settings := &models.ChatbotSettings{
+		OrganizationID: org.ID,
+		AgentAssignment: models.AgentAssignmentConfig{
+			FallbackType:   models.AssignmentFallbackTeam,
+			FallbackTeamID: &overflowTeam.ID,
+		},
+	}
+
+	transfer := newFallbackTestTransfer(account, contact, &salesTeam.ID)
+	require.NoError(t, app.saveAndFinalizeTransfer(transfer, account, contact, settings, false))
+
+	var saved models.AgentTransfer
+	require.NoError(t, app.DB.First(&saved, transfer.ID).Error)
+	require.NotNil(t, saved.TeamID)
+	assert.Equal(t, overflowTeam.ID, *saved.TeamID)
+	require.NotNil(t, saved.AgentID)
+	assert.Equal(t, onCall.ID, *saved.AgentID)
+}
+
+func TestAssignmentFallback_QueueClearsTeam(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+	away := createFallbackTestAgent(t, app, org.ID, false)
+	team := createFallbackTestTeam(t, app, org.ID, away.ID)
+
+	settings := &models.ChatbotSettings{
+		OrganizationID:  org.ID,
+		AgentAssignment: models.AgentAssignmentConfig{FallbackType: models.AssignmentFallbackQueue},
+	}
+
+	transfer := newFallbackTestTransfer(account, contact, &team.ID)
+	require.NoError(t, app.saveAndFinalizeTransfer(transfer, account, contact, settings, false))
+
+	var saved models.AgentTransfer
+	require.NoError(t, app.DB.First(&saved, transfer.ID).Error)
+	assert.Nil(t, saved.TeamID)
+	assert.Nil(t, saved.AgentID)
+}
+
+func TestAssignmentFallback_NotAppliedWhenAgentAvailable(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+	createFallbackTestAgent(t, app, org.ID, true)
+	supervisor := createFallbackTestAgent(t, app, org.ID, false)
+
+	settings := &models.ChatbotSettings{
+		OrganizationID: org.ID,
+		AgentAssignment: models.AgentAssignmentConfig{
+			FallbackType:   models.AssignmentFallbackUser,
+			FallbackUserID: &supervisor.ID,
+		},
+	}
+
+	transfer := newFallbackTestTransfer(account, contact, nil)
+	require.NoError(t, app.saveAndFinalizeTransfer(transfer, account, contact, settings, false))
+
+	var saved models.AgentTransfer
+	require.NoError(t, app.DB.First(&saved, transfer.ID).Error)
+	assert.Nil(t, saved.AgentID, "transfer should stay in the queue while an agent is available")
+}
+
+func TestAssignToOrgAgent_RoundRobinSkipsUnavailable(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+	first := createFallbackTestAgent(t, app, org.ID, true)
+	second := createFallbackTestAgent(t, app, org.ID, true)
+	away := createFallbackTestAgent(t, app, org.ID, false)
+
+	counts := make(map[uuid.UUID]int)
+	for i := 0; i < 6; i++ {
+		picked := app.assignToOrgAgent(org.ID, models.AssignmentStrategyRoundRobin)
+		require.NotNil(t, picked)
+		counts[*picked]++
+	}
+
+	assert.Equal(t, 3, counts[first.ID])
+	assert.Equal(t, 3, counts[second.ID])
+	assert.Zero(t, counts[away.ID])
+}
+
+func TestAssignToOrgAgent_LeastBusyPicksLowestLoad(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	busy := createFallbackTestAgent(t, app, org.ID, true)
+	idle := createFallbackTestAgent(t, app, org.ID, true)
+
+	for i := 0; i < 2; i++ {
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+		transfer := newFallbackTestTransfer(account, contact, nil)
+		transfer.AgentID = &busy.ID
+		require.NoError(t, app.DB.Create(transfer).Error)
+	}
+
+	picked := app.assignToOrgAgent(org.ID, models.AssignmentStrategyLeastBusy)
+	require.NotNil(t, picked)
+	assert.Equal(t, idle.ID, *picked)
+}
+
+func TestAssignToOrgAgent_ConcurrentPicksGoToDifferentAgents(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+	first := createFallbackTestAgent(t, app, org.ID, true)
+	second := createFallbackTestAgent(t, app, org.ID, true)
+
+	var wg sync.WaitGroup
+	picked := make([]*uuid.UUID, 2)
+	for i := range picked {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			picked[i] = app.assignToOrgAgent(org.ID, models.AssignmentStrategyRoundRobin)
+		}(i)
+	}
+	wg.Wait()
+
+	require.NotNil(t, picked[0])
+	require.NotNil(t, picked[1])
+	assert.ElementsMatch(t, []uuid.UUID{first.ID, second.ID}, []uuid.UUID{*picked[0], *picked[1]})
+}
+
+func TestProcessIncomingMessage_AssignsNewConversation(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+	agent := createFallbackTestAgent(t, app, org.ID, true)
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:          models.BaseModel{ID: uuid.New()},
+		OrganizationID:     org.ID,
+		WhatsAppAccount:    account.Name,
+		IsEnabled:          true,
+		SessionTimeoutMins: 30,
+		AgentAssignment:    models.AgentAssignmentConfig{Strategy: models.AssignmentStrategyRoundRobin},
+	}).Error)
+
+	phone := uniqueTestPhone()
+	app.processIncomingMessageFull(account.PhoneID, incomingText(phone, "hi"), "Newcomer")
+
+	var contact models.Contact
+	require.NoError(t, app.DB.Where("organization_id = ? AND phone_number = ?", org.ID, phone).First(&contact).Error)
+	require.NotNil(t, contact.AssignedUserID)
+	assert.Equal(t, agent.ID, *contact.AssignedUserID)
+
+	// An assigned contact keeps its agent
+	other := createFallbackTestAgent(t, app, org.ID, true)
+	app.assignNewConversation(&contact, &models.ChatbotSettings{
+		AgentAssignment: models.AgentAssignmentConfig{Strategy: models.AssignmentStrategyRoundRobin},
+	})
+	require.NoError(t, app.DB.First(&contact, contact.ID).Error)
+	assert.Equal(t, agent.ID, *contact.AssignedUserID)
+	assert.NotEqual(t, other.ID, *contact.AssignedUserID)
+}
+
+func TestAssignToOrgAgent_ManualAssignsNobody(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+	createFallbackTestAgent(t, app, org.ID, true)
+
+	assert.Nil(t, app.assignToOrgAgent(org.ID, models.AssignmentStrategyManual))
+	assert.Nil(t, app.assignToOrgAgent(org.ID, ""))
+}
+
+func TestSaveAndFinalizeTransfer_AutoAssignsQueueTransfer(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+	agent := createFallbackTestAgent(t, app, org.ID, true)
+
+	settings := &models.ChatbotSettings{
+		OrganizationID:  org.ID,
+		AgentAssignment: models.AgentAssignmentConfig{Strategy: models.AssignmentStrategyRoundRobin},
+	}
+
+	transfer := newFallbackTestTransfer(account, contact, nil)
+	require.NoError(t, app.saveAndFinalizeTransfer(transfer, account, contact, settings, false))
+
+	var saved models.AgentTransfer
+	require.NoError(t, app.DB.First(&saved, transfer.ID).Error)
+	require.NotNil(t, saved.AgentID)
+	assert.Equal(t, agent.ID, *saved.AgentID)
+
+	var updated models.Contact
+	require.NoError(t, app.DB.First(&updated, contact.ID).Error)
+	require.NotNil(t, updated.AssignedUserID)
+	assert.Equal(t, agent.ID, *updated.AssignedUserID)
+}
+
+// setOffShiftToday gives the user a schedule that has today switched off.
+func setOffShiftToday(t *testing.T, app *App, orgID, userID uuid.UUID) {
+	t.Helper()
+	require.NoError(t, app.DB.Create(&models.UserSchedule{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		UserID:         userID,
+		OrganizationID: orgID,
+		Hours: models.JSONBArray{
+			map[string]interface{}{"day": int(time.Now().Weekday()), "enabled": false},
+		},
+	}).Error)
+}
+
+func TestAssignToOrgAgent_SkipsAgentsOffShift(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+	onShift := createFallbackTestAgent(t, app, org.ID, true)
+	offShift := createFallbackTestAgent(t, app, org.ID, true)
+	setOffShiftToday(t, app, org.ID, offShift.ID)
+
+	for i := 0; i < 3; i++ {
+		picked := app.assignToOrgAgent(org.ID, models.AssignmentStrategyRoundRobin)
+		require.NotNil(t, picked)
+		assert.Equal(t, onShift.ID, *picked)
+	}
+}
+
+func TestAssignToTeam_SkipsAgentsOffShift(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+	onShift := createFallbackTestAgent(t, app, org.ID, true)
+	offShift := createFallbackTestAgent(t, app, org.ID, true)
+	setOffShiftToday(t, app, org.ID, offShift.ID)
+	team := createFallbackTestTeam(t, app, org.ID, offShift.ID, onShift.ID)
+
+	for i := 0; i < 3; i++ {
+		picked := app.assignToTeam(team.ID, org.ID)
+		require.NotNil(t, picked)
+		assert.Equal(t, onShift.ID, *picked)
+	}
+}
+
+func TestHasAvailableAgent_OffShiftCountsAsAway(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+	agent := createFallbackTestAgent(t, app, org.ID, true)
+	team := createFallbackTestTeam(t, app, org.ID, agent.ID)
+
+	assert.True(t, app.hasAvailableAgent(org.ID, nil))
+	assert.True(t, app.hasAvailableAgent(org.ID, &team.ID))
+
+	setOffShiftToday(t, app, org.ID, agent.ID)
+	assert.False(t, app.hasAvailableAgent(org.ID, nil))
+	assert.False(t, app.hasAvailableAgent(org.ID, &team.ID))
+}
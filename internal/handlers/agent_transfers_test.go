@@ -864,3 +864,124 @@ func TestApp_ReturnAgentTransfersToQueue(t *testing.T) {
 	assert.Nil(t, updatedTransfer1.AgentID)
 	assert.Nil(t, updatedTransfer2.AgentID)
 }
+
+// createTestSessionWithStatus creates a chatbot session for the contact in the database.
+func createTestSessionWithStatus(t *testing.T, app *handlers.App, orgID uuid.UUID, contact *models.Contact, accountName string, status models.SessionStatus) *models.ChatbotSession {
+	t.Helper()
+
+	session := &models.ChatbotSession{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  orgID,
+		ContactID:       contact.ID,
+		WhatsAppAccount: accountName,
+		PhoneNumber:     contact.PhoneNumber,
+		Status:          status,
+		CurrentStep:     "ask_issue",
+		SessionData:     models.JSONB{"issue": "billing"},
+		StartedAt:       time.Now(),
+		LastActivityAt:  time.Now(),
+	}
+	require.NoError(t, app.DB.Create(session).Error)
+	return session
+}
+
+func TestApp_TransferChatbotSession_Success(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	agent := createTestAgent(t, app, org.ID)
+	session := createTestSessionWithStatus(t, app, org.ID, contact, account.Name, models.SessionStatusActive)
+
+	req := testutil.NewJSONRequest(t, map[string]any{
+		"agent_id": agent.ID.String(),
+		"reason":   "Customer asked for a human",
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", session.ID.String())
+
+	err := app.TransferChatbotSession(req)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var result struct {
+		Data handlers.TransferChatbotSessionResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &result))
+	require.NotNil(t, result.Data.AgentID)
+	assert.Equal(t, agent.ID, *result.Data.AgentID)
+	assert.Equal(t, "Test Agent", result.Data.AgentName)
+
+	// Session is handed off and records the transfer
+	var updated models.ChatbotSession
+	require.NoError(t, app.DB.First(&updated, session.ID).Error)
+	assert.Equal(t, models.SessionStatusTransferred, updated.Status)
+	assert.NotNil(t, updated.CompletedAt)
+	assert.Equal(t, "billing", updated.SessionData["issue"])
+	record, ok := updated.SessionData["_transfer"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, result.Data.TransferID.String(), record["transfer_id"])
+	assert.Equal(t, "Customer asked for a human", record["reason"])
+	assert.NotEmpty(t, record["transferred_at"])
+
+	// Contact is assigned to the agent with an active transfer
+	var updatedContact models.Contact
+	require.NoError(t, app.DB.First(&updatedContact, contact.ID).Error)
+	require.NotNil(t, updatedContact.AssignedUserID)
+	assert.Equal(t, agent.ID, *updatedContact.AssignedUserID)
+
+	var transfer models.AgentTransfer
+	require.NoError(t, app.DB.First(&transfer, result.Data.TransferID).Error)
+	assert.Equal(t, models.TransferStatusActive, transfer.Status)
+	assert.Equal(t, "Customer asked for a human", transfer.Notes)
+}
+
+func TestApp_TransferChatbotSession_NotActive(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	session := createTestSessionWithStatus(t, app, org.ID, contact, account.Name, models.SessionStatusCompleted)
+
+	req := testutil.NewJSONRequest(t, map[string]any{"reason": "too late"})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", session.ID.String())
+
+	err := app.TransferChatbotSession(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+	var count int64
+	app.DB.Model(&models.AgentTransfer{}).Where("contact_id = ?", contact.ID).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestApp_TransferChatbotSession_CrossOrgIsolation(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	otherOrg := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	otherAccount := testutil.CreateTestWhatsAppAccount(t, app.DB, otherOrg.ID)
+
+	otherContact := testutil.CreateTestContact(t, app.DB, otherOrg.ID)
+	session := createTestSessionWithStatus(t, app, otherOrg.ID, otherContact, otherAccount.Name, models.SessionStatusActive)
+
+	req := testutil.NewJSONRequest(t, map[string]any{"reason": "not mine"})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", session.ID.String())
+
+	err := app.TransferChatbotSession(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+
+	var unchanged models.ChatbotSession
+	require.NoError(t, app.DB.First(&unchanged, session.ID).Error)
+	assert.Equal(t, models.SessionStatusActive, unchanged.Status)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+)
+
+// aiQueueTimeout is how long a queued AI request waits for a free slot before giving up
+var aiQueueTimeout = 10 * time.Second
+
+var (
+	errAIQueueFull    = errors.New("AI request queue is full")
+	errAIQueueTimeout = errors.New("timed out waiting for an AI request slot")
+)
+
+// aiLimiter bounds the number of in-flight AI requests for one organization.
+// Requests beyond the limit wait in a short queue; once the queue is full
+// they are rejected so the caller can fall back.
+type aiLimiter struct {
+	maxConcurrency int
+	queueSize      int
+	slots          chan struct{}
+	waiting        atomic.Int32
+}
+
+func newAILimiter(maxConcurrency, queueSize int) *aiLimiter {
+	return &aiLimiter{
+		maxConcurrency: maxConcurrency,
+		queueSize:      queueSize,
+		slots:          make(chan struct{}, maxConcurrency),
+	}
+}
+
+// acquire takes a slot, queueing briefly if none is free. The returned
+// function must be called to release the slot.
+func (l *aiLimiter) acquire() (func(), error) {
+	release := func() { <-l.slots }
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if int(l.waiting.Add(1)) > l.queueSize {
+		l.waiting.Add(-1)
+		return nil, errAIQueueFull
+	}
+	defer l.waiting.Add(-1)
+
+	timer := time.NewTimer(aiQueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, errAIQueueTimeout
+	}
+}
+
+// acquireAISlot reserves an AI request slot for the organization according to
+// its configured concurrency limit. A limit of 0 means unlimited.
+func (a *App) acquireAISlot(orgID uuid.UUID, cfg models.AIConfig) (func(), error) {
+	if cfg.MaxConcurrency <= 0 {
+		return func() {}, nil
+	}
+	return a.getAILimiter(orgID, cfg.MaxConcurrency, cfg.QueueSize).acquire()
+}
+
+// getAILimiter returns the organization's limiter, replacing it when the
+// configured limits have changed. Requests holding a slot on a replaced
+// limiter release it there, so the new limits apply to new requests only.
+func (a *App) getAILimiter(orgID uuid.UUID, maxConcurrency, queueSize int) *aiLimiter {
+	if v, ok := a.aiLimiters.Load(orgID); ok {
+		l := v.(*aiLimiter)
+		if l.maxConcurrency == maxConcurrency && l.queueSize == queueSize {
+			return l
+		}
+		l = newAILimiter(maxConcurrency, queueSize)
+		a.aiLimiters.Store(orgID, l)
+		return l
+	}
+	v, _ := a.aiLimiters.LoadOrStore(orgID, newAILimiter(maxConcurrency, queueSize))
+	return v.(*aiLimiter)
+}
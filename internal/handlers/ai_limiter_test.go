@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingAITransport holds every AI request until released and records peak concurrency.
+type blockingAITransport struct {
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	started     chan struct{}
+	release     chan struct{}
+}
+
+func newBlockingAITransport() *blockingAITransport {
+	return &blockingAITransport{
+		started: make(chan struct{}, 10),
+		release: make(chan struct{}),
+	}
+}
+
+func (t *blockingAITransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := t.inFlight.Add(1)
+	for {
+		peak := t.maxInFlight.Load()
+		if n <= peak || t.maxInFlight.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	t.started <- struct{}{}
+	<-t.release
+	t.inFlight.Add(-1)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"choices":[{"message":{"content":"ok"}}]}`)),
+		Request:    req,
+	}, nil
+}
+
+func limitedAISettings(orgID uuid.UUID, maxConcurrency, queueSize int) *models.ChatbotSettings {
+	return &models.ChatbotSettings{
+		OrganizationID: orgID,
+		AI: models.AIConfig{
+			Enabled:        true,
+			Provider:       models.AIProviderOpenAI,
+			APIKey:         "sk-openai-test",
+			Model:          "gpt-4o-mini",
+			MaxTokens:      200,
+			MaxConcurrency: maxConcurrency,
+			QueueSize:      queueSize,
+		},
+	}
+}
+
+func TestGenerateAIResponse_SerializesBeyondConcurrencyLimit(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, _ := createProcessorTestOrg(t, app)
+
+	transport := newBlockingAITransport()
+	app.HTTPClient = &http.Client{Transport: transport}
+	settings := limitedAISettings(org.ID, 1, 5)
+
+	const requests = 3
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := app.generateAIResponse(settings, nil, "hello")
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < requests; i++ {
+		select {
+		case <-transport.started:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("request %d never reached the provider", i+1)
+		}
+		// Give queued requests a chance to (incorrectly) start alongside this one
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(1), transport.inFlight.Load())
+		transport.release <- struct{}{}
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), transport.maxInFlight.Load())
+}
+
+func TestGenerateAIResponse_QueueFullReturnsError(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+	settings := limitedAISettings(org.ID, 1, 0)
+
+	release, err := app.acquireAISlot(org.ID, settings.AI)
+	require.NoError(t, err)
+	defer release()
+
+	_, err = app.generateAIResponse(settings, nil, "hello")
+	assert.ErrorIs(t, err, errAIQueueFull)
+}
+
+func TestProcessIncomingMessage_AIQueueFullSendsFallback(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+
+	transport := &aiProviderTransport{body: `{"choices":[{"message":{"content":"Hi from GPT"}}]}`}
+	app.HTTPClient = &http.Client{Transport: transport}
+
+	settings := limitedAISettings(org.ID, 1, 0)
+	settings.WhatsAppAccount = account.Name
+	settings.IsEnabled = true
+	settings.SessionTimeoutMins = 30
+	settings.FallbackMessage = "We're busy right now, please try again shortly."
+	require.NoError(t, app.DB.Create(settings).Error)
+
+	phone := uniqueTestPhone()
+	send := func(id, body string) {
+		msg := IncomingTextMessage{From: phone, ID: id, Type: "text"}
+		msg.Text = &struct {
+			Body string `json:"body"`
+		}{Body: body}
+		app.processIncomingMessageFull(account.PhoneID, msg, "Customer")
+	}
+
+	// First message starts the session and gets an AI answer
+	send("wamid.ai-1", "hello")
+	require.Len(t, transport.hosts, 1)
+
+	// Occupy the only slot so the next request overflows the (empty) queue
+	release, err := app.acquireAISlot(org.ID, settings.AI)
+	require.NoError(t, err)
+	defer release()
+
+	send("wamid.ai-2", "are you there?")
+	assert.Len(t, transport.hosts, 1, "overflowing request should not reach the provider")
+
+	var fallbacks int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("organization_id = ? AND direction = ? AND content = ?", org.ID, models.DirectionOutgoing, settings.FallbackMessage).
+		Count(&fallbacks).Error)
+	assert.Equal(t, int64(1), fallbacks)
+}
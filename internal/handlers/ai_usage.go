@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/config"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// aiTokenUsage is the token count reported by an AI provider for one request
+type aiTokenUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// aiModelPrice is a model's list price in USD per million tokens
+type aiModelPrice struct {
+	Prompt     float64
+	Completion float64
+}
+
+// aiModelPricing maps model name prefixes to list prices. The longest matching
+// prefix wins, so dated and -latest model names share their family's price.
+// Providers change their prices, so these can be overridden with
+// ai.model_prices in the config.
+var aiModelPricing = map[string]aiModelPrice{
+	"gpt-4o":            {Prompt: 2.50, Completion: 10.00},
+	"gpt-4o-mini":       {Prompt: 0.15, Completion: 0.60},
+	"gpt-4.1":           {Prompt: 2.00, Completion: 8.00},
+	"gpt-4.1-mini":      {Prompt: 0.40, Completion: 1.60},
+	"gpt-4.1-nano":      {Prompt: 0.10, Completion: 0.40},
+	"gpt-4-turbo":       {Prompt: 10.00, Completion: 30.00},
+	"gpt-3.5-turbo":     {Prompt: 0.50, Completion: 1.50},
+	"claude-opus-4":     {Prompt: 15.00, Completion: 75.00},
+	"claude-sonnet-4":   {Prompt: 3.00, Completion: 15.00},
+	"claude-3-7-sonnet": {Prompt: 3.00, Completion: 15.00},
+	"claude-3-5-sonnet": {Prompt: 3.00, Completion: 15.00},
+	"claude-3-5-haiku":  {Prompt: 0.80, Completion: 4.00},
+	"claude-3-opus":     {Prompt: 15.00, Completion: 75.00},
+	"claude-3-haiku":    {Prompt: 0.25, Completion: 1.25},
+	"gemini-2.5-pro":    {Prompt: 1.25, Completion: 10.00},
+	"gemini-2.5-flash":  {Prompt: 0.30, Completion: 2.50},
+	"gemini-2.0-flash":  {Prompt: 0.10, Completion: 0.40},
+	"gemini-1.5-pro":    {Prompt: 1.25, Completion: 5.00},
+	"gemini-1.5-flash":  {Prompt: 0.075, Completion: 0.30},
+}
+
+// estimateAICost returns the estimated USD cost of a request, or 0 when the
+// model has no known price. Configured prices take precedence over the
+// built-in ones for the same prefix.
+func estimateAICost(model string, usage aiTokenUsage, configured []config.AIModelPriceConfig) float64 {
+	prices := maps.Clone(aiModelPricing)
+	for _, p := range configured {
+		prices[strings.ToLower(p.Model)] = aiModelPrice{Prompt: p.Prompt, Completion: p.Completion}
+	}
+
+	model = strings.ToLower(model)
+	var price aiModelPrice
+	matched := ""
+	for prefix, p := range prices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(matched) {
+			matched, price = prefix, p
+		}
+	}
+	return (float64(usage.PromptTokens)*price.Prompt + float64(usage.CompletionTokens)*price.Completion) / 1_000_000
+}
+
+// recordAIUsage stores the tokens used by an AI request for usage reporting
+func (a *App) recordAIUsage(settings *models.ChatbotSettings, usage aiTokenUsage) {
+	if usage.PromptTokens == 0 && usage.CompletionTokens == 0 {
+		return
+	}
+
+	record := models.AIUsage{
+		BaseModel:        models.BaseModel{ID: uuid.New()},
+		OrganizationID:   settings.OrganizationID,
+		WhatsAppAccount:  settings.WhatsAppAccount,
+		Provider:         settings.AI.Provider,
+		Model:            settings.AI.Model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		EstimatedCost:    estimateAICost(settings.AI.Model, usage, a.Config.AI.ModelPrices),
+	}
+	if err := a.DB.Create(&record).Error; err != nil {
+		a.Log.Error("Failed to record AI usage", "error", err, "org_id", settings.OrganizationID)
+	}
+}
+
+// AIUsageTotals is aggregate AI usage
+type AIUsageTotals struct {
+	Requests         int64   `json:"requests"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	TotalTokens      int64   `json:"total_tokens"`
+	EstimatedCost    float64 `json:"estimated_cost"`
+}
+
+// AIUsageByModel is AI usage for one provider and model
+type AIUsageByModel struct {
+	Provider models.AIProvider `json:"provider"`
+	Model    string            `json:"model"`
+	AIUsageTotals
+}
+
+// AIUsageByDay is AI usage for one day
+type AIUsageByDay struct {
+	Date string `json:"date"`
+	AIUsageTotals
+}
+
+// AIUsageResponse is the organization's AI usage over a date range
+type AIUsageResponse struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Totals  AIUsageTotals    `json:"totals"`
+	ByModel []AIUsageByModel `json:"by_model"`
+	Daily   []AIUsageByDay   `json:"daily"`
+}
+
+// aiUsageSelect aggregates ai_usage rows into AIUsageTotals columns
+const aiUsageSelect = `COUNT(*) AS requests,
+	COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens,
+	COALESCE(SUM(completion_tokens), 0) AS completion_tokens,
+	COALESCE(SUM(prompt_tokens + completion_tokens), 0) AS total_tokens,
+	COALESCE(SUM(estimated_cost), 0) AS estimated_cost`
+
+// GetAIUsage returns AI requests, tokens and estimated cost for a date range
+// (default: the current month), in total, per model and per day
+func (a *App) GetAIUsage(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceAnalytics, models.ActionRead); err != nil {
+		return nil
+	}
+
+	fromStr := string(r.RequestCtx.QueryArgs().Peek("from"))
+	toStr := string(r.RequestCtx.QueryArgs().Peek("to"))
+
+	now := time.Now()
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := now
+	if fromStr != "" && toStr != "" {
+		var errMsg string
+		periodStart, periodEnd, errMsg = parseDateRange(fromStr, toStr)
+		if errMsg != "" {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+		}
+	}
+
+	usage := func() *gorm.DB {
+		return a.DB.Model(&models.AIUsage{}).
+			Where("organization_id = ? AND created_at >= ? AND created_at <= ?", orgID, periodStart, periodEnd)
+	}
+
+	resp := AIUsageResponse{
+		From:    periodStart.Format("2006-01-02"),
+		To:      periodEnd.Format("2006-01-02"),
+		ByModel: []AIUsageByModel{},
+		Daily:   []AIUsageByDay{},
+	}
+
+	if err := usage().Select(aiUsageSelect).Scan(&resp.Totals).Error; err != nil {
+		a.Log.Error("Failed to aggregate AI usage", "error", err, "org_id", orgID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to fetch AI usage", nil, "")
+	}
+	if err := usage().Select("provider, model, " + aiUsageSelect).
+		Group("provider, model").
+		Order("estimated_cost DESC, total_tokens DESC").
+		Scan(&resp.ByModel).Error; err != nil {
+		a.Log.Error("Failed to aggregate AI usage by model", "error", err, "org_id", orgID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to fetch AI usage", nil, "")
+	}
+	if err := usage().Select("TO_CHAR(DATE(created_at), 'YYYY-MM-DD') AS date, " + aiUsageSelect).
+		Group("DATE(created_at)").
+		Order("DATE(created_at) ASC").
+		Scan(&resp.Daily).Error; err != nil {
+		a.Log.Error("Failed to aggregate AI usage by day", "error", err, "org_id", orgID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to fetch AI usage", nil, "")
+	}
+
+	return r.SendEnvelope(resp)
+}
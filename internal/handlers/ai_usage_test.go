@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/shridarpatil/whatomate/internal/config"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateAICost(t *testing.T) {
+	usage := aiTokenUsage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000}
+
+	assert.InDelta(t, 12.50, estimateAICost("gpt-4o", usage, nil), 0.0001)
+	assert.InDelta(t, 0.75, estimateAICost("gpt-4o-mini-2024-07-18", usage, nil), 0.0001, "longest prefix wins")
+	assert.InDelta(t, 18.00, estimateAICost("claude-3-5-sonnet-latest", usage, nil), 0.0001)
+	assert.InDelta(t, 0.375, estimateAICost("Gemini-1.5-Flash", usage, nil), 0.0001)
+	assert.Zero(t, estimateAICost("my-local-model", usage, nil))
+}
+
+func TestEstimateAICost_ConfiguredPrices(t *testing.T) {
+	usage := aiTokenUsage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000}
+	configured := []config.AIModelPriceConfig{
+		{Model: "gpt-4o", Prompt: 2.00, Completion: 8.00},
+		{Model: "My-Local-Model", Prompt: 0.01, Completion: 0.02},
+	}
+
+	assert.InDelta(t, 10.00, estimateAICost("gpt-4o-2024-08-06", usage, configured), 0.0001, "configured price overrides the built-in one")
+	assert.InDelta(t, 0.75, estimateAICost("gpt-4o-mini", usage, configured), 0.0001, "longer built-in prefix still wins")
+	assert.InDelta(t, 0.03, estimateAICost("my-local-model-v2", usage, configured), 0.0001)
+	assert.InDelta(t, 12.50, estimateAICost("gpt-4o", usage, nil), 0.0001, "built-in prices are left unchanged")
+}
+
+func TestGenerateAIResponse_RecordsUsage(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+
+	transport := &aiProviderTransport{body: `{"content":[{"type":"text","text":"Hi"}],"usage":{"input_tokens":120,"output_tokens":30}}`}
+	app.HTTPClient = &http.Client{Transport: transport}
+
+	settings := &models.ChatbotSettings{
+		OrganizationID:  org.ID,
+		WhatsAppAccount: "support",
+		AI: models.AIConfig{
+			Enabled:   true,
+			Provider:  models.AIProviderAnthropic,
+			APIKey:    "sk-ant-test",
+			Model:     "claude-3-5-haiku-latest",
+			MaxTokens: 200,
+		},
+	}
+
+	_, err := app.generateAIResponse(settings, nil, "hello")
+	require.NoError(t, err)
+
+	var usage models.AIUsage
+	require.NoError(t, app.DB.Where("organization_id = ?", org.ID).First(&usage).Error)
+	assert.Equal(t, "support", usage.WhatsAppAccount)
+	assert.Equal(t, models.AIProviderAnthropic, usage.Provider)
+	assert.Equal(t, 120, usage.PromptTokens)
+	assert.Equal(t, 30, usage.CompletionTokens)
+	assert.InDelta(t, (120*0.80+30*4.00)/1_000_000, usage.EstimatedCost, 0.000001)
+
+	// Responses without usage data aren't recorded
+	transport.body = `{"content":[{"type":"text","text":"Hi"}]}`
+	_, err = app.generateAIResponse(settings, nil, "hello")
+	require.NoError(t, err)
+
+	var count int64
+	app.DB.Model(&models.AIUsage{}).Where("organization_id = ?", org.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
@@ -1,6 +1,10 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shridarpatil/whatomate/internal/models"
@@ -170,3 +174,247 @@ func calculatePercentageChange(previous, current int64) float64 {
 	}
 	return float64(current-previous) / float64(previous) * 100.0
 }
+
+// maxAcquisitionBuckets caps the number of points returned by GetContactAcquisitionStats
+const maxAcquisitionBuckets = 400
+
+// ContactAcquisitionBucket holds new and returning contact activity for one day or week
+type ContactAcquisitionBucket struct {
+	Date              string `json:"date"`
+	NewContacts       int64  `json:"new_contacts"`
+	ReturningContacts int64  `json:"returning_contacts"`
+	ReturningMessages int64  `json:"returning_messages"`
+}
+
+// acquisitionRow is a raw per-bucket aggregate
+type acquisitionRow struct {
+	Date     time.Time
+	Contacts int64
+	Messages int64
+}
+
+// GetContactAcquisitionStats returns new contacts per day or week alongside
+// incoming messages from returning contacts, i.e. contacts created on an
+// earlier day than the message
+func (a *App) GetContactAcquisitionStats(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	if err := a.requirePermission(r, userID, models.ResourceAnalytics, models.ActionRead); err != nil {
+		return nil
+	}
+
+	groupBy := string(r.RequestCtx.QueryArgs().Peek("group_by"))
+	if groupBy == "" {
+		groupBy = "day"
+	}
+	if groupBy != "day" && groupBy != "week" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "group_by must be day or week", nil, "")
+	}
+
+	now := time.Now()
+	fromStr := string(r.RequestCtx.QueryArgs().Peek("from"))
+	toStr := string(r.RequestCtx.QueryArgs().Peek("to"))
+
+	var periodStart, periodEnd time.Time
+	if fromStr != "" && toStr != "" {
+		var errMsg string
+		periodStart, periodEnd, errMsg = parseDateRange(fromStr, toStr)
+		if errMsg != "" {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+		}
+	} else {
+		// Default to current month
+		periodStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		periodEnd = now
+	}
+
+	// Zero-filled buckets so charts get a point for every day/week in range
+	step := 24 * time.Hour
+	if groupBy == "week" {
+		step = 7 * step
+	}
+	first := truncateToBucket(periodStart, groupBy)
+	if periodEnd.Before(periodStart) || int(periodEnd.Sub(first)/step) >= maxAcquisitionBuckets {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			fmt.Sprintf("Date range must cover at most %d %ss", maxAcquisitionBuckets, groupBy), nil, "")
+	}
+	buckets := []ContactAcquisitionBucket{}
+	index := make(map[string]int)
+	for t := first; !t.After(periodEnd); t = t.Add(step) {
+		key := t.Format("2006-01-02")
+		index[key] = len(buckets)
+		buckets = append(buckets, ContactAcquisitionBucket{Date: key})
+	}
+
+	var newRows []acquisitionRow
+	if err := a.DB.Model(&models.Contact{}).
+		Select("DATE_TRUNC('"+groupBy+"', created_at) AS date, COUNT(*) AS contacts").
+		Where("organization_id = ? AND created_at >= ? AND created_at <= ?", orgID, periodStart, periodEnd).
+		Group("DATE_TRUNC('" + groupBy + "', created_at)").
+		Scan(&newRows).Error; err != nil {
+		a.Log.Error("Failed to aggregate new contacts", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load contact acquisition stats", nil, "")
+	}
+
+	var returningRows []acquisitionRow
+	if err := a.DB.Model(&models.Message{}).
+		Select("DATE_TRUNC('"+groupBy+"', messages.created_at) AS date, COUNT(DISTINCT messages.contact_id) AS contacts, COUNT(*) AS messages").
+		Joins("JOIN contacts c ON c.id = messages.contact_id").
+		Where("messages.organization_id = ? AND messages.direction = ? AND messages.created_at >= ? AND messages.created_at <= ?",
+			orgID, models.DirectionIncoming, periodStart, periodEnd).
+		Where("c.created_at < DATE_TRUNC('day', messages.created_at)").
+		Group("DATE_TRUNC('" + groupBy + "', messages.created_at)").
+		Scan(&returningRows).Error; err != nil {
+		a.Log.Error("Failed to aggregate returning contact messages", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load contact acquisition stats", nil, "")
+	}
+
+	var totalNew, totalReturningMessages int64
+	for _, row := range newRows {
+		if i, ok := index[row.Date.Format("2006-01-02")]; ok {
+			buckets[i].NewContacts = row.Contacts
+		}
+		totalNew += row.Contacts
+	}
+	for _, row := range returningRows {
+		if i, ok := index[row.Date.Format("2006-01-02")]; ok {
+			buckets[i].ReturningContacts = row.Contacts
+			buckets[i].ReturningMessages = row.Messages
+		}
+		totalReturningMessages += row.Messages
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"buckets":                  buckets,
+		"total_new_contacts":       totalNew,
+		"total_returning_messages": totalReturningMessages,
+		"group_by":                 groupBy,
+		"from":                     periodStart.Format(time.RFC3339),
+		"to":                       periodEnd.Format(time.RFC3339),
+	})
+}
+
+// truncateToBucket returns the start of the day, or the Monday of the week,
+// containing t, matching Postgres DATE_TRUNC
+func truncateToBucket(t time.Time, groupBy string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if groupBy == "week" {
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+// flowCompletedCondition matches flow sessions that reached the end of their
+// flow, whether or not the flow sends a completion message
+const flowCompletedCondition = "s.flow_completed_at IS NOT NULL"
+
+// keywordRuleReportRow is a per-rule row of the automation report
+type keywordRuleReportRow struct {
+	ID   string
+	Name string
+	Hits int64
+}
+
+// flowReportRow is a per-flow row of the automation report
+type flowReportRow struct {
+	ID        string
+	Name      string
+	Started   int64
+	Completed int64
+	Abandoned int64
+}
+
+// aiUsageReportRow holds aggregate AI usage for the automation report
+type aiUsageReportRow struct {
+	Responses int64
+	Sessions  int64
+}
+
+// ExportAutomationReport exports keyword rule hits, flow completions/abandons
+// and AI usage for a date range as CSV
+func (a *App) ExportAutomationReport(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	if err := a.requirePermission(r, userID, models.ResourceAnalytics, models.ActionExport); err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	fromStr := string(r.RequestCtx.QueryArgs().Peek("from"))
+	toStr := string(r.RequestCtx.QueryArgs().Peek("to"))
+
+	var periodStart, periodEnd time.Time
+	if fromStr != "" && toStr != "" {
+		var errMsg string
+		periodStart, periodEnd, errMsg = parseDateRange(fromStr, toStr)
+		if errMsg != "" {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+		}
+	} else {
+		// Default to current month
+		periodStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		periodEnd = now
+	}
+
+	// Keyword rule hits (rules without hits are included with zero)
+	var ruleRows []keywordRuleReportRow
+	if err := a.DB.Model(&models.KeywordRule{}).
+		Select("keyword_rules.id, keyword_rules.name, COUNT(h.id) AS hits").
+		Joins("LEFT JOIN keyword_rule_hits h ON h.keyword_rule_id = keyword_rules.id AND h.deleted_at IS NULL AND h.created_at >= ? AND h.created_at <= ?", periodStart, periodEnd).
+		Where("keyword_rules.organization_id = ?", orgID).
+		Group("keyword_rules.id, keyword_rules.name").
+		Order("keyword_rules.name ASC").
+		Scan(&ruleRows).Error; err != nil {
+		a.Log.Error("Failed to aggregate keyword rule hits", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to build report", nil, "")
+	}
+
+	flowRows, err := a.flowSessionCounts(orgID, periodStart, periodEnd, now, nil)
+	if err != nil {
+		a.Log.Error("Failed to aggregate flow sessions", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to build report", nil, "")
+	}
+
+	var aiUsage aiUsageReportRow
+	if err := a.DB.Model(&models.ChatbotSessionMessage{}).
+		Select("COUNT(*) AS responses, COUNT(DISTINCT chatbot_session_messages.session_id) AS sessions").
+		Joins("JOIN chatbot_sessions s ON s.id = chatbot_session_messages.session_id").
+		Where("s.organization_id = ? AND chatbot_session_messages.step_name = ? AND chatbot_session_messages.created_at >= ? AND chatbot_session_messages.created_at <= ?",
+			orgID, "ai_response", periodStart, periodEnd).
+		Scan(&aiUsage).Error; err != nil {
+		a.Log.Error("Failed to aggregate AI usage", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to build report", nil, "")
+	}
+
+	var buf strings.Builder
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write([]string{"category", "id", "name", "metric", "value"})
+
+	writeRow := func(category, id, name, metric string, value int64) {
+		_ = writer.Write([]string{category, id, escapeCSVCell(name), metric, strconv.FormatInt(value, 10)})
+	}
+	for _, row := range ruleRows {
+		writeRow("keyword_rule", row.ID, row.Name, "hits", row.Hits)
+	}
+	for _, row := range flowRows {
+		writeRow("flow", row.ID, row.Name, "started", row.Started)
+		writeRow("flow", row.ID, row.Name, "completed", row.Completed)
+		writeRow("flow", row.ID, row.Name, "abandoned", row.Abandoned)
+	}
+	writeRow("ai", "", "AI responses", "responses", aiUsage.Responses)
+	writeRow("ai", "", "AI responses", "sessions", aiUsage.Sessions)
+
+	writer.Flush()
+
+	filename := fmt.Sprintf("automation_report_%s_%s.csv", periodStart.Format("20060102"), periodEnd.Format("20060102"))
+	r.RequestCtx.Response.Header.Set("Content-Type", "text/csv")
+	r.RequestCtx.Response.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	r.RequestCtx.SetBody([]byte(buf.String()))
+
+	return nil
+}
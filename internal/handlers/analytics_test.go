@@ -1,6 +1,8 @@
 package handlers_test
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"testing"
 	"time"
@@ -627,3 +629,741 @@ func TestApp_GetAgentComparison_EmptyAgents(t *testing.T) {
 
 	assert.Empty(t, resp.Data.Agents)
 }
+
+// --- GetAgentWorkload Tests ---
+
+func TestApp_GetAgentWorkload(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	perms := getAnalyticsPermissions(t, app)
+	role := testutil.CreateTestRoleExact(t, app.DB, org.ID, "Analytics Workload", false, false, perms)
+	supervisor := testutil.CreateTestUser(t, app.DB, org.ID,
+		testutil.WithEmail(testutil.UniqueEmail("workload-supervisor")),
+		testutil.WithRoleID(&role.ID),
+		testutil.WithFullName("Supervisor"),
+	)
+	busy := testutil.CreateTestUser(t, app.DB, org.ID,
+		testutil.WithEmail(testutil.UniqueEmail("workload-busy")),
+		testutil.WithFullName("Busy Agent"),
+	)
+	away := testutil.CreateTestUser(t, app.DB, org.ID,
+		testutil.WithEmail(testutil.UniqueEmail("workload-away")),
+		testutil.WithFullName("Away Agent"),
+	)
+	require.NoError(t, app.DB.Model(away).Update("is_available", false).Error)
+
+	now := time.Now().UTC()
+	assignContact := func(userID uuid.UUID, status models.ConversationStatus) *models.Contact {
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(contact).Updates(map[string]any{
+			"assigned_user_id":    userID,
+			"conversation_status": status,
+		}).Error)
+		return contact
+	}
+
+	// Two open conversations, one with two unread messages and one read
+	first := assignContact(busy.ID, models.ConversationStatusOpen)
+	createTestMessage(t, app, org.ID, first.ID, models.DirectionIncoming, now.Add(-time.Hour))
+	createTestMessage(t, app, org.ID, first.ID, models.DirectionIncoming, now.Add(-30*time.Minute))
+	read := createTestMessage(t, app, org.ID, first.ID, models.DirectionIncoming, now.Add(-2*time.Hour))
+	require.NoError(t, app.DB.Model(read).Update("status", models.MessageStatusRead).Error)
+	assignContact(busy.ID, models.ConversationStatusOpen)
+	// Resolved conversations and their messages don't count
+	resolved := assignContact(busy.ID, models.ConversationStatusResolved)
+	createTestMessage(t, app, org.ID, resolved.ID, models.DirectionIncoming, now.Add(-time.Hour))
+	assignContact(away.ID, models.ConversationStatusOpen)
+
+	// First responses after 10 and 20 minutes, plus one still waiting
+	for _, mins := range []int{10, 20} {
+		transfer := createTestAgentTransfer(t, app, org.ID, first.ID, &busy.ID,
+			models.TransferStatusResumed, models.TransferSourceManual, now.Add(-3*time.Hour), nil)
+		require.NoError(t, app.DB.Model(transfer).Update("first_response_at", transfer.TransferredAt.Add(time.Duration(mins)*time.Minute)).Error)
+	}
+	createTestAgentTransfer(t, app, org.ID, first.ID, &busy.ID,
+		models.TransferStatusActive, models.TransferSourceManual, now.Add(-time.Hour), nil)
+
+	getWorkload := func(params map[string]string) []handlers.AgentWorkload {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, supervisor.ID)
+		testutil.SetQueryParam(req, "from", now.Add(-24*time.Hour).Format("2006-01-02"))
+		testutil.SetQueryParam(req, "to", now.Format("2006-01-02"))
+		for k, v := range params {
+			testutil.SetQueryParam(req, k, v)
+		}
+		require.NoError(t, app.GetAgentWorkload(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Agents []handlers.AgentWorkload `json:"agents"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		return resp.Data.Agents
+	}
+
+	agents := getWorkload(nil)
+	require.Len(t, agents, 3)
+
+	// Available agents first, least loaded first; away agents last
+	assert.Equal(t, supervisor.ID, agents[0].AgentID)
+	assert.Zero(t, agents[0].OpenConversations)
+	assert.Nil(t, agents[0].AvgFirstResponseMins)
+
+	assert.Equal(t, busy.ID, agents[1].AgentID)
+	assert.Equal(t, int64(2), agents[1].OpenConversations)
+	assert.Equal(t, int64(2), agents[1].UnreadMessages)
+	require.NotNil(t, agents[1].AvgFirstResponseMins)
+	assert.InDelta(t, 15.0, *agents[1].AvgFirstResponseMins, 0.01)
+
+	assert.Equal(t, away.ID, agents[2].AgentID)
+	assert.False(t, agents[2].IsAvailable)
+	assert.Equal(t, int64(1), agents[2].OpenConversations)
+
+	available := getWorkload(map[string]string{"available_only": "true"})
+	require.Len(t, available, 2)
+	for _, agent := range available {
+		assert.NotEqual(t, away.ID, agent.AgentID)
+	}
+}
+
+func TestApp_GetAgentWorkload_RequiresAnalyticsPermission(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID,
+		testutil.WithEmail(testutil.UniqueEmail("workload-agent")),
+	)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.GetAgentWorkload(req))
+	assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+}
+
+// --- GetAIUsage Tests ---
+
+func TestApp_GetAIUsage(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	otherOrg := testutil.CreateTestOrganization(t, app.DB)
+	perms := getAnalyticsPermissions(t, app)
+	role := testutil.CreateTestRoleExact(t, app.DB, org.ID, "Analytics AI Usage", false, false, perms)
+	user := testutil.CreateTestUser(t, app.DB, org.ID,
+		testutil.WithEmail(testutil.UniqueEmail("ai-usage")),
+		testutil.WithRoleID(&role.ID),
+	)
+
+	now := time.Now().UTC()
+	record := func(orgID uuid.UUID, model string, prompt, completion int, cost float64, at time.Time) {
+		usage := &models.AIUsage{
+			BaseModel:        models.BaseModel{ID: uuid.New(), CreatedAt: at},
+			OrganizationID:   orgID,
+			Provider:         models.AIProviderOpenAI,
+			Model:            model,
+			PromptTokens:     prompt,
+			CompletionTokens: completion,
+			EstimatedCost:    cost,
+		}
+		require.NoError(t, app.DB.Create(usage).Error)
+	}
+	record(org.ID, "gpt-4o", 1000, 200, 0.0045, now.Add(-time.Hour))
+	record(org.ID, "gpt-4o", 500, 100, 0.00225, now.Add(-25*time.Hour))
+	record(org.ID, "gpt-4o-mini", 2000, 400, 0.00054, now.Add(-time.Hour))
+	// Outside the range and other organizations are left out
+	record(org.ID, "gpt-4o", 9000, 9000, 1, now.AddDate(0, 0, -10))
+	record(otherOrg.ID, "gpt-4o", 9000, 9000, 1, now.Add(-time.Hour))
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetQueryParam(req, "from", now.AddDate(0, 0, -2).Format("2006-01-02"))
+	testutil.SetQueryParam(req, "to", now.Format("2006-01-02"))
+	require.NoError(t, app.GetAIUsage(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.AIUsageResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+
+	assert.Equal(t, int64(3), resp.Data.Totals.Requests)
+	assert.Equal(t, int64(3500), resp.Data.Totals.PromptTokens)
+	assert.Equal(t, int64(700), resp.Data.Totals.CompletionTokens)
+	assert.Equal(t, int64(4200), resp.Data.Totals.TotalTokens)
+	assert.InDelta(t, 0.00729, resp.Data.Totals.EstimatedCost, 0.000001)
+
+	require.Len(t, resp.Data.ByModel, 2)
+	assert.Equal(t, "gpt-4o", resp.Data.ByModel[0].Model, "most expensive model first")
+	assert.Equal(t, int64(2), resp.Data.ByModel[0].Requests)
+	assert.Equal(t, "gpt-4o-mini", resp.Data.ByModel[1].Model)
+
+	var dailyRequests int64
+	for _, day := range resp.Data.Daily {
+		dailyRequests += day.Requests
+	}
+	assert.Equal(t, int64(3), dailyRequests)
+}
+
+func TestApp_GetAIUsage_InvalidDate(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	perms := getAnalyticsPermissions(t, app)
+	role := testutil.CreateTestRoleExact(t, app.DB, org.ID, "Analytics AI Usage Dates", false, false, perms)
+	user := testutil.CreateTestUser(t, app.DB, org.ID,
+		testutil.WithEmail(testutil.UniqueEmail("ai-usage-dates")),
+		testutil.WithRoleID(&role.ID),
+	)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetQueryParam(req, "from", "yesterday")
+	testutil.SetQueryParam(req, "to", "today")
+	require.NoError(t, app.GetAIUsage(req))
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+}
+
+// --- ExportAutomationReport Tests ---
+
+// createReportFlowSession creates a flow session with the given status and last activity.
+func createReportFlowSession(t *testing.T, app *handlers.App, orgID, contactID, flowID uuid.UUID, status models.SessionStatus, lastActivity time.Time) *models.ChatbotSession {
+	t.Helper()
+
+	session := &models.ChatbotSession{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  orgID,
+		ContactID:       contactID,
+		WhatsAppAccount: "test-account",
+		PhoneNumber:     "+1234567890",
+		Status:          status,
+		CurrentFlowID:   &flowID,
+		StartedAt:       lastActivity,
+		LastActivityAt:  lastActivity,
+	}
+	require.NoError(t, app.DB.Create(session).Error)
+	return session
+}
+
+// markReportFlowCompleted marks a flow session as having reached the end of its flow.
+func markReportFlowCompleted(t *testing.T, app *handlers.App, session *models.ChatbotSession, at time.Time) {
+	t.Helper()
+	require.NoError(t, app.DB.Model(session).Update("flow_completed_at", at).Error)
+}
+
+// createReportSessionMessage logs a session message with the given step name.
+func createReportSessionMessage(t *testing.T, app *handlers.App, sessionID uuid.UUID, stepName string) {
+	t.Helper()
+
+	msg := &models.ChatbotSessionMessage{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		SessionID: sessionID,
+		Direction: models.DirectionOutgoing,
+		Message:   "reply",
+		StepName:  stepName,
+	}
+	require.NoError(t, app.DB.Create(msg).Error)
+}
+
+// createReportRuleHit records a keyword rule hit at the given time.
+func createReportRuleHit(t *testing.T, app *handlers.App, orgID, ruleID, contactID uuid.UUID, at time.Time) {
+	t.Helper()
+
+	hit := &models.KeywordRuleHit{
+		BaseModel:      models.BaseModel{ID: uuid.New(), CreatedAt: at},
+		OrganizationID: orgID,
+		KeywordRuleID:  ruleID,
+		ContactID:      contactID,
+	}
+	require.NoError(t, app.DB.Create(hit).Error)
+}
+
+// parseAutomationReport maps "category|name|metric" to the reported value.
+func parseAutomationReport(t *testing.T, body []byte) map[string]string {
+	t.Helper()
+
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	assert.Equal(t, []string{"category", "id", "name", "metric", "value"}, records[0])
+
+	values := make(map[string]string, len(records)-1)
+	for _, rec := range records[1:] {
+		values[rec[0]+"|"+rec[2]+"|"+rec[3]] = rec[4]
+	}
+	return values
+}
+
+func TestApp_ExportAutomationReport(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("auto-report")),
+			testutil.WithRoleID(&role.ID),
+		)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+		now := time.Now().UTC()
+
+		// Keyword rules: one with hits (plus one outside the range), one never matched
+		pricing := createTestKeywordRule(t, app, org.ID, "Pricing", []string{"price"})
+		createTestKeywordRule(t, app, org.ID, "Unused", []string{"never"})
+		for i := 0; i < 3; i++ {
+			createReportRuleHit(t, app, org.ID, pricing.ID, contact.ID, now.Add(-time.Hour))
+		}
+		createReportRuleHit(t, app, org.ID, pricing.ID, contact.ID, now.AddDate(0, 0, -60))
+
+		// Flow: one completed, one exited early, one in progress, one timed out
+		flow := createTestChatbotFlow(t, app, org.ID, "Onboarding")
+		completed := createReportFlowSession(t, app, org.ID, contact.ID, flow.ID, models.SessionStatusCompleted, now.Add(-time.Hour))
+		markReportFlowCompleted(t, app, completed, now.Add(-time.Hour))
+		cancelled := createReportFlowSession(t, app, org.ID, contact.ID, flow.ID, models.SessionStatusCompleted, now.Add(-time.Hour))
+		createReportSessionMessage(t, app, cancelled.ID, "flow_cancel")
+		createReportFlowSession(t, app, org.ID, contact.ID, flow.ID, models.SessionStatusActive, now.Add(-time.Minute))
+		createReportFlowSession(t, app, org.ID, contact.ID, flow.ID, models.SessionStatusActive, now.Add(-2*time.Hour))
+
+		// AI: three responses across two sessions
+		aiSession1 := createTestChatbotSession(t, app, org.ID, contact.ID, now.Add(-time.Hour))
+		aiSession2 := createTestChatbotSession(t, app, org.ID, contact.ID, now.Add(-time.Hour))
+		createReportSessionMessage(t, app, aiSession1.ID, "ai_response")
+		createReportSessionMessage(t, app, aiSession1.ID, "ai_response")
+		createReportSessionMessage(t, app, aiSession2.ID, "ai_response")
+
+		// Another org's data must not leak into the report
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		otherRule := createTestKeywordRule(t, app, otherOrg.ID, "Other", []string{"x"})
+		createReportRuleHit(t, app, otherOrg.ID, otherRule.ID, contact.ID, now)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "from", now.AddDate(0, 0, -1).Format("2006-01-02"))
+		testutil.SetQueryParam(req, "to", now.Format("2006-01-02"))
+
+		require.NoError(t, app.ExportAutomationReport(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		assert.Equal(t, "text/csv", string(req.RequestCtx.Response.Header.ContentType()))
+
+		values := parseAutomationReport(t, testutil.GetResponseBody(req))
+		assert.Equal(t, "3", values["keyword_rule|Pricing|hits"])
+		assert.Equal(t, "0", values["keyword_rule|Unused|hits"])
+		assert.NotContains(t, values, "keyword_rule|Other|hits")
+		assert.Equal(t, "4", values["flow|Onboarding|started"])
+		assert.Equal(t, "1", values["flow|Onboarding|completed"])
+		assert.Equal(t, "2", values["flow|Onboarding|abandoned"])
+		assert.Equal(t, "3", values["ai|AI responses|responses"])
+		assert.Equal(t, "2", values["ai|AI responses|sessions"])
+	})
+
+	t.Run("forbidden without analytics export permission", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAgentRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("auto-report-agent")),
+			testutil.WithRoleID(&role.ID),
+		)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.ExportAutomationReport(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("auto-report-date")),
+			testutil.WithRoleID(&role.ID),
+		)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "from", "yesterday")
+		testutil.SetQueryParam(req, "to", "today")
+
+		require.NoError(t, app.ExportAutomationReport(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+}
+
+// --- GetFlowAnalytics Tests ---
+
+func TestApp_GetFlowAnalytics(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	role := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID,
+		testutil.WithEmail(testutil.UniqueEmail("flow-analytics")),
+		testutil.WithRoleID(&role.ID),
+	)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	now := time.Now().UTC()
+
+	flow := createTestChatbotFlow(t, app, org.ID, "Signup")
+	for i, name := range []string{"ask_name", "ask_email"} {
+		require.NoError(t, app.DB.Create(&models.ChatbotFlowStep{
+			BaseModel: models.BaseModel{ID: uuid.New()},
+			FlowID:    flow.ID,
+			StepName:  name,
+			StepOrder: i + 1,
+			Message:   name,
+		}).Error)
+	}
+	logSteps := func(session *models.ChatbotSession, steps ...string) {
+		for _, step := range steps {
+			createReportSessionMessage(t, app, session.ID, step)
+		}
+	}
+
+	completed := createReportFlowSession(t, app, org.ID, contact.ID, flow.ID, models.SessionStatusCompleted, now.Add(-time.Hour))
+	logSteps(completed, "flow_start", "ask_name", "ask_email", "flow_complete")
+	markReportFlowCompleted(t, app, completed, now.Add(-time.Hour))
+	timedOut := createReportFlowSession(t, app, org.ID, contact.ID, flow.ID, models.SessionStatusTimeout, now.Add(-time.Hour))
+	logSteps(timedOut, "ask_name", "ask_email", "ask_email_retry")
+	cancelled := createReportFlowSession(t, app, org.ID, contact.ID, flow.ID, models.SessionStatusCompleted, now.Add(-time.Hour))
+	logSteps(cancelled, "ask_name", "flow_cancel")
+	inProgress := createReportFlowSession(t, app, org.ID, contact.ID, flow.ID, models.SessionStatusActive, now.Add(-time.Minute))
+	logSteps(inProgress, "ask_name")
+
+	// Another org's flow must not show up
+	otherOrg := testutil.CreateTestOrganization(t, app.DB)
+	otherFlow := createTestChatbotFlow(t, app, otherOrg.ID, "Other")
+
+	getAnalytics := func(query map[string]string) (int, []handlers.FlowAnalytics) {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		for k, v := range query {
+			testutil.SetQueryParam(req, k, v)
+		}
+		require.NoError(t, app.GetFlowAnalytics(req))
+
+		var resp struct {
+			Data struct {
+				Flows []handlers.FlowAnalytics `json:"flows"`
+			} `json:"data"`
+		}
+		status := testutil.GetResponseStatusCode(req)
+		if status == fasthttp.StatusOK {
+			require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		}
+		return status, resp.Data.Flows
+	}
+
+	t.Run("funnel", func(t *testing.T) {
+		status, flows := getAnalytics(map[string]string{
+			"from": now.AddDate(0, 0, -1).Format("2006-01-02"),
+			"to":   now.Format("2006-01-02"),
+		})
+		require.Equal(t, fasthttp.StatusOK, status)
+		require.Len(t, flows, 1)
+
+		got := flows[0]
+		assert.Equal(t, flow.ID.String(), got.FlowID)
+		assert.Equal(t, int64(4), got.Started)
+		assert.Equal(t, int64(1), got.Completed)
+		assert.Equal(t, int64(2), got.Abandoned)
+		assert.Equal(t, int64(1), got.InProgress)
+		assert.Equal(t, 25.0, got.CompletionRate)
+
+		require.Len(t, got.Steps, 2)
+		assert.Equal(t, handlers.FlowStepAnalytics{StepName: "ask_name", StepOrder: 1, Reached: 4, DropOffs: 1, DropOffRate: 25}, got.Steps[0])
+		assert.Equal(t, handlers.FlowStepAnalytics{StepName: "ask_email", StepOrder: 2, Reached: 2, DropOffs: 1, DropOffRate: 50}, got.Steps[1])
+	})
+
+	t.Run("sessions outside the range are excluded", func(t *testing.T) {
+		status, flows := getAnalytics(map[string]string{"from": "2020-01-01", "to": "2020-01-31"})
+		require.Equal(t, fasthttp.StatusOK, status)
+		require.Len(t, flows, 1)
+		assert.Zero(t, flows[0].Started)
+		assert.Zero(t, flows[0].Steps[0].Reached)
+	})
+
+	t.Run("flow from another organization", func(t *testing.T) {
+		status, _ := getAnalytics(map[string]string{"flow_id": otherFlow.ID.String()})
+		assert.Equal(t, fasthttp.StatusNotFound, status)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		status, _ := getAnalytics(map[string]string{"flow_id": "not-a-uuid"})
+		assert.Equal(t, fasthttp.StatusBadRequest, status)
+
+		status, _ = getAnalytics(map[string]string{"from": "2024-03-11", "to": "2024-03-10"})
+		assert.Equal(t, fasthttp.StatusBadRequest, status)
+	})
+
+	t.Run("forbidden without analytics permission", func(t *testing.T) {
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		agent := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("flow-analytics-agent")),
+			testutil.WithRoleID(&agentRole.ID),
+		)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, agent.ID)
+		require.NoError(t, app.GetFlowAnalytics(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
+
+// --- GetContactAcquisitionStats Tests ---
+
+// createAcquisitionContact creates a contact with a specific creation time.
+func createAcquisitionContact(t *testing.T, app *handlers.App, orgID uuid.UUID, createdAt time.Time) *models.Contact {
+	t.Helper()
+	contact := testutil.CreateTestContact(t, app.DB, orgID)
+	require.NoError(t, app.DB.Model(contact).UpdateColumn("created_at", createdAt).Error)
+	return contact
+}
+
+// getAcquisitionStats calls GetContactAcquisitionStats for March 3-16 2025 and returns buckets keyed by date.
+func getAcquisitionStats(t *testing.T, app *handlers.App, orgID, userID uuid.UUID, groupBy string) (map[string]handlers.ContactAcquisitionBucket, int, int64) {
+	t.Helper()
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, orgID, userID)
+	testutil.SetQueryParam(req, "from", "2025-03-03")
+	testutil.SetQueryParam(req, "to", "2025-03-16")
+	testutil.SetQueryParam(req, "group_by", groupBy)
+
+	require.NoError(t, app.GetContactAcquisitionStats(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data struct {
+			Buckets          []handlers.ContactAcquisitionBucket `json:"buckets"`
+			TotalNewContacts int64                               `json:"total_new_contacts"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+
+	byDate := make(map[string]handlers.ContactAcquisitionBucket)
+	for _, b := range resp.Data.Buckets {
+		byDate[b.Date] = b
+	}
+	return byDate, len(resp.Data.Buckets), resp.Data.TotalNewContacts
+}
+
+func TestApp_GetContactAcquisitionStats(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	role := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID,
+		testutil.WithEmail(testutil.UniqueEmail("acquisition")),
+		testutil.WithRoleID(&role.ID),
+	)
+
+	day := func(d, hour int) time.Time { return time.Date(2025, 3, d, hour, 0, 0, 0, time.UTC) }
+
+	// Contacts: one from before the range, two on Mon 3rd, one on Wed 5th, one on Mon 10th
+	old := createAcquisitionContact(t, app, org.ID, time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC))
+	fresh := createAcquisitionContact(t, app, org.ID, day(3, 9))
+	createAcquisitionContact(t, app, org.ID, day(3, 11))
+	createAcquisitionContact(t, app, org.ID, day(5, 9))
+	createAcquisitionContact(t, app, org.ID, day(10, 9))
+
+	// Returning: the old contact writes twice on the 4th and once on the 11th,
+	// the fresh contact comes back on the 5th
+	createTestMessage(t, app, org.ID, old.ID, models.DirectionIncoming, day(4, 10))
+	createTestMessage(t, app, org.ID, old.ID, models.DirectionIncoming, day(4, 12))
+	createTestMessage(t, app, org.ID, old.ID, models.DirectionIncoming, day(11, 10))
+	createTestMessage(t, app, org.ID, fresh.ID, models.DirectionIncoming, day(5, 10))
+	// Not returning: first-day message, outgoing message, message outside the range
+	createTestMessage(t, app, org.ID, fresh.ID, models.DirectionIncoming, day(3, 10))
+	createTestMessage(t, app, org.ID, old.ID, models.DirectionOutgoing, day(4, 11))
+	createTestMessage(t, app, org.ID, old.ID, models.DirectionIncoming, day(20, 10))
+
+	// Another org's data is ignored
+	otherOrg := testutil.CreateTestOrganization(t, app.DB)
+	otherContact := createAcquisitionContact(t, app, otherOrg.ID, day(3, 9))
+	createTestMessage(t, app, otherOrg.ID, otherContact.ID, models.DirectionIncoming, day(4, 10))
+
+	t.Run("daily buckets", func(t *testing.T) {
+		buckets, count, totalNew := getAcquisitionStats(t, app, org.ID, user.ID, "day")
+		assert.Equal(t, 14, count)
+		assert.Equal(t, int64(4), totalNew)
+
+		assert.Equal(t, handlers.ContactAcquisitionBucket{Date: "2025-03-03", NewContacts: 2}, buckets["2025-03-03"])
+		assert.Equal(t, handlers.ContactAcquisitionBucket{Date: "2025-03-04", ReturningContacts: 1, ReturningMessages: 2}, buckets["2025-03-04"])
+		assert.Equal(t, handlers.ContactAcquisitionBucket{Date: "2025-03-05", NewContacts: 1, ReturningContacts: 1, ReturningMessages: 1}, buckets["2025-03-05"])
+		assert.Equal(t, handlers.ContactAcquisitionBucket{Date: "2025-03-10", NewContacts: 1}, buckets["2025-03-10"])
+		assert.Equal(t, handlers.ContactAcquisitionBucket{Date: "2025-03-11", ReturningContacts: 1, ReturningMessages: 1}, buckets["2025-03-11"])
+		assert.Equal(t, handlers.ContactAcquisitionBucket{Date: "2025-03-07"}, buckets["2025-03-07"])
+	})
+
+	t.Run("weekly buckets", func(t *testing.T) {
+		buckets, count, _ := getAcquisitionStats(t, app, org.ID, user.ID, "week")
+		assert.Equal(t, 2, count)
+		assert.Equal(t, handlers.ContactAcquisitionBucket{Date: "2025-03-03", NewContacts: 3, ReturningContacts: 2, ReturningMessages: 3}, buckets["2025-03-03"])
+		assert.Equal(t, handlers.ContactAcquisitionBucket{Date: "2025-03-10", NewContacts: 1, ReturningContacts: 1, ReturningMessages: 1}, buckets["2025-03-10"])
+	})
+
+	t.Run("invalid group_by", func(t *testing.T) {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "group_by", "month")
+
+		require.NoError(t, app.GetContactAcquisitionStats(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("forbidden without analytics permission", func(t *testing.T) {
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		agent := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("acquisition-agent")),
+			testutil.WithRoleID(&agentRole.ID),
+		)
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, agent.ID)
+
+		require.NoError(t, app.GetContactAcquisitionStats(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
+
+// --- ListSLABreaches Tests ---
+
+func TestApp_ListSLABreaches(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	role := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID,
+		testutil.WithEmail(testutil.UniqueEmail("sla-breaches")),
+		testutil.WithRoleID(&role.ID),
+		testutil.WithFullName("Jane Agent"),
+	)
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		SLA: models.SLAConfig{
+			Enabled:           true,
+			ResponseMinutes:   15,
+			ResolutionMinutes: 60,
+		},
+	}).Error)
+
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	now := time.Now().UTC()
+	setFirstResponse := func(transfer *models.AgentTransfer, at time.Time) {
+		require.NoError(t, app.DB.Model(transfer).Update("first_response_at", at).Error)
+	}
+
+	// Answered quickly but resolved after 150 minutes
+	resumedLate := now.Add(-30 * time.Minute)
+	slowResolution := createTestAgentTransfer(t, app, org.ID, contact.ID, &user.ID,
+		models.TransferStatusResumed, models.TransferSourceManual, now.Add(-3*time.Hour), &resumedLate)
+	setFirstResponse(slowResolution, now.Add(-3*time.Hour+5*time.Minute))
+
+	// Still waiting for a first response after 30 minutes
+	unanswered := createTestAgentTransfer(t, app, org.ID, contact.ID, nil,
+		models.TransferStatusActive, models.TransferSourceFlow, now.Add(-30*time.Minute), nil)
+
+	// Within both SLAs
+	resumedOnTime := now.Add(-20 * time.Minute)
+	onTime := createTestAgentTransfer(t, app, org.ID, contact.ID, &user.ID,
+		models.TransferStatusResumed, models.TransferSourceManual, now.Add(-50*time.Minute), &resumedOnTime)
+	setFirstResponse(onTime, now.Add(-45*time.Minute))
+
+	// Breached, but in another organization
+	otherOrg := testutil.CreateTestOrganization(t, app.DB)
+	otherContact := testutil.CreateTestContact(t, app.DB, otherOrg.ID)
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: otherOrg.ID,
+		SLA:            models.SLAConfig{Enabled: true, ResponseMinutes: 15, ResolutionMinutes: 60},
+	}).Error)
+	createTestAgentTransfer(t, app, otherOrg.ID, otherContact.ID, nil,
+		models.TransferStatusActive, models.TransferSourceFlow, now.Add(-2*time.Hour), nil)
+
+	listBreaches := func(t *testing.T, params map[string]string) []handlers.SLABreachResponse {
+		t.Helper()
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "from", now.Add(-24*time.Hour).Format("2006-01-02"))
+		testutil.SetQueryParam(req, "to", now.Format("2006-01-02"))
+		for k, v := range params {
+			testutil.SetQueryParam(req, k, v)
+		}
+
+		require.NoError(t, app.ListSLABreaches(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Breaches []handlers.SLABreachResponse `json:"breaches"`
+				Total    int64                        `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, int64(len(resp.Data.Breaches)), resp.Data.Total)
+		return resp.Data.Breaches
+	}
+
+	t.Run("lists response and resolution breaches", func(t *testing.T) {
+		breaches := listBreaches(t, nil)
+		require.Len(t, breaches, 2)
+
+		// Newest transfer first
+		assert.Equal(t, unanswered.ID, breaches[0].TransferID)
+		assert.True(t, breaches[0].ResponseBreached)
+		assert.False(t, breaches[0].ResolutionBreached)
+		assert.Nil(t, breaches[0].AgentName)
+		assert.InDelta(t, 30, breaches[0].ResponseMins, 1)
+
+		assert.Equal(t, slowResolution.ID, breaches[1].TransferID)
+		assert.False(t, breaches[1].ResponseBreached)
+		assert.True(t, breaches[1].ResolutionBreached)
+		assert.InDelta(t, 150, breaches[1].ResolutionMins, 1)
+		assert.Equal(t, 60, breaches[1].ResolutionSLAMins)
+		require.NotNil(t, breaches[1].AgentName)
+		assert.Equal(t, "Jane Agent", *breaches[1].AgentName)
+	})
+
+	t.Run("filters by agent", func(t *testing.T) {
+		breaches := listBreaches(t, map[string]string{"agent_id": user.ID.String()})
+		require.Len(t, breaches, 1)
+		assert.Equal(t, slowResolution.ID, breaches[0].TransferID)
+	})
+
+	t.Run("date range excludes older transfers", func(t *testing.T) {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "from", "2020-01-01")
+		testutil.SetQueryParam(req, "to", "2020-01-31")
+
+		require.NoError(t, app.ListSLABreaches(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Breaches []handlers.SLABreachResponse `json:"breaches"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Empty(t, resp.Data.Breaches)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "from", "yesterday")
+		testutil.SetQueryParam(req, "to", "2020-01-31")
+
+		require.NoError(t, app.ListSLABreaches(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("forbidden without analytics permission", func(t *testing.T) {
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		agent := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("sla-breaches-agent")),
+			testutil.WithRoleID(&agentRole.ID),
+		)
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, agent.ID)
+
+		require.NoError(t, app.ListSLABreaches(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
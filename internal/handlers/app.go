@@ -41,6 +41,8 @@ type App struct {
 	S3Client *storage.S3Client
 	// wg tracks background goroutines for graceful shutdown
 	wg sync.WaitGroup
+	// aiLimiters holds the per-organization AI concurrency limiters (uuid.UUID -> *aiLimiter)
+	aiLimiters sync.Map
 }
 
 // WaitForBackgroundTasks blocks until all background goroutines complete.
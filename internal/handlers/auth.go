@@ -47,11 +47,18 @@ func (a *App) Login(r *fastglue.Request) error {
 		return nil
 	}
 
+	// Block brute-forcing by IP and by email
+	throttleKeys := a.loginThrottleKeys(r, req.Email)
+	if remaining := a.loginLockoutRemaining(throttleKeys); remaining > 0 {
+		return sendLoginLockedOut(r, remaining)
+	}
+
 	// Find user by email with role preloaded
 	var user models.User
 	if err := a.DB.Preload("Role").Where("email = ?", req.Email).First(&user).Error; err != nil {
 		// Run dummy bcrypt to prevent timing-based account enumeration
 		_ = bcrypt.CompareHashAndPassword([]byte("$2a$10$xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx"), []byte(req.Password))
+		a.recordLoginFailure(throttleKeys)
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Invalid credentials", nil, "")
 	}
 
@@ -77,13 +84,16 @@ func (a *App) Login(r *fastglue.Request) error {
 
 	// Check if user is active
 	if !user.IsActive {
+		a.recordLoginFailure(throttleKeys)
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Account is disabled", nil, "")
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		a.recordLoginFailure(throttleKeys)
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Invalid credentials", nil, "")
 	}
+	a.clearLoginFailures(throttleKeys)
 
 	// Generate tokens
 	accessToken, err := a.generateAccessToken(&user)
@@ -355,6 +365,10 @@ func (a *App) generateRefreshToken(user *models.User) (string, error) {
 	if err := a.Redis.Set(ctx, refreshTokenKey(jti), user.ID.String(), expiry).Err(); err != nil {
 		a.Log.Error("Failed to store refresh token in Redis", "error", err)
 	}
+	// Track the JTI per user so all of a user's sessions can be revoked at once
+	userKey := userRefreshTokensKey(user.ID)
+	a.Redis.SAdd(ctx, userKey, jti)
+	a.Redis.Expire(ctx, userKey, expiry)
 
 	return signed, nil
 }
@@ -364,6 +378,33 @@ func refreshTokenKey(jti string) string {
 	return fmt.Sprintf("refresh:%s", jti)
 }
 
+// userRefreshTokensKey returns the Redis key for the set of a user's refresh token JTIs.
+func userRefreshTokensKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user_refresh:%s", userID.String())
+}
+
+// revokeUserSessions revokes every refresh token issued to the user, so they
+// must log in again once their current access token expires.
+func (a *App) revokeUserSessions(userID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	userKey := userRefreshTokensKey(userID)
+	jtis, err := a.Redis.SMembers(ctx, userKey).Result()
+	if err != nil {
+		a.Log.Error("Failed to load user sessions", "error", err, "user_id", userID)
+		return
+	}
+	keys := make([]string, 0, len(jtis)+1)
+	for _, jti := range jtis {
+		keys = append(keys, refreshTokenKey(jti))
+	}
+	keys = append(keys, userKey)
+	if err := a.Redis.Del(ctx, keys...).Err(); err != nil {
+		a.Log.Error("Failed to revoke user sessions", "error", err, "user_id", userID)
+	}
+}
+
 // SwitchOrgRequest represents the request body for switching organization
 type SwitchOrgRequest struct {
 	OrganizationID uuid.UUID `json:"organization_id"`
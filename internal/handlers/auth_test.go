@@ -2,6 +2,7 @@ package handlers_test
 
 import (
 	"encoding/json"
+	"strconv"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
 )
 
 func TestApp_Login_Success(t *testing.T) {
@@ -139,6 +141,105 @@ func TestApp_Login_UserWithRole(t *testing.T) {
 	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 }
 
+// loginFrom builds a login request from the given client IP.
+func loginFrom(t *testing.T, ip, email, password string) *fastglue.Request {
+	t.Helper()
+
+	req := testutil.NewJSONRequest(t, map[string]string{
+		"email":    email,
+		"password": password,
+	})
+	req.RequestCtx.Request.Header.Set("X-Forwarded-For", ip)
+	return req
+}
+
+func TestApp_Login_LocksOutEmailFromIPAfterFailures(t *testing.T) {
+	app := newTestApp(t, withLoginLockout(3))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	email := testutil.UniqueEmail("lockout")
+	testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(email), testutil.WithPassword("correctpassword"))
+	ip := uuid.NewString()
+
+	for i := 0; i < 3; i++ {
+		req := loginFrom(t, ip, email, "wrongpassword")
+		require.NoError(t, app.Login(req))
+		testutil.AssertErrorResponse(t, req, fasthttp.StatusUnauthorized, "Invalid credentials")
+	}
+
+	req := loginFrom(t, ip, email, "correctpassword")
+	require.NoError(t, app.Login(req))
+	assert.Equal(t, fasthttp.StatusTooManyRequests, testutil.GetResponseStatusCode(req))
+	retryAfter, err := strconv.Atoi(string(req.RequestCtx.Response.Header.Peek("Retry-After")))
+	require.NoError(t, err)
+	assert.InDelta(t, 60, retryAfter, 2)
+
+	// The user can still log in from their own address
+	req = loginFrom(t, uuid.NewString(), email, "correctpassword")
+	require.NoError(t, app.Login(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+}
+
+func TestApp_Login_FailuresFromManyIPsDontLockOutEmail(t *testing.T) {
+	app := newTestApp(t, withLoginLockout(3))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	email := testutil.UniqueEmail("spread-lockout")
+	testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(email), testutil.WithPassword("correctpassword"))
+
+	for i := 0; i < 6; i++ {
+		req := loginFrom(t, uuid.NewString(), email, "wrongpassword")
+		require.NoError(t, app.Login(req))
+		testutil.AssertErrorResponse(t, req, fasthttp.StatusUnauthorized, "Invalid credentials")
+	}
+
+	req := loginFrom(t, uuid.NewString(), email, "correctpassword")
+	require.NoError(t, app.Login(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+}
+
+func TestApp_Login_LocksOutIPAfterFailures(t *testing.T) {
+	app := newTestApp(t, withLoginLockout(3))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	email := testutil.UniqueEmail("ip-lockout")
+	testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(email), testutil.WithPassword("correctpassword"))
+	ip := uuid.NewString()
+
+	// Four times the per-email threshold, spread over different emails
+	for i := 0; i < 12; i++ {
+		req := loginFrom(t, ip, testutil.UniqueEmail("unknown"), "anypassword")
+		require.NoError(t, app.Login(req))
+		assert.Equal(t, fasthttp.StatusUnauthorized, testutil.GetResponseStatusCode(req))
+	}
+
+	req := loginFrom(t, ip, email, "correctpassword")
+	require.NoError(t, app.Login(req))
+	assert.Equal(t, fasthttp.StatusTooManyRequests, testutil.GetResponseStatusCode(req))
+
+	// Other addresses can still log in
+	req = loginFrom(t, uuid.NewString(), email, "correctpassword")
+	require.NoError(t, app.Login(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+}
+
+func TestApp_Login_SuccessResetsEmailFailures(t *testing.T) {
+	app := newTestApp(t, withLoginLockout(3))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	email := testutil.UniqueEmail("lockout-reset")
+	testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(email), testutil.WithPassword("correctpassword"))
+
+	attempt := func(password string) int {
+		req := loginFrom(t, uuid.NewString(), email, password)
+		require.NoError(t, app.Login(req))
+		return testutil.GetResponseStatusCode(req)
+	}
+
+	assert.Equal(t, fasthttp.StatusUnauthorized, attempt("wrongpassword"))
+	assert.Equal(t, fasthttp.StatusUnauthorized, attempt("wrongpassword"))
+	assert.Equal(t, fasthttp.StatusOK, attempt("correctpassword"))
+	assert.Equal(t, fasthttp.StatusUnauthorized, attempt("wrongpassword"))
+	assert.Equal(t, fasthttp.StatusUnauthorized, attempt("wrongpassword"))
+	assert.Equal(t, fasthttp.StatusOK, attempt("correctpassword"))
+}
+
 func TestApp_Register_Success(t *testing.T) {
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
@@ -57,6 +57,7 @@ func (a *App) getChatbotSettingsCached(orgID uuid.UUID, whatsAppAccount string)
 		if err := json.Unmarshal([]byte(cached), &cacheData); err == nil {
 			// Restore the API key from the cache wrapper
 			cacheData.AI.APIKey = cacheData.AIAPIKey
+			a.decryptChatbotSecrets(&cacheData.ChatbotSettings)
 			return &cacheData.ChatbotSettings, nil
 		}
 	}
@@ -81,9 +82,17 @@ func (a *App) getChatbotSettingsCached(orgID uuid.UUID, whatsAppAccount string)
 		a.Redis.Set(ctx, cacheKey, data, settingsCacheTTL)
 	}
 
+	// Decrypt secrets before returning (the cache keeps them encrypted)
+	a.decryptChatbotSecrets(&settings)
 	return &settings, nil
 }
 
+// decryptChatbotSecrets decrypts the AI API key on chatbot settings.
+// Handles both encrypted ("enc:" prefixed) and legacy unencrypted values transparently.
+func (a *App) decryptChatbotSecrets(settings *models.ChatbotSettings) {
+	crypto.DecryptFields(a.Config.App.EncryptionKey, &settings.AI.APIKey)
+}
+
 // getChatbotFlowsCached retrieves all enabled flows with steps from cache or database
 func (a *App) getChatbotFlowsCached(orgID uuid.UUID) ([]models.ChatbotFlow, error) {
 	ctx := context.Background()
@@ -393,6 +402,9 @@ type UserPermissions struct {
 	IsSystem     bool      `json:"is_system"`
 	IsSuperAdmin bool      `json:"is_super_admin"`
 	Permissions  []string  `json:"permissions"` // Format: "resource:action"
+
+	ContactsOwnOnly           bool `json:"contacts_own_only"`
+	ContactsIncludeUnassigned bool `json:"contacts_include_unassigned"`
 }
 
 // getUserPermissionsCached retrieves user permissions from cache or database.
@@ -463,6 +475,9 @@ func (a *App) getUserPermissionsCached(userID uuid.UUID, orgIDs ...uuid.UUID) (*
 		IsSystem:     role.IsSystem,
 		IsSuperAdmin: user.IsSuperAdmin,
 		Permissions:  make([]string, 0, len(role.Permissions)),
+
+		ContactsOwnOnly:           role.ContactsOwnOnly,
+		ContactsIncludeUnassigned: role.ContactsIncludeUnassigned,
 	}
 
 	for _, p := range role.Permissions {
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// CampaignQueueStatusResponse reports how much of a campaign is left to send
+// and when it should finish at the organization's send rate
+type CampaignQueueStatusResponse struct {
+	CampaignID            uuid.UUID             `json:"campaign_id"`
+	Status                models.CampaignStatus `json:"status"`
+	QueueDepth            int64                 `json:"queue_depth"` // Recipients not yet sent
+	SentCount             int                   `json:"sent_count"`
+	FailedCount           int                   `json:"failed_count"`
+	SkippedCount          int                   `json:"skipped_count"`
+	MessagesPerSecond     int                   `json:"messages_per_second"`
+	EstimatedSecondsLeft  *int64                `json:"estimated_seconds_left"`  // nil unless the campaign is running
+	EstimatedCompletionAt *time.Time            `json:"estimated_completion_at"` // nil unless the campaign is running
+}
+
+// GetCampaignQueueStatus returns a campaign's remaining queue depth and, while
+// it is running, its estimated completion time. The estimate assumes the
+// campaign has the organization's whole send rate to itself.
+func (a *App) GetCampaignQueueStatus(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceCampaigns, models.ActionRead); err != nil {
+		return nil
+	}
+
+	id, err := parsePathUUID(r, "id", "campaign")
+	if err != nil {
+		return nil
+	}
+
+	campaign, err := findByIDAndOrg[models.BulkMessageCampaign](a.DB, r, id, orgID, "Campaign")
+	if err != nil {
+		return nil
+	}
+
+	var org models.Organization
+	if err := a.DB.Select("id", "settings").Where("id = ?", orgID).First(&org).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Organization not found", nil, "")
+	}
+
+	var pending int64
+	if err := a.DB.Model(&models.BulkMessageRecipient{}).
+		Where("campaign_id = ? AND status = ?", campaign.ID, models.MessageStatusPending).
+		Count(&pending).Error; err != nil {
+		a.Log.Error("Failed to count pending recipients", "error", err, "campaign_id", campaign.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load campaign queue", nil, "")
+	}
+
+	response := CampaignQueueStatusResponse{
+		CampaignID:        campaign.ID,
+		Status:            campaign.Status,
+		QueueDepth:        pending,
+		SentCount:         campaign.SentCount,
+		FailedCount:       campaign.FailedCount,
+		SkippedCount:      campaign.SkippedCount,
+		MessagesPerSecond: org.CampaignMessagesPerSecond(),
+	}
+	if campaign.Status == models.CampaignStatusProcessing || campaign.Status == models.CampaignStatusQueued {
+		seconds := int64(math.Ceil(float64(pending) / float64(response.MessagesPerSecond)))
+		completion := time.Now().Add(time.Duration(seconds) * time.Second)
+		response.EstimatedSecondsLeft = &seconds
+		response.EstimatedCompletionAt = &completion
+	}
+
+	return r.SendEnvelope(response)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"gorm.io/gorm"
+)
+
+// campaignSegmentBatchSize is the number of contacts turned into recipients per batch
+const campaignSegmentBatchSize = 1000
+
+// CampaignSegment selects the contacts a campaign is sent to. Contacts match
+// when they have any of the tags and every custom field value (compared as text).
+type CampaignSegment struct {
+	Tags         []string          `json:"tags,omitempty"`
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+}
+
+// isEmpty reports whether the segment has no criteria, which would match every contact
+func (s *CampaignSegment) isEmpty() bool {
+	return len(normalizeTagList(s.Tags)) == 0 && len(s.CustomFields) == 0
+}
+
+// segmentContactsQuery returns the organization's contacts matching a segment
+func segmentContactsQuery(db *gorm.DB, orgID uuid.UUID, segment *CampaignSegment) *gorm.DB {
+	query := db.Model(&models.Contact{}).Where("organization_id = ?", orgID)
+	if tags := normalizeTagList(segment.Tags); len(tags) > 0 {
+		query = filterContactsByAnyTag(query, tags)
+	}
+	for field, value := range segment.CustomFields {
+		query = query.Where("custom_fields ->> ? = ?", field, value)
+	}
+	return query
+}
+
+// addSegmentRecipients adds the contacts matching a segment to a campaign as
+// pending recipients, skipping blacklisted numbers and opted-out or blocked
+// contacts, and returns how many were added
+func (a *App) addSegmentRecipients(tx *gorm.DB, campaign *models.BulkMessageCampaign, segment *CampaignSegment, templateParams map[string]interface{}) (int, error) {
+	var blacklisted []string
+	if err := tx.Model(&models.PhoneBlacklist{}).
+		Where("organization_id = ?", campaign.OrganizationID).
+		Pluck("phone_number", &blacklisted).Error; err != nil {
+		return 0, err
+	}
+	skip := make(map[string]bool, len(blacklisted))
+	for _, phone := range blacklisted {
+		skip[phone] = true
+	}
+
+	added := 0
+	var batch []models.Contact
+	result := segmentContactsQuery(tx, campaign.OrganizationID, segment).
+		Where("opted_out = ? AND blocked = ?", false, false).
+		Select("id", "phone_number", "profile_name").
+		FindInBatches(&batch, campaignSegmentBatchSize, func(_ *gorm.DB, _ int) error {
+			recipients := make([]models.BulkMessageRecipient, 0, len(batch))
+			for _, contact := range batch {
+				if skip[strings.TrimPrefix(contact.PhoneNumber, "+")] {
+					continue
+				}
+				recipients = append(recipients, models.BulkMessageRecipient{
+					CampaignID:     campaign.ID,
+					PhoneNumber:    contact.PhoneNumber,
+					RecipientName:  contact.ProfileName,
+					TemplateParams: models.JSONB(templateParams),
+					Status:         models.MessageStatusPending,
+				})
+			}
+			if len(recipients) == 0 {
+				return nil
+			}
+			if err := tx.Create(&recipients).Error; err != nil {
+				return err
+			}
+			added += len(recipients)
+			return nil
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return added, nil
+}
@@ -26,6 +26,10 @@ type CampaignRequest struct {
 	TemplateID      string     `json:"template_id" validate:"required"`
 	HeaderMediaID   string     `json:"header_media_id"`
 	ScheduledAt     *time.Time `json:"scheduled_at"`
+
+	// Optional: add the contacts matching a segment as recipients on create
+	Segment        *CampaignSegment       `json:"segment,omitempty"`
+	TemplateParams map[string]interface{} `json:"template_params,omitempty"` // Params for every segment recipient
 }
 
 // CampaignResponse represents campaign in API responses
@@ -44,6 +48,7 @@ type CampaignResponse struct {
 	DeliveredCount  int                  `json:"delivered_count"`
 	ReadCount       int                  `json:"read_count"`
 	FailedCount     int                  `json:"failed_count"`
+	SkippedCount    int                  `json:"skipped_count"`
 	ScheduledAt     *time.Time           `json:"scheduled_at,omitempty"`
 	StartedAt       *time.Time           `json:"started_at,omitempty"`
 	CompletedAt     *time.Time           `json:"completed_at,omitempty"`
@@ -121,6 +126,7 @@ func (a *App) ListCampaigns(r *fastglue.Request) error {
 			DeliveredCount:      c.DeliveredCount,
 			ReadCount:           c.ReadCount,
 			FailedCount:         c.FailedCount,
+			SkippedCount:        c.SkippedCount,
 			ScheduledAt:         c.ScheduledAt,
 			StartedAt:           c.StartedAt,
 			CompletedAt:         c.CompletedAt,
@@ -147,6 +153,10 @@ func (a *App) CreateCampaign(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
 
+	if err := a.requirePermission(r, userID, models.ResourceCampaigns, models.ActionWrite); err != nil {
+		return nil
+	}
+
 	var req CampaignRequest
 	if err := a.decodeRequest(r, &req); err != nil {
 		return nil
@@ -162,6 +172,13 @@ func (a *App) CreateCampaign(r *fastglue.Request) error {
 	if err != nil {
 		return nil
 	}
+	if template.Status != string(models.TemplateStatusApproved) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, fmt.Sprintf("Template is not approved (status: %s)", template.Status), nil, "")
+	}
+
+	if req.Segment != nil && req.Segment.isEmpty() {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Segment must specify tags or custom_fields", nil, "")
+	}
 
 	// Validate WhatsApp account exists
 	if _, err := a.resolveWhatsAppAccount(orgID, req.WhatsAppAccount); err != nil {
@@ -179,12 +196,25 @@ func (a *App) CreateCampaign(r *fastglue.Request) error {
 		CreatedBy:       userID,
 	}
 
-	if err := a.DB.Create(&campaign).Error; err != nil {
+	if err := a.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&campaign).Error; err != nil {
+			return err
+		}
+		if req.Segment == nil {
+			return nil
+		}
+		count, err := a.addSegmentRecipients(tx, &campaign, req.Segment, req.TemplateParams)
+		if err != nil {
+			return err
+		}
+		campaign.TotalRecipients = count
+		return tx.Model(&campaign).Update("total_recipients", count).Error
+	}); err != nil {
 		a.Log.Error("Failed to create campaign", "error", err)
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to create campaign", nil, "")
 	}
 
-	a.Log.Info("Campaign created", "campaign_id", campaign.ID, "name", campaign.Name)
+	a.Log.Info("Campaign created", "campaign_id", campaign.ID, "name", campaign.Name, "recipients", campaign.TotalRecipients)
 
 	return r.SendEnvelope(CampaignResponse{
 		ID:                  campaign.ID,
@@ -200,6 +230,7 @@ func (a *App) CreateCampaign(r *fastglue.Request) error {
 		SentCount:           campaign.SentCount,
 		DeliveredCount:      campaign.DeliveredCount,
 		FailedCount:         campaign.FailedCount,
+		SkippedCount:        campaign.SkippedCount,
 		ScheduledAt:         campaign.ScheduledAt,
 		CreatedAt:           campaign.CreatedAt,
 		UpdatedAt:           campaign.UpdatedAt,
@@ -238,6 +269,7 @@ func (a *App) GetCampaign(r *fastglue.Request) error {
 		SentCount:           campaign.SentCount,
 		DeliveredCount:      campaign.DeliveredCount,
 		FailedCount:         campaign.FailedCount,
+		SkippedCount:        campaign.SkippedCount,
 		ScheduledAt:         campaign.ScheduledAt,
 		StartedAt:           campaign.StartedAt,
 		CompletedAt:         campaign.CompletedAt,
@@ -317,6 +349,7 @@ func (a *App) UpdateCampaign(r *fastglue.Request) error {
 		SentCount:           campaign.SentCount,
 		DeliveredCount:      campaign.DeliveredCount,
 		FailedCount:         campaign.FailedCount,
+		SkippedCount:        campaign.SkippedCount,
 		ScheduledAt:         campaign.ScheduledAt,
 		CreatedAt:           campaign.CreatedAt,
 		UpdatedAt:           campaign.UpdatedAt,
@@ -2,6 +2,8 @@ package handlers_test
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -128,7 +130,8 @@ func TestApp_CreateCampaign_Success(t *testing.T) {
 	mockQueue := testutil.NewMockQueue()
 	app := newTestApp(t, withQueue(mockQueue))
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("create-campaign")), testutil.WithPassword("password"))
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("create-campaign")), testutil.WithPassword("password"), testutil.WithRoleID(&adminRole.ID))
 	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("create-account"))
 	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
 
@@ -153,11 +156,33 @@ func TestApp_CreateCampaign_Success(t *testing.T) {
 	assert.Equal(t, template.ID, resp.Data.TemplateID)
 }
 
+func TestApp_CreateCampaign_NoAccountWithSeveralAccounts(t *testing.T) {
+	mockQueue := testutil.NewMockQueue()
+	app := newTestApp(t, withQueue(mockQueue))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	// Neither account is the default, which only the messages API insists on
+	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("first-"+uuid.New().String()[:8]))
+	testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("second-"+uuid.New().String()[:8]))
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+
+	req := testutil.NewJSONRequest(t, map[string]interface{}{
+		"name":        "No Account Campaign",
+		"template_id": template.ID.String(),
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.CreateCampaign(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+}
+
 func TestApp_CreateCampaign_WithScheduledAt(t *testing.T) {
 	mockQueue := testutil.NewMockQueue()
 	app := newTestApp(t, withQueue(mockQueue))
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("create-scheduled")), testutil.WithPassword("password"))
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("create-scheduled")), testutil.WithPassword("password"), testutil.WithRoleID(&adminRole.ID))
 	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("scheduled-account"))
 	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
 
@@ -187,7 +212,8 @@ func TestApp_CreateCampaign_InvalidTemplateID(t *testing.T) {
 	mockQueue := testutil.NewMockQueue()
 	app := newTestApp(t, withQueue(mockQueue))
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("invalid-template")), testutil.WithPassword("password"))
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("invalid-template")), testutil.WithPassword("password"), testutil.WithRoleID(&adminRole.ID))
 	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("invalid-template-account"))
 
 	req := testutil.NewJSONRequest(t, map[string]interface{}{
@@ -206,7 +232,8 @@ func TestApp_CreateCampaign_TemplateNotFound(t *testing.T) {
 	mockQueue := testutil.NewMockQueue()
 	app := newTestApp(t, withQueue(mockQueue))
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("template-not-found")), testutil.WithPassword("password"))
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("template-not-found")), testutil.WithPassword("password"), testutil.WithRoleID(&adminRole.ID))
 	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("no-template-account"))
 
 	req := testutil.NewJSONRequest(t, map[string]interface{}{
@@ -225,7 +252,8 @@ func TestApp_CreateCampaign_AccountNotFound(t *testing.T) {
 	mockQueue := testutil.NewMockQueue()
 	app := newTestApp(t, withQueue(mockQueue))
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("account-not-found")), testutil.WithPassword("password"))
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("account-not-found")), testutil.WithPassword("password"), testutil.WithRoleID(&adminRole.ID))
 	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("temp-account-for-template"))
 	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
 
@@ -245,7 +273,8 @@ func TestApp_CreateCampaign_InvalidRequestBody(t *testing.T) {
 	mockQueue := testutil.NewMockQueue()
 	app := newTestApp(t, withQueue(mockQueue))
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("invalid-body")), testutil.WithPassword("password"))
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("invalid-body")), testutil.WithPassword("password"), testutil.WithRoleID(&adminRole.ID))
 
 	req := testutil.NewRequest(t)
 	req.RequestCtx.Request.SetBody([]byte("invalid json"))
@@ -257,6 +286,131 @@ func TestApp_CreateCampaign_InvalidRequestBody(t *testing.T) {
 	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 }
 
+func TestApp_CreateCampaign_WithSegment(t *testing.T) {
+	mockQueue := testutil.NewMockQueue()
+	app := newTestApp(t, withQueue(mockQueue))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("segment-account"))
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+
+	segmentContact := func(tags models.JSONBArray, plan string) *models.Contact {
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(contact).Updates(map[string]any{
+			"tags":          tags,
+			"custom_fields": models.JSONB{"plan": plan},
+		}).Error)
+		return contact
+	}
+	vipPro := segmentContact(models.JSONBArray{"vip"}, "pro")
+	segmentContact(models.JSONBArray{"vip"}, "free")
+	segmentContact(models.JSONBArray{"newsletter"}, "pro")
+	blocked := segmentContact(models.JSONBArray{"vip"}, "pro")
+	require.NoError(t, app.DB.Create(&models.PhoneBlacklist{
+		OrganizationID: org.ID,
+		PhoneNumber:    strings.TrimPrefix(blocked.PhoneNumber, "+"),
+	}).Error)
+
+	// Another organization's matching contact must not be targeted
+	otherOrg := testutil.CreateTestOrganization(t, app.DB)
+	other := testutil.CreateTestContact(t, app.DB, otherOrg.ID)
+	require.NoError(t, app.DB.Model(other).Updates(map[string]any{
+		"tags":          models.JSONBArray{"vip"},
+		"custom_fields": models.JSONB{"plan": "pro"},
+	}).Error)
+
+	req := testutil.NewJSONRequest(t, map[string]interface{}{
+		"name":             "VIP Pro Campaign",
+		"whatsapp_account": account.Name,
+		"template_id":      template.ID.String(),
+		"segment": map[string]interface{}{
+			"tags":          []string{"vip"},
+			"custom_fields": map[string]string{"plan": "pro"},
+		},
+		"template_params": map[string]interface{}{"1": "SPRING"},
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.CreateCampaign(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.CampaignResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, 1, resp.Data.TotalRecipients)
+
+	var recipients []models.BulkMessageRecipient
+	require.NoError(t, app.DB.Where("campaign_id = ?", resp.Data.ID).Find(&recipients).Error)
+	require.Len(t, recipients, 1)
+	assert.Equal(t, vipPro.PhoneNumber, recipients[0].PhoneNumber)
+	assert.Equal(t, vipPro.ProfileName, recipients[0].RecipientName)
+	assert.Equal(t, models.MessageStatusPending, recipients[0].Status)
+	assert.Equal(t, "SPRING", recipients[0].TemplateParams["1"])
+}
+
+func TestApp_CreateCampaign_EmptySegment(t *testing.T) {
+	mockQueue := testutil.NewMockQueue()
+	app := newTestApp(t, withQueue(mockQueue))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("empty-segment-account"))
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+
+	req := testutil.NewJSONRequest(t, map[string]interface{}{
+		"name":             "Everyone",
+		"whatsapp_account": account.Name,
+		"template_id":      template.ID.String(),
+		"segment":          map[string]interface{}{},
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.CreateCampaign(req))
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+}
+
+func TestApp_CreateCampaign_TemplateNotApproved(t *testing.T) {
+	mockQueue := testutil.NewMockQueue()
+	app := newTestApp(t, withQueue(mockQueue))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("pending-template-account"))
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+	require.NoError(t, app.DB.Model(template).Update("status", models.TemplateStatusPending).Error)
+
+	req := testutil.NewJSONRequest(t, map[string]interface{}{
+		"name":             "Test Campaign",
+		"whatsapp_account": account.Name,
+		"template_id":      template.ID.String(),
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.CreateCampaign(req))
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+}
+
+func TestApp_CreateCampaign_RequiresPermission(t *testing.T) {
+	mockQueue := testutil.NewMockQueue()
+	app := newTestApp(t, withQueue(mockQueue))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("no-permission-account"))
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+
+	req := testutil.NewJSONRequest(t, map[string]interface{}{
+		"name":             "Test Campaign",
+		"whatsapp_account": account.Name,
+		"template_id":      template.ID.String(),
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.CreateCampaign(req))
+	assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+}
+
 // --- GetCampaign Tests ---
 
 func TestApp_GetCampaign_Success(t *testing.T) {
@@ -315,6 +469,87 @@ func TestApp_GetCampaign_InvalidID(t *testing.T) {
 	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 }
 
+// --- GetCampaignQueueStatus Tests ---
+
+func TestApp_GetCampaignQueueStatus_Running(t *testing.T) {
+	mockQueue := testutil.NewMockQueue()
+	app := newTestApp(t, withQueue(mockQueue))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("queue-status-account"))
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+	campaign := createTestCampaign(t, app, org.ID, template.ID, user.ID, account.Name, models.CampaignStatusProcessing)
+	for i := 0; i < 25; i++ {
+		createTestRecipient(t, app, campaign.ID, fmt.Sprintf("+1555%07d", i), models.MessageStatusPending)
+	}
+	createTestRecipient(t, app, campaign.ID, "+15559999999", models.MessageStatusSent)
+	require.NoError(t, app.DB.Model(org).Update("settings", models.JSONB{"campaign_messages_per_second": 10}).Error)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", campaign.ID.String())
+
+	require.NoError(t, app.GetCampaignQueueStatus(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.CampaignQueueStatusResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, int64(25), resp.Data.QueueDepth)
+	assert.Equal(t, 10, resp.Data.MessagesPerSecond)
+	require.NotNil(t, resp.Data.EstimatedSecondsLeft)
+	assert.Equal(t, int64(3), *resp.Data.EstimatedSecondsLeft)
+	require.NotNil(t, resp.Data.EstimatedCompletionAt)
+	assert.WithinDuration(t, time.Now().Add(3*time.Second), *resp.Data.EstimatedCompletionAt, 2*time.Second)
+}
+
+func TestApp_GetCampaignQueueStatus_NotRunning(t *testing.T) {
+	mockQueue := testutil.NewMockQueue()
+	app := newTestApp(t, withQueue(mockQueue))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("queue-paused-account"))
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+	campaign := createTestCampaign(t, app, org.ID, template.ID, user.ID, account.Name, models.CampaignStatusPaused)
+	createTestRecipient(t, app, campaign.ID, "+15550000001", models.MessageStatusPending)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", campaign.ID.String())
+
+	require.NoError(t, app.GetCampaignQueueStatus(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.CampaignQueueStatusResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, int64(1), resp.Data.QueueDepth)
+	assert.Equal(t, models.DefaultCampaignMessagesPerSecond, resp.Data.MessagesPerSecond)
+	assert.Nil(t, resp.Data.EstimatedSecondsLeft)
+	assert.Nil(t, resp.Data.EstimatedCompletionAt)
+}
+
+func TestApp_GetCampaignQueueStatus_RequiresPermission(t *testing.T) {
+	mockQueue := testutil.NewMockQueue()
+	app := newTestApp(t, withQueue(mockQueue))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	account := testutil.CreateTestWhatsAppAccountWith(t, app.DB, org.ID, testutil.WithAccountName("queue-forbidden-account"))
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+	campaign := createTestCampaign(t, app, org.ID, template.ID, user.ID, account.Name, models.CampaignStatusProcessing)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", campaign.ID.String())
+
+	require.NoError(t, app.GetCampaignQueueStatus(req))
+	assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+}
+
 // --- UpdateCampaign Tests ---
 
 func TestApp_UpdateCampaign_Success(t *testing.T) {
@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/google/uuid"
 	"github.com/shridarpatil/whatomate/internal/models"
 	"github.com/valyala/fasthttp"
@@ -109,6 +112,11 @@ func (a *App) CreateCannedResponse(r *fastglue.Request) error {
 			"Canned response with this name already exists", nil, "")
 	}
 
+	if a.cannedShortcutTaken(orgID, req.Shortcut, uuid.Nil) {
+		return r.SendErrorEnvelope(fasthttp.StatusConflict,
+			"Canned response with this shortcut already exists", nil, "")
+	}
+
 	cannedResponse := models.CannedResponse{
 		OrganizationID: orgID,
 		Name:           req.Name,
@@ -150,6 +158,31 @@ func (a *App) GetCannedResponse(r *fastglue.Request) error {
 	return r.SendEnvelope(cannedResponseToResponse(cannedResponse))
 }
 
+// GetCannedResponseByShortcut resolves a shortcut typed in the composer to its
+// active canned response. Shortcuts match with or without the leading "/".
+// When several responses share the shortcut, the most used one wins.
+func (a *App) GetCannedResponseByShortcut(r *fastglue.Request) error {
+	orgID, err := a.getOrgID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	shortcut := strings.TrimPrefix(strings.TrimSpace(string(r.RequestCtx.QueryArgs().Peek("shortcut"))), "/")
+	if shortcut == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "shortcut is required", nil, "")
+	}
+
+	var cannedResponse models.CannedResponse
+	if err := a.DB.Where("organization_id = ? AND is_active = ? AND shortcut IN ?", orgID, true, []string{shortcut, "/" + shortcut}).
+		Order("usage_count DESC, created_at ASC").
+		First(&cannedResponse).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound,
+			"Canned response not found", nil, "")
+	}
+
+	return r.SendEnvelope(cannedResponseToResponse(cannedResponse))
+}
+
 // UpdateCannedResponse updates an existing canned response
 func (a *App) UpdateCannedResponse(r *fastglue.Request) error {
 	orgID, err := a.getOrgID(r)
@@ -174,6 +207,11 @@ func (a *App) UpdateCannedResponse(r *fastglue.Request) error {
 		return nil
 	}
 
+	if req.Shortcut != cannedResponse.Shortcut && a.cannedShortcutTaken(orgID, req.Shortcut, cannedResponse.ID) {
+		return r.SendErrorEnvelope(fasthttp.StatusConflict,
+			"Canned response with this shortcut already exists", nil, "")
+	}
+
 	// Update fields
 	if req.Name != "" {
 		cannedResponse.Name = req.Name
@@ -244,6 +282,161 @@ func (a *App) IncrementCannedResponseUsage(r *fastglue.Request) error {
 	return r.SendEnvelope(map[string]string{"message": "Usage incremented"})
 }
 
+// BulkRecategorizeCannedResponsesRequest selects canned responses either by ID
+// or by their current category and moves them to a new category
+type BulkRecategorizeCannedResponsesRequest struct {
+	IDs         []uuid.UUID `json:"ids"`
+	OldCategory string      `json:"old_category"`
+	Category    string      `json:"category"`
+}
+
+// BulkRecategorizeCannedResponses moves many canned responses to a new category at once
+func (a *App) BulkRecategorizeCannedResponses(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceCannedResponses, models.ActionWrite, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Permission denied", nil, "")
+	}
+
+	var req BulkRecategorizeCannedResponsesRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	req.OldCategory = strings.TrimSpace(req.OldCategory)
+	req.Category = strings.TrimSpace(req.Category)
+	if (len(req.IDs) > 0) == (req.OldCategory != "") {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Provide either ids or old_category", nil, "")
+	}
+	if len(req.Category) > 50 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "category must be at most 50 characters", nil, "")
+	}
+
+	var updated int64
+	err = a.DB.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.CannedResponse{}).Where("organization_id = ?", orgID)
+		if len(req.IDs) > 0 {
+			query = query.Where("id IN ?", req.IDs)
+		} else {
+			query = query.Where("category = ?", req.OldCategory)
+		}
+		result := query.Update("category", req.Category)
+		if result.Error != nil {
+			return result.Error
+		}
+		updated = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		a.Log.Error("Failed to recategorize canned responses", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError,
+			"Failed to update canned responses", nil, "")
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"updated": updated,
+		"message": fmt.Sprintf("%d canned responses updated", updated),
+	})
+}
+
+// cannedShortcutTaken reports whether another canned response already uses the
+// shortcut, with or without the leading "/" since both resolve the same way.
+// Duplicates are only rejected when the organization enforces unique shortcuts.
+func (a *App) cannedShortcutTaken(orgID uuid.UUID, shortcut string, excludeID uuid.UUID) bool {
+	shortcut = strings.TrimPrefix(strings.TrimSpace(shortcut), "/")
+	if shortcut == "" || !a.isUniqueCannedShortcutsEnabled(orgID) {
+		return false
+	}
+	var count int64
+	a.DB.Model(&models.CannedResponse{}).
+		Where("organization_id = ? AND shortcut IN ? AND id != ?", orgID, []string{shortcut, "/" + shortcut}, excludeID).
+		Count(&count)
+	return count > 0
+}
+
+// RenderCannedResponseRequest selects the contact to personalize a canned response for
+type RenderCannedResponseRequest struct {
+	ContactID uuid.UUID `json:"contact_id"`
+}
+
+// RenderCannedResponse fills a canned response's {{placeholders}} with the
+// contact's fields and the data collected in their latest chatbot session.
+// Placeholders with no value are left in the text and listed as unresolved.
+func (a *App) RenderCannedResponse(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	id, err := parsePathUUID(r, "id", "canned response")
+	if err != nil {
+		return nil
+	}
+
+	var req RenderCannedResponseRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	if req.ContactID == uuid.Nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "contact_id is required", nil, "")
+	}
+
+	var cannedResponse models.CannedResponse
+	if err := a.DB.Where("id = ? AND organization_id = ?", id, orgID).
+		First(&cannedResponse).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound,
+			"Canned response not found", nil, "")
+	}
+
+	// Users without contacts:read can only render for contacts assigned to them
+	var contact models.Contact
+	query := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", req.ContactID, orgID), userID, orgID)
+	if err := query.First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	data := make(map[string]interface{})
+	var session models.ChatbotSession
+	if err := a.DB.Where("contact_id = ? AND organization_id = ?", contact.ID, orgID).
+		Order("last_activity_at DESC").First(&session).Error; err == nil {
+		for k, v := range session.SessionData {
+			data[k] = v
+		}
+	}
+	// Contact fields take precedence over session data with the same name
+	data["contact_name"] = contact.ProfileName
+	data["profile_name"] = contact.ProfileName
+	data["phone_number"] = contact.PhoneNumber
+
+	content, unresolved := renderPlaceholders(cannedResponse.Content, data)
+	return r.SendEnvelope(map[string]any{
+		"content":    content,
+		"unresolved": unresolved,
+	})
+}
+
+// renderPlaceholders replaces {{placeholders}} that have a non-empty value and
+// returns the names of the ones left in place
+func renderPlaceholders(content string, data map[string]interface{}) (string, []string) {
+	unresolved := []string{}
+	seen := make(map[string]bool)
+	rendered := variablePattern.ReplaceAllStringFunc(content, func(match string) string {
+		path := match[2 : len(match)-2]
+		if value := formatValue(getNestedValue(data, path)); value != "" {
+			return value
+		}
+		if !seen[path] {
+			seen[path] = true
+			unresolved = append(unresolved, path)
+		}
+		return match
+	})
+	return rendered, unresolved
+}
+
 func cannedResponseToResponse(cr models.CannedResponse) CannedResponseResponse {
 	return CannedResponseResponse{
 		ID:         cr.ID,
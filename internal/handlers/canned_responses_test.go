@@ -667,7 +667,7 @@ func TestApp_CreateCannedResponse_DuplicateShortcut(t *testing.T) {
 	createTestCannedResponse(t, app, org.ID, user.ID, "First", "/dup-shortcut", "First content", "general")
 
 	// Creating a second canned response with the same shortcut but different name should succeed,
-	// since the handler only checks for duplicate names, not shortcuts.
+	// since unique shortcuts aren't enforced unless the organization enables it.
 	req := testutil.NewJSONRequest(t, map[string]any{
 		"name":     "Second",
 		"shortcut": "/dup-shortcut",
@@ -688,6 +688,79 @@ func TestApp_CreateCannedResponse_DuplicateShortcut(t *testing.T) {
 	assert.Equal(t, "/dup-shortcut", resp.Data.Shortcut)
 }
 
+func TestApp_CannedResponse_UniqueShortcutsEnforced(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(&models.Organization{}).Where("id = ?", org.ID).
+		Update("settings", models.JSONB{"unique_canned_shortcuts": true}).Error)
+
+	first := createTestCannedResponse(t, app, org.ID, user.ID, "First", "/taken", "First content", "general")
+	other := createTestCannedResponse(t, app, org.ID, user.ID, "Other", "/free", "Other content", "general")
+
+	t.Run("create with colliding shortcut", func(t *testing.T) {
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":     "Second",
+			"shortcut": "/taken",
+			"content":  "Second content",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.CreateCannedResponse(req))
+		testutil.AssertErrorResponse(t, req, fasthttp.StatusConflict, "Canned response with this shortcut already exists")
+	})
+
+	t.Run("create with shortcut differing only by leading slash", func(t *testing.T) {
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":     "Slashless",
+			"shortcut": "taken",
+			"content":  "Slashless content",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.CreateCannedResponse(req))
+		testutil.AssertErrorResponse(t, req, fasthttp.StatusConflict, "Canned response with this shortcut already exists")
+	})
+
+	t.Run("create without shortcut", func(t *testing.T) {
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":    "No Shortcut",
+			"content": "Plain content",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.CreateCannedResponse(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("update to colliding shortcut", func(t *testing.T) {
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"shortcut":  "/taken",
+			"is_active": true,
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", other.ID.String())
+
+		require.NoError(t, app.UpdateCannedResponse(req))
+		testutil.AssertErrorResponse(t, req, fasthttp.StatusConflict, "Canned response with this shortcut already exists")
+	})
+
+	t.Run("update keeping own shortcut", func(t *testing.T) {
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"shortcut":  "/taken",
+			"content":   "Updated content",
+			"is_active": true,
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", first.ID.String())
+
+		require.NoError(t, app.UpdateCannedResponse(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+	})
+}
+
 func TestApp_CreateCannedResponse_SameNameDifferentOrgs(t *testing.T) {
 	t.Parallel()
 
@@ -1380,3 +1453,239 @@ func TestApp_IncrementCannedResponseUsage(t *testing.T) {
 		assert.Equal(t, fasthttp.StatusUnauthorized, testutil.GetResponseStatusCode(req))
 	})
 }
+
+// --- BulkRecategorizeCannedResponses Tests ---
+
+func TestApp_BulkRecategorizeCannedResponses(t *testing.T) {
+	t.Parallel()
+
+	t.Run("moves a category", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		a := createTestCannedResponse(t, app, org.ID, user.ID, "Hello", "/hello", "Hello!", "general")
+		b := createTestCannedResponse(t, app, org.ID, user.ID, "Hi", "/hi", "Hi!", "general")
+		c := createTestCannedResponse(t, app, org.ID, user.ID, "Price", "/price", "It costs $5", "sales")
+
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		otherUser := testutil.CreateTestUser(t, app.DB, otherOrg.ID)
+		other := createTestCannedResponse(t, app, otherOrg.ID, otherUser.ID, "Hello", "/hello", "Hello!", "general")
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"old_category": "general",
+			"category":     "greeting",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.BulkRecategorizeCannedResponses(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Updated int64 `json:"updated"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, int64(2), resp.Data.Updated)
+
+		for id, want := range map[uuid.UUID]string{a.ID: "greeting", b.ID: "greeting", c.ID: "sales", other.ID: "general"} {
+			var cr models.CannedResponse
+			require.NoError(t, app.DB.First(&cr, "id = ?", id).Error)
+			assert.Equal(t, want, cr.Category)
+		}
+	})
+
+	t.Run("moves selected ids", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		a := createTestCannedResponse(t, app, org.ID, user.ID, "Hello", "/hello", "Hello!", "general")
+		b := createTestCannedResponse(t, app, org.ID, user.ID, "Price", "/price", "It costs $5", "sales")
+		c := createTestCannedResponse(t, app, org.ID, user.ID, "Bye", "/bye", "Goodbye!", "general")
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"ids":      []string{a.ID.String(), b.ID.String()},
+			"category": "support",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.BulkRecategorizeCannedResponses(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		for id, want := range map[uuid.UUID]string{a.ID: "support", b.ID: "support", c.ID: "general"} {
+			var cr models.CannedResponse
+			require.NoError(t, app.DB.First(&cr, "id = ?", id).Error)
+			assert.Equal(t, want, cr.Category)
+		}
+	})
+
+	t.Run("requires ids or old category", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		req := testutil.NewJSONRequest(t, map[string]any{"category": "support"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.BulkRecategorizeCannedResponses(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("forbidden without write permission", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+
+		req := testutil.NewJSONRequest(t, map[string]any{"old_category": "general", "category": "support"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.BulkRecategorizeCannedResponses(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_RenderCannedResponse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fills contact and session placeholders", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithPhoneNumber("15550001111"))
+		require.NoError(t, app.DB.Model(contact).Update("profile_name", "Asha").Error)
+
+		require.NoError(t, app.DB.Create(&models.ChatbotSession{
+			BaseModel:       models.BaseModel{ID: uuid.New()},
+			OrganizationID:  org.ID,
+			ContactID:       contact.ID,
+			WhatsAppAccount: contact.WhatsAppAccount,
+			PhoneNumber:     contact.PhoneNumber,
+			Status:          models.SessionStatusCompleted,
+			SessionData:     models.JSONB{"order_id": "ORD-42"},
+		}).Error)
+
+		cr := createTestCannedResponse(t, app, org.ID, user.ID, "Order Update", "/order",
+			"Hi {{contact_name}}, order {{order_id}} for {{phone_number}} ships {{ship_date}}. {{ship_date}}", "support")
+
+		req := testutil.NewJSONRequest(t, map[string]any{"contact_id": contact.ID.String()})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", cr.ID.String())
+
+		require.NoError(t, app.RenderCannedResponse(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Content    string   `json:"content"`
+				Unresolved []string `json:"unresolved"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, "Hi Asha, order ORD-42 for 15550001111 ships {{ship_date}}. {{ship_date}}", resp.Data.Content)
+		assert.Equal(t, []string{"ship_date"}, resp.Data.Unresolved)
+	})
+
+	t.Run("agent cannot render for unassigned contact", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		agent := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		cr := createTestCannedResponse(t, app, org.ID, agent.ID, "Hello", "/hello", "Hello {{contact_name}}", "greeting")
+
+		req := testutil.NewJSONRequest(t, map[string]any{"contact_id": contact.ID.String()})
+		testutil.SetAuthContext(req, org.ID, agent.ID)
+		testutil.SetPathParam(req, "id", cr.ID.String())
+
+		require.NoError(t, app.RenderCannedResponse(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("canned response from another org", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		otherUser := testutil.CreateTestUser(t, app.DB, otherOrg.ID)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		cr := createTestCannedResponse(t, app, otherOrg.ID, otherUser.ID, "Other", "/other", "Hi {{contact_name}}", "general")
+
+		req := testutil.NewJSONRequest(t, map[string]any{"contact_id": contact.ID.String()})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", cr.ID.String())
+
+		require.NoError(t, app.RenderCannedResponse(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_GetCannedResponseByShortcut(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the most used active match", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		createTestCannedResponse(t, app, org.ID, user.ID, "Greet A", "/greet", "Hello!", "greeting")
+		popular := createTestCannedResponse(t, app, org.ID, user.ID, "Greet B", "/greet", "Hi there!", "greeting")
+		require.NoError(t, app.DB.Model(popular).Update("usage_count", 5).Error)
+		inactive := createTestCannedResponse(t, app, org.ID, user.ID, "Greet C", "/greet", "Hey!", "greeting")
+		require.NoError(t, app.DB.Model(inactive).Updates(map[string]any{"usage_count": 50, "is_active": false}).Error)
+
+		for _, shortcut := range []string{"/greet", "greet"} {
+			req := testutil.NewGETRequest(t)
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			testutil.SetQueryParam(req, "shortcut", shortcut)
+
+			require.NoError(t, app.GetCannedResponseByShortcut(req))
+			require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+			var resp struct {
+				Data handlers.CannedResponseResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+			assert.Equal(t, popular.ID, resp.Data.ID)
+			assert.Equal(t, "Hi there!", resp.Data.Content)
+		}
+	})
+
+	t.Run("not found in another org", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		otherUser := testutil.CreateTestUser(t, app.DB, otherOrg.ID)
+
+		createTestCannedResponse(t, app, otherOrg.ID, otherUser.ID, "Theirs", "/theirs", "Not yours", "general")
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "shortcut", "/theirs")
+
+		require.NoError(t, app.GetCannedResponseByShortcut(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("missing shortcut", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.GetCannedResponseByShortcut(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+}
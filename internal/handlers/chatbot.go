@@ -1,10 +1,21 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/crypto"
 	"github.com/shridarpatil/whatomate/internal/models"
 	"github.com/valyala/fasthttp"
 	"github.com/zerodha/fastglue"
@@ -19,18 +30,42 @@ type ChatbotSettingsResponse struct {
 	FallbackMessage       string                   `json:"fallback_message"`
 	FallbackButtons       []map[string]interface{} `json:"fallback_buttons"`
 	SessionTimeoutMinutes int                      `json:"session_timeout_minutes"`
+	SessionWarningPercent int                      `json:"session_warning_percent"`
+	SessionWarningMessage string                   `json:"session_warning_message"`
+	SessionResumeGraceMinutes  int                      `json:"session_resume_grace_minutes"`
+	InboundRateLimitPerMinute  int                      `json:"inbound_rate_limit_per_minute"`
+	InboundRateLimitFlag       bool                     `json:"inbound_rate_limit_flag_contact"`
 	BusinessHoursEnabled       bool                     `json:"business_hours_enabled"`
 	BusinessHours              []map[string]interface{} `json:"business_hours"`
 	OutOfHoursMessage          string                   `json:"out_of_hours_message"`
 	AllowAutomatedOutsideHours bool                     `json:"allow_automated_outside_hours"`
+	BusinessHoursTimezone      string                   `json:"business_hours_timezone"`
+	QuietHoursEnabled          bool                     `json:"quiet_hours_enabled"`
+	QuietHoursStart            string                   `json:"quiet_hours_start"`
+	QuietHoursEnd              string                   `json:"quiet_hours_end"`
+	QuietHoursTimezone         string                   `json:"quiet_hours_timezone"`
 	AllowAgentQueuePickup        bool                     `json:"allow_agent_queue_pickup"`
 	AssignToSameAgent            bool                     `json:"assign_to_same_agent"`
 	AgentCurrentConversationOnly bool                     `json:"agent_current_conversation_only"`
+	UnassignOnResolve            bool                     `json:"unassign_on_resolve"`
+	AssignmentStrategy           string                   `json:"assignment_strategy"`
+	AssignmentFallbackType       string                   `json:"assignment_fallback_type"`
+	AssignmentFallbackUserID     *uuid.UUID               `json:"assignment_fallback_user_id"`
+	AssignmentFallbackTeamID     *uuid.UUID               `json:"assignment_fallback_team_id"`
 	AIEnabled                    bool                     `json:"ai_enabled"`
 	AIProvider            models.AIProvider        `json:"ai_provider"`
+	AIHasKey              bool                     `json:"ai_has_key"`
+	AIAPIKeyMasked        string                   `json:"ai_api_key_masked"`
+	AIAPIKeyUpdatedAt     *time.Time               `json:"ai_api_key_updated_at,omitempty"`
 	AIModel               string                   `json:"ai_model"`
 	AIMaxTokens           int                      `json:"ai_max_tokens"`
 	AISystemPrompt        string                   `json:"ai_system_prompt"`
+	AIMaxConcurrency             int                      `json:"ai_max_concurrency"`
+	AIQueueSize                  int                      `json:"ai_queue_size"`
+	AIContextLimit               int                      `json:"ai_context_limit"`
+	AIContextMaxChars            int                      `json:"ai_context_max_chars"`
+	AITimeoutSeconds             int                      `json:"ai_timeout_seconds"`
+	AIFailureAction              models.AIFailureAction   `json:"ai_failure_action"`
 	// SLA Settings
 	SLAEnabled             bool     `json:"sla_enabled"`
 	SLAResponseMinutes     int      `json:"sla_response_minutes"`
@@ -66,22 +101,29 @@ type KeywordRuleResponse struct {
 	Name            string             `json:"name"`
 	Keywords        []string           `json:"keywords"`
 	MatchType       models.MatchType   `json:"match_type"`
+	CaseSensitive   bool               `json:"case_sensitive"`
+	WordBoundary    bool               `json:"word_boundary"`
 	ResponseType    models.ResponseType `json:"response_type"`
 	ResponseContent json.RawMessage    `json:"response_content"`
 	Priority        int                `json:"priority"`
 	Enabled         bool               `json:"enabled"`
+	HitCount        int64               `json:"hit_count"`
 	CreatedAt       string             `json:"created_at"`
 }
 
 // ChatbotFlowResponse represents a chatbot flow for API response
 type ChatbotFlowResponse struct {
-	ID              string   `json:"id"`
-	Name            string   `json:"name"`
-	Description     string   `json:"description"`
-	TriggerKeywords []string `json:"trigger_keywords"`
-	Enabled         bool     `json:"enabled"`
-	StepsCount      int      `json:"steps_count"`
-	CreatedAt       string   `json:"created_at"`
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	TriggerType     string     `json:"trigger_type"`
+	TriggerKeywords []string   `json:"trigger_keywords"`
+	TriggerButtonID string     `json:"trigger_button_id,omitempty"`
+	Enabled         bool       `json:"enabled"`
+	StepsCount      int        `json:"steps_count"`
+	ActiveFrom      *time.Time `json:"active_from,omitempty"`
+	ActiveUntil     *time.Time `json:"active_until,omitempty"`
+	CreatedAt       string     `json:"created_at"`
 }
 
 // AIContextResponse represents an AI context for API response
@@ -116,6 +158,10 @@ func (a *App) GetChatbotSettings(r *fastglue.Request) error {
 		}
 	}
 
+	// Only a masked suffix of the AI key ever leaves the server
+	a.decryptChatbotSecrets(&settings)
+	aiKeyMasked := maskSecret(settings.AI.APIKey)
+
 	// Gather stats
 	stats := a.getChatbotStats(orgID)
 
@@ -155,21 +201,46 @@ func (a *App) GetChatbotSettings(r *fastglue.Request) error {
 		FallbackMessage:       settings.FallbackMessage,
 		FallbackButtons:       fallbackButtons,
 		SessionTimeoutMinutes: settings.SessionTimeoutMins,
+		SessionWarningPercent: settings.SessionWarningPercent,
+		SessionWarningMessage: settings.SessionWarningMessage,
+		SessionResumeGraceMinutes: settings.SessionGraceMins,
+		InboundRateLimitPerMinute: settings.FloodControl.MaxPerMinute,
+		InboundRateLimitFlag:      settings.FloodControl.FlagContact,
 		// Business Hours
 		BusinessHoursEnabled:       settings.BusinessHours.Enabled,
 		BusinessHours:              businessHours,
 		OutOfHoursMessage:          settings.BusinessHours.OutOfHoursMessage,
 		AllowAutomatedOutsideHours: settings.BusinessHours.AllowAutomatedOutside,
+		BusinessHoursTimezone:      settings.BusinessHours.Timezone,
+		// Quiet Hours
+		QuietHoursEnabled:  settings.QuietHours.Enabled,
+		QuietHoursStart:    settings.QuietHours.Start,
+		QuietHoursEnd:      settings.QuietHours.End,
+		QuietHoursTimezone: settings.QuietHours.Timezone,
 		// Agent Assignment
 		AllowAgentQueuePickup:        settings.AgentAssignment.AllowQueuePickup,
 		AssignToSameAgent:            settings.AgentAssignment.AssignToSameAgent,
 		AgentCurrentConversationOnly: settings.AgentAssignment.CurrentConversationOnly,
+		UnassignOnResolve:            settings.AgentAssignment.UnassignOnResolve,
+		AssignmentStrategy:           string(settings.AgentAssignment.Strategy),
+		AssignmentFallbackType:       string(settings.AgentAssignment.FallbackType),
+		AssignmentFallbackUserID:     settings.AgentAssignment.FallbackUserID,
+		AssignmentFallbackTeamID:     settings.AgentAssignment.FallbackTeamID,
 		// AI
-		AIEnabled:      settings.AI.Enabled,
-		AIProvider:     settings.AI.Provider,
-		AIModel:        settings.AI.Model,
-		AIMaxTokens:    settings.AI.MaxTokens,
-		AISystemPrompt: settings.AI.SystemPrompt,
+		AIEnabled:         settings.AI.Enabled,
+		AIProvider:        settings.AI.Provider,
+		AIHasKey:          settings.AI.APIKey != "",
+		AIAPIKeyMasked:    aiKeyMasked,
+		AIAPIKeyUpdatedAt: settings.AI.APIKeyUpdatedAt,
+		AIModel:           settings.AI.Model,
+		AIMaxTokens:       settings.AI.MaxTokens,
+		AISystemPrompt:    settings.AI.SystemPrompt,
+		AIMaxConcurrency:  settings.AI.MaxConcurrency,
+		AIQueueSize:       settings.AI.QueueSize,
+		AIContextLimit:    settings.AI.ContextLimit,
+		AIContextMaxChars: settings.AI.ContextMaxChars,
+		AITimeoutSeconds:  settings.AI.TimeoutSecs,
+		AIFailureAction:   settings.AI.FailureAction,
 		// SLA Settings
 		SLAEnabled:             settings.SLA.Enabled,
 		SLAResponseMinutes:     settings.SLA.ResponseMinutes,
@@ -207,19 +278,40 @@ func (a *App) UpdateChatbotSettings(r *fastglue.Request) error {
 		FallbackMessage            *string                    `json:"fallback_message"`
 		FallbackButtons            *[]map[string]interface{}  `json:"fallback_buttons"`
 		SessionTimeoutMinutes      *int                       `json:"session_timeout_minutes"`
+		SessionWarningPercent      *int                       `json:"session_warning_percent"`
+		SessionWarningMessage      *string                    `json:"session_warning_message"`
+		SessionResumeGraceMinutes  *int                       `json:"session_resume_grace_minutes"`
+		InboundRateLimitPerMinute  *int                       `json:"inbound_rate_limit_per_minute"`
+		InboundRateLimitFlag       *bool                      `json:"inbound_rate_limit_flag_contact"`
 		BusinessHoursEnabled       *bool                      `json:"business_hours_enabled"`
 		BusinessHours              *[]map[string]interface{}  `json:"business_hours"`
 		OutOfHoursMessage          *string                    `json:"out_of_hours_message"`
 		AllowAutomatedOutsideHours *bool                      `json:"allow_automated_outside_hours"`
+		BusinessHoursTimezone      *string                    `json:"business_hours_timezone"`
+		QuietHoursEnabled          *bool                      `json:"quiet_hours_enabled"`
+		QuietHoursStart            *string                    `json:"quiet_hours_start"`
+		QuietHoursEnd              *string                    `json:"quiet_hours_end"`
+		QuietHoursTimezone         *string                    `json:"quiet_hours_timezone"`
 		AllowAgentQueuePickup        *bool                      `json:"allow_agent_queue_pickup"`
 		AssignToSameAgent            *bool                      `json:"assign_to_same_agent"`
 		AgentCurrentConversationOnly *bool                      `json:"agent_current_conversation_only"`
+		UnassignOnResolve            *bool                      `json:"unassign_on_resolve"`
+		AssignmentStrategy           *models.AssignmentStrategy `json:"assignment_strategy"`
+		AssignmentFallbackType       *models.AssignmentFallback `json:"assignment_fallback_type"`
+		AssignmentFallbackUserID     *string                    `json:"assignment_fallback_user_id"`
+		AssignmentFallbackTeamID     *string                    `json:"assignment_fallback_team_id"`
 		AIEnabled                    *bool                      `json:"ai_enabled"`
 		AIProvider                 *models.AIProvider         `json:"ai_provider"`
 		AIAPIKey                   *string                    `json:"ai_api_key"`
 		AIModel                    *string                    `json:"ai_model"`
 		AIMaxTokens                *int                       `json:"ai_max_tokens"`
 		AISystemPrompt             *string                    `json:"ai_system_prompt"`
+		AIMaxConcurrency             *int                       `json:"ai_max_concurrency"`
+		AIQueueSize                  *int                       `json:"ai_queue_size"`
+		AIContextLimit               *int                       `json:"ai_context_limit"`
+		AIContextMaxChars            *int                       `json:"ai_context_max_chars"`
+		AITimeoutSeconds             *int                       `json:"ai_timeout_seconds"`
+		AIFailureAction              *models.AIFailureAction    `json:"ai_failure_action"`
 		// SLA Settings
 		SLAEnabled             *bool     `json:"sla_enabled"`
 		SLAResponseMinutes     *int      `json:"sla_response_minutes"`
@@ -251,6 +343,9 @@ func (a *App) UpdateChatbotSettings(r *fastglue.Request) error {
 		settings = models.ChatbotSettings{
 			BaseModel:      models.BaseModel{ID: uuid.New()},
 			OrganizationID: orgID,
+			// Column defaults, so timings are validated against what gets stored
+			SLA:              models.SLAConfig{ResponseMinutes: 15, EscalationMinutes: 30, ResolutionMinutes: 60, AutoCloseHours: 24},
+			ClientInactivity: models.ClientInactivityConfig{ReminderMinutes: 30, AutoCloseMinutes: 60},
 		}
 	}
 
@@ -281,11 +376,38 @@ func (a *App) UpdateChatbotSettings(r *fastglue.Request) error {
 	if req.SessionTimeoutMinutes != nil {
 		settings.SessionTimeoutMins = *req.SessionTimeoutMinutes
 	}
+	if req.SessionWarningPercent != nil {
+		if *req.SessionWarningPercent < 0 || *req.SessionWarningPercent >= 100 {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "session_warning_percent must be between 0 and 99", nil, "")
+		}
+		settings.SessionWarningPercent = *req.SessionWarningPercent
+	}
+	if req.SessionWarningMessage != nil {
+		settings.SessionWarningMessage = *req.SessionWarningMessage
+	}
+	if req.SessionResumeGraceMinutes != nil {
+		if *req.SessionResumeGraceMinutes < 0 || *req.SessionResumeGraceMinutes > 1440 {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "session_resume_grace_minutes must be between 0 and 1440", nil, "")
+		}
+		settings.SessionGraceMins = *req.SessionResumeGraceMinutes
+	}
+	if req.InboundRateLimitPerMinute != nil {
+		if *req.InboundRateLimitPerMinute < 0 || *req.InboundRateLimitPerMinute > maxInboundRateLimit {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, fmt.Sprintf("inbound_rate_limit_per_minute must be between 0 and %d", maxInboundRateLimit), nil, "")
+		}
+		settings.FloodControl.MaxPerMinute = *req.InboundRateLimitPerMinute
+	}
+	if req.InboundRateLimitFlag != nil {
+		settings.FloodControl.FlagContact = *req.InboundRateLimitFlag
+	}
 	// Business Hours
 	if req.BusinessHoursEnabled != nil {
 		settings.BusinessHours.Enabled = *req.BusinessHoursEnabled
 	}
 	if req.BusinessHours != nil {
+		if errMsg := validateBusinessHours(*req.BusinessHours); errMsg != "" {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+		}
 		hours := make([]interface{}, len(*req.BusinessHours))
 		for i, bh := range *req.BusinessHours {
 			hours[i] = bh
@@ -298,6 +420,29 @@ func (a *App) UpdateChatbotSettings(r *fastglue.Request) error {
 	if req.AllowAutomatedOutsideHours != nil {
 		settings.BusinessHours.AllowAutomatedOutside = *req.AllowAutomatedOutsideHours
 	}
+	if req.BusinessHoursTimezone != nil {
+		tz := strings.TrimSpace(*req.BusinessHoursTimezone)
+		if tz != "" {
+			if _, err := time.LoadLocation(tz); err != nil {
+				return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid business_hours_timezone", nil, "")
+			}
+		}
+		settings.BusinessHours.Timezone = tz
+	}
+
+	// Quiet Hours
+	if req.QuietHoursEnabled != nil {
+		settings.QuietHours.Enabled = *req.QuietHoursEnabled
+	}
+	if req.QuietHoursStart != nil {
+		settings.QuietHours.Start = strings.TrimSpace(*req.QuietHoursStart)
+	}
+	if req.QuietHoursEnd != nil {
+		settings.QuietHours.End = strings.TrimSpace(*req.QuietHoursEnd)
+	}
+	if req.QuietHoursTimezone != nil {
+		settings.QuietHours.Timezone = strings.TrimSpace(*req.QuietHoursTimezone)
+	}
 
 	// Agent Assignment
 	if req.AllowAgentQueuePickup != nil {
@@ -309,8 +454,48 @@ func (a *App) UpdateChatbotSettings(r *fastglue.Request) error {
 	if req.AgentCurrentConversationOnly != nil {
 		settings.AgentAssignment.CurrentConversationOnly = *req.AgentCurrentConversationOnly
 	}
+	if req.UnassignOnResolve != nil {
+		settings.AgentAssignment.UnassignOnResolve = *req.UnassignOnResolve
+	}
+	if req.AssignmentStrategy != nil {
+		switch *req.AssignmentStrategy {
+		case models.AssignmentStrategyManual, models.AssignmentStrategyRoundRobin, models.AssignmentStrategyLeastBusy:
+			settings.AgentAssignment.Strategy = *req.AssignmentStrategy
+		default:
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "assignment_strategy must be one of: manual, round_robin, least_busy", nil, "")
+		}
+	}
+	if req.AssignmentFallbackType != nil {
+		settings.AgentAssignment.FallbackType = *req.AssignmentFallbackType
+	}
+	if req.AssignmentFallbackUserID != nil {
+		settings.AgentAssignment.FallbackUserID = nil
+		if *req.AssignmentFallbackUserID != "" {
+			userID, err := uuid.Parse(*req.AssignmentFallbackUserID)
+			if err != nil {
+				return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid assignment_fallback_user_id", nil, "")
+			}
+			settings.AgentAssignment.FallbackUserID = &userID
+		}
+	}
+	if req.AssignmentFallbackTeamID != nil {
+		settings.AgentAssignment.FallbackTeamID = nil
+		if *req.AssignmentFallbackTeamID != "" {
+			teamID, err := uuid.Parse(*req.AssignmentFallbackTeamID)
+			if err != nil {
+				return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid assignment_fallback_team_id", nil, "")
+			}
+			settings.AgentAssignment.FallbackTeamID = &teamID
+		}
+	}
+	if errMsg := a.validateAssignmentFallback(orgID, settings.AgentAssignment); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
 
 	// AI Settings
+	if errMsg := validateAIProviderModel(req.AIProvider, req.AIModel, settings.AI); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
 	if req.AIEnabled != nil {
 		settings.AI.Enabled = *req.AIEnabled
 	}
@@ -318,10 +503,13 @@ func (a *App) UpdateChatbotSettings(r *fastglue.Request) error {
 		settings.AI.Provider = *req.AIProvider
 	}
 	if req.AIAPIKey != nil && *req.AIAPIKey != "" {
-		settings.AI.APIKey = *req.AIAPIKey
+		if err := a.setAIAPIKey(&settings, *req.AIAPIKey); err != nil {
+			a.Log.Error("Failed to encrypt AI API key", "error", err)
+			return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to save settings", nil, "")
+		}
 	}
 	if req.AIModel != nil {
-		settings.AI.Model = *req.AIModel
+		settings.AI.Model = strings.TrimSpace(*req.AIModel)
 	}
 	if req.AIMaxTokens != nil {
 		settings.AI.MaxTokens = *req.AIMaxTokens
@@ -329,6 +517,44 @@ func (a *App) UpdateChatbotSettings(r *fastglue.Request) error {
 	if req.AISystemPrompt != nil {
 		settings.AI.SystemPrompt = *req.AISystemPrompt
 	}
+	if req.AIMaxConcurrency != nil {
+		if *req.AIMaxConcurrency < 0 {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "ai_max_concurrency cannot be negative", nil, "")
+		}
+		settings.AI.MaxConcurrency = *req.AIMaxConcurrency
+	}
+	if req.AIQueueSize != nil {
+		if *req.AIQueueSize < 0 {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "ai_queue_size cannot be negative", nil, "")
+		}
+		settings.AI.QueueSize = *req.AIQueueSize
+	}
+	if req.AIContextLimit != nil {
+		if *req.AIContextLimit < 0 {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "ai_context_limit cannot be negative", nil, "")
+		}
+		settings.AI.ContextLimit = *req.AIContextLimit
+	}
+	if req.AIContextMaxChars != nil {
+		if *req.AIContextMaxChars < 0 {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "ai_context_max_chars cannot be negative", nil, "")
+		}
+		settings.AI.ContextMaxChars = *req.AIContextMaxChars
+	}
+	if req.AITimeoutSeconds != nil {
+		if *req.AITimeoutSeconds < 0 || *req.AITimeoutSeconds > maxAITimeoutSeconds {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, fmt.Sprintf("ai_timeout_seconds must be between 0 and %d", maxAITimeoutSeconds), nil, "")
+		}
+		settings.AI.TimeoutSecs = *req.AITimeoutSeconds
+	}
+	if req.AIFailureAction != nil {
+		switch *req.AIFailureAction {
+		case "", models.AIFailureFallbackMessage, models.AIFailureTransfer:
+		default:
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "ai_failure_action must be one of: fallback_message, transfer", nil, "")
+		}
+		settings.AI.FailureAction = *req.AIFailureAction
+	}
 
 	// SLA Settings
 	if req.SLAEnabled != nil {
@@ -373,6 +599,17 @@ func (a *App) UpdateChatbotSettings(r *fastglue.Request) error {
 		settings.ClientInactivity.AutoCloseMessage = *req.ClientAutoCloseMessage
 	}
 
+	// Check the resulting timings together, since a request may change only some of them
+	if errMsg := validateSLATimings(settings.SLA); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if errMsg := validateClientInactivityTimings(settings.ClientInactivity); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if errMsg := validateQuietHours(settings.QuietHours); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+
 	if err := a.DB.Save(&settings).Error; err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to save settings", nil, "")
 	}
@@ -392,6 +629,19 @@ func (a *App) UpdateChatbotSettings(r *fastglue.Request) error {
 		if req.AssignToSameAgent != nil && !*req.AssignToSameAgent {
 			zeroOverrides["assign_to_same_agent"] = false
 		}
+		// Timers likewise fall back to their column default when set to 0 (disabled)
+		for column, value := range map[string]*int{
+			"sla_response_minutes":      req.SLAResponseMinutes,
+			"sla_escalation_minutes":    req.SLAEscalationMinutes,
+			"sla_resolution_minutes":    req.SLAResolutionMinutes,
+			"sla_auto_close_hours":      req.SLAAutoCloseHours,
+			"client_reminder_minutes":   req.ClientReminderMinutes,
+			"client_auto_close_minutes": req.ClientAutoCloseMinutes,
+		} {
+			if value != nil && *value == 0 {
+				zeroOverrides[column] = 0
+			}
+		}
 		if len(zeroOverrides) > 0 {
 			if err := a.DB.Model(&settings).Updates(zeroOverrides).Error; err != nil {
 				return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to save settings", nil, "")
@@ -408,6 +658,221 @@ func (a *App) UpdateChatbotSettings(r *fastglue.Request) error {
 	})
 }
 
+// validateSLATimings checks SLA durations are non-negative and that a transfer
+// is due a response before it escalates, and escalates before it must be
+// resolved. Zero disables a timer, so it's left out of the ordering.
+func validateSLATimings(cfg models.SLAConfig) string {
+	for _, field := range []struct {
+		name  string
+		value int
+	}{
+		{"sla_response_minutes", cfg.ResponseMinutes},
+		{"sla_escalation_minutes", cfg.EscalationMinutes},
+		{"sla_resolution_minutes", cfg.ResolutionMinutes},
+		{"sla_auto_close_hours", cfg.AutoCloseHours},
+	} {
+		if field.value < 0 {
+			return field.name + " cannot be negative"
+		}
+	}
+
+	if cfg.ResponseMinutes > 0 && cfg.EscalationMinutes > 0 && cfg.ResponseMinutes > cfg.EscalationMinutes {
+		return "sla_response_minutes cannot exceed sla_escalation_minutes"
+	}
+	if cfg.EscalationMinutes > 0 && cfg.ResolutionMinutes > 0 && cfg.EscalationMinutes > cfg.ResolutionMinutes {
+		return "sla_escalation_minutes cannot exceed sla_resolution_minutes"
+	}
+	if cfg.ResponseMinutes > 0 && cfg.ResolutionMinutes > 0 && cfg.ResponseMinutes > cfg.ResolutionMinutes {
+		return "sla_response_minutes cannot exceed sla_resolution_minutes"
+	}
+	return ""
+}
+
+// validateClientInactivityTimings checks the reminder is sent before an
+// inactive conversation is auto-closed. Zero disables either step.
+func validateClientInactivityTimings(cfg models.ClientInactivityConfig) string {
+	if cfg.ReminderMinutes < 0 {
+		return "client_reminder_minutes cannot be negative"
+	}
+	if cfg.AutoCloseMinutes < 0 {
+		return "client_auto_close_minutes cannot be negative"
+	}
+	if cfg.ReminderEnabled && cfg.ReminderMinutes > 0 && cfg.AutoCloseMinutes > 0 && cfg.ReminderMinutes >= cfg.AutoCloseMinutes {
+		return "client_reminder_minutes must be less than client_auto_close_minutes"
+	}
+	return ""
+}
+
+// validateQuietHours checks an enabled quiet window has distinct HH:MM start
+// and end times, and that any timezone is a known IANA name
+func validateQuietHours(cfg models.QuietHoursConfig) string {
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			return "Invalid quiet_hours_timezone"
+		}
+	}
+	if !cfg.Enabled {
+		return ""
+	}
+	start, err := time.Parse("15:04", cfg.Start)
+	if err != nil {
+		return "quiet_hours_start must be a time in HH:MM format"
+	}
+	end, err := time.Parse("15:04", cfg.End)
+	if err != nil {
+		return "quiet_hours_end must be a time in HH:MM format"
+	}
+	if start.Equal(end) {
+		return "quiet_hours_start and quiet_hours_end must differ"
+	}
+	return ""
+}
+
+// validateBusinessHours checks business hours use the same day and time
+// rules as user schedules
+func validateBusinessHours(hours []map[string]interface{}) string {
+	raw, err := json.Marshal(hours)
+	if err != nil {
+		return "Invalid business_hours"
+	}
+	var parsed []ScheduleHours
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return "Invalid business_hours"
+	}
+	if errMsg := validateScheduleHours(parsed); errMsg != "" {
+		return "business_hours: " + errMsg
+	}
+	return ""
+}
+
+// validateAssignmentFallback checks that the configured fallback points at a user
+// or team of the organization. It returns an error message, or "" when valid.
+func (a *App) validateAssignmentFallback(orgID uuid.UUID, cfg models.AgentAssignmentConfig) string {
+	switch cfg.FallbackType {
+	case "", models.AssignmentFallbackQueue:
+		return ""
+	case models.AssignmentFallbackUser:
+		if cfg.FallbackUserID == nil {
+			return "assignment_fallback_user_id is required for user fallback"
+		}
+		var count int64
+		a.DB.Model(&models.UserOrganization{}).
+			Where("user_id = ? AND organization_id = ?", *cfg.FallbackUserID, orgID).
+			Count(&count)
+		if count == 0 {
+			return "Fallback user not found"
+		}
+		return ""
+	case models.AssignmentFallbackTeam:
+		if cfg.FallbackTeamID == nil {
+			return "assignment_fallback_team_id is required for team fallback"
+		}
+		var count int64
+		a.DB.Model(&models.Team{}).
+			Where("id = ? AND organization_id = ? AND is_active = ?", *cfg.FallbackTeamID, orgID, true).
+			Count(&count)
+		if count == 0 {
+			return "Fallback team not found or inactive"
+		}
+		return ""
+	default:
+		return "assignment_fallback_type must be one of: user, team, queue"
+	}
+}
+
+// maxAITimeoutSeconds caps the configurable AI provider timeout
+const maxAITimeoutSeconds = 120
+
+// validateAIProviderModel checks the requested AI provider and model against the
+// current config. Model names aren't checked against a list, since providers
+// release new ones all the time. Returns an error message, or "" if the
+// combination is valid.
+func validateAIProviderModel(provider *models.AIProvider, model *string, current models.AIConfig) string {
+	effectiveProvider := current.Provider
+	if provider != nil {
+		effectiveProvider = *provider
+		switch effectiveProvider {
+		case "", models.AIProviderOpenAI, models.AIProviderAnthropic, models.AIProviderGoogle:
+		default:
+			return "Unsupported AI provider: " + string(effectiveProvider)
+		}
+	}
+	if model != nil && effectiveProvider != "" && strings.TrimSpace(*model) == "" {
+		return "ai_model is required for AI provider " + string(effectiveProvider)
+	}
+	return ""
+}
+
+// RotateAIKey replaces the organization's AI provider API key
+func (a *App) RotateAIKey(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	if err := a.requirePermission(r, userID, models.ResourceSettingsChatbot, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	var req struct {
+		APIKey string `json:"api_key"`
+	}
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	req.APIKey = strings.TrimSpace(req.APIKey)
+	if req.APIKey == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "api_key is required", nil, "")
+	}
+
+	var settings models.ChatbotSettings
+	if err := a.DB.Where("organization_id = ? AND whats_app_account = ?", orgID, "").First(&settings).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Chatbot settings not found", nil, "")
+	}
+
+	if err := a.setAIAPIKey(&settings, req.APIKey); err != nil {
+		a.Log.Error("Failed to encrypt AI API key", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to rotate API key", nil, "")
+	}
+	if err := a.DB.Model(&settings).Updates(map[string]any{
+		"ai_api_key":            settings.AI.APIKey,
+		"ai_api_key_updated_at": settings.AI.APIKeyUpdatedAt,
+	}).Error; err != nil {
+		a.Log.Error("Failed to rotate AI API key", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to rotate API key", nil, "")
+	}
+
+	a.InvalidateChatbotSettingsCache(orgID)
+
+	return r.SendEnvelope(map[string]any{
+		"ai_has_key":            true,
+		"ai_api_key_masked":     maskSecret(req.APIKey),
+		"ai_api_key_updated_at": settings.AI.APIKeyUpdatedAt,
+	})
+}
+
+// setAIAPIKey encrypts and stores a new AI API key on the settings
+func (a *App) setAIAPIKey(settings *models.ChatbotSettings, apiKey string) error {
+	enc, err := crypto.Encrypt(apiKey, a.Config.App.EncryptionKey)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	settings.AI.APIKey = enc
+	settings.AI.APIKeyUpdatedAt = &now
+	return nil
+}
+
+// maskSecret hides all but the last four characters of a secret
+func maskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) <= 8 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
+
 // ListKeywordRules lists all keyword rules for the organization
 func (a *App) ListKeywordRules(r *fastglue.Request) error {
 	orgID, err := a.getOrgID(r)
@@ -417,21 +882,34 @@ func (a *App) ListKeywordRules(r *fastglue.Request) error {
 
 	pg := parsePagination(r)
 	search := string(r.RequestCtx.QueryArgs().Peek("search"))
+	enabled := string(r.RequestCtx.QueryArgs().Peek("enabled"))
+	responseType := string(r.RequestCtx.QueryArgs().Peek("response_type"))
 
 	query := a.DB.Model(&models.KeywordRule{}).Where("organization_id = ?", orgID)
 
-	// Apply search filter - search by name or keywords
+	if enabled != "" {
+		isEnabled, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "enabled must be true or false", nil, "")
+		}
+		query = query.Where("is_enabled = ?", isEnabled)
+	}
+	if responseType != "" {
+		query = query.Where("response_type = ?", responseType)
+	}
+
+	// Apply search filter - search by name or any individual keyword
 	if search != "" {
 		searchPattern := "%" + search + "%"
-		// Search in name (case-insensitive) or in keywords JSONB array
-		query = query.Where("name ILIKE ? OR keywords::text ILIKE ?", searchPattern, searchPattern)
+		query = query.Where("name ILIKE ? OR EXISTS (SELECT 1 FROM jsonb_array_elements_text(keywords) AS kw WHERE kw ILIKE ?)",
+			searchPattern, searchPattern)
 	}
 
 	var total int64
 	query.Count(&total)
 
 	var rules []models.KeywordRule
-	if err := pg.Apply(query.Order("priority DESC, created_at DESC")).
+	if err := pg.Apply(query.Order("priority DESC, name ASC")).
 		Find(&rules).Error; err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to fetch keyword rules", nil, "")
 	}
@@ -444,10 +922,13 @@ func (a *App) ListKeywordRules(r *fastglue.Request) error {
 			Name:            rule.Name,
 			Keywords:        rule.Keywords,
 			MatchType:       rule.MatchType,
+			CaseSensitive:   rule.CaseSensitive,
+			WordBoundary:    rule.WordBoundary,
 			ResponseType:    rule.ResponseType,
 			ResponseContent: responseContent,
 			Priority:        rule.Priority,
 			Enabled:         rule.IsEnabled,
+			HitCount:        rule.HitCount,
 			CreatedAt:       rule.CreatedAt.Format(time.RFC3339),
 		}
 	}
@@ -471,6 +952,8 @@ func (a *App) CreateKeywordRule(r *fastglue.Request) error {
 		Name            string                 `json:"name"`
 		Keywords        []string               `json:"keywords"`
 		MatchType       models.MatchType       `json:"match_type"`
+		CaseSensitive   bool                   `json:"case_sensitive"`
+		WordBoundary    bool                   `json:"word_boundary"`
 		ResponseType    models.ResponseType    `json:"response_type"`
 		ResponseContent map[string]interface{} `json:"response_content"`
 		Priority        int                    `json:"priority"`
@@ -495,6 +978,9 @@ func (a *App) CreateKeywordRule(r *fastglue.Request) error {
 	if req.Name == "" {
 		req.Name = req.Keywords[0]
 	}
+	if msg := validateKeywordResponseContent(req.ResponseType, req.ResponseContent); msg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, msg, nil, "")
+	}
 
 	rule := models.KeywordRule{
 		BaseModel:       models.BaseModel{ID: uuid.New()},
@@ -502,6 +988,8 @@ func (a *App) CreateKeywordRule(r *fastglue.Request) error {
 		Name:            req.Name,
 		Keywords:        req.Keywords,
 		MatchType:       req.MatchType,
+		CaseSensitive:   req.CaseSensitive,
+		WordBoundary:    req.WordBoundary,
 		ResponseType:    req.ResponseType,
 		ResponseContent: models.JSONB(req.ResponseContent),
 		Priority:        req.Priority,
@@ -544,10 +1032,13 @@ func (a *App) GetKeywordRule(r *fastglue.Request) error {
 		Name:            rule.Name,
 		Keywords:        rule.Keywords,
 		MatchType:       rule.MatchType,
+		CaseSensitive:   rule.CaseSensitive,
+		WordBoundary:    rule.WordBoundary,
 		ResponseType:    rule.ResponseType,
 		ResponseContent: responseContent,
 		Priority:        rule.Priority,
 		Enabled:         rule.IsEnabled,
+		HitCount:        rule.HitCount,
 		CreatedAt:       rule.CreatedAt.Format(time.RFC3339),
 	}
 
@@ -575,6 +1066,8 @@ func (a *App) UpdateKeywordRule(r *fastglue.Request) error {
 		Name            *string                 `json:"name"`
 		Keywords        []string                `json:"keywords"`
 		MatchType       *models.MatchType       `json:"match_type"`
+		CaseSensitive   *bool                   `json:"case_sensitive"`
+		WordBoundary    *bool                   `json:"word_boundary"`
 		ResponseType    *models.ResponseType    `json:"response_type"`
 		ResponseContent map[string]interface{}  `json:"response_content"`
 		Priority        *int                    `json:"priority"`
@@ -595,6 +1088,12 @@ func (a *App) UpdateKeywordRule(r *fastglue.Request) error {
 	if req.MatchType != nil {
 		rule.MatchType = *req.MatchType
 	}
+	if req.CaseSensitive != nil {
+		rule.CaseSensitive = *req.CaseSensitive
+	}
+	if req.WordBoundary != nil {
+		rule.WordBoundary = *req.WordBoundary
+	}
 	if req.ResponseType != nil {
 		rule.ResponseType = *req.ResponseType
 	}
@@ -607,6 +1106,9 @@ func (a *App) UpdateKeywordRule(r *fastglue.Request) error {
 	if req.Enabled != nil {
 		rule.IsEnabled = *req.Enabled
 	}
+	if msg := validateKeywordResponseContent(rule.ResponseType, rule.ResponseContent); msg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, msg, nil, "")
+	}
 
 	if err := a.DB.Save(rule).Error; err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update keyword rule", nil, "")
@@ -620,6 +1122,31 @@ func (a *App) UpdateKeywordRule(r *fastglue.Request) error {
 	})
 }
 
+// validateKeywordResponseContent checks type-specific response content and
+// returns an error message, or "" when valid.
+func validateKeywordResponseContent(responseType models.ResponseType, content map[string]interface{}) string {
+	if msg := validateKeywordVariants(responseType, content); msg != "" {
+		return msg
+	}
+	if responseType != models.ResponseTypeTemplate {
+		return ""
+	}
+	// Rules without a template_id send their body as text
+	idStr, _ := content["template_id"].(string)
+	if body, _ := content["body"].(string); idStr == "" && body != "" {
+		return ""
+	}
+	if _, err := uuid.Parse(idStr); err != nil {
+		return "Template responses require a valid template_id"
+	}
+	if v, ok := content["fallback_text"]; ok {
+		if _, isString := v.(string); !isString {
+			return "fallback_text must be a string"
+		}
+	}
+	return ""
+}
+
 // DeleteKeywordRule deletes a keyword rule
 func (a *App) DeleteKeywordRule(r *fastglue.Request) error {
 	orgID, err := a.getOrgID(r)
@@ -648,6 +1175,105 @@ func (a *App) DeleteKeywordRule(r *fastglue.Request) error {
 	})
 }
 
+// KeywordRuleStats represents per-rule match counts for a date range
+type KeywordRuleStats struct {
+	ID        string                    `json:"id"`
+	Name      string                    `json:"name"`
+	Enabled   bool                      `json:"enabled"`
+	Hits      int64                     `json:"hits"`
+	LastHitAt *time.Time                `json:"last_hit_at,omitempty"`
+	Variants  []KeywordRuleVariantStats `json:"variants,omitempty"`
+}
+
+// KeywordRuleVariantStats is how often one response variant of a rule was sent
+type KeywordRuleVariantStats struct {
+	Variant int     `json:"variant"`
+	Hits    int64   `json:"hits"`
+	Share   float64 `json:"share"` // Fraction of the rule's hits in the range
+}
+
+// GetKeywordRuleStats returns match counts per keyword rule for a date range,
+// split by response variant for rules that have them.
+// Rules without any hits in the range are included with zero so they can be pruned.
+func (a *App) GetKeywordRuleStats(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	if err := a.requirePermission(r, userID, models.ResourceChatbotKeywords, models.ActionRead); err != nil {
+		return nil
+	}
+
+	periodStart, periodEnd, errMsg := parseReportPeriod(r)
+	if errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+
+	var rows []struct {
+		ID        uuid.UUID
+		Name      string
+		IsEnabled bool
+		Hits      int64
+		LastHitAt *time.Time
+	}
+	if err := a.DB.Model(&models.KeywordRule{}).
+		Select("keyword_rules.id, keyword_rules.name, keyword_rules.is_enabled, COUNT(h.id) AS hits, MAX(h.created_at) AS last_hit_at").
+		Joins("LEFT JOIN keyword_rule_hits h ON h.keyword_rule_id = keyword_rules.id AND h.organization_id = keyword_rules.organization_id AND h.deleted_at IS NULL AND h.created_at >= ? AND h.created_at <= ?", periodStart, periodEnd).
+		Where("keyword_rules.organization_id = ?", orgID).
+		Group("keyword_rules.id, keyword_rules.name, keyword_rules.is_enabled").
+		Order("hits DESC, keyword_rules.name ASC").
+		Scan(&rows).Error; err != nil {
+		a.Log.Error("Failed to aggregate keyword rule hits", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load keyword rule stats", nil, "")
+	}
+
+	var variantRows []struct {
+		KeywordRuleID uuid.UUID
+		Variant       int
+		Hits          int64
+	}
+	if err := a.DB.Model(&models.KeywordRuleHit{}).
+		Select("keyword_rule_id, variant, COUNT(*) AS hits").
+		Where("organization_id = ? AND variant IS NOT NULL AND created_at >= ? AND created_at <= ?", orgID, periodStart, periodEnd).
+		Group("keyword_rule_id, variant").
+		Order("variant ASC").
+		Scan(&variantRows).Error; err != nil {
+		a.Log.Error("Failed to aggregate keyword rule variant hits", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load keyword rule stats", nil, "")
+	}
+	variants := make(map[uuid.UUID][]KeywordRuleVariantStats)
+	for _, row := range variantRows {
+		variants[row.KeywordRuleID] = append(variants[row.KeywordRuleID], KeywordRuleVariantStats{
+			Variant: row.Variant,
+			Hits:    row.Hits,
+		})
+	}
+
+	var totalHits int64
+	stats := make([]KeywordRuleStats, len(rows))
+	for i, row := range rows {
+		stats[i] = KeywordRuleStats{
+			ID:        row.ID.String(),
+			Name:      row.Name,
+			Enabled:   row.IsEnabled,
+			Hits:      row.Hits,
+			LastHitAt: row.LastHitAt,
+			Variants:  variants[row.ID],
+		}
+		for j := range stats[i].Variants {
+			stats[i].Variants[j].Share = float64(stats[i].Variants[j].Hits) / float64(row.Hits)
+		}
+		totalHits += row.Hits
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"rules":      stats,
+		"total_hits": totalHits,
+		"from":       periodStart.Format(time.RFC3339),
+		"to":         periodEnd.Format(time.RFC3339),
+	})
+}
+
 // ListChatbotFlows lists all chatbot flows
 func (a *App) ListChatbotFlows(r *fastglue.Request) error {
 	orgID, userID, err := a.getOrgAndUserID(r)
@@ -685,9 +1311,13 @@ func (a *App) ListChatbotFlows(r *fastglue.Request) error {
 			ID:              flow.ID.String(),
 			Name:            flow.Name,
 			Description:     flow.Description,
+			TriggerType:     string(flow.TriggerType),
 			TriggerKeywords: flow.TriggerKeywords,
+			TriggerButtonID: flow.TriggerButtonID,
 			Enabled:         flow.IsEnabled,
 			StepsCount:      len(flow.Steps),
+			ActiveFrom:      flow.ActiveFrom,
+			ActiveUntil:     flow.ActiveUntil,
 			CreatedAt:       flow.CreatedAt.Format(time.RFC3339),
 		}
 	}
@@ -714,11 +1344,13 @@ type FlowStepRequest struct {
 	ValidationRegex string                   `json:"validation_regex"`
 	ValidationError string                   `json:"validation_error"`
 	StoreAs         string                   `json:"store_as"`
+	Sensitive       bool                     `json:"sensitive"`
 	NextStep        string                   `json:"next_step"`
 	ConditionalNext map[string]interface{}   `json:"conditional_next"`
 	SkipCondition   string                   `json:"skip_condition"`
 	RetryOnInvalid  bool                     `json:"retry_on_invalid"`
 	MaxRetries      int                      `json:"max_retries"`
+	StepTimeoutMins int                      `json:"step_timeout_minutes"`
 }
 
 // CreateChatbotFlow creates a new chatbot flow
@@ -735,14 +1367,20 @@ func (a *App) CreateChatbotFlow(r *fastglue.Request) error {
 	var req struct {
 		Name              string                 `json:"name"`
 		Description       string                 `json:"description"`
+		TriggerType       models.FlowTriggerType `json:"trigger_type"`
 		TriggerKeywords   []string               `json:"trigger_keywords"`
+		TriggerButtonID   string                 `json:"trigger_button_id"`
 		InitialMessage    string                 `json:"initial_message"`
-		CompletionMessage string                 `json:"completion_message"`
-		OnCompleteAction  string                 `json:"on_complete_action"`
-		CompletionConfig  map[string]interface{} `json:"completion_config"`
-		PanelConfig       map[string]interface{} `json:"panel_config"`
-		Enabled           bool                   `json:"enabled"`
-		Steps             []FlowStepRequest      `json:"steps"`
+		CompletionMessage  string                   `json:"completion_message"`
+		OnCompleteAction   string                   `json:"on_complete_action"`
+		CompletionConfig   map[string]interface{}   `json:"completion_config"`
+		PanelConfig        map[string]interface{}   `json:"panel_config"`
+		TimeoutMessage     string                   `json:"timeout_message"`
+		StepTimeoutMinutes int                      `json:"step_timeout_minutes"`
+		StepTimeoutAction  models.StepTimeoutAction `json:"step_timeout_action"`
+		Enabled            bool                     `json:"enabled"`
+		Steps              []FlowStepRequest        `json:"steps"`
+		FlowActiveWindowRequest
 	}
 
 	if err := json.Unmarshal(r.RequestCtx.PostBody(), &req); err != nil {
@@ -752,6 +1390,28 @@ func (a *App) CreateChatbotFlow(r *fastglue.Request) error {
 	if req.Name == "" {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Name is required", nil, "")
 	}
+	if req.TriggerType == "" {
+		req.TriggerType = models.FlowTriggerKeyword
+	}
+	if req.StepTimeoutAction == "" {
+		req.StepTimeoutAction = models.StepTimeoutNudge
+	}
+	if errMsg := validateFlowTrigger(req.TriggerType, req.TriggerButtonID); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	activeFrom, activeUntil, errMsg := req.FlowActiveWindowRequest.resolve(nil, nil)
+	if errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if errMsg := validateFlowStepTimeouts(req.StepTimeoutMinutes, req.StepTimeoutAction, req.Steps); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if errMsg := validateFlowStepLinks(req.Steps); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if errMsg := validateFlowStepButtons(req.Steps); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
 
 	// Use transaction for flow + steps
 	tx := a.DB.Begin()
@@ -762,13 +1422,20 @@ func (a *App) CreateChatbotFlow(r *fastglue.Request) error {
 		OrganizationID:    orgID,
 		Name:              req.Name,
 		Description:       req.Description,
+		TriggerType:       req.TriggerType,
 		TriggerKeywords:   req.TriggerKeywords,
+		TriggerButtonID:   req.TriggerButtonID,
 		InitialMessage:    req.InitialMessage,
 		CompletionMessage: req.CompletionMessage,
 		OnCompleteAction:  req.OnCompleteAction,
 		CompletionConfig:  models.JSONB(req.CompletionConfig),
 		PanelConfig:       models.JSONB(req.PanelConfig),
+		TimeoutMessage:    req.TimeoutMessage,
+		StepTimeoutMins:   req.StepTimeoutMinutes,
+		StepTimeoutAction: req.StepTimeoutAction,
 		IsEnabled:         req.Enabled,
+		ActiveFrom:        activeFrom,
+		ActiveUntil:       activeUntil,
 	}
 
 	if err := tx.Create(&flow).Error; err != nil {
@@ -799,11 +1466,13 @@ func (a *App) CreateChatbotFlow(r *fastglue.Request) error {
 			ValidationRegex: stepReq.ValidationRegex,
 			ValidationError: stepReq.ValidationError,
 			StoreAs:         stepReq.StoreAs,
+			Sensitive:       stepReq.Sensitive,
 			NextStep:        stepReq.NextStep,
 			ConditionalNext: models.JSONB(stepReq.ConditionalNext),
 			SkipCondition:   stepReq.SkipCondition,
 			RetryOnInvalid:  stepReq.RetryOnInvalid,
 			MaxRetries:      stepReq.MaxRetries,
+			StepTimeoutMins: stepReq.StepTimeoutMins,
 		}
 		if step.MessageType == "" {
 			step.MessageType = models.FlowStepTypeText
@@ -878,22 +1547,58 @@ func (a *App) UpdateChatbotFlow(r *fastglue.Request) error {
 	}
 
 	var req struct {
-		Name              *string                `json:"name"`
-		Description       *string                `json:"description"`
-		TriggerKeywords   []string               `json:"trigger_keywords"`
-		InitialMessage    *string                `json:"initial_message"`
-		CompletionMessage *string                `json:"completion_message"`
-		OnCompleteAction  *string                `json:"on_complete_action"`
-		CompletionConfig  map[string]interface{} `json:"completion_config"`
-		PanelConfig       map[string]interface{} `json:"panel_config"`
-		Enabled           *bool                  `json:"enabled"`
-		Steps             []FlowStepRequest      `json:"steps"`
+		Name              *string                 `json:"name"`
+		Description       *string                 `json:"description"`
+		TriggerType       *models.FlowTriggerType `json:"trigger_type"`
+		TriggerKeywords   []string                `json:"trigger_keywords"`
+		TriggerButtonID   *string                 `json:"trigger_button_id"`
+		InitialMessage    *string                 `json:"initial_message"`
+		CompletionMessage  *string                   `json:"completion_message"`
+		OnCompleteAction   *string                   `json:"on_complete_action"`
+		CompletionConfig   map[string]interface{}    `json:"completion_config"`
+		PanelConfig        map[string]interface{}    `json:"panel_config"`
+		TimeoutMessage     *string                   `json:"timeout_message"`
+		StepTimeoutMinutes *int                      `json:"step_timeout_minutes"`
+		StepTimeoutAction  *models.StepTimeoutAction `json:"step_timeout_action"`
+		Enabled            *bool                     `json:"enabled"`
+		Steps              []FlowStepRequest         `json:"steps"`
+		FlowActiveWindowRequest
 	}
 
 	if err := json.Unmarshal(r.RequestCtx.PostBody(), &req); err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid request body", nil, "")
 	}
 
+	if req.TriggerType != nil {
+		flow.TriggerType = *req.TriggerType
+	}
+	if req.TriggerButtonID != nil {
+		flow.TriggerButtonID = *req.TriggerButtonID
+	}
+	if errMsg := validateFlowTrigger(flow.TriggerType, flow.TriggerButtonID); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if req.StepTimeoutMinutes != nil {
+		flow.StepTimeoutMins = *req.StepTimeoutMinutes
+	}
+	if req.StepTimeoutAction != nil {
+		flow.StepTimeoutAction = *req.StepTimeoutAction
+	}
+	var errMsg string
+	flow.ActiveFrom, flow.ActiveUntil, errMsg = req.FlowActiveWindowRequest.resolve(flow.ActiveFrom, flow.ActiveUntil)
+	if errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if errMsg := validateFlowStepTimeouts(flow.StepTimeoutMins, flow.StepTimeoutAction, req.Steps); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if errMsg := validateFlowStepLinks(req.Steps); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if errMsg := validateFlowStepButtons(req.Steps); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+
 	tx := a.DB.Begin()
 
 	if req.Name != nil {
@@ -920,6 +1625,9 @@ func (a *App) UpdateChatbotFlow(r *fastglue.Request) error {
 	if req.PanelConfig != nil {
 		flow.PanelConfig = models.JSONB(req.PanelConfig)
 	}
+	if req.TimeoutMessage != nil {
+		flow.TimeoutMessage = *req.TimeoutMessage
+	}
 	if req.Enabled != nil {
 		flow.IsEnabled = *req.Enabled
 	}
@@ -960,11 +1668,13 @@ func (a *App) UpdateChatbotFlow(r *fastglue.Request) error {
 				ValidationRegex: stepReq.ValidationRegex,
 				ValidationError: stepReq.ValidationError,
 				StoreAs:         stepReq.StoreAs,
+				Sensitive:       stepReq.Sensitive,
 				NextStep:        stepReq.NextStep,
 				ConditionalNext: models.JSONB(stepReq.ConditionalNext),
 				SkipCondition:   stepReq.SkipCondition,
 				RetryOnInvalid:  stepReq.RetryOnInvalid,
 				MaxRetries:      stepReq.MaxRetries,
+				StepTimeoutMins: stepReq.StepTimeoutMins,
 			}
 			if step.MessageType == "" {
 				step.MessageType = models.FlowStepTypeText
@@ -989,90 +1699,783 @@ func (a *App) UpdateChatbotFlow(r *fastglue.Request) error {
 	})
 }
 
-// DeleteChatbotFlow deletes a chatbot flow
-func (a *App) DeleteChatbotFlow(r *fastglue.Request) error {
+// StepAnswerCount is the number of sessions that gave a particular answer to a flow step
+type StepAnswerCount struct {
+	Answer string `json:"answer"`
+	Label  string `json:"label,omitempty"` // Button title when the answer is a button ID
+	Count  int64  `json:"count"`
+}
+
+// GetStepAnswerDistribution returns how many sessions gave each distinct answer
+// to a flow step over a date range, read from the step's store_as session variable
+func (a *App) GetStepAnswerDistribution(r *fastglue.Request) error {
 	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
 
-	if !a.HasPermission(userID, models.ResourceFlowsChatbot, models.ActionDelete, orgID) {
+	if !a.HasPermission(userID, models.ResourceFlowsChatbot, models.ActionRead, orgID) {
 		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Permission denied", nil, "")
 	}
 
-	id, err := parsePathUUID(r, "id", "flow")
+	flowID, err := parsePathUUID(r, "id", "flow")
+	if err != nil {
+		return nil
+	}
+	stepID, err := parsePathUUID(r, "step_id", "step")
 	if err != nil {
 		return nil
 	}
 
-	// Delete flow and steps in transaction
-	tx := a.DB.Begin()
-
-	// Delete steps first
-	if err := tx.Where("flow_id = ?", id).Delete(&models.ChatbotFlowStep{}).Error; err != nil {
-		tx.Rollback()
-		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete flow steps", nil, "")
+	if _, err := findByIDAndOrg[models.ChatbotFlow](a.DB, r, flowID, orgID, "Flow"); err != nil {
+		return nil
 	}
 
-	// Delete flow
-	result := tx.Where("id = ? AND organization_id = ?", id, orgID).Delete(&models.ChatbotFlow{})
-	if result.Error != nil {
-		tx.Rollback()
-		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete flow", nil, "")
+	var step models.ChatbotFlowStep
+	if err := a.DB.Where("id = ? AND flow_id = ?", stepID, flowID).First(&step).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Step not found", nil, "")
 	}
-	if result.RowsAffected == 0 {
-		tx.Rollback()
-		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Flow not found", nil, "")
+	if step.StoreAs == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Step does not store an answer", nil, "")
 	}
 
-	tx.Commit()
+	periodStart, periodEnd, errMsg := parseReportPeriod(r)
+	if errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
 
-	// Invalidate cache
-	a.InvalidateChatbotFlowsCache(orgID)
+	answers := []StepAnswerCount{}
+	if err := a.DB.Model(&models.ChatbotSession{}).
+		Select("session_data->>? AS answer, COALESCE(MAX(session_data->>?), '') AS label, COUNT(*) AS count", step.StoreAs, step.StoreAs+"_title").
+		Where("organization_id = ? AND current_flow_id = ? AND created_at >= ? AND created_at <= ?", orgID, flowID, periodStart, periodEnd).
+		Where("COALESCE(session_data->>?, '') <> ''", step.StoreAs).
+		Group("answer").
+		Order("count DESC, answer ASC").
+		Scan(&answers).Error; err != nil {
+		a.Log.Error("Failed to aggregate step answers", "error", err, "flow_id", flowID, "step_id", stepID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load answer distribution", nil, "")
+	}
 
-	return r.SendEnvelope(map[string]interface{}{
-		"message": "Flow deleted successfully",
+	var total int64
+	for _, answer := range answers {
+		total += answer.Count
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"flow_id":   flowID.String(),
+		"step_id":   stepID.String(),
+		"step_name": step.StepName,
+		"store_as":  step.StoreAs,
+		"answers":   answers,
+		"total":     total,
+		"from":      periodStart.Format(time.RFC3339),
+		"to":        periodEnd.Format(time.RFC3339),
 	})
 }
 
-// ListAIContexts lists all AI contexts
-func (a *App) ListAIContexts(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
-	if err != nil {
-		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+// validateFlowTrigger checks the trigger type and that button triggers name a button
+func validateFlowTrigger(triggerType models.FlowTriggerType, buttonID string) string {
+	switch triggerType {
+	case "", models.FlowTriggerKeyword, models.FlowTriggerFirstInbound, models.FlowTriggerAfterHours:
+		return ""
+	case models.FlowTriggerButton:
+		if strings.TrimSpace(buttonID) == "" {
+			return "trigger_button_id is required for button triggers"
+		}
+		return ""
+	default:
+		return "trigger_type must be one of: keyword, first_inbound, after_hours, button"
 	}
+}
 
-	pg := parsePagination(r)
-	search := string(r.RequestCtx.QueryArgs().Peek("search"))
+// FlowActiveWindowRequest sets when a chatbot flow can be triggered. Times are
+// RFC3339; an empty string clears the bound and an omitted one is left as is.
+type FlowActiveWindowRequest struct {
+	ActiveFrom  *string `json:"active_from"`
+	ActiveUntil *string `json:"active_until"`
+}
 
-	query := a.DB.Model(&models.AIContext{}).Where("organization_id = ?", orgID)
+// resolve applies the request to the current window and validates the result,
+// returning an error message when it is invalid
+func (w FlowActiveWindowRequest) resolve(from, until *time.Time) (*time.Time, *time.Time, string) {
+	var err error
+	if w.ActiveFrom != nil {
+		if from, err = parseOptionalTime(*w.ActiveFrom); err != nil {
+			return nil, nil, "Invalid active_from, use RFC3339"
+		}
+	}
+	if w.ActiveUntil != nil {
+		if until, err = parseOptionalTime(*w.ActiveUntil); err != nil {
+			return nil, nil, "Invalid active_until, use RFC3339"
+		}
+	}
+	return from, until, validateFlowActiveWindow(from, until)
+}
 
-	// Apply search filter - search by name, static content, or trigger keywords
-	if search != "" {
-		searchPattern := "%" + search + "%"
-		query = query.Where("name ILIKE ? OR static_content ILIKE ? OR trigger_keywords::text ILIKE ?", searchPattern, searchPattern, searchPattern)
+// validateFlowActiveWindow checks that a flow's active window, when fully set, is not empty
+func validateFlowActiveWindow(from, until *time.Time) string {
+	if from != nil && until != nil && !until.After(*from) {
+		return "active_until must be after active_from"
 	}
+	return ""
+}
 
-	var total int64
-	query.Count(&total)
+// maxStepTimeoutMins caps step timeouts at a day
+const maxStepTimeoutMins = 1440
 
-	var contexts []models.AIContext
-	if err := pg.Apply(query.Order("priority DESC, created_at DESC")).
-		Find(&contexts).Error; err != nil {
-		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to fetch AI contexts", nil, "")
+// validateFlowStepTimeouts checks the flow's step timeout and action and the
+// steps' own timeouts
+func validateFlowStepTimeouts(minutes int, action models.StepTimeoutAction, steps []FlowStepRequest) string {
+	switch action {
+	case "", models.StepTimeoutNudge, models.StepTimeoutAdvance, models.StepTimeoutAbandon:
+	default:
+		return "step_timeout_action must be one of: nudge, advance, abandon"
 	}
-
-	response := make([]AIContextResponse, len(contexts))
-	for i, ctx := range contexts {
-		response[i] = AIContextResponse{
-			ID:              ctx.ID.String(),
-			Name:            ctx.Name,
-			ContextType:     ctx.ContextType,
-			TriggerKeywords: ctx.TriggerKeywords,
-			StaticContent:   ctx.StaticContent,
-			Enabled:         ctx.IsEnabled,
-			Priority:        ctx.Priority,
-			CreatedAt:       ctx.CreatedAt.Format(time.RFC3339),
+	if minutes < 0 || minutes > maxStepTimeoutMins {
+		return fmt.Sprintf("step_timeout_minutes must be between 0 and %d", maxStepTimeoutMins)
+	}
+	for _, step := range steps {
+		if step.StepTimeoutMins < 0 || step.StepTimeoutMins > maxStepTimeoutMins {
+			return fmt.Sprintf("Step %q step_timeout_minutes must be between 0 and %d", step.StepName, maxStepTimeoutMins)
+		}
+	}
+	return ""
+}
+
+// validateFlowStepLinks checks that every step's next_step names a step in the
+// same flow and that steps don't loop forever. A step continues to next_step,
+// or to the following step when next_step is empty; a loop is only rejected
+// when none of its steps has conditional_next or button next_step to branch
+// out of it.
+func validateFlowStepLinks(steps []FlowStepRequest) string {
+	index := make(map[string]int, len(steps))
+	for i, step := range steps {
+		if _, exists := index[step.StepName]; !exists {
+			index[step.StepName] = i
+		}
+	}
+
+	for _, step := range steps {
+		if step.NextStep == "" {
+			continue
+		}
+		if _, ok := index[step.NextStep]; !ok {
+			return fmt.Sprintf("Step %q has next_step %q which does not exist", step.StepName, step.NextStep)
+		}
+	}
+
+	// successor returns the step that unconditionally follows step i, or -1
+	// when the flow completes or a conditional branch decides
+	successor := func(i int) int {
+		if len(steps[i].ConditionalNext) > 0 || stepHasButtonRoutes(steps[i]) {
+			return -1
+		}
+		if steps[i].NextStep != "" {
+			return index[steps[i].NextStep]
+		}
+		if i+1 < len(steps) {
+			return i + 1
+		}
+		return -1
+	}
+
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make([]int, len(steps))
+	for start := range steps {
+		var path []int
+		i := start
+		for i >= 0 && state[i] == unvisited {
+			state[i] = onPath
+			path = append(path, i)
+			i = successor(i)
+		}
+		if i >= 0 && state[i] == onPath {
+			var loop []string
+			for k := slices.Index(path, i); k < len(path); k++ {
+				loop = append(loop, steps[path[k]].StepName)
+			}
+			loop = append(loop, steps[i].StepName)
+			return fmt.Sprintf("Steps form a loop with no exit: %s", strings.Join(loop, " -> "))
+		}
+		for _, p := range path {
+			state[p] = done
+		}
+	}
+	return ""
+}
+
+// validateFlowStepButtons checks that button IDs are unique within each step
+// and that a button's next_step names a step in the same flow. Buttons
+// without an ID get btn_1, btn_2, ... as when they're sent.
+func validateFlowStepButtons(steps []FlowStepRequest) string {
+	names := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		names[step.StepName] = true
+	}
+
+	for _, step := range steps {
+		seen := make(map[string]bool, len(step.Buttons))
+		for i, btn := range step.Buttons {
+			btnID, _ := btn["id"].(string)
+			if btnID == "" {
+				btnID = fmt.Sprintf("btn_%d", i+1)
+			}
+			if seen[btnID] {
+				return fmt.Sprintf("Step %q has more than one button with id %q", step.StepName, btnID)
+			}
+			seen[btnID] = true
+
+			if next, _ := btn["next_step"].(string); next != "" && !names[next] {
+				return fmt.Sprintf("Step %q button %q has next_step %q which does not exist", step.StepName, btnID, next)
+			}
+		}
+	}
+	return ""
+}
+
+// flowStepRoutingRequest builds a FlowStepRequest with just the fields
+// validateFlowStepLinks and validateFlowStepButtons look at, so stored and
+// imported steps can be checked the same way as submitted ones
+func flowStepRoutingRequest(stepName, nextStep string, conditionalNext models.JSONB, buttons models.JSONBArray) FlowStepRequest {
+	req := FlowStepRequest{
+		StepName:        stepName,
+		NextStep:        nextStep,
+		ConditionalNext: conditionalNext,
+	}
+	for _, btn := range buttons {
+		if m, ok := btn.(map[string]interface{}); ok {
+			req.Buttons = append(req.Buttons, m)
+		}
+	}
+	return req
+}
+
+// stepHasButtonRoutes reports whether any of a step's buttons sets its own next_step
+func stepHasButtonRoutes(step FlowStepRequest) bool {
+	for _, btn := range step.Buttons {
+		if next, _ := btn["next_step"].(string); next != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOptionalTime parses an RFC3339 timestamp, treating an empty string as unset
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// UpdateChatbotFlowStep patches a single step of a chatbot flow, leaving other steps untouched
+func (a *App) UpdateChatbotFlowStep(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceFlowsChatbot, models.ActionWrite, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Permission denied", nil, "")
+	}
+
+	flowID, err := parsePathUUID(r, "id", "flow")
+	if err != nil {
+		return nil
+	}
+	stepID, err := parsePathUUID(r, "step_id", "step")
+	if err != nil {
+		return nil
+	}
+
+	if _, err := findByIDAndOrg[models.ChatbotFlow](a.DB, r, flowID, orgID, "Flow"); err != nil {
+		return nil
+	}
+
+	var step models.ChatbotFlowStep
+	if err := a.DB.Where("id = ? AND flow_id = ?", stepID, flowID).First(&step).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Step not found", nil, "")
+	}
+
+	var req struct {
+		Message         *string           `json:"message"`
+		InputType       *models.InputType `json:"input_type"`
+		StoreAs         *string           `json:"store_as"`
+		Sensitive       *bool             `json:"sensitive"`
+		ValidationRegex *string           `json:"validation_regex"`
+		ValidationError *string           `json:"validation_error"`
+		NextStep        *string           `json:"next_step"`
+		StepTimeoutMins *int              `json:"step_timeout_minutes"`
+	}
+
+	if err := json.Unmarshal(r.RequestCtx.PostBody(), &req); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid request body", nil, "")
+	}
+
+	if req.Message != nil {
+		step.Message = *req.Message
+	}
+	if req.InputType != nil {
+		step.InputType = *req.InputType
+	}
+	if req.StoreAs != nil {
+		step.StoreAs = *req.StoreAs
+	}
+	if req.Sensitive != nil {
+		step.Sensitive = *req.Sensitive
+	}
+	if req.ValidationRegex != nil {
+		if _, err := regexp.Compile(*req.ValidationRegex); err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, fmt.Sprintf("Invalid validation_regex %q", *req.ValidationRegex), nil, "")
+		}
+		step.ValidationRegex = *req.ValidationRegex
+	}
+	if req.ValidationError != nil {
+		step.ValidationError = *req.ValidationError
+	}
+	if req.NextStep != nil {
+		step.NextStep = *req.NextStep
+	}
+	if req.StepTimeoutMins != nil {
+		if *req.StepTimeoutMins < 0 || *req.StepTimeoutMins > maxStepTimeoutMins {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+				fmt.Sprintf("step_timeout_minutes must be between 0 and %d", maxStepTimeoutMins), nil, "")
+		}
+		step.StepTimeoutMins = *req.StepTimeoutMins
+	}
+
+	// Check the patched step against the rest of the flow
+	var flowSteps []models.ChatbotFlowStep
+	if err := a.DB.Where("flow_id = ?", flowID).Order("step_order ASC").Find(&flowSteps).Error; err != nil {
+		a.Log.Error("Failed to load flow steps", "error", err, "flow_id", flowID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update flow step", nil, "")
+	}
+	routing := make([]FlowStepRequest, len(flowSteps))
+	for i, existing := range flowSteps {
+		if existing.ID == step.ID {
+			existing = step
+		}
+		routing[i] = flowStepRoutingRequest(existing.StepName, existing.NextStep, existing.ConditionalNext, existing.Buttons)
+	}
+	if errMsg := validateFlowStepLinks(routing); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if errMsg := validateFlowStepButtons(routing); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+
+	if err := a.DB.Save(&step).Error; err != nil {
+		a.Log.Error("Failed to update flow step", "error", err, "step_id", stepID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update flow step", nil, "")
+	}
+
+	// Invalidate cache
+	a.InvalidateChatbotFlowsCache(orgID)
+
+	return r.SendEnvelope(step)
+}
+
+// DeleteChatbotFlow deletes a chatbot flow
+func (a *App) DeleteChatbotFlow(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceFlowsChatbot, models.ActionDelete, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Permission denied", nil, "")
+	}
+
+	id, err := parsePathUUID(r, "id", "flow")
+	if err != nil {
+		return nil
+	}
+
+	// Delete flow and steps in transaction
+	tx := a.DB.Begin()
+
+	// Delete steps first
+	if err := tx.Where("flow_id = ?", id).Delete(&models.ChatbotFlowStep{}).Error; err != nil {
+		tx.Rollback()
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete flow steps", nil, "")
+	}
+
+	// Delete flow
+	result := tx.Where("id = ? AND organization_id = ?", id, orgID).Delete(&models.ChatbotFlow{})
+	if result.Error != nil {
+		tx.Rollback()
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete flow", nil, "")
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Flow not found", nil, "")
+	}
+
+	tx.Commit()
+
+	// Invalidate cache
+	a.InvalidateChatbotFlowsCache(orgID)
+
+	return r.SendEnvelope(map[string]interface{}{
+		"message": "Flow deleted successfully",
+	})
+}
+
+// maxSimulationSteps bounds the number of steps a simulation may enter, guarding
+// against flows whose next_step references form a loop of no-input steps
+const maxSimulationSteps = 100
+
+// FlowSimulationMessage is a bot message that would be sent during a simulated flow run
+type FlowSimulationMessage struct {
+	Step    string   `json:"step"`
+	Type    string   `json:"type"` // flow_start, step, validation_error, flow_complete, flow_cancel
+	Text    string   `json:"text"`
+	Buttons []string `json:"buttons,omitempty"`
+}
+
+// FlowSimulationResponse is the result of a flow dry-run
+type FlowSimulationResponse struct {
+	Messages       []FlowSimulationMessage `json:"messages"`
+	SessionData    models.JSONB            `json:"session_data"`
+	CurrentStep    string                  `json:"current_step"`
+	Status         string                  `json:"status"` // active, completed, cancelled, transferred
+	InputsConsumed int                     `json:"inputs_consumed"`
+}
+
+// SimulateChatbotFlow dry-runs a flow against an ordered list of user inputs.
+// It applies the same validation, skip and branching rules as the live processor
+// but sends nothing and persists no session rows.
+func (a *App) SimulateChatbotFlow(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceFlowsChatbot, models.ActionRead, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Permission denied", nil, "")
+	}
+
+	id, err := parsePathUUID(r, "id", "flow")
+	if err != nil {
+		return nil
+	}
+
+	var req struct {
+		Inputs []string `json:"inputs"`
+	}
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	var flow models.ChatbotFlow
+	if err := a.DB.Where("id = ? AND organization_id = ?", id, orgID).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step_order ASC")
+		}).
+		First(&flow).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Flow not found", nil, "")
+	}
+
+	return r.SendEnvelope(a.simulateFlow(&flow, req.Inputs))
+}
+
+// flowSimulator holds the in-memory session state of a flow dry-run
+type flowSimulator struct {
+	app         *App
+	flow        *models.ChatbotFlow
+	data        models.JSONB
+	messages    []FlowSimulationMessage
+	currentStep string
+	retries     int
+	status      string
+	entered     int
+}
+
+// simulateFlow walks a flow with the given inputs, mirroring startFlow and processFlowResponse
+func (a *App) simulateFlow(flow *models.ChatbotFlow, inputs []string) FlowSimulationResponse {
+	sim := &flowSimulator{
+		app:  a,
+		flow: flow,
+		data: models.JSONB{
+			"_flow_id":   flow.ID.String(),
+			"_flow_name": flow.Name,
+		},
+		messages: []FlowSimulationMessage{},
+		status:   string(models.SessionStatusActive),
+	}
+
+	if flow.InitialMessage != "" {
+		sim.say("flow_start", "flow_start", flow.InitialMessage, nil)
+	}
+
+	if len(flow.Steps) > 0 {
+		sim.enterStep(&flow.Steps[0], nil)
+	} else {
+		sim.complete()
+	}
+
+	consumed := 0
+	for _, input := range inputs {
+		if sim.status != string(models.SessionStatusActive) {
+			break
+		}
+		consumed++
+		sim.handleInput(input)
+	}
+
+	return FlowSimulationResponse{
+		Messages:       sim.messages,
+		SessionData:    sim.data,
+		CurrentStep:    sim.currentStep,
+		Status:         sim.status,
+		InputsConsumed: consumed,
+	}
+}
+
+func (s *flowSimulator) say(step, msgType, text string, buttons []string) {
+	s.messages = append(s.messages, FlowSimulationMessage{Step: step, Type: msgType, Text: text, Buttons: buttons})
+}
+
+func (s *flowSimulator) findStep(name string) *models.ChatbotFlowStep {
+	for i := range s.flow.Steps {
+		if s.flow.Steps[i].StepName == name {
+			return &s.flow.Steps[i]
+		}
+	}
+	return nil
+}
+
+// nextStepByOrder returns the step's explicit next step, or the one following it in order
+func (s *flowSimulator) nextStepByOrder(step *models.ChatbotFlowStep) string {
+	if step.NextStep != "" {
+		return step.NextStep
+	}
+	for i, st := range s.flow.Steps {
+		if st.StepName == step.StepName && i+1 < len(s.flow.Steps) {
+			return s.flow.Steps[i+1].StepName
+		}
+	}
+	return ""
+}
+
+// enterStep mirrors sendStepWithSkipCheck: it applies skip conditions, emits the
+// step message and auto-advances past steps that take no input
+func (s *flowSimulator) enterStep(step *models.ChatbotFlowStep, skipped map[string]bool) {
+	if skipped == nil {
+		skipped = make(map[string]bool)
+	}
+	s.entered++
+	if skipped[step.StepName] || s.entered > maxSimulationSteps {
+		s.complete()
+		return
+	}
+
+	s.currentStep = step.StepName
+	s.retries = 0
+
+	if step.SkipCondition != "" && evaluateExpression(step.SkipCondition, s.data) {
+		skipped[step.StepName] = true
+		s.advance(s.nextStepByOrder(step), skipped)
+		return
+	}
+
+	s.sendStep(step)
+	if step.MessageType == models.FlowStepTypeTransfer {
+		s.currentStep = ""
+		s.status = "transferred"
+		return
+	}
+
+	if step.InputType == models.InputTypeNone {
+		s.advance(s.nextStepByOrder(step), skipped)
+	}
+}
+
+// advance moves to the named step, completing the flow when it is empty or missing
+func (s *flowSimulator) advance(nextStepName string, skipped map[string]bool) {
+	if nextStepName == "" {
+		s.complete()
+		return
+	}
+	next := s.findStep(nextStepName)
+	if next == nil {
+		s.complete()
+		return
+	}
+	s.enterStep(next, skipped)
+}
+
+// sendStep records the message a step would send. API fetch steps are not
+// executed; their message template is rendered against the current data.
+func (s *flowSimulator) sendStep(step *models.ChatbotFlowStep) {
+	text := processTemplate(step.Message, s.data)
+	if step.MessageType == models.FlowStepTypeTransfer && text == "" {
+		return
+	}
+
+	var buttons []string
+	if step.MessageType == models.FlowStepTypeButtons {
+		for _, btn := range step.Buttons {
+			if btnMap, ok := btn.(map[string]interface{}); ok {
+				title, _ := btnMap["title"].(string)
+				buttons = append(buttons, title)
+			}
+		}
+	}
+	s.say(step.StepName, "step", text, buttons)
+}
+
+func (s *flowSimulator) complete() {
+	if s.flow.CompletionMessage != "" {
+		s.say("flow_complete", "flow_complete", s.app.replaceVariables(s.flow.CompletionMessage, s.data), nil)
+	}
+	s.currentStep = ""
+	s.status = string(models.SessionStatusCompleted)
+}
+
+// handleInput mirrors processFlowResponse for a plain text reply
+func (s *flowSimulator) handleInput(userInput string) {
+	s.entered = 0
+	userInputLower := strings.ToLower(userInput)
+	for _, cancelKw := range s.flow.CancelKeywords {
+		if strings.Contains(userInputLower, strings.ToLower(cancelKw)) {
+			s.say("flow_cancel", "flow_cancel", "Flow cancelled.", nil)
+			s.currentStep = ""
+			s.status = string(models.SessionStatusCancelled)
+			return
+		}
+	}
+
+	step := s.findStep(s.currentStep)
+	if step == nil {
+		s.currentStep = ""
+		s.status = string(models.SessionStatusCompleted)
+		return
+	}
+
+	if step.ValidationRegex != "" {
+		re, err := regexp.Compile(step.ValidationRegex)
+		if err == nil && !re.MatchString(userInput) {
+			s.retries++
+			if step.RetryOnInvalid && s.retries < step.MaxRetries {
+				errorMsg := step.ValidationError
+				if errorMsg == "" {
+					errorMsg = "Invalid input. Please try again."
+				}
+				s.say(step.StepName, "validation_error", errorMsg, nil)
+				return
+			}
+		}
+	}
+
+	// Typed replies are matched against button titles or IDs, as in the live processor
+	buttonID, buttonNextStep := "", ""
+	if len(step.Buttons) > 0 && (step.InputType == models.InputTypeButton || step.InputType == models.InputTypeSelect) {
+		for i, btn := range step.Buttons {
+			btnMap, ok := btn.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			btnID, _ := btnMap["id"].(string)
+			btnTitle, _ := btnMap["title"].(string)
+			if btnID == "" {
+				btnID = fmt.Sprintf("btn_%d", i+1)
+			}
+			if strings.ToLower(btnTitle) == userInputLower || btnID == userInput {
+				buttonID = btnID
+				buttonNextStep, _ = btnMap["next_step"].(string)
+				break
+			}
+		}
+
+		if buttonID == "" {
+			s.retries++
+			maxRetries := step.MaxRetries
+			if maxRetries == 0 {
+				maxRetries = 3
+			}
+			if s.retries >= maxRetries {
+				s.say(step.StepName, "flow_cancel", "Sorry, we couldn't continue. Please try again later.", nil)
+				s.currentStep = ""
+				s.status = string(models.SessionStatusCancelled)
+				return
+			}
+			s.sendStep(step)
+			return
+		}
+	}
+
+	if step.StoreAs != "" {
+		if buttonID != "" {
+			s.data[step.StoreAs] = buttonID
+			s.data[step.StoreAs+"_title"] = userInput
+		} else {
+			s.data[step.StoreAs] = userInput
+		}
+	}
+
+	nextStepName := s.nextStepByOrder(step)
+	if buttonNextStep != "" {
+		nextStepName = buttonNextStep
+	} else if len(step.ConditionalNext) > 0 {
+		if next, ok := step.ConditionalNext[buttonID].(string); buttonID != "" && ok {
+			nextStepName = next
+		} else if next, ok := step.ConditionalNext[userInput].(string); ok {
+			nextStepName = next
+		} else if defaultNext, ok := step.ConditionalNext["default"].(string); ok {
+			nextStepName = defaultNext
+		}
+	}
+
+	s.advance(nextStepName, nil)
+}
+
+// ListAIContexts lists all AI contexts
+func (a *App) ListAIContexts(r *fastglue.Request) error {
+	orgID, err := a.getOrgID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	pg := parsePagination(r)
+	search := string(r.RequestCtx.QueryArgs().Peek("search"))
+
+	query := a.DB.Model(&models.AIContext{}).Where("organization_id = ?", orgID)
+
+	// Apply search filter - search by name, static content, or trigger keywords
+	if search != "" {
+		searchPattern := "%" + search + "%"
+		query = query.Where("name ILIKE ? OR static_content ILIKE ? OR trigger_keywords::text ILIKE ?", searchPattern, searchPattern, searchPattern)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var contexts []models.AIContext
+	if err := pg.Apply(query.Order("priority DESC, created_at DESC")).
+		Find(&contexts).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to fetch AI contexts", nil, "")
+	}
+
+	response := make([]AIContextResponse, len(contexts))
+	for i, ctx := range contexts {
+		response[i] = AIContextResponse{
+			ID:              ctx.ID.String(),
+			Name:            ctx.Name,
+			ContextType:     ctx.ContextType,
+			TriggerKeywords: ctx.TriggerKeywords,
+			StaticContent:   ctx.StaticContent,
+			Enabled:         ctx.IsEnabled,
+			Priority:        ctx.Priority,
+			CreatedAt:       ctx.CreatedAt.Format(time.RFC3339),
 		}
 	}
 
@@ -1244,9 +2647,271 @@ func (a *App) DeleteAIContext(r *fastglue.Request) error {
 	})
 }
 
-// ListChatbotSessions lists chatbot sessions
+// aiContextTestTimeout bounds the outbound request made when testing an API context
+const aiContextTestTimeout = 10 * time.Second
+
+// aiContextTestPreviewLen is the maximum number of response body characters returned by TestAIContext
+const aiContextTestPreviewLen = 500
+
+// sensitiveParamPattern matches header and query parameter names that usually carry credentials
+var sensitiveParamPattern = regexp.MustCompile(`(?i)(key|token|secret|password|passwd|auth|signature|session|cookie)`)
+
+const redactedValue = "[REDACTED]"
+
+// AIContextTestResponse is the result of testing an AI context
+type AIContextTestResponse struct {
+	ContextType   models.ContextType `json:"context_type"`
+	Success       bool               `json:"success"`
+	StatusCode    int                `json:"status_code,omitempty"`
+	BodyPreview   string             `json:"body_preview,omitempty"`
+	DurationMs    int64              `json:"duration_ms,omitempty"`
+	Error         string             `json:"error,omitempty"`
+	ContentLength int                `json:"content_length"`
+	ApiConfig     map[string]any     `json:"api_config,omitempty"`
+}
+
+// TestAIContext verifies an AI context. For API contexts it performs the configured
+// request and reports the status code and a body preview; for static contexts it
+// reports the content length. Credentials in the config are redacted.
+func (a *App) TestAIContext(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceChatbotAI, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	id, err := parsePathUUID(r, "id", "context")
+	if err != nil {
+		return nil
+	}
+
+	aiCtx, err := findByIDAndOrg[models.AIContext](a.DB, r, id, orgID, "AI context")
+	if err != nil {
+		return nil
+	}
+
+	// Optional sample message used for {{user_message}} substitution
+	var req struct {
+		UserMessage string `json:"user_message"`
+	}
+	if body := r.RequestCtx.PostBody(); len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid request body", nil, "")
+		}
+	}
+
+	result := AIContextTestResponse{
+		ContextType:   aiCtx.ContextType,
+		ContentLength: len(aiCtx.StaticContent),
+	}
+
+	if aiCtx.ContextType != models.ContextTypeAPI {
+		result.Success = true
+		return r.SendEnvelope(result)
+	}
+
+	result.ApiConfig = redactAPIConfig(aiCtx.ApiConfig)
+
+	ctx, cancel := context.WithTimeout(context.Background(), aiContextTestTimeout)
+	defer cancel()
+
+	httpReq, err := a.newAPIContextRequest(ctx, aiCtx.ApiConfig, models.JSONB{"user_message": req.UserMessage})
+	if err != nil {
+		result.Error = redactRequestError(err)
+		return r.SendEnvelope(result)
+	}
+
+	start := time.Now()
+	resp, err := a.HTTPClient.Do(httpReq)
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = "API request failed: " + redactRequestError(err)
+		return r.SendEnvelope(result)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	preview, _ := io.ReadAll(io.LimitReader(resp.Body, aiContextTestPreviewLen+1))
+	result.StatusCode = resp.StatusCode
+	result.BodyPreview = truncateString(string(preview), aiContextTestPreviewLen)
+	result.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !result.Success {
+		result.Error = fmt.Sprintf("API returned status %d", resp.StatusCode)
+	}
+
+	return r.SendEnvelope(result)
+}
+
+// AIContextPreviewItem is a context that matched the preview text
+type AIContextPreviewItem struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	ContextType models.ContextType `json:"context_type"`
+	Priority    int                `json:"priority"`
+	Chars       int                `json:"chars"`
+	Truncated   bool               `json:"truncated"`
+}
+
+// AIContextPreviewResponse is the context the AI would receive for a message
+type AIContextPreviewResponse struct {
+	Context    string                 `json:"context"`
+	TotalChars int                    `json:"total_chars"`
+	Included   []AIContextPreviewItem `json:"included"`
+	Excluded   []AIContextPreviewItem `json:"excluded"`
+	Limit      int                    `json:"ai_context_limit"`
+	MaxChars   int                    `json:"ai_context_max_chars"`
+}
+
+// PreviewAIContext shows which AI contexts a message would trigger and the
+// context block assembled from them under the org's count and character
+// limits. API contexts contribute only their static content; no requests are made.
+func (a *App) PreviewAIContext(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceChatbotAI, models.ActionRead); err != nil {
+		return nil
+	}
+
+	var req struct {
+		TriggerText     string `json:"trigger_text"`
+		WhatsAppAccount string `json:"whatsapp_account"`
+	}
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	if strings.TrimSpace(req.TriggerText) == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "trigger_text is required", nil, "")
+	}
+
+	contexts, err := a.getAIContextsCached(orgID, req.WhatsAppAccount)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to fetch AI contexts", nil, "")
+	}
+
+	var limit, maxChars int
+	if settings, err := a.getChatbotSettingsCached(orgID, req.WhatsAppAccount); err == nil {
+		limit, maxChars = settings.AI.ContextLimit, settings.AI.ContextMaxChars
+	}
+
+	var sections []aiContextSection
+	for _, ctx := range matchAIContexts(contexts, req.TriggerText, limit) {
+		if ctx.StaticContent != "" {
+			sections = append(sections, aiContextSection{Context: ctx, Content: ctx.StaticContent})
+		}
+	}
+	fitted := fitAIContextBudget(sections, maxChars)
+
+	result := AIContextPreviewResponse{
+		Context:  formatAIContext(fitted),
+		Included: make([]AIContextPreviewItem, 0, len(fitted)),
+		Excluded: []AIContextPreviewItem{},
+		Limit:    limit,
+		MaxChars: maxChars,
+	}
+	for _, section := range fitted {
+		chars := utf8.RuneCountInString(section.Content)
+		result.TotalChars += chars
+		result.Included = append(result.Included, aiContextPreviewItem(section.Context, chars, section.Truncated))
+	}
+
+	// Report matching contexts that were cut by the limits
+	included := make(map[uuid.UUID]bool, len(fitted))
+	for _, section := range fitted {
+		included[section.Context.ID] = true
+	}
+	for _, ctx := range matchAIContexts(contexts, req.TriggerText, 0) {
+		if !included[ctx.ID] {
+			result.Excluded = append(result.Excluded, aiContextPreviewItem(ctx, 0, false))
+		}
+	}
+
+	return r.SendEnvelope(result)
+}
+
+// aiContextPreviewItem describes a context for PreviewAIContext
+func aiContextPreviewItem(ctx models.AIContext, chars int, truncated bool) AIContextPreviewItem {
+	return AIContextPreviewItem{
+		ID:          ctx.ID.String(),
+		Name:        ctx.Name,
+		ContextType: ctx.ContextType,
+		Priority:    ctx.Priority,
+		Chars:       chars,
+		Truncated:   truncated,
+	}
+}
+
+// redactAPIConfig returns a copy of an API context config that is safe to show to users
+func redactAPIConfig(apiConfig models.JSONB) map[string]any {
+	redacted := make(map[string]any, len(apiConfig))
+	for key, value := range apiConfig {
+		switch key {
+		case "headers":
+			headers, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// Header values are almost always credentials; keep only the names
+			masked := make(map[string]any, len(headers))
+			for name := range headers {
+				masked[name] = redactedValue
+			}
+			redacted[key] = masked
+		case "url":
+			if rawURL, ok := value.(string); ok {
+				redacted[key] = redactURL(rawURL)
+			}
+		case "body":
+			if body, ok := value.(string); ok && body != "" {
+				redacted[key] = redactedValue
+			}
+		default:
+			redacted[key] = value
+		}
+	}
+	return redacted
+}
+
+// redactRequestError drops the URL that url.Error embeds, since it may carry credentials
+func redactRequestError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// redactURL masks userinfo and sensitive query parameters in a URL
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redactedValue
+	}
+	if u.User != nil {
+		u.User = url.User(redactedValue)
+	}
+	query := u.Query()
+	changed := false
+	for name := range query {
+		if sensitiveParamPattern.MatchString(name) {
+			query.Set(name, redactedValue)
+			changed = true
+		}
+	}
+	if changed {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// ListChatbotSessions lists chatbot sessions, masking sensitive values like
+// GetChatbotSession
 func (a *App) ListChatbotSessions(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
@@ -1266,14 +2931,31 @@ func (a *App) ListChatbotSessions(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to fetch sessions", nil, "")
 	}
 
+	if !a.HasPermission(userID, models.ResourcePII, models.ActionRead, orgID) {
+		sensitiveByFlow := make(map[uuid.UUID]map[string]bool)
+		for i := range sessions {
+			flowID := sessionFlowID(&sessions[i])
+			if flowID == nil {
+				continue
+			}
+			sensitive, ok := sensitiveByFlow[*flowID]
+			if !ok {
+				sensitive = a.sensitiveSessionKeys(*flowID)
+				sensitiveByFlow[*flowID] = sensitive
+			}
+			sessions[i].SessionData = redactSessionData(sessions[i].SessionData, sensitive)
+		}
+	}
+
 	return r.SendEnvelope(map[string]interface{}{
 		"sessions": sessions,
 	})
 }
 
-// GetChatbotSession gets a single chatbot session with messages
+// GetChatbotSession gets a single chatbot session with messages.
+// Values stored by sensitive steps are masked unless the user has pii:read.
 func (a *App) GetChatbotSession(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
@@ -1291,6 +2973,10 @@ func (a *App) GetChatbotSession(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Session not found", nil, "")
 	}
 
+	if flowID := sessionFlowID(&session); flowID != nil && !a.HasPermission(userID, models.ResourcePII, models.ActionRead, orgID) {
+		session.SessionData = redactSessionData(session.SessionData, a.sensitiveSessionKeys(*flowID))
+	}
+
 	return r.SendEnvelope(session)
 }
 
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// ChatbotAccountSettingsRequest updates a WhatsApp account's chatbot
+// settings. Omitted or null fields keep their current value.
+type ChatbotAccountSettingsRequest struct {
+	Enabled               *bool                     `json:"enabled"`
+	GreetingMessage       *string                   `json:"greeting_message"`
+	GreetingButtons       *[]map[string]interface{} `json:"greeting_buttons"`
+	FallbackMessage       *string                   `json:"fallback_message"`
+	FallbackButtons       *[]map[string]interface{} `json:"fallback_buttons"`
+	OutOfHoursMessage     *string                   `json:"out_of_hours_message"`
+	SessionTimeoutMinutes *int                      `json:"session_timeout_minutes"`
+}
+
+// ChatbotAccountSettings is the part of the chatbot settings that can be
+// set per WhatsApp account
+type ChatbotAccountSettings struct {
+	Enabled               bool                     `json:"enabled"`
+	GreetingMessage       string                   `json:"greeting_message"`
+	GreetingButtons       []map[string]interface{} `json:"greeting_buttons"`
+	FallbackMessage       string                   `json:"fallback_message"`
+	FallbackButtons       []map[string]interface{} `json:"fallback_buttons"`
+	OutOfHoursMessage     string                   `json:"out_of_hours_message"`
+	SessionTimeoutMinutes int                      `json:"session_timeout_minutes"`
+}
+
+// ChatbotAccountSettingsResponse is the chatbot settings used for a WhatsApp
+// account. Inherited is true when the account has no settings of its own and
+// uses the organization settings.
+type ChatbotAccountSettingsResponse struct {
+	WhatsAppAccount string                 `json:"whatsapp_account"`
+	Inherited       bool                   `json:"inherited"`
+	Settings        ChatbotAccountSettings `json:"settings"`
+}
+
+// GetChatbotAccountSettings returns the chatbot settings used for a WhatsApp account
+func (a *App) GetChatbotAccountSettings(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	if err := a.requirePermission(r, userID, models.ResourceSettingsChatbot, models.ActionRead); err != nil {
+		return nil
+	}
+
+	accountName, ok := a.chatbotSettingsAccount(r, orgID)
+	if !ok {
+		return nil
+	}
+
+	var count int64
+	if err := a.DB.Model(&models.ChatbotSettings{}).
+		Where("organization_id = ? AND whats_app_account = ?", orgID, accountName).
+		Count(&count).Error; err != nil {
+		a.Log.Error("Failed to load account chatbot settings", "error", err, "account", accountName)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to fetch account settings", nil, "")
+	}
+
+	return r.SendEnvelope(a.chatbotAccountSettingsResponse(orgID, accountName, count == 0))
+}
+
+// UpdateChatbotAccountSettings sets chatbot settings for a WhatsApp account.
+// The account's settings row is created from the organization settings on
+// first update, and from then on takes precedence over them.
+func (a *App) UpdateChatbotAccountSettings(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	if err := a.requirePermission(r, userID, models.ResourceSettingsChatbot, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	accountName, ok := a.chatbotSettingsAccount(r, orgID)
+	if !ok {
+		return nil
+	}
+
+	var req ChatbotAccountSettingsRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	if req.SessionTimeoutMinutes != nil && *req.SessionTimeoutMinutes < 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "session_timeout_minutes cannot be negative", nil, "")
+	}
+
+	var settings models.ChatbotSettings
+	isNew := false
+	err = a.DB.Where("organization_id = ? AND whats_app_account = ?", orgID, accountName).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		isNew = true
+		settings, err = a.newAccountChatbotSettings(orgID, accountName)
+	}
+	if err != nil {
+		a.Log.Error("Failed to load account chatbot settings", "error", err, "account", accountName)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update account settings", nil, "")
+	}
+
+	if req.Enabled != nil {
+		settings.IsEnabled = *req.Enabled
+	}
+	if req.GreetingMessage != nil {
+		settings.DefaultResponse = *req.GreetingMessage
+	}
+	if req.GreetingButtons != nil {
+		settings.GreetingButtons = buttonsToJSONBArray(req.GreetingButtons)
+	}
+	if req.FallbackMessage != nil {
+		settings.FallbackMessage = *req.FallbackMessage
+	}
+	if req.FallbackButtons != nil {
+		settings.FallbackButtons = buttonsToJSONBArray(req.FallbackButtons)
+	}
+	if req.OutOfHoursMessage != nil {
+		settings.BusinessHours.OutOfHoursMessage = *req.OutOfHoursMessage
+	}
+	if req.SessionTimeoutMinutes != nil {
+		settings.SessionTimeoutMins = *req.SessionTimeoutMinutes
+	}
+
+	if isNew {
+		// Insert every column so copied false/zero values don't fall back to column defaults
+		err = a.DB.Select("*").Create(&settings).Error
+	} else {
+		err = a.DB.Save(&settings).Error
+	}
+	if err != nil {
+		a.Log.Error("Failed to save account chatbot settings", "error", err, "account", accountName)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update account settings", nil, "")
+	}
+
+	a.InvalidateChatbotSettingsCache(orgID)
+	a.InvalidateSLASettingsCache()
+
+	return r.SendEnvelope(a.chatbotAccountSettingsResponse(orgID, accountName, false))
+}
+
+// DeleteChatbotAccountSettings removes a WhatsApp account's chatbot settings
+// so the account goes back to the organization settings
+func (a *App) DeleteChatbotAccountSettings(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	if err := a.requirePermission(r, userID, models.ResourceSettingsChatbot, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	accountName, ok := a.chatbotSettingsAccount(r, orgID)
+	if !ok {
+		return nil
+	}
+
+	if err := a.DB.Where("organization_id = ? AND whats_app_account = ?", orgID, accountName).
+		Delete(&models.ChatbotSettings{}).Error; err != nil {
+		a.Log.Error("Failed to delete account chatbot settings", "error", err, "account", accountName)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete account settings", nil, "")
+	}
+
+	a.InvalidateChatbotSettingsCache(orgID)
+	a.InvalidateSLASettingsCache()
+
+	return r.SendEnvelope(a.chatbotAccountSettingsResponse(orgID, accountName, true))
+}
+
+// chatbotSettingsAccount returns the WhatsApp account named in the path,
+// sending a 404 when the organization has no such account
+func (a *App) chatbotSettingsAccount(r *fastglue.Request, orgID uuid.UUID) (string, bool) {
+	accountName, _ := r.RequestCtx.UserValue("account").(string)
+	var count int64
+	a.DB.Model(&models.WhatsAppAccount{}).
+		Where("organization_id = ? AND name = ?", orgID, accountName).
+		Count(&count)
+	if accountName == "" || count == 0 {
+		_ = r.SendErrorEnvelope(fasthttp.StatusNotFound, "WhatsApp account not found", nil, "")
+		return "", false
+	}
+	return accountName, true
+}
+
+// newAccountChatbotSettings returns an unsaved settings row for the account,
+// copied from the organization settings so fields the request leaves out
+// keep the values the account was already using
+func (a *App) newAccountChatbotSettings(orgID uuid.UUID, accountName string) (models.ChatbotSettings, error) {
+	var settings models.ChatbotSettings
+	err := a.DB.Where("organization_id = ? AND whats_app_account = ?", orgID, "").First(&settings).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Same defaults GetChatbotSettings reports when nothing is saved
+		settings = models.ChatbotSettings{
+			OrganizationID:     orgID,
+			DefaultResponse:    "Hello! How can I help you today?",
+			SessionTimeoutMins: 30,
+			SLA:                models.SLAConfig{ResponseMinutes: 15, EscalationMinutes: 30, ResolutionMinutes: 60, AutoCloseHours: 24},
+			ClientInactivity:   models.ClientInactivityConfig{ReminderMinutes: 30, AutoCloseMinutes: 60},
+		}
+	case err != nil:
+		return settings, err
+	}
+
+	settings.BaseModel = models.BaseModel{ID: uuid.New()}
+	settings.WhatsAppAccount = accountName
+	return settings, nil
+}
+
+// chatbotAccountSettingsResponse builds the response for an account. The
+// settings go through getChatbotSettingsCached so they match what the
+// chatbot uses.
+func (a *App) chatbotAccountSettingsResponse(orgID uuid.UUID, accountName string, inherited bool) ChatbotAccountSettingsResponse {
+	settings, err := a.getChatbotSettingsCached(orgID, accountName)
+	if err != nil {
+		// Same defaults GetChatbotSettings reports when nothing is saved
+		settings = &models.ChatbotSettings{
+			DefaultResponse:    "Hello! How can I help you today?",
+			SessionTimeoutMins: 30,
+		}
+	}
+
+	return ChatbotAccountSettingsResponse{
+		WhatsAppAccount: accountName,
+		Inherited:       inherited,
+		Settings: ChatbotAccountSettings{
+			Enabled:               settings.IsEnabled,
+			GreetingMessage:       settings.DefaultResponse,
+			GreetingButtons:       buttonMaps(settings.GreetingButtons),
+			FallbackMessage:       settings.FallbackMessage,
+			FallbackButtons:       buttonMaps(settings.FallbackButtons),
+			OutOfHoursMessage:     settings.BusinessHours.OutOfHoursMessage,
+			SessionTimeoutMinutes: settings.SessionTimeoutMins,
+		},
+	}
+}
+
+// buttonsToJSONBArray converts request buttons for storage
+func buttonsToJSONBArray(buttons *[]map[string]interface{}) models.JSONBArray {
+	arr := make(models.JSONBArray, len(*buttons))
+	for i, btn := range *buttons {
+		arr[i] = btn
+	}
+	return arr
+}
+
+// buttonMaps returns the button objects in a stored button list
+func buttonMaps(arr models.JSONBArray) []map[string]interface{} {
+	buttons := make([]map[string]interface{}, 0, len(arr))
+	for _, btn := range arr {
+		if btnMap, ok := btn.(map[string]interface{}); ok {
+			buttons = append(buttons, btnMap)
+		}
+	}
+	return buttons
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+)
+
+// aiAssistNoValue is what the model is asked to reply when the answer
+// doesn't contain the requested value
+const aiAssistNoValue = "NONE"
+
+// stepAIAssist is a flow step's optional AI interpretation of free-text
+// answers, configured under input_config.ai_assist:
+//
+//	{"enabled": true, "instruction": "Extract the delivery date as YYYY-MM-DD",
+//	 "store_as": "delivery_date", "required": true}
+type stepAIAssist struct {
+	Instruction string
+	StoreAs     string // Session variable for the result; defaults to the step's store_as + "_ai"
+	Required    bool   // Treat answers the AI can't interpret as invalid input
+}
+
+// parseStepAIAssist returns the step's AI assist config, or nil if it's not enabled
+func parseStepAIAssist(step *models.ChatbotFlowStep) *stepAIAssist {
+	cfg, ok := step.InputConfig["ai_assist"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if enabled, _ := cfg["enabled"].(bool); !enabled {
+		return nil
+	}
+	instruction, _ := cfg["instruction"].(string)
+	if strings.TrimSpace(instruction) == "" {
+		return nil
+	}
+
+	assist := &stepAIAssist{Instruction: instruction}
+	assist.StoreAs, _ = cfg["store_as"].(string)
+	assist.Required, _ = cfg["required"].(bool)
+	if assist.StoreAs == "" {
+		if step.StoreAs == "" {
+			return nil
+		}
+		assist.StoreAs = step.StoreAs + "_ai"
+	}
+	return assist
+}
+
+// extractWithAI asks the organization's AI provider to interpret a single
+// answer according to instruction. It returns "" when the model reports that
+// the value isn't present. Conversation history and AI contexts are left out
+// so the model only sees the answer being interpreted.
+func (a *App) extractWithAI(settings *models.ChatbotSettings, instruction, input string) (string, error) {
+	release, err := a.acquireAISlot(settings.OrganizationID, settings.AI)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	extraction := *settings
+	extraction.AI.SystemPrompt = instruction + "\n\nReply with only the extracted value and nothing else. " +
+		"If the message does not contain it, reply with " + aiAssistNoValue + "."
+	extraction.AI.IncludeHistory = false
+
+	result, err := a.callAIProvider(&extraction, nil, input, "")
+	if err != nil {
+		return "", err
+	}
+	result = strings.Trim(strings.TrimSpace(result), "\"'`")
+	if strings.EqualFold(result, aiAssistNoValue) {
+		return "", nil
+	}
+	return result, nil
+}
+
+// applyStepAIAssist runs a step's AI assist on the user's answer and stores
+// the result in the session. It returns false when the answer was rejected and
+// the step has been re-asked, in which case the caller should stop processing.
+// AI being unavailable never blocks the flow; the raw answer is still stored.
+func (a *App) applyStepAIAssist(account *models.WhatsAppAccount, session *models.ChatbotSession, contact *models.Contact, step *models.ChatbotFlowStep, assist *stepAIAssist, userInput string) bool {
+	settings, err := a.getChatbotSettingsCached(account.OrganizationID, account.Name)
+	if err != nil || !settings.AI.Enabled || settings.AI.Provider == "" || settings.AI.APIKey == "" {
+		a.Log.Warn("AI assist skipped, AI is not configured", "step", step.StepName)
+		return true
+	}
+
+	value, err := a.extractWithAI(settings, assist.Instruction, userInput)
+	if err != nil {
+		a.Log.Error("AI assist failed", "error", err, "step", step.StepName)
+		return true
+	}
+
+	if value == "" {
+		if !assist.Required {
+			return true
+		}
+		session.StepRetries++
+		if step.RetryOnInvalid && session.StepRetries < step.MaxRetries {
+			a.DB.Model(session).Update("step_retries", session.StepRetries)
+			errorMsg := step.ValidationError
+			if errorMsg == "" {
+				errorMsg = "Invalid input. Please try again."
+			}
+			if err := a.sendAndSaveTextMessage(account, contact, errorMsg); err != nil {
+				a.Log.Error("Failed to send validation error", "error", err, "contact", contact.PhoneNumber)
+			}
+			a.logSessionMessage(session.ID, models.DirectionOutgoing, errorMsg, step.StepName+"_retry")
+			return false
+		}
+		a.Log.Warn("Max retries exceeded", "step", step.StepName)
+		return true
+	}
+
+	sessionData := session.SessionData
+	if sessionData == nil {
+		sessionData = models.JSONB{}
+	}
+	sessionData[assist.StoreAs] = value
+	a.DB.Model(session).Update("session_data", sessionData)
+	session.SessionData = sessionData
+	return true
+}
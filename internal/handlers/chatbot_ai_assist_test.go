@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAIReply builds an OpenAI chat completion body answering with content.
+func openAIReply(t *testing.T, content string) string {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"choices": []any{map[string]any{"message": map[string]any{"content": content}}},
+	})
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestParseStepAIAssist(t *testing.T) {
+	step := &models.ChatbotFlowStep{StoreAs: "delivery"}
+	assert.Nil(t, parseStepAIAssist(step), "no input_config")
+
+	step.InputConfig = models.JSONB{"ai_assist": map[string]interface{}{"enabled": false, "instruction": "Extract the date"}}
+	assert.Nil(t, parseStepAIAssist(step), "disabled")
+
+	step.InputConfig = models.JSONB{"ai_assist": map[string]interface{}{"enabled": true}}
+	assert.Nil(t, parseStepAIAssist(step), "missing instruction")
+
+	step.InputConfig = models.JSONB{"ai_assist": map[string]interface{}{"enabled": true, "instruction": "Extract the date"}}
+	assist := parseStepAIAssist(step)
+	require.NotNil(t, assist)
+	assert.Equal(t, "delivery_ai", assist.StoreAs)
+	assert.False(t, assist.Required)
+
+	step.InputConfig = models.JSONB{"ai_assist": map[string]interface{}{
+		"enabled": true, "instruction": "Extract the date", "store_as": "delivery_date", "required": true,
+	}}
+	assist = parseStepAIAssist(step)
+	require.NotNil(t, assist)
+	assert.Equal(t, "delivery_date", assist.StoreAs)
+	assert.True(t, assist.Required)
+}
+
+func TestExtractWithAI(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+	settings := limitedAISettings(org.ID, 0, 0)
+	settings.AI.SystemPrompt = "You are a friendly store assistant."
+	settings.AI.IncludeHistory = true
+
+	t.Run("returns the extracted value", func(t *testing.T) {
+		transport := &aiProviderTransport{body: openAIReply(t, " \"2025-03-14\" ")}
+		app.HTTPClient = &http.Client{Transport: transport}
+
+		value, err := app.extractWithAI(settings, "Extract the delivery date as YYYY-MM-DD.", "next friday, the 14th of March")
+		require.NoError(t, err)
+		assert.Equal(t, "2025-03-14", value)
+
+		require.Len(t, transport.requests, 1)
+		var payload struct {
+			Messages []map[string]string `json:"messages"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(transport.requests[0]), &payload))
+		require.Len(t, payload.Messages, 2, "only the instruction and the answer are sent")
+		assert.Contains(t, payload.Messages[0]["content"], "Extract the delivery date as YYYY-MM-DD.")
+		assert.NotContains(t, payload.Messages[0]["content"], "friendly store assistant")
+		assert.Equal(t, "next friday, the 14th of March", payload.Messages[1]["content"])
+	})
+
+	t.Run("NONE means no value", func(t *testing.T) {
+		app.HTTPClient = &http.Client{Transport: &aiProviderTransport{body: openAIReply(t, "none")}}
+
+		value, err := app.extractWithAI(settings, "Extract the delivery date as YYYY-MM-DD.", "what?")
+		require.NoError(t, err)
+		assert.Empty(t, value)
+	})
+}
+
+// setupAIAssistFlow creates AI-enabled chatbot settings and a flow whose only
+// step asks for a delivery date and interprets it with AI.
+func setupAIAssistFlow(t *testing.T, app *App, required bool) (*models.WhatsAppAccount, *models.ChatbotFlow) {
+	t.Helper()
+	org, account := createProcessorTestOrg(t, app)
+
+	settings := limitedAISettings(org.ID, 0, 0)
+	settings.WhatsAppAccount = account.Name
+	settings.IsEnabled = true
+	settings.SessionTimeoutMins = 30
+	require.NoError(t, app.DB.Create(settings).Error)
+
+	flow := &models.ChatbotFlow{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "Delivery",
+		TriggerKeywords: models.StringArray{"deliver"},
+		IsEnabled:       true,
+	}
+	require.NoError(t, app.DB.Create(flow).Error)
+	step := &models.ChatbotFlowStep{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		FlowID:          flow.ID,
+		StepName:        "when",
+		StepOrder:       1,
+		Message:         "When should we deliver?",
+		MessageType:     models.FlowStepTypeText,
+		InputType:       models.InputTypeText,
+		StoreAs:         "delivery",
+		ValidationError: "Sorry, which day works for you?",
+		RetryOnInvalid:  true,
+		MaxRetries:      3,
+		InputConfig: models.JSONB{"ai_assist": map[string]interface{}{
+			"enabled":     true,
+			"instruction": "Extract the delivery date as YYYY-MM-DD.",
+			"store_as":    "delivery_date",
+			"required":    required,
+		}},
+	}
+	require.NoError(t, app.DB.Create(step).Error)
+	return account, flow
+}
+
+func TestProcessFlowResponse_AIAssistStoresExtractedValue(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	account, _ := setupAIAssistFlow(t, app, true)
+	transport := &aiProviderTransport{body: openAIReply(t, "2025-03-14")}
+	app.HTTPClient = &http.Client{Transport: transport}
+
+	phone := uniqueTestPhone()
+	send := func(id, body string) {
+		msg := IncomingTextMessage{From: phone, ID: id, Type: "text"}
+		msg.Text = &struct {
+			Body string `json:"body"`
+		}{Body: body}
+		app.processIncomingMessageFull(account.PhoneID, msg, "Customer")
+	}
+
+	send("wamid.assist-1", "deliver")
+	send("wamid.assist-2", "next friday, the 14th of March")
+	require.Len(t, transport.hosts, 1)
+
+	var session models.ChatbotSession
+	require.NoError(t, app.DB.Where("organization_id = ? AND phone_number = ?", account.OrganizationID, phone).
+		Order("created_at DESC").First(&session).Error)
+	assert.Equal(t, "next friday, the 14th of March", session.SessionData["delivery"])
+	assert.Equal(t, "2025-03-14", session.SessionData["delivery_date"])
+	assert.Equal(t, models.SessionStatusCompleted, session.Status, "flow should complete after its only step")
+}
+
+func TestProcessFlowResponse_AIAssistRequiredReasksStep(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	account, flow := setupAIAssistFlow(t, app, true)
+	app.HTTPClient = &http.Client{Transport: &aiProviderTransport{body: openAIReply(t, aiAssistNoValue)}}
+
+	phone := uniqueTestPhone()
+	send := func(id, body string) {
+		msg := IncomingTextMessage{From: phone, ID: id, Type: "text"}
+		msg.Text = &struct {
+			Body string `json:"body"`
+		}{Body: body}
+		app.processIncomingMessageFull(account.PhoneID, msg, "Customer")
+	}
+
+	send("wamid.assist-1", "deliver")
+	send("wamid.assist-2", "whenever")
+
+	var session models.ChatbotSession
+	require.NoError(t, app.DB.Where("organization_id = ? AND phone_number = ?", account.OrganizationID, phone).
+		Order("created_at DESC").First(&session).Error)
+	require.NotNil(t, session.CurrentFlowID)
+	assert.Equal(t, flow.ID, *session.CurrentFlowID)
+	assert.Equal(t, "when", session.CurrentStep)
+	assert.Equal(t, 1, session.StepRetries)
+	assert.NotContains(t, session.SessionData, "delivery_date")
+
+	var reasks int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("organization_id = ? AND direction = ? AND content = ?", account.OrganizationID, models.DirectionOutgoing, "Sorry, which day works for you?").
+		Count(&reasks).Error)
+	assert.Equal(t, int64(1), reasks)
+}
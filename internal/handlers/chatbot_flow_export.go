@@ -0,0 +1,423 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// FlowExportVersion is the schema version written to exported flow documents
+const FlowExportVersion = 1
+
+// FlowExportDocument is a portable, org-independent representation of a chatbot flow.
+// Templates and transfer teams are referenced by name so the document can be
+// imported into another organization or environment.
+type FlowExportDocument struct {
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Flow       FlowExportData `json:"flow"`
+}
+
+// FlowExportData holds the exported flow fields
+type FlowExportData struct {
+	Name               string                   `json:"name"`
+	Description        string                   `json:"description"`
+	Enabled            bool                     `json:"enabled"`
+	TriggerType        models.FlowTriggerType   `json:"trigger_type,omitempty"`
+	TriggerKeywords    []string                 `json:"trigger_keywords"`
+	TriggerButtonID    string                   `json:"trigger_button_id"`
+	InitialMessage     string                   `json:"initial_message"`
+	InitialMessageType models.FlowStepType      `json:"initial_message_type"`
+	InitialTemplate    *FlowTemplateRef         `json:"initial_template,omitempty"`
+	CompletionMessage  string                   `json:"completion_message"`
+	OnCompleteAction   string                   `json:"on_complete_action"`
+	CompletionConfig   models.JSONB             `json:"completion_config"`
+	TimeoutMessage     string                   `json:"timeout_message"`
+	StepTimeoutMins    int                      `json:"step_timeout_minutes,omitempty"`
+	StepTimeoutAction  models.StepTimeoutAction `json:"step_timeout_action,omitempty"`
+	CancelKeywords     []string                 `json:"cancel_keywords"`
+	PanelConfig        models.JSONB             `json:"panel_config"`
+	ActiveFrom         *time.Time               `json:"active_from,omitempty"`
+	ActiveUntil        *time.Time               `json:"active_until,omitempty"`
+	Steps              []FlowExportStep         `json:"steps"`
+}
+
+// FlowExportStep holds the exported fields of a flow step
+type FlowExportStep struct {
+	StepName        string              `json:"step_name"`
+	StepOrder       int                 `json:"step_order"`
+	Message         string              `json:"message"`
+	MessageType     models.FlowStepType `json:"message_type"`
+	Template        *FlowTemplateRef    `json:"template,omitempty"`
+	ApiConfig       models.JSONB        `json:"api_config"`
+	Buttons         models.JSONBArray   `json:"buttons"`
+	TransferConfig  models.JSONB        `json:"transfer_config"` // team_id is replaced by team_name
+	InputType       models.InputType    `json:"input_type"`
+	InputConfig     models.JSONB        `json:"input_config"`
+	ValidationRegex string              `json:"validation_regex"`
+	ValidationError string              `json:"validation_error"`
+	StoreAs         string              `json:"store_as"`
+	Sensitive       bool                `json:"sensitive,omitempty"`
+	NextStep        string              `json:"next_step"`
+	ConditionalNext models.JSONB        `json:"conditional_next"`
+	SkipCondition   string              `json:"skip_condition"`
+	RetryOnInvalid  bool                `json:"retry_on_invalid"`
+	MaxRetries      int                 `json:"max_retries"`
+	StepTimeoutMins int                 `json:"step_timeout_minutes,omitempty"`
+}
+
+// FlowTemplateRef identifies a message template by name and language
+type FlowTemplateRef struct {
+	Name     string `json:"name"`
+	Language string `json:"language"`
+}
+
+// ExportChatbotFlow returns a chatbot flow and its steps as a portable JSON document
+func (a *App) ExportChatbotFlow(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceFlowsChatbot, models.ActionRead, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Permission denied", nil, "")
+	}
+
+	id, err := parsePathUUID(r, "id", "flow")
+	if err != nil {
+		return nil
+	}
+
+	var flow models.ChatbotFlow
+	if err := a.DB.Where("id = ? AND organization_id = ?", id, orgID).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step_order ASC")
+		}).
+		First(&flow).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Flow not found", nil, "")
+	}
+
+	doc := FlowExportDocument{
+		Version:    FlowExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Flow: FlowExportData{
+			Name:               flow.Name,
+			Description:        flow.Description,
+			Enabled:            flow.IsEnabled,
+			TriggerType:        flow.TriggerType,
+			TriggerKeywords:    flow.TriggerKeywords,
+			TriggerButtonID:    flow.TriggerButtonID,
+			InitialMessage:     flow.InitialMessage,
+			InitialMessageType: flow.InitialMessageType,
+			InitialTemplate:    a.exportTemplateRef(orgID, flow.InitialTemplateID),
+			CompletionMessage:  flow.CompletionMessage,
+			OnCompleteAction:   flow.OnCompleteAction,
+			CompletionConfig:   flow.CompletionConfig,
+			TimeoutMessage:     flow.TimeoutMessage,
+			StepTimeoutMins:    flow.StepTimeoutMins,
+			StepTimeoutAction:  flow.StepTimeoutAction,
+			CancelKeywords:     flow.CancelKeywords,
+			PanelConfig:        flow.PanelConfig,
+			ActiveFrom:         flow.ActiveFrom,
+			ActiveUntil:        flow.ActiveUntil,
+			Steps:              make([]FlowExportStep, len(flow.Steps)),
+		},
+	}
+
+	for i, step := range flow.Steps {
+		doc.Flow.Steps[i] = FlowExportStep{
+			StepName:        step.StepName,
+			StepOrder:       step.StepOrder,
+			Message:         step.Message,
+			MessageType:     step.MessageType,
+			Template:        a.exportTemplateRef(orgID, step.TemplateID),
+			ApiConfig:       step.ApiConfig,
+			Buttons:         step.Buttons,
+			TransferConfig:  a.exportTransferConfig(orgID, step.TransferConfig),
+			InputType:       step.InputType,
+			InputConfig:     step.InputConfig,
+			ValidationRegex: step.ValidationRegex,
+			ValidationError: step.ValidationError,
+			StoreAs:         step.StoreAs,
+			Sensitive:       step.Sensitive,
+			NextStep:        step.NextStep,
+			ConditionalNext: step.ConditionalNext,
+			SkipCondition:   step.SkipCondition,
+			RetryOnInvalid:  step.RetryOnInvalid,
+			MaxRetries:      step.MaxRetries,
+			StepTimeoutMins: step.StepTimeoutMins,
+		}
+	}
+
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		a.Log.Error("Failed to marshal flow export", "error", err, "flow_id", flow.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to export flow", nil, "")
+	}
+
+	r.RequestCtx.Response.Header.Set("Content-Type", "application/json")
+	r.RequestCtx.Response.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", sanitizeFilename(flow.Name)+".flow.json"))
+	r.RequestCtx.SetBody(body)
+
+	return nil
+}
+
+// ImportChatbotFlow creates a new chatbot flow in the caller's organization from an exported document
+func (a *App) ImportChatbotFlow(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceFlowsChatbot, models.ActionWrite, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Permission denied", nil, "")
+	}
+
+	var doc FlowExportDocument
+	decoder := json.NewDecoder(bytes.NewReader(r.RequestCtx.PostBody()))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid flow document: "+err.Error(), nil, "")
+	}
+
+	if doc.Version != FlowExportVersion {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, fmt.Sprintf("Unsupported flow document version %d, expected %d", doc.Version, FlowExportVersion), nil, "")
+	}
+	// Keep the document's step order; next_step and conditional_next refer to
+	// steps by name, so they resolve against the freshly created steps as-is.
+	sort.SliceStable(doc.Flow.Steps, func(i, j int) bool {
+		return doc.Flow.Steps[i].StepOrder < doc.Flow.Steps[j].StepOrder
+	})
+	if errMsg := validateFlowExportData(&doc.Flow); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+
+	initialTemplateID, errMsg := a.resolveTemplateRef(orgID, doc.Flow.InitialTemplate)
+	if errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+
+	flowID := uuid.New()
+	flow := models.ChatbotFlow{
+		BaseModel:          models.BaseModel{ID: flowID},
+		OrganizationID:     orgID,
+		Name:               doc.Flow.Name,
+		Description:        doc.Flow.Description,
+		IsEnabled:          doc.Flow.Enabled,
+		TriggerType:        doc.Flow.TriggerType,
+		TriggerKeywords:    doc.Flow.TriggerKeywords,
+		TriggerButtonID:    doc.Flow.TriggerButtonID,
+		InitialMessage:     doc.Flow.InitialMessage,
+		InitialMessageType: doc.Flow.InitialMessageType,
+		InitialTemplateID:  initialTemplateID,
+		CompletionMessage:  doc.Flow.CompletionMessage,
+		OnCompleteAction:   doc.Flow.OnCompleteAction,
+		CompletionConfig:   doc.Flow.CompletionConfig,
+		TimeoutMessage:     doc.Flow.TimeoutMessage,
+		StepTimeoutMins:    doc.Flow.StepTimeoutMins,
+		StepTimeoutAction:  doc.Flow.StepTimeoutAction,
+		CancelKeywords:     doc.Flow.CancelKeywords,
+		PanelConfig:        doc.Flow.PanelConfig,
+		ActiveFrom:         doc.Flow.ActiveFrom,
+		ActiveUntil:        doc.Flow.ActiveUntil,
+	}
+	if flow.InitialMessageType == "" {
+		flow.InitialMessageType = models.FlowStepTypeText
+	}
+	if flow.StepTimeoutAction == "" {
+		flow.StepTimeoutAction = models.StepTimeoutNudge
+	}
+
+	steps := make([]models.ChatbotFlowStep, len(doc.Flow.Steps))
+	for i, stepDoc := range doc.Flow.Steps {
+		templateID, errMsg := a.resolveTemplateRef(orgID, stepDoc.Template)
+		if errMsg != "" {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+		}
+		transferConfig, errMsg := a.importTransferConfig(orgID, stepDoc.TransferConfig)
+		if errMsg != "" {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+		}
+
+		steps[i] = models.ChatbotFlowStep{
+			BaseModel:       models.BaseModel{ID: uuid.New()},
+			FlowID:          flowID,
+			StepName:        stepDoc.StepName,
+			StepOrder:       i + 1,
+			Message:         stepDoc.Message,
+			MessageType:     stepDoc.MessageType,
+			TemplateID:      templateID,
+			ApiConfig:       stepDoc.ApiConfig,
+			Buttons:         stepDoc.Buttons,
+			TransferConfig:  transferConfig,
+			InputType:       stepDoc.InputType,
+			InputConfig:     stepDoc.InputConfig,
+			ValidationRegex: stepDoc.ValidationRegex,
+			ValidationError: stepDoc.ValidationError,
+			StoreAs:         stepDoc.StoreAs,
+			Sensitive:       stepDoc.Sensitive,
+			NextStep:        stepDoc.NextStep,
+			ConditionalNext: stepDoc.ConditionalNext,
+			SkipCondition:   stepDoc.SkipCondition,
+			RetryOnInvalid:  stepDoc.RetryOnInvalid,
+			MaxRetries:      stepDoc.MaxRetries,
+			StepTimeoutMins: stepDoc.StepTimeoutMins,
+		}
+		if steps[i].MessageType == "" {
+			steps[i].MessageType = models.FlowStepTypeText
+		}
+	}
+
+	// Select("*") writes false/zero values as-is instead of letting column defaults replace them
+	err = a.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("*").Create(&flow).Error; err != nil {
+			return err
+		}
+		for i := range steps {
+			if err := tx.Select("*").Create(&steps[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		a.Log.Error("Failed to import flow", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to import flow", nil, "")
+	}
+
+	a.InvalidateChatbotFlowsCache(orgID)
+
+	return r.SendEnvelope(map[string]any{
+		"id":      flowID.String(),
+		"message": "Flow imported successfully",
+	})
+}
+
+// validateFlowExportData checks that an imported flow is complete and its step
+// references resolve. Steps must already be in step_order.
+func validateFlowExportData(flow *FlowExportData) string {
+	if flow.Name == "" {
+		return "Flow name is required"
+	}
+	if flow.TriggerType == "" {
+		flow.TriggerType = models.FlowTriggerKeyword
+	}
+	if errMsg := validateFlowTrigger(flow.TriggerType, flow.TriggerButtonID); errMsg != "" {
+		return errMsg
+	}
+	if errMsg := validateFlowStepTimeouts(flow.StepTimeoutMins, flow.StepTimeoutAction, nil); errMsg != "" {
+		return errMsg
+	}
+
+	names := make(map[string]bool, len(flow.Steps))
+	for _, step := range flow.Steps {
+		if step.StepName == "" {
+			return "Every step needs a step_name"
+		}
+		if names[step.StepName] {
+			return fmt.Sprintf("Duplicate step_name %q", step.StepName)
+		}
+		if step.StepTimeoutMins < 0 || step.StepTimeoutMins > maxStepTimeoutMins {
+			return fmt.Sprintf("Step %q step_timeout_minutes must be between 0 and %d", step.StepName, maxStepTimeoutMins)
+		}
+		names[step.StepName] = true
+	}
+
+	routing := make([]FlowStepRequest, len(flow.Steps))
+	for i, step := range flow.Steps {
+		routing[i] = flowStepRoutingRequest(step.StepName, step.NextStep, step.ConditionalNext, step.Buttons)
+	}
+	if errMsg := validateFlowStepLinks(routing); errMsg != "" {
+		return errMsg
+	}
+	if errMsg := validateFlowStepButtons(routing); errMsg != "" {
+		return errMsg
+	}
+
+	for _, step := range flow.Steps {
+		for option, target := range step.ConditionalNext {
+			name, ok := target.(string)
+			if !ok {
+				return fmt.Sprintf("Step %q has a non-string conditional_next target for %q", step.StepName, option)
+			}
+			if name != "" && !names[name] {
+				return fmt.Sprintf("Step %q has conditional_next target %q which does not exist", step.StepName, name)
+			}
+		}
+	}
+	return ""
+}
+
+// exportTemplateRef converts a template ID into a portable name/language reference
+func (a *App) exportTemplateRef(orgID uuid.UUID, templateID *uuid.UUID) *FlowTemplateRef {
+	if templateID == nil {
+		return nil
+	}
+	var tmpl models.Template
+	if err := a.DB.Where("id = ? AND organization_id = ?", *templateID, orgID).First(&tmpl).Error; err != nil {
+		return nil
+	}
+	return &FlowTemplateRef{Name: tmpl.Name, Language: tmpl.Language}
+}
+
+// resolveTemplateRef finds the organization's template matching an exported reference
+func (a *App) resolveTemplateRef(orgID uuid.UUID, ref *FlowTemplateRef) (*uuid.UUID, string) {
+	if ref == nil {
+		return nil, ""
+	}
+	var tmpl models.Template
+	if err := a.DB.Where("organization_id = ? AND name = ? AND language = ?", orgID, ref.Name, ref.Language).
+		First(&tmpl).Error; err != nil {
+		return nil, fmt.Sprintf("Template %q (%s) not found", ref.Name, ref.Language)
+	}
+	return &tmpl.ID, ""
+}
+
+// exportTransferConfig replaces a transfer step's team_id with the team's name
+func (a *App) exportTransferConfig(orgID uuid.UUID, cfg models.JSONB) models.JSONB {
+	teamID, ok := cfg["team_id"].(string)
+	if !ok || teamID == "" || teamID == "_general" {
+		return cfg
+	}
+
+	exported := make(models.JSONB, len(cfg))
+	for k, v := range cfg {
+		exported[k] = v
+	}
+	delete(exported, "team_id")
+
+	var team models.Team
+	if err := a.DB.Where("id = ? AND organization_id = ?", teamID, orgID).First(&team).Error; err == nil {
+		exported["team_name"] = team.Name
+	}
+	return exported
+}
+
+// importTransferConfig replaces a transfer step's team_name with the matching team's ID
+func (a *App) importTransferConfig(orgID uuid.UUID, cfg models.JSONB) (models.JSONB, string) {
+	teamName, ok := cfg["team_name"].(string)
+	if !ok {
+		return cfg, ""
+	}
+
+	imported := make(models.JSONB, len(cfg))
+	for k, v := range cfg {
+		imported[k] = v
+	}
+	delete(imported, "team_name")
+
+	var team models.Team
+	if err := a.DB.Where("organization_id = ? AND name = ?", orgID, teamName).First(&team).Error; err != nil {
+		return nil, fmt.Sprintf("Team %q not found", teamName)
+	}
+	imported["team_id"] = team.ID.String()
+	return imported, ""
+}
@@ -0,0 +1,294 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/handlers"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// newFlowEditor creates a user with full flows.chatbot permissions.
+func newFlowEditor(t *testing.T, app *handlers.App, orgID uuid.UUID) *models.User {
+	t.Helper()
+	role := testutil.CreateTestRole(t, app.DB, orgID, "flow-editor", getChatbotFlowPermissions(t, app))
+	return testutil.CreateTestUser(t, app.DB, orgID,
+		testutil.WithEmail(testutil.UniqueEmail("flow-export")),
+		testutil.WithRoleID(&role.ID),
+	)
+}
+
+// exportFlow calls ExportChatbotFlow and decodes the resulting document.
+func exportFlow(t *testing.T, app *handlers.App, orgID, userID, flowID uuid.UUID) (handlers.FlowExportDocument, []byte) {
+	t.Helper()
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, orgID, userID)
+	testutil.SetPathParam(req, "id", flowID.String())
+
+	require.NoError(t, app.ExportChatbotFlow(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	body := testutil.GetResponseBody(req)
+	var doc handlers.FlowExportDocument
+	require.NoError(t, json.Unmarshal(body, &doc))
+	return doc, body
+}
+
+// importFlow posts a raw document to ImportChatbotFlow and returns the status and new flow ID.
+func importFlow(t *testing.T, app *handlers.App, orgID, userID uuid.UUID, body []byte) (int, uuid.UUID) {
+	t.Helper()
+	req := testutil.NewJSONRequest(t, nil)
+	req.RequestCtx.Request.SetBody(body)
+	testutil.SetAuthContext(req, orgID, userID)
+
+	require.NoError(t, app.ImportChatbotFlow(req))
+	status := testutil.GetResponseStatusCode(req)
+	if status != fasthttp.StatusOK {
+		return status, uuid.Nil
+	}
+
+	var resp struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	return status, uuid.MustParse(resp.Data.ID)
+}
+
+func TestApp_ExportImportChatbotFlow_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	srcOrg := testutil.CreateTestOrganization(t, app.DB)
+	dstOrg := testutil.CreateTestOrganization(t, app.DB)
+	srcUser := newFlowEditor(t, app, srcOrg.ID)
+	dstUser := newFlowEditor(t, app, dstOrg.ID)
+
+	// Same-named template and team exist in both orgs with different IDs
+	srcTemplate := testutil.CreateTestTemplate(t, app.DB, srcOrg.ID, "src-account")
+	dstTemplate := &models.Template{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  dstOrg.ID,
+		WhatsAppAccount: "dst-account",
+		Name:            srcTemplate.Name,
+		Language:        srcTemplate.Language,
+		Status:          string(models.TemplateStatusApproved),
+		BodyContent:     "Hello {{1}}",
+	}
+	require.NoError(t, app.DB.Create(dstTemplate).Error)
+	srcTeam := &models.Team{BaseModel: models.BaseModel{ID: uuid.New()}, OrganizationID: srcOrg.ID, Name: "Billing", IsActive: true}
+	dstTeam := &models.Team{BaseModel: models.BaseModel{ID: uuid.New()}, OrganizationID: dstOrg.ID, Name: "Billing", IsActive: true}
+	require.NoError(t, app.DB.Create(srcTeam).Error)
+	require.NoError(t, app.DB.Create(dstTeam).Error)
+
+	activeFrom := time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)
+	flow := &models.ChatbotFlow{
+		BaseModel:         models.BaseModel{ID: uuid.New()},
+		OrganizationID:    srcOrg.ID,
+		Name:              "Returns",
+		Description:       "Handle return requests",
+		IsEnabled:         true,
+		TriggerKeywords:   models.StringArray{"return", "refund"},
+		InitialMessage:    "Let's sort out your return.",
+		InitialTemplateID: &srcTemplate.ID,
+		CompletionMessage: "Thanks {{name}}!",
+		CancelKeywords:    models.StringArray{"stop"},
+		PanelConfig:       models.JSONB{"sections": []any{}},
+		ActiveFrom:        &activeFrom,
+	}
+	require.NoError(t, app.DB.Create(flow).Error)
+
+	steps := []*models.ChatbotFlowStep{
+		{
+			StepName:        "reason",
+			StepOrder:       1,
+			Message:         "Why are you returning it?",
+			MessageType:     models.FlowStepTypeButtons,
+			InputType:       models.InputTypeButton,
+			Buttons:         models.JSONBArray{map[string]any{"id": "damaged", "title": "Damaged"}, map[string]any{"id": "other", "title": "Other"}},
+			StoreAs:         "reason",
+			ConditionalNext: models.JSONB{"damaged": "handoff", "default": "name"},
+			RetryOnInvalid:  true,
+			MaxRetries:      2,
+		},
+		{
+			StepName:        "name",
+			StepOrder:       2,
+			Message:         "What's your name?",
+			MessageType:     models.FlowStepTypeText,
+			InputType:       models.InputTypeText,
+			ValidationRegex: "^[A-Za-z ]+$",
+			StoreAs:         "name",
+			NextStep:        "handoff",
+			MaxRetries:      3,
+		},
+		{
+			StepName:       "handoff",
+			StepOrder:      3,
+			Message:        "Connecting you to billing",
+			MessageType:    models.FlowStepTypeTransfer,
+			TemplateID:     &srcTemplate.ID,
+			TransferConfig: models.JSONB{"team_id": srcTeam.ID.String(), "notes": "Reason: {{reason}}"},
+			MaxRetries:     3,
+		},
+	}
+	for _, step := range steps {
+		step.ID = uuid.New()
+		step.FlowID = flow.ID
+		require.NoError(t, app.DB.Create(step).Error)
+	}
+	// RetryOnInvalid defaults to true in the DB, so set false explicitly
+	require.NoError(t, app.DB.Model(steps[1]).Update("retry_on_invalid", false).Error)
+
+	original, body := exportFlow(t, app, srcOrg.ID, srcUser.ID, flow.ID)
+	assert.Equal(t, handlers.FlowExportVersion, original.Version)
+	assert.NotContains(t, string(body), srcOrg.ID.String())
+	assert.NotContains(t, string(body), flow.ID.String())
+	assert.NotContains(t, string(body), srcTemplate.ID.String())
+	assert.NotContains(t, string(body), srcTeam.ID.String())
+	assert.Equal(t, "Billing", original.Flow.Steps[2].TransferConfig["team_name"])
+
+	status, newFlowID := importFlow(t, app, dstOrg.ID, dstUser.ID, body)
+	require.Equal(t, fasthttp.StatusOK, status)
+	assert.NotEqual(t, flow.ID, newFlowID)
+
+	var imported models.ChatbotFlow
+	require.NoError(t, app.DB.Preload("Steps").First(&imported, "id = ?", newFlowID).Error)
+	assert.Equal(t, dstOrg.ID, imported.OrganizationID)
+	require.NotNil(t, imported.InitialTemplateID)
+	assert.Equal(t, dstTemplate.ID, *imported.InitialTemplateID)
+	for _, step := range imported.Steps {
+		if step.StepName == "handoff" {
+			assert.Equal(t, dstTeam.ID.String(), step.TransferConfig["team_id"])
+		}
+	}
+
+	// Re-exporting the imported flow yields the same document
+	roundTrip, _ := exportFlow(t, app, dstOrg.ID, dstUser.ID, newFlowID)
+	roundTrip.ExportedAt = original.ExportedAt
+	assert.Equal(t, original, roundTrip)
+}
+
+func TestApp_ImportChatbotFlow_Validation(t *testing.T) {
+	t.Parallel()
+
+	validStep := map[string]any{"step_name": "ask", "step_order": 1, "message": "Hi", "next_step": ""}
+
+	tests := []struct {
+		name string
+		doc  map[string]any
+	}{
+		{
+			name: "unsupported version",
+			doc:  map[string]any{"version": 99, "flow": map[string]any{"name": "Flow", "steps": []any{validStep}}},
+		},
+		{
+			name: "unknown top-level field",
+			doc:  map[string]any{"version": 1, "organization_id": uuid.New().String(), "flow": map[string]any{"name": "Flow"}},
+		},
+		{
+			name: "unknown step field",
+			doc: map[string]any{"version": 1, "flow": map[string]any{
+				"name":  "Flow",
+				"steps": []any{map[string]any{"step_name": "ask", "id": uuid.New().String()}},
+			}},
+		},
+		{
+			name: "dangling next_step",
+			doc: map[string]any{"version": 1, "flow": map[string]any{
+				"name":  "Flow",
+				"steps": []any{map[string]any{"step_name": "ask", "next_step": "missing"}},
+			}},
+		},
+		{
+			name: "dangling conditional_next",
+			doc: map[string]any{"version": 1, "flow": map[string]any{
+				"name":  "Flow",
+				"steps": []any{map[string]any{"step_name": "ask", "conditional_next": map[string]any{"yes": "missing"}}},
+			}},
+		},
+		{
+			name: "steps loop with no exit",
+			doc: map[string]any{"version": 1, "flow": map[string]any{
+				"name": "Flow",
+				"steps": []any{
+					map[string]any{"step_name": "a", "step_order": 1, "next_step": "b"},
+					map[string]any{"step_name": "b", "step_order": 2, "next_step": "a"},
+				},
+			}},
+		},
+		{
+			name: "duplicate button id",
+			doc: map[string]any{"version": 1, "flow": map[string]any{
+				"name": "Flow",
+				"steps": []any{map[string]any{"step_name": "ask", "buttons": []any{
+					map[string]any{"id": "yes", "title": "Yes"},
+					map[string]any{"id": "yes", "title": "Sure"},
+				}}},
+			}},
+		},
+		{
+			name: "dangling button next_step",
+			doc: map[string]any{"version": 1, "flow": map[string]any{
+				"name": "Flow",
+				"steps": []any{map[string]any{"step_name": "ask", "buttons": []any{
+					map[string]any{"id": "yes", "title": "Yes", "next_step": "missing"},
+				}}},
+			}},
+		},
+		{
+			name: "unknown template",
+			doc: map[string]any{"version": 1, "flow": map[string]any{
+				"name":             "Flow",
+				"initial_template": map[string]any{"name": "does-not-exist", "language": "en"},
+			}},
+		},
+		{
+			name: "missing name",
+			doc:  map[string]any{"version": 1, "flow": map[string]any{"steps": []any{validStep}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			app := newTestApp(t)
+			org := testutil.CreateTestOrganization(t, app.DB)
+			user := newFlowEditor(t, app, org.ID)
+
+			body, err := json.Marshal(tt.doc)
+			require.NoError(t, err)
+
+			status, _ := importFlow(t, app, org.ID, user.ID, body)
+			assert.Equal(t, fasthttp.StatusBadRequest, status)
+
+			var count int64
+			app.DB.Model(&models.ChatbotFlow{}).Where("organization_id = ?", org.ID).Count(&count)
+			assert.Zero(t, count)
+		})
+	}
+}
+
+func TestApp_ExportChatbotFlow_OtherOrgNotFound(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	otherOrg := testutil.CreateTestOrganization(t, app.DB)
+	user := newFlowEditor(t, app, org.ID)
+	flow := createTestChatbotFlow(t, app, otherOrg.ID, "Foreign")
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", flow.ID.String())
+
+	require.NoError(t, app.ExportChatbotFlow(req))
+	assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+const (
+	// flowWebhookMaxAttempts is how many times a completion webhook is tried before it's marked failed
+	flowWebhookMaxAttempts = 3
+	// flowWebhookTimeout bounds each delivery attempt
+	flowWebhookTimeout = 10 * time.Second
+)
+
+// flowWebhookBackoff is the wait before retry n (1s, 2s, ...)
+func flowWebhookBackoff(retry int) time.Duration {
+	return time.Duration(1<<(retry-1)) * time.Second
+}
+
+// flowWebhookRequest is a completion webhook built from the session at completion time
+type flowWebhookRequest struct {
+	Method  string
+	URL     string
+	Body    []byte
+	Headers map[string]string
+}
+
+// FlowWebhookDeliveryResponse represents a completion webhook delivery in API responses
+type FlowWebhookDeliveryResponse struct {
+	ID             uuid.UUID                    `json:"id"`
+	SessionID      uuid.UUID                    `json:"session_id"`
+	URL            string                       `json:"url"`
+	Status         models.WebhookDeliveryStatus `json:"status"`
+	Attempts       int                          `json:"attempts"`
+	LastStatusCode int                          `json:"last_status_code,omitempty"`
+	LastError      string                       `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time                   `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time                    `json:"created_at"`
+}
+
+// deliverFlowCompletionWebhook records a delivery for the flow's completion
+// webhook and sends it in the background with retries. When the completion
+// config sets strict_session_data, a session whose data failed validation is
+// recorded as a failed delivery and not sent.
+func (a *App) deliverFlowCompletionWebhook(flow *models.ChatbotFlow, session *models.ChatbotSession, contact *models.Contact, validationErrs []SessionDataError) {
+	// Build the request now so later session changes don't leak into the payload
+	req, err := a.buildFlowWebhookRequest(flow, session, contact, validationErrs)
+	if err != nil {
+		a.Log.Error("Failed to build flow completion webhook", "error", err, "flow_id", flow.ID)
+		return
+	}
+
+	delivery := models.FlowWebhookDelivery{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: flow.OrganizationID,
+		FlowID:         flow.ID,
+		SessionID:      session.ID,
+		URL:            req.URL,
+		Status:         models.WebhookDeliveryPending,
+	}
+
+	strict, _ := flow.CompletionConfig["strict_session_data"].(bool)
+	if strict && len(validationErrs) > 0 {
+		msgs := make([]string, len(validationErrs))
+		for i, e := range validationErrs {
+			msgs[i] = e.Error()
+		}
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = "session data validation failed: " + strings.Join(msgs, "; ")
+		if err := a.DB.Create(&delivery).Error; err != nil {
+			a.Log.Error("Failed to record flow webhook delivery", "error", err, "flow_id", flow.ID)
+		}
+		a.Log.Warn("Flow completion webhook skipped, session data is invalid", "flow_id", flow.ID, "session_id", session.ID)
+		return
+	}
+
+	if err := a.DB.Create(&delivery).Error; err != nil {
+		a.Log.Error("Failed to record flow webhook delivery", "error", err, "flow_id", flow.ID)
+		return
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.sendFlowWebhookWithRetry(&delivery, req)
+	}()
+}
+
+// buildFlowWebhookRequest renders the URL, body and headers from the flow's
+// completion config. The default body lists any session data validation errors.
+func (a *App) buildFlowWebhookRequest(flow *models.ChatbotFlow, session *models.ChatbotSession, contact *models.Contact, validationErrs []SessionDataError) (*flowWebhookRequest, error) {
+	config := flow.CompletionConfig
+
+	// Get webhook URL (required)
+	webhookURL, ok := config["url"].(string)
+	if !ok || webhookURL == "" {
+		return nil, fmt.Errorf("webhook URL not configured")
+	}
+
+	req := &flowWebhookRequest{
+		Method:  "POST",
+		URL:     a.replaceVariables(webhookURL, session.SessionData),
+		Headers: map[string]string{},
+	}
+	if m, ok := config["method"].(string); ok && m != "" {
+		req.Method = strings.ToUpper(m)
+	}
+
+	// Allow custom body template if provided
+	if bodyTemplate, ok := config["body"].(string); ok && bodyTemplate != "" {
+		req.Body = []byte(a.replaceVariables(bodyTemplate, session.SessionData))
+	} else {
+		payload := map[string]interface{}{
+			"flow_id":      flow.ID.String(),
+			"flow_name":    flow.Name,
+			"session_id":   session.ID.String(),
+			"phone_number": session.PhoneNumber,
+			"contact_id":   contact.ID.String(),
+			"contact_name": contact.ProfileName,
+			"session_data": session.SessionData,
+			"completed_at": time.Now().UTC().Format(time.RFC3339),
+		}
+		if len(validationErrs) > 0 {
+			payload["validation_errors"] = validationErrs
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+
+	// Add custom headers if configured
+	if headers, ok := config["headers"].(map[string]interface{}); ok {
+		for key, value := range headers {
+			if strVal, ok := value.(string); ok {
+				req.Headers[key] = a.replaceVariables(strVal, session.SessionData)
+			}
+		}
+	}
+
+	// Sign the body with the organization's secret so receivers can verify it
+	if secret := a.flowWebhookSecret(flow.OrganizationID); secret != "" {
+		req.Headers["X-Webhook-Signature"] = computeHMACSignature(req.Body, secret)
+	}
+
+	return req, nil
+}
+
+// sendFlowWebhookWithRetry sends the webhook, retrying with backoff on network
+// errors and 5xx responses, and records every attempt on the delivery
+func (a *App) sendFlowWebhookWithRetry(delivery *models.FlowWebhookDelivery, req *flowWebhookRequest) {
+	for attempt := 1; attempt <= flowWebhookMaxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(flowWebhookBackoff(attempt - 1))
+		}
+
+		statusCode, err := a.sendFlowWebhookAttempt(req)
+		updates := map[string]interface{}{
+			"attempts":         attempt,
+			"last_status_code": statusCode,
+			"last_error":       "",
+		}
+		if err != nil {
+			updates["last_error"] = err.Error()
+		}
+
+		retryable := err != nil && (statusCode == 0 || statusCode >= 500)
+		switch {
+		case err == nil:
+			now := time.Now()
+			updates["status"] = models.WebhookDeliveryDelivered
+			updates["delivered_at"] = now
+		case !retryable || attempt == flowWebhookMaxAttempts:
+			updates["status"] = models.WebhookDeliveryFailed
+		}
+		if dbErr := a.DB.Model(delivery).Updates(updates).Error; dbErr != nil {
+			a.Log.Error("Failed to update flow webhook delivery", "error", dbErr, "delivery_id", delivery.ID)
+		}
+
+		if err == nil {
+			a.Log.Info("Flow completion webhook delivered", "flow_id", delivery.FlowID, "session_id", delivery.SessionID, "status", statusCode)
+			return
+		}
+		a.Log.Warn("Flow completion webhook attempt failed",
+			"error", err,
+			"flow_id", delivery.FlowID,
+			"session_id", delivery.SessionID,
+			"attempt", attempt,
+			"max_attempts", flowWebhookMaxAttempts,
+		)
+		if !retryable {
+			return
+		}
+	}
+}
+
+// sendFlowWebhookAttempt makes one delivery attempt and returns the response
+// status code (0 if no response was received)
+func (a *App) sendFlowWebhookAttempt(req *flowWebhookRequest) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), flowWebhookTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "Whatomate-Webhook/1.0")
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	resp, err := a.HTTPClient.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.StatusCode, nil
+}
+
+// flowWebhookSecret returns the organization's secret for signing flow completion webhooks
+func (a *App) flowWebhookSecret(orgID uuid.UUID) string {
+	var org models.Organization
+	if err := a.DB.Where("id = ?", orgID).First(&org).Error; err != nil {
+		return ""
+	}
+	secret, _ := org.Settings["flow_webhook_secret"].(string)
+	return secret
+}
+
+// ListFlowWebhookDeliveries returns the completion webhook deliveries for a flow, newest first
+func (a *App) ListFlowWebhookDeliveries(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceFlowsChatbot, models.ActionRead); err != nil {
+		return nil
+	}
+
+	id, err := parsePathUUID(r, "id", "flow")
+	if err != nil {
+		return nil
+	}
+
+	if _, err := findByIDAndOrg[models.ChatbotFlow](a.DB, r, id, orgID, "Flow"); err != nil {
+		return nil
+	}
+
+	pg := parsePagination(r)
+	query := a.DB.Model(&models.FlowWebhookDelivery{}).Where("flow_id = ? AND organization_id = ?", id, orgID)
+	if status := string(r.RequestCtx.QueryArgs().Peek("status")); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var deliveries []models.FlowWebhookDelivery
+	if err := pg.Apply(query.Order("created_at DESC")).Find(&deliveries).Error; err != nil {
+		a.Log.Error("Failed to list flow webhook deliveries", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list webhook deliveries", nil, "")
+	}
+
+	response := make([]FlowWebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		response[i] = FlowWebhookDeliveryResponse{
+			ID:             d.ID,
+			SessionID:      d.SessionID,
+			URL:            d.URL,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			LastStatusCode: d.LastStatusCode,
+			LastError:      d.LastError,
+			DeliveredAt:    d.DeliveredAt,
+			CreatedAt:      d.CreatedAt,
+		}
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"deliveries": response,
+		"total":      total,
+		"page":       pg.Page,
+		"limit":      pg.Limit,
+	})
+}
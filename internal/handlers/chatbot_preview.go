@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/contactutil"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/pkg/whatsapp"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// PreviewSendRequest selects a keyword rule or flow step to send to a test number
+type PreviewSendRequest struct {
+	PhoneNumber   string `json:"phone_number"`
+	AccountName   string `json:"account_name"` // Defaults to the rule's or flow's account
+	KeywordRuleID string `json:"keyword_rule_id"`
+	FlowID        string `json:"flow_id"`
+	StepName      string `json:"step_name"` // Defaults to the flow's first step
+}
+
+// previewMessage is a rule or step response rendered for sending
+type previewMessage struct {
+	Text       string
+	Buttons    []whatsapp.Button
+	Template   *models.Template
+	BodyParams map[string]string
+}
+
+// errPreviewUnsupported is returned for responses that only make sense inside a live conversation
+var errPreviewUnsupported = errors.New("this response type can't be previewed")
+
+// PreviewSendToNumber sends a keyword rule's response or a flow step's message
+// to a test number so it can be checked on a real device before going live.
+// Preview sends bypass the messages table, so they never show up in
+// conversations, contacts or analytics.
+func (a *App) PreviewSendToNumber(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	var req PreviewSendRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	phone := contactutil.NormalizePhone(req.PhoneNumber)
+	if phone == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "phone_number is required", nil, "")
+	}
+	if (req.KeywordRuleID == "") == (req.FlowID == "") {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Provide either keyword_rule_id or flow_id", nil, "")
+	}
+
+	var (
+		preview     *previewMessage
+		accountName string
+	)
+	if req.KeywordRuleID != "" {
+		if err := a.requirePermission(r, userID, models.ResourceChatbotKeywords, models.ActionWrite); err != nil {
+			return nil
+		}
+		ruleID, err := uuid.Parse(req.KeywordRuleID)
+		if err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid keyword_rule_id", nil, "")
+		}
+		var rule models.KeywordRule
+		if err := a.DB.Where("id = ? AND organization_id = ?", ruleID, orgID).First(&rule).Error; err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Keyword rule not found", nil, "")
+		}
+		accountName = rule.WhatsAppAccount
+		preview, err = a.renderKeywordRulePreview(&rule)
+		if err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+		}
+	} else {
+		if err := a.requirePermission(r, userID, models.ResourceFlowsChatbot, models.ActionWrite); err != nil {
+			return nil
+		}
+		flowID, err := uuid.Parse(req.FlowID)
+		if err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid flow_id", nil, "")
+		}
+		var flow models.ChatbotFlow
+		if err := a.DB.Where("id = ? AND organization_id = ?", flowID, orgID).
+			Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("step_order ASC") }).
+			First(&flow).Error; err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Flow not found", nil, "")
+		}
+		step := findPreviewStep(&flow, req.StepName)
+		if step == nil {
+			return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Step not found", nil, "")
+		}
+		accountName = flow.WhatsAppAccount
+		preview, err = renderFlowStepPreview(step)
+		if err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+		}
+	}
+
+	if strings.TrimSpace(req.AccountName) != "" {
+		accountName = strings.TrimSpace(req.AccountName)
+	}
+	account, err := a.resolveWhatsAppAccount(orgID, accountName)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	wamid, err := a.sendPreview(ctx, account, phone, preview)
+	if err != nil {
+		a.Log.Warn("Preview send failed", "error", err, "org_id", orgID, "phone", phone)
+		return r.SendErrorEnvelope(fasthttp.StatusBadGateway, "Failed to send preview: "+err.Error(), nil, "")
+	}
+
+	a.Log.Info("Preview sent", "org_id", orgID, "user_id", userID, "account", account.Name, "phone", phone)
+	return r.SendEnvelope(map[string]any{
+		"message_id": wamid,
+		"account":    account.Name,
+		"preview":    true,
+	})
+}
+
+// renderKeywordRulePreview renders a keyword rule's response as the chatbot would send it.
+// Rules with variants render their first variant that can be sent.
+func (a *App) renderKeywordRulePreview(rule *models.KeywordRule) (*previewMessage, error) {
+	content, _ := selectKeywordContent(rule.ResponseContent, 0)
+	body, _ := content["body"].(string)
+
+	switch rule.ResponseType {
+	case models.ResponseTypeTemplate:
+		var resp KeywordResponse
+		if !applyKeywordTemplateContent(&resp, rule.ResponseContent) {
+			return nil, errors.New("keyword rule has no valid template")
+		}
+		if resp.TemplateID == uuid.Nil {
+			return &previewMessage{Text: resp.Body}, nil
+		}
+		var template models.Template
+		if err := a.DB.Where("id = ? AND organization_id = ?", resp.TemplateID, rule.OrganizationID).First(&template).Error; err != nil {
+			return nil, errors.New("keyword rule template not found")
+		}
+		return &previewMessage{Template: &template, BodyParams: resp.BodyParams}, nil
+	case models.ResponseTypeText, models.ResponseTypeTransfer, "":
+		if body == "" {
+			return nil, errors.New("keyword rule has no response text")
+		}
+		preview := &previewMessage{Text: body}
+		if rule.ResponseType != models.ResponseTypeTransfer {
+			if buttons, ok := content["buttons"].([]interface{}); ok {
+				preview.Buttons = previewButtons(buttons)
+			}
+		}
+		return preview, nil
+	default:
+		return nil, errPreviewUnsupported
+	}
+}
+
+// findPreviewStep returns the named step, or the first step when name is empty
+func findPreviewStep(flow *models.ChatbotFlow, name string) *models.ChatbotFlowStep {
+	for i := range flow.Steps {
+		if name == "" || flow.Steps[i].StepName == name {
+			return &flow.Steps[i]
+		}
+	}
+	return nil
+}
+
+// renderFlowStepPreview renders a step's static message. Steps that depend on
+// live data (API fetches, WhatsApp Flows) can't be previewed.
+func renderFlowStepPreview(step *models.ChatbotFlowStep) (*previewMessage, error) {
+	switch step.MessageType {
+	case models.FlowStepTypeText, models.FlowStepTypeButtons, models.FlowStepTypeTransfer, "":
+	default:
+		return nil, errPreviewUnsupported
+	}
+
+	// There's no session yet, so variables render as they would at the start of a conversation
+	text := processTemplate(step.Message, nil)
+	if text == "" {
+		return nil, errors.New("step has no message")
+	}
+	preview := &previewMessage{Text: text}
+	if step.MessageType == models.FlowStepTypeButtons {
+		preview.Buttons = previewButtons(step.Buttons)
+	}
+	return preview, nil
+}
+
+// previewButtons converts configured reply buttons, skipping URL and phone buttons
+func previewButtons(buttons []interface{}) []whatsapp.Button {
+	var out []whatsapp.Button
+	for i, btn := range buttons {
+		btnMap, ok := btn.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if btnType, _ := btnMap["type"].(string); btnType == "url" || btnType == "phone" {
+			continue
+		}
+		title, _ := btnMap["title"].(string)
+		if title == "" {
+			continue
+		}
+		id, _ := btnMap["id"].(string)
+		if id == "" {
+			id = fmt.Sprintf("btn_%d", i+1)
+		}
+		out = append(out, whatsapp.Button{ID: id, Title: title})
+		if len(out) == 10 {
+			break
+		}
+	}
+	return out
+}
+
+// sendPreview sends the rendered preview straight through the WhatsApp client
+func (a *App) sendPreview(ctx context.Context, account *models.WhatsAppAccount, phone string, preview *previewMessage) (string, error) {
+	waAccount := a.toWhatsAppAccount(account)
+	switch {
+	case preview.Template != nil:
+		components := whatsapp.BodyParamsToComponents(preview.BodyParams)
+		return a.WhatsApp.SendTemplateMessage(ctx, waAccount, phone, preview.Template.Name, preview.Template.Language, components)
+	case len(preview.Buttons) > 0:
+		return a.WhatsApp.SendInteractiveButtons(ctx, waAccount, phone, preview.Text, preview.Buttons)
+	default:
+		return a.WhatsApp.SendTextMessage(ctx, waAccount, phone, preview.Text)
+	}
+}
@@ -0,0 +1,177 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/handlers"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestApp_PreviewSendToNumber(t *testing.T) {
+	t.Parallel()
+
+	t.Run("keyword rule with buttons is sent and not recorded", func(t *testing.T) {
+		t.Parallel()
+		mockServer := newMockWhatsAppServer()
+		defer mockServer.close()
+
+		app := newMsgTestApp(t, mockServer)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := createTestAccount(t, app, org.ID)
+
+		rule := &models.KeywordRule{
+			BaseModel:       models.BaseModel{ID: uuid.New()},
+			OrganizationID:  org.ID,
+			WhatsAppAccount: account.Name,
+			Name:            "Pricing",
+			IsEnabled:       true,
+			Keywords:        models.StringArray{"price"},
+			MatchType:       models.MatchTypeContains,
+			ResponseType:    models.ResponseTypeText,
+			ResponseContent: models.JSONB{
+				"body": "Which plan are you interested in?",
+				"buttons": []interface{}{
+					map[string]interface{}{"id": "basic", "title": "Basic"},
+					map[string]interface{}{"id": "pro", "title": "Pro"},
+				},
+			},
+		}
+		require.NoError(t, app.DB.Create(rule).Error)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"phone_number":    "+1 (555) 010-2030",
+			"keyword_rule_id": rule.ID.String(),
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.PreviewSendToNumber(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				MessageID string `json:"message_id"`
+				Account   string `json:"account"`
+				Preview   bool   `json:"preview"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.True(t, resp.Data.Preview)
+		assert.Equal(t, account.Name, resp.Data.Account)
+		assert.Equal(t, mockServer.nextMessageID, resp.Data.MessageID)
+
+		require.Len(t, mockServer.sentMessages, 1)
+		sent := mockServer.sentMessages[0]
+		assert.Equal(t, "15550102030", sent["to"])
+		assert.Equal(t, "interactive", sent["type"])
+
+		// Previews must not show up as conversations or in analytics
+		var messages, contacts int64
+		require.NoError(t, app.DB.Model(&models.Message{}).Where("organization_id = ?", org.ID).Count(&messages).Error)
+		require.NoError(t, app.DB.Model(&models.Contact{}).Where("organization_id = ?", org.ID).Count(&contacts).Error)
+		assert.Zero(t, messages)
+		assert.Zero(t, contacts)
+
+		statsReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(statsReq, org.ID, user.ID)
+		require.NoError(t, app.GetDashboardStats(statsReq))
+		var statsResp struct {
+			Data struct {
+				Stats handlers.DashboardStats `json:"stats"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(statsReq), &statsResp))
+		assert.Zero(t, statsResp.Data.Stats.TotalMessages)
+		assert.Zero(t, statsResp.Data.Stats.TotalContacts)
+	})
+
+	t.Run("flow step by name", func(t *testing.T) {
+		t.Parallel()
+		mockServer := newMockWhatsAppServer()
+		defer mockServer.close()
+
+		app := newMsgTestApp(t, mockServer)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := createTestAccount(t, app, org.ID)
+
+		flowID := uuid.New()
+		flow := &models.ChatbotFlow{
+			BaseModel:       models.BaseModel{ID: flowID},
+			OrganizationID:  org.ID,
+			WhatsAppAccount: account.Name,
+			Name:            "Onboarding",
+			IsEnabled:       true,
+			Steps: []models.ChatbotFlowStep{
+				{BaseModel: models.BaseModel{ID: uuid.New()}, FlowID: flowID, StepName: "ask_name", StepOrder: 1, Message: "What is your name?", MessageType: models.FlowStepTypeText},
+				{BaseModel: models.BaseModel{ID: uuid.New()}, FlowID: flowID, StepName: "thanks", StepOrder: 2, Message: "Thanks for signing up!", MessageType: models.FlowStepTypeText},
+			},
+		}
+		require.NoError(t, app.DB.Create(flow).Error)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"phone_number": "15550102030",
+			"flow_id":      flow.ID.String(),
+			"step_name":    "thanks",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.PreviewSendToNumber(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		require.Len(t, mockServer.sentMessages, 1)
+		text, _ := mockServer.sentMessages[0]["text"].(map[string]interface{})
+		assert.Equal(t, "Thanks for signing up!", text["body"])
+	})
+
+	t.Run("rejects rule and flow together", func(t *testing.T) {
+		t.Parallel()
+		mockServer := newMockWhatsAppServer()
+		defer mockServer.close()
+
+		app := newMsgTestApp(t, mockServer)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"phone_number":    "15550102030",
+			"keyword_rule_id": uuid.New().String(),
+			"flow_id":         uuid.New().String(),
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.PreviewSendToNumber(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+		assert.Empty(t, mockServer.sentMessages)
+	})
+
+	t.Run("forbidden without keyword write", func(t *testing.T) {
+		t.Parallel()
+		mockServer := newMockWhatsAppServer()
+		defer mockServer.close()
+
+		app := newMsgTestApp(t, mockServer)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"phone_number":    "15550102030",
+			"keyword_rule_id": uuid.New().String(),
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.PreviewSendToNumber(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+		assert.Empty(t, mockServer.sentMessages)
+	})
+}
@@ -4,18 +4,23 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/shridarpatil/whatomate/internal/contactutil"
 	"github.com/shridarpatil/whatomate/internal/models"
 	"github.com/shridarpatil/whatomate/internal/websocket"
 	"github.com/shridarpatil/whatomate/pkg/whatsapp"
+	"gorm.io/gorm"
 )
 
 // IncomingTextMessage represents a text, interactive, or media message from the webhook
@@ -116,6 +121,16 @@ func (a *App) processIncomingMessageFull(phoneNumberID string, msg IncomingTextM
 		return
 	}
 
+	// Drop anything from numbers the organization has blacklisted
+	if a.isPhoneBlacklisted(account.OrganizationID, msg.From) {
+		a.Log.Info("Dropping message from blacklisted number", "from", msg.From, "org_id", account.OrganizationID)
+		return
+	}
+	if a.isContactBlocked(account.OrganizationID, msg.From) {
+		a.Log.Info("Dropping message from blocked contact", "from", msg.From, "org_id", account.OrganizationID)
+		return
+	}
+
 	// Handle reaction messages specially - they update existing messages, not create new ones
 	if msg.Type == "reaction" && msg.Reaction != nil {
 		a.handleIncomingReaction(account, msg.From, msg.Reaction.MessageID, msg.Reaction.Emoji, profileName)
@@ -124,6 +139,8 @@ func (a *App) processIncomingMessageFull(phoneNumberID string, msg IncomingTextM
 
 	// Get or create contact (always do this for all incoming messages)
 	contact, isNewContact, _ := contactutil.GetOrCreateContact(a.DB, account.OrganizationID, msg.From, profileName)
+	// Read before saving the message, which stamps last_inbound_at
+	isFirstInbound := isNewContact || contact.LastInboundAt == nil
 
 	// Dispatch webhook if new contact was created
 	if isNewContact {
@@ -279,11 +296,28 @@ func (a *App) processIncomingMessageFull(phoneNumberID string, msg IncomingTextM
 	if msg.Context != nil && msg.Context.ID != "" {
 		replyToWAMID = msg.Context.ID
 	}
-	a.saveIncomingMessage(account, contact, msg.ID, messageType, messageText, mediaInfo, replyToWAMID)
+	// Button and list replies keep the chosen option; the context links them
+	// back to the interactive message they answer
+	var interactiveReply models.JSONB
+	if buttonID != "" {
+		interactiveReply = models.JSONB{
+			"type":  msg.Interactive.Type,
+			"id":    buttonID,
+			"title": messageText,
+		}
+	}
+	a.saveIncomingMessage(account, contact, msg.ID, messageType, messageText, mediaInfo, interactiveReply, replyToWAMID)
 
 	// Clear chatbot tracking since client has replied
 	a.ClearContactChatbotTracking(contact.ID)
 
+	// Opt-out keywords count even during an agent transfer, and nothing
+	// automated replies to a contact who has opted out
+	if a.applyOptOutKeywords(account.OrganizationID, contact, messageText) {
+		a.Log.Info("Contact opted out, skipping chatbot processing", "contact_id", contact.ID)
+		return
+	}
+
 	// Check for active agent transfer - skip chatbot processing if transferred
 	if a.hasActiveAgentTransfer(account.OrganizationID, contact.ID) {
 		a.Log.Info("Contact has active agent transfer, skipping chatbot processing",
@@ -292,25 +326,60 @@ func (a *App) processIncomingMessageFull(phoneNumberID string, msg IncomingTextM
 		return
 	}
 
+	// Skip automated responses while an agent has paused the chatbot for this contact
+	if a.chatbotPausedForContact(contact) {
+		a.Log.Info("Chatbot paused for contact, skipping chatbot processing",
+			"contact_id", contact.ID,
+			"resume_at", contact.BotResumeAt)
+		return
+	}
+
 	// Check if chatbot is enabled for this account (use cache)
 	settings, err := a.getChatbotSettingsCached(account.OrganizationID, account.Name)
 	if err != nil {
 		a.Log.Error("Failed to load chatbot settings", "error", err, "account", account.Name, "org_id", account.OrganizationID)
 		return
 	}
+
+	// A contact flooding the inbox gets no automated responses; their messages are already stored
+	if a.applyInboundRateLimit(settings, contact, time.Now()) {
+		a.Log.Info("Contact over inbound rate limit, skipping chatbot processing",
+			"contact_id", contact.ID,
+			"limit_per_minute", settings.FloodControl.MaxPerMinute)
+		return
+	}
+
 	if !settings.IsEnabled {
 		a.Log.Debug("Chatbot not enabled for this account, creating transfer for agent queue", "account", account.Name, "settings_id", settings.ID)
 		// Create transfer to agent queue when chatbot is disabled
 		a.createTransferToQueue(account, contact, models.TransferSourceChatbotDisabled)
 		return
 	}
+
+	// Transfers to the queue above are assigned when they're created; here the
+	// contact gets an agent up front and later transfers follow AssignToSameAgent
+	a.assignNewConversation(contact, settings)
 	a.Log.Info("Chatbot settings loaded", "settings_id", settings.ID, "is_enabled", settings.IsEnabled, "ai_enabled", settings.AI.Enabled, "ai_provider", settings.AI.Provider, "default_response", settings.DefaultResponse)
 
 	// Check business hours if enabled
+	outsideHours := false
 	if settings.BusinessHours.Enabled && len(settings.BusinessHours.Hours) > 0 {
-		if !a.isWithinBusinessHours(settings.BusinessHours.Hours) {
+		if !settings.BusinessHours.IsWithinBusinessHours(time.Now()) {
+			outsideHours = true
 			// If automated responses are not allowed outside hours, send out-of-hours message and stop
 			if !settings.BusinessHours.AllowAutomatedOutside {
+				// An after-hours flow takes over from the out of hours message
+				if messageText != "" {
+					if flow := a.matchFlowTrigger(account.OrganizationID, account.Name, flowTriggerInput{OutsideHours: true}); flow != nil {
+						session, _ := a.getOrCreateSession(account.OrganizationID, contact.ID, account.Name, msg.From, settings.SessionTimeoutMins, settings.SessionGraceMins)
+						if session.CurrentFlowID != nil {
+							a.processFlowResponse(account, session, contact, messageText, buttonID, flowResponseData)
+						} else {
+							a.startFlow(account, session, contact, flow)
+						}
+						return
+					}
+				}
 				a.Log.Info("Outside business hours, sending out of hours message")
 				if settings.BusinessHours.OutOfHoursMessage != "" {
 					if err := a.sendAndSaveTextMessage(account, contact, settings.BusinessHours.OutOfHoursMessage); err != nil {
@@ -333,18 +402,21 @@ func (a *App) processIncomingMessageFull(phoneNumberID string, msg IncomingTextM
 	a.Log.Info("Processing message", "text", messageText, "buttonID", buttonID, "from", msg.From)
 
 	// Get or create active session for this contact
-	session, isNewSession := a.getOrCreateSession(account.OrganizationID, contact.ID, account.Name, msg.From, settings.SessionTimeoutMins)
+	session, isNewSession := a.getOrCreateSession(account.OrganizationID, contact.ID, account.Name, msg.From, settings.SessionTimeoutMins, settings.SessionGraceMins)
 
 	// Log incoming message to session
 	a.logSessionMessage(session.ID, models.DirectionIncoming, messageText, "keyword_check")
 
 	// Check for transfer keyword BEFORE sending greeting (transfer takes priority)
 	keywordResponse, keywordMatched := a.matchKeywordRules(account.OrganizationID, account.Name, messageText)
+	if keywordMatched {
+		a.recordKeywordRuleHit(account.OrganizationID, keywordResponse.RuleID, contact.ID, keywordResponse.Variant)
+	}
 	if keywordMatched && keywordResponse.ResponseType == models.ResponseTypeTransfer {
 		a.Log.Info("Transfer keyword matched", "response", keywordResponse.Body)
 		// Check business hours - if outside hours, send out of hours message instead
 		if settings.BusinessHours.Enabled && len(settings.BusinessHours.Hours) > 0 {
-			if !a.isWithinBusinessHours(settings.BusinessHours.Hours) {
+			if !settings.BusinessHours.IsWithinBusinessHours(time.Now()) {
 				a.Log.Info("Outside business hours, sending out of hours message instead of transfer")
 				if settings.BusinessHours.OutOfHoursMessage != "" {
 					if err := a.sendAndSaveTextMessage(account, contact, settings.BusinessHours.OutOfHoursMessage); err != nil {
@@ -370,8 +442,14 @@ func (a *App) processIncomingMessageFull(phoneNumberID string, msg IncomingTextM
 		return
 	}
 
-	// Try to match flow trigger keywords first (before greeting to avoid duplicate messages)
-	if flow := a.matchFlowTrigger(account.OrganizationID, account.Name, messageText); flow != nil {
+	// Try to match flow triggers first (before greeting to avoid duplicate messages)
+	trigger := flowTriggerInput{
+		Text:         messageText,
+		ButtonID:     buttonID,
+		FirstInbound: isFirstInbound,
+		OutsideHours: outsideHours,
+	}
+	if flow := a.matchFlowTrigger(account.OrganizationID, account.Name, trigger); flow != nil {
 		a.startFlow(account, session, contact, flow)
 		return
 	}
@@ -408,6 +486,12 @@ func (a *App) processIncomingMessageFull(phoneNumberID string, msg IncomingTextM
 	if keywordMatched && keywordResponse.ResponseType != models.ResponseTypeTransfer {
 		a.Log.Info("Keyword rule matched", "response_type", keywordResponse.ResponseType, "response", keywordResponse.Body)
 
+		if keywordResponse.ResponseType == models.ResponseTypeTemplate {
+			sent := a.sendKeywordTemplateResponse(account, contact, keywordResponse)
+			a.logSessionMessage(session.ID, models.DirectionOutgoing, sent, "keyword_response")
+			return
+		}
+
 		// Handle regular text response
 		if len(keywordResponse.Buttons) > 0 {
 			if err := a.sendAndSaveInteractiveButtons(account, contact, keywordResponse.Body, keywordResponse.Buttons); err != nil {
@@ -429,7 +513,6 @@ func (a *App) processIncomingMessageFull(phoneNumberID string, msg IncomingTextM
 		aiResponse, err := a.generateAIResponse(settings, session, messageText)
 		if err != nil {
 			a.Log.Error("AI response failed", "error", err, "provider", settings.AI.Provider, "model", settings.AI.Model)
-			// Fall through to default response
 		} else if aiResponse != "" {
 			a.Log.Info("AI response generated successfully", "response_length", len(aiResponse))
 			if err := a.sendAndSaveTextMessage(account, contact, aiResponse); err != nil {
@@ -440,6 +523,14 @@ func (a *App) processIncomingMessageFull(phoneNumberID string, msg IncomingTextM
 		} else {
 			a.Log.Warn("AI returned empty response")
 		}
+
+		// The AI is unavailable; hand over to an agent if configured, otherwise
+		// fall through to the fallback message
+		if settings.AI.FailureAction == models.AIFailureTransfer {
+			a.Log.Info("AI unavailable, transferring to agent queue", "contact", contact.PhoneNumber)
+			a.createTransferToQueue(account, contact, models.TransferSourceAIFailure)
+			return
+		}
 	} else {
 		a.Log.Info("AI not configured", "ai_enabled", settings.AI.Enabled, "has_provider", settings.AI.Provider != "", "has_api_key", settings.AI.APIKey != "")
 	}
@@ -477,9 +568,16 @@ func (a *App) processIncomingMessageFull(phoneNumberID string, msg IncomingTextM
 
 // KeywordResponse holds the response content and optional buttons
 type KeywordResponse struct {
+	RuleID       uuid.UUID
 	Body         string
 	Buttons      []map[string]interface{}
-	ResponseType models.ResponseType // text, transfer
+	ResponseType models.ResponseType // text, template, transfer
+	Variant      *int                // Index of the response variant sent, nil when the rule has none
+
+	// Template responses
+	TemplateID   uuid.UUID
+	BodyParams   map[string]string
+	FallbackText string // Sent as plain text when the template send fails
 }
 
 // matchKeywordRules checks if the message matches any keyword rules
@@ -506,10 +604,14 @@ func (a *App) matchKeywordRules(orgID uuid.UUID, accountName, messageText string
 					matched = messageLower == keywordLower
 				}
 			case models.MatchTypeContains:
+				text, kw := messageLower, keywordLower
 				if rule.CaseSensitive {
-					matched = strings.Contains(messageText, keyword)
+					text, kw = messageText, keyword
+				}
+				if rule.WordBoundary {
+					matched = containsWord(text, kw)
 				} else {
-					matched = strings.Contains(messageLower, keywordLower)
+					matched = strings.Contains(text, kw)
 				}
 			case models.MatchTypeStartsWith:
 				if rule.CaseSensitive {
@@ -524,29 +626,46 @@ func (a *App) matchKeywordRules(orgID uuid.UUID, accountName, messageText string
 				}
 			default:
 				// Default to contains
-				matched = strings.Contains(messageLower, keywordLower)
+				if rule.CaseSensitive {
+					matched = strings.Contains(messageText, keyword)
+				} else {
+					matched = strings.Contains(messageLower, keywordLower)
+				}
 			}
 
 			if matched {
 				response := &KeywordResponse{
+					RuleID:       rule.ID,
 					ResponseType: rule.ResponseType,
 				}
 
+				// For template type, send the referenced template with an optional text fallback
+				if rule.ResponseType == models.ResponseTypeTemplate {
+					if applyKeywordTemplateContent(response, rule.ResponseContent) {
+						return response, true
+					}
+					continue
+				}
+
+				// Rules with variants send one of them, picked by weight
+				content, variant := pickKeywordContent(rule.ResponseContent)
+				response.Variant = variant
+
 				// For transfer type, use body as the transfer message
 				if rule.ResponseType == models.ResponseTypeTransfer {
-					if body, ok := rule.ResponseContent["body"].(string); ok {
+					if body, ok := content["body"].(string); ok {
 						response.Body = body
 					}
 					return response, true
 				}
 
 				// Get response body
-				if body, ok := rule.ResponseContent["body"].(string); ok {
+				if body, ok := content["body"].(string); ok {
 					response.Body = body
 				}
 
 				// Get buttons if present
-				if buttons, ok := rule.ResponseContent["buttons"].([]interface{}); ok && len(buttons) > 0 {
+				if buttons, ok := content["buttons"].([]interface{}); ok && len(buttons) > 0 {
 					response.Buttons = make([]map[string]interface{}, 0, len(buttons))
 					for _, btn := range buttons {
 						if btnMap, ok := btn.(map[string]interface{}); ok {
@@ -565,6 +684,97 @@ func (a *App) matchKeywordRules(orgID uuid.UUID, accountName, messageText string
 	return nil, false
 }
 
+// containsWord reports whether word occurs in text as a whole word, i.e. not
+// directly preceded or followed by a letter, digit or underscore.
+func containsWord(text, word string) bool {
+	if word == "" {
+		return false
+	}
+	for offset := 0; offset < len(text); {
+		idx := strings.Index(text[offset:], word)
+		if idx < 0 {
+			return false
+		}
+		start := offset + idx
+		end := start + len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isWordRune(before)) && (end == len(text) || !isWordRune(after)) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return false
+}
+
+// applyKeywordTemplateContent reads template response content
+// ({template_id, body_params, fallback_text}) into response. Rules saved
+// before templates were supported have no template_id and send their body as
+// text instead. It reports false when there is neither a valid template nor a
+// body to send.
+func applyKeywordTemplateContent(response *KeywordResponse, content models.JSONB) bool {
+	idStr, _ := content["template_id"].(string)
+	if idStr == "" {
+		response.ResponseType = models.ResponseTypeText
+		response.Body, _ = content["body"].(string)
+		return response.Body != ""
+	}
+	templateID, err := uuid.Parse(idStr)
+	if err != nil {
+		return false
+	}
+	response.TemplateID = templateID
+	response.FallbackText, _ = content["fallback_text"].(string)
+	if params, ok := content["body_params"].(map[string]interface{}); ok && len(params) > 0 {
+		response.BodyParams = make(map[string]string, len(params))
+		for k, v := range params {
+			response.BodyParams[k] = fmt.Sprint(v)
+		}
+	}
+	return true
+}
+
+// sendKeywordTemplateResponse sends a keyword rule's template and falls back to
+// its plain text when the template can't be sent (e.g. not approved or deleted).
+// Returns the content that was sent, for the session log.
+func (a *App) sendKeywordTemplateResponse(account *models.WhatsAppAccount, contact *models.Contact, resp *KeywordResponse) string {
+	var template models.Template
+	err := a.DB.Where("id = ? AND organization_id = ?", resp.TemplateID, account.OrganizationID).First(&template).Error
+	if err == nil {
+		var msg *models.Message
+		msg, err = a.SendOutgoingMessage(context.Background(), OutgoingMessageRequest{
+			Account:    account,
+			Contact:    contact,
+			Type:       models.MessageTypeTemplate,
+			Template:   &template,
+			BodyParams: resp.BodyParams,
+		}, ChatbotSendOptions())
+		if err == nil && msg.Status == models.MessageStatusFailed {
+			err = errors.New(msg.ErrorMessage)
+		}
+		if err == nil {
+			return fmt.Sprintf("[Template: %s]", template.Name)
+		}
+	}
+
+	a.Log.Warn("Keyword template send failed", "error", err, "template_id", resp.TemplateID, "contact", contact.PhoneNumber)
+	if resp.FallbackText == "" {
+		return ""
+	}
+	if err := a.sendAndSaveTextMessage(account, contact, resp.FallbackText); err != nil {
+		a.Log.Error("Failed to send template fallback text", "error", err, "contact", contact.PhoneNumber)
+	}
+	return resp.FallbackText
+}
+
+// isWordRune reports whether r is part of a word for keyword matching
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
 // sendAndSaveTextMessage sends a text message and saves it to the database
 // Uses the unified SendOutgoingMessage for consistent behavior
 func (a *App) sendAndSaveTextMessage(account *models.WhatsAppAccount, contact *models.Contact, message string) error {
@@ -713,9 +923,35 @@ func (a *App) sendAndSaveFlowMessage(account *models.WhatsAppAccount, contact *m
 }
 
 
-// getOrCreateSession finds an active session or creates a new one
+// recordKeywordRuleHit stores a keyword rule match, and the response variant
+// sent if any, for reporting
+func (a *App) recordKeywordRuleHit(orgID, ruleID, contactID uuid.UUID, variant *int) {
+	hit := models.KeywordRuleHit{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: orgID,
+		KeywordRuleID:  ruleID,
+		ContactID:      contactID,
+		Variant:        variant,
+	}
+	if err := a.DB.Create(&hit).Error; err != nil {
+		a.Log.Error("Failed to record keyword rule hit", "error", err, "rule_id", ruleID)
+		return
+	}
+
+	if err := a.DB.Model(&models.KeywordRule{}).
+		Where("id = ? AND organization_id = ?", ruleID, orgID).
+		UpdateColumns(map[string]any{
+			"hit_count":   gorm.Expr("hit_count + 1"),
+			"last_hit_at": hit.CreatedAt,
+		}).Error; err != nil {
+		a.Log.Error("Failed to update keyword rule hit count", "error", err, "rule_id", ruleID)
+	}
+}
+
+// getOrCreateSession finds an active session or creates a new one. A session
+// that timed out less than graceMins ago is resumed instead of starting over.
 // Returns the session and a boolean indicating if it's a new session
-func (a *App) getOrCreateSession(orgID, contactID uuid.UUID, accountName, phoneNumber string, timeoutMins int) (*models.ChatbotSession, bool) {
+func (a *App) getOrCreateSession(orgID, contactID uuid.UUID, accountName, phoneNumber string, timeoutMins, graceMins int) (*models.ChatbotSession, bool) {
 	now := time.Now()
 
 	// Look for an active session that hasn't timed out
@@ -725,11 +961,20 @@ func (a *App) getOrCreateSession(orgID, contactID uuid.UUID, accountName, phoneN
 		orgID, contactID, accountName, models.SessionStatusActive, timeout).First(&session)
 
 	if result.Error == nil {
-		// Update last activity
-		a.DB.Model(&session).Update("last_activity_at", now)
+		// Update last activity and re-arm the timeout warning
+		a.DB.Model(&session).Updates(map[string]any{
+			"last_activity_at": now,
+			"warning_sent_at":  nil,
+		})
 		return &session, false // existing session
 	}
 
+	if graceMins > 0 {
+		if resumed := a.resumeTimedOutSession(orgID, contactID, accountName, timeout, now, graceMins); resumed != nil {
+			return resumed, false
+		}
+	}
+
 	// Create new session
 	session = models.ChatbotSession{
 		BaseModel:       models.BaseModel{ID: uuid.New()},
@@ -748,6 +993,61 @@ func (a *App) getOrCreateSession(orgID, contactID uuid.UUID, accountName, phoneN
 	return &session, true // new session
 }
 
+// resumeTimedOutSession reactivates the contact's most recent session if it
+// timed out within the grace window, keeping its flow position and session
+// data. Sessions that were completed, cancelled or transferred stay closed.
+func (a *App) resumeTimedOutSession(orgID, contactID uuid.UUID, accountName string, timeout, now time.Time, graceMins int) *models.ChatbotSession {
+	var last models.ChatbotSession
+	if err := a.DB.Where("organization_id = ? AND contact_id = ? AND whats_app_account = ?", orgID, contactID, accountName).
+		Order("last_activity_at DESC").First(&last).Error; err != nil {
+		return nil
+	}
+
+	grace := time.Duration(graceMins) * time.Minute
+	switch last.Status {
+	case models.SessionStatusActive:
+		// Not yet closed by session maintenance
+		if !last.LastActivityAt.After(timeout.Add(-grace)) {
+			return nil
+		}
+	case models.SessionStatusTimeout:
+		if last.CompletedAt == nil || !last.CompletedAt.After(now.Add(-grace)) {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	// Claim the session; session maintenance may be closing it concurrently
+	claim := a.DB.Model(&models.ChatbotSession{}).
+		Where("id = ? AND status = ?", last.ID, last.Status).
+		Updates(map[string]any{
+			"status":           models.SessionStatusActive,
+			"last_activity_at": now,
+			"completed_at":     nil,
+			"warning_sent_at":  nil,
+			"resumed_at":       now,
+			"resume_count":     gorm.Expr("resume_count + 1"),
+		})
+	if claim.Error != nil {
+		a.Log.Error("Failed to resume session", "error", claim.Error, "session_id", last.ID)
+		return nil
+	}
+	if claim.RowsAffected == 0 {
+		return nil
+	}
+
+	last.Status = models.SessionStatusActive
+	last.LastActivityAt = now
+	last.CompletedAt = nil
+	last.WarningSentAt = nil
+	last.ResumedAt = &now
+	last.ResumeCount++
+
+	a.Log.Info("Chatbot session resumed within grace window", "session_id", last.ID, "contact_id", contactID, "resume_count", last.ResumeCount)
+	return &last
+}
+
 // logSessionMessage logs a message to the chatbot session
 func (a *App) logSessionMessage(sessionID uuid.UUID, direction models.Direction, message, stepName string) {
 	msg := models.ChatbotSessionMessage{
@@ -762,8 +1062,25 @@ func (a *App) logSessionMessage(sessionID uuid.UUID, direction models.Direction,
 	}
 }
 
+// flowTriggerInput describes an inbound message for flow trigger matching
+type flowTriggerInput struct {
+	Text         string
+	ButtonID     string
+	FirstInbound bool // The contact has never messaged before
+	OutsideHours bool // Received outside business hours
+}
+
+// flowTriggerRank orders trigger types when several flows match; lower wins.
+// Event triggers are more specific than keywords, so they take precedence.
+var flowTriggerRank = map[models.FlowTriggerType]int{
+	models.FlowTriggerButton:       0,
+	models.FlowTriggerFirstInbound: 1,
+	models.FlowTriggerAfterHours:   2,
+	models.FlowTriggerKeyword:      3,
+}
+
 // matchFlowTrigger checks if the message triggers any flow
-func (a *App) matchFlowTrigger(orgID uuid.UUID, accountName, messageText string) *models.ChatbotFlow {
+func (a *App) matchFlowTrigger(orgID uuid.UUID, accountName string, in flowTriggerInput) *models.ChatbotFlow {
 	// Use cached flows (includes steps)
 	flows, err := a.getChatbotFlowsCached(orgID)
 	if err != nil {
@@ -771,16 +1088,60 @@ func (a *App) matchFlowTrigger(orgID uuid.UUID, accountName, messageText string)
 		return nil
 	}
 
-	messageLower := strings.ToLower(messageText)
+	now := time.Now()
+	var matched *models.ChatbotFlow
+	for i := range flows {
+		flow := &flows[i]
+		if !isFlowActiveAt(flow, now) || !flowTriggerMatches(flow, in) {
+			continue
+		}
+		if matched == nil || flowTriggerRank[flowTriggerType(flow)] < flowTriggerRank[flowTriggerType(matched)] {
+			matched = flow
+		}
+	}
+	return matched
+}
 
-	for _, flow := range flows {
+// flowTriggerType returns the flow's trigger type, treating unset as keyword
+func flowTriggerType(flow *models.ChatbotFlow) models.FlowTriggerType {
+	if flow.TriggerType == "" {
+		return models.FlowTriggerKeyword
+	}
+	return flow.TriggerType
+}
+
+// flowTriggerMatches reports whether the inbound message satisfies the flow's trigger
+func flowTriggerMatches(flow *models.ChatbotFlow, in flowTriggerInput) bool {
+	switch flowTriggerType(flow) {
+	case models.FlowTriggerFirstInbound:
+		return in.FirstInbound
+	case models.FlowTriggerAfterHours:
+		return in.OutsideHours
+	case models.FlowTriggerButton:
+		return flow.TriggerButtonID != "" && in.ButtonID == flow.TriggerButtonID
+	case models.FlowTriggerKeyword:
+		if in.Text == "" {
+			return false
+		}
+		messageLower := strings.ToLower(in.Text)
 		for _, keyword := range flow.TriggerKeywords {
 			if strings.Contains(messageLower, strings.ToLower(keyword)) {
-				return &flow
+				return true
 			}
 		}
 	}
-	return nil
+	return false
+}
+
+// isFlowActiveAt reports whether t falls inside the flow's optional active window
+func isFlowActiveAt(flow *models.ChatbotFlow, t time.Time) bool {
+	if flow.ActiveFrom != nil && t.Before(*flow.ActiveFrom) {
+		return false
+	}
+	if flow.ActiveUntil != nil && t.After(*flow.ActiveUntil) {
+		return false
+	}
+	return true
 }
 
 // startFlow initiates a chatbot flow for a user
@@ -887,11 +1248,23 @@ func (a *App) processFlowResponse(account *models.WhatsAppAccount, session *mode
 		}
 	}
 
+	// Interpret free-text answers with AI when the step asks for it
+	if buttonID == "" {
+		if assist := parseStepAIAssist(currentStep); assist != nil {
+			if !a.applyStepAIAssist(account, session, contact, currentStep, assist, userInput) {
+				return
+			}
+		}
+	}
+
 	// Auto-validate button responses when step expects button/select input
 	// Only validate if InputType is button/select, or if buttons are configured and user clicked a button
 	shouldValidateButtons := len(currentStep.Buttons) > 0 &&
 		(currentStep.InputType == models.InputTypeButton || currentStep.InputType == models.InputTypeSelect || buttonID != "")
 
+	// Next step mapped on the chosen button, if any
+	buttonNextStep := ""
+
 	if shouldValidateButtons {
 		isValidButton := false
 		userInputLower := strings.ToLower(userInput)
@@ -910,10 +1283,12 @@ func (a *App) processFlowResponse(account *models.WhatsAppAccount, session *mode
 				// Match by buttonID (exact match) or by title (case-insensitive)
 				if buttonID != "" && buttonID == btnID {
 					isValidButton = true
+					buttonNextStep, _ = btnMap["next_step"].(string)
 					break
 				}
 				if strings.ToLower(btnTitle) == userInputLower || btnID == userInput {
 					isValidButton = true
+					buttonNextStep, _ = btnMap["next_step"].(string)
 					// Set buttonID if not already set (user typed the button text)
 					if buttonID == "" {
 						buttonID = btnID
@@ -992,8 +1367,11 @@ func (a *App) processFlowResponse(account *models.WhatsAppAccount, session *mode
 		nextStepName = flow.Steps[currentStepIndex+1].StepName
 	}
 
-	// Check conditional next - use buttonID first (for button/list responses), then userInput
-	if len(currentStep.ConditionalNext) > 0 {
+	// A button's own next_step wins; otherwise check conditional next -
+	// use buttonID first (for button/list responses), then userInput
+	if buttonNextStep != "" {
+		nextStepName = buttonNextStep
+	} else if len(currentStep.ConditionalNext) > 0 {
 		// Try buttonID first (for interactive responses)
 		if buttonID != "" {
 			if next, ok := currentStep.ConditionalNext[buttonID].(string); ok {
@@ -1057,117 +1435,30 @@ func (a *App) completeFlow(account *models.WhatsAppAccount, session *models.Chat
 		a.logSessionMessage(session.ID, models.DirectionOutgoing, message, "flow_complete")
 	}
 
+	// Check the collected data against the flow's steps
+	validationErrs := ValidateSessionData(flow, session.SessionData)
+	if len(validationErrs) > 0 {
+		a.Log.Warn("Session data does not match flow steps", "flow_id", flow.ID, "session_id", session.ID, "errors", validationErrs)
+	}
+
 	// Execute on-complete action
 	if flow.OnCompleteAction == "webhook" && len(flow.CompletionConfig) > 0 {
-		go a.sendFlowCompletionWebhook(flow, session, contact)
+		a.deliverFlowCompletionWebhook(flow, session, contact, validationErrs)
 	}
 
 	// Update session (keep current_flow_id for panel config reference)
 	now := time.Now()
 	a.DB.Model(session).Updates(map[string]interface{}{
-		"current_step": "",
-		"status":       models.SessionStatusCompleted,
-		"completed_at": now,
+		"current_step":      "",
+		"status":            models.SessionStatusCompleted,
+		"completed_at":      now,
+		"flow_completed_at": now,
 	})
 
 	// Clear chatbot tracking so SLA doesn't fire after flow completion
 	a.ClearContactChatbotTracking(contact.ID)
 }
 
-// sendFlowCompletionWebhook sends session data to configured webhook URL
-func (a *App) sendFlowCompletionWebhook(flow *models.ChatbotFlow, session *models.ChatbotSession, contact *models.Contact) {
-	config := flow.CompletionConfig
-
-	// Get webhook URL (required)
-	webhookURL, ok := config["url"].(string)
-	if !ok || webhookURL == "" {
-		a.Log.Error("Webhook URL not configured", "flow_id", flow.ID)
-		return
-	}
-
-	// Replace variables in URL
-	webhookURL = a.replaceVariables(webhookURL, session.SessionData)
-
-	// Get HTTP method (default: POST)
-	method := "POST"
-	if m, ok := config["method"].(string); ok && m != "" {
-		method = strings.ToUpper(m)
-	}
-
-	// Build the payload
-	payload := map[string]interface{}{
-		"flow_id":      flow.ID.String(),
-		"flow_name":    flow.Name,
-		"session_id":   session.ID.String(),
-		"phone_number": session.PhoneNumber,
-		"contact_id":   contact.ID.String(),
-		"contact_name": contact.ProfileName,
-		"session_data": session.SessionData,
-		"completed_at": time.Now().UTC().Format(time.RFC3339),
-	}
-
-	// Allow custom body template if provided
-	var bodyReader io.Reader
-	if bodyTemplate, ok := config["body"].(string); ok && bodyTemplate != "" {
-		// Replace variables in body template
-		bodyWithVars := a.replaceVariables(bodyTemplate, session.SessionData)
-		bodyReader = strings.NewReader(bodyWithVars)
-	} else {
-		// Use default payload
-		jsonPayload, err := json.Marshal(payload)
-		if err != nil {
-			a.Log.Error("Failed to marshal webhook payload", "error", err)
-			return
-		}
-		bodyReader = bytes.NewReader(jsonPayload)
-	}
-
-	// Create request
-	req, err := http.NewRequest(method, webhookURL, bodyReader)
-	if err != nil {
-		a.Log.Error("Failed to create webhook request", "error", err)
-		return
-	}
-
-	// Set default headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "Whatomate-Webhook/1.0")
-
-	// Add custom headers if configured
-	if headers, ok := config["headers"].(map[string]interface{}); ok {
-		for key, value := range headers {
-			if strVal, ok := value.(string); ok {
-				req.Header.Set(key, a.replaceVariables(strVal, session.SessionData))
-			}
-		}
-	}
-
-	// Make the request
-	resp, err := a.HTTPClient.Do(req)
-	if err != nil {
-		a.Log.Error("Webhook request failed", "error", err, "url", webhookURL)
-		return
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		a.Log.Info("Webhook sent successfully",
-			"flow_id", flow.ID,
-			"session_id", session.ID,
-			"status", resp.StatusCode,
-		)
-	} else {
-		a.Log.Error("Webhook returned error",
-			"flow_id", flow.ID,
-			"session_id", session.ID,
-			"status", resp.StatusCode,
-			"response", string(body),
-		)
-	}
-}
-
 // exitFlow ends a flow session (transfer, cancel, or error)
 func (a *App) exitFlow(session *models.ChatbotSession) {
 	now := time.Now()
@@ -1648,23 +1939,60 @@ func (a *App) fetchApiResponse(apiConfig models.JSONB, sessionData models.JSONB,
 
 // generateAIResponse generates a response using the configured AI provider
 func (a *App) generateAIResponse(settings *models.ChatbotSettings, session *models.ChatbotSession, userMessage string) (string, error) {
+	// Respect the org's concurrent AI request limit
+	release, err := a.acquireAISlot(settings.OrganizationID, settings.AI)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
 	// Build context from AIContext entries
-	contextData := a.buildAIContext(settings.OrganizationID, session, userMessage)
+	contextData := a.buildAIContext(settings, session, userMessage)
 
+	return a.callAIProvider(settings, session, userMessage, contextData)
+}
+
+// aiDefaultTimeout bounds an AI provider request when ai_timeout_seconds isn't set
+const aiDefaultTimeout = 30 * time.Second
+
+// aiRequestTimeout returns how long to wait for the AI provider to reply
+func aiRequestTimeout(cfg models.AIConfig) time.Duration {
+	if cfg.TimeoutSecs > 0 {
+		return time.Duration(cfg.TimeoutSecs) * time.Second
+	}
+	return aiDefaultTimeout
+}
+
+// callAIProvider sends a single request to the configured AI provider and
+// records the tokens it used. Callers are responsible for holding an AI slot
+// (see acquireAISlot).
+func (a *App) callAIProvider(settings *models.ChatbotSettings, session *models.ChatbotSession, userMessage, contextData string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), aiRequestTimeout(settings.AI))
+	defer cancel()
+
+	var (
+		response string
+		usage    aiTokenUsage
+		err      error
+	)
 	switch settings.AI.Provider {
 	case models.AIProviderOpenAI:
-		return a.generateOpenAIResponse(settings, session, userMessage, contextData)
+		response, usage, err = a.generateOpenAIResponse(ctx, settings, session, userMessage, contextData)
 	case models.AIProviderAnthropic:
-		return a.generateAnthropicResponse(settings, session, userMessage, contextData)
+		response, usage, err = a.generateAnthropicResponse(ctx, settings, session, userMessage, contextData)
 	case models.AIProviderGoogle:
-		return a.generateGoogleResponse(settings, session, userMessage, contextData)
+		response, usage, err = a.generateGoogleResponse(ctx, settings, session, userMessage, contextData)
 	default:
 		return "", fmt.Errorf("unsupported AI provider: %s", settings.AI.Provider)
 	}
+
+	// Tokens are billed even when the reply turns out to be unusable
+	a.recordAIUsage(settings, usage)
+	return response, err
 }
 
-// buildAIContext fetches and combines all AI context data
-func (a *App) buildAIContext(orgID uuid.UUID, session *models.ChatbotSession, userMessage string) string {
+// buildAIContext fetches and combines the AI context data matching a message
+func (a *App) buildAIContext(settings *models.ChatbotSettings, session *models.ChatbotSession, userMessage string) string {
 	// Get WhatsApp account for cache key
 	whatsAppAccount := ""
 	if session != nil {
@@ -1672,14 +2000,14 @@ func (a *App) buildAIContext(orgID uuid.UUID, session *models.ChatbotSession, us
 	}
 
 	// Use cached AI contexts
-	contexts, err := a.getAIContextsCached(orgID, whatsAppAccount)
+	contexts, err := a.getAIContextsCached(settings.OrganizationID, whatsAppAccount)
 	if err != nil || len(contexts) == 0 {
 		return ""
 	}
 
-	var contextParts []string
+	var sections []aiContextSection
 
-	for _, ctx := range contexts {
+	for _, ctx := range matchAIContexts(contexts, userMessage, settings.AI.ContextLimit) {
 		var content string
 
 		switch ctx.ContextType {
@@ -1705,29 +2033,89 @@ func (a *App) buildAIContext(orgID uuid.UUID, session *models.ChatbotSession, us
 		}
 
 		if content != "" {
-			contextParts = append(contextParts, fmt.Sprintf("### %s\n%s", ctx.Name, content))
+			sections = append(sections, aiContextSection{Context: ctx, Content: content})
 		}
 	}
 
-	if len(contextParts) == 0 {
-		return ""
+	return formatAIContext(fitAIContextBudget(sections, settings.AI.ContextMaxChars))
+}
+
+// aiContextSection is one AI context's content as it goes into the prompt
+type aiContextSection struct {
+	Context   models.AIContext
+	Content   string
+	Truncated bool
+}
+
+// matchAIContexts returns the contexts triggered by a message, highest priority
+// first, keeping at most limit of them (0 = all). Contexts without trigger
+// keywords always match. Equal priorities keep their order, so account-specific
+// contexts stay ahead of org-wide ones.
+func matchAIContexts(contexts []models.AIContext, message string, limit int) []models.AIContext {
+	messageLower := strings.ToLower(message)
+
+	var matched []models.AIContext
+	for _, ctx := range contexts {
+		if len(ctx.TriggerKeywords) == 0 {
+			matched = append(matched, ctx)
+			continue
+		}
+		for _, keyword := range ctx.TriggerKeywords {
+			if keyword != "" && strings.Contains(messageLower, strings.ToLower(keyword)) {
+				matched = append(matched, ctx)
+				break
+			}
+		}
 	}
 
-	return "## Context Information\n\n" + strings.Join(contextParts, "\n\n")
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Priority > matched[j].Priority
+	})
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched
 }
 
-// fetchAPIContext fetches context data from an external API
-func (a *App) fetchAPIContext(apiConfig models.JSONB, session *models.ChatbotSession, userMessage string) (string, error) {
-	if apiConfig == nil {
-		return "", fmt.Errorf("API config is empty")
+// fitAIContextBudget cuts sections down to maxChars characters of content in
+// total (0 = unlimited). Sections are in priority order, so the lowest-priority
+// ones are truncated or dropped first.
+func fitAIContextBudget(sections []aiContextSection, maxChars int) []aiContextSection {
+	if maxChars <= 0 {
+		return sections
 	}
 
-	// Get API URL (required)
-	apiURL, ok := apiConfig["url"].(string)
-	if !ok || apiURL == "" {
-		return "", fmt.Errorf("API URL is required")
+	fitted := make([]aiContextSection, 0, len(sections))
+	remaining := maxChars
+	for _, section := range sections {
+		if remaining == 0 {
+			break
+		}
+		if runes := []rune(section.Content); len(runes) > remaining {
+			section.Content = string(runes[:remaining])
+			section.Truncated = true
+		}
+		remaining -= utf8.RuneCountInString(section.Content)
+		fitted = append(fitted, section)
+	}
+	return fitted
+}
+
+// formatAIContext joins context sections into the block passed to the AI provider
+func formatAIContext(sections []aiContextSection) string {
+	if len(sections) == 0 {
+		return ""
+	}
+
+	contextParts := make([]string, len(sections))
+	for i, section := range sections {
+		contextParts[i] = fmt.Sprintf("### %s\n%s", section.Context.Name, section.Content)
 	}
+	return "## Context Information\n\n" + strings.Join(contextParts, "\n\n")
+}
 
+// fetchAPIContext fetches context data from an external API
+func (a *App) fetchAPIContext(apiConfig models.JSONB, session *models.ChatbotSession, userMessage string) (string, error) {
 	// Build session data for variable replacement
 	sessionData := models.JSONB{}
 	if session != nil {
@@ -1739,6 +2127,53 @@ func (a *App) fetchAPIContext(apiConfig models.JSONB, session *models.ChatbotSes
 		sessionData["user_message"] = userMessage
 	}
 
+	req, err := a.newAPIContextRequest(context.Background(), apiConfig, sessionData)
+	if err != nil {
+		return "", err
+	}
+
+	// Make the request
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("API request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Read response body
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	// Check for response_path to extract specific field
+	if responsePath, ok := apiConfig["response_path"].(string); ok && responsePath != "" {
+		var jsonResp map[string]interface{}
+		if err := json.Unmarshal(respBody, &jsonResp); err == nil {
+			if value := getNestedValue(jsonResp, responsePath); value != nil {
+				return formatValue(value), nil
+			}
+		}
+	}
+
+	return string(respBody), nil
+}
+
+// newAPIContextRequest builds the outbound HTTP request for an API-type AI context
+func (a *App) newAPIContextRequest(ctx context.Context, apiConfig models.JSONB, sessionData models.JSONB) (*http.Request, error) {
+	if apiConfig == nil {
+		return nil, fmt.Errorf("API config is empty")
+	}
+
+	// Get API URL (required)
+	apiURL, ok := apiConfig["url"].(string)
+	if !ok || apiURL == "" {
+		return nil, fmt.Errorf("API URL is required")
+	}
+
 	// Replace variables in URL
 	apiURL = a.replaceVariables(apiURL, sessionData)
 
@@ -1756,9 +2191,9 @@ func (a *App) fetchAPIContext(apiConfig models.JSONB, session *models.ChatbotSes
 	}
 
 	// Create request
-	req, err := http.NewRequest(method, apiURL, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, bodyReader)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set default headers
@@ -1774,38 +2209,11 @@ func (a *App) fetchAPIContext(apiConfig models.JSONB, session *models.ChatbotSes
 		}
 	}
 
-	// Make the request
-	resp, err := a.HTTPClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("API request failed: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("API returned status %d", resp.StatusCode)
-	}
-
-	// Check for response_path to extract specific field
-	if responsePath, ok := apiConfig["response_path"].(string); ok && responsePath != "" {
-		var jsonResp map[string]interface{}
-		if err := json.Unmarshal(respBody, &jsonResp); err == nil {
-			if value := getNestedValue(jsonResp, responsePath); value != nil {
-				return formatValue(value), nil
-			}
-		}
-	}
-
-	return string(respBody), nil
+	return req, nil
 }
 
 // generateOpenAIResponse generates a response using OpenAI API
-func (a *App) generateOpenAIResponse(settings *models.ChatbotSettings, session *models.ChatbotSession, userMessage string, contextData string) (string, error) {
+func (a *App) generateOpenAIResponse(ctx context.Context, settings *models.ChatbotSettings, session *models.ChatbotSession, userMessage string, contextData string) (string, aiTokenUsage, error) {
 	url := "https://api.openai.com/v1/chat/completions"
 
 	// Build messages array
@@ -1862,12 +2270,12 @@ func (a *App) generateOpenAIResponse(settings *models.ChatbotSettings, session *
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal payload: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -1875,7 +2283,7 @@ func (a *App) generateOpenAIResponse(settings *models.ChatbotSettings, session *
 
 	resp, err := a.HTTPClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("request failed: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -1888,7 +2296,7 @@ func (a *App) generateOpenAIResponse(settings *models.ChatbotSettings, session *
 			} `json:"error"`
 		}
 		_ = json.Unmarshal(body, &errResp)
-		return "", fmt.Errorf("OpenAI API error: %s", errResp.Error.Message)
+		return "", aiTokenUsage{}, fmt.Errorf("OpenAI API error: %s", errResp.Error.Message)
 	}
 
 	var result struct {
@@ -1897,20 +2305,25 @@ func (a *App) generateOpenAIResponse(settings *models.ChatbotSettings, session *
 				Content string `json:"content"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
 	}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("failed to parse response: %w", err)
 	}
+	usage := aiTokenUsage{PromptTokens: result.Usage.PromptTokens, CompletionTokens: result.Usage.CompletionTokens}
 
 	if len(result.Choices) > 0 {
-		return strings.TrimSpace(result.Choices[0].Message.Content), nil
+		return strings.TrimSpace(result.Choices[0].Message.Content), usage, nil
 	}
 
-	return "", fmt.Errorf("no response from OpenAI")
+	return "", usage, fmt.Errorf("no response from OpenAI")
 }
 
 // generateAnthropicResponse generates a response using Anthropic API
-func (a *App) generateAnthropicResponse(settings *models.ChatbotSettings, session *models.ChatbotSession, userMessage string, contextData string) (string, error) {
+func (a *App) generateAnthropicResponse(ctx context.Context, settings *models.ChatbotSettings, session *models.ChatbotSession, userMessage string, contextData string) (string, aiTokenUsage, error) {
 	url := "https://api.anthropic.com/v1/messages"
 
 	// Build messages array
@@ -1964,12 +2377,12 @@ func (a *App) generateAnthropicResponse(settings *models.ChatbotSettings, sessio
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal payload: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -1978,7 +2391,7 @@ func (a *App) generateAnthropicResponse(settings *models.ChatbotSettings, sessio
 
 	resp, err := a.HTTPClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("request failed: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -1991,7 +2404,7 @@ func (a *App) generateAnthropicResponse(settings *models.ChatbotSettings, sessio
 			} `json:"error"`
 		}
 		_ = json.Unmarshal(body, &errResp)
-		return "", fmt.Errorf("anthropic API error: %s", errResp.Error.Message)
+		return "", aiTokenUsage{}, fmt.Errorf("anthropic API error: %s", errResp.Error.Message)
 	}
 
 	var result struct {
@@ -1999,22 +2412,27 @@ func (a *App) generateAnthropicResponse(settings *models.ChatbotSettings, sessio
 			Type string `json:"type"`
 			Text string `json:"text"`
 		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
 	}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("failed to parse response: %w", err)
 	}
+	usage := aiTokenUsage{PromptTokens: result.Usage.InputTokens, CompletionTokens: result.Usage.OutputTokens}
 
 	for _, content := range result.Content {
 		if content.Type == "text" {
-			return strings.TrimSpace(content.Text), nil
+			return strings.TrimSpace(content.Text), usage, nil
 		}
 	}
 
-	return "", fmt.Errorf("no text response from Anthropic")
+	return "", usage, fmt.Errorf("no text response from Anthropic")
 }
 
 // generateGoogleResponse generates a response using Google Gemini API
-func (a *App) generateGoogleResponse(settings *models.ChatbotSettings, session *models.ChatbotSession, userMessage string, contextData string) (string, error) {
+func (a *App) generateGoogleResponse(ctx context.Context, settings *models.ChatbotSettings, session *models.ChatbotSession, userMessage string, contextData string) (string, aiTokenUsage, error) {
 	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s",
 		settings.AI.Model, settings.AI.APIKey)
 
@@ -2078,19 +2496,19 @@ func (a *App) generateGoogleResponse(settings *models.ChatbotSettings, session *
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal payload: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := a.HTTPClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("request failed: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -2103,7 +2521,7 @@ func (a *App) generateGoogleResponse(settings *models.ChatbotSettings, session *
 			} `json:"error"`
 		}
 		_ = json.Unmarshal(body, &errResp)
-		return "", fmt.Errorf("google AI API error: %s", errResp.Error.Message)
+		return "", aiTokenUsage{}, fmt.Errorf("google AI API error: %s", errResp.Error.Message)
 	}
 
 	var result struct {
@@ -2114,16 +2532,21 @@ func (a *App) generateGoogleResponse(settings *models.ChatbotSettings, session *
 				} `json:"parts"`
 			} `json:"content"`
 		} `json:"candidates"`
+		UsageMetadata struct {
+			PromptTokenCount     int `json:"promptTokenCount"`
+			CandidatesTokenCount int `json:"candidatesTokenCount"`
+		} `json:"usageMetadata"`
 	}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return "", aiTokenUsage{}, fmt.Errorf("failed to parse response: %w", err)
 	}
+	usage := aiTokenUsage{PromptTokens: result.UsageMetadata.PromptTokenCount, CompletionTokens: result.UsageMetadata.CandidatesTokenCount}
 
 	if len(result.Candidates) > 0 && len(result.Candidates[0].Content.Parts) > 0 {
-		return strings.TrimSpace(result.Candidates[0].Content.Parts[0].Text), nil
+		return strings.TrimSpace(result.Candidates[0].Content.Parts[0].Text), usage, nil
 	}
 
-	return "", fmt.Errorf("no response from Google AI")
+	return "", usage, fmt.Errorf("no response from Google AI")
 }
 
 // getSessionHistory retrieves recent messages from the session
@@ -2269,7 +2692,7 @@ type MediaInfo struct {
 }
 
 // saveIncomingMessage saves an incoming message to the messages table
-func (a *App) saveIncomingMessage(account *models.WhatsAppAccount, contact *models.Contact, whatsappMsgID, msgType, content string, mediaInfo *MediaInfo, replyToWAMID string) {
+func (a *App) saveIncomingMessage(account *models.WhatsAppAccount, contact *models.Contact, whatsappMsgID, msgType, content string, mediaInfo *MediaInfo, interactiveData models.JSONB, replyToWAMID string) {
 	now := time.Now()
 
 	message := models.Message{
@@ -2281,6 +2704,7 @@ func (a *App) saveIncomingMessage(account *models.WhatsAppAccount, contact *mode
 		Direction:         models.DirectionIncoming,
 		MessageType:       models.MessageType(msgType),
 		Content:           content,
+		InteractiveData:   interactiveData,
 		Status:            models.MessageStatusReceived,
 	}
 
@@ -2317,11 +2741,15 @@ func (a *App) saveIncomingMessage(account *models.WhatsAppAccount, contact *mode
 	}
 
 	a.DB.Model(contact).Updates(map[string]interface{}{
-		"last_message_at":      now,
-		"last_message_preview": preview,
-		"is_read":              false,
-		"whats_app_account":    account.Name,
-		"last_inbound_at":      now,
+		"last_message_at":        now,
+		"last_message_preview":   preview,
+		"last_message_direction": models.DirectionIncoming,
+		"is_read":                false,
+		"whats_app_account":      account.Name,
+		"last_inbound_at":        now,
+		// A new customer message reopens resolved, pending or snoozed conversations
+		"conversation_status": models.ConversationStatusOpen,
+		"snooze_until":        nil,
 	})
 
 	a.Log.Info("Saved incoming message", "message_id", message.ID, "contact_id", contact.ID, "media_url", message.MediaURL)
@@ -2347,6 +2775,7 @@ func (a *App) saveIncomingMessage(account *models.WhatsAppAccount, contact *mode
 			"media_url":        message.MediaURL,
 			"media_mime_type":  message.MediaMimeType,
 			"media_filename":   message.MediaFilename,
+			"interactive_data": message.InteractiveData,
 			"status":           message.Status,
 			"wamid":            message.WhatsAppMessageID,
 			"created_at":       message.CreatedAt,
@@ -2374,7 +2803,7 @@ func (a *App) saveIncomingMessage(account *models.WhatsAppAccount, contact *mode
 	}
 
 	// Dispatch webhook for incoming message
-	a.DispatchWebhook(account.OrganizationID, models.WebhookEventMessageIncoming, MessageEventData{
+	eventData := MessageEventData{
 		MessageID:       message.ID.String(),
 		ContactID:       contact.ID.String(),
 		ContactPhone:    contact.PhoneNumber,
@@ -2383,56 +2812,16 @@ func (a *App) saveIncomingMessage(account *models.WhatsAppAccount, contact *mode
 		Content:         content,
 		WhatsAppAccount: account.Name,
 		Direction:       models.DirectionIncoming,
-	})
-}
-
-// isWithinBusinessHours checks if current time is within configured business hours
-func (a *App) isWithinBusinessHours(businessHours models.JSONBArray) bool {
-	now := time.Now()
-	currentDay := int(now.Weekday()) // 0 = Sunday, 1 = Monday, etc.
-	currentTime := now.Format("15:04")
-
-	for _, bh := range businessHours {
-		bhMap, ok := bh.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		// Get day (0-6, Sunday-Saturday)
-		day, ok := bhMap["day"].(float64)
-		if !ok {
-			continue
-		}
-
-		if int(day) != currentDay {
-			continue
-		}
-
-		// Check if enabled for this day
-		enabled, ok := bhMap["enabled"].(bool)
-		if !ok || !enabled {
-			return false // Day exists but is disabled
-		}
-
-		// Get start and end times
-		startTime, ok := bhMap["start_time"].(string)
-		if !ok {
-			continue
-		}
-		endTime, ok := bhMap["end_time"].(string)
-		if !ok {
-			continue
-		}
-
-		// Compare times (simple string comparison works for HH:MM format)
-		if currentTime >= startTime && currentTime <= endTime {
-			return true
-		}
-		return false // Found the day but outside hours
+		WAMID:           message.WhatsAppMessageID,
+		MediaURL:        message.MediaURL,
+		MediaMimeType:   message.MediaMimeType,
+		MediaFilename:   message.MediaFilename,
+		CreatedAt:       &message.CreatedAt,
 	}
-
-	// If no matching day found, assume outside business hours
-	return false
+	if message.ReplyToMessageID != nil {
+		eventData.ReplyToID = message.ReplyToMessageID.String()
+	}
+	a.DispatchWebhook(account.OrganizationID, models.WebhookEventMessageIncoming, eventData)
 }
 
 // shouldSkipStep evaluates a text expression like "(status == 'vip' OR amount > 100) AND name != ”"
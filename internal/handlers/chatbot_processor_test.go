@@ -1,13 +1,20 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/config"
 	"github.com/shridarpatil/whatomate/internal/models"
 	"github.com/shridarpatil/whatomate/pkg/whatsapp"
 	"github.com/shridarpatil/whatomate/test/testutil"
@@ -33,6 +40,7 @@ func newProcessorTestApp(t *testing.T) *App {
 	t.Cleanup(waServer.Close)
 
 	app := &App{
+		Config:   &config.Config{},
 		DB:       db,
 		Log:      log,
 		WhatsApp: whatsapp.NewWithBaseURL(log, waServer.URL),
@@ -43,6 +51,11 @@ func newProcessorTestApp(t *testing.T) *App {
 	return app
 }
 
+// uniqueTestPhone returns a random digits-only phone number for inbound tests.
+func uniqueTestPhone() string {
+	return fmt.Sprintf("1555%07d", rand.IntN(10_000_000))
+}
+
 // createProcessorTestOrg creates an organization and WhatsApp account for processor tests.
 func createProcessorTestOrg(t *testing.T, app *App) (*models.Organization, *models.WhatsAppAccount) {
 	t.Helper()
@@ -115,6 +128,52 @@ func TestMatchKeywordRules_ExactMatch_CaseSensitive(t *testing.T) {
 	assert.False(t, matched2)
 }
 
+func TestMatchKeywordRules_CaseSensitivity(t *testing.T) {
+	tests := []struct {
+		matchType     models.MatchType
+		caseSensitive bool
+		keyword       string
+		message       string
+		want          bool
+	}{
+		{models.MatchTypeExact, false, "STOP", "stop", true},
+		{models.MatchTypeExact, true, "STOP", "STOP", true},
+		{models.MatchTypeExact, true, "STOP", "stop", false},
+		{models.MatchTypeContains, false, "Order", "my ORDER is late", true},
+		{models.MatchTypeContains, true, "Order", "my Order is late", true},
+		{models.MatchTypeContains, true, "Order", "my order is late", false},
+		{models.MatchTypeStartsWith, false, "Hi", "hi there", true},
+		{models.MatchTypeStartsWith, true, "Hi", "Hi there", true},
+		{models.MatchTypeStartsWith, true, "Hi", "hi there", false},
+		// Regex ignores the flag and matches case-sensitively unless the pattern says otherwise
+		{models.MatchTypeRegex, false, "^STOP$", "stop", false},
+		{models.MatchTypeRegex, true, "(?i)^stop$", "STOP", true},
+	}
+
+	app := newProcessorTestApp(t)
+	for _, tt := range tests {
+		name := fmt.Sprintf("%s/case_sensitive=%t/%s", tt.matchType, tt.caseSensitive, tt.message)
+		t.Run(name, func(t *testing.T) {
+			org, account := createProcessorTestOrg(t, app)
+			require.NoError(t, app.DB.Create(&models.KeywordRule{
+				BaseModel:       models.BaseModel{ID: uuid.New()},
+				OrganizationID:  org.ID,
+				WhatsAppAccount: account.Name,
+				Name:            "case",
+				Keywords:        models.StringArray{tt.keyword},
+				MatchType:       tt.matchType,
+				CaseSensitive:   tt.caseSensitive,
+				ResponseType:    models.ResponseTypeText,
+				ResponseContent: models.JSONB{"body": "matched"},
+				IsEnabled:       true,
+			}).Error)
+
+			_, matched := app.matchKeywordRules(org.ID, account.Name, tt.message)
+			assert.Equal(t, tt.want, matched)
+		})
+	}
+}
+
 func TestMatchKeywordRules_ContainsMatch(t *testing.T) {
 	app := newProcessorTestApp(t)
 	org, account := createProcessorTestOrg(t, app)
@@ -145,6 +204,72 @@ func TestMatchKeywordRules_ContainsMatch(t *testing.T) {
 	assert.False(t, matched3)
 }
 
+func TestMatchKeywordRules_ContainsMatch_WordBoundary(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+
+	substring := &models.KeywordRule{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "contains-scan",
+		Keywords:        models.StringArray{"scan"},
+		MatchType:       models.MatchTypeContains,
+		ResponseType:    models.ResponseTypeText,
+		ResponseContent: models.JSONB{"body": "Substring response"},
+		Priority:        10,
+		IsEnabled:       true,
+	}
+	require.NoError(t, app.DB.Create(substring).Error)
+
+	// Substring mode (default) matches "scan" inside "scandal"
+	resp, matched := app.matchKeywordRules(org.ID, account.Name, "what a scandal")
+	assert.True(t, matched)
+	require.NotNil(t, resp)
+	assert.Equal(t, "Substring response", resp.Body)
+
+	// Switch the same rule to word-boundary mode
+	require.NoError(t, app.DB.Model(substring).Update("word_boundary", true).Error)
+	app.InvalidateKeywordRulesCache(org.ID)
+
+	_, matched2 := app.matchKeywordRules(org.ID, account.Name, "what a scandal")
+	assert.False(t, matched2)
+
+	resp3, matched3 := app.matchKeywordRules(org.ID, account.Name, "please SCAN this, thanks")
+	assert.True(t, matched3)
+	require.NotNil(t, resp3)
+	assert.Equal(t, "Substring response", resp3.Body)
+}
+
+func TestContainsWord(t *testing.T) {
+	tests := []struct {
+		text string
+		word string
+		want bool
+	}{
+		{"scan", "scan", true},
+		{"please scan it", "scan", true},
+		{"scan.", "scan", true},
+		{"(scan)", "scan", true},
+		{"scandal", "scan", false},
+		{"rescan", "scan", false},
+		{"scan_now", "scan", false},
+		{"scan2", "scan", false},
+		{"scandal then scan", "scan", true},
+		{"café scan", "scan", true},
+		{"écran", "cran", false},
+		{"order status", "order status", true},
+		{"", "scan", false},
+		{"scan", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text+"/"+tt.word, func(t *testing.T) {
+			assert.Equal(t, tt.want, containsWord(tt.text, tt.word))
+		})
+	}
+}
+
 func TestMatchKeywordRules_StartsWithMatch(t *testing.T) {
 	app := newProcessorTestApp(t)
 	org, account := createProcessorTestOrg(t, app)
@@ -312,6 +437,32 @@ func TestMatchKeywordRules_TransferType(t *testing.T) {
 	assert.Equal(t, "Connecting you to an agent...", resp.Body)
 }
 
+func TestMatchKeywordRules_TemplateWithoutTemplateIDSendsBody(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+
+	rule := &models.KeywordRule{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "legacy-template",
+		Keywords:        models.StringArray{"offer"},
+		MatchType:       models.MatchTypeExact,
+		ResponseType:    models.ResponseTypeTemplate,
+		ResponseContent: models.JSONB{"body": "This week's offer: 20% off."},
+		Priority:        10,
+		IsEnabled:       true,
+	}
+	require.NoError(t, app.DB.Create(rule).Error)
+
+	resp, matched := app.matchKeywordRules(org.ID, account.Name, "offer")
+	assert.True(t, matched)
+	require.NotNil(t, resp)
+	assert.Equal(t, models.ResponseTypeText, resp.ResponseType)
+	assert.Equal(t, "This week's offer: 20% off.", resp.Body)
+	assert.Equal(t, uuid.Nil, resp.TemplateID)
+}
+
 func TestMatchKeywordRules_WithButtons(t *testing.T) {
 	app := newProcessorTestApp(t)
 	org, account := createProcessorTestOrg(t, app)
@@ -343,6 +494,80 @@ func TestMatchKeywordRules_WithButtons(t *testing.T) {
 	assert.Len(t, resp.Buttons, 2)
 }
 
+func TestRecordKeywordRuleHit_IncrementsCounter(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+	rule := &models.KeywordRule{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "counted",
+		Keywords:        models.StringArray{"count"},
+		MatchType:       models.MatchTypeContains,
+		ResponseType:    models.ResponseTypeText,
+		ResponseContent: models.JSONB{"body": "Counted"},
+		IsEnabled:       true,
+	}
+	require.NoError(t, app.DB.Create(rule).Error)
+
+	app.recordKeywordRuleHit(org.ID, rule.ID, contact.ID, nil)
+	app.recordKeywordRuleHit(org.ID, rule.ID, contact.ID, nil)
+
+	var updated models.KeywordRule
+	require.NoError(t, app.DB.First(&updated, rule.ID).Error)
+	assert.Equal(t, int64(2), updated.HitCount)
+	assert.NotNil(t, updated.LastHitAt)
+
+	var hits int64
+	require.NoError(t, app.DB.Model(&models.KeywordRuleHit{}).Where("keyword_rule_id = ?", rule.ID).Count(&hits).Error)
+	assert.Equal(t, int64(2), hits)
+}
+
+func TestMatchKeywordRules_Variants(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+	rule := &models.KeywordRule{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "promo",
+		Keywords:        models.StringArray{"promo"},
+		MatchType:       models.MatchTypeExact,
+		ResponseType:    models.ResponseTypeText,
+		ResponseContent: models.JSONB{
+			"variants": []interface{}{
+				map[string]interface{}{"body": "Paused", "weight": 0},
+				map[string]interface{}{
+					"body":    "Use code SAVE10",
+					"weight":  1,
+					"buttons": []interface{}{map[string]interface{}{"id": "shop", "title": "Shop now"}},
+				},
+			},
+		},
+		Priority:  10,
+		IsEnabled: true,
+	}
+	require.NoError(t, app.DB.Create(rule).Error)
+
+	resp, matched := app.matchKeywordRules(org.ID, account.Name, "promo")
+	require.True(t, matched)
+	require.NotNil(t, resp.Variant)
+	assert.Equal(t, 1, *resp.Variant, "zero-weight variants are never picked")
+	assert.Equal(t, "Use code SAVE10", resp.Body)
+	assert.Len(t, resp.Buttons, 1)
+
+	app.recordKeywordRuleHit(org.ID, rule.ID, contact.ID, resp.Variant)
+
+	var hit models.KeywordRuleHit
+	require.NoError(t, app.DB.Where("keyword_rule_id = ?", rule.ID).First(&hit).Error)
+	require.NotNil(t, hit.Variant)
+	assert.Equal(t, 1, *hit.Variant)
+}
+
 // =============================================================================
 // getOrCreateSession
 // =============================================================================
@@ -352,7 +577,7 @@ func TestGetOrCreateSession_NewSession(t *testing.T) {
 	org, account := createProcessorTestOrg(t, app)
 	contact := testutil.CreateTestContact(t, app.DB, org.ID)
 
-	session, isNew := app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30)
+	session, isNew := app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30, 0)
 	assert.True(t, isNew)
 	require.NotNil(t, session)
 	assert.Equal(t, models.SessionStatusActive, session.Status)
@@ -385,7 +610,7 @@ func TestGetOrCreateSession_ExistingSession(t *testing.T) {
 	}
 	require.NoError(t, app.DB.Create(&existing).Error)
 
-	session, isNew := app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30)
+	session, isNew := app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30, 0)
 	assert.False(t, isNew)
 	require.NotNil(t, session)
 	assert.Equal(t, existing.ID, session.ID)
@@ -410,18 +635,99 @@ func TestGetOrCreateSession_ExpiredSession(t *testing.T) {
 	}
 	require.NoError(t, app.DB.Create(&expired).Error)
 
-	session, isNew := app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30)
+	session, isNew := app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30, 0)
 	assert.True(t, isNew)
 	require.NotNil(t, session)
 	assert.NotEqual(t, expired.ID, session.ID, "should create a new session, not return expired one")
 }
 
+func TestGetOrCreateSession_ResumesWithinGrace(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	flowID := uuid.New()
+	closedAt := time.Now().Add(-5 * time.Minute)
+	timedOut := models.ChatbotSession{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		ContactID:       contact.ID,
+		WhatsAppAccount: account.Name,
+		PhoneNumber:     contact.PhoneNumber,
+		Status:          models.SessionStatusTimeout,
+		CurrentFlowID:   &flowID,
+		CurrentStep:     "ask_email",
+		SessionData:     models.JSONB{"name": "Ada"},
+		StartedAt:       time.Now().Add(-60 * time.Minute),
+		LastActivityAt:  time.Now().Add(-35 * time.Minute),
+		CompletedAt:     &closedAt,
+	}
+	require.NoError(t, app.DB.Create(&timedOut).Error)
+
+	session, isNew := app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30, 10)
+	assert.False(t, isNew)
+	require.NotNil(t, session)
+	assert.Equal(t, timedOut.ID, session.ID)
+
+	var dbSession models.ChatbotSession
+	require.NoError(t, app.DB.First(&dbSession, session.ID).Error)
+	assert.Equal(t, models.SessionStatusActive, dbSession.Status)
+	assert.Nil(t, dbSession.CompletedAt)
+	assert.NotNil(t, dbSession.ResumedAt)
+	assert.Equal(t, 1, dbSession.ResumeCount)
+	assert.Equal(t, "ask_email", dbSession.CurrentStep)
+	assert.Equal(t, "Ada", dbSession.SessionData["name"])
+}
+
+func TestGetOrCreateSession_NoResumeOutsideGrace(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	create := func(status models.SessionStatus, closedAgo time.Duration) models.ChatbotSession {
+		closedAt := time.Now().Add(-closedAgo)
+		s := models.ChatbotSession{
+			BaseModel:       models.BaseModel{ID: uuid.New()},
+			OrganizationID:  org.ID,
+			ContactID:       contact.ID,
+			WhatsAppAccount: account.Name,
+			PhoneNumber:     contact.PhoneNumber,
+			Status:          status,
+			SessionData:     models.JSONB{},
+			StartedAt:       closedAt.Add(-40 * time.Minute),
+			LastActivityAt:  closedAt,
+			CompletedAt:     &closedAt,
+		}
+		require.NoError(t, app.DB.Create(&s).Error)
+		return s
+	}
+
+	// Timed out longer ago than the grace window
+	old := create(models.SessionStatusTimeout, 20*time.Minute)
+	session, isNew := app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30, 10)
+	assert.True(t, isNew)
+	assert.NotEqual(t, old.ID, session.ID)
+	require.NoError(t, app.DB.Delete(session).Error)
+
+	// Completed sessions are never resumed
+	completed := create(models.SessionStatusCompleted, time.Minute)
+	session, isNew = app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30, 10)
+	assert.True(t, isNew)
+	assert.NotEqual(t, completed.ID, session.ID)
+	require.NoError(t, app.DB.Delete(session).Error)
+
+	// Grace disabled
+	recent := create(models.SessionStatusTimeout, time.Minute)
+	session, isNew = app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30, 0)
+	assert.True(t, isNew)
+	assert.NotEqual(t, recent.ID, session.ID)
+}
+
 // =============================================================================
-// isWithinBusinessHours
+// BusinessHoursConfig.IsWithinBusinessHours
 // =============================================================================
 
 func TestIsWithinBusinessHours_WithinHours(t *testing.T) {
-	app := newProcessorTestApp(t)
 	now := time.Now()
 	dayOfWeek := float64(now.Weekday())
 
@@ -434,12 +740,11 @@ func TestIsWithinBusinessHours_WithinHours(t *testing.T) {
 		},
 	}
 
-	result := app.isWithinBusinessHours(hours)
+	result := models.BusinessHoursConfig{Hours: hours}.IsWithinBusinessHours(now)
 	assert.True(t, result)
 }
 
 func TestIsWithinBusinessHours_OutsideHours(t *testing.T) {
-	app := newProcessorTestApp(t)
 	now := time.Now()
 	dayOfWeek := float64(now.Weekday())
 
@@ -457,13 +762,12 @@ func TestIsWithinBusinessHours_OutsideHours(t *testing.T) {
 	// This will only be true if running at midnight; for all practical purposes it tests false
 	currentTime := now.Format("15:04")
 	if currentTime > "00:01" {
-		result := app.isWithinBusinessHours(hours)
+		result := models.BusinessHoursConfig{Hours: hours}.IsWithinBusinessHours(now)
 		assert.False(t, result)
 	}
 }
 
 func TestIsWithinBusinessHours_DayDisabled(t *testing.T) {
-	app := newProcessorTestApp(t)
 	now := time.Now()
 	dayOfWeek := float64(now.Weekday())
 
@@ -476,12 +780,11 @@ func TestIsWithinBusinessHours_DayDisabled(t *testing.T) {
 		},
 	}
 
-	result := app.isWithinBusinessHours(hours)
+	result := models.BusinessHoursConfig{Hours: hours}.IsWithinBusinessHours(now)
 	assert.False(t, result)
 }
 
 func TestIsWithinBusinessHours_NoMatchingDay(t *testing.T) {
-	app := newProcessorTestApp(t)
 	now := time.Now()
 	// Use a different day of the week
 	otherDay := float64((int(now.Weekday()) + 1) % 7)
@@ -495,14 +798,13 @@ func TestIsWithinBusinessHours_NoMatchingDay(t *testing.T) {
 		},
 	}
 
-	result := app.isWithinBusinessHours(hours)
+	result := models.BusinessHoursConfig{Hours: hours}.IsWithinBusinessHours(now)
 	assert.False(t, result)
 }
 
 func TestIsWithinBusinessHours_EmptyHours(t *testing.T) {
-	app := newProcessorTestApp(t)
 
-	result := app.isWithinBusinessHours(models.JSONBArray{})
+	result := models.BusinessHoursConfig{Hours: models.JSONBArray{}}.IsWithinBusinessHours(time.Now())
 	assert.False(t, result)
 }
 
@@ -732,6 +1034,227 @@ func TestCompleteFlow_UpdatesSession(t *testing.T) {
 	assert.Equal(t, models.SessionStatusCompleted, dbSession.Status)
 	assert.Equal(t, "", dbSession.CurrentStep)
 	assert.NotNil(t, dbSession.CompletedAt)
+	assert.NotNil(t, dbSession.FlowCompletedAt)
+}
+
+func TestCompleteFlow_WithoutCompletionMessageCountsAsCompleted(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	flow := &models.ChatbotFlow{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "Silent Flow",
+		IsEnabled:       true,
+	}
+	require.NoError(t, app.DB.Create(flow).Error)
+
+	session := &models.ChatbotSession{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		ContactID:       contact.ID,
+		WhatsAppAccount: account.Name,
+		PhoneNumber:     contact.PhoneNumber,
+		Status:          models.SessionStatusActive,
+		CurrentFlowID:   &flow.ID,
+		CurrentStep:     "step1",
+		StartedAt:       time.Now(),
+		LastActivityAt:  time.Now(),
+	}
+	require.NoError(t, app.DB.Create(session).Error)
+
+	app.completeFlow(account, session, contact, flow)
+
+	// No completion message is logged, but the session still counts as completed
+	var logged int64
+	require.NoError(t, app.DB.Model(&models.ChatbotSessionMessage{}).
+		Where("session_id = ? AND step_name = ?", session.ID, "flow_complete").Count(&logged).Error)
+	assert.Zero(t, logged)
+
+	now := time.Now()
+	rows, err := app.flowSessionCounts(org.ID, now.Add(-time.Hour), now.Add(time.Minute), now, &flow.ID)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, int64(1), rows[0].Started)
+	assert.Equal(t, int64(1), rows[0].Completed)
+	assert.Zero(t, rows[0].Abandoned)
+}
+
+// createWebhookFlowSession creates a webhook-completing flow and an active session for it.
+func createWebhookFlowSession(t *testing.T, app *App, url string) (*models.WhatsAppAccount, *models.Contact, *models.ChatbotFlow, *models.ChatbotSession) {
+	t.Helper()
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	flow := &models.ChatbotFlow{
+		BaseModel:        models.BaseModel{ID: uuid.New()},
+		OrganizationID:   org.ID,
+		WhatsAppAccount:  account.Name,
+		Name:             "Webhook Flow",
+		OnCompleteAction: "webhook",
+		CompletionConfig: models.JSONB{"url": url},
+		IsEnabled:        true,
+	}
+	require.NoError(t, app.DB.Create(flow).Error)
+
+	session := &models.ChatbotSession{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		ContactID:       contact.ID,
+		WhatsAppAccount: account.Name,
+		PhoneNumber:     contact.PhoneNumber,
+		Status:          models.SessionStatusActive,
+		CurrentFlowID:   &flow.ID,
+		SessionData:     models.JSONB{"name": "John"},
+		StartedAt:       time.Now(),
+		LastActivityAt:  time.Now(),
+	}
+	require.NoError(t, app.DB.Create(session).Error)
+	return account, contact, flow, session
+}
+
+// completeWebhookFlow completes the flow and returns its delivery record once sending finishes.
+func completeWebhookFlow(t *testing.T, app *App, url string, orgSettings models.JSONB) models.FlowWebhookDelivery {
+	t.Helper()
+	app.HTTPClient = &http.Client{}
+	account, contact, flow, session := createWebhookFlowSession(t, app, url)
+	if orgSettings != nil {
+		require.NoError(t, app.DB.Model(&models.Organization{}).Where("id = ?", flow.OrganizationID).
+			Update("settings", orgSettings).Error)
+	}
+
+	app.completeFlow(account, session, contact, flow)
+	app.WaitForBackgroundTasks()
+
+	var delivery models.FlowWebhookDelivery
+	require.NoError(t, app.DB.Where("session_id = ?", session.ID).First(&delivery).Error)
+	assert.Equal(t, flow.ID, delivery.FlowID)
+	return delivery
+}
+
+func TestCompleteFlow_WebhookDeliveredAndSigned(t *testing.T) {
+	app := newProcessorTestApp(t)
+
+	var (
+		body      []byte
+		signature string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get("X-Webhook-Signature")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	delivery := completeWebhookFlow(t, app, server.URL, models.JSONB{"flow_webhook_secret": "s3cret"})
+
+	assert.Equal(t, models.WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusOK, delivery.LastStatusCode)
+	assert.NotNil(t, delivery.DeliveredAt)
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, map[string]interface{}{"name": "John"}, payload["session_data"])
+	assert.Equal(t, computeHMACSignature(body, "s3cret"), signature)
+}
+
+func TestCompleteFlow_WebhookRetriesOnServerError(t *testing.T) {
+	app := newProcessorTestApp(t)
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	delivery := completeWebhookFlow(t, app, server.URL, nil)
+
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, models.WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Empty(t, delivery.LastError)
+}
+
+func TestCompleteFlow_WebhookClientErrorNotRetried(t *testing.T) {
+	app := newProcessorTestApp(t)
+
+	var calls atomic.Int32
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		signature = r.Header.Get("X-Webhook-Signature")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("bad payload"))
+	}))
+	defer server.Close()
+
+	delivery := completeWebhookFlow(t, app, server.URL, nil)
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, models.WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusBadRequest, delivery.LastStatusCode)
+	assert.Contains(t, delivery.LastError, "bad payload")
+	assert.Empty(t, signature, "unsigned without an org secret")
+}
+
+func TestCompleteFlow_StrictSessionDataSkipsWebhook(t *testing.T) {
+	app := newProcessorTestApp(t)
+	app.HTTPClient = &http.Client{}
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	account, contact, flow, session := createWebhookFlowSession(t, app, server.URL)
+	// The session stored "name" but the flow only collects "email"
+	flow.Steps = []models.ChatbotFlowStep{{StepName: "ask_email", InputType: models.InputTypeEmail, StoreAs: "email"}}
+	flow.CompletionConfig["strict_session_data"] = true
+
+	app.completeFlow(account, session, contact, flow)
+	app.WaitForBackgroundTasks()
+
+	assert.Equal(t, int32(0), calls.Load())
+	var delivery models.FlowWebhookDelivery
+	require.NoError(t, app.DB.Where("session_id = ?", session.ID).First(&delivery).Error)
+	assert.Equal(t, models.WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, 0, delivery.Attempts)
+	assert.Contains(t, delivery.LastError, "name: not stored by any step in the flow")
+}
+
+func TestCompleteFlow_WebhookIncludesValidationErrors(t *testing.T) {
+	app := newProcessorTestApp(t)
+	app.HTTPClient = &http.Client{}
+
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	account, contact, flow, session := createWebhookFlowSession(t, app, server.URL)
+	flow.Steps = []models.ChatbotFlowStep{{StepName: "ask_email", InputType: models.InputTypeEmail, StoreAs: "email"}}
+
+	app.completeFlow(account, session, contact, flow)
+	app.WaitForBackgroundTasks()
+
+	var payload struct {
+		ValidationErrors []SessionDataError `json:"validation_errors"`
+	}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	require.Len(t, payload.ValidationErrors, 1)
+	assert.Equal(t, "name", payload.ValidationErrors[0].Key)
 }
 
 // =============================================================================
@@ -788,7 +1311,7 @@ func TestSaveIncomingMessage_TextMessage(t *testing.T) {
 	contact := testutil.CreateTestContact(t, app.DB, org.ID)
 
 	waMsgID := "wamid." + uuid.New().String()[:16]
-	app.saveIncomingMessage(account, contact, waMsgID, "text", "Hello from test", nil, "")
+	app.saveIncomingMessage(account, contact, waMsgID, "text", "Hello from test", nil, nil, "")
 
 	// Verify message was saved
 	var msg models.Message
@@ -805,6 +1328,7 @@ func TestSaveIncomingMessage_TextMessage(t *testing.T) {
 	require.NoError(t, app.DB.First(&dbContact, contact.ID).Error)
 	assert.NotNil(t, dbContact.LastMessageAt)
 	assert.Equal(t, "Hello from test", dbContact.LastMessagePreview)
+	assert.Equal(t, models.DirectionIncoming, dbContact.LastMessageDirection)
 	assert.False(t, dbContact.IsRead)
 }
 
@@ -819,7 +1343,7 @@ func TestSaveIncomingMessage_WithMedia(t *testing.T) {
 		MediaMimeType: "image/jpeg",
 		MediaFilename: "photo.jpg",
 	}
-	app.saveIncomingMessage(account, contact, waMsgID, "image", "Look at this", media, "")
+	app.saveIncomingMessage(account, contact, waMsgID, "image", "Look at this", media, nil, "")
 
 	var msg models.Message
 	require.NoError(t, app.DB.Where("whats_app_message_id = ?", waMsgID).First(&msg).Error)
@@ -855,7 +1379,7 @@ func TestSaveIncomingMessage_WithReplyContext(t *testing.T) {
 
 	// Save reply message
 	replyWAMID := "wamid.reply_" + uuid.New().String()[:8]
-	app.saveIncomingMessage(account, contact, replyWAMID, "text", "Reply to your message", nil, originalWAMID)
+	app.saveIncomingMessage(account, contact, replyWAMID, "text", "Reply to your message", nil, nil, originalWAMID)
 
 	var replyMsg models.Message
 	require.NoError(t, app.DB.Where("whats_app_message_id = ?", replyWAMID).First(&replyMsg).Error)
@@ -864,6 +1388,53 @@ func TestSaveIncomingMessage_WithReplyContext(t *testing.T) {
 	assert.Equal(t, originalMsg.ID, *replyMsg.ReplyToMessageID)
 }
 
+func TestProcessIncomingMessage_ListReplyLinksToOriginal(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+	phone := uniqueTestPhone()
+	contact := &models.Contact{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		PhoneNumber:     phone,
+		WhatsAppAccount: account.Name,
+	}
+	require.NoError(t, app.DB.Create(contact).Error)
+
+	menuWAMID := "wamid.menu_" + uuid.New().String()[:8]
+	menu := models.Message{
+		BaseModel:         models.BaseModel{ID: uuid.New()},
+		OrganizationID:    org.ID,
+		WhatsAppAccount:   account.Name,
+		ContactID:         contact.ID,
+		WhatsAppMessageID: menuWAMID,
+		Direction:         models.DirectionOutgoing,
+		MessageType:       models.MessageTypeInteractive,
+		Content:           "Pick a size",
+		Status:            models.MessageStatusDelivered,
+	}
+	require.NoError(t, app.DB.Create(&menu).Error)
+
+	msg := IncomingTextMessage{From: phone, ID: "wamid.pick_" + uuid.New().String()[:8], Type: "interactive"}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "list_reply",
+		"list_reply": {"id": "size_large", "title": "Large"}
+	}`), &msg.Interactive))
+	require.NoError(t, json.Unmarshal([]byte(`{"from": "`+phone+`", "id": "`+menuWAMID+`"}`), &msg.Context))
+
+	app.processIncomingMessageFull(account.PhoneID, msg, "Customer")
+
+	var reply models.Message
+	require.NoError(t, app.DB.Where("whats_app_message_id = ?", msg.ID).First(&reply).Error)
+	assert.Equal(t, "Large", reply.Content)
+	require.NotNil(t, reply.ReplyToMessageID)
+	assert.Equal(t, menu.ID, *reply.ReplyToMessageID)
+	assert.Equal(t, "list_reply", reply.InteractiveData["type"])
+	assert.Equal(t, "size_large", reply.InteractiveData["id"])
+}
+
 func TestSaveIncomingMessage_LongContent(t *testing.T) {
 	app := newProcessorTestApp(t)
 	org, account := createProcessorTestOrg(t, app)
@@ -875,7 +1446,7 @@ func TestSaveIncomingMessage_LongContent(t *testing.T) {
 		longContent += "x"
 	}
 	waMsgID := "wamid." + uuid.New().String()[:16]
-	app.saveIncomingMessage(account, contact, waMsgID, "text", longContent, nil, "")
+	app.saveIncomingMessage(account, contact, waMsgID, "text", longContent, nil, nil, "")
 
 	var dbContact models.Contact
 	require.NoError(t, app.DB.First(&dbContact, contact.ID).Error)
@@ -884,11 +1455,75 @@ func TestSaveIncomingMessage_LongContent(t *testing.T) {
 	assert.True(t, len(dbContact.LastMessagePreview) <= 100)
 }
 
-// =============================================================================
-// replaceVariables
-// =============================================================================
-
-func TestReplaceVariables_Basic(t *testing.T) {
+func TestSaveIncomingMessage_DispatchesWebhook(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	app.HTTPClient = &http.Client{}
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	var (
+		calls     atomic.Int32
+		body      []byte
+		header    http.Header
+		signature string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt so the retry is delivered with the same ID
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header.Clone()
+		signature = r.Header.Get("X-Webhook-Signature")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	require.NoError(t, app.DB.Create(&models.Webhook{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		Name:           "inbound-mirror",
+		URL:            server.URL,
+		Events:         models.StringArray{string(models.WebhookEventMessageIncoming)},
+		Secret:         "mirror-secret",
+		IsActive:       true,
+	}).Error)
+
+	waMsgID := "wamid." + uuid.New().String()[:16]
+	media := &MediaInfo{MediaURL: "/uploads/doc.pdf", MediaMimeType: "application/pdf", MediaFilename: "doc.pdf"}
+	app.saveIncomingMessage(account, contact, waMsgID, "document", "Invoice", media, nil, "")
+	app.WaitForBackgroundTasks()
+
+	require.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, computeHMACSignature(body, "mirror-secret"), signature)
+
+	var payload struct {
+		ID    string           `json:"id"`
+		Event string           `json:"event"`
+		Data  MessageEventData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.NotEmpty(t, payload.ID)
+	assert.Equal(t, payload.ID, header.Get("X-Webhook-ID"))
+	assert.Equal(t, string(models.WebhookEventMessageIncoming), payload.Event)
+	assert.Equal(t, contact.ID.String(), payload.Data.ContactID)
+	assert.Equal(t, contact.PhoneNumber, payload.Data.ContactPhone)
+	assert.Equal(t, waMsgID, payload.Data.WAMID)
+	assert.Equal(t, "Invoice", payload.Data.Content)
+	assert.Equal(t, "/uploads/doc.pdf", payload.Data.MediaURL)
+	assert.Equal(t, "application/pdf", payload.Data.MediaMimeType)
+	assert.NotNil(t, payload.Data.CreatedAt)
+}
+
+// =============================================================================
+// replaceVariables
+// =============================================================================
+
+func TestReplaceVariables_Basic(t *testing.T) {
 	app := newProcessorTestApp(t)
 
 	result := app.replaceVariables("Hello {{name}}, your order is {{order_id}}", models.JSONB{
@@ -962,15 +1597,318 @@ func TestMatchFlowTrigger_Match(t *testing.T) {
 	}
 	require.NoError(t, app.DB.Create(flow).Error)
 
-	result := app.matchFlowTrigger(org.ID, account.Name, "I want to order")
+	result := app.matchFlowTrigger(org.ID, account.Name, flowTriggerInput{Text: "I want to order"})
 	require.NotNil(t, result)
 	assert.Equal(t, flow.ID, result.ID)
 
 	// No match
-	noMatch := app.matchFlowTrigger(org.ID, account.Name, "hello there")
+	noMatch := app.matchFlowTrigger(org.ID, account.Name, flowTriggerInput{Text: "hello there"})
 	assert.Nil(t, noMatch)
 }
 
+func TestMatchFlowTrigger_SkipsFlowsOutsideActiveWindow(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+
+	past := time.Now().Add(-48 * time.Hour)
+	yesterday := time.Now().Add(-24 * time.Hour)
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	expired := &models.ChatbotFlow{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "Expired Promo",
+		TriggerKeywords: models.StringArray{"promo"},
+		IsEnabled:       true,
+		ActiveFrom:      &past,
+		ActiveUntil:     &yesterday,
+	}
+	upcoming := &models.ChatbotFlow{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "Upcoming Sale",
+		TriggerKeywords: models.StringArray{"sale"},
+		IsEnabled:       true,
+		ActiveFrom:      &tomorrow,
+	}
+	current := &models.ChatbotFlow{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "Current Deal",
+		TriggerKeywords: models.StringArray{"deal"},
+		IsEnabled:       true,
+		ActiveFrom:      &yesterday,
+		ActiveUntil:     &tomorrow,
+	}
+	require.NoError(t, app.DB.Create(expired).Error)
+	require.NoError(t, app.DB.Create(upcoming).Error)
+	require.NoError(t, app.DB.Create(current).Error)
+
+	assert.Nil(t, app.matchFlowTrigger(org.ID, account.Name, flowTriggerInput{Text: "any promo left?"}))
+	assert.Nil(t, app.matchFlowTrigger(org.ID, account.Name, flowTriggerInput{Text: "when is the sale"}))
+
+	result := app.matchFlowTrigger(org.ID, account.Name, flowTriggerInput{Text: "show me the deal"})
+	require.NotNil(t, result)
+	assert.Equal(t, current.ID, result.ID)
+}
+
+func TestMatchFlowTrigger_TriggerTypes(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+
+	newFlow := func(name string, triggerType models.FlowTriggerType, buttonID string) *models.ChatbotFlow {
+		flow := &models.ChatbotFlow{
+			BaseModel:       models.BaseModel{ID: uuid.New()},
+			OrganizationID:  org.ID,
+			WhatsAppAccount: account.Name,
+			Name:            name,
+			TriggerType:     triggerType,
+			TriggerKeywords: models.StringArray{"help"},
+			TriggerButtonID: buttonID,
+			IsEnabled:       true,
+		}
+		require.NoError(t, app.DB.Create(flow).Error)
+		return flow
+	}
+	keyword := newFlow("Help", models.FlowTriggerKeyword, "")
+	welcome := newFlow("Welcome", models.FlowTriggerFirstInbound, "")
+	afterHours := newFlow("After Hours", models.FlowTriggerAfterHours, "")
+	button := newFlow("Book Demo", models.FlowTriggerButton, "book_demo")
+
+	cases := []struct {
+		name string
+		in   flowTriggerInput
+		want *models.ChatbotFlow
+	}{
+		{"keyword only", flowTriggerInput{Text: "I need help"}, keyword},
+		{"first inbound beats keyword", flowTriggerInput{Text: "help", FirstInbound: true}, welcome},
+		{"after hours", flowTriggerInput{Text: "hello", OutsideHours: true}, afterHours},
+		{"button beats everything", flowTriggerInput{Text: "help", ButtonID: "book_demo", FirstInbound: true, OutsideHours: true}, button},
+		{"other button", flowTriggerInput{Text: "Pricing", ButtonID: "pricing"}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := app.matchFlowTrigger(org.ID, account.Name, tc.in)
+			if tc.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tc.want.ID, got.ID)
+		})
+	}
+}
+
+func TestProcessIncomingMessage_FirstInboundStartsFlow(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:          models.BaseModel{ID: uuid.New()},
+		OrganizationID:     org.ID,
+		WhatsAppAccount:    account.Name,
+		IsEnabled:          true,
+		SessionTimeoutMins: 30,
+	}).Error)
+
+	flowID := uuid.New()
+	flow := &models.ChatbotFlow{
+		BaseModel:       models.BaseModel{ID: flowID},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "Welcome",
+		TriggerType:     models.FlowTriggerFirstInbound,
+		IsEnabled:       true,
+		Steps: []models.ChatbotFlowStep{
+			{
+				BaseModel:   models.BaseModel{ID: uuid.New()},
+				FlowID:      flowID,
+				StepName:    "ask_name",
+				StepOrder:   1,
+				Message:     "What is your name?",
+				MessageType: models.FlowStepTypeText,
+				InputType:   models.InputTypeText,
+				StoreAs:     "name",
+			},
+		},
+	}
+	require.NoError(t, app.DB.Create(flow).Error)
+
+	phone := uniqueTestPhone()
+	msg := IncomingTextMessage{From: phone, ID: "wamid.first_inbound", Type: "text"}
+	msg.Text = &struct {
+		Body string `json:"body"`
+	}{Body: "hi"}
+	app.processIncomingMessageFull(account.PhoneID, msg, "Newcomer")
+
+	var contact models.Contact
+	require.NoError(t, app.DB.Where("organization_id = ? AND phone_number = ?", org.ID, phone).First(&contact).Error)
+
+	var session models.ChatbotSession
+	require.NoError(t, app.DB.Where("contact_id = ?", contact.ID).First(&session).Error)
+	require.NotNil(t, session.CurrentFlowID, "first inbound flow should start for a brand-new contact")
+	assert.Equal(t, flowID, *session.CurrentFlowID)
+	assert.Equal(t, "ask_name", session.CurrentStep)
+}
+
+func TestProcessIncomingMessage_ButtonReplyFollowsButtonNextStep(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:          models.BaseModel{ID: uuid.New()},
+		OrganizationID:     org.ID,
+		WhatsAppAccount:    account.Name,
+		IsEnabled:          true,
+		SessionTimeoutMins: 30,
+	}).Error)
+
+	flowID := uuid.New()
+	step := func(name string, order int, inputType models.InputType) models.ChatbotFlowStep {
+		return models.ChatbotFlowStep{
+			BaseModel:   models.BaseModel{ID: uuid.New()},
+			FlowID:      flowID,
+			StepName:    name,
+			StepOrder:   order,
+			Message:     name,
+			MessageType: models.FlowStepTypeText,
+			InputType:   inputType,
+		}
+	}
+	menu := step("menu", 1, models.InputTypeButton)
+	menu.MessageType = models.FlowStepTypeButtons
+	menu.Buttons = models.JSONBArray{
+		map[string]interface{}{"id": "support", "title": "Support"},
+		map[string]interface{}{"id": "sales", "title": "Sales", "next_step": "sales_desk"},
+	}
+	require.NoError(t, app.DB.Create(&models.ChatbotFlow{
+		BaseModel:       models.BaseModel{ID: flowID},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "Menu",
+		TriggerType:     models.FlowTriggerFirstInbound,
+		IsEnabled:       true,
+		Steps: []models.ChatbotFlowStep{
+			menu,
+			step("support_desk", 2, models.InputTypeText),
+			step("sales_desk", 3, models.InputTypeText),
+		},
+	}).Error)
+
+	phone := uniqueTestPhone()
+	first := IncomingTextMessage{From: phone, ID: "wamid.menu_start_" + uuid.New().String()[:8], Type: "text"}
+	first.Text = &struct {
+		Body string `json:"body"`
+	}{Body: "hi"}
+	app.processIncomingMessageFull(account.PhoneID, first, "Customer")
+
+	tap := func(id, title string) {
+		msg := IncomingTextMessage{From: phone, ID: "wamid.tap_" + uuid.New().String()[:8], Type: "interactive"}
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"type": "button_reply",
+			"button_reply": {"id": "`+id+`", "title": "`+title+`"}
+		}`), &msg.Interactive))
+		app.processIncomingMessageFull(account.PhoneID, msg, "Customer")
+	}
+	session := func() models.ChatbotSession {
+		var contact models.Contact
+		require.NoError(t, app.DB.Where("organization_id = ? AND phone_number = ?", org.ID, phone).First(&contact).Error)
+		var s models.ChatbotSession
+		require.NoError(t, app.DB.Where("contact_id = ?", contact.ID).Order("created_at DESC").First(&s).Error)
+		return s
+	}
+	require.Equal(t, "menu", session().CurrentStep)
+
+	// An ID the step didn't offer is retried instead of advancing
+	tap("refunds", "Refunds")
+	s := session()
+	assert.Equal(t, "menu", s.CurrentStep)
+	assert.Equal(t, 1, s.StepRetries)
+
+	// The button's next_step wins over step order
+	tap("sales", "Sales")
+	assert.Equal(t, "sales_desk", session().CurrentStep)
+}
+
+func TestProcessIncomingMessage_PausedContactSkipsChatbot(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:          models.BaseModel{ID: uuid.New()},
+		OrganizationID:     org.ID,
+		WhatsAppAccount:    account.Name,
+		IsEnabled:          true,
+		SessionTimeoutMins: 30,
+	}).Error)
+
+	flowID := uuid.New()
+	require.NoError(t, app.DB.Create(&models.ChatbotFlow{
+		BaseModel:       models.BaseModel{ID: flowID},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "Welcome",
+		TriggerType:     models.FlowTriggerFirstInbound,
+		IsEnabled:       true,
+		Steps: []models.ChatbotFlowStep{
+			{
+				BaseModel:   models.BaseModel{ID: uuid.New()},
+				FlowID:      flowID,
+				StepName:    "ask_name",
+				StepOrder:   1,
+				Message:     "What is your name?",
+				MessageType: models.FlowStepTypeText,
+				InputType:   models.InputTypeText,
+				StoreAs:     "name",
+			},
+		},
+	}).Error)
+
+	sendFrom := func(contact *models.Contact) {
+		msg := IncomingTextMessage{From: contact.PhoneNumber, ID: "wamid." + uuid.NewString(), Type: "text"}
+		msg.Text = &struct {
+			Body string `json:"body"`
+		}{Body: "hi"}
+		app.processIncomingMessageFull(account.PhoneID, msg, "Customer")
+	}
+
+	// Paused indefinitely: the message is saved but the bot stays quiet
+	paused := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(paused).Update("bot_paused", true).Error)
+	sendFrom(paused)
+
+	var count int64
+	app.DB.Model(&models.Message{}).Where("contact_id = ?", paused.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+	app.DB.Model(&models.ChatbotSession{}).Where("contact_id = ?", paused.ID).Count(&count)
+	assert.Zero(t, count, "paused contact should not start a flow")
+
+	// An expired pause is cleared and the bot responds again
+	expired := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(expired).Updates(map[string]any{
+		"bot_paused":    true,
+		"bot_resume_at": time.Now().Add(-time.Minute),
+	}).Error)
+	sendFrom(expired)
+
+	var resumed models.Contact
+	require.NoError(t, app.DB.First(&resumed, expired.ID).Error)
+	assert.False(t, resumed.BotPaused)
+	assert.Nil(t, resumed.BotResumeAt)
+	var session models.ChatbotSession
+	require.NoError(t, app.DB.Where("contact_id = ?", expired.ID).First(&session).Error)
+	assert.Equal(t, "ask_name", session.CurrentStep)
+}
+
 // =============================================================================
 // evaluateExpression (package-level, not on App)
 // =============================================================================
@@ -1007,3 +1945,599 @@ func TestEvaluateExpression_Parentheses(t *testing.T) {
 func TestEvaluateExpression_EmptyExpression(t *testing.T) {
 	assert.False(t, evaluateExpression("", map[string]interface{}{}))
 }
+
+// =============================================================================
+// AI context selection
+// =============================================================================
+
+func TestMatchAIContexts(t *testing.T) {
+	contexts := []models.AIContext{
+		{Name: "Shipping", Priority: 5, TriggerKeywords: models.StringArray{"shipping", "delivery"}},
+		{Name: "Company", Priority: 1},
+		{Name: "Returns", Priority: 20, TriggerKeywords: models.StringArray{"return"}},
+		{Name: "Pricing", Priority: 5, TriggerKeywords: models.StringArray{"price"}},
+	}
+
+	names := func(matched []models.AIContext) []string {
+		result := make([]string, len(matched))
+		for i, ctx := range matched {
+			result[i] = ctx.Name
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"Returns", "Shipping", "Company"}, names(matchAIContexts(contexts, "When is DELIVERY for my return?", 0)))
+	assert.Equal(t, []string{"Returns", "Shipping"}, names(matchAIContexts(contexts, "When is delivery for my return?", 2)))
+	assert.Equal(t, []string{"Shipping", "Pricing", "Company"}, names(matchAIContexts(contexts, "delivery price", 0)), "equal priorities keep their order")
+	assert.Equal(t, []string{"Company"}, names(matchAIContexts(contexts, "hello", 0)))
+}
+
+func TestFitAIContextBudget(t *testing.T) {
+	sections := []aiContextSection{
+		{Context: models.AIContext{Name: "High"}, Content: "0123456789"},
+		{Context: models.AIContext{Name: "Mid"}, Content: "abcdefghij"},
+		{Context: models.AIContext{Name: "Low"}, Content: "ABCDEFGHIJ"},
+	}
+
+	assert.Equal(t, sections, fitAIContextBudget(sections, 0))
+
+	fitted := fitAIContextBudget(sections, 15)
+	require.Len(t, fitted, 2)
+	assert.Equal(t, "0123456789", fitted[0].Content)
+	assert.False(t, fitted[0].Truncated)
+	assert.Equal(t, "abcde", fitted[1].Content)
+	assert.True(t, fitted[1].Truncated)
+
+	// Characters, not bytes, are counted
+	fitted = fitAIContextBudget([]aiContextSection{{Content: "héllo wörld"}}, 4)
+	assert.Equal(t, "héll", fitted[0].Content)
+
+	assert.Equal(t, "## Context Information\n\n### High\n0123456789", formatAIContext(fitAIContextBudget(sections, 10)))
+	assert.Empty(t, formatAIContext(nil))
+}
+
+// =============================================================================
+// generateAIResponse provider selection
+// =============================================================================
+
+// aiProviderTransport records outgoing AI requests and answers with a canned body.
+type aiProviderTransport struct {
+	hosts    []string
+	headers  []http.Header
+	requests []string
+	body     string
+}
+
+func (t *aiProviderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.hosts = append(t.hosts, req.URL.Host)
+	t.headers = append(t.headers, req.Header.Clone())
+	if req.Body != nil {
+		reqBody, _ := io.ReadAll(req.Body)
+		t.requests = append(t.requests, string(reqBody))
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(t.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestGenerateAIResponse_UsesAnthropicProvider(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+
+	transport := &aiProviderTransport{body: `{"content":[{"type":"text","text":"Hi from Claude"}]}`}
+	app.HTTPClient = &http.Client{Transport: transport}
+
+	settings := &models.ChatbotSettings{
+		OrganizationID: org.ID,
+		AI: models.AIConfig{
+			Enabled:   true,
+			Provider:  models.AIProviderAnthropic,
+			APIKey:    "sk-ant-test",
+			Model:     "claude-3-5-sonnet-latest",
+			MaxTokens: 200,
+		},
+	}
+
+	resp, err := app.generateAIResponse(settings, nil, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "Hi from Claude", resp)
+	require.Len(t, transport.hosts, 1)
+	assert.Equal(t, "api.anthropic.com", transport.hosts[0])
+	assert.Equal(t, "sk-ant-test", transport.headers[0].Get("x-api-key"))
+}
+
+func TestGenerateAIResponse_UsesOpenAIProvider(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+
+	transport := &aiProviderTransport{body: `{"choices":[{"message":{"content":"Hi from GPT"}}]}`}
+	app.HTTPClient = &http.Client{Transport: transport}
+
+	settings := &models.ChatbotSettings{
+		OrganizationID: org.ID,
+		AI: models.AIConfig{
+			Enabled:   true,
+			Provider:  models.AIProviderOpenAI,
+			APIKey:    "sk-openai-test",
+			Model:     "gpt-4o-mini",
+			MaxTokens: 200,
+		},
+	}
+
+	resp, err := app.generateAIResponse(settings, nil, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "Hi from GPT", resp)
+	require.Len(t, transport.hosts, 1)
+	assert.Equal(t, "api.openai.com", transport.hosts[0])
+}
+
+// failingAITransport answers every AI request with a server error, or waits
+// for the request to be cancelled when hang is set.
+type failingAITransport struct {
+	calls atomic.Int32
+	hang  bool
+}
+
+func (t *failingAITransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls.Add(1)
+	if t.hang {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	return &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"error":{"message":"overloaded"}}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestAIRequestTimeout(t *testing.T) {
+	assert.Equal(t, aiDefaultTimeout, aiRequestTimeout(models.AIConfig{}))
+	assert.Equal(t, 5*time.Second, aiRequestTimeout(models.AIConfig{TimeoutSecs: 5}))
+}
+
+func TestGenerateAIResponse_TimesOut(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+
+	app.HTTPClient = &http.Client{Transport: &failingAITransport{hang: true}}
+	settings := &models.ChatbotSettings{
+		OrganizationID: org.ID,
+		AI: models.AIConfig{
+			Enabled:     true,
+			Provider:    models.AIProviderOpenAI,
+			APIKey:      "sk-openai-test",
+			Model:       "gpt-4o-mini",
+			TimeoutSecs: 1,
+		},
+	}
+
+	start := time.Now()
+	_, err := app.generateAIResponse(settings, nil, "hello")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestProcessIncomingMessage_AIFailureTransfersToAgent(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+
+	transport := &failingAITransport{}
+	app.HTTPClient = &http.Client{Transport: transport}
+
+	settings := &models.ChatbotSettings{
+		BaseModel:          models.BaseModel{ID: uuid.New()},
+		OrganizationID:     org.ID,
+		WhatsAppAccount:    account.Name,
+		IsEnabled:          true,
+		SessionTimeoutMins: 30,
+		FallbackMessage:    "Sorry, I didn't get that.",
+		AI: models.AIConfig{
+			Enabled:       true,
+			Provider:      models.AIProviderOpenAI,
+			APIKey:        "sk-openai-test",
+			Model:         "gpt-4o-mini",
+			FailureAction: models.AIFailureTransfer,
+		},
+	}
+	require.NoError(t, app.DB.Create(settings).Error)
+
+	phone := uniqueTestPhone()
+	msg := IncomingTextMessage{From: phone, ID: "wamid.ai-fail-1", Type: "text"}
+	msg.Text = &struct {
+		Body string `json:"body"`
+	}{Body: "hello"}
+	app.processIncomingMessageFull(account.PhoneID, msg, "Customer")
+	require.Equal(t, int32(1), transport.calls.Load())
+
+	var transfer models.AgentTransfer
+	require.NoError(t, app.DB.Where("organization_id = ? AND source = ?", org.ID, models.TransferSourceAIFailure).First(&transfer).Error)
+	assert.Equal(t, account.Name, transfer.WhatsAppAccount)
+	assert.Equal(t, models.TransferStatusActive, transfer.Status)
+
+	var fallbacks int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("organization_id = ? AND direction = ? AND content = ?", org.ID, models.DirectionOutgoing, settings.FallbackMessage).
+		Count(&fallbacks).Error)
+	assert.Zero(t, fallbacks, "the fallback message is replaced by the transfer")
+}
+
+// =============================================================================
+// Phone blacklist
+// =============================================================================
+
+func TestProcessIncomingMessage_BlacklistedNumberDropped(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+
+	phone := uniqueTestPhone()
+	require.NoError(t, app.DB.Create(&models.PhoneBlacklist{
+		OrganizationID: org.ID,
+		PhoneNumber:    phone,
+		Reason:         "spam",
+	}).Error)
+
+	msg := IncomingTextMessage{
+		From: "+" + phone,
+		ID:   "wamid.blacklisted",
+		Type: "text",
+	}
+	msg.Text = &struct {
+		Body string `json:"body"`
+	}{Body: "hello"}
+
+	app.processIncomingMessageFull(account.PhoneID, msg, "Spammer")
+
+	var contacts int64
+	require.NoError(t, app.DB.Model(&models.Contact{}).
+		Where("organization_id = ? AND phone_number = ?", org.ID, phone).Count(&contacts).Error)
+	assert.Zero(t, contacts, "no contact should be created for a blacklisted number")
+
+	var messages int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("organization_id = ?", org.ID).Count(&messages).Error)
+	assert.Zero(t, messages, "no message should be stored for a blacklisted number")
+}
+
+// =============================================================================
+// Opt-out keywords
+// =============================================================================
+
+// incomingText builds an incoming text message from phone
+func incomingText(phone, body string) IncomingTextMessage {
+	msg := IncomingTextMessage{
+		From: phone,
+		ID:   "wamid.in_" + uuid.New().String()[:8],
+		Type: "text",
+	}
+	msg.Text = &struct {
+		Body string `json:"body"`
+	}{Body: body}
+	return msg
+}
+
+func TestProcessIncomingMessage_OptOutAndBackIn(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+	phone := uniqueTestPhone()
+
+	app.processIncomingMessageFull(account.PhoneID, incomingText(phone, " stop "), "Customer")
+
+	var contact models.Contact
+	require.NoError(t, app.DB.Where("organization_id = ? AND phone_number = ?", org.ID, phone).First(&contact).Error)
+	assert.True(t, contact.OptedOut)
+	require.NotNil(t, contact.OptedOutAt)
+
+	var messages int64
+	require.NoError(t, app.DB.Model(&models.Message{}).Where("contact_id = ?", contact.ID).Count(&messages).Error)
+	assert.Equal(t, int64(1), messages, "the STOP message is kept and nothing is sent back")
+
+	app.processIncomingMessageFull(account.PhoneID, incomingText(phone, "START"), "Customer")
+
+	require.NoError(t, app.DB.First(&contact, contact.ID).Error)
+	assert.False(t, contact.OptedOut)
+	assert.Nil(t, contact.OptedOutAt)
+	assert.True(t, contact.HasValidOptIn(), "the opt-in keyword records consent")
+	assert.Equal(t, models.OptInMethodWhatsApp, contact.OptInMethod)
+}
+
+func TestProcessIncomingMessage_BlockedContactIgnored(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+	phone := uniqueTestPhone()
+	contact := &models.Contact{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		PhoneNumber:    phone,
+		Blocked:        true,
+	}
+	require.NoError(t, app.DB.Create(contact).Error)
+
+	app.processIncomingMessageFull(account.PhoneID, incomingText("+"+phone, "hello"), "Customer")
+
+	var messages int64
+	require.NoError(t, app.DB.Model(&models.Message{}).Where("contact_id = ?", contact.ID).Count(&messages).Error)
+	assert.Zero(t, messages)
+}
+
+func TestProcessIncomingMessage_BlockedFormattedContactIgnored(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+	phone := uniqueTestPhone()
+	// Stored before numbers were normalized
+	contact := &models.Contact{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		PhoneNumber:    "+" + phone[:1] + " (" + phone[1:4] + ") " + phone[4:7] + "-" + phone[7:],
+		Blocked:        true,
+	}
+	require.NoError(t, app.DB.Create(contact).Error)
+
+	assert.True(t, app.isContactBlocked(org.ID, phone))
+	assert.False(t, app.isContactBlocked(org.ID, uniqueTestPhone()))
+
+	app.processIncomingMessageFull(account.PhoneID, incomingText(phone, "hello"), "Customer")
+
+	var messages int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("organization_id = ? AND direction = ?", org.ID, models.DirectionIncoming).
+		Count(&messages).Error)
+	assert.Zero(t, messages)
+}
+
+func TestSendOutgoingMessage_BlockedContact(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	contact.Blocked = true
+
+	msg, err := app.SendOutgoingMessage(context.Background(), OutgoingMessageRequest{
+		Account: account,
+		Contact: contact,
+		Type:    models.MessageTypeText,
+		Content: "Hello",
+	}, DefaultSendOptions())
+	assert.ErrorIs(t, err, errContactBlocked)
+	assert.Nil(t, msg)
+}
+
+func TestApplyOptOutKeywords_OrganizationKeywords(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+	require.NoError(t, app.DB.Model(org).Update("settings", models.JSONB{
+		"opt_out_keywords": []string{"PARAR"},
+		"opt_in_keywords":  []string{},
+	}).Error)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	assert.False(t, app.applyOptOutKeywords(org.ID, contact, "STOP"), "default keywords are replaced")
+	assert.False(t, app.applyOptOutKeywords(org.ID, contact, "please parar"), "keywords must match the whole message")
+	assert.True(t, app.applyOptOutKeywords(org.ID, contact, "Parar"))
+	assert.True(t, app.applyOptOutKeywords(org.ID, contact, "START"), "opt-in keywords are disabled")
+
+	var stored models.Contact
+	require.NoError(t, app.DB.First(&stored, contact.ID).Error)
+	assert.True(t, stored.OptedOut)
+}
+
+func TestSendOutgoingMessage_AutomatedSendsSkipOptedOutContacts(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(contact).Update("opted_out", true).Error)
+	contact.OptedOut = true
+
+	req := OutgoingMessageRequest{
+		Account: account,
+		Contact: contact,
+		Type:    models.MessageTypeText,
+		Content: "Are you still there?",
+	}
+
+	for name, opts := range map[string]MessageSendOptions{"chatbot": ChatbotSendOptions(), "sla": SLASendOptions()} {
+		msg, err := app.SendOutgoingMessage(context.Background(), req, opts)
+		assert.ErrorIs(t, err, errContactOptedOut, name)
+		assert.Nil(t, msg, name)
+	}
+
+	var messages int64
+	require.NoError(t, app.DB.Model(&models.Message{}).Where("contact_id = ?", contact.ID).Count(&messages).Error)
+	assert.Zero(t, messages)
+
+	// Agents can still reply to the contact
+	opts := DefaultSendOptions()
+	opts.Async = false
+	msg, err := app.SendOutgoingMessage(context.Background(), req, opts)
+	require.NoError(t, err)
+	assert.Equal(t, models.MessageStatusSent, msg.Status)
+}
+
+// =============================================================================
+// Keyword template responses
+// =============================================================================
+
+// newTemplateRejectingWhatsApp points app.WhatsApp at a mock that fails every
+// template send with a Meta error and accepts all other messages. Returns a
+// function reporting how many template sends were attempted.
+func newTemplateRejectingWhatsApp(t *testing.T, app *App) func() int {
+	t.Helper()
+	var templateSends atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"type":"template"`) {
+			templateSends.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"Template name does not exist in the translation","code":132001}}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"messages": []map[string]string{{"id": "wamid.mock_" + uuid.New().String()[:8]}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	app.WhatsApp = whatsapp.NewWithBaseURL(app.Log, server.URL)
+	return func() int { return int(templateSends.Load()) }
+}
+
+func TestValidateKeywordResponseContent_Template(t *testing.T) {
+	assert.Empty(t, validateKeywordResponseContent(models.ResponseTypeTemplate, map[string]interface{}{"template_id": uuid.New().String()}))
+	// Rules without a template_id fall back to their body
+	assert.Empty(t, validateKeywordResponseContent(models.ResponseTypeTemplate, map[string]interface{}{"body": "Hi"}))
+	assert.NotEmpty(t, validateKeywordResponseContent(models.ResponseTypeTemplate, map[string]interface{}{}))
+	assert.NotEmpty(t, validateKeywordResponseContent(models.ResponseTypeTemplate, map[string]interface{}{"template_id": "nope", "body": "Hi"}))
+}
+
+func TestSendKeywordTemplateResponse_FallsBackOnTemplateError(t *testing.T) {
+	app := newProcessorTestApp(t)
+	templateSends := newTemplateRejectingWhatsApp(t, app)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+
+	resp := &KeywordResponse{}
+	require.True(t, applyKeywordTemplateContent(resp, models.JSONB{
+		"template_id":   template.ID.String(),
+		"body_params":   map[string]interface{}{"1": "Sam"},
+		"fallback_text": "Hi Sam, our offer is 20% off this week.",
+	}))
+
+	sent := app.sendKeywordTemplateResponse(account, contact, resp)
+	assert.Equal(t, resp.FallbackText, sent)
+	assert.Equal(t, 1, templateSends())
+
+	var templateMsg, fallbackMsg models.Message
+	require.NoError(t, app.DB.Where("contact_id = ? AND message_type = ?", contact.ID, models.MessageTypeTemplate).
+		First(&templateMsg).Error)
+	assert.Equal(t, models.MessageStatusFailed, templateMsg.Status)
+	require.NoError(t, app.DB.Where("contact_id = ? AND message_type = ?", contact.ID, models.MessageTypeText).
+		First(&fallbackMsg).Error)
+	assert.Equal(t, resp.FallbackText, fallbackMsg.Content)
+	assert.Equal(t, models.MessageStatusSent, fallbackMsg.Status)
+}
+
+func TestSendKeywordTemplateResponse_NoFallbackConfigured(t *testing.T) {
+	app := newProcessorTestApp(t)
+	newTemplateRejectingWhatsApp(t, app)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+
+	sent := app.sendKeywordTemplateResponse(account, contact, &KeywordResponse{TemplateID: template.ID})
+	assert.Empty(t, sent)
+
+	var texts int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("contact_id = ? AND message_type = ?", contact.ID, models.MessageTypeText).Count(&texts).Error)
+	assert.Zero(t, texts)
+}
+
+func TestSendKeywordTemplateResponse_TemplateSent(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+
+	resp := &KeywordResponse{TemplateID: template.ID, FallbackText: "fallback"}
+	sent := app.sendKeywordTemplateResponse(account, contact, resp)
+	assert.Contains(t, sent, template.Name)
+
+	var texts int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("contact_id = ? AND message_type = ?", contact.ID, models.MessageTypeText).Count(&texts).Error)
+	assert.Zero(t, texts, "fallback should not be sent when the template succeeds")
+}
+
+// =============================================================================
+// Inbound contact deduplication
+// =============================================================================
+
+func TestProcessIncomingMessage_AttachesToContactWithFormattedNumber(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+
+	phone := uniqueTestPhone()
+	// Same number as imported from a spreadsheet: "+1 (555) 012-3456"
+	formatted := fmt.Sprintf("+%s (%s) %s-%s", phone[:1], phone[1:4], phone[4:7], phone[7:])
+	existing := &models.Contact{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		PhoneNumber:    formatted,
+		ProfileName:    "Imported",
+	}
+	require.NoError(t, app.DB.Create(existing).Error)
+
+	msg := IncomingTextMessage{From: phone, ID: "wamid.dedupe", Type: "text"}
+	msg.Text = &struct {
+		Body string `json:"body"`
+	}{Body: "hello"}
+	app.processIncomingMessageFull(account.PhoneID, msg, "Customer")
+
+	var contacts []models.Contact
+	require.NoError(t, app.DB.Where("organization_id = ?", org.ID).Find(&contacts).Error)
+	require.Len(t, contacts, 1, "inbound should attach to the existing contact")
+	assert.Equal(t, existing.ID, contacts[0].ID)
+
+	var incoming models.Message
+	require.NoError(t, app.DB.Where("organization_id = ? AND direction = ?", org.ID, models.DirectionIncoming).
+		First(&incoming).Error)
+	assert.Equal(t, existing.ID, incoming.ContactID)
+}
+
+// =============================================================================
+// getChatbotSettingsCached
+// =============================================================================
+
+func TestGetChatbotSettingsCached_PrefersAccountSettings(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	other := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:          models.BaseModel{ID: uuid.New()},
+		OrganizationID:     org.ID,
+		IsEnabled:          true,
+		DefaultResponse:    "Org greeting",
+		SessionTimeoutMins: 30,
+	}).Error)
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:          models.BaseModel{ID: uuid.New()},
+		OrganizationID:     org.ID,
+		WhatsAppAccount:    account.Name,
+		IsEnabled:          true,
+		DefaultResponse:    "Account greeting",
+		SessionTimeoutMins: 10,
+	}).Error)
+
+	settings, err := app.getChatbotSettingsCached(org.ID, account.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "Account greeting", settings.DefaultResponse)
+	assert.Equal(t, 10, settings.SessionTimeoutMins)
+
+	settings, err = app.getChatbotSettingsCached(org.ID, other.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "Org greeting", settings.DefaultResponse, "accounts without settings use the org defaults")
+	assert.Equal(t, 30, settings.SessionTimeoutMins)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+)
+
+// sessionFlowID returns the flow a session ran, falling back to the _flow_id
+// recorded in its data once the session has moved off the flow
+func sessionFlowID(session *models.ChatbotSession) *uuid.UUID {
+	if session.CurrentFlowID != nil {
+		return session.CurrentFlowID
+	}
+	if flowIDStr, ok := session.SessionData["_flow_id"].(string); ok {
+		if parsedID, err := uuid.Parse(flowIDStr); err == nil {
+			return &parsedID
+		}
+	}
+	return nil
+}
+
+// sensitiveSessionKeys returns the session variables stored by the flow's
+// sensitive steps, including the button title stored alongside a choice.
+// Steps are read from the database so disabled flows are covered too.
+func (a *App) sensitiveSessionKeys(flowID uuid.UUID) map[string]bool {
+	var storeAs []string
+	if err := a.DB.Model(&models.ChatbotFlowStep{}).
+		Where("flow_id = ? AND sensitive = ? AND store_as <> ''", flowID, true).
+		Pluck("store_as", &storeAs).Error; err != nil {
+		a.Log.Error("Failed to load sensitive flow steps", "error", err, "flow_id", flowID)
+		return nil
+	}
+
+	keys := make(map[string]bool, len(storeAs)*2)
+	for _, key := range storeAs {
+		keys[key] = true
+		keys[key+"_title"] = true
+	}
+	return keys
+}
+
+// redactSessionData returns a copy of data with the sensitive keys masked.
+// The stored session data is never modified.
+func redactSessionData(data models.JSONB, sensitive map[string]bool) models.JSONB {
+	if len(sensitive) == 0 || len(data) == 0 {
+		return data
+	}
+	redacted := make(models.JSONB, len(data))
+	for key, value := range data {
+		if sensitive[key] && value != nil {
+			redacted[key] = maskSensitiveValue(value)
+		} else {
+			redacted[key] = value
+		}
+	}
+	return redacted
+}
+
+// maskSensitiveValue masks all but the last 4 characters of a value, or all
+// of it when it is too short for the tail to be safe to show
+func maskSensitiveValue(value any) string {
+	runes := []rune(fmt.Sprint(value))
+	if len(runes) <= 4 {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-4:])
+}
@@ -0,0 +1,112 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/handlers"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// createSensitiveSession creates a session for a flow whose email step is
+// sensitive and whose name step is not.
+func createSensitiveSession(t *testing.T, app *handlers.App, orgID, contactID uuid.UUID) *models.ChatbotSession {
+	t.Helper()
+
+	flow := createTestChatbotFlow(t, app, orgID, "Signup")
+	require.NoError(t, app.DB.Model(flow).Update("panel_config", models.JSONB{
+		"sections": []any{map[string]any{
+			"id":     "details",
+			"fields": []any{map[string]any{"key": "email"}, map[string]any{"key": "name"}},
+		}},
+	}).Error)
+	steps := []models.ChatbotFlowStep{
+		{BaseModel: models.BaseModel{ID: uuid.New()}, FlowID: flow.ID, StepName: "ask_email", StepOrder: 1, Message: "Email?", StoreAs: "email", Sensitive: true},
+		{BaseModel: models.BaseModel{ID: uuid.New()}, FlowID: flow.ID, StepName: "ask_name", StepOrder: 2, Message: "Name?", StoreAs: "name"},
+	}
+	require.NoError(t, app.DB.Create(&steps).Error)
+
+	session := &models.ChatbotSession{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: orgID,
+		ContactID:      contactID,
+		PhoneNumber:    "+15550001111",
+		Status:         models.SessionStatusActive,
+		CurrentFlowID:  &flow.ID,
+		SessionData:    models.JSONB{"email": "jane@example.com", "name": "Jane"},
+		StartedAt:      time.Now(),
+		LastActivityAt: time.Now(),
+	}
+	require.NoError(t, app.DB.Create(session).Error)
+	return session
+}
+
+func TestApp_GetChatbotSession_MasksSensitiveData(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	agent := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+	admin := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	session := createSensitiveSession(t, app, org.ID, contact.ID)
+
+	getSession := func(userID uuid.UUID) models.JSONB {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, userID)
+		testutil.SetPathParam(req, "id", session.ID.String())
+		require.NoError(t, app.GetChatbotSession(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data models.ChatbotSession `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		return resp.Data.SessionData
+	}
+
+	masked := getSession(agent.ID)
+	assert.Equal(t, "************.com", masked["email"])
+	assert.Equal(t, "Jane", masked["name"])
+
+	unmasked := getSession(admin.ID)
+	assert.Equal(t, "jane@example.com", unmasked["email"])
+
+	// The stored value is untouched
+	var stored models.ChatbotSession
+	require.NoError(t, app.DB.First(&stored, session.ID).Error)
+	assert.Equal(t, "jane@example.com", stored.SessionData["email"])
+}
+
+func TestApp_GetContactSessionData_MasksSensitiveData(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+	agent := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(contact).Update("assigned_user_id", agent.ID).Error)
+	createSensitiveSession(t, app, org.ID, contact.ID)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, agent.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+	require.NoError(t, app.GetContactSessionData(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.ContactSessionDataResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, "************.com", resp.Data.SessionData["email"])
+	assert.Equal(t, "Jane", resp.Data.SessionData["name"])
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// TransferChatbotSessionRequest hands an active chatbot session to a human.
+// With neither agent_id nor team_id the contact goes to the general queue.
+type TransferChatbotSessionRequest struct {
+	AgentID *uuid.UUID `json:"agent_id"`
+	TeamID  *uuid.UUID `json:"team_id"`
+	Reason  string     `json:"reason"`
+}
+
+// TransferChatbotSessionResponse is the result of a session transfer
+type TransferChatbotSessionResponse struct {
+	SessionID  uuid.UUID  `json:"session_id"`
+	TransferID uuid.UUID  `json:"transfer_id"`
+	AgentID    *uuid.UUID `json:"agent_id,omitempty"`
+	AgentName  string     `json:"agent_name,omitempty"`
+	TeamID     *uuid.UUID `json:"team_id,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+}
+
+// TransferChatbotSession ends an active chatbot session and creates an agent
+// transfer for its contact. The transfer is recorded in the session's data
+// under _transfer.
+func (a *App) TransferChatbotSession(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceTransfers, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	id, err := parsePathUUID(r, "id", "session")
+	if err != nil {
+		return nil
+	}
+
+	var req TransferChatbotSessionRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	session, err := findByIDAndOrg[models.ChatbotSession](a.DB, r, id, orgID, "Session")
+	if err != nil {
+		return nil
+	}
+	if session.Status != models.SessionStatusActive {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Only active sessions can be transferred", nil, "")
+	}
+
+	contact, err := findByIDAndOrg[models.Contact](a.DB, r, session.ContactID, orgID, "Contact")
+	if err != nil {
+		return nil
+	}
+	if a.hasActiveAgentTransfer(orgID, contact.ID) {
+		return r.SendErrorEnvelope(fasthttp.StatusConflict, "Contact already has an active transfer", nil, "")
+	}
+
+	var account models.WhatsAppAccount
+	if err := a.DB.Where("organization_id = ? AND name = ?", orgID, session.WhatsAppAccount).First(&account).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "WhatsApp account not found", nil, "")
+	}
+
+	if req.TeamID != nil {
+		var team models.Team
+		if err := a.DB.Where("id = ? AND organization_id = ? AND is_active = ?", *req.TeamID, orgID, true).First(&team).Error; err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Team not found or inactive", nil, "")
+		}
+	}
+
+	// An explicit agent wins, otherwise apply the team's assignment strategy
+	var agentID *uuid.UUID
+	if req.AgentID != nil {
+		agent, err := findByIDAndOrg[models.User](a.DB, r, *req.AgentID, orgID, "Agent")
+		if err != nil {
+			return nil
+		}
+		if !agent.IsAvailable {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Agent is currently away", nil, "")
+		}
+		agentID = req.AgentID
+	} else if req.TeamID != nil {
+		agentID = a.assignToTeam(*req.TeamID, orgID)
+	}
+
+	now := time.Now()
+	transfer := models.AgentTransfer{
+		BaseModel:           models.BaseModel{ID: uuid.New()},
+		OrganizationID:      orgID,
+		ContactID:           contact.ID,
+		WhatsAppAccount:     session.WhatsAppAccount,
+		PhoneNumber:         contact.PhoneNumber,
+		Status:              models.TransferStatusActive,
+		Source:              models.TransferSourceManual,
+		AgentID:             agentID,
+		TeamID:              req.TeamID,
+		TransferredByUserID: &userID,
+		Notes:               req.Reason,
+		TransferredAt:       now,
+	}
+
+	settings, _ := a.getChatbotSettingsCached(orgID, session.WhatsAppAccount)
+	// This session is closed below with its own status, so don't cancel it here
+	if err := a.saveAndFinalizeTransfer(&transfer, &account, contact, settings, false); err != nil {
+		a.Log.Error("Failed to transfer chatbot session", "error", err, "session_id", session.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to transfer session", nil, "")
+	}
+
+	sessionData := session.SessionData
+	if sessionData == nil {
+		sessionData = models.JSONB{}
+	}
+	record := map[string]interface{}{
+		"transfer_id":    transfer.ID.String(),
+		"reason":         req.Reason,
+		"transferred_at": now.UTC().Format(time.RFC3339),
+		"transferred_by": userID.String(),
+	}
+	if transfer.AgentID != nil {
+		record["agent_id"] = transfer.AgentID.String()
+	}
+	if transfer.TeamID != nil {
+		record["team_id"] = transfer.TeamID.String()
+	}
+	sessionData["_transfer"] = record
+
+	if err := a.DB.Model(session).Updates(map[string]interface{}{
+		"status":       models.SessionStatusTransferred,
+		"current_step": "",
+		"step_retries": 0,
+		"session_data": sessionData,
+		"completed_at": now,
+	}).Error; err != nil {
+		a.Log.Error("Failed to update transferred session", "error", err, "session_id", session.ID)
+	}
+	a.ClearContactChatbotTracking(contact.ID)
+
+	resp := TransferChatbotSessionResponse{
+		SessionID:  session.ID,
+		TransferID: transfer.ID,
+		AgentID:    transfer.AgentID,
+		TeamID:     transfer.TeamID,
+		Reason:     req.Reason,
+	}
+
+	var agentIDStr, agentName *string
+	if transfer.AgentID != nil {
+		var assigned models.User
+		if a.DB.Where("id = ?", transfer.AgentID).First(&assigned).Error == nil {
+			resp.AgentName = assigned.FullName
+			agentName = &assigned.FullName
+		}
+		idStr := transfer.AgentID.String()
+		agentIDStr = &idStr
+	}
+
+	a.DispatchWebhook(orgID, models.WebhookEventTransferCreated, TransferEventData{
+		TransferID:      transfer.ID.String(),
+		ContactID:       contact.ID.String(),
+		ContactPhone:    contact.PhoneNumber,
+		ContactName:     contact.ProfileName,
+		Source:          transfer.Source,
+		Reason:          transfer.Notes,
+		AgentID:         agentIDStr,
+		AgentName:       agentName,
+		WhatsAppAccount: transfer.WhatsAppAccount,
+	})
+
+	a.Log.Info("Chatbot session transferred", "session_id", session.ID, "transfer_id", transfer.ID, "user_id", userID)
+	return r.SendEnvelope(resp)
+}
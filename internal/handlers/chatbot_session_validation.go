@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+)
+
+// sessionDataDateLayouts are the date formats accepted for date inputs
+var sessionDataDateLayouts = []string{"2006-01-02", "02/01/2006", "01/02/2006", "2 Jan 2006", "January 2, 2006"}
+
+// sessionDataPhonePattern matches phone numbers with an optional leading + and common separators
+var sessionDataPhonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ()\-]{5,}$`)
+
+// SessionDataError describes a session variable that doesn't match the flow's steps
+type SessionDataError struct {
+	Key     string `json:"key"`
+	Step    string `json:"step,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e SessionDataError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Message)
+}
+
+// ValidateSessionData checks the variables collected by a session against the
+// flow's steps. Every non-internal key must be produced by a step (store_as,
+// the button title, AI assist or an API response mapping), and values stored
+// by typed inputs must match their input_type and validation_regex.
+// The flow's steps must be loaded; a flow without steps is not validated.
+func ValidateSessionData(flow *models.ChatbotFlow, data models.JSONB) []SessionDataError {
+	if len(flow.Steps) == 0 {
+		return nil
+	}
+
+	var errs []SessionDataError
+	expected := make(map[string]bool)
+	// WhatsApp Flow responses store whatever fields the form defines
+	allowAnyKey := false
+
+	for i := range flow.Steps {
+		step := &flow.Steps[i]
+		if step.InputType == models.InputTypeWhatsAppFlow || step.MessageType == models.FlowStepTypeWhatsAppFlow {
+			allowAnyKey = true
+		}
+		if assist := parseStepAIAssist(step); assist != nil {
+			expected[assist.StoreAs] = true
+		}
+		if step.MessageType == models.FlowStepTypeAPIFetch {
+			if mapping, ok := step.ApiConfig["response_mapping"].(map[string]interface{}); ok {
+				for key := range mapping {
+					expected[key] = true
+				}
+			}
+		}
+		if step.StoreAs == "" {
+			continue
+		}
+		expected[step.StoreAs] = true
+		expected[step.StoreAs+"_title"] = true
+
+		value, ok := data[step.StoreAs]
+		if !ok {
+			// Steps can be skipped, so a missing value isn't an error
+			continue
+		}
+		if msg := validateSessionValue(step, value); msg != "" {
+			errs = append(errs, SessionDataError{Key: step.StoreAs, Step: step.StepName, Message: msg})
+		}
+	}
+
+	if !allowAnyKey {
+		for key := range data {
+			if strings.HasPrefix(key, "_") || expected[key] {
+				continue
+			}
+			errs = append(errs, SessionDataError{Key: key, Message: "not stored by any step in the flow"})
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Key < errs[j].Key })
+	return errs
+}
+
+// validateSessionValue checks a stored value against the step's input type
+// and validation regex, returning a message describing the mismatch
+func validateSessionValue(step *models.ChatbotFlowStep, value interface{}) string {
+	str, isString := value.(string)
+	if !isString {
+		switch step.InputType {
+		case models.InputTypeNumber:
+			if _, ok := value.(float64); !ok {
+				return "expected a number"
+			}
+		case models.InputTypeEmail, models.InputTypePhone, models.InputTypeDate:
+			return fmt.Sprintf("expected a %s value, got %T", step.InputType, value)
+		}
+		return ""
+	}
+
+	// Button answers store the button ID, which the regex and type don't describe
+	if len(step.Buttons) > 0 {
+		return ""
+	}
+
+	str = strings.TrimSpace(str)
+	switch step.InputType {
+	case models.InputTypeNumber:
+		if _, err := strconv.ParseFloat(str, 64); err != nil {
+			return "expected a number"
+		}
+	case models.InputTypeEmail:
+		if addr, err := mail.ParseAddress(str); err != nil || addr.Address != str {
+			return "expected an email address"
+		}
+	case models.InputTypePhone:
+		if !sessionDataPhonePattern.MatchString(str) {
+			return "expected a phone number"
+		}
+	case models.InputTypeDate:
+		if !isSessionDataDate(str) {
+			return "expected a date"
+		}
+	}
+
+	if step.ValidationRegex != "" {
+		if re, err := regexp.Compile(step.ValidationRegex); err == nil && !re.MatchString(str) {
+			return "does not match the step's validation_regex"
+		}
+	}
+	return ""
+}
+
+// isSessionDataDate reports whether s parses with one of the accepted date layouts
+func isSessionDataDate(s string) bool {
+	for _, layout := range sessionDataDateLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sessionValidationFlow() *models.ChatbotFlow {
+	return &models.ChatbotFlow{
+		Steps: []models.ChatbotFlowStep{
+			{StepName: "ask_email", InputType: models.InputTypeEmail, StoreAs: "email"},
+			{StepName: "ask_qty", InputType: models.InputTypeNumber, StoreAs: "quantity"},
+			{StepName: "ask_date", InputType: models.InputTypeDate, StoreAs: "delivery_date"},
+			{StepName: "ask_code", InputType: models.InputTypeText, StoreAs: "code", ValidationRegex: `^[A-Z]{3}$`},
+			{
+				StepName:  "ask_size",
+				InputType: models.InputTypeButton,
+				StoreAs:   "size",
+				Buttons:   models.JSONBArray{map[string]interface{}{"id": "s", "title": "Small"}},
+			},
+			{
+				StepName:    "lookup",
+				MessageType: models.FlowStepTypeAPIFetch,
+				ApiConfig:   models.JSONB{"response_mapping": map[string]interface{}{"order_status": "data.status"}},
+			},
+		},
+	}
+}
+
+func TestValidateSessionData_Valid(t *testing.T) {
+	data := models.JSONB{
+		"_flow_id":      "internal",
+		"email":         "jane@example.com",
+		"quantity":      "3",
+		"delivery_date": "2024-05-01",
+		"code":          "ABC",
+		"size":          "s",
+		"size_title":    "Small",
+		"order_status":  "shipped",
+	}
+	assert.Empty(t, ValidateSessionData(sessionValidationFlow(), data))
+
+	// Skipped steps leave no value behind
+	assert.Empty(t, ValidateSessionData(sessionValidationFlow(), models.JSONB{"email": "jane@example.com"}))
+}
+
+func TestValidateSessionData_Errors(t *testing.T) {
+	data := models.JSONB{
+		"email":         "not-an-email",
+		"quantity":      "three",
+		"delivery_date": "someday",
+		"code":          "abc",
+		"emial":         "typo@example.com",
+	}
+	errs := ValidateSessionData(sessionValidationFlow(), data)
+	require.Len(t, errs, 5)
+
+	byKey := make(map[string]SessionDataError, len(errs))
+	for _, e := range errs {
+		byKey[e.Key] = e
+	}
+	assert.Equal(t, "ask_email", byKey["email"].Step)
+	assert.Equal(t, "expected a number", byKey["quantity"].Message)
+	assert.Equal(t, "expected a date", byKey["delivery_date"].Message)
+	assert.Contains(t, byKey["code"].Message, "validation_regex")
+	assert.Equal(t, "not stored by any step in the flow", byKey["emial"].Message)
+	assert.Empty(t, byKey["emial"].Step)
+}
+
+func TestValidateSessionData_WhatsAppFlowAllowsAnyKey(t *testing.T) {
+	flow := &models.ChatbotFlow{
+		Steps: []models.ChatbotFlowStep{
+			{StepName: "form", InputType: models.InputTypeWhatsAppFlow},
+		},
+	}
+	assert.Empty(t, ValidateSessionData(flow, models.JSONB{"first_name": "Jane", "age": float64(30)}))
+
+	// Without loaded steps there's nothing to validate against
+	assert.Empty(t, ValidateSessionData(&models.ChatbotFlow{}, models.JSONB{"anything": "goes"}))
+}
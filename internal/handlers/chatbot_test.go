@@ -2,10 +2,14 @@ package handlers_test
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/crypto"
 	"github.com/shridarpatil/whatomate/internal/handlers"
 	"github.com/shridarpatil/whatomate/internal/models"
 	"github.com/shridarpatil/whatomate/test/testutil"
@@ -250,6 +254,120 @@ func TestApp_ListKeywordRules(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, resp.Data.Rules, 0)
 	})
+
+	t.Run("pagination, search and ordering", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		createTestKeywordRule(t, app, org.ID, "Shipping", []string{"delivery", "track"})
+		createTestKeywordRule(t, app, org.ID, "Billing", []string{"invoice", "refund"})
+		createTestKeywordRule(t, app, org.ID, "Returns", []string{"return", "exchange"})
+		urgent := createTestKeywordRule(t, app, org.ID, "Urgent", []string{"asap"})
+		require.NoError(t, app.DB.Model(urgent).Update("priority", 50).Error)
+
+		list := func(params map[string]string) (names []string, total int64) {
+			req := testutil.NewGETRequest(t)
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			for k, v := range params {
+				testutil.SetQueryParam(req, k, v)
+			}
+			require.NoError(t, app.ListKeywordRules(req))
+			require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+			var resp struct {
+				Data struct {
+					Rules []handlers.KeywordRuleResponse `json:"rules"`
+					Total int64                          `json:"total"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+			for _, rule := range resp.Data.Rules {
+				names = append(names, rule.Name)
+			}
+			return names, resp.Data.Total
+		}
+
+		// Priority first, then name
+		names, total := list(map[string]string{"page": "1", "limit": "3"})
+		assert.Equal(t, []string{"Urgent", "Billing", "Returns"}, names)
+		assert.Equal(t, int64(4), total)
+
+		names, total = list(map[string]string{"page": "2", "limit": "3"})
+		assert.Equal(t, []string{"Shipping"}, names)
+		assert.Equal(t, int64(4), total)
+
+		// Search matches a keyword...
+		names, total = list(map[string]string{"search": "REFUND"})
+		assert.Equal(t, []string{"Billing"}, names)
+		assert.Equal(t, int64(1), total)
+
+		// ...or the name
+		names, _ = list(map[string]string{"search": "ship"})
+		assert.Equal(t, []string{"Shipping"}, names)
+
+		// JSON punctuation in the stored array doesn't match
+		names, total = list(map[string]string{"search": `", "`})
+		assert.Empty(t, names)
+		assert.Zero(t, total)
+	})
+
+	t.Run("filters by enabled and response type", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		createTestKeywordRule(t, app, org.ID, "Agent", []string{"agent", "human"})
+		pausedTransfer := createTestKeywordRule(t, app, org.ID, "Agent (old)", []string{"operator"})
+		createTestKeywordRule(t, app, org.ID, "Hours", []string{"hours"})
+		createTestKeywordRule(t, app, otherOrg.ID, "Foreign agent", []string{"agent"})
+		for _, name := range []string{"Agent", "Agent (old)"} {
+			require.NoError(t, app.DB.Model(&models.KeywordRule{}).
+				Where("organization_id = ? AND name = ?", org.ID, name).
+				Update("response_type", models.ResponseTypeTransfer).Error)
+		}
+		require.NoError(t, app.DB.Model(pausedTransfer).Update("is_enabled", false).Error)
+
+		list := func(params map[string]string) (int, []string) {
+			req := testutil.NewGETRequest(t)
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			for k, v := range params {
+				testutil.SetQueryParam(req, k, v)
+			}
+			require.NoError(t, app.ListKeywordRules(req))
+
+			var resp struct {
+				Data struct {
+					Rules []handlers.KeywordRuleResponse `json:"rules"`
+				} `json:"data"`
+			}
+			_ = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+			var names []string
+			for _, rule := range resp.Data.Rules {
+				names = append(names, rule.Name)
+			}
+			return testutil.GetResponseStatusCode(req), names
+		}
+
+		_, names := list(map[string]string{"enabled": "true", "response_type": "transfer"})
+		assert.Equal(t, []string{"Agent"}, names)
+
+		_, names = list(map[string]string{"enabled": "false"})
+		assert.Equal(t, []string{"Agent (old)"}, names)
+
+		_, names = list(map[string]string{"response_type": "transfer"})
+		assert.Equal(t, []string{"Agent", "Agent (old)"}, names)
+
+		_, names = list(map[string]string{"response_type": "transfer", "search": "operator"})
+		assert.Equal(t, []string{"Agent (old)"}, names)
+
+		_, names = list(map[string]string{})
+		assert.Len(t, names, 3)
+
+		status, _ := list(map[string]string{"enabled": "maybe"})
+		assert.Equal(t, fasthttp.StatusBadRequest, status)
+	})
 }
 
 // =============================================================================
@@ -324,6 +442,38 @@ func TestApp_CreateKeywordRule(t *testing.T) {
 		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 	})
 
+	t.Run("variants", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		create := func(variants []map[string]any) int {
+			req := testutil.NewJSONRequest(t, map[string]any{
+				"keywords":         []string{"promo"},
+				"response_type":    "text",
+				"response_content": map[string]any{"variants": variants},
+			})
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			require.NoError(t, app.CreateKeywordRule(req))
+			return testutil.GetResponseStatusCode(req)
+		}
+
+		assert.Equal(t, fasthttp.StatusOK, create([]map[string]any{
+			{"body": "Use code SAVE10", "weight": 70},
+			{"body": "Free shipping today", "weight": 30},
+		}))
+		assert.Equal(t, fasthttp.StatusBadRequest, create([]map[string]any{
+			{"body": "Use code SAVE10", "weight": 0},
+			{"body": "Free shipping today", "weight": 0},
+		}), "weights must add up to more than 0")
+		assert.Equal(t, fasthttp.StatusBadRequest, create([]map[string]any{
+			{"body": "Use code SAVE10", "weight": -5},
+		}))
+		assert.Equal(t, fasthttp.StatusBadRequest, create([]map[string]any{
+			{"weight": 10},
+		}), "variants need a body")
+	})
+
 	t.Run("defaults name to first keyword when name empty", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
@@ -508,6 +658,152 @@ func TestApp_DeleteKeywordRule(t *testing.T) {
 	})
 }
 
+// =============================================================================
+// GetKeywordRuleStats
+// =============================================================================
+
+func TestApp_GetKeywordRuleStats(t *testing.T) {
+	t.Parallel()
+
+	type statsResponse struct {
+		Data struct {
+			Rules     []handlers.KeywordRuleStats `json:"rules"`
+			TotalHits int64                       `json:"total_hits"`
+		} `json:"data"`
+	}
+
+	recordHit := func(t *testing.T, app *handlers.App, rule *models.KeywordRule, contactID uuid.UUID, at time.Time) {
+		t.Helper()
+		hit := &models.KeywordRuleHit{
+			BaseModel:      models.BaseModel{ID: uuid.New(), CreatedAt: at},
+			OrganizationID: rule.OrganizationID,
+			KeywordRuleID:  rule.ID,
+			ContactID:      contactID,
+		}
+		require.NoError(t, app.DB.Create(hit).Error)
+	}
+
+	recordVariantHit := func(t *testing.T, app *handlers.App, rule *models.KeywordRule, contactID uuid.UUID, variant int) {
+		t.Helper()
+		hit := &models.KeywordRuleHit{
+			BaseModel:      models.BaseModel{ID: uuid.New(), CreatedAt: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)},
+			OrganizationID: rule.OrganizationID,
+			KeywordRuleID:  rule.ID,
+			ContactID:      contactID,
+			Variant:        &variant,
+		}
+		require.NoError(t, app.DB.Create(hit).Error)
+	}
+
+	t.Run("counts hits and includes unused rules", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		popular := createTestKeywordRule(t, app, org.ID, "Popular", []string{"price"})
+		unused := createTestKeywordRule(t, app, org.ID, "Unused", []string{"fax"})
+		foreign := createTestKeywordRule(t, app, otherOrg.ID, "Foreign", []string{"price"})
+
+		inRange := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+		recordHit(t, app, popular, contact.ID, inRange)
+		recordHit(t, app, popular, contact.ID, inRange.Add(time.Hour))
+		recordHit(t, app, popular, contact.ID, time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC))
+		recordHit(t, app, foreign, contact.ID, inRange)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "from", "2025-03-01")
+		testutil.SetQueryParam(req, "to", "2025-03-31")
+
+		require.NoError(t, app.GetKeywordRuleStats(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp statsResponse
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		require.Len(t, resp.Data.Rules, 2)
+		assert.Equal(t, int64(2), resp.Data.TotalHits)
+
+		assert.Equal(t, popular.ID.String(), resp.Data.Rules[0].ID)
+		assert.Equal(t, int64(2), resp.Data.Rules[0].Hits)
+		assert.NotNil(t, resp.Data.Rules[0].LastHitAt)
+
+		assert.Equal(t, unused.ID.String(), resp.Data.Rules[1].ID)
+		assert.Equal(t, int64(0), resp.Data.Rules[1].Hits)
+		assert.Nil(t, resp.Data.Rules[1].LastHitAt)
+	})
+
+	t.Run("reports variant distribution", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		rule := createTestKeywordRule(t, app, org.ID, "Promo", []string{"promo"})
+		plain := createTestKeywordRule(t, app, org.ID, "Plain", []string{"hours"})
+		recordVariantHit(t, app, rule, contact.ID, 0)
+		recordVariantHit(t, app, rule, contact.ID, 1)
+		recordVariantHit(t, app, rule, contact.ID, 1)
+		recordVariantHit(t, app, rule, contact.ID, 1)
+		recordHit(t, app, plain, contact.ID, time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC))
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "from", "2025-03-01")
+		testutil.SetQueryParam(req, "to", "2025-03-31")
+
+		require.NoError(t, app.GetKeywordRuleStats(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp statsResponse
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		require.Len(t, resp.Data.Rules, 2)
+
+		assert.Equal(t, rule.ID.String(), resp.Data.Rules[0].ID)
+		require.Len(t, resp.Data.Rules[0].Variants, 2)
+		assert.Equal(t, handlers.KeywordRuleVariantStats{Variant: 0, Hits: 1, Share: 0.25}, resp.Data.Rules[0].Variants[0])
+		assert.Equal(t, handlers.KeywordRuleVariantStats{Variant: 1, Hits: 3, Share: 0.75}, resp.Data.Rules[0].Variants[1])
+
+		assert.Equal(t, plain.ID.String(), resp.Data.Rules[1].ID)
+		assert.Empty(t, resp.Data.Rules[1].Variants)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "from", "not-a-date")
+		testutil.SetQueryParam(req, "to", "2025-03-31")
+
+		require.NoError(t, app.GetKeywordRuleStats(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("forbidden without keyword read", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "No Keywords", nil)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.GetKeywordRuleStats(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
+
 // =============================================================================
 // ListChatbotFlows
 // =============================================================================
@@ -755,236 +1051,597 @@ func TestApp_UpdateChatbotFlow(t *testing.T) {
 	})
 }
 
-// =============================================================================
-// DeleteChatbotFlow
-// =============================================================================
-
-func TestApp_DeleteChatbotFlow(t *testing.T) {
+func TestApp_ChatbotFlow_ActiveWindow(t *testing.T) {
 	t.Parallel()
 
-	t.Run("success", func(t *testing.T) {
-		app := newTestApp(t)
-		org := testutil.CreateTestOrganization(t, app.DB)
-		perms := getChatbotFlowPermissions(t, app)
-		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
-		user := testutil.CreateTestUser(t, app.DB, org.ID,
-			testutil.WithEmail(testutil.UniqueEmail("delete-flow")),
+	newFlowUser := func(t *testing.T, app *handlers.App, orgID uuid.UUID) *models.User {
+		t.Helper()
+		role := testutil.CreateTestRole(t, app.DB, orgID, "flow-admin", getChatbotFlowPermissions(t, app))
+		return testutil.CreateTestUser(t, app.DB, orgID,
+			testutil.WithEmail(testutil.UniqueEmail("flow-window")),
 			testutil.WithRoleID(&role.ID),
 		)
-		flow := createTestChatbotFlow(t, app, org.ID, "To Delete Flow")
+	}
 
-		req := testutil.NewGETRequest(t)
+	t.Run("create persists window", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := newFlowUser(t, app, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":         "Black Friday",
+			"enabled":      true,
+			"active_from":  "2025-11-28T00:00:00Z",
+			"active_until": "2025-12-01T23:59:59Z",
+		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", flow.ID.String())
 
-		err := app.DeleteChatbotFlow(req)
-		require.NoError(t, err)
+		require.NoError(t, app.CreateChatbotFlow(req))
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
+		var flow models.ChatbotFlow
+		require.NoError(t, app.DB.Where("organization_id = ? AND name = ?", org.ID, "Black Friday").First(&flow).Error)
+		require.NotNil(t, flow.ActiveFrom)
+		require.NotNil(t, flow.ActiveUntil)
+		assert.True(t, flow.ActiveFrom.Equal(time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, flow.ActiveUntil.Equal(time.Date(2025, 12, 1, 23, 59, 59, 0, time.UTC)))
+
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+		testutil.SetPathParam(getReq, "id", flow.ID.String())
+		require.NoError(t, app.GetChatbotFlow(getReq))
+
 		var resp struct {
 			Data struct {
-				Message string `json:"message"`
+				ActiveFrom  *time.Time `json:"active_from"`
+				ActiveUntil *time.Time `json:"active_until"`
 			} `json:"data"`
 		}
-		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
-		require.NoError(t, err)
-		assert.Equal(t, "Flow deleted successfully", resp.Data.Message)
-
-		// Verify soft-deleted
-		var count int64
-		app.DB.Model(&models.ChatbotFlow{}).Where("id = ?", flow.ID).Count(&count)
-		assert.Equal(t, int64(0), count)
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(getReq), &resp))
+		require.NotNil(t, resp.Data.ActiveFrom)
+		require.NotNil(t, resp.Data.ActiveUntil)
 	})
 
-	t.Run("not found", func(t *testing.T) {
+	t.Run("create rejects inverted window", func(t *testing.T) {
+		t.Parallel()
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		perms := getChatbotFlowPermissions(t, app)
-		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
-		user := testutil.CreateTestUser(t, app.DB, org.ID,
-			testutil.WithEmail(testutil.UniqueEmail("delete-flow-nf")),
-			testutil.WithRoleID(&role.ID),
-		)
+		user := newFlowUser(t, app, org.ID)
 
-		req := testutil.NewGETRequest(t)
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":         "Backwards",
+			"active_from":  "2025-12-01T00:00:00Z",
+			"active_until": "2025-11-01T00:00:00Z",
+		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", uuid.New().String())
 
-		err := app.DeleteChatbotFlow(req)
-		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+		require.NoError(t, app.CreateChatbotFlow(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 	})
-}
 
-// =============================================================================
-// ListAIContexts
-// =============================================================================
+	t.Run("create rejects malformed timestamp and treats empty as unset", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := newFlowUser(t, app, org.ID)
 
-func TestApp_ListAIContexts(t *testing.T) {
-	t.Parallel()
+		req := testutil.NewJSONRequest(t, map[string]any{"name": "Malformed", "active_until": "next tuesday"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.CreateChatbotFlow(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 
-	t.Run("success", func(t *testing.T) {
+		emptyReq := testutil.NewJSONRequest(t, map[string]any{"name": "Open", "active_from": "", "active_until": ""})
+		testutil.SetAuthContext(emptyReq, org.ID, user.ID)
+		require.NoError(t, app.CreateChatbotFlow(emptyReq))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(emptyReq))
+
+		var flow models.ChatbotFlow
+		require.NoError(t, app.DB.Where("organization_id = ? AND name = ?", org.ID, "Open").First(&flow).Error)
+		assert.Nil(t, flow.ActiveFrom)
+		assert.Nil(t, flow.ActiveUntil)
+	})
+
+	t.Run("update validates against stored bound", func(t *testing.T) {
+		t.Parallel()
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
-
-		createTestAIContext(t, app, org.ID, "FAQ Context")
-		createTestAIContext(t, app, org.ID, "Product Context")
+		user := newFlowUser(t, app, org.ID)
+		flow := createTestChatbotFlow(t, app, org.ID, "Campaign")
+		from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, app.DB.Model(flow).Update("active_from", from).Error)
 
-		req := testutil.NewGETRequest(t)
+		req := testutil.NewJSONRequest(t, map[string]any{"active_until": "2025-05-31T00:00:00Z"})
 		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
 
-		err := app.ListAIContexts(req)
-		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		require.NoError(t, app.UpdateChatbotFlow(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 
-		var resp struct {
-			Data struct {
-				Contexts []handlers.AIContextResponse `json:"contexts"`
-			} `json:"data"`
-		}
-		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
-		require.NoError(t, err)
-		assert.Len(t, resp.Data.Contexts, 2)
+		var unchanged models.ChatbotFlow
+		require.NoError(t, app.DB.First(&unchanged, "id = ?", flow.ID).Error)
+		assert.Nil(t, unchanged.ActiveUntil)
 	})
 
-	t.Run("empty list", func(t *testing.T) {
+	t.Run("update sets and clears window", func(t *testing.T) {
+		t.Parallel()
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		user := newFlowUser(t, app, org.ID)
+		flow := createTestChatbotFlow(t, app, org.ID, "Campaign")
 
-		req := testutil.NewGETRequest(t)
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"active_from":  "2025-06-01T00:00:00Z",
+			"active_until": "2025-06-30T00:00:00Z",
+		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
-
-		err := app.ListAIContexts(req)
-		require.NoError(t, err)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+		require.NoError(t, app.UpdateChatbotFlow(req))
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		var resp struct {
-			Data struct {
-				Contexts []handlers.AIContextResponse `json:"contexts"`
-			} `json:"data"`
-		}
-		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
-		require.NoError(t, err)
-		assert.Len(t, resp.Data.Contexts, 0)
+		var updated models.ChatbotFlow
+		require.NoError(t, app.DB.First(&updated, "id = ?", flow.ID).Error)
+		require.NotNil(t, updated.ActiveFrom)
+		require.NotNil(t, updated.ActiveUntil)
+
+		clearReq := testutil.NewJSONRequest(t, map[string]any{"active_from": "", "active_until": ""})
+		testutil.SetAuthContext(clearReq, org.ID, user.ID)
+		testutil.SetPathParam(clearReq, "id", flow.ID.String())
+		require.NoError(t, app.UpdateChatbotFlow(clearReq))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(clearReq))
+
+		var cleared models.ChatbotFlow
+		require.NoError(t, app.DB.First(&cleared, "id = ?", flow.ID).Error)
+		assert.Nil(t, cleared.ActiveFrom)
+		assert.Nil(t, cleared.ActiveUntil)
+	})
+
+	t.Run("update rejects malformed timestamp", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := newFlowUser(t, app, org.ID)
+		flow := createTestChatbotFlow(t, app, org.ID, "Campaign")
+
+		req := testutil.NewJSONRequest(t, map[string]any{"active_from": "next tuesday"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+
+		require.NoError(t, app.UpdateChatbotFlow(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 	})
 }
 
+func TestApp_ChatbotFlow_StepLinkValidation(t *testing.T) {
+	t.Parallel()
+
+	step := func(name, next string) map[string]any {
+		return map[string]any{"step_name": name, "message": name, "input_type": "text", "next_step": next}
+	}
+
+	tests := []struct {
+		name      string
+		steps     []map[string]any
+		wantError string // empty means the flow is accepted
+	}{
+		{
+			name:  "linear flow with terminal step",
+			steps: []map[string]any{step("a", "b"), step("b", "")},
+		},
+		{
+			name:      "dangling next_step",
+			steps:     []map[string]any{step("a", "missing"), step("b", "")},
+			wantError: `Step "a" has next_step "missing" which does not exist`,
+		},
+		{
+			name:      "explicit cycle",
+			steps:     []map[string]any{step("a", "b"), step("b", "a")},
+			wantError: "Steps form a loop with no exit: a -> b -> a",
+		},
+		{
+			name:      "last step loops back through step order",
+			steps:     []map[string]any{step("a", ""), step("b", ""), step("c", "b")},
+			wantError: "Steps form a loop with no exit: b -> c -> b",
+		},
+		{
+			name:      "self loop",
+			steps:     []map[string]any{step("a", "a")},
+			wantError: "Steps form a loop with no exit: a -> a",
+		},
+		{
+			name: "loop with a conditional exit",
+			steps: []map[string]any{
+				step("menu", ""),
+				{"step_name": "confirm", "message": "Done?", "input_type": "text", "next_step": "menu",
+					"conditional_next": map[string]any{"yes": "bye", "default": "menu"}},
+				step("bye", ""),
+			},
+		},
+		{
+			name: "loop with a button exit",
+			steps: []map[string]any{
+				{"step_name": "menu", "message": "Pick one", "input_type": "button", "next_step": "menu",
+					"buttons": []map[string]any{{"id": "done", "title": "Done", "next_step": "bye"}, {"id": "again", "title": "Again"}}},
+				step("bye", ""),
+			},
+		},
+		{
+			name: "button next_step that does not exist",
+			steps: []map[string]any{
+				{"step_name": "menu", "message": "Pick one", "input_type": "button",
+					"buttons": []map[string]any{{"id": "sales", "title": "Sales", "next_step": "missing"}}},
+			},
+			wantError: `Step "menu" button "sales" has next_step "missing" which does not exist`,
+		},
+		{
+			name: "duplicate button ids",
+			steps: []map[string]any{
+				{"step_name": "menu", "message": "Pick one", "input_type": "button",
+					"buttons": []map[string]any{{"title": "Sales"}, {"id": "btn_1", "title": "Support"}}},
+			},
+			wantError: `Step "menu" has more than one button with id "btn_1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			app := newTestApp(t)
+			org := testutil.CreateTestOrganization(t, app.DB)
+			role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", getChatbotFlowPermissions(t, app))
+			user := testutil.CreateTestUser(t, app.DB, org.ID,
+				testutil.WithEmail(testutil.UniqueEmail("flow-links")),
+				testutil.WithRoleID(&role.ID),
+			)
+
+			createReq := testutil.NewJSONRequest(t, map[string]any{"name": "Links", "steps": tt.steps})
+			testutil.SetAuthContext(createReq, org.ID, user.ID)
+			require.NoError(t, app.CreateChatbotFlow(createReq))
+
+			// The same steps sent as an update to an existing flow get the same verdict
+			flow := createTestChatbotFlow(t, app, org.ID, "Existing")
+			createTestFlowStep(t, app, flow.ID, "original", 1)
+			updateReq := testutil.NewJSONRequest(t, map[string]any{"steps": tt.steps})
+			testutil.SetAuthContext(updateReq, org.ID, user.ID)
+			testutil.SetPathParam(updateReq, "id", flow.ID.String())
+			require.NoError(t, app.UpdateChatbotFlow(updateReq))
+
+			if tt.wantError == "" {
+				assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(createReq))
+				assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(updateReq))
+				return
+			}
+
+			for _, req := range []*fastglue.Request{createReq, updateReq} {
+				assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+				var result map[string]any
+				require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &result))
+				assert.Equal(t, tt.wantError, result["message"])
+			}
+
+			var created int64
+			app.DB.Model(&models.ChatbotFlow{}).Where("organization_id = ? AND name = ?", org.ID, "Links").Count(&created)
+			assert.Zero(t, created)
+
+			var steps []models.ChatbotFlowStep
+			require.NoError(t, app.DB.Where("flow_id = ?", flow.ID).Find(&steps).Error)
+			require.Len(t, steps, 1)
+			assert.Equal(t, "original", steps[0].StepName)
+		})
+	}
+}
+
 // =============================================================================
-// CreateAIContext
+// UpdateChatbotFlowStep
 // =============================================================================
 
-func TestApp_CreateAIContext(t *testing.T) {
+// createTestFlowStep creates a flow step directly in the DB for testing.
+func createTestFlowStep(t *testing.T, app *handlers.App, flowID uuid.UUID, name string, order int) *models.ChatbotFlowStep {
+	t.Helper()
+
+	step := &models.ChatbotFlowStep{
+		BaseModel:   models.BaseModel{ID: uuid.New()},
+		FlowID:      flowID,
+		StepName:    name,
+		StepOrder:   order,
+		Message:     "Message for " + name,
+		MessageType: models.FlowStepTypeText,
+		InputType:   models.InputTypeText,
+		StoreAs:     name + "_value",
+		MaxRetries:  3,
+	}
+	require.NoError(t, app.DB.Create(step).Error)
+	return step
+}
+
+func TestApp_UpdateChatbotFlowStep(t *testing.T) {
 	t.Parallel()
 
-	t.Run("success", func(t *testing.T) {
+	t.Run("success patches only provided fields", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("update-step")),
+			testutil.WithRoleID(&role.ID),
+		)
+		flow := createTestChatbotFlow(t, app, org.ID, "Step Flow")
+		step1 := createTestFlowStep(t, app, flow.ID, "ask_name", 1)
+		step2 := createTestFlowStep(t, app, flow.ID, "ask_email", 2)
 
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"name":             "Product FAQ",
-			"context_type":     "static",
-			"trigger_keywords": []string{"product", "pricing"},
-			"static_content":   "Our product costs $99/month. We offer a free trial.",
-			"priority":         20,
-			"enabled":          true,
+			"message":   "What should we call you?",
+			"next_step": "ask_email",
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+		testutil.SetPathParam(req, "step_id", step1.ID.String())
 
-		err := app.CreateAIContext(req)
+		err := app.UpdateChatbotFlowStep(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
-			Data struct {
-				ID      string `json:"id"`
-				Message string `json:"message"`
-			} `json:"data"`
+			Data models.ChatbotFlowStep `json:"data"`
 		}
-		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
-		require.NoError(t, err)
-		assert.NotEmpty(t, resp.Data.ID)
-		assert.Equal(t, "AI context created successfully", resp.Data.Message)
-
-		// Verify persisted
-		parsedID, err := uuid.Parse(resp.Data.ID)
-		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, step1.ID, resp.Data.ID)
+		assert.Equal(t, "What should we call you?", resp.Data.Message)
+		assert.Equal(t, "ask_email", resp.Data.NextStep)
+		assert.Equal(t, "ask_name_value", resp.Data.StoreAs)
+
+		// Other steps are untouched
+		var other models.ChatbotFlowStep
+		require.NoError(t, app.DB.First(&other, "id = ?", step2.ID).Error)
+		assert.Equal(t, step2.Message, other.Message)
+		assert.Equal(t, step2.StepOrder, other.StepOrder)
+	})
 
-		var ctx models.AIContext
-		require.NoError(t, app.DB.First(&ctx, "id = ?", parsedID).Error)
-		assert.Equal(t, "Product FAQ", ctx.Name)
-		assert.Equal(t, models.ContextTypeStatic, ctx.ContextType)
-		assert.Equal(t, 20, ctx.Priority)
-		assert.True(t, ctx.IsEnabled)
+	t.Run("rejects next_step that breaks the flow", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("update-step-links")),
+			testutil.WithRoleID(&role.ID),
+		)
+		flow := createTestChatbotFlow(t, app, org.ID, "Linked Flow")
+		createTestFlowStep(t, app, flow.ID, "ask_name", 1)
+		step2 := createTestFlowStep(t, app, flow.ID, "ask_email", 2)
+
+		for name, next := range map[string]string{"dangling": "missing", "loop": "ask_name"} {
+			req := testutil.NewJSONRequest(t, map[string]any{"next_step": next})
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			testutil.SetPathParam(req, "id", flow.ID.String())
+			testutil.SetPathParam(req, "step_id", step2.ID.String())
+
+			require.NoError(t, app.UpdateChatbotFlowStep(req))
+			assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req), name)
+		}
+
+		var unchanged models.ChatbotFlowStep
+		require.NoError(t, app.DB.First(&unchanged, "id = ?", step2.ID).Error)
+		assert.Empty(t, unchanged.NextStep)
+	})
+
+	t.Run("rejects invalid validation_regex", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("update-step-regex")),
+			testutil.WithRoleID(&role.ID),
+		)
+		flow := createTestChatbotFlow(t, app, org.ID, "Regex Flow")
+		step := createTestFlowStep(t, app, flow.ID, "ask_code", 1)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"validation_regex": "^[0-9+$"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+		testutil.SetPathParam(req, "step_id", step.ID.String())
+
+		require.NoError(t, app.UpdateChatbotFlowStep(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+		var unchanged models.ChatbotFlowStep
+		require.NoError(t, app.DB.First(&unchanged, "id = ?", step.ID).Error)
+		assert.Empty(t, unchanged.ValidationRegex)
+	})
+
+	t.Run("step from another flow not found", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("update-step-other")),
+			testutil.WithRoleID(&role.ID),
+		)
+		flow := createTestChatbotFlow(t, app, org.ID, "Flow A")
+		otherFlow := createTestChatbotFlow(t, app, org.ID, "Flow B")
+		otherStep := createTestFlowStep(t, app, otherFlow.ID, "ask_name", 1)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"message": "Changed"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+		testutil.SetPathParam(req, "step_id", otherStep.ID.String())
+
+		err := app.UpdateChatbotFlowStep(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("cannot update step of flow in another org", func(t *testing.T) {
+		app := newTestApp(t)
+		org1 := testutil.CreateTestOrganization(t, app.DB)
+		flow := createTestChatbotFlow(t, app, org1.ID, "Secret Flow")
+		step := createTestFlowStep(t, app, flow.ID, "ask_name", 1)
+
+		org2 := testutil.CreateTestOrganization(t, app.DB)
+		perms := getChatbotFlowPermissions(t, app)
+		role2 := testutil.CreateTestRole(t, app.DB, org2.ID, "flow-admin", perms)
+		user2 := testutil.CreateTestUser(t, app.DB, org2.ID,
+			testutil.WithEmail(testutil.UniqueEmail("org2-update-step")),
+			testutil.WithRoleID(&role2.ID),
+		)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"message": "Hijacked"})
+		testutil.SetAuthContext(req, org2.ID, user2.ID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+		testutil.SetPathParam(req, "step_id", step.ID.String())
+
+		err := app.UpdateChatbotFlowStep(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+
+		var unchanged models.ChatbotFlowStep
+		require.NoError(t, app.DB.First(&unchanged, "id = ?", step.ID).Error)
+		assert.Equal(t, step.Message, unchanged.Message)
 	})
 }
 
 // =============================================================================
-// GetAIContext
+// GetStepAnswerDistribution
 // =============================================================================
 
-func TestApp_GetAIContext(t *testing.T) {
+func TestApp_GetStepAnswerDistribution(t *testing.T) {
 	t.Parallel()
 
-	t.Run("success", func(t *testing.T) {
+	type distributionResponse struct {
+		Data struct {
+			StoreAs string                     `json:"store_as"`
+			Answers []handlers.StepAnswerCount `json:"answers"`
+			Total   int64                      `json:"total"`
+		} `json:"data"`
+	}
+
+	createSession := func(t *testing.T, app *handlers.App, orgID, flowID uuid.UUID, data models.JSONB, createdAt time.Time) {
+		t.Helper()
+		contact := testutil.CreateTestContact(t, app.DB, orgID)
+		session := &models.ChatbotSession{
+			BaseModel:       models.BaseModel{ID: uuid.New(), CreatedAt: createdAt},
+			OrganizationID:  orgID,
+			ContactID:       contact.ID,
+			WhatsAppAccount: "test-account",
+			PhoneNumber:     contact.PhoneNumber,
+			Status:          models.SessionStatusCompleted,
+			CurrentFlowID:   &flowID,
+			SessionData:     data,
+			LastActivityAt:  createdAt,
+		}
+		require.NoError(t, app.DB.Create(session).Error)
+	}
+
+	newAnalyst := func(t *testing.T, app *handlers.App, orgID uuid.UUID) *models.User {
+		t.Helper()
+		role := testutil.CreateTestRole(t, app.DB, orgID, "flow-analyst", getChatbotFlowPermissions(t, app))
+		return testutil.CreateTestUser(t, app.DB, orgID,
+			testutil.WithEmail(testutil.UniqueEmail("answer-dist")),
+			testutil.WithRoleID(&role.ID),
+		)
+	}
+
+	t.Run("counts distinct answers in range", func(t *testing.T) {
+		t.Parallel()
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
-		ctx := createTestAIContext(t, app, org.ID, "FAQ Context")
+		user := newAnalyst(t, app, org.ID)
+		flow := createTestChatbotFlow(t, app, org.ID, "Support")
+		step := createTestFlowStep(t, app, flow.ID, "reason", 1)
+		otherFlow := createTestChatbotFlow(t, app, org.ID, "Sales")
+
+		inRange := time.Date(2025, 3, 15, 10, 0, 0, 0, time.UTC)
+		createSession(t, app, org.ID, flow.ID, models.JSONB{"reason_value": "refund", "reason_value_title": "Refund"}, inRange)
+		createSession(t, app, org.ID, flow.ID, models.JSONB{"reason_value": "refund", "reason_value_title": "Refund"}, inRange)
+		createSession(t, app, org.ID, flow.ID, models.JSONB{"reason_value": "refund", "reason_value_title": "Refund"}, inRange)
+		createSession(t, app, org.ID, flow.ID, models.JSONB{"reason_value": "exchange", "reason_value_title": "Exchange"}, inRange)
+		// Not answered yet, outside the range, or a different flow: all excluded
+		createSession(t, app, org.ID, flow.ID, models.JSONB{"other": "x"}, inRange)
+		createSession(t, app, org.ID, flow.ID, models.JSONB{"reason_value": "refund"}, time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))
+		createSession(t, app, org.ID, otherFlow.ID, models.JSONB{"reason_value": "refund"}, inRange)
 
 		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", ctx.ID.String())
+		testutil.SetPathParam(req, "id", flow.ID.String())
+		testutil.SetPathParam(req, "step_id", step.ID.String())
+		testutil.SetQueryParam(req, "from", "2025-03-01")
+		testutil.SetQueryParam(req, "to", "2025-03-31")
 
-		err := app.GetAIContext(req)
-		require.NoError(t, err)
+		require.NoError(t, app.GetStepAnswerDistribution(req))
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		var resp struct {
-			Data models.AIContext `json:"data"`
-		}
-		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
-		require.NoError(t, err)
-		assert.Equal(t, ctx.ID, resp.Data.ID)
-		assert.Equal(t, "FAQ Context", resp.Data.Name)
-		assert.Equal(t, models.ContextTypeStatic, resp.Data.ContextType)
+		var resp distributionResponse
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, "reason_value", resp.Data.StoreAs)
+		assert.Equal(t, int64(4), resp.Data.Total)
+		require.Len(t, resp.Data.Answers, 2)
+		assert.Equal(t, handlers.StepAnswerCount{Answer: "refund", Label: "Refund", Count: 3}, resp.Data.Answers[0])
+		assert.Equal(t, handlers.StepAnswerCount{Answer: "exchange", Label: "Exchange", Count: 1}, resp.Data.Answers[1])
 	})
 
-	t.Run("not found", func(t *testing.T) {
+	t.Run("step without store_as", func(t *testing.T) {
+		t.Parallel()
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		user := newAnalyst(t, app, org.ID)
+		flow := createTestChatbotFlow(t, app, org.ID, "Support")
+		step := createTestFlowStep(t, app, flow.ID, "greeting", 1)
+		require.NoError(t, app.DB.Model(step).Update("store_as", "").Error)
 
 		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", uuid.New().String())
+		testutil.SetPathParam(req, "id", flow.ID.String())
+		testutil.SetPathParam(req, "step_id", step.ID.String())
 
-		err := app.GetAIContext(req)
-		require.NoError(t, err)
+		require.NoError(t, app.GetStepAnswerDistribution(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("flow in other org", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		user := newAnalyst(t, app, org.ID)
+		flow := createTestChatbotFlow(t, app, otherOrg.ID, "Foreign")
+		step := createTestFlowStep(t, app, flow.ID, "reason", 1)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+		testutil.SetPathParam(req, "step_id", step.ID.String())
+
+		require.NoError(t, app.GetStepAnswerDistribution(req))
 		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
 	})
 }
 
 // =============================================================================
-// DeleteAIContext
+// DeleteChatbotFlow
 // =============================================================================
 
-func TestApp_DeleteAIContext(t *testing.T) {
+func TestApp_DeleteChatbotFlow(t *testing.T) {
 	t.Parallel()
 
 	t.Run("success", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
-		ctx := createTestAIContext(t, app, org.ID, "To Delete Context")
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("delete-flow")),
+			testutil.WithRoleID(&role.ID),
+		)
+		flow := createTestChatbotFlow(t, app, org.ID, "To Delete Flow")
 
 		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", ctx.ID.String())
+		testutil.SetPathParam(req, "id", flow.ID.String())
 
-		err := app.DeleteAIContext(req)
+		err := app.DeleteChatbotFlow(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
@@ -995,703 +1652,896 @@ func TestApp_DeleteAIContext(t *testing.T) {
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
-		assert.Equal(t, "AI context deleted successfully", resp.Data.Message)
+		assert.Equal(t, "Flow deleted successfully", resp.Data.Message)
 
 		// Verify soft-deleted
 		var count int64
-		app.DB.Model(&models.AIContext{}).Where("id = ?", ctx.ID).Count(&count)
+		app.DB.Model(&models.ChatbotFlow{}).Where("id = ?", flow.ID).Count(&count)
 		assert.Equal(t, int64(0), count)
 	})
 
 	t.Run("not found", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("delete-flow-nf")),
+			testutil.WithRoleID(&role.ID),
+		)
 
 		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
 		testutil.SetPathParam(req, "id", uuid.New().String())
 
-		err := app.DeleteAIContext(req)
+		err := app.DeleteChatbotFlow(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
 	})
 }
 
 // =============================================================================
-// GetChatbotSettings — additional coverage
+// ListAIContexts
 // =============================================================================
 
-func TestApp_GetChatbotSettings_ExistingSettings(t *testing.T) {
+func TestApp_ListAIContexts(t *testing.T) {
 	t.Parallel()
 
-	t.Run("returns persisted settings when they exist", func(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
-		// Create settings directly in the DB
-		settings := &models.ChatbotSettings{
-			BaseModel:      models.BaseModel{ID: uuid.New()},
-			OrganizationID: org.ID,
-			IsEnabled:      true,
-			DefaultResponse: "Custom greeting!",
-			FallbackMessage: "I do not understand.",
-			SessionTimeoutMins: 45,
-			AI: models.AIConfig{
-				Enabled:  true,
-				Provider: models.AIProviderOpenAI,
-				Model:    "gpt-4o",
-			},
-		}
-		require.NoError(t, app.DB.Create(settings).Error)
+		createTestAIContext(t, app, org.ID, "FAQ Context")
+		createTestAIContext(t, app, org.ID, "Product Context")
 
 		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.GetChatbotSettings(req)
+		err := app.ListAIContexts(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
 			Data struct {
-				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+				Contexts []handlers.AIContextResponse `json:"contexts"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
-
-		assert.True(t, resp.Data.Settings.Enabled)
-		assert.Equal(t, "Custom greeting!", resp.Data.Settings.GreetingMessage)
-		assert.Equal(t, "I do not understand.", resp.Data.Settings.FallbackMessage)
-		assert.Equal(t, 45, resp.Data.Settings.SessionTimeoutMinutes)
-		assert.True(t, resp.Data.Settings.AIEnabled)
-		assert.Equal(t, models.AIProviderOpenAI, resp.Data.Settings.AIProvider)
-		assert.Equal(t, "gpt-4o", resp.Data.Settings.AIModel)
+		assert.Len(t, resp.Data.Contexts, 2)
 	})
 
-	t.Run("stats reflect actual data counts", func(t *testing.T) {
+	t.Run("empty list", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
-		// Create some keyword rules and flows so stats are non-zero
-		createTestKeywordRule(t, app, org.ID, "Rule A", []string{"hi"})
-		createTestKeywordRule(t, app, org.ID, "Rule B", []string{"bye"})
-		createTestChatbotFlow(t, app, org.ID, "Flow A")
-
 		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.GetChatbotSettings(req)
+		err := app.ListAIContexts(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
 			Data struct {
-				Stats handlers.ChatbotStatsResponse `json:"stats"`
+				Contexts []handlers.AIContextResponse `json:"contexts"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
-
-		assert.Equal(t, int64(2), resp.Data.Stats.KeywordsCount)
-		assert.Equal(t, int64(1), resp.Data.Stats.FlowsCount)
+		assert.Len(t, resp.Data.Contexts, 0)
 	})
 }
 
 // =============================================================================
-// UpdateChatbotSettings — additional coverage
+// CreateAIContext
 // =============================================================================
 
-func TestApp_UpdateChatbotSettings_PartialUpdate(t *testing.T) {
+func TestApp_CreateAIContext(t *testing.T) {
 	t.Parallel()
 
-	t.Run("partial update only changes provided fields", func(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
-		// First, create full settings
-		setupReq := testutil.NewJSONRequest(t, map[string]any{
-			"enabled":                 true,
-			"greeting_message":        "Hello!",
-			"session_timeout_minutes": 60,
-			"fallback_message":        "Sorry, I did not get that.",
-		})
-		testutil.SetAuthContext(setupReq, org.ID, user.ID)
-		err := app.UpdateChatbotSettings(setupReq)
-		require.NoError(t, err)
-
-		// Now update only the greeting message
-		updateReq := testutil.NewJSONRequest(t, map[string]any{
-			"greeting_message": "Welcome!",
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":             "Product FAQ",
+			"context_type":     "static",
+			"trigger_keywords": []string{"product", "pricing"},
+			"static_content":   "Our product costs $99/month. We offer a free trial.",
+			"priority":         20,
+			"enabled":          true,
 		})
-		testutil.SetAuthContext(updateReq, org.ID, user.ID)
-		err = app.UpdateChatbotSettings(updateReq)
-		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(updateReq))
+		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		// Verify: greeting changed, other fields preserved
-		getReq := testutil.NewGETRequest(t)
-		testutil.SetAuthContext(getReq, org.ID, user.ID)
-		err = app.GetChatbotSettings(getReq)
+		err := app.CreateAIContext(req)
 		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
 			Data struct {
-				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+				ID      string `json:"id"`
+				Message string `json:"message"`
 			} `json:"data"`
 		}
-		err = json.Unmarshal(testutil.GetResponseBody(getReq), &resp)
+		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.Data.ID)
+		assert.Equal(t, "AI context created successfully", resp.Data.Message)
+
+		// Verify persisted
+		parsedID, err := uuid.Parse(resp.Data.ID)
 		require.NoError(t, err)
 
-		assert.True(t, resp.Data.Settings.Enabled)
-		assert.Equal(t, "Welcome!", resp.Data.Settings.GreetingMessage)
-		assert.Equal(t, 60, resp.Data.Settings.SessionTimeoutMinutes)
-		assert.Equal(t, "Sorry, I did not get that.", resp.Data.Settings.FallbackMessage)
+		var ctx models.AIContext
+		require.NoError(t, app.DB.First(&ctx, "id = ?", parsedID).Error)
+		assert.Equal(t, "Product FAQ", ctx.Name)
+		assert.Equal(t, models.ContextTypeStatic, ctx.ContextType)
+		assert.Equal(t, 20, ctx.Priority)
+		assert.True(t, ctx.IsEnabled)
 	})
+}
 
-	t.Run("update business hours settings", func(t *testing.T) {
+// =============================================================================
+// GetAIContext
+// =============================================================================
+
+func TestApp_GetAIContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		ctx := createTestAIContext(t, app, org.ID, "FAQ Context")
 
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"business_hours_enabled":        true,
-			"out_of_hours_message":          "We are closed right now.",
-			"allow_automated_outside_hours": false,
-		})
+		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", ctx.ID.String())
 
-		err := app.UpdateChatbotSettings(req)
+		err := app.GetAIContext(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		// Read back
-		getReq := testutil.NewGETRequest(t)
-		testutil.SetAuthContext(getReq, org.ID, user.ID)
-		err = app.GetChatbotSettings(getReq)
-		require.NoError(t, err)
-
 		var resp struct {
-			Data struct {
-				Settings handlers.ChatbotSettingsResponse `json:"settings"`
-			} `json:"data"`
+			Data models.AIContext `json:"data"`
 		}
-		err = json.Unmarshal(testutil.GetResponseBody(getReq), &resp)
+		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
-
-		assert.True(t, resp.Data.Settings.BusinessHoursEnabled)
-		assert.Equal(t, "We are closed right now.", resp.Data.Settings.OutOfHoursMessage)
-		assert.False(t, resp.Data.Settings.AllowAutomatedOutsideHours)
+		assert.Equal(t, ctx.ID, resp.Data.ID)
+		assert.Equal(t, "FAQ Context", resp.Data.Name)
+		assert.Equal(t, models.ContextTypeStatic, resp.Data.ContextType)
 	})
 
-	t.Run("update agent assignment settings", func(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"allow_agent_queue_pickup":        false,
-			"assign_to_same_agent":            true,
-			"agent_current_conversation_only": true,
-		})
+		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", uuid.New().String())
 
-		err := app.UpdateChatbotSettings(req)
-		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
-
-		getReq := testutil.NewGETRequest(t)
-		testutil.SetAuthContext(getReq, org.ID, user.ID)
-		err = app.GetChatbotSettings(getReq)
-		require.NoError(t, err)
-
-		var resp struct {
-			Data struct {
-				Settings handlers.ChatbotSettingsResponse `json:"settings"`
-			} `json:"data"`
-		}
-		err = json.Unmarshal(testutil.GetResponseBody(getReq), &resp)
+		err := app.GetAIContext(req)
 		require.NoError(t, err)
-
-		assert.False(t, resp.Data.Settings.AllowAgentQueuePickup)
-		assert.True(t, resp.Data.Settings.AssignToSameAgent)
-		assert.True(t, resp.Data.Settings.AgentCurrentConversationOnly)
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
 	})
+}
 
-	t.Run("update client inactivity settings", func(t *testing.T) {
-		app := newTestApp(t)
-		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
-
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"client_reminder_enabled":   true,
-			"client_reminder_minutes":   15,
-			"client_reminder_message":   "Are you still there?",
-			"client_auto_close_minutes": 30,
-			"client_auto_close_message": "Session closed due to inactivity.",
-		})
-		testutil.SetAuthContext(req, org.ID, user.ID)
-
-		err := app.UpdateChatbotSettings(req)
-		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
-
-		getReq := testutil.NewGETRequest(t)
-		testutil.SetAuthContext(getReq, org.ID, user.ID)
-		err = app.GetChatbotSettings(getReq)
-		require.NoError(t, err)
-
-		var resp struct {
-			Data struct {
-				Settings handlers.ChatbotSettingsResponse `json:"settings"`
-			} `json:"data"`
-		}
-		err = json.Unmarshal(testutil.GetResponseBody(getReq), &resp)
-		require.NoError(t, err)
+// =============================================================================
+// DeleteAIContext
+// =============================================================================
 
-		assert.True(t, resp.Data.Settings.ClientReminderEnabled)
-		assert.Equal(t, 15, resp.Data.Settings.ClientReminderMinutes)
-		assert.Equal(t, "Are you still there?", resp.Data.Settings.ClientReminderMessage)
-		assert.Equal(t, 30, resp.Data.Settings.ClientAutoCloseMinutes)
-		assert.Equal(t, "Session closed due to inactivity.", resp.Data.Settings.ClientAutoCloseMessage)
-	})
+func TestApp_DeleteAIContext(t *testing.T) {
+	t.Parallel()
 
-	t.Run("update SLA settings", func(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		ctx := createTestAIContext(t, app, org.ID, "To Delete Context")
 
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"sla_enabled":           true,
-			"sla_response_minutes":  5,
-			"sla_resolution_minutes": 120,
-			"sla_escalation_minutes": 45,
-			"sla_auto_close_hours":  48,
-			"sla_auto_close_message": "Conversation auto-closed.",
-			"sla_warning_message":   "SLA warning: response time exceeded.",
-		})
+		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", ctx.ID.String())
 
-		err := app.UpdateChatbotSettings(req)
+		err := app.DeleteAIContext(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		getReq := testutil.NewGETRequest(t)
-		testutil.SetAuthContext(getReq, org.ID, user.ID)
-		err = app.GetChatbotSettings(getReq)
-		require.NoError(t, err)
-
 		var resp struct {
 			Data struct {
-				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+				Message string `json:"message"`
 			} `json:"data"`
 		}
-		err = json.Unmarshal(testutil.GetResponseBody(getReq), &resp)
+		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
+		assert.Equal(t, "AI context deleted successfully", resp.Data.Message)
 
-		assert.True(t, resp.Data.Settings.SLAEnabled)
-		assert.Equal(t, 5, resp.Data.Settings.SLAResponseMinutes)
-		assert.Equal(t, 120, resp.Data.Settings.SLAResolutionMinutes)
-		assert.Equal(t, 45, resp.Data.Settings.SLAEscalationMinutes)
-		assert.Equal(t, 48, resp.Data.Settings.SLAAutoCloseHours)
-		assert.Equal(t, "Conversation auto-closed.", resp.Data.Settings.SLAAutoCloseMessage)
-		assert.Equal(t, "SLA warning: response time exceeded.", resp.Data.Settings.SLAWarningMessage)
+		// Verify soft-deleted
+		var count int64
+		app.DB.Model(&models.AIContext{}).Where("id = ?", ctx.ID).Count(&count)
+		assert.Equal(t, int64(0), count)
 	})
 
-	t.Run("invalid JSON body returns 400", func(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
-		ctx := &fasthttp.RequestCtx{}
-		ctx.Request.Header.SetContentType("application/json")
-		ctx.Request.Header.SetMethod("POST")
-		ctx.Request.SetBody([]byte(`{invalid json`))
-		req := &fastglue.Request{RequestCtx: ctx}
+		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", uuid.New().String())
 
-		err := app.UpdateChatbotSettings(req)
+		err := app.DeleteAIContext(req)
 		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
 	})
 }
 
 // =============================================================================
-// CreateKeywordRule — additional match types and response types
+// TestAIContext
 // =============================================================================
 
-func TestApp_CreateKeywordRule_MatchTypes(t *testing.T) {
+func TestApp_TestAIContext(t *testing.T) {
 	t.Parallel()
 
-	t.Run("exact match type", func(t *testing.T) {
+	t.Run("api context performs request and redacts secrets", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer super-secret-token", r.Header.Get("Authorization"))
+			assert.Equal(t, "sk-123", r.URL.Query().Get("api_key"))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"orders":[{"id":"A1"}]}`))
+		}))
+		t.Cleanup(server.Close)
+
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
 
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"name":          "Exact Match Rule",
-			"keywords":      []string{"STOP"},
-			"match_type":    "exact",
-			"response_type": "text",
-			"response_content": map[string]any{
-				"text": "You have been unsubscribed.",
+		aiCtx := &models.AIContext{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			OrganizationID: org.ID,
+			Name:           "Orders API",
+			ContextType:    models.ContextTypeAPI,
+			ApiConfig: models.JSONB{
+				"url":     server.URL + "/orders?api_key=sk-123&status=open",
+				"method":  "GET",
+				"headers": map[string]interface{}{"Authorization": "Bearer super-secret-token"},
 			},
-			"priority": 100,
-			"enabled":  true,
-		})
+			Priority:  10,
+			IsEnabled: true,
+		}
+		require.NoError(t, app.DB.Create(aiCtx).Error)
+
+		req := testutil.NewJSONRequest(t, map[string]any{})
 		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", aiCtx.ID.String())
 
-		err := app.CreateKeywordRule(req)
+		err := app.TestAIContext(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
+		body := testutil.GetResponseBody(req)
+		assert.NotContains(t, string(body), "super-secret-token")
+		assert.NotContains(t, string(body), "sk-123")
+
 		var resp struct {
-			Data struct {
-				ID string `json:"id"`
-			} `json:"data"`
+			Data handlers.AIContextTestResponse `json:"data"`
 		}
-		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
-		require.NoError(t, err)
-
-		parsedID, err := uuid.Parse(resp.Data.ID)
-		require.NoError(t, err)
-
-		var rule models.KeywordRule
-		require.NoError(t, app.DB.First(&rule, "id = ?", parsedID).Error)
-		assert.Equal(t, models.MatchTypeExact, rule.MatchType)
-		assert.Equal(t, 100, rule.Priority)
+		require.NoError(t, json.Unmarshal(body, &resp))
+		assert.True(t, resp.Data.Success)
+		assert.Equal(t, http.StatusOK, resp.Data.StatusCode)
+		assert.Equal(t, `{"orders":[{"id":"A1"}]}`, resp.Data.BodyPreview)
+		assert.Contains(t, resp.Data.ApiConfig["url"], "status=open")
 	})
 
-	t.Run("starts_with match type", func(t *testing.T) {
+	t.Run("api context reports non-2xx status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(strings.Repeat("x", 2000)))
+		}))
+		t.Cleanup(server.Close)
+
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
 
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"name":          "Prefix Rule",
-			"keywords":      []string{"order#"},
-			"match_type":    "starts_with",
-			"response_type": "text",
-			"response_content": map[string]any{
-				"text": "Looking up your order...",
-			},
-			"enabled": true,
-		})
+		aiCtx := &models.AIContext{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			OrganizationID: org.ID,
+			Name:           "Broken API",
+			ContextType:    models.ContextTypeAPI,
+			ApiConfig:      models.JSONB{"url": server.URL},
+			Priority:       10,
+			IsEnabled:      true,
+		}
+		require.NoError(t, app.DB.Create(aiCtx).Error)
+
+		req := testutil.NewRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", aiCtx.ID.String())
 
-		err := app.CreateKeywordRule(req)
+		err := app.TestAIContext(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
-			Data struct {
-				ID string `json:"id"`
-			} `json:"data"`
+			Data handlers.AIContextTestResponse `json:"data"`
 		}
-		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
-		require.NoError(t, err)
-
-		parsedID, err := uuid.Parse(resp.Data.ID)
-		require.NoError(t, err)
-
-		var rule models.KeywordRule
-		require.NoError(t, app.DB.First(&rule, "id = ?", parsedID).Error)
-		assert.Equal(t, models.MatchTypeStartsWith, rule.MatchType)
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.False(t, resp.Data.Success)
+		assert.Equal(t, http.StatusUnauthorized, resp.Data.StatusCode)
+		assert.LessOrEqual(t, len(resp.Data.BodyPreview), 500)
+		assert.NotEmpty(t, resp.Data.Error)
 	})
 
-	t.Run("regex match type", func(t *testing.T) {
+	t.Run("static context echoes content length", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		aiCtx := createTestAIContext(t, app, org.ID, "Static Context")
 
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"name":          "Regex Rule",
-			"keywords":      []string{`^\d{5,6}$`},
-			"match_type":    "regex",
-			"response_type": "text",
-			"response_content": map[string]any{
-				"text": "Got your PIN code.",
-			},
-			"enabled": true,
-		})
+		req := testutil.NewRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", aiCtx.ID.String())
 
-		err := app.CreateKeywordRule(req)
+		err := app.TestAIContext(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
-			Data struct {
-				ID string `json:"id"`
-			} `json:"data"`
+			Data handlers.AIContextTestResponse `json:"data"`
 		}
-		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
-		require.NoError(t, err)
-
-		parsedID, err := uuid.Parse(resp.Data.ID)
-		require.NoError(t, err)
-
-		var rule models.KeywordRule
-		require.NoError(t, app.DB.First(&rule, "id = ?", parsedID).Error)
-		assert.Equal(t, models.MatchTypeRegex, rule.MatchType)
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.True(t, resp.Data.Success)
+		assert.Equal(t, models.ContextTypeStatic, resp.Data.ContextType)
+		assert.Equal(t, len(aiCtx.StaticContent), resp.Data.ContentLength)
+		assert.Zero(t, resp.Data.StatusCode)
 	})
 
-	t.Run("transfer response type", func(t *testing.T) {
+	t.Run("cannot test context from another org", func(t *testing.T) {
 		app := newTestApp(t)
-		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		org1 := testutil.CreateTestOrganization(t, app.DB)
+		aiCtx := createTestAIContext(t, app, org1.ID, "Org1 Context")
 
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"name":          "Transfer Rule",
-			"keywords":      []string{"agent", "human"},
-			"match_type":    "contains",
-			"response_type": "transfer",
-			"response_content": map[string]any{
-				"text": "Connecting you with a live agent.",
+		org2 := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org2.ID)
+		user2 := testutil.CreateTestUser(t, app.DB, org2.ID, testutil.WithRoleID(&adminRole.ID))
+
+		req := testutil.NewRequest(t)
+		testutil.SetAuthContext(req, org2.ID, user2.ID)
+		testutil.SetPathParam(req, "id", aiCtx.ID.String())
+
+		err := app.TestAIContext(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_PreviewAIContext(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+	for _, c := range []struct {
+		name     string
+		keywords models.StringArray
+		content  string
+		priority int
+	}{
+		{"Returns", models.StringArray{"return"}, "Returns are accepted within 30 days.", 20},
+		{"Shipping", models.StringArray{"delivery"}, "Delivery takes 3-5 business days.", 10},
+		{"Company", nil, "We sell furniture.", 1},
+		{"Pricing", models.StringArray{"price"}, "Prices include tax.", 50},
+	} {
+		require.NoError(t, app.DB.Create(&models.AIContext{
+			BaseModel:       models.BaseModel{ID: uuid.New()},
+			OrganizationID:  org.ID,
+			Name:            c.name,
+			ContextType:     models.ContextTypeStatic,
+			TriggerKeywords: c.keywords,
+			StaticContent:   c.content,
+			Priority:        c.priority,
+			IsEnabled:       true,
+		}).Error)
+	}
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		AI:             models.AIConfig{ContextLimit: 2, ContextMaxChars: 50},
+	}).Error)
+
+	req := testutil.NewJSONRequest(t, map[string]any{"trigger_text": "Can I return it after delivery?"})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	require.NoError(t, app.PreviewAIContext(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.AIContextPreviewResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+
+	require.Len(t, resp.Data.Included, 2)
+	assert.Equal(t, "Returns", resp.Data.Included[0].Name)
+	assert.False(t, resp.Data.Included[0].Truncated)
+	assert.Equal(t, "Shipping", resp.Data.Included[1].Name)
+	assert.True(t, resp.Data.Included[1].Truncated, "lowest priority is cut to fit the budget")
+	assert.Equal(t, 50, resp.Data.TotalChars)
+	require.Len(t, resp.Data.Excluded, 1)
+	assert.Equal(t, "Company", resp.Data.Excluded[0].Name)
+	assert.Contains(t, resp.Data.Context, "### Returns\nReturns are accepted within 30 days.")
+	assert.NotContains(t, resp.Data.Context, "Prices include tax.")
+
+	t.Run("requires trigger text", func(t *testing.T) {
+		req := testutil.NewJSONRequest(t, map[string]any{"trigger_text": "  "})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.PreviewAIContext(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+}
+
+// =============================================================================
+// GetChatbotSettings — additional coverage
+// =============================================================================
+
+func TestApp_GetChatbotSettings_ExistingSettings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns persisted settings when they exist", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		// Create settings directly in the DB
+		settings := &models.ChatbotSettings{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			OrganizationID: org.ID,
+			IsEnabled:      true,
+			DefaultResponse: "Custom greeting!",
+			FallbackMessage: "I do not understand.",
+			SessionTimeoutMins: 45,
+			AI: models.AIConfig{
+				Enabled:  true,
+				Provider: models.AIProviderOpenAI,
+				Model:    "gpt-4o",
 			},
-			"priority": 99,
-			"enabled":  true,
-		})
+		}
+		require.NoError(t, app.DB.Create(settings).Error)
+
+		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.CreateKeywordRule(req)
+		err := app.GetChatbotSettings(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
 			Data struct {
-				ID string `json:"id"`
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
 
-		parsedID, err := uuid.Parse(resp.Data.ID)
-		require.NoError(t, err)
-
-		var rule models.KeywordRule
-		require.NoError(t, app.DB.First(&rule, "id = ?", parsedID).Error)
-		assert.Equal(t, models.ResponseTypeTransfer, rule.ResponseType)
-		assert.Equal(t, 99, rule.Priority)
+		assert.True(t, resp.Data.Settings.Enabled)
+		assert.Equal(t, "Custom greeting!", resp.Data.Settings.GreetingMessage)
+		assert.Equal(t, "I do not understand.", resp.Data.Settings.FallbackMessage)
+		assert.Equal(t, 45, resp.Data.Settings.SessionTimeoutMinutes)
+		assert.True(t, resp.Data.Settings.AIEnabled)
+		assert.Equal(t, models.AIProviderOpenAI, resp.Data.Settings.AIProvider)
+		assert.Equal(t, "gpt-4o", resp.Data.Settings.AIModel)
 	})
 
-	t.Run("defaults match_type and response_type when omitted", func(t *testing.T) {
+	t.Run("stats reflect actual data counts", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"name":     "Default Types Rule",
-			"keywords": []string{"test"},
-			"response_content": map[string]any{
-				"text": "default types test",
-			},
-			"enabled": true,
-		})
+		// Create some keyword rules and flows so stats are non-zero
+		createTestKeywordRule(t, app, org.ID, "Rule A", []string{"hi"})
+		createTestKeywordRule(t, app, org.ID, "Rule B", []string{"bye"})
+		createTestChatbotFlow(t, app, org.ID, "Flow A")
+
+		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.CreateKeywordRule(req)
+		err := app.GetChatbotSettings(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
 			Data struct {
-				ID string `json:"id"`
+				Stats handlers.ChatbotStatsResponse `json:"stats"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
 
-		parsedID, err := uuid.Parse(resp.Data.ID)
-		require.NoError(t, err)
-
-		var rule models.KeywordRule
-		require.NoError(t, app.DB.First(&rule, "id = ?", parsedID).Error)
-		assert.Equal(t, models.MatchTypeContains, rule.MatchType)
-		assert.Equal(t, models.ResponseTypeText, rule.ResponseType)
+		assert.Equal(t, int64(2), resp.Data.Stats.KeywordsCount)
+		assert.Equal(t, int64(1), resp.Data.Stats.FlowsCount)
 	})
 }
 
 // =============================================================================
-// UpdateKeywordRule — additional coverage
+// UpdateChatbotSettings — additional coverage
 // =============================================================================
 
-func TestApp_UpdateKeywordRule_Additional(t *testing.T) {
+func TestApp_UpdateChatbotSettings_PartialUpdate(t *testing.T) {
 	t.Parallel()
 
-	t.Run("not found", func(t *testing.T) {
+	t.Run("partial update only changes provided fields", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"name": "Ghost",
+		// First, create full settings
+		setupReq := testutil.NewJSONRequest(t, map[string]any{
+			"enabled":                 true,
+			"greeting_message":        "Hello!",
+			"session_timeout_minutes": 60,
+			"fallback_message":        "Sorry, I did not get that.",
 		})
-		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", uuid.New().String())
+		testutil.SetAuthContext(setupReq, org.ID, user.ID)
+		err := app.UpdateChatbotSettings(setupReq)
+		require.NoError(t, err)
 
-		err := app.UpdateKeywordRule(req)
+		// Now update only the greeting message
+		updateReq := testutil.NewJSONRequest(t, map[string]any{
+			"greeting_message": "Welcome!",
+		})
+		testutil.SetAuthContext(updateReq, org.ID, user.ID)
+		err = app.UpdateChatbotSettings(updateReq)
 		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(updateReq))
+
+		// Verify: greeting changed, other fields preserved
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+		err = app.GetChatbotSettings(getReq)
+		require.NoError(t, err)
+
+		var resp struct {
+			Data struct {
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(getReq), &resp)
+		require.NoError(t, err)
+
+		assert.True(t, resp.Data.Settings.Enabled)
+		assert.Equal(t, "Welcome!", resp.Data.Settings.GreetingMessage)
+		assert.Equal(t, 60, resp.Data.Settings.SessionTimeoutMinutes)
+		assert.Equal(t, "Sorry, I did not get that.", resp.Data.Settings.FallbackMessage)
 	})
 
-	t.Run("update match_type and response_content", func(t *testing.T) {
+	t.Run("update business hours settings", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
-		rule := createTestKeywordRule(t, app, org.ID, "MatchUpdate", []string{"hello"})
 
-		newMatchType := models.MatchTypeExact
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"match_type": string(newMatchType),
-			"response_content": map[string]any{
-				"text": "Updated response content.",
-			},
+			"business_hours_enabled":        true,
+			"out_of_hours_message":          "We are closed right now.",
+			"allow_automated_outside_hours": false,
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", rule.ID.String())
 
-		err := app.UpdateKeywordRule(req)
+		err := app.UpdateChatbotSettings(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		var updated models.KeywordRule
-		require.NoError(t, app.DB.First(&updated, "id = ?", rule.ID).Error)
-		assert.Equal(t, models.MatchTypeExact, updated.MatchType)
-		assert.Equal(t, "Updated response content.", updated.ResponseContent["text"])
+		// Read back
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+		err = app.GetChatbotSettings(getReq)
+		require.NoError(t, err)
+
+		var resp struct {
+			Data struct {
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(getReq), &resp)
+		require.NoError(t, err)
+
+		assert.True(t, resp.Data.Settings.BusinessHoursEnabled)
+		assert.Equal(t, "We are closed right now.", resp.Data.Settings.OutOfHoursMessage)
+		assert.False(t, resp.Data.Settings.AllowAutomatedOutsideHours)
 	})
 
-	t.Run("update response_type to transfer", func(t *testing.T) {
+	t.Run("update agent assignment settings", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
-		rule := createTestKeywordRule(t, app, org.ID, "TypeSwitch", []string{"agent"})
 
-		newRespType := models.ResponseTypeTransfer
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"response_type": string(newRespType),
+			"allow_agent_queue_pickup":        false,
+			"assign_to_same_agent":            true,
+			"agent_current_conversation_only": true,
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", rule.ID.String())
 
-		err := app.UpdateKeywordRule(req)
+		err := app.UpdateChatbotSettings(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		var updated models.KeywordRule
-		require.NoError(t, app.DB.First(&updated, "id = ?", rule.ID).Error)
-		assert.Equal(t, models.ResponseTypeTransfer, updated.ResponseType)
-	})
-}
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+		err = app.GetChatbotSettings(getReq)
+		require.NoError(t, err)
 
-// =============================================================================
-// ListKeywordRules — cross-org isolation
-// =============================================================================
+		var resp struct {
+			Data struct {
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(getReq), &resp)
+		require.NoError(t, err)
 
-func TestApp_ListKeywordRules_OrgIsolation(t *testing.T) {
-	t.Parallel()
+		assert.False(t, resp.Data.Settings.AllowAgentQueuePickup)
+		assert.True(t, resp.Data.Settings.AssignToSameAgent)
+		assert.True(t, resp.Data.Settings.AgentCurrentConversationOnly)
+	})
 
-	t.Run("rules from other org are not visible", func(t *testing.T) {
+	t.Run("update client inactivity settings", func(t *testing.T) {
 		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
-		org1 := testutil.CreateTestOrganization(t, app.DB)
-		user1 := testutil.CreateTestUser(t, app.DB, org1.ID)
-		createTestKeywordRule(t, app, org1.ID, "Org1 Rule", []string{"org1"})
-
-		org2 := testutil.CreateTestOrganization(t, app.DB)
-		user2 := testutil.CreateTestUser(t, app.DB, org2.ID,
-			testutil.WithEmail(testutil.UniqueEmail("org2-kw")),
-		)
-		createTestKeywordRule(t, app, org2.ID, "Org2 Rule", []string{"org2"})
-
-		// User from org1 should only see org1's rules
-		req := testutil.NewGETRequest(t)
-		testutil.SetAuthContext(req, org1.ID, user1.ID)
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"client_reminder_enabled":   true,
+			"client_reminder_minutes":   15,
+			"client_reminder_message":   "Are you still there?",
+			"client_auto_close_minutes": 30,
+			"client_auto_close_message": "Session closed due to inactivity.",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.ListKeywordRules(req)
+		err := app.UpdateChatbotSettings(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+		err = app.GetChatbotSettings(getReq)
+		require.NoError(t, err)
+
 		var resp struct {
 			Data struct {
-				Rules []handlers.KeywordRuleResponse `json:"rules"`
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
 			} `json:"data"`
 		}
-		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		err = json.Unmarshal(testutil.GetResponseBody(getReq), &resp)
 		require.NoError(t, err)
-		assert.Len(t, resp.Data.Rules, 1)
-		assert.Equal(t, "Org1 Rule", resp.Data.Rules[0].Name)
 
-		// User from org2 should only see org2's rules
-		req2 := testutil.NewGETRequest(t)
-		testutil.SetAuthContext(req2, org2.ID, user2.ID)
+		assert.True(t, resp.Data.Settings.ClientReminderEnabled)
+		assert.Equal(t, 15, resp.Data.Settings.ClientReminderMinutes)
+		assert.Equal(t, "Are you still there?", resp.Data.Settings.ClientReminderMessage)
+		assert.Equal(t, 30, resp.Data.Settings.ClientAutoCloseMinutes)
+		assert.Equal(t, "Session closed due to inactivity.", resp.Data.Settings.ClientAutoCloseMessage)
+	})
 
-		err = app.ListKeywordRules(req2)
+	t.Run("update SLA settings", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"sla_enabled":           true,
+			"sla_response_minutes":  5,
+			"sla_resolution_minutes": 120,
+			"sla_escalation_minutes": 45,
+			"sla_auto_close_hours":  48,
+			"sla_auto_close_message": "Conversation auto-closed.",
+			"sla_warning_message":   "SLA warning: response time exceeded.",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.UpdateChatbotSettings(req)
 		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		var resp2 struct {
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+		err = app.GetChatbotSettings(getReq)
+		require.NoError(t, err)
+
+		var resp struct {
 			Data struct {
-				Rules []handlers.KeywordRuleResponse `json:"rules"`
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
 			} `json:"data"`
 		}
-		err = json.Unmarshal(testutil.GetResponseBody(req2), &resp2)
+		err = json.Unmarshal(testutil.GetResponseBody(getReq), &resp)
 		require.NoError(t, err)
-		assert.Len(t, resp2.Data.Rules, 1)
-		assert.Equal(t, "Org2 Rule", resp2.Data.Rules[0].Name)
+
+		assert.True(t, resp.Data.Settings.SLAEnabled)
+		assert.Equal(t, 5, resp.Data.Settings.SLAResponseMinutes)
+		assert.Equal(t, 120, resp.Data.Settings.SLAResolutionMinutes)
+		assert.Equal(t, 45, resp.Data.Settings.SLAEscalationMinutes)
+		assert.Equal(t, 48, resp.Data.Settings.SLAAutoCloseHours)
+		assert.Equal(t, "Conversation auto-closed.", resp.Data.Settings.SLAAutoCloseMessage)
+		assert.Equal(t, "SLA warning: response time exceeded.", resp.Data.Settings.SLAWarningMessage)
 	})
-}
 
-// =============================================================================
-// CreateChatbotFlow — additional coverage
-// =============================================================================
+	t.Run("rejects inconsistent timings", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
-func TestApp_CreateChatbotFlow_Additional(t *testing.T) {
-	t.Parallel()
+		cases := []struct {
+			body    map[string]any
+			wantErr string
+		}{
+			{map[string]any{"sla_response_minutes": -1}, "sla_response_minutes cannot be negative"},
+			{map[string]any{"sla_auto_close_hours": -2}, "sla_auto_close_hours cannot be negative"},
+			{map[string]any{"sla_response_minutes": 45, "sla_escalation_minutes": 30}, "sla_response_minutes cannot exceed sla_escalation_minutes"},
+			// Compared against the default resolution time of 60 minutes
+			{map[string]any{"sla_escalation_minutes": 90}, "sla_escalation_minutes cannot exceed sla_resolution_minutes"},
+			{map[string]any{"sla_response_minutes": 90, "sla_escalation_minutes": 0, "sla_resolution_minutes": 60}, "sla_response_minutes cannot exceed sla_resolution_minutes"},
+			{map[string]any{"client_reminder_enabled": true, "client_reminder_minutes": 60, "client_auto_close_minutes": 60}, "client_reminder_minutes must be less than client_auto_close_minutes"},
+			{map[string]any{"client_auto_close_minutes": -5}, "client_auto_close_minutes cannot be negative"},
+			{map[string]any{"business_hours": []map[string]any{{"day": 7, "enabled": true, "start_time": "09:00", "end_time": "17:00"}}}, "business_hours: day must be between 0 (Sunday) and 6 (Saturday)"},
+			{map[string]any{"business_hours": []map[string]any{{"day": 1, "enabled": true, "start_time": "17:00", "end_time": "09:00"}}}, "business_hours: end_time must be after start_time"},
+			{map[string]any{"ai_timeout_seconds": 600}, "ai_timeout_seconds must be between 0 and 120"},
+			{map[string]any{"ai_failure_action": "retry"}, "ai_failure_action must be one of: fallback_message, transfer"},
+			{map[string]any{"quiet_hours_enabled": true, "quiet_hours_start": "10pm", "quiet_hours_end": "07:00"}, "quiet_hours_start must be a time in HH:MM format"},
+			{map[string]any{"quiet_hours_enabled": true, "quiet_hours_start": "22:00"}, "quiet_hours_end must be a time in HH:MM format"},
+			{map[string]any{"quiet_hours_enabled": true, "quiet_hours_start": "22:00", "quiet_hours_end": "22:00"}, "quiet_hours_start and quiet_hours_end must differ"},
+			{map[string]any{"quiet_hours_timezone": "Mars/Olympus"}, "Invalid quiet_hours_timezone"},
+		}
+		for _, tc := range cases {
+			req := testutil.NewJSONRequest(t, tc.body)
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			require.NoError(t, app.UpdateChatbotSettings(req))
+			require.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req), "body: %v", tc.body)
+
+			var result map[string]any
+			require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &result))
+			assert.Equal(t, tc.wantErr, result["message"])
+		}
 
-	t.Run("validation error missing name", func(t *testing.T) {
+		var count int64
+		app.DB.Model(&models.ChatbotSettings{}).Where("organization_id = ?", org.ID).Count(&count)
+		assert.Zero(t, count, "rejected settings aren't saved")
+
+		// Disabled timers are left out of the ordering
+		req := testutil.NewJSONRequest(t, map[string]any{"sla_response_minutes": 90, "sla_escalation_minutes": 0, "sla_resolution_minutes": 120})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var saved models.ChatbotSettings
+		require.NoError(t, app.DB.Where("organization_id = ?", org.ID).First(&saved).Error)
+		assert.Zero(t, saved.SLA.EscalationMinutes)
+	})
+
+	t.Run("business hours timezone", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		perms := getChatbotFlowPermissions(t, app)
-		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
-		user := testutil.CreateTestUser(t, app.DB, org.ID,
-			testutil.WithEmail(testutil.UniqueEmail("create-flow-noname")),
-			testutil.WithRoleID(&role.ID),
-		)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"business_hours_timezone": "Mars/Olympus"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+		req = testutil.NewJSONRequest(t, map[string]any{
+			"business_hours_enabled":  true,
+			"business_hours_timezone": "Asia/Kolkata",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+		require.NoError(t, app.GetChatbotSettings(getReq))
+
+		var resp struct {
+			Data struct {
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(getReq), &resp))
+		assert.Equal(t, "Asia/Kolkata", resp.Data.Settings.BusinessHoursTimezone)
+	})
+
+	t.Run("quiet hours", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"description":      "Missing name flow",
-			"trigger_keywords": []string{"test"},
-			"enabled":          true,
+			"quiet_hours_enabled":  true,
+			"quiet_hours_start":    "22:00",
+			"quiet_hours_end":      "07:30",
+			"quiet_hours_timezone": "Europe/Berlin",
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		err := app.CreateChatbotFlow(req)
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+		require.NoError(t, app.GetChatbotSettings(getReq))
+
+		var resp struct {
+			Data struct {
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(getReq), &resp))
+		assert.True(t, resp.Data.Settings.QuietHoursEnabled)
+		assert.Equal(t, "22:00", resp.Data.Settings.QuietHoursStart)
+		assert.Equal(t, "07:30", resp.Data.Settings.QuietHoursEnd)
+		assert.Equal(t, "Europe/Berlin", resp.Data.Settings.QuietHoursTimezone)
+
+		// Disabling keeps the window so it can be switched back on
+		req = testutil.NewJSONRequest(t, map[string]any{"quiet_hours_enabled": false})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var saved models.ChatbotSettings
+		require.NoError(t, app.DB.Where("organization_id = ?", org.ID).First(&saved).Error)
+		assert.False(t, saved.QuietHours.Enabled)
+		assert.Equal(t, "22:00", saved.QuietHours.Start)
+	})
+
+	t.Run("invalid JSON body returns 400", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetContentType("application/json")
+		ctx.Request.Header.SetMethod("POST")
+		ctx.Request.SetBody([]byte(`{invalid json`))
+		req := &fastglue.Request{RequestCtx: ctx}
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.UpdateChatbotSettings(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 	})
+}
 
-	t.Run("create flow without steps", func(t *testing.T) {
+// =============================================================================
+// CreateKeywordRule — additional match types and response types
+// =============================================================================
+
+func TestApp_CreateKeywordRule_MatchTypes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("exact match type", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		perms := getChatbotFlowPermissions(t, app)
-		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
-		user := testutil.CreateTestUser(t, app.DB, org.ID,
-			testutil.WithEmail(testutil.UniqueEmail("create-flow-nosteps")),
-			testutil.WithRoleID(&role.ID),
-		)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"name":             "No Steps Flow",
-			"description":      "A flow with no steps",
-			"trigger_keywords": []string{"nostep"},
-			"enabled":          true,
+			"name":          "Exact Match Rule",
+			"keywords":      []string{"STOP"},
+			"match_type":    "exact",
+			"response_type": "text",
+			"response_content": map[string]any{
+				"text": "You have been unsubscribed.",
+			},
+			"priority": 100,
+			"enabled":  true,
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.CreateChatbotFlow(req)
+		err := app.CreateKeywordRule(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
@@ -1706,46 +2556,30 @@ func TestApp_CreateChatbotFlow_Additional(t *testing.T) {
 		parsedID, err := uuid.Parse(resp.Data.ID)
 		require.NoError(t, err)
 
-		var flow models.ChatbotFlow
-		require.NoError(t, app.DB.Preload("Steps").First(&flow, "id = ?", parsedID).Error)
-		assert.Equal(t, "No Steps Flow", flow.Name)
-		assert.Len(t, flow.Steps, 0)
+		var rule models.KeywordRule
+		require.NoError(t, app.DB.First(&rule, "id = ?", parsedID).Error)
+		assert.Equal(t, models.MatchTypeExact, rule.MatchType)
+		assert.Equal(t, 100, rule.Priority)
 	})
 
-	t.Run("create flow with completion config", func(t *testing.T) {
+	t.Run("starts_with match type", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		perms := getChatbotFlowPermissions(t, app)
-		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
-		user := testutil.CreateTestUser(t, app.DB, org.ID,
-			testutil.WithEmail(testutil.UniqueEmail("create-flow-complete")),
-			testutil.WithRoleID(&role.ID),
-		)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"name":               "Webhook Flow",
-			"trigger_keywords":   []string{"webhook"},
-			"initial_message":    "Starting form...",
-			"completion_message": "Thank you for submitting!",
-			"on_complete_action": "webhook",
-			"completion_config": map[string]any{
-				"url":    "https://example.com/webhook",
-				"method": "POST",
+			"name":          "Prefix Rule",
+			"keywords":      []string{"order#"},
+			"match_type":    "starts_with",
+			"response_type": "text",
+			"response_content": map[string]any{
+				"text": "Looking up your order...",
 			},
 			"enabled": true,
-			"steps": []map[string]any{
-				{
-					"step_name":  "ask_info",
-					"step_order": 1,
-					"message":    "Please enter your info.",
-					"input_type": "text",
-					"store_as":   "user_info",
-				},
-			},
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.CreateChatbotFlow(req)
+		err := app.CreateKeywordRule(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
@@ -1760,251 +2594,363 @@ func TestApp_CreateChatbotFlow_Additional(t *testing.T) {
 		parsedID, err := uuid.Parse(resp.Data.ID)
 		require.NoError(t, err)
 
-		var flow models.ChatbotFlow
-		require.NoError(t, app.DB.First(&flow, "id = ?", parsedID).Error)
-		assert.Equal(t, "webhook", flow.OnCompleteAction)
-		assert.Equal(t, "Starting form...", flow.InitialMessage)
-		assert.Equal(t, "Thank you for submitting!", flow.CompletionMessage)
+		var rule models.KeywordRule
+		require.NoError(t, app.DB.First(&rule, "id = ?", parsedID).Error)
+		assert.Equal(t, models.MatchTypeStartsWith, rule.MatchType)
 	})
-}
-
-// =============================================================================
-// UpdateChatbotFlow — additional coverage
-// =============================================================================
-
-func TestApp_UpdateChatbotFlow_Additional(t *testing.T) {
-	t.Parallel()
 
-	t.Run("not found", func(t *testing.T) {
+	t.Run("regex match type", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		perms := getChatbotFlowPermissions(t, app)
-		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
-		user := testutil.CreateTestUser(t, app.DB, org.ID,
-			testutil.WithEmail(testutil.UniqueEmail("update-flow-nf")),
-			testutil.WithRoleID(&role.ID),
-		)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"name": "Ghost Flow",
+			"name":          "Regex Rule",
+			"keywords":      []string{`^\d{5,6}$`},
+			"match_type":    "regex",
+			"response_type": "text",
+			"response_content": map[string]any{
+				"text": "Got your PIN code.",
+			},
+			"enabled": true,
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", uuid.New().String())
 
-		err := app.UpdateChatbotFlow(req)
+		err := app.CreateKeywordRule(req)
 		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		require.NoError(t, err)
+
+		parsedID, err := uuid.Parse(resp.Data.ID)
+		require.NoError(t, err)
+
+		var rule models.KeywordRule
+		require.NoError(t, app.DB.First(&rule, "id = ?", parsedID).Error)
+		assert.Equal(t, models.MatchTypeRegex, rule.MatchType)
 	})
 
-	t.Run("update replaces steps when provided", func(t *testing.T) {
+	t.Run("transfer response type", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		perms := getChatbotFlowPermissions(t, app)
-		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
-		user := testutil.CreateTestUser(t, app.DB, org.ID,
-			testutil.WithEmail(testutil.UniqueEmail("update-flow-steps")),
-			testutil.WithRoleID(&role.ID),
-		)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
-		// Create flow with one step
-		createReq := testutil.NewJSONRequest(t, map[string]any{
-			"name":             "Steps Flow",
-			"trigger_keywords": []string{"steps"},
-			"enabled":          true,
-			"steps": []map[string]any{
-				{
-					"step_name":  "old_step",
-					"step_order": 1,
-					"message":    "Old step",
-					"input_type": "text",
-					"store_as":   "old_data",
-				},
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":          "Transfer Rule",
+			"keywords":      []string{"agent", "human"},
+			"match_type":    "contains",
+			"response_type": "transfer",
+			"response_content": map[string]any{
+				"text": "Connecting you with a live agent.",
 			},
+			"priority": 99,
+			"enabled":  true,
 		})
-		testutil.SetAuthContext(createReq, org.ID, user.ID)
+		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.CreateChatbotFlow(createReq)
+		err := app.CreateKeywordRule(req)
 		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		var createResp struct {
+		var resp struct {
 			Data struct {
 				ID string `json:"id"`
 			} `json:"data"`
 		}
-		err = json.Unmarshal(testutil.GetResponseBody(createReq), &createResp)
+		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
 
-		// Update: replace steps with two new ones
-		updateReq := testutil.NewJSONRequest(t, map[string]any{
-			"steps": []map[string]any{
-				{
-					"step_name":  "new_step_1",
-					"step_order": 1,
-					"message":    "First new step",
-					"input_type": "text",
-					"store_as":   "new_data_1",
-				},
-				{
-					"step_name":  "new_step_2",
-					"step_order": 2,
-					"message":    "Second new step",
-					"input_type": "email",
-					"store_as":   "new_data_2",
-				},
+		parsedID, err := uuid.Parse(resp.Data.ID)
+		require.NoError(t, err)
+
+		var rule models.KeywordRule
+		require.NoError(t, app.DB.First(&rule, "id = ?", parsedID).Error)
+		assert.Equal(t, models.ResponseTypeTransfer, rule.ResponseType)
+		assert.Equal(t, 99, rule.Priority)
+	})
+
+	t.Run("defaults match_type and response_type when omitted", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":     "Default Types Rule",
+			"keywords": []string{"test"},
+			"response_content": map[string]any{
+				"text": "default types test",
 			},
+			"enabled": true,
 		})
-		testutil.SetAuthContext(updateReq, org.ID, user.ID)
-		testutil.SetPathParam(updateReq, "id", createResp.Data.ID)
+		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err = app.UpdateChatbotFlow(updateReq)
+		err := app.CreateKeywordRule(req)
 		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(updateReq))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		// Verify old step is gone and new steps exist
-		parsedID, err := uuid.Parse(createResp.Data.ID)
+		var resp struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
 
-		var flow models.ChatbotFlow
-		require.NoError(t, app.DB.Preload("Steps").First(&flow, "id = ?", parsedID).Error)
-		assert.Len(t, flow.Steps, 2)
+		parsedID, err := uuid.Parse(resp.Data.ID)
+		require.NoError(t, err)
 
-		// Steps should be ordered
-		stepNames := make([]string, len(flow.Steps))
-		for i, s := range flow.Steps {
-			stepNames[i] = s.StepName
+		var rule models.KeywordRule
+		require.NoError(t, app.DB.First(&rule, "id = ?", parsedID).Error)
+		assert.Equal(t, models.MatchTypeContains, rule.MatchType)
+		assert.Equal(t, models.ResponseTypeText, rule.ResponseType)
+	})
+}
+
+// =============================================================================
+// UpdateKeywordRule — additional coverage
+// =============================================================================
+
+func TestApp_KeywordRule_CaseSensitive(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+	getRule := func(id string) handlers.KeywordRuleResponse {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", id)
+		require.NoError(t, app.GetKeywordRule(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.KeywordRuleResponse `json:"data"`
 		}
-		assert.Contains(t, stepNames, "new_step_1")
-		assert.Contains(t, stepNames, "new_step_2")
-		assert.NotContains(t, stepNames, "old_step")
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		return resp.Data
+	}
+
+	// Defaults to case-insensitive
+	plain := createTestKeywordRule(t, app, org.ID, "Plain", []string{"hello"})
+	assert.False(t, getRule(plain.ID.String()).CaseSensitive)
+
+	req := testutil.NewJSONRequest(t, map[string]any{
+		"name":             "Stop",
+		"keywords":         []string{"STOP"},
+		"match_type":       "exact",
+		"case_sensitive":   true,
+		"response_type":    "text",
+		"response_content": map[string]any{"body": "You have been unsubscribed."},
+		"enabled":          true,
 	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	require.NoError(t, app.CreateKeywordRule(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &created))
+	assert.True(t, getRule(created.Data.ID).CaseSensitive)
+
+	// Updates that leave the flag out keep it
+	req = testutil.NewJSONRequest(t, map[string]any{"priority": 50})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", created.Data.ID)
+	require.NoError(t, app.UpdateKeywordRule(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+	assert.True(t, getRule(created.Data.ID).CaseSensitive)
+
+	req = testutil.NewJSONRequest(t, map[string]any{"case_sensitive": false})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", created.Data.ID)
+	require.NoError(t, app.UpdateKeywordRule(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+	assert.False(t, getRule(created.Data.ID).CaseSensitive)
+}
 
-	t.Run("update trigger keywords", func(t *testing.T) {
+func TestApp_UpdateKeywordRule_Additional(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not found", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		perms := getChatbotFlowPermissions(t, app)
-		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
-		user := testutil.CreateTestUser(t, app.DB, org.ID,
-			testutil.WithEmail(testutil.UniqueEmail("update-flow-kw")),
-			testutil.WithRoleID(&role.ID),
-		)
-		flow := createTestChatbotFlow(t, app, org.ID, "Keyword Flow")
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"trigger_keywords": []string{"newkw1", "newkw2", "newkw3"},
+			"name": "Ghost",
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", flow.ID.String())
+		testutil.SetPathParam(req, "id", uuid.New().String())
 
-		err := app.UpdateChatbotFlow(req)
+		err := app.UpdateKeywordRule(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("update match_type and response_content", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		rule := createTestKeywordRule(t, app, org.ID, "MatchUpdate", []string{"hello"})
+
+		newMatchType := models.MatchTypeExact
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"match_type": string(newMatchType),
+			"response_content": map[string]any{
+				"text": "Updated response content.",
+			},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", rule.ID.String())
+
+		err := app.UpdateKeywordRule(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		var updated models.ChatbotFlow
-		require.NoError(t, app.DB.First(&updated, "id = ?", flow.ID).Error)
-		assert.Equal(t, models.StringArray{"newkw1", "newkw2", "newkw3"}, updated.TriggerKeywords)
+		var updated models.KeywordRule
+		require.NoError(t, app.DB.First(&updated, "id = ?", rule.ID).Error)
+		assert.Equal(t, models.MatchTypeExact, updated.MatchType)
+		assert.Equal(t, "Updated response content.", updated.ResponseContent["text"])
+	})
+
+	t.Run("update response_type to transfer", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		rule := createTestKeywordRule(t, app, org.ID, "TypeSwitch", []string{"agent"})
+
+		newRespType := models.ResponseTypeTransfer
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"response_type": string(newRespType),
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", rule.ID.String())
+
+		err := app.UpdateKeywordRule(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var updated models.KeywordRule
+		require.NoError(t, app.DB.First(&updated, "id = ?", rule.ID).Error)
+		assert.Equal(t, models.ResponseTypeTransfer, updated.ResponseType)
 	})
 }
 
 // =============================================================================
-// ListChatbotFlows — cross-org isolation
+// ListKeywordRules — cross-org isolation
 // =============================================================================
 
-func TestApp_ListChatbotFlows_OrgIsolation(t *testing.T) {
+func TestApp_ListKeywordRules_OrgIsolation(t *testing.T) {
 	t.Parallel()
 
-	t.Run("flows from other org are not visible", func(t *testing.T) {
+	t.Run("rules from other org are not visible", func(t *testing.T) {
 		app := newTestApp(t)
 
 		org1 := testutil.CreateTestOrganization(t, app.DB)
-		perms := getChatbotFlowPermissions(t, app)
-		role1 := testutil.CreateTestRole(t, app.DB, org1.ID, "flow-admin", perms)
-		user1 := testutil.CreateTestUser(t, app.DB, org1.ID,
-			testutil.WithEmail(testutil.UniqueEmail("iso-flow1")),
-			testutil.WithRoleID(&role1.ID),
-		)
-		createTestChatbotFlow(t, app, org1.ID, "Org1 Flow")
+		user1 := testutil.CreateTestUser(t, app.DB, org1.ID)
+		createTestKeywordRule(t, app, org1.ID, "Org1 Rule", []string{"org1"})
 
 		org2 := testutil.CreateTestOrganization(t, app.DB)
-		role2 := testutil.CreateTestRole(t, app.DB, org2.ID, "flow-admin", perms)
 		user2 := testutil.CreateTestUser(t, app.DB, org2.ID,
-			testutil.WithEmail(testutil.UniqueEmail("iso-flow2")),
-			testutil.WithRoleID(&role2.ID),
+			testutil.WithEmail(testutil.UniqueEmail("org2-kw")),
 		)
-		createTestChatbotFlow(t, app, org2.ID, "Org2 Flow")
+		createTestKeywordRule(t, app, org2.ID, "Org2 Rule", []string{"org2"})
 
+		// User from org1 should only see org1's rules
 		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org1.ID, user1.ID)
 
-		err := app.ListChatbotFlows(req)
+		err := app.ListKeywordRules(req)
 		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
 			Data struct {
-				Flows []handlers.ChatbotFlowResponse `json:"flows"`
+				Rules []handlers.KeywordRuleResponse `json:"rules"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
-		assert.Len(t, resp.Data.Flows, 1)
-		assert.Equal(t, "Org1 Flow", resp.Data.Flows[0].Name)
+		assert.Len(t, resp.Data.Rules, 1)
+		assert.Equal(t, "Org1 Rule", resp.Data.Rules[0].Name)
 
-		// Org2 sees only its flow
+		// User from org2 should only see org2's rules
 		req2 := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req2, org2.ID, user2.ID)
 
-		err = app.ListChatbotFlows(req2)
+		err = app.ListKeywordRules(req2)
 		require.NoError(t, err)
 
 		var resp2 struct {
 			Data struct {
-				Flows []handlers.ChatbotFlowResponse `json:"flows"`
+				Rules []handlers.KeywordRuleResponse `json:"rules"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req2), &resp2)
 		require.NoError(t, err)
-		assert.Len(t, resp2.Data.Flows, 1)
-		assert.Equal(t, "Org2 Flow", resp2.Data.Flows[0].Name)
+		assert.Len(t, resp2.Data.Rules, 1)
+		assert.Equal(t, "Org2 Rule", resp2.Data.Rules[0].Name)
 	})
 }
 
 // =============================================================================
-// CreateAIContext — additional coverage
+// CreateChatbotFlow — additional coverage
 // =============================================================================
 
-func TestApp_CreateAIContext_Additional(t *testing.T) {
+func TestApp_CreateChatbotFlow_Additional(t *testing.T) {
 	t.Parallel()
 
 	t.Run("validation error missing name", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("create-flow-noname")),
+			testutil.WithRoleID(&role.ID),
+		)
 
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"context_type":     "static",
-			"trigger_keywords": []string{"help"},
-			"static_content":   "Some content",
+			"description":      "Missing name flow",
+			"trigger_keywords": []string{"test"},
+			"enabled":          true,
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.CreateAIContext(req)
+		err := app.CreateChatbotFlow(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 	})
 
-	t.Run("defaults context_type to static when omitted", func(t *testing.T) {
+	t.Run("create flow without steps", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
-
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"name":             "Defaulted Context",
-			"trigger_keywords": []string{"default"},
-			"static_content":   "Default content.",
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("create-flow-nosteps")),
+			testutil.WithRoleID(&role.ID),
+		)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":             "No Steps Flow",
+			"description":      "A flow with no steps",
+			"trigger_keywords": []string{"nostep"},
 			"enabled":          true,
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.CreateAIContext(req)
+		err := app.CreateChatbotFlow(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
@@ -2019,26 +2965,46 @@ func TestApp_CreateAIContext_Additional(t *testing.T) {
 		parsedID, err := uuid.Parse(resp.Data.ID)
 		require.NoError(t, err)
 
-		var ctx models.AIContext
-		require.NoError(t, app.DB.First(&ctx, "id = ?", parsedID).Error)
-		assert.Equal(t, models.ContextTypeStatic, ctx.ContextType)
+		var flow models.ChatbotFlow
+		require.NoError(t, app.DB.Preload("Steps").First(&flow, "id = ?", parsedID).Error)
+		assert.Equal(t, "No Steps Flow", flow.Name)
+		assert.Len(t, flow.Steps, 0)
 	})
 
-	t.Run("create API context type", func(t *testing.T) {
+	t.Run("create flow with completion config", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("create-flow-complete")),
+			testutil.WithRoleID(&role.ID),
+		)
 
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"name":             "API Context",
-			"context_type":     "api",
-			"trigger_keywords": []string{"live_data"},
-			"priority":         50,
-			"enabled":          true,
+			"name":               "Webhook Flow",
+			"trigger_keywords":   []string{"webhook"},
+			"initial_message":    "Starting form...",
+			"completion_message": "Thank you for submitting!",
+			"on_complete_action": "webhook",
+			"completion_config": map[string]any{
+				"url":    "https://example.com/webhook",
+				"method": "POST",
+			},
+			"enabled": true,
+			"steps": []map[string]any{
+				{
+					"step_name":  "ask_info",
+					"step_order": 1,
+					"message":    "Please enter your info.",
+					"input_type": "text",
+					"store_as":   "user_info",
+				},
+			},
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.CreateAIContext(req)
+		err := app.CreateChatbotFlow(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
@@ -2053,373 +3019,722 @@ func TestApp_CreateAIContext_Additional(t *testing.T) {
 		parsedID, err := uuid.Parse(resp.Data.ID)
 		require.NoError(t, err)
 
-		var ctx models.AIContext
-		require.NoError(t, app.DB.First(&ctx, "id = ?", parsedID).Error)
-		assert.Equal(t, models.ContextTypeAPI, ctx.ContextType)
-		assert.Equal(t, 50, ctx.Priority)
+		var flow models.ChatbotFlow
+		require.NoError(t, app.DB.First(&flow, "id = ?", parsedID).Error)
+		assert.Equal(t, "webhook", flow.OnCompleteAction)
+		assert.Equal(t, "Starting form...", flow.InitialMessage)
+		assert.Equal(t, "Thank you for submitting!", flow.CompletionMessage)
 	})
 }
 
 // =============================================================================
-// UpdateAIContext
+// UpdateChatbotFlow — additional coverage
 // =============================================================================
 
-func TestApp_UpdateAIContext(t *testing.T) {
+func TestApp_UpdateChatbotFlow_Additional(t *testing.T) {
 	t.Parallel()
 
-	t.Run("success", func(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
-		aiCtx := createTestAIContext(t, app, org.ID, "Original Context")
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("update-flow-nf")),
+			testutil.WithRoleID(&role.ID),
+		)
 
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"name":             "Updated Context",
-			"static_content":   "Updated content for the context.",
-			"priority":         99,
-			"trigger_keywords": []string{"updated", "new"},
-			"enabled":          false,
+			"name": "Ghost Flow",
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", aiCtx.ID.String())
-
-		err := app.UpdateAIContext(req)
-		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		testutil.SetPathParam(req, "id", uuid.New().String())
 
-		var resp struct {
-			Data struct {
-				Message string `json:"message"`
-			} `json:"data"`
-		}
-		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		err := app.UpdateChatbotFlow(req)
 		require.NoError(t, err)
-		assert.Equal(t, "AI context updated successfully", resp.Data.Message)
-
-		// Verify persisted
-		var updated models.AIContext
-		require.NoError(t, app.DB.First(&updated, "id = ?", aiCtx.ID).Error)
-		assert.Equal(t, "Updated Context", updated.Name)
-		assert.Equal(t, "Updated content for the context.", updated.StaticContent)
-		assert.Equal(t, 99, updated.Priority)
-		assert.False(t, updated.IsEnabled)
-		assert.Equal(t, models.StringArray{"updated", "new"}, updated.TriggerKeywords)
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
 	})
 
-	t.Run("not found", func(t *testing.T) {
+	t.Run("update replaces steps when provided", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("update-flow-steps")),
+			testutil.WithRoleID(&role.ID),
+		)
 
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"name": "Ghost Context",
+		// Create flow with one step
+		createReq := testutil.NewJSONRequest(t, map[string]any{
+			"name":             "Steps Flow",
+			"trigger_keywords": []string{"steps"},
+			"enabled":          true,
+			"steps": []map[string]any{
+				{
+					"step_name":  "old_step",
+					"step_order": 1,
+					"message":    "Old step",
+					"input_type": "text",
+					"store_as":   "old_data",
+				},
+			},
 		})
-		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", uuid.New().String())
+		testutil.SetAuthContext(createReq, org.ID, user.ID)
 
-		err := app.UpdateAIContext(req)
+		err := app.CreateChatbotFlow(createReq)
 		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
-	})
 
-	t.Run("partial update only changes provided fields", func(t *testing.T) {
-		app := newTestApp(t)
-		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
-		aiCtx := createTestAIContext(t, app, org.ID, "Partial Update Ctx")
+		var createResp struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(createReq), &createResp)
+		require.NoError(t, err)
 
-		// Only update the name
-		req := testutil.NewJSONRequest(t, map[string]any{
-			"name": "Renamed Context",
+		// Update: replace steps with two new ones
+		updateReq := testutil.NewJSONRequest(t, map[string]any{
+			"steps": []map[string]any{
+				{
+					"step_name":  "new_step_1",
+					"step_order": 1,
+					"message":    "First new step",
+					"input_type": "text",
+					"store_as":   "new_data_1",
+				},
+				{
+					"step_name":  "new_step_2",
+					"step_order": 2,
+					"message":    "Second new step",
+					"input_type": "email",
+					"store_as":   "new_data_2",
+				},
+			},
 		})
-		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", aiCtx.ID.String())
+		testutil.SetAuthContext(updateReq, org.ID, user.ID)
+		testutil.SetPathParam(updateReq, "id", createResp.Data.ID)
 
-		err := app.UpdateAIContext(req)
+		err = app.UpdateChatbotFlow(updateReq)
 		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(updateReq))
 
-		var updated models.AIContext
-		require.NoError(t, app.DB.First(&updated, "id = ?", aiCtx.ID).Error)
-		assert.Equal(t, "Renamed Context", updated.Name)
-		// Original fields should be preserved
-		assert.Equal(t, models.ContextTypeStatic, updated.ContextType)
-		assert.Equal(t, "Our business hours are 9-5.", updated.StaticContent)
-		assert.Equal(t, 10, updated.Priority)
-		assert.True(t, updated.IsEnabled)
+		// Verify old step is gone and new steps exist
+		parsedID, err := uuid.Parse(createResp.Data.ID)
+		require.NoError(t, err)
+
+		var flow models.ChatbotFlow
+		require.NoError(t, app.DB.Preload("Steps").First(&flow, "id = ?", parsedID).Error)
+		assert.Len(t, flow.Steps, 2)
+
+		// Steps should be ordered
+		stepNames := make([]string, len(flow.Steps))
+		for i, s := range flow.Steps {
+			stepNames[i] = s.StepName
+		}
+		assert.Contains(t, stepNames, "new_step_1")
+		assert.Contains(t, stepNames, "new_step_2")
+		assert.NotContains(t, stepNames, "old_step")
 	})
 
-	t.Run("change context_type from static to api", func(t *testing.T) {
+	t.Run("update trigger keywords", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
-		aiCtx := createTestAIContext(t, app, org.ID, "Type Change Ctx")
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("update-flow-kw")),
+			testutil.WithRoleID(&role.ID),
+		)
+		flow := createTestChatbotFlow(t, app, org.ID, "Keyword Flow")
 
 		req := testutil.NewJSONRequest(t, map[string]any{
-			"context_type": "api",
+			"trigger_keywords": []string{"newkw1", "newkw2", "newkw3"},
 		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", aiCtx.ID.String())
+		testutil.SetPathParam(req, "id", flow.ID.String())
 
-		err := app.UpdateAIContext(req)
+		err := app.UpdateChatbotFlow(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		var updated models.AIContext
-		require.NoError(t, app.DB.First(&updated, "id = ?", aiCtx.ID).Error)
-		assert.Equal(t, models.ContextTypeAPI, updated.ContextType)
+		var updated models.ChatbotFlow
+		require.NoError(t, app.DB.First(&updated, "id = ?", flow.ID).Error)
+		assert.Equal(t, models.StringArray{"newkw1", "newkw2", "newkw3"}, updated.TriggerKeywords)
 	})
 }
 
 // =============================================================================
-// ListAIContexts — cross-org isolation
+// ListChatbotFlows — cross-org isolation
 // =============================================================================
 
-func TestApp_ListAIContexts_OrgIsolation(t *testing.T) {
+func TestApp_ListChatbotFlows_OrgIsolation(t *testing.T) {
 	t.Parallel()
 
-	t.Run("contexts from other org are not visible", func(t *testing.T) {
+	t.Run("flows from other org are not visible", func(t *testing.T) {
 		app := newTestApp(t)
 
 		org1 := testutil.CreateTestOrganization(t, app.DB)
-		user1 := testutil.CreateTestUser(t, app.DB, org1.ID)
-		createTestAIContext(t, app, org1.ID, "Org1 Context")
+		perms := getChatbotFlowPermissions(t, app)
+		role1 := testutil.CreateTestRole(t, app.DB, org1.ID, "flow-admin", perms)
+		user1 := testutil.CreateTestUser(t, app.DB, org1.ID,
+			testutil.WithEmail(testutil.UniqueEmail("iso-flow1")),
+			testutil.WithRoleID(&role1.ID),
+		)
+		createTestChatbotFlow(t, app, org1.ID, "Org1 Flow")
 
 		org2 := testutil.CreateTestOrganization(t, app.DB)
+		role2 := testutil.CreateTestRole(t, app.DB, org2.ID, "flow-admin", perms)
 		user2 := testutil.CreateTestUser(t, app.DB, org2.ID,
-			testutil.WithEmail(testutil.UniqueEmail("org2-ai")),
+			testutil.WithEmail(testutil.UniqueEmail("iso-flow2")),
+			testutil.WithRoleID(&role2.ID),
 		)
-		createTestAIContext(t, app, org2.ID, "Org2 Context")
+		createTestChatbotFlow(t, app, org2.ID, "Org2 Flow")
 
 		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org1.ID, user1.ID)
 
-		err := app.ListAIContexts(req)
+		err := app.ListChatbotFlows(req)
 		require.NoError(t, err)
 
 		var resp struct {
 			Data struct {
-				Contexts []handlers.AIContextResponse `json:"contexts"`
+				Flows []handlers.ChatbotFlowResponse `json:"flows"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
-		assert.Len(t, resp.Data.Contexts, 1)
-		assert.Equal(t, "Org1 Context", resp.Data.Contexts[0].Name)
+		assert.Len(t, resp.Data.Flows, 1)
+		assert.Equal(t, "Org1 Flow", resp.Data.Flows[0].Name)
 
+		// Org2 sees only its flow
 		req2 := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req2, org2.ID, user2.ID)
 
-		err = app.ListAIContexts(req2)
+		err = app.ListChatbotFlows(req2)
 		require.NoError(t, err)
 
 		var resp2 struct {
 			Data struct {
-				Contexts []handlers.AIContextResponse `json:"contexts"`
+				Flows []handlers.ChatbotFlowResponse `json:"flows"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req2), &resp2)
 		require.NoError(t, err)
-		assert.Len(t, resp2.Data.Contexts, 1)
-		assert.Equal(t, "Org2 Context", resp2.Data.Contexts[0].Name)
+		assert.Len(t, resp2.Data.Flows, 1)
+		assert.Equal(t, "Org2 Flow", resp2.Data.Flows[0].Name)
 	})
 }
 
 // =============================================================================
-// ListChatbotSessions
+// CreateAIContext — additional coverage
 // =============================================================================
 
-// createSessionForChatbotTest creates a chatbot session directly in the DB for testing.
-func createSessionForChatbotTest(t *testing.T, app *handlers.App, orgID, contactID uuid.UUID, phone string, status models.SessionStatus) *models.ChatbotSession {
-	t.Helper()
-
-	now := time.Now()
-	session := &models.ChatbotSession{
-		BaseModel:       models.BaseModel{ID: uuid.New()},
-		OrganizationID:  orgID,
-		ContactID:       contactID,
-		PhoneNumber:     phone,
-		Status:          status,
-		SessionData:     models.JSONB{},
-		StartedAt:       now,
-		LastActivityAt:  now,
-	}
-	require.NoError(t, app.DB.Create(session).Error)
-	return session
-}
-
-func TestApp_ListChatbotSessions(t *testing.T) {
+func TestApp_CreateAIContext_Additional(t *testing.T) {
 	t.Parallel()
 
-	t.Run("success returns all sessions", func(t *testing.T) {
+	t.Run("validation error missing name", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
-		contact := testutil.CreateTestContact(t, app.DB, org.ID)
 
-		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+1234567890", models.SessionStatusActive)
-		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+1234567890", models.SessionStatusCompleted)
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"context_type":     "static",
+			"trigger_keywords": []string{"help"},
+			"static_content":   "Some content",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		req := testutil.NewGETRequest(t)
+		err := app.CreateAIContext(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("defaults context_type to static when omitted", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":             "Defaulted Context",
+			"trigger_keywords": []string{"default"},
+			"static_content":   "Default content.",
+			"enabled":          true,
+		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.ListChatbotSessions(req)
+		err := app.CreateAIContext(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
 			Data struct {
-				Sessions []models.ChatbotSession `json:"sessions"`
+				ID string `json:"id"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
-		assert.Len(t, resp.Data.Sessions, 2)
+
+		parsedID, err := uuid.Parse(resp.Data.ID)
+		require.NoError(t, err)
+
+		var ctx models.AIContext
+		require.NoError(t, app.DB.First(&ctx, "id = ?", parsedID).Error)
+		assert.Equal(t, models.ContextTypeStatic, ctx.ContextType)
 	})
 
-	t.Run("empty list", func(t *testing.T) {
+	t.Run("create API context type", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
 
-		req := testutil.NewGETRequest(t)
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":             "API Context",
+			"context_type":     "api",
+			"trigger_keywords": []string{"live_data"},
+			"priority":         50,
+			"enabled":          true,
+		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
 
-		err := app.ListChatbotSessions(req)
+		err := app.CreateAIContext(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
 			Data struct {
-				Sessions []models.ChatbotSession `json:"sessions"`
+				ID string `json:"id"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
-		assert.Len(t, resp.Data.Sessions, 0)
+
+		parsedID, err := uuid.Parse(resp.Data.ID)
+		require.NoError(t, err)
+
+		var ctx models.AIContext
+		require.NoError(t, app.DB.First(&ctx, "id = ?", parsedID).Error)
+		assert.Equal(t, models.ContextTypeAPI, ctx.ContextType)
+		assert.Equal(t, 50, ctx.Priority)
 	})
+}
 
-	t.Run("filter by status active", func(t *testing.T) {
+// =============================================================================
+// UpdateAIContext
+// =============================================================================
+
+func TestApp_UpdateAIContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
-		contact := testutil.CreateTestContact(t, app.DB, org.ID)
-
-		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+1111111111", models.SessionStatusActive)
-		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+1111111111", models.SessionStatusCompleted)
-		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+1111111111", models.SessionStatusActive)
+		aiCtx := createTestAIContext(t, app, org.ID, "Original Context")
 
-		req := testutil.NewGETRequest(t)
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":             "Updated Context",
+			"static_content":   "Updated content for the context.",
+			"priority":         99,
+			"trigger_keywords": []string{"updated", "new"},
+			"enabled":          false,
+		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetQueryParam(req, "status", "active")
+		testutil.SetPathParam(req, "id", aiCtx.ID.String())
 
-		err := app.ListChatbotSessions(req)
+		err := app.UpdateAIContext(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
 			Data struct {
-				Sessions []models.ChatbotSession `json:"sessions"`
+				Message string `json:"message"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
-		assert.Len(t, resp.Data.Sessions, 2)
-		for _, s := range resp.Data.Sessions {
-			assert.Equal(t, models.SessionStatusActive, s.Status)
-		}
+		assert.Equal(t, "AI context updated successfully", resp.Data.Message)
+
+		// Verify persisted
+		var updated models.AIContext
+		require.NoError(t, app.DB.First(&updated, "id = ?", aiCtx.ID).Error)
+		assert.Equal(t, "Updated Context", updated.Name)
+		assert.Equal(t, "Updated content for the context.", updated.StaticContent)
+		assert.Equal(t, 99, updated.Priority)
+		assert.False(t, updated.IsEnabled)
+		assert.Equal(t, models.StringArray{"updated", "new"}, updated.TriggerKeywords)
 	})
 
-	t.Run("filter by status completed", func(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		user := testutil.CreateTestUser(t, app.DB, org.ID)
-		contact := testutil.CreateTestContact(t, app.DB, org.ID)
 
-		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+2222222222", models.SessionStatusActive)
-		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+2222222222", models.SessionStatusCompleted)
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name": "Ghost Context",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", uuid.New().String())
 
-		req := testutil.NewGETRequest(t)
+		err := app.UpdateAIContext(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("partial update only changes provided fields", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		aiCtx := createTestAIContext(t, app, org.ID, "Partial Update Ctx")
+
+		// Only update the name
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name": "Renamed Context",
+		})
 		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetQueryParam(req, "status", "completed")
+		testutil.SetPathParam(req, "id", aiCtx.ID.String())
 
-		err := app.ListChatbotSessions(req)
+		err := app.UpdateAIContext(req)
 		require.NoError(t, err)
 		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-		var resp struct {
-			Data struct {
-				Sessions []models.ChatbotSession `json:"sessions"`
-			} `json:"data"`
-		}
-		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		var updated models.AIContext
+		require.NoError(t, app.DB.First(&updated, "id = ?", aiCtx.ID).Error)
+		assert.Equal(t, "Renamed Context", updated.Name)
+		// Original fields should be preserved
+		assert.Equal(t, models.ContextTypeStatic, updated.ContextType)
+		assert.Equal(t, "Our business hours are 9-5.", updated.StaticContent)
+		assert.Equal(t, 10, updated.Priority)
+		assert.True(t, updated.IsEnabled)
+	})
+
+	t.Run("change context_type from static to api", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		aiCtx := createTestAIContext(t, app, org.ID, "Type Change Ctx")
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"context_type": "api",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", aiCtx.ID.String())
+
+		err := app.UpdateAIContext(req)
 		require.NoError(t, err)
-		assert.Len(t, resp.Data.Sessions, 1)
-		assert.Equal(t, models.SessionStatusCompleted, resp.Data.Sessions[0].Status)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var updated models.AIContext
+		require.NoError(t, app.DB.First(&updated, "id = ?", aiCtx.ID).Error)
+		assert.Equal(t, models.ContextTypeAPI, updated.ContextType)
 	})
+}
 
-	t.Run("cross-org isolation", func(t *testing.T) {
+// =============================================================================
+// ListAIContexts — cross-org isolation
+// =============================================================================
+
+func TestApp_ListAIContexts_OrgIsolation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("contexts from other org are not visible", func(t *testing.T) {
 		app := newTestApp(t)
 
 		org1 := testutil.CreateTestOrganization(t, app.DB)
 		user1 := testutil.CreateTestUser(t, app.DB, org1.ID)
-		contact1 := testutil.CreateTestContact(t, app.DB, org1.ID)
-		createSessionForChatbotTest(t, app, org1.ID, contact1.ID, "+3333333333", models.SessionStatusActive)
+		createTestAIContext(t, app, org1.ID, "Org1 Context")
 
 		org2 := testutil.CreateTestOrganization(t, app.DB)
 		user2 := testutil.CreateTestUser(t, app.DB, org2.ID,
-			testutil.WithEmail(testutil.UniqueEmail("org2-sess")),
+			testutil.WithEmail(testutil.UniqueEmail("org2-ai")),
 		)
-		contact2 := testutil.CreateTestContact(t, app.DB, org2.ID)
-		createSessionForChatbotTest(t, app, org2.ID, contact2.ID, "+4444444444", models.SessionStatusActive)
+		createTestAIContext(t, app, org2.ID, "Org2 Context")
 
 		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org1.ID, user1.ID)
 
-		err := app.ListChatbotSessions(req)
+		err := app.ListAIContexts(req)
 		require.NoError(t, err)
 
 		var resp struct {
 			Data struct {
-				Sessions []models.ChatbotSession `json:"sessions"`
+				Contexts []handlers.AIContextResponse `json:"contexts"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
 		require.NoError(t, err)
-		assert.Len(t, resp.Data.Sessions, 1)
+		assert.Len(t, resp.Data.Contexts, 1)
+		assert.Equal(t, "Org1 Context", resp.Data.Contexts[0].Name)
 
 		req2 := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req2, org2.ID, user2.ID)
 
-		err = app.ListChatbotSessions(req2)
+		err = app.ListAIContexts(req2)
 		require.NoError(t, err)
 
 		var resp2 struct {
 			Data struct {
-				Sessions []models.ChatbotSession `json:"sessions"`
+				Contexts []handlers.AIContextResponse `json:"contexts"`
 			} `json:"data"`
 		}
 		err = json.Unmarshal(testutil.GetResponseBody(req2), &resp2)
 		require.NoError(t, err)
-		assert.Len(t, resp2.Data.Sessions, 1)
+		assert.Len(t, resp2.Data.Contexts, 1)
+		assert.Equal(t, "Org2 Context", resp2.Data.Contexts[0].Name)
 	})
 }
 
 // =============================================================================
-// GetChatbotSession
+// ListChatbotSessions
 // =============================================================================
 
-func TestApp_GetChatbotSession(t *testing.T) {
-	t.Parallel()
-
-	t.Run("success", func(t *testing.T) {
-		app := newTestApp(t)
-		org := testutil.CreateTestOrganization(t, app.DB)
-		user := testutil.CreateTestUser(t, app.DB, org.ID)
-		contact := testutil.CreateTestContact(t, app.DB, org.ID)
-		session := createSessionForChatbotTest(t, app, org.ID, contact.ID, "+5555555555", models.SessionStatusActive)
+// createSessionForChatbotTest creates a chatbot session directly in the DB for testing.
+func createSessionForChatbotTest(t *testing.T, app *handlers.App, orgID, contactID uuid.UUID, phone string, status models.SessionStatus) *models.ChatbotSession {
+	t.Helper()
 
-		req := testutil.NewGETRequest(t)
+	now := time.Now()
+	session := &models.ChatbotSession{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  orgID,
+		ContactID:       contactID,
+		PhoneNumber:     phone,
+		Status:          status,
+		SessionData:     models.JSONB{},
+		StartedAt:       now,
+		LastActivityAt:  now,
+	}
+	require.NoError(t, app.DB.Create(session).Error)
+	return session
+}
+
+func TestApp_ListChatbotSessions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success returns all sessions", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+1234567890", models.SessionStatusActive)
+		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+1234567890", models.SessionStatusCompleted)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.ListChatbotSessions(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Sessions []models.ChatbotSession `json:"sessions"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		require.NoError(t, err)
+		assert.Len(t, resp.Data.Sessions, 2)
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.ListChatbotSessions(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Sessions []models.ChatbotSession `json:"sessions"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		require.NoError(t, err)
+		assert.Len(t, resp.Data.Sessions, 0)
+	})
+
+	t.Run("filter by status active", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+1111111111", models.SessionStatusActive)
+		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+1111111111", models.SessionStatusCompleted)
+		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+1111111111", models.SessionStatusActive)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "status", "active")
+
+		err := app.ListChatbotSessions(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Sessions []models.ChatbotSession `json:"sessions"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		require.NoError(t, err)
+		assert.Len(t, resp.Data.Sessions, 2)
+		for _, s := range resp.Data.Sessions {
+			assert.Equal(t, models.SessionStatusActive, s.Status)
+		}
+	})
+
+	t.Run("filter by status completed", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+2222222222", models.SessionStatusActive)
+		createSessionForChatbotTest(t, app, org.ID, contact.ID, "+2222222222", models.SessionStatusCompleted)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "status", "completed")
+
+		err := app.ListChatbotSessions(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Sessions []models.ChatbotSession `json:"sessions"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		require.NoError(t, err)
+		assert.Len(t, resp.Data.Sessions, 1)
+		assert.Equal(t, models.SessionStatusCompleted, resp.Data.Sessions[0].Status)
+	})
+
+	t.Run("cross-org isolation", func(t *testing.T) {
+		app := newTestApp(t)
+
+		org1 := testutil.CreateTestOrganization(t, app.DB)
+		user1 := testutil.CreateTestUser(t, app.DB, org1.ID)
+		contact1 := testutil.CreateTestContact(t, app.DB, org1.ID)
+		createSessionForChatbotTest(t, app, org1.ID, contact1.ID, "+3333333333", models.SessionStatusActive)
+
+		org2 := testutil.CreateTestOrganization(t, app.DB)
+		user2 := testutil.CreateTestUser(t, app.DB, org2.ID,
+			testutil.WithEmail(testutil.UniqueEmail("org2-sess")),
+		)
+		contact2 := testutil.CreateTestContact(t, app.DB, org2.ID)
+		createSessionForChatbotTest(t, app, org2.ID, contact2.ID, "+4444444444", models.SessionStatusActive)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org1.ID, user1.ID)
+
+		err := app.ListChatbotSessions(req)
+		require.NoError(t, err)
+
+		var resp struct {
+			Data struct {
+				Sessions []models.ChatbotSession `json:"sessions"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		require.NoError(t, err)
+		assert.Len(t, resp.Data.Sessions, 1)
+
+		req2 := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req2, org2.ID, user2.ID)
+
+		err = app.ListChatbotSessions(req2)
+		require.NoError(t, err)
+
+		var resp2 struct {
+			Data struct {
+				Sessions []models.ChatbotSession `json:"sessions"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(req2), &resp2)
+		require.NoError(t, err)
+		assert.Len(t, resp2.Data.Sessions, 1)
+	})
+}
+
+func TestApp_RunSessionMaintenance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("closes idle sessions for the organization", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			OrganizationID: org.ID,
+			ClientInactivity: models.ClientInactivityConfig{
+				ReminderEnabled:  true,
+				AutoCloseMinutes: 30,
+			},
+		}).Error)
+
+		idle := createSessionForChatbotTest(t, app, org.ID, contact.ID, contact.PhoneNumber, models.SessionStatusActive)
+		require.NoError(t, app.DB.Model(idle).Update("last_activity_at", time.Now().Add(-time.Hour)).Error)
+		fresh := createSessionForChatbotTest(t, app, org.ID, contact.ID, contact.PhoneNumber, models.SessionStatusActive)
+
+		req := testutil.NewJSONRequest(t, map[string]any{})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.RunSessionMaintenance(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.SessionMaintenanceResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, 1, resp.Data.SessionsClosed)
+		assert.Equal(t, 0, resp.Data.MessagesSent)
+
+		var closed, active models.ChatbotSession
+		require.NoError(t, app.DB.First(&closed, idle.ID).Error)
+		require.NoError(t, app.DB.First(&active, fresh.ID).Error)
+		assert.Equal(t, models.SessionStatusTimeout, closed.Status)
+		assert.Equal(t, models.SessionStatusActive, active.Status)
+	})
+
+	t.Run("forbidden without chatbot settings permission", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+
+		req := testutil.NewJSONRequest(t, map[string]any{})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.RunSessionMaintenance(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
+
+// =============================================================================
+// GetChatbotSession
+// =============================================================================
+
+func TestApp_GetChatbotSession(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+		session := createSessionForChatbotTest(t, app, org.ID, contact.ID, "+5555555555", models.SessionStatusActive)
+
+		req := testutil.NewGETRequest(t)
 		testutil.SetAuthContext(req, org.ID, user.ID)
 		testutil.SetPathParam(req, "id", session.ID.String())
 
@@ -2756,3 +4071,845 @@ func TestApp_GetKeywordRule_ResponseFields(t *testing.T) {
 		assert.NotEmpty(t, resp.Data.CreatedAt)
 	})
 }
+
+// =============================================================================
+// AI API key storage and rotation
+// =============================================================================
+
+const testEncryptionKey = "test-encryption-key-for-ai-keys"
+
+// getStoredAIKey reads the raw (encrypted) AI key from the org-level settings row.
+func getStoredAIKey(t *testing.T, app *handlers.App, orgID uuid.UUID) string {
+	t.Helper()
+
+	var settings models.ChatbotSettings
+	require.NoError(t, app.DB.Where("organization_id = ? AND whats_app_account = ?", orgID, "").First(&settings).Error)
+	return settings.AI.APIKey
+}
+
+func TestApp_UpdateChatbotSettings_AIKeyEncrypted(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	app.Config.App.EncryptionKey = testEncryptionKey
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+	const apiKey = "sk-org-secret-key-9876wxyz"
+	req := testutil.NewJSONRequest(t, map[string]any{
+		"ai_enabled":  true,
+		"ai_provider": "openai",
+		"ai_api_key":  apiKey,
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	require.NoError(t, app.UpdateChatbotSettings(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	stored := getStoredAIKey(t, app, org.ID)
+	assert.True(t, crypto.IsEncrypted(stored), "key should be encrypted at rest")
+	decrypted, err := crypto.Decrypt(stored, testEncryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, apiKey, decrypted)
+
+	getReq := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(getReq, org.ID, user.ID)
+	require.NoError(t, app.GetChatbotSettings(getReq))
+
+	body := testutil.GetResponseBody(getReq)
+	assert.NotContains(t, string(body), apiKey)
+	assert.NotContains(t, string(body), stored)
+
+	var resp struct {
+		Data struct {
+			Settings handlers.ChatbotSettingsResponse `json:"settings"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.True(t, resp.Data.Settings.AIHasKey)
+	assert.Equal(t, "****wxyz", resp.Data.Settings.AIAPIKeyMasked)
+	assert.NotNil(t, resp.Data.Settings.AIAPIKeyUpdatedAt)
+}
+
+func TestApp_RotateAIKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success replaces key", func(t *testing.T) {
+		app := newTestApp(t)
+		app.Config.App.EncryptionKey = testEncryptionKey
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("rotate-ai-key")),
+			testutil.WithRoleID(&role.ID),
+		)
+
+		updateReq := testutil.NewJSONRequest(t, map[string]any{"ai_api_key": "sk-old-key-00001111"})
+		testutil.SetAuthContext(updateReq, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(updateReq))
+		oldStored := getStoredAIKey(t, app, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"api_key": "sk-new-key-22223333"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.RotateAIKey(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				HasKey bool   `json:"ai_has_key"`
+				Masked string `json:"ai_api_key_masked"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.True(t, resp.Data.HasKey)
+		assert.Equal(t, "****3333", resp.Data.Masked)
+		assert.NotContains(t, string(testutil.GetResponseBody(req)), "sk-new-key-22223333")
+
+		stored := getStoredAIKey(t, app, org.ID)
+		assert.NotEqual(t, oldStored, stored)
+		decrypted, err := crypto.Decrypt(stored, testEncryptionKey)
+		require.NoError(t, err)
+		assert.Equal(t, "sk-new-key-22223333", decrypted)
+	})
+
+	t.Run("empty key rejected", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("rotate-ai-empty")),
+			testutil.WithRoleID(&role.ID),
+		)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"api_key": "  "})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.RotateAIKey(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("settings not found", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("rotate-ai-nf")),
+			testutil.WithRoleID(&role.ID),
+		)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"api_key": "sk-new"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.RotateAIKey(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("forbidden without settings permission", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAgentRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("rotate-ai-agent")),
+			testutil.WithRoleID(&role.ID),
+		)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"api_key": "sk-new"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.RotateAIKey(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_UpdateChatbotSettings_AnthropicProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success round-trips anthropic provider", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"ai_enabled":       true,
+			"ai_provider":      "anthropic",
+			"ai_model":         "claude-3-5-sonnet-latest",
+			"ai_max_tokens":    800,
+			"ai_system_prompt": "You are a helpful assistant.",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.UpdateChatbotSettings(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+
+		err = app.GetChatbotSettings(getReq)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(getReq))
+
+		var getResp struct {
+			Data struct {
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+			} `json:"data"`
+		}
+		err = json.Unmarshal(testutil.GetResponseBody(getReq), &getResp)
+		require.NoError(t, err)
+
+		assert.True(t, getResp.Data.Settings.AIEnabled)
+		assert.Equal(t, models.AIProviderAnthropic, getResp.Data.Settings.AIProvider)
+		assert.Equal(t, "claude-3-5-sonnet-latest", getResp.Data.Settings.AIModel)
+		assert.Equal(t, 800, getResp.Data.Settings.AIMaxTokens)
+	})
+
+	t.Run("newly released model accepted", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"ai_provider": "anthropic",
+			"ai_model":    "claude-future-9-0",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.UpdateChatbotSettings(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("blank model rejected", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"ai_provider": "anthropic",
+			"ai_model":    "  ",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.UpdateChatbotSettings(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("unknown provider rejected", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"ai_provider": "acme-ai",
+			"ai_model":    "acme-1",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.UpdateChatbotSettings(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+}
+
+// =============================================================================
+// SimulateChatbotFlow
+// =============================================================================
+
+// createSimulationFlow builds a two-step flow that asks for a name and a
+// validated email address, then thanks the user by name.
+func createSimulationFlow(t *testing.T, app *handlers.App, orgID uuid.UUID) *models.ChatbotFlow {
+	t.Helper()
+
+	flow := createTestChatbotFlow(t, app, orgID, "Signup")
+	require.NoError(t, app.DB.Model(flow).Updates(map[string]any{
+		"initial_message":    "Welcome!",
+		"completion_message": "Thanks {{name_value}}",
+	}).Error)
+
+	createTestFlowStep(t, app, flow.ID, "name", 1)
+	email := createTestFlowStep(t, app, flow.ID, "email", 2)
+	require.NoError(t, app.DB.Model(email).Updates(map[string]any{
+		"message":          "Hi {{name_value}}, what is your email?",
+		"validation_regex": `^[^@\s]+@[^@\s]+$`,
+		"validation_error": "Please enter a valid email",
+		"retry_on_invalid": true,
+	}).Error)
+
+	return flow
+}
+
+func TestApp_SimulateChatbotFlow(t *testing.T) {
+	t.Parallel()
+
+	type simulationResponse struct {
+		Data handlers.FlowSimulationResponse `json:"data"`
+	}
+
+	t.Run("walks steps and surfaces validation errors", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("simulate-flow")),
+			testutil.WithRoleID(&role.ID),
+		)
+		flow := createSimulationFlow(t, app, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"inputs": []string{"Alice", "not-an-email", "alice@example.com"},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+
+		require.NoError(t, app.SimulateChatbotFlow(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp simulationResponse
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+
+		texts := make([]string, 0, len(resp.Data.Messages))
+		for _, m := range resp.Data.Messages {
+			texts = append(texts, m.Text)
+		}
+		assert.Equal(t, []string{
+			"Welcome!",
+			"Message for name",
+			"Hi Alice, what is your email?",
+			"Please enter a valid email",
+			"Thanks Alice",
+		}, texts)
+		assert.Equal(t, "validation_error", resp.Data.Messages[3].Type)
+		assert.Equal(t, "email", resp.Data.Messages[3].Step)
+
+		assert.Equal(t, "completed", resp.Data.Status)
+		assert.Empty(t, resp.Data.CurrentStep)
+		assert.Equal(t, 3, resp.Data.InputsConsumed)
+		assert.Equal(t, "Alice", resp.Data.SessionData["name_value"])
+		assert.Equal(t, "alice@example.com", resp.Data.SessionData["email_value"])
+		assert.Equal(t, "Signup", resp.Data.SessionData["_flow_name"])
+
+		var sessions int64
+		require.NoError(t, app.DB.Model(&models.ChatbotSession{}).Where("organization_id = ?", org.ID).Count(&sessions).Error)
+		assert.Zero(t, sessions)
+	})
+
+	t.Run("stops at pending step when inputs run out", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("simulate-flow-partial")),
+			testutil.WithRoleID(&role.ID),
+		)
+		flow := createSimulationFlow(t, app, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"inputs": []string{"Bob"}})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+
+		require.NoError(t, app.SimulateChatbotFlow(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp simulationResponse
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, "active", resp.Data.Status)
+		assert.Equal(t, "email", resp.Data.CurrentStep)
+		assert.Equal(t, "Bob", resp.Data.SessionData["name_value"])
+		assert.NotContains(t, resp.Data.SessionData, "email_value")
+	})
+
+	t.Run("follows conditional next for button replies", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("simulate-flow-branch")),
+			testutil.WithRoleID(&role.ID),
+		)
+		flow := createTestChatbotFlow(t, app, org.ID, "Branching")
+		menu := createTestFlowStep(t, app, flow.ID, "menu", 1)
+		require.NoError(t, app.DB.Model(menu).Updates(map[string]any{
+			"message_type": models.FlowStepTypeButtons,
+			"input_type":   models.InputTypeButton,
+			"buttons": models.JSONBArray{
+				map[string]any{"id": "sales", "title": "Sales"},
+				map[string]any{"id": "support", "title": "Support"},
+			},
+			"conditional_next": models.JSONB{"support": "support"},
+		}).Error)
+		createTestFlowStep(t, app, flow.ID, "sales", 2)
+		createTestFlowStep(t, app, flow.ID, "support", 3)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"inputs": []string{"Support"}})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+
+		require.NoError(t, app.SimulateChatbotFlow(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp simulationResponse
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		require.Len(t, resp.Data.Messages, 2)
+		assert.Equal(t, []string{"Sales", "Support"}, resp.Data.Messages[0].Buttons)
+		assert.Equal(t, "support", resp.Data.Messages[1].Step)
+		assert.Equal(t, "support", resp.Data.CurrentStep)
+		assert.Equal(t, "support", resp.Data.SessionData["menu_value"])
+		assert.Equal(t, "Support", resp.Data.SessionData["menu_value_title"])
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		perms := getChatbotFlowPermissions(t, app)
+		role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", perms)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("simulate-flow-nf")),
+			testutil.WithRoleID(&role.ID),
+		)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"inputs": []string{}})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", uuid.New().String())
+
+		require.NoError(t, app.SimulateChatbotFlow(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("forbidden without flow permission", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAgentRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("simulate-flow-agent")),
+			testutil.WithRoleID(&role.ID),
+		)
+		flow := createTestChatbotFlow(t, app, org.ID, "Hidden")
+
+		req := testutil.NewJSONRequest(t, map[string]any{"inputs": []string{"hi"}})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+
+		require.NoError(t, app.SimulateChatbotFlow(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_UpdateChatbotSettings_AssignmentFallback(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user fallback round-trips", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		supervisor := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("fallback-supervisor")))
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"assignment_fallback_type":    "user",
+			"assignment_fallback_user_id": supervisor.ID.String(),
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+		require.NoError(t, app.GetChatbotSettings(getReq))
+
+		var getResp struct {
+			Data struct {
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(getReq), &getResp))
+		assert.Equal(t, "user", getResp.Data.Settings.AssignmentFallbackType)
+		require.NotNil(t, getResp.Data.Settings.AssignmentFallbackUserID)
+		assert.Equal(t, supervisor.ID, *getResp.Data.Settings.AssignmentFallbackUserID)
+	})
+
+	t.Run("user from another org rejected", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		outsider := testutil.CreateTestUser(t, app.DB, otherOrg.ID, testutil.WithEmail(testutil.UniqueEmail("fallback-outsider")))
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"assignment_fallback_type":    "user",
+			"assignment_fallback_user_id": outsider.ID.String(),
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("team fallback requires team id", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"assignment_fallback_type": "team"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("unknown fallback type rejected", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"assignment_fallback_type": "nobody"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_UpdateChatbotSettings_SessionResumeGrace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round-trips", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"session_resume_grace_minutes": 15})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+		require.NoError(t, app.GetChatbotSettings(getReq))
+
+		var getResp struct {
+			Data struct {
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(getReq), &getResp))
+		assert.Equal(t, 15, getResp.Data.Settings.SessionResumeGraceMinutes)
+	})
+
+	t.Run("out of range rejected", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		for _, minutes := range []int{-1, 1441} {
+			req := testutil.NewJSONRequest(t, map[string]any{"session_resume_grace_minutes": minutes})
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			require.NoError(t, app.UpdateChatbotSettings(req))
+			assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req), "minutes: %d", minutes)
+		}
+	})
+}
+
+func TestApp_UpdateChatbotSettings_InboundRateLimit(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+	req := testutil.NewJSONRequest(t, map[string]any{
+		"inbound_rate_limit_per_minute":   20,
+		"inbound_rate_limit_flag_contact": true,
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	require.NoError(t, app.UpdateChatbotSettings(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	getReq := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(getReq, org.ID, user.ID)
+	require.NoError(t, app.GetChatbotSettings(getReq))
+
+	var getResp struct {
+		Data struct {
+			Settings handlers.ChatbotSettingsResponse `json:"settings"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(getReq), &getResp))
+	assert.Equal(t, 20, getResp.Data.Settings.InboundRateLimitPerMinute)
+	assert.True(t, getResp.Data.Settings.InboundRateLimitFlag)
+
+	for _, limit := range []int{-1, 1001} {
+		req := testutil.NewJSONRequest(t, map[string]any{"inbound_rate_limit_per_minute": limit})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req), "limit: %d", limit)
+	}
+}
+
+func TestApp_UpdateChatbotSettings_AssignmentStrategy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("strategy round-trips", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"assignment_strategy": "least_busy"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, user.ID)
+		require.NoError(t, app.GetChatbotSettings(getReq))
+
+		var getResp struct {
+			Data struct {
+				Settings handlers.ChatbotSettingsResponse `json:"settings"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(getReq), &getResp))
+		assert.Equal(t, "least_busy", getResp.Data.Settings.AssignmentStrategy)
+	})
+
+	t.Run("unknown strategy rejected", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"assignment_strategy": "load_balanced"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+}
+
+// =============================================================================
+// ListFlowWebhookDeliveries
+// =============================================================================
+
+func TestApp_ListFlowWebhookDeliveries(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	flow := createTestChatbotFlow(t, app, org.ID, "Webhook Flow")
+	role := testutil.CreateTestRole(t, app.DB, org.ID, "flow-admin", getChatbotFlowPermissions(t, app))
+	user := testutil.CreateTestUser(t, app.DB, org.ID,
+		testutil.WithEmail(testutil.UniqueEmail("flow-deliveries")),
+		testutil.WithRoleID(&role.ID),
+	)
+
+	for _, status := range []models.WebhookDeliveryStatus{models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed} {
+		require.NoError(t, app.DB.Create(&models.FlowWebhookDelivery{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			OrganizationID: org.ID,
+			FlowID:         flow.ID,
+			SessionID:      uuid.New(),
+			URL:            "https://example.com/hook",
+			Status:         status,
+			Attempts:       1,
+		}).Error)
+	}
+
+	list := func(t *testing.T, orgID, userID uuid.UUID, status string) (int, []handlers.FlowWebhookDeliveryResponse) {
+		t.Helper()
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, orgID, userID)
+		testutil.SetPathParam(req, "id", flow.ID.String())
+		if status != "" {
+			testutil.SetQueryParam(req, "status", status)
+		}
+		require.NoError(t, app.ListFlowWebhookDeliveries(req))
+
+		var resp struct {
+			Data struct {
+				Deliveries []handlers.FlowWebhookDeliveryResponse `json:"deliveries"`
+				Total      int64                                  `json:"total"`
+			} `json:"data"`
+		}
+		_ = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		return testutil.GetResponseStatusCode(req), resp.Data.Deliveries
+	}
+
+	t.Run("lists deliveries", func(t *testing.T) {
+		status, deliveries := list(t, org.ID, user.ID, "")
+		assert.Equal(t, fasthttp.StatusOK, status)
+		assert.Len(t, deliveries, 2)
+	})
+
+	t.Run("filters by status", func(t *testing.T) {
+		status, deliveries := list(t, org.ID, user.ID, "failed")
+		assert.Equal(t, fasthttp.StatusOK, status)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, models.WebhookDeliveryFailed, deliveries[0].Status)
+	})
+
+	t.Run("flow from another org is not found", func(t *testing.T) {
+		org2 := testutil.CreateTestOrganization(t, app.DB)
+		role2 := testutil.CreateTestRole(t, app.DB, org2.ID, "flow-admin", getChatbotFlowPermissions(t, app))
+		user2 := testutil.CreateTestUser(t, app.DB, org2.ID,
+			testutil.WithEmail(testutil.UniqueEmail("flow-deliveries-other")),
+			testutil.WithRoleID(&role2.ID),
+		)
+		status, _ := list(t, org2.ID, user2.ID, "")
+		assert.Equal(t, fasthttp.StatusNotFound, status)
+	})
+}
+
+func TestApp_ChatbotAccountSettings(t *testing.T) {
+	t.Parallel()
+
+	type accountSettingsResponse struct {
+		Data handlers.ChatbotAccountSettingsResponse `json:"data"`
+	}
+
+	setup := func(t *testing.T, email string) (*handlers.App, uuid.UUID, uuid.UUID, *models.WhatsAppAccount) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail(email)),
+			testutil.WithRoleID(&role.ID),
+		)
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		orgReq := testutil.NewJSONRequest(t, map[string]any{
+			"greeting_message": "Org greeting",
+			"fallback_message": "Org fallback",
+		})
+		testutil.SetAuthContext(orgReq, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(orgReq))
+		return app, org.ID, user.ID, account
+	}
+
+	update := func(t *testing.T, app *handlers.App, orgID, userID uuid.UUID, account string, body map[string]any) (int, accountSettingsResponse) {
+		req := testutil.NewJSONRequest(t, body)
+		testutil.SetAuthContext(req, orgID, userID)
+		testutil.SetPathParam(req, "account", account)
+		require.NoError(t, app.UpdateChatbotAccountSettings(req))
+		var resp accountSettingsResponse
+		if testutil.GetResponseStatusCode(req) == fasthttp.StatusOK {
+			require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		}
+		return testutil.GetResponseStatusCode(req), resp
+	}
+
+	get := func(t *testing.T, app *handlers.App, orgID, userID uuid.UUID, account string) accountSettingsResponse {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, orgID, userID)
+		testutil.SetPathParam(req, "account", account)
+		require.NoError(t, app.GetChatbotAccountSettings(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		var resp accountSettingsResponse
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		return resp
+	}
+
+	accountRow := func(t *testing.T, app *handlers.App, orgID uuid.UUID, account string) models.ChatbotSettings {
+		var settings models.ChatbotSettings
+		require.NoError(t, app.DB.Where("organization_id = ? AND whats_app_account = ?", orgID, account).First(&settings).Error)
+		return settings
+	}
+
+	t.Run("no account settings falls back to org defaults", func(t *testing.T) {
+		app, orgID, userID, account := setup(t, "acct-settings-get")
+
+		resp := get(t, app, orgID, userID, account.Name)
+		assert.Equal(t, account.Name, resp.Data.WhatsAppAccount)
+		assert.True(t, resp.Data.Inherited)
+		assert.Equal(t, "Org greeting", resp.Data.Settings.GreetingMessage)
+		assert.Equal(t, "Org fallback", resp.Data.Settings.FallbackMessage)
+	})
+
+	t.Run("first update copies the org settings", func(t *testing.T) {
+		app, orgID, userID, account := setup(t, "acct-settings-set")
+		require.NoError(t, app.DB.Model(&models.ChatbotSettings{}).
+			Where("organization_id = ? AND whats_app_account = ?", orgID, "").
+			Update("allow_automated_outside_hours", false).Error)
+
+		status, resp := update(t, app, orgID, userID, account.Name, map[string]any{
+			"greeting_message":        "Welcome to support",
+			"session_timeout_minutes": 5,
+		})
+		require.Equal(t, fasthttp.StatusOK, status)
+		assert.False(t, resp.Data.Inherited)
+		assert.Equal(t, "Welcome to support", resp.Data.Settings.GreetingMessage)
+		assert.Equal(t, 5, resp.Data.Settings.SessionTimeoutMinutes)
+		assert.Equal(t, "Org fallback", resp.Data.Settings.FallbackMessage)
+
+		row := accountRow(t, app, orgID, account.Name)
+		assert.Equal(t, "Org fallback", row.FallbackMessage)
+		assert.False(t, row.BusinessHours.AllowAutomatedOutside, "copied false values keep their value")
+
+		var org models.ChatbotSettings
+		require.NoError(t, app.DB.Where("organization_id = ? AND whats_app_account = ?", orgID, "").First(&org).Error)
+		assert.Equal(t, "Org greeting", org.DefaultResponse, "the org settings are unchanged")
+
+		// Later updates keep the fields they don't send
+		status, resp = update(t, app, orgID, userID, account.Name, map[string]any{"fallback_message": "Try again"})
+		require.Equal(t, fasthttp.StatusOK, status)
+		assert.Equal(t, "Welcome to support", resp.Data.Settings.GreetingMessage)
+		assert.Equal(t, "Try again", resp.Data.Settings.FallbackMessage)
+
+		var count int64
+		app.DB.Model(&models.ChatbotSettings{}).Where("organization_id = ? AND whats_app_account = ?", orgID, account.Name).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("existing account settings row is updated", func(t *testing.T) {
+		app, orgID, userID, account := setup(t, "acct-settings-row")
+		require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+			BaseModel:          models.BaseModel{ID: uuid.New()},
+			OrganizationID:     orgID,
+			WhatsAppAccount:    account.Name,
+			IsEnabled:          true,
+			DefaultResponse:    "Account greeting",
+			FallbackMessage:    "Account fallback",
+			SessionTimeoutMins: 45,
+		}).Error)
+
+		assert.False(t, get(t, app, orgID, userID, account.Name).Data.Inherited)
+
+		status, resp := update(t, app, orgID, userID, account.Name, map[string]any{"fallback_message": "New fallback"})
+		require.Equal(t, fasthttp.StatusOK, status)
+		assert.Equal(t, "Account greeting", resp.Data.Settings.GreetingMessage)
+		assert.Equal(t, "New fallback", resp.Data.Settings.FallbackMessage)
+		assert.Equal(t, 45, resp.Data.Settings.SessionTimeoutMinutes)
+	})
+
+	t.Run("delete returns the account to the org settings", func(t *testing.T) {
+		app, orgID, userID, account := setup(t, "acct-settings-delete")
+		status, _ := update(t, app, orgID, userID, account.Name, map[string]any{"greeting_message": "Welcome to support"})
+		require.Equal(t, fasthttp.StatusOK, status)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, orgID, userID)
+		testutil.SetPathParam(req, "account", account.Name)
+		require.NoError(t, app.DeleteChatbotAccountSettings(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		resp := get(t, app, orgID, userID, account.Name)
+		assert.True(t, resp.Data.Inherited)
+		assert.Equal(t, "Org greeting", resp.Data.Settings.GreetingMessage)
+	})
+
+	t.Run("negative session timeout rejected", func(t *testing.T) {
+		app, orgID, userID, account := setup(t, "acct-settings-timeout")
+		status, _ := update(t, app, orgID, userID, account.Name, map[string]any{"session_timeout_minutes": -1})
+		assert.Equal(t, fasthttp.StatusBadRequest, status)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		app, orgID, userID, _ := setup(t, "acct-settings-unknown")
+		status, _ := update(t, app, orgID, userID, "no-such-account", map[string]any{"greeting_message": "Hi"})
+		assert.Equal(t, fasthttp.StatusNotFound, status)
+	})
+
+	t.Run("forbidden without settings permission", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAgentRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("acct-settings-agent")),
+			testutil.WithRoleID(&role.ID),
+		)
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		status, _ := update(t, app, org.ID, user.ID, account.Name, map[string]any{"greeting_message": "Hi"})
+		assert.Equal(t, fasthttp.StatusForbidden, status)
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/contactutil"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// errContactBlocked is returned for any send to a blocked contact
+var errContactBlocked = errors.New("contact is blocked")
+
+// ContactBlockResponse reports whether a contact is blocked
+type ContactBlockResponse struct {
+	ContactID uuid.UUID  `json:"contact_id"`
+	Blocked   bool       `json:"blocked"`
+	BlockedAt *time.Time `json:"blocked_at,omitempty"`
+}
+
+// BlockContact stops all interaction with a contact: their messages are
+// ignored and nothing can be sent to them
+func (a *App) BlockContact(r *fastglue.Request) error {
+	return a.setContactBlocked(r, true)
+}
+
+// UnblockContact lets a blocked contact message and be messaged again
+func (a *App) UnblockContact(r *fastglue.Request) error {
+	return a.setContactBlocked(r, false)
+}
+
+func (a *App) setContactBlocked(r *fastglue.Request, blocked bool) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceContacts, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	var contact models.Contact
+	if err := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID).
+		First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	// Blocking an already blocked contact keeps the original time
+	blockedAt := contact.BlockedAt
+	if blocked && !contact.Blocked {
+		now := time.Now()
+		blockedAt = &now
+	} else if !blocked {
+		blockedAt = nil
+	}
+
+	if err := a.DB.Model(&contact).Updates(map[string]any{
+		"blocked":    blocked,
+		"blocked_at": blockedAt,
+	}).Error; err != nil {
+		a.Log.Error("Failed to update contact block", "error", err, "contact_id", contact.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update contact", nil, "")
+	}
+
+	a.Log.Info("Contact block updated", "contact_id", contact.ID, "blocked", blocked, "user_id", userID)
+	return r.SendEnvelope(ContactBlockResponse{ContactID: contact.ID, Blocked: blocked, BlockedAt: blockedAt})
+}
+
+// isContactBlocked reports whether the organization blocked a contact with
+// this phone number, however either side was formatted
+func (a *App) isContactBlocked(orgID uuid.UUID, phone string) bool {
+	phone = contactutil.NormalizePhone(phone)
+	if phone == "" {
+		return false
+	}
+	var count int64
+	a.DB.Unscoped().Model(&models.Contact{}).
+		Where("organization_id = ? AND blocked = ? AND (phone_number IN ? OR "+contactutil.PhoneDigitsExpr+" = ?)",
+			orgID, true, []string{phone, "+" + phone}, phone).
+		Count(&count)
+	return count > 0
+}
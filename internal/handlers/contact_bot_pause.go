@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// maxBotPauseMinutes caps how long an automatically resuming pause can last (7 days)
+const maxBotPauseMinutes = 7 * 24 * 60
+
+// PauseChatbotRequest pauses the chatbot for a contact. Without minutes the
+// pause lasts until it's resumed.
+type PauseChatbotRequest struct {
+	Minutes int `json:"minutes"`
+}
+
+// ChatbotPauseResponse reports whether the chatbot is paused for a contact
+type ChatbotPauseResponse struct {
+	ContactID   uuid.UUID  `json:"contact_id"`
+	BotPaused   bool       `json:"bot_paused"`
+	BotResumeAt *time.Time `json:"bot_resume_at,omitempty"`
+}
+
+// PauseChatbotForContact stops automated responses to a contact, optionally
+// resuming them after the given number of minutes
+func (a *App) PauseChatbotForContact(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceChat, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	var req PauseChatbotRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	if req.Minutes < 0 || req.Minutes > maxBotPauseMinutes {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "minutes must be between 0 and 10080", nil, "")
+	}
+
+	var contact models.Contact
+	if err := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID).
+		First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	var resumeAt *time.Time
+	if req.Minutes > 0 {
+		t := time.Now().Add(time.Duration(req.Minutes) * time.Minute)
+		resumeAt = &t
+	}
+
+	if err := a.DB.Model(&contact).Updates(map[string]any{
+		"bot_paused":    true,
+		"bot_resume_at": resumeAt,
+	}).Error; err != nil {
+		a.Log.Error("Failed to pause chatbot for contact", "error", err, "contact_id", contact.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to pause chatbot", nil, "")
+	}
+
+	a.Log.Info("Chatbot paused for contact", "contact_id", contact.ID, "user_id", userID, "resume_at", resumeAt)
+	return r.SendEnvelope(ChatbotPauseResponse{ContactID: contact.ID, BotPaused: true, BotResumeAt: resumeAt})
+}
+
+// ResumeChatbotForContact lets the chatbot respond to a contact again
+func (a *App) ResumeChatbotForContact(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceChat, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	var contact models.Contact
+	if err := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID).
+		First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	if err := a.resumeBot(&contact); err != nil {
+		a.Log.Error("Failed to resume chatbot for contact", "error", err, "contact_id", contact.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to resume chatbot", nil, "")
+	}
+
+	a.Log.Info("Chatbot resumed for contact", "contact_id", contact.ID, "user_id", userID)
+	return r.SendEnvelope(ChatbotPauseResponse{ContactID: contact.ID, BotPaused: false})
+}
+
+// isBotPaused reports whether the chatbot is paused for the contact at now
+func isBotPaused(contact *models.Contact, now time.Time) bool {
+	return contact.BotPaused && (contact.BotResumeAt == nil || now.Before(*contact.BotResumeAt))
+}
+
+// chatbotPausedForContact reports whether automated responses to the contact
+// are paused, clearing a pause whose resume time has passed
+func (a *App) chatbotPausedForContact(contact *models.Contact) bool {
+	if !contact.BotPaused {
+		return false
+	}
+	if isBotPaused(contact, time.Now()) {
+		return true
+	}
+	if err := a.resumeBot(contact); err != nil {
+		a.Log.Error("Failed to clear expired chatbot pause", "error", err, "contact_id", contact.ID)
+	}
+	return false
+}
+
+// resumeBot clears the contact's chatbot pause
+func (a *App) resumeBot(contact *models.Contact) error {
+	if err := a.DB.Model(contact).Updates(map[string]any{
+		"bot_paused":    false,
+		"bot_resume_at": nil,
+	}).Error; err != nil {
+		return err
+	}
+	contact.BotPaused = false
+	contact.BotResumeAt = nil
+	return nil
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// maxSnoozeDuration caps how far ahead a conversation can be snoozed
+const maxSnoozeDuration = 365 * 24 * time.Hour
+
+// UpdateConversationStatusRequest changes a contact's conversation status.
+// snooze_until is required for, and only allowed with, the snoozed status.
+type UpdateConversationStatusRequest struct {
+	Status      models.ConversationStatus `json:"status"`
+	SnoozeUntil *time.Time                `json:"snooze_until"`
+}
+
+// ConversationStatusResponse reports a contact's conversation status
+type ConversationStatusResponse struct {
+	ContactID          uuid.UUID                 `json:"contact_id"`
+	ConversationStatus models.ConversationStatus `json:"conversation_status"`
+	SnoozeUntil        *time.Time                `json:"snooze_until,omitempty"`
+	AssignedUserID     *uuid.UUID                `json:"assigned_user_id"`
+}
+
+// UpdateConversationStatus moves a contact's conversation between open,
+// pending, resolved and snoozed. Resolving unassigns the contact when the
+// chatbot settings have unassign_on_resolve.
+func (a *App) UpdateConversationStatus(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceChat, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	var req UpdateConversationStatusRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	if errMsg := validateConversationStatus(req.Status, req.SnoozeUntil, time.Now()); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+
+	var contact models.Contact
+	if err := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID).
+		First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	updates := map[string]any{
+		"conversation_status": req.Status,
+		"snooze_until":        req.SnoozeUntil,
+	}
+	previousUserID := contact.AssignedUserID
+	assignedUserID := previousUserID
+	if req.Status == models.ConversationStatusResolved && previousUserID != nil && a.unassignsOnResolve(orgID, contact.WhatsAppAccount) {
+		updates["assigned_user_id"] = nil
+		assignedUserID = nil
+	}
+
+	err = a.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&contact).Updates(updates).Error; err != nil {
+			return err
+		}
+		return recordContactAssignment(tx, orgID, contact.ID, previousUserID, assignedUserID, userID)
+	})
+	if err != nil {
+		a.Log.Error("Failed to update conversation status", "error", err, "contact_id", contact.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update conversation status", nil, "")
+	}
+
+	a.Log.Info("Conversation status updated", "contact_id", contact.ID, "user_id", userID, "status", req.Status)
+	return r.SendEnvelope(ConversationStatusResponse{
+		ContactID:          contact.ID,
+		ConversationStatus: req.Status,
+		SnoozeUntil:        req.SnoozeUntil,
+		AssignedUserID:     assignedUserID,
+	})
+}
+
+// validateConversationStatus checks a requested status change and returns an
+// error message, or "" when valid. snooze_until is required for, and only
+// allowed with, the snoozed status.
+func validateConversationStatus(status models.ConversationStatus, snoozeUntil *time.Time, now time.Time) string {
+	switch status {
+	case models.ConversationStatusOpen, models.ConversationStatusPending, models.ConversationStatusResolved:
+		if snoozeUntil != nil {
+			return "snooze_until is only allowed when status is snoozed"
+		}
+	case models.ConversationStatusSnoozed:
+		if snoozeUntil == nil || !snoozeUntil.After(now) {
+			return "snooze_until must be in the future"
+		}
+		if snoozeUntil.After(now.Add(maxSnoozeDuration)) {
+			return "snooze_until must be within a year"
+		}
+	default:
+		return "status must be one of: open, pending, resolved, snoozed"
+	}
+	return ""
+}
+
+// unassignsOnResolve reports whether the chatbot settings for the account
+// return resolved conversations to the unassigned pool
+func (a *App) unassignsOnResolve(orgID uuid.UUID, whatsAppAccount string) bool {
+	settings, err := a.getChatbotSettingsCached(orgID, whatsAppAccount)
+	return err == nil && settings.AgentAssignment.UnassignOnResolve
+}
+
+// conversationStatus returns the contact's conversation status at now,
+// treating a snooze that has run out as open
+func conversationStatus(contact *models.Contact, now time.Time) (models.ConversationStatus, *time.Time) {
+	switch {
+	case contact.ConversationStatus == "":
+		return models.ConversationStatusOpen, nil
+	case contact.ConversationStatus == models.ConversationStatusSnoozed:
+		if contact.SnoozeUntil == nil || !now.Before(*contact.SnoozeUntil) {
+			return models.ConversationStatusOpen, nil
+		}
+		return contact.ConversationStatus, contact.SnoozeUntil
+	}
+	return contact.ConversationStatus, nil
+}
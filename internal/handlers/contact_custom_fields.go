@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// customFieldKeyPattern restricts keys to identifiers that are safe in filters and templates
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// maxCustomFieldTextLength caps the length of text custom field values
+const maxCustomFieldTextLength = 1000
+
+// ContactCustomFieldRequest represents the request body for creating/updating a custom field.
+// Key and type can't be changed once the field exists.
+type ContactCustomFieldRequest struct {
+	Key     string   `json:"key"`
+	Label   string   `json:"label"`
+	Type    string   `json:"type"`
+	Options []string `json:"options"`
+}
+
+// UpdateContactCustomFieldsRequest sets custom field values on a contact.
+// Keys not present are left unchanged; a null value clears the field.
+type UpdateContactCustomFieldsRequest struct {
+	CustomFields map[string]any `json:"custom_fields"`
+}
+
+// ListContactCustomFields returns the organization's contact custom field definitions
+func (a *App) ListContactCustomFields(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceContacts, models.ActionRead); err != nil {
+		return nil
+	}
+
+	var fields []models.ContactCustomField
+	if err := a.DB.Where("organization_id = ?", orgID).Order("created_at ASC").Find(&fields).Error; err != nil {
+		a.Log.Error("Failed to list contact custom fields", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list custom fields", nil, "")
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"custom_fields": fields,
+	})
+}
+
+// CreateContactCustomField defines a new contact custom field
+func (a *App) CreateContactCustomField(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceSettingsGeneral, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	var req ContactCustomFieldRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	req.Key = strings.TrimSpace(req.Key)
+	if !customFieldKeyPattern.MatchString(req.Key) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			"key must start with a lowercase letter and contain only lowercase letters, digits and underscores (max 50)", nil, "")
+	}
+	if !models.IsValidCustomFieldType(req.Type) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			"invalid type. Valid types: "+strings.Join(models.ValidCustomFieldTypes, ", "), nil, "")
+	}
+
+	label := strings.TrimSpace(req.Label)
+	if label == "" {
+		label = req.Key
+	}
+	if len(label) > 100 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "label must be at most 100 characters", nil, "")
+	}
+
+	options, msg := normalizeCustomFieldOptions(req.Type, req.Options)
+	if msg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, msg, nil, "")
+	}
+
+	// Check for duplicate key
+	var existing models.ContactCustomField
+	if err := a.DB.Where("organization_id = ? AND key = ?", orgID, req.Key).First(&existing).Error; err == nil {
+		return r.SendErrorEnvelope(fasthttp.StatusConflict, "Custom field with this key already exists", nil, "")
+	}
+
+	field := models.ContactCustomField{
+		OrganizationID: orgID,
+		Key:            req.Key,
+		Label:          label,
+		FieldType:      req.Type,
+		Options:        options,
+	}
+	if err := a.DB.Create(&field).Error; err != nil {
+		a.Log.Error("Failed to create contact custom field", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to create custom field", nil, "")
+	}
+
+	return r.SendEnvelope(field)
+}
+
+// UpdateContactCustomField updates a custom field's label and, for select fields, its options.
+// Existing contact values are not revalidated.
+func (a *App) UpdateContactCustomField(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceSettingsGeneral, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	id, err := parsePathUUID(r, "id", "custom field")
+	if err != nil {
+		return nil
+	}
+
+	field, err := findByIDAndOrg[models.ContactCustomField](a.DB, r, id, orgID, "Custom field")
+	if err != nil {
+		return nil
+	}
+
+	var req ContactCustomFieldRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	if req.Key != "" && req.Key != field.Key {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "key cannot be changed", nil, "")
+	}
+	if req.Type != "" && req.Type != field.FieldType {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "type cannot be changed", nil, "")
+	}
+
+	updates := map[string]any{}
+	if label := strings.TrimSpace(req.Label); label != "" {
+		if len(label) > 100 {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "label must be at most 100 characters", nil, "")
+		}
+		updates["label"] = label
+	}
+	if req.Options != nil {
+		options, msg := normalizeCustomFieldOptions(field.FieldType, req.Options)
+		if msg != "" {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, msg, nil, "")
+		}
+		updates["options"] = options
+	}
+
+	if len(updates) > 0 {
+		if err := a.DB.Model(field).Updates(updates).Error; err != nil {
+			a.Log.Error("Failed to update contact custom field", "error", err)
+			return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update custom field", nil, "")
+		}
+	}
+
+	return r.SendEnvelope(field)
+}
+
+// DeleteContactCustomField deletes a custom field definition and clears its value from contacts
+func (a *App) DeleteContactCustomField(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceSettingsGeneral, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	id, err := parsePathUUID(r, "id", "custom field")
+	if err != nil {
+		return nil
+	}
+
+	field, err := findByIDAndOrg[models.ContactCustomField](a.DB, r, id, orgID, "Custom field")
+	if err != nil {
+		return nil
+	}
+
+	// Remove the field's value from all contacts that have it
+	if err := a.DB.Exec(`
+		UPDATE contacts
+		SET custom_fields = custom_fields - ?
+		WHERE organization_id = ?
+		AND jsonb_exists(custom_fields, ?)
+	`, field.Key, orgID, field.Key).Error; err != nil {
+		a.Log.Error("Failed to remove custom field from contacts", "error", err)
+		// Continue anyway - values of unknown fields are rejected on the next update
+	}
+
+	if err := a.DB.Delete(field).Error; err != nil {
+		a.Log.Error("Failed to delete contact custom field", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete custom field", nil, "")
+	}
+
+	return r.SendEnvelope(map[string]string{"message": "Custom field deleted"})
+}
+
+// UpdateContactCustomFields sets custom field values on a contact, validating
+// each value against the organization's field definitions
+func (a *App) UpdateContactCustomFields(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceContacts, models.ActionWrite, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "You do not have permission to update contacts", nil, "")
+	}
+
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	var req UpdateContactCustomFieldsRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	if len(req.CustomFields) == 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "custom_fields is required", nil, "")
+	}
+
+	contact, err := findByIDAndOrg[models.Contact](a.DB, r, contactID, orgID, "Contact")
+	if err != nil {
+		return nil
+	}
+
+	var defs []models.ContactCustomField
+	if err := a.DB.Where("organization_id = ?", orgID).Find(&defs).Error; err != nil {
+		a.Log.Error("Failed to load contact custom fields", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update custom fields", nil, "")
+	}
+	byKey := make(map[string]*models.ContactCustomField, len(defs))
+	for i := range defs {
+		byKey[defs[i].Key] = &defs[i]
+	}
+
+	values := models.JSONB{}
+	for k, v := range contact.CustomFields {
+		values[k] = v
+	}
+
+	errs := map[string]string{}
+	for key, value := range req.CustomFields {
+		field, ok := byKey[key]
+		if !ok {
+			errs[key] = "unknown custom field"
+			continue
+		}
+		if value == nil {
+			delete(values, key)
+			continue
+		}
+		normalized, msg := validateCustomFieldValue(field, value)
+		if msg != "" {
+			errs[key] = msg
+			continue
+		}
+		values[key] = normalized
+	}
+	if len(errs) > 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid custom field values", map[string]any{"errors": errs}, "")
+	}
+
+	if err := a.DB.Model(contact).Update("custom_fields", values).Error; err != nil {
+		a.Log.Error("Failed to update contact custom fields", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update custom fields", nil, "")
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"message":       "Contact custom fields updated",
+		"custom_fields": values,
+	})
+}
+
+// normalizeCustomFieldOptions trims and dedupes select options, returning an
+// error message when they don't suit the field type
+func normalizeCustomFieldOptions(fieldType string, options []string) (models.StringArray, string) {
+	result := models.StringArray{}
+	if fieldType != models.CustomFieldTypeSelect {
+		if len(options) > 0 {
+			return nil, "options are only allowed for select fields"
+		}
+		return result, ""
+	}
+
+	seen := make(map[string]bool, len(options))
+	for _, opt := range options {
+		opt = strings.TrimSpace(opt)
+		if opt == "" || seen[opt] {
+			continue
+		}
+		seen[opt] = true
+		result = append(result, opt)
+	}
+	if len(result) == 0 {
+		return nil, "select fields require at least one option"
+	}
+	return result, ""
+}
+
+// validateCustomFieldValue checks a value against the field's type and allowed
+// options, returning the value to store or a message describing the problem
+func validateCustomFieldValue(field *models.ContactCustomField, value any) (any, string) {
+	switch field.FieldType {
+	case models.CustomFieldTypeText:
+		s, ok := value.(string)
+		if !ok {
+			return nil, "expected a string"
+		}
+		if len(s) > maxCustomFieldTextLength {
+			return nil, fmt.Sprintf("must be at most %d characters", maxCustomFieldTextLength)
+		}
+		return s, ""
+	case models.CustomFieldTypeNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return nil, "expected a number"
+		}
+		return n, ""
+	case models.CustomFieldTypeBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, "expected true or false"
+		}
+		return b, ""
+	case models.CustomFieldTypeDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, "expected a date (YYYY-MM-DD)"
+		}
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, "expected a date (YYYY-MM-DD)"
+		}
+		return s, ""
+	case models.CustomFieldTypeSelect:
+		s, ok := value.(string)
+		if !ok {
+			return nil, "expected one of: " + strings.Join(field.Options, ", ")
+		}
+		for _, opt := range field.Options {
+			if opt == s {
+				return s, ""
+			}
+		}
+		return nil, "expected one of: " + strings.Join(field.Options, ", ")
+	}
+	return nil, "unsupported field type"
+}
+
+// contactCustomFields returns the contact's custom field values, never nil
+func contactCustomFields(contact *models.Contact) models.JSONB {
+	if contact.CustomFields == nil {
+		return models.JSONB{}
+	}
+	return contact.CustomFields
+}
@@ -0,0 +1,194 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/handlers"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// createTestCustomField creates a contact custom field directly in the database for testing.
+func createTestCustomField(t *testing.T, app *handlers.App, orgID uuid.UUID, key, fieldType string, options ...string) *models.ContactCustomField {
+	t.Helper()
+
+	field := &models.ContactCustomField{
+		OrganizationID: orgID,
+		Key:            key,
+		Label:          key,
+		FieldType:      fieldType,
+		Options:        models.StringArray(options),
+	}
+	if field.Options == nil {
+		field.Options = models.StringArray{}
+	}
+	require.NoError(t, app.DB.Create(field).Error)
+	return field
+}
+
+func TestApp_CreateContactCustomField(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"key":     "plan",
+			"label":   "Plan",
+			"type":    "select",
+			"options": []string{"free", " pro ", "free"},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.CreateContactCustomField(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data models.ContactCustomField `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, "plan", resp.Data.Key)
+		assert.Equal(t, models.StringArray{"free", "pro"}, resp.Data.Options)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+
+		bodies := []map[string]any{
+			{"key": "Plan", "type": "text"},
+			{"key": "plan", "type": "color"},
+			{"key": "plan", "type": "select"},
+			{"key": "plan", "type": "text", "options": []string{"a"}},
+		}
+		for _, body := range bodies {
+			req := testutil.NewJSONRequest(t, body)
+			testutil.SetAuthContext(req, org.ID, user.ID)
+
+			require.NoError(t, app.CreateContactCustomField(req))
+			assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req), body)
+		}
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+		createTestCustomField(t, app, org.ID, "region", models.CustomFieldTypeText)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"key": "region", "type": "text"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.CreateContactCustomField(req))
+		assert.Equal(t, fasthttp.StatusConflict, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_UpdateContactCustomFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - sets and clears values", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+		createTestCustomField(t, app, org.ID, "plan", models.CustomFieldTypeSelect, "free", "pro")
+		createTestCustomField(t, app, org.ID, "seats", models.CustomFieldTypeNumber)
+		createTestCustomField(t, app, org.ID, "region", models.CustomFieldTypeText)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(contact).Update("custom_fields", models.JSONB{"region": "EU"}).Error)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"custom_fields": map[string]any{"plan": "pro", "seats": 12, "region": nil},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+
+		require.NoError(t, app.UpdateContactCustomFields(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var stored models.Contact
+		require.NoError(t, app.DB.Where("id = ?", contact.ID).First(&stored).Error)
+		assert.Equal(t, "pro", stored.CustomFields["plan"])
+		assert.Equal(t, float64(12), stored.CustomFields["seats"])
+		assert.NotContains(t, stored.CustomFields, "region")
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+		createTestCustomField(t, app, org.ID, "plan", models.CustomFieldTypeSelect, "free", "pro")
+		createTestCustomField(t, app, org.ID, "renewal", models.CustomFieldTypeDate)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"custom_fields": map[string]any{"plan": "enterprise", "renewal": "next week", "unknown": "x"},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+
+		require.NoError(t, app.UpdateContactCustomFields(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Errors map[string]string `json:"errors"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Len(t, resp.Data.Errors, 3)
+
+		var stored models.Contact
+		require.NoError(t, app.DB.Where("id = ?", contact.ID).First(&stored).Error)
+		assert.Empty(t, stored.CustomFields)
+	})
+}
+
+func TestApp_ListContacts_FilterByCustomField(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	role := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+	pro := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(pro).Update("custom_fields", models.JSONB{"plan": "pro"}).Error)
+	free := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(free).Update("custom_fields", models.JSONB{"plan": "free"}).Error)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetQueryParam(req, "custom_field", "plan")
+	testutil.SetQueryParam(req, "custom_value", "pro")
+
+	require.NoError(t, app.ListContacts(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data struct {
+			Contacts []handlers.ContactResponse `json:"contacts"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	require.Len(t, resp.Data.Contacts, 1)
+	assert.Equal(t, pro.ID, resp.Data.Contacts[0].ID)
+	assert.Equal(t, map[string]any{"plan": "pro"}, resp.Data.Contacts[0].CustomFields)
+}
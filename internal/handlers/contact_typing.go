@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// typingIndicatorDuration is how long WhatsApp shows the indicator unless a reply is sent first
+const typingIndicatorDuration = 25 * time.Second
+
+// SendTypingIndicatorRequest optionally picks the account to show typing on
+type SendTypingIndicatorRequest struct {
+	WhatsAppAccount string `json:"whatsapp_account,omitempty"`
+}
+
+// TypingIndicatorResponse reports a typing indicator shown to a contact
+type TypingIndicatorResponse struct {
+	ContactID uuid.UUID `json:"contact_id"`
+	MessageID uuid.UUID `json:"message_id"` // The incoming message the indicator responds to
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SendTypingIndicator shows the contact that an agent is typing a reply.
+// WhatsApp ties the indicator to the contact's latest incoming message, so
+// the contact must have written in on the account. Nothing is stored.
+func (a *App) SendTypingIndicator(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	var req SendTypingIndicatorRequest
+	if len(r.RequestCtx.PostBody()) > 0 {
+		if err := a.decodeRequest(r, &req); err != nil {
+			return nil
+		}
+	}
+
+	// Users without full read permission can only type to their assigned contacts
+	var contact models.Contact
+	query := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID)
+	if err := query.First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	if a.isPhoneBlacklisted(orgID, contact.PhoneNumber) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Phone number is blacklisted", nil, "")
+	}
+	if contact.Blocked {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Contact is blocked", nil, "")
+	}
+
+	account, err := a.resolveSendingWhatsAppAccount(orgID, contactAccountName(&contact, req.WhatsAppAccount))
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	var message models.Message
+	if err := a.DB.Where("contact_id = ? AND organization_id = ? AND whats_app_account = ? AND direction = ? AND whats_app_message_id <> ''",
+		contact.ID, orgID, account.Name, models.DirectionIncoming).
+		Order("created_at DESC").First(&message).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Contact has no incoming message to respond to", nil, "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := a.WhatsApp.SendTypingIndicator(ctx, a.toWhatsAppAccount(account), message.WhatsAppMessageID); err != nil {
+		a.Log.Error("Failed to send typing indicator", "error", err, "contact_id", contact.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusBadGateway, "Failed to send typing indicator", nil, "")
+	}
+
+	return r.SendEnvelope(TypingIndicatorResponse{
+		ContactID: contact.ID,
+		MessageID: message.ID,
+		ExpiresAt: time.Now().Add(typingIndicatorDuration),
+	})
+}
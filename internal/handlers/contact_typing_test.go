@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/handlers"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestApp_SendTypingIndicator(t *testing.T) {
+	mockServer := newMockWhatsAppServer()
+	defer mockServer.close()
+
+	app := newMsgTestApp(t, mockServer)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := createTestAccount(t, app, org.ID)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+	incoming := &models.Message{
+		BaseModel:         models.BaseModel{ID: uuid.New()},
+		OrganizationID:    org.ID,
+		ContactID:         contact.ID,
+		WhatsAppAccount:   account.Name,
+		WhatsAppMessageID: "wamid.incoming-1",
+		Direction:         models.DirectionIncoming,
+		MessageType:       models.MessageTypeText,
+		Content:           "Hi there",
+		Status:            models.MessageStatusDelivered,
+	}
+	require.NoError(t, app.DB.Create(incoming).Error)
+
+	var before int64
+	app.DB.Model(&models.Message{}).Where("contact_id = ?", contact.ID).Count(&before)
+
+	req := testutil.NewJSONRequest(t, nil)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+	require.NoError(t, app.SendTypingIndicator(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.TypingIndicatorResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, incoming.ID, resp.Data.MessageID)
+	assert.True(t, resp.Data.ExpiresAt.After(time.Now()))
+
+	require.Len(t, mockServer.sentMessages, 1)
+	sent := mockServer.sentMessages[0]
+	assert.Equal(t, "wamid.incoming-1", sent["message_id"])
+	assert.NotNil(t, sent["typing_indicator"])
+
+	// No message row is stored for the indicator
+	var after int64
+	app.DB.Model(&models.Message{}).Where("contact_id = ?", contact.ID).Count(&after)
+	assert.Equal(t, before, after)
+}
+
+func TestApp_SendTypingIndicator_NoIncomingMessage(t *testing.T) {
+	mockServer := newMockWhatsAppServer()
+	defer mockServer.close()
+
+	app := newMsgTestApp(t, mockServer)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := createTestAccount(t, app, org.ID)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+	req := testutil.NewJSONRequest(t, nil)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+	require.NoError(t, app.SendTypingIndicator(req))
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	assert.Empty(t, mockServer.sentMessages)
+}
+
+func TestApp_SendTypingIndicator_APIError(t *testing.T) {
+	mockServer := newMockWhatsAppServer()
+	defer mockServer.close()
+	mockServer.returnError = true
+	mockServer.errorMessage = "Message not found"
+
+	app := newMsgTestApp(t, mockServer)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := createTestAccount(t, app, org.ID)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+	require.NoError(t, app.DB.Create(&models.Message{
+		BaseModel:         models.BaseModel{ID: uuid.New()},
+		OrganizationID:    org.ID,
+		ContactID:         contact.ID,
+		WhatsAppAccount:   account.Name,
+		WhatsAppMessageID: "wamid.incoming-2",
+		Direction:         models.DirectionIncoming,
+		MessageType:       models.MessageTypeText,
+		Content:           "Hello",
+		Status:            models.MessageStatusDelivered,
+	}).Error)
+
+	req := testutil.NewJSONRequest(t, nil)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+	require.NoError(t, app.SendTypingIndicator(req))
+	assert.Equal(t, fasthttp.StatusBadGateway, testutil.GetResponseStatusCode(req))
+}
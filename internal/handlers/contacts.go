@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -14,32 +16,50 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/contactutil"
 	"github.com/shridarpatil/whatomate/internal/models"
 	"github.com/shridarpatil/whatomate/internal/websocket"
 	"github.com/shridarpatil/whatomate/pkg/whatsapp"
 	"github.com/valyala/fasthttp"
 	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
 )
 
 // ContactResponse represents a contact with additional fields for the frontend
 type ContactResponse struct {
-	ID                 uuid.UUID  `json:"id"`
-	PhoneNumber        string     `json:"phone_number"`
-	Name               string     `json:"name"`
-	ProfileName        string     `json:"profile_name"`
-	AvatarURL          string     `json:"avatar_url"`
-	Status             string     `json:"status"`
-	Tags               []string   `json:"tags"`
-	Metadata           any        `json:"metadata"`
-	LastMessageAt      *time.Time `json:"last_message_at"`
-	LastMessagePreview string     `json:"last_message_preview"`
-	UnreadCount        int        `json:"unread_count"`
-	AssignedUserID     *uuid.UUID `json:"assigned_user_id,omitempty"`
-	WhatsAppAccount    string     `json:"whatsapp_account,omitempty"`
-	LastInboundAt      *time.Time `json:"last_inbound_at,omitempty"`
-	ServiceWindowOpen  bool       `json:"service_window_open"`
-	CreatedAt          time.Time  `json:"created_at"`
-	UpdatedAt          time.Time  `json:"updated_at"`
+	ID                   uuid.UUID                 `json:"id"`
+	PhoneNumber          string                    `json:"phone_number"`
+	Name                 string                    `json:"name"`
+	ProfileName          string                    `json:"profile_name"`
+	AvatarURL            string                    `json:"avatar_url"`
+	Status               string                    `json:"status"`
+	Tags                 []string                  `json:"tags"`
+	Metadata             any                       `json:"metadata"`
+	CustomFields         any                       `json:"custom_fields"`
+	LastMessageAt        *time.Time                `json:"last_message_at"`
+	LastMessagePreview   string                    `json:"last_message_preview"`
+	LastMessageDirection models.Direction          `json:"last_message_direction,omitempty"`
+	UnreadCount          int                       `json:"unread_count"`
+	AssignedUserID       *uuid.UUID                `json:"assigned_user_id,omitempty"`
+	AssignedUserName     *string                   `json:"assigned_user_name"`      // nil when unassigned
+	AssignedUserAvail    *bool                     `json:"assigned_user_available"` // nil when unassigned
+	WhatsAppAccount      string                    `json:"whatsapp_account,omitempty"`
+	LastInboundAt        *time.Time                `json:"last_inbound_at,omitempty"`
+	ServiceWindowOpen    bool                      `json:"service_window_open"`
+	BotPaused            bool                      `json:"bot_paused"`
+	BotResumeAt          *time.Time                `json:"bot_resume_at,omitempty"`
+	ConversationStatus   models.ConversationStatus `json:"conversation_status"`
+	SnoozeUntil          *time.Time                `json:"snooze_until,omitempty"`
+	OptedOut             bool                      `json:"opted_out"`
+	OptedOutAt           *time.Time                `json:"opted_out_at,omitempty"`
+	OptInAt              *time.Time                `json:"opt_in_at,omitempty"`
+	OptInSource          string                    `json:"opt_in_source,omitempty"`
+	OptInMethod          models.OptInMethod        `json:"opt_in_method,omitempty"`
+	Blocked              bool                      `json:"blocked"`
+	BlockedAt            *time.Time                `json:"blocked_at,omitempty"`
+	FloodFlaggedAt       *time.Time                `json:"flood_flagged_at,omitempty"`
+	CreatedAt            time.Time                 `json:"created_at"`
+	UpdatedAt            time.Time                 `json:"updated_at"`
 }
 
 // MessageResponse represents a message for the frontend
@@ -81,7 +101,7 @@ type ReactionInfo struct {
 }
 
 // ListContacts returns all contacts for the organization
-// Users without contacts:read permission only see contacts assigned to them
+// Users limited to their own contacts only see contacts assigned to them
 func (a *App) ListContacts(r *fastglue.Request) error {
 	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
@@ -90,55 +110,26 @@ func (a *App) ListContacts(r *fastglue.Request) error {
 
 	// Pagination
 	pg := parsePagination(r)
-	search := string(r.RequestCtx.QueryArgs().Peek("search"))
-	tagsParam := string(r.RequestCtx.QueryArgs().Peek("tags"))
 
 	var contacts []models.Contact
 	query := a.ScopeToOrg(a.DB, userID, orgID)
 
-	// Users without contacts:read permission can only see contacts assigned to them
-	if !a.HasPermission(userID, models.ResourceContacts, models.ActionRead, orgID) {
-		query = query.Where("assigned_user_id = ?", userID)
-	}
-
-	if search != "" {
-		// Limit search string length to prevent abuse
-		if len(search) > 1000 {
-			search = search[:1000]
-		}
-		searchPattern := "%" + search + "%"
-		// Use ILIKE for case-insensitive search on profile_name
-		query = query.Where("phone_number LIKE ? OR profile_name ILIKE ?", searchPattern, searchPattern)
-	}
+	// Users without contacts:read, or whose role is limited to their own contacts,
+	// only see contacts assigned to them
+	query = a.scopeContactsQuery(query, userID, orgID)
+	query = filterContactsFromQueryArgs(query, r.RequestCtx.QueryArgs())
 
-	// Filter by tags (comma-separated, matches contacts that have ANY of the specified tags)
-	if tagsParam != "" {
-		tagList := strings.Split(tagsParam, ",")
-		// Trim whitespace from each tag and build OR conditions
-		// Using @> operator which leverages the GIN index on tags
-		conditions := make([]string, 0, len(tagList))
-		args := make([]any, 0, len(tagList))
-		for _, tag := range tagList {
-			tag = strings.TrimSpace(tag)
-			if tag != "" {
-				// Use proper JSONB containment with explicit cast
-				conditions = append(conditions, "tags @> ?::jsonb")
-				tagJSON, _ := json.Marshal([]string{tag})
-				args = append(args, string(tagJSON))
-			}
-		}
-		if len(conditions) > 0 {
-			query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
-		}
+	// Most recent conversations first unless the caller picks another order
+	orderBy, ok := contactsOrder(string(r.RequestCtx.QueryArgs().Peek("sort")), string(r.RequestCtx.QueryArgs().Peek("order")))
+	if !ok {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid sort. Use last_message_at, last_inbound_at or created_at, with order asc or desc", nil, "")
 	}
-
-	// Order by last message time (most recent first)
-	query = query.Order("last_message_at DESC NULLS LAST, created_at DESC")
+	query = query.Order(orderBy)
 
 	var total int64
 	query.Model(&models.Contact{}).Count(&total)
 
-	if err := query.Offset(pg.Offset).Limit(pg.Limit).Find(&contacts).Error; err != nil {
+	if err := query.Offset(pg.Offset).Limit(pg.Limit).Preload("AssignedUser", selectAssignedUser).Find(&contacts).Error; err != nil {
 		a.Log.Error("Failed to list contacts", "error", err)
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list contacts", nil, "")
 	}
@@ -172,24 +163,47 @@ func (a *App) ListContacts(r *fastglue.Request) error {
 		}
 
 		serviceWindowOpen := c.LastInboundAt != nil && time.Since(*c.LastInboundAt) < 24*time.Hour
+		assignedName, assignedAvail := assignedUserSummary(&c)
+		botPaused := isBotPaused(&c, time.Now())
+		convStatus, snoozeUntil := conversationStatus(&c, time.Now())
+		var botResumeAt *time.Time
+		if botPaused {
+			botResumeAt = c.BotResumeAt
+		}
 
 		response[i] = ContactResponse{
-			ID:                 c.ID,
-			PhoneNumber:        phoneNumber,
-			Name:               profileName,
-			ProfileName:        profileName,
-			Status:             "active",
-			Tags:               tags,
-			Metadata:           c.Metadata,
-			LastMessageAt:      c.LastMessageAt,
-			LastMessagePreview: c.LastMessagePreview,
-			UnreadCount:        int(unreadCount),
-			AssignedUserID:     c.AssignedUserID,
-			WhatsAppAccount:    c.WhatsAppAccount,
-			LastInboundAt:      c.LastInboundAt,
-			ServiceWindowOpen:  serviceWindowOpen,
-			CreatedAt:          c.CreatedAt,
-			UpdatedAt:          c.UpdatedAt,
+			ID:                   c.ID,
+			PhoneNumber:          phoneNumber,
+			Name:                 profileName,
+			ProfileName:          profileName,
+			Status:               "active",
+			Tags:                 tags,
+			Metadata:             c.Metadata,
+			CustomFields:         contactCustomFields(&c),
+			LastMessageAt:        c.LastMessageAt,
+			LastMessagePreview:   c.LastMessagePreview,
+			LastMessageDirection: c.LastMessageDirection,
+			UnreadCount:          int(unreadCount),
+			AssignedUserID:       c.AssignedUserID,
+			AssignedUserName:     assignedName,
+			AssignedUserAvail:    assignedAvail,
+			WhatsAppAccount:      c.WhatsAppAccount,
+			LastInboundAt:        c.LastInboundAt,
+			ServiceWindowOpen:    serviceWindowOpen,
+			BotPaused:            botPaused,
+			BotResumeAt:          botResumeAt,
+			ConversationStatus:   convStatus,
+			SnoozeUntil:          snoozeUntil,
+			OptedOut:             c.OptedOut,
+			OptedOutAt:           c.OptedOutAt,
+			OptInAt:              c.OptInAt,
+			OptInSource:          c.OptInSource,
+			OptInMethod:          c.OptInMethod,
+			Blocked:              c.Blocked,
+			BlockedAt:            c.BlockedAt,
+			FloodFlaggedAt:       c.FloodFlaggedAt,
+			CreatedAt:            c.CreatedAt,
+			UpdatedAt:            c.UpdatedAt,
 		}
 	}
 
@@ -216,12 +230,10 @@ func (a *App) GetContact(r *fastglue.Request) error {
 	var contact models.Contact
 	query := a.DB.Where("id = ? AND organization_id = ?", contactID, orgID)
 
-	// Users without contacts:read permission can only access their assigned contacts
-	if !a.HasPermission(userID, models.ResourceContacts, models.ActionRead, orgID) {
-		query = query.Where("assigned_user_id = ?", userID)
-	}
+	// Users limited to their own contacts can only access their assigned contacts
+	query = a.scopeContactsQuery(query, userID, orgID)
 
-	if err := query.First(&contact).Error; err != nil {
+	if err := query.Preload("AssignedUser", selectAssignedUser).First(&contact).Error; err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
 	}
 
@@ -247,27 +259,79 @@ func (a *App) GetContact(r *fastglue.Request) error {
 		phoneNumber = MaskPhoneNumber(phoneNumber)
 		profileName = MaskIfPhoneNumber(profileName)
 	}
+	assignedName, assignedAvail := assignedUserSummary(&contact)
+	convStatus, snoozeUntil := conversationStatus(&contact, time.Now())
 
 	response := ContactResponse{
-		ID:                 contact.ID,
-		PhoneNumber:        phoneNumber,
-		Name:               profileName,
-		ProfileName:        profileName,
-		Status:             "active",
-		Tags:               tags,
-		Metadata:           contact.Metadata,
-		LastMessageAt:      contact.LastMessageAt,
-		LastMessagePreview: contact.LastMessagePreview,
-		UnreadCount:        int(unreadCount),
-		AssignedUserID:     contact.AssignedUserID,
-		WhatsAppAccount:    contact.WhatsAppAccount,
-		CreatedAt:          contact.CreatedAt,
-		UpdatedAt:          contact.UpdatedAt,
+		ID:                   contact.ID,
+		PhoneNumber:          phoneNumber,
+		Name:                 profileName,
+		ProfileName:          profileName,
+		Status:               "active",
+		Tags:                 tags,
+		Metadata:             contact.Metadata,
+		CustomFields:         contactCustomFields(&contact),
+		LastMessageAt:        contact.LastMessageAt,
+		LastMessagePreview:   contact.LastMessagePreview,
+		LastMessageDirection: contact.LastMessageDirection,
+		UnreadCount:          int(unreadCount),
+		AssignedUserID:       contact.AssignedUserID,
+		AssignedUserName:     assignedName,
+		AssignedUserAvail:    assignedAvail,
+		WhatsAppAccount:      contact.WhatsAppAccount,
+		ConversationStatus:   convStatus,
+		SnoozeUntil:          snoozeUntil,
+		OptedOut:             contact.OptedOut,
+		OptedOutAt:           contact.OptedOutAt,
+		OptInAt:              contact.OptInAt,
+		OptInSource:          contact.OptInSource,
+		OptInMethod:          contact.OptInMethod,
+		Blocked:              contact.Blocked,
+		BlockedAt:            contact.BlockedAt,
+		FloodFlaggedAt:       contact.FloodFlaggedAt,
+		CreatedAt:            contact.CreatedAt,
+		UpdatedAt:            contact.UpdatedAt,
 	}
 
 	return r.SendEnvelope(response)
 }
 
+// UnreadSummary counts conversations with unread incoming messages
+type UnreadSummary struct {
+	AssignedToMe int64 `json:"assigned_to_me"`
+	Unassigned   int64 `json:"unassigned"`
+}
+
+// GetUnreadSummary returns how many contacts assigned to the caller have unread
+// incoming messages, plus the org-wide count of unassigned unread contacts
+func (a *App) GetUnreadSummary(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	var summary UnreadSummary
+	if err := a.DB.Model(&models.Contact{}).
+		Select("COUNT(*) FILTER (WHERE assigned_user_id = ?) AS assigned_to_me, "+
+			"COUNT(*) FILTER (WHERE assigned_user_id IS NULL) AS unassigned", userID).
+		Where("organization_id = ?", orgID).
+		Where("EXISTS (SELECT 1 FROM messages m WHERE m.contact_id = contacts.id AND m.direction = ? AND m.status != ? AND m.deleted_at IS NULL)",
+			models.DirectionIncoming, models.MessageStatusRead).
+		Scan(&summary).Error; err != nil {
+		a.Log.Error("Failed to count unread conversations", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load unread summary", nil, "")
+	}
+
+	return r.SendEnvelope(summary)
+}
+
+// Messages imported in bulk can share a created_at, so the WhatsApp message ID
+// and then the row ID break ties to keep ordering stable across requests.
+const (
+	messageOrderAsc  = "created_at ASC, whats_app_message_id ASC, id ASC"
+	messageOrderDesc = "created_at DESC, whats_app_message_id DESC, id DESC"
+)
+
 // GetMessages returns messages for a contact
 // Agents can only access messages for their assigned contacts
 // Supports cursor-based pagination with before_id for loading older messages
@@ -281,14 +345,11 @@ func (a *App) GetMessages(r *fastglue.Request) error {
 		return nil
 	}
 
-	hasContactsReadPermission := a.HasPermission(userID, models.ResourceContacts, models.ActionRead, orgID)
+	ownContactsOnly, _ := a.contactVisibility(userID, orgID)
 
-	// Verify contact belongs to org (and to user if no contacts:read permission)
+	// Verify contact belongs to org (and is visible to the user)
 	var contact models.Contact
-	query := a.DB.Where("id = ? AND organization_id = ?", contactID, orgID)
-	if !hasContactsReadPermission {
-		query = query.Where("assigned_user_id = ?", userID)
-	}
+	query := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID)
 	if err := query.First(&contact).Error; err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
 	}
@@ -310,8 +371,8 @@ func (a *App) GetMessages(r *fastglue.Request) error {
 		msgQuery = msgQuery.Where("whats_app_account = ?", accountFilter)
 	}
 
-	// Check if user without contacts:read should only see current conversation
-	if !hasContactsReadPermission {
+	// Check if a user limited to their own contacts should only see current conversation
+	if ownContactsOnly {
 		settings, err := a.getChatbotSettingsCached(orgID, "")
 		if err == nil {
 			if settings.AgentAssignment.CurrentConversationOnly {
@@ -337,12 +398,16 @@ func (a *App) GetMessages(r *fastglue.Request) error {
 			// Get the created_at of the before_id message
 			var beforeMsg models.Message
 			if err := a.DB.Where("id = ?", beforeID).First(&beforeMsg).Error; err == nil {
-				msgQuery = msgQuery.Where("created_at < ?", beforeMsg.CreatedAt)
+				// Compare on the full sort key so messages sharing a timestamp aren't skipped
+				msgQuery = msgQuery.Where(
+					"created_at < ? OR (created_at = ? AND (whats_app_message_id < ? OR (whats_app_message_id = ? AND id < ?)))",
+					beforeMsg.CreatedAt, beforeMsg.CreatedAt, beforeMsg.WhatsAppMessageID, beforeMsg.WhatsAppMessageID, beforeMsg.ID,
+				)
 			}
 		}
 		// For loading older messages, order DESC and limit, then reverse
 		var messages []models.Message
-		if err := msgQuery.Preload("ReplyToMessage").Order("created_at DESC").Limit(limit).Find(&messages).Error; err != nil {
+		if err := msgQuery.Preload("ReplyToMessage").Order(messageOrderDesc).Limit(limit).Find(&messages).Error; err != nil {
 			a.Log.Error("Failed to list messages", "error", err)
 			return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list messages", nil, "")
 		}
@@ -378,7 +443,7 @@ func (a *App) GetMessages(r *fastglue.Request) error {
 	}
 
 	var messages []models.Message
-	if err := msgQuery.Preload("ReplyToMessage").Order("created_at ASC").Offset(offset).Limit(queryLimit).Find(&messages).Error; err != nil {
+	if err := msgQuery.Preload("ReplyToMessage").Order(messageOrderAsc).Offset(offset).Limit(queryLimit).Find(&messages).Error; err != nil {
 		a.Log.Error("Failed to list messages", "error", err)
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list messages", nil, "")
 	}
@@ -467,7 +532,7 @@ func (a *App) buildMessagesResponse(messages []models.Message) []MessageResponse
 func (a *App) markMessagesAsRead(orgID uuid.UUID, contactID uuid.UUID, contact *models.Contact) {
 	var unreadMessages []models.Message
 	a.DB.Where("contact_id = ? AND direction = ? AND status != ?", contactID, models.DirectionIncoming, models.MessageStatusRead).
-		Find(&unreadMessages)
+		Order("created_at ASC").Find(&unreadMessages)
 
 	a.DB.Model(&models.Message{}).
 		Where("contact_id = ? AND direction = ?", contactID, models.DirectionIncoming).
@@ -475,33 +540,94 @@ func (a *App) markMessagesAsRead(orgID uuid.UUID, contactID uuid.UUID, contact *
 
 	a.DB.Model(contact).Update("is_read", true)
 
-	if len(unreadMessages) > 0 && contact.WhatsAppAccount != "" {
-		if account, err := a.resolveWhatsAppAccount(orgID, contact.WhatsAppAccount); err == nil {
-			if account.AutoReadReceipt {
-				a.wg.Add(1)
-				go func() {
-					defer a.wg.Done()
-					// Use timeout context for external API calls
-					ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-					defer cancel()
-
-					waAccount := a.toWhatsAppAccount(account)
-					for _, msg := range unreadMessages {
-						// Check if context was cancelled
-						if ctx.Err() != nil {
-							a.Log.Warn("Read receipt sending cancelled", "reason", ctx.Err())
-							return
-						}
-						if msg.WhatsAppMessageID != "" {
-							if err := a.WhatsApp.MarkMessageRead(ctx, waAccount, msg.WhatsAppMessageID); err != nil {
-								a.Log.Error("Failed to send read receipt", "error", err, "message_id", msg.WhatsAppMessageID)
-							}
-						}
-					}
-				}()
+	if len(unreadMessages) == 0 {
+		return
+	}
+
+	// WhatsApp marks everything before a message read along with it, so one
+	// receipt for the newest unread message per account covers the batch
+	latest := make(map[string]string)
+	var accountNames []string
+	for _, msg := range unreadMessages {
+		if msg.WhatsAppMessageID == "" {
+			continue
+		}
+		accountName := msg.WhatsAppAccount
+		if accountName == "" {
+			accountName = contact.WhatsAppAccount
+		}
+		if accountName == "" {
+			continue
+		}
+		if _, ok := latest[accountName]; !ok {
+			accountNames = append(accountNames, accountName)
+		}
+		latest[accountName] = msg.WhatsAppMessageID
+	}
+	if len(accountNames) == 0 {
+		return
+	}
+
+	orgEnabled := a.orgSendsReadReceipts(orgID)
+	for _, accountName := range accountNames {
+		account, err := a.resolveWhatsAppAccount(orgID, accountName)
+		if err != nil || !(orgEnabled || account.AutoReadReceipt) {
+			continue
+		}
+		messageID := latest[accountName]
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			// Use timeout context for external API calls
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			a.sendReadReceipt(ctx, a.toWhatsAppAccount(account), messageID)
+		}()
+	}
+}
+
+// orgSendsReadReceipts reports whether the organization sends read receipts
+// for every account, from the send_read_receipts setting
+func (a *App) orgSendsReadReceipts(orgID uuid.UUID) bool {
+	var org models.Organization
+	if err := a.DB.Select("id", "settings").Where("id = ?", orgID).First(&org).Error; err != nil {
+		return false
+	}
+	return org.SendsReadReceipts()
+}
+
+// sendReadReceipt sends a read receipt, retrying transient failures with
+// exponential backoff. Errors are only logged since receipts are best effort.
+func (a *App) sendReadReceipt(ctx context.Context, account *whatsapp.Account, messageID string) {
+	maxAttempts, delay := 3, time.Second
+	if a.Config != nil {
+		if a.Config.WhatsApp.ReadReceiptMaxAttempts > 0 {
+			maxAttempts = a.Config.WhatsApp.ReadReceiptMaxAttempts
+		}
+		if a.Config.WhatsApp.ReadReceiptRetryDelayMs > 0 {
+			delay = time.Duration(a.Config.WhatsApp.ReadReceiptRetryDelayMs) * time.Millisecond
+		}
+	}
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				a.Log.Warn("Read receipt retry cancelled", "reason", ctx.Err(), "message_id", messageID)
+				return
+			case <-time.After(delay << (attempt - 1)):
 			}
 		}
+
+		if err = a.WhatsApp.MarkMessageRead(ctx, account, messageID); err == nil {
+			return
+		}
+		a.Log.Warn("Read receipt send failed", "error", err, "message_id", messageID, "attempt", attempt+1, "max_attempts", maxAttempts)
 	}
+
+	a.Log.Error("Failed to send read receipt after all retries", "error", err, "message_id", messageID)
 }
 
 // SendMessageRequest represents a send message request
@@ -515,6 +641,16 @@ type SendMessageRequest struct {
 
 	// Interactive message fields (for type="interactive")
 	Interactive *InteractiveContent `json:"interactive,omitempty"`
+
+	// Media message fields (for type="image", "video", "audio" or "document")
+	Media *MediaContent `json:"media,omitempty"`
+}
+
+// MediaContent points to media to fetch and send
+type MediaContent struct {
+	URL      string `json:"url"`
+	Filename string `json:"filename,omitempty"` // Defaults to the last segment of the URL path
+	Caption  string `json:"caption,omitempty"`  // Not shown for audio
 }
 
 // InteractiveContent holds interactive message data
@@ -544,30 +680,65 @@ func (a *App) SendMessage(r *fastglue.Request) error {
 		return nil
 	}
 
+	// Uploaded files are sent as multipart forms, like SendMediaMessage
+	if strings.HasPrefix(string(r.RequestCtx.Request.Header.ContentType()), "multipart/form-data") {
+		form, err := r.RequestCtx.MultipartForm()
+		if err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid multipart form", nil, "")
+		}
+		return a.sendUploadedMedia(r, orgID, userID, contactID, form)
+	}
+
 	// Parse request body
 	var req SendMessageRequest
 	if err := json.Unmarshal(r.RequestCtx.PostBody(), &req); err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid request body", nil, "")
 	}
+	isMedia := isMediaMessageType(req.Type)
+	if isMedia && (req.Media == nil || strings.TrimSpace(req.Media.URL) == "") {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "media.url is required for media messages", nil, "")
+	}
 
 	// Get contact (users without full read permission can only message their assigned contacts)
 	var contact models.Contact
-	query := a.DB.Where("id = ? AND organization_id = ?", contactID, orgID)
-	if !a.HasPermission(userID, models.ResourceContacts, models.ActionRead, orgID) {
-		query = query.Where("assigned_user_id = ?", userID)
-	}
+	query := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID)
 	if err := query.First(&contact).Error; err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
 	}
 
-	// Get WhatsApp account - prefer request-specified account over contact default
-	accountName := contact.WhatsAppAccount
-	if req.WhatsAppAccount != "" {
-		accountName = req.WhatsAppAccount
+	if a.isPhoneBlacklisted(orgID, contact.PhoneNumber) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Phone number is blacklisted", nil, "")
+	}
+	if contact.Blocked {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Contact is blocked", nil, "")
 	}
-	account, err := a.resolveWhatsAppAccount(orgID, accountName)
+
+	// Reply on the contact's own account unless another was explicitly requested
+	account, err := a.resolveSendingWhatsAppAccount(orgID, contactAccountName(&contact, req.WhatsAppAccount))
 	if err != nil {
-		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Failed to resolve WhatsApp account", nil, "")
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	// Media is fetched from its URL now and uploaded to WhatsApp when sent
+	if isMedia {
+		data, mimeType, err := a.fetchOutgoingMedia(req.Media.URL)
+		if err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+		}
+		if err := validateOutgoingMedia(req.Type, mimeType, int64(len(data))); err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+		}
+		filename := strings.TrimSpace(req.Media.Filename)
+		if filename == "" {
+			filename = mediaFilenameFromURL(req.Media.URL)
+		}
+		return a.sendMediaToContact(r, userID, account, &contact, OutgoingMessageRequest{
+			Type:          req.Type,
+			MediaData:     data,
+			MediaMimeType: mimeType,
+			MediaFilename: filename,
+			Caption:       req.Media.Caption,
+		})
 	}
 
 	// Handle reply context
@@ -593,21 +764,7 @@ func (a *App) SendMessage(r *fastglue.Request) error {
 
 	// Handle interactive messages
 	if req.Type == models.MessageTypeInteractive && req.Interactive != nil {
-		msgReq.InteractiveType = req.Interactive.Type
-		msgReq.BodyText = req.Interactive.Body
-		msgReq.ButtonText = req.Interactive.ButtonText
-		msgReq.URL = req.Interactive.URL
-
-		// Convert buttons
-		if len(req.Interactive.Buttons) > 0 {
-			msgReq.Buttons = make([]whatsapp.Button, len(req.Interactive.Buttons))
-			for i, btn := range req.Interactive.Buttons {
-				msgReq.Buttons[i] = whatsapp.Button{
-					ID:    btn.ID,
-					Title: btn.Title,
-				}
-			}
-		}
+		applyInteractiveContent(&msgReq, req.Interactive)
 	}
 
 	opts := DefaultSendOptions()
@@ -649,29 +806,109 @@ func (a *App) SendMessage(r *fastglue.Request) error {
 	return r.SendEnvelope(response)
 }
 
-// resolveWhatsAppAccount gets the WhatsApp account for sending messages
-func (a *App) resolveWhatsAppAccount(orgID uuid.UUID, accountName string) (*models.WhatsAppAccount, error) {
-	var account models.WhatsAppAccount
+// applyInteractiveContent copies interactive message data onto an outgoing message request
+func applyInteractiveContent(msgReq *OutgoingMessageRequest, interactive *InteractiveContent) {
+	msgReq.InteractiveType = interactive.Type
+	msgReq.BodyText = interactive.Body
+	msgReq.ButtonText = interactive.ButtonText
+	msgReq.URL = interactive.URL
+
+	// Convert buttons
+	if len(interactive.Buttons) > 0 {
+		msgReq.Buttons = make([]whatsapp.Button, len(interactive.Buttons))
+		for i, btn := range interactive.Buttons {
+			msgReq.Buttons[i] = whatsapp.Button{
+				ID:    btn.ID,
+				Title: btn.Title,
+			}
+		}
+	}
+}
 
-	if accountName != "" {
-		if err := a.DB.Where("name = ? AND organization_id = ?", accountName, orgID).First(&account).Error; err != nil {
-			return nil, fmt.Errorf("WhatsApp account not found")
+// resolveWhatsAppAccount gets the WhatsApp account for sending messages: the
+// named account, or when accountName is empty the organization's default,
+// falling back to any of its accounts. Messages a user sends go through
+// resolveSendingWhatsAppAccount instead, which never guesses.
+func (a *App) resolveWhatsAppAccount(orgID uuid.UUID, accountName string) (*models.WhatsAppAccount, error) {
+	if accountName == "" {
+		if account, err := a.defaultWhatsAppAccount(orgID); err == nil {
+			return account, nil
+		}
+		var account models.WhatsAppAccount
+		if err := a.DB.Where("organization_id = ?", orgID).Order("created_at ASC").First(&account).Error; err != nil {
+			return nil, fmt.Errorf("no WhatsApp account configured")
 		}
 		a.decryptAccountSecrets(&account)
 		return &account, nil
 	}
 
-	// Get default outgoing account
-	if err := a.DB.Where("organization_id = ? AND is_default_outgoing = ?", orgID, true).First(&account).Error; err != nil {
-		// Fall back to any account
-		if err := a.DB.Where("organization_id = ?", orgID).First(&account).Error; err != nil {
-			return nil, fmt.Errorf("no WhatsApp account configured")
-		}
+	var account models.WhatsAppAccount
+	if err := a.DB.Where("name = ? AND organization_id = ?", accountName, orgID).First(&account).Error; err != nil {
+		return nil, fmt.Errorf("WhatsApp account %q not found", accountName)
 	}
 	a.decryptAccountSecrets(&account)
 	return &account, nil
 }
 
+// resolveSendingWhatsAppAccount gets the account for a message sent through
+// the messages API: the named account, or the organization's default when
+// accountName is empty. Unlike resolveWhatsAppAccount it fails rather than
+// pick one of several accounts, and the error says why.
+func (a *App) resolveSendingWhatsAppAccount(orgID uuid.UUID, accountName string) (*models.WhatsAppAccount, error) {
+	if accountName == "" {
+		return a.defaultWhatsAppAccount(orgID)
+	}
+	return a.resolveWhatsAppAccount(orgID, accountName)
+}
+
+// defaultWhatsAppAccount picks the account to send from when none is named.
+// In order: the default_whatsapp_account organization setting, the account
+// marked default outgoing, then the organization's only account. The error
+// says why no account could be chosen.
+func (a *App) defaultWhatsAppAccount(orgID uuid.UUID) (*models.WhatsAppAccount, error) {
+	var org models.Organization
+	if err := a.DB.Select("id", "settings").Where("id = ?", orgID).First(&org).Error; err == nil {
+		if name := org.DefaultWhatsAppAccount(); name != "" {
+			var account models.WhatsAppAccount
+			if err := a.DB.Where("name = ? AND organization_id = ?", name, orgID).First(&account).Error; err != nil {
+				return nil, fmt.Errorf("default WhatsApp account %q no longer exists; choose another in organization settings", name)
+			}
+			a.decryptAccountSecrets(&account)
+			return &account, nil
+		}
+	}
+
+	var accounts []models.WhatsAppAccount
+	if err := a.DB.Where("organization_id = ?", orgID).Order("created_at ASC").Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to load WhatsApp accounts")
+	}
+	for i := range accounts {
+		if accounts[i].IsDefaultOutgoing {
+			a.decryptAccountSecrets(&accounts[i])
+			return &accounts[i], nil
+		}
+	}
+	switch len(accounts) {
+	case 0:
+		return nil, fmt.Errorf("no WhatsApp account configured")
+	case 1:
+		a.decryptAccountSecrets(&accounts[0])
+		return &accounts[0], nil
+	default:
+		return nil, fmt.Errorf("no default WhatsApp account: the organization has %d accounts, so name one or set a default account", len(accounts))
+	}
+}
+
+// contactAccountName returns the account to message a contact from: the
+// explicitly requested one if given, otherwise the account the contact last
+// wrote to. An empty result falls back to the org's default outgoing account.
+func contactAccountName(contact *models.Contact, requested string) string {
+	if requested = strings.TrimSpace(requested); requested != "" {
+		return requested
+	}
+	return contact.WhatsAppAccount
+}
+
 // resolveWhatsAppAccountByID fetches a WhatsApp account by UUID and org, decrypts secrets.
 func (a *App) resolveWhatsAppAccountByID(r *fastglue.Request, id, orgID uuid.UUID) (*models.WhatsAppAccount, error) {
 	account, err := findByIDAndOrg[models.WhatsAppAccount](a.DB, r, id, orgID, "Account")
@@ -712,10 +949,18 @@ func (a *App) SendMediaMessage(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid contact ID", nil, "")
 	}
 
+	return a.sendUploadedMedia(r, orgID, userID, contactID, form)
+}
+
+// sendUploadedMedia sends the file uploaded in a multipart form to a contact
+func (a *App) sendUploadedMedia(r *fastglue.Request, orgID, userID, contactID uuid.UUID, form *multipart.Form) error {
 	// Get media type (image, document, video, audio)
-	mediaType := "image"
+	mediaType := models.MessageTypeImage
 	if typeValues := form.Value["type"]; len(typeValues) > 0 {
-		mediaType = typeValues[0]
+		mediaType = models.MessageType(typeValues[0])
+	}
+	if !isMediaMessageType(mediaType) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "type must be one of: image, video, audio, document", nil, "")
 	}
 
 	// Get caption (optional)
@@ -751,49 +996,53 @@ func (a *App) SendMediaMessage(r *fastglue.Request) error {
 	}
 
 	// Get MIME type
-	mimeType := fileHeader.Header.Get("Content-Type")
-	if mimeType == "" {
-		mimeType = "application/octet-stream"
+	mimeType := mediaMimeType(fileHeader.Header.Get("Content-Type"), fileData)
+	if err := validateOutgoingMedia(mediaType, mimeType, int64(len(fileData))); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
 	}
 
 	// Get contact (users without full read permission can only message their assigned contacts)
 	var contact models.Contact
-	query := a.DB.Where("id = ? AND organization_id = ?", contactID, orgID)
-	if !a.HasPermission(userID, models.ResourceContacts, models.ActionRead, orgID) {
-		query = query.Where("assigned_user_id = ?", userID)
-	}
+	query := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID)
 	if err := query.First(&contact).Error; err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
 	}
-
-	// Get WhatsApp account - prefer form-specified account over contact default
-	mediaAccountName := contact.WhatsAppAccount
-	if formWhatsAppAccount != "" {
-		mediaAccountName = formWhatsAppAccount
+	if a.isPhoneBlacklisted(orgID, contact.PhoneNumber) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Phone number is blacklisted", nil, "")
 	}
-	account, err := a.resolveWhatsAppAccount(orgID, mediaAccountName)
+	if contact.Blocked {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Contact is blocked", nil, "")
+	}
+
+	// Reply on the contact's own account unless another was explicitly requested
+	account, err := a.resolveSendingWhatsAppAccount(orgID, contactAccountName(&contact, formWhatsAppAccount))
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
 	}
 
+	return a.sendMediaToContact(r, userID, account, &contact, OutgoingMessageRequest{
+		Type:          mediaType,
+		MediaData:     fileData,
+		MediaMimeType: mimeType,
+		MediaFilename: fileHeader.Filename,
+		Caption:       caption,
+	})
+}
+
+// sendMediaToContact stores the media in msgReq locally, sends it and
+// responds with the created message
+func (a *App) sendMediaToContact(r *fastglue.Request, userID uuid.UUID, account *models.WhatsAppAccount, contact *models.Contact, msgReq OutgoingMessageRequest) error {
 	// Save file locally first
-	localPath, err := a.saveMediaLocally(fileData, mimeType, fileHeader.Filename)
+	localPath, err := a.saveMediaLocally(msgReq.MediaData, msgReq.MediaMimeType, msgReq.MediaFilename)
 	if err != nil {
 		a.Log.Error("Failed to save media locally", "error", err)
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to save media", nil, "")
 	}
 
 	// Build and send via unified message sender
-	msgReq := OutgoingMessageRequest{
-		Account:       account,
-		Contact:       &contact,
-		Type:          models.MessageType(mediaType),
-		MediaData:     fileData,
-		MediaURL:      localPath,
-		MediaMimeType: mimeType,
-		MediaFilename: fileHeader.Filename,
-		Caption:       caption,
-	}
+	msgReq.Account = account
+	msgReq.Contact = contact
+	msgReq.MediaURL = localPath
 
 	opts := DefaultSendOptions()
 	opts.SentByUserID = &userID
@@ -900,13 +1149,13 @@ func (a *App) SendReaction(r *fastglue.Request) error {
 
 	// Get contact (users without full read permission can only react to messages in their assigned contacts)
 	var contact models.Contact
-	query := a.DB.Where("id = ? AND organization_id = ?", contactID, orgID)
-	if !a.HasPermission(userID, models.ResourceContacts, models.ActionRead, orgID) {
-		query = query.Where("assigned_user_id = ?", userID)
-	}
+	query := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID)
 	if err := query.First(&contact).Error; err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
 	}
+	if contact.Blocked {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Contact is blocked", nil, "")
+	}
 
 	// Get message
 	var message models.Message
@@ -983,6 +1232,16 @@ func (a *App) SendReaction(r *fastglue.Request) error {
 	// Send reaction to WhatsApp API
 	go a.sendWhatsAppReaction(account, &contact, &message, req.Emoji)
 
+	// Notify webhook subscribers (empty emoji means the reaction was removed)
+	a.DispatchWebhook(orgID, models.WebhookEventMessageReaction, ReactionEventData{
+		MessageID:       message.ID.String(),
+		ContactID:       contact.ID.String(),
+		ContactPhone:    contact.PhoneNumber,
+		Emoji:           req.Emoji,
+		ActorUserID:     userIDStr,
+		WhatsAppAccount: account.Name,
+	})
+
 	// Broadcast via WebSocket
 	if a.WSHub != nil {
 		a.WSHub.BroadcastToOrg(orgID, websocket.WSMessage{
@@ -1052,6 +1311,82 @@ func (a *App) sendWhatsAppReaction(account *models.WhatsAppAccount, contact *mod
 	a.Log.Info("Reaction sent successfully", "message_id", message.WhatsAppMessageID, "emoji", emoji)
 }
 
+// messageRevokeWindow is how long after sending a message can still be deleted for everyone
+const messageRevokeWindow = 48 * time.Hour
+
+// DeleteMessage soft-deletes a message so it no longer shows in the conversation.
+// With ?revoke=true, recent outgoing messages are also deleted on WhatsApp for the recipient.
+func (a *App) DeleteMessage(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+	messageID, err := parsePathUUID(r, "message_id", "message")
+	if err != nil {
+		return nil
+	}
+
+	revoke := r.RequestCtx.QueryArgs().GetBool("revoke")
+
+	// Get contact (users without full read permission can only delete messages in their assigned contacts)
+	var contact models.Contact
+	query := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID)
+	if err := query.First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	var message models.Message
+	if err := a.DB.Where("id = ? AND contact_id = ? AND organization_id = ?", messageID, contactID, orgID).
+		First(&message).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Message not found", nil, "")
+	}
+
+	// Only our own recent messages can be deleted for everyone
+	revoked := false
+	if revoke && message.Direction == models.DirectionOutgoing && message.WhatsAppMessageID != "" &&
+		time.Since(message.CreatedAt) <= messageRevokeWindow {
+		account, err := a.resolveWhatsAppAccount(orgID, message.WhatsAppAccount)
+		if err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := a.WhatsApp.DeleteMessage(ctx, a.toWhatsAppAccount(account), message.WhatsAppMessageID); err != nil {
+			a.Log.Error("Failed to revoke message on WhatsApp", "error", err, "message_id", message.ID)
+			return r.SendErrorEnvelope(fasthttp.StatusBadGateway, "Failed to delete message on WhatsApp", nil, "")
+		}
+		revoked = true
+	}
+
+	if err := a.DB.Delete(&message).Error; err != nil {
+		a.Log.Error("Failed to delete message", "error", err, "message_id", message.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete message", nil, "")
+	}
+
+	// Broadcast via WebSocket
+	if a.WSHub != nil {
+		a.WSHub.BroadcastToOrg(orgID, websocket.WSMessage{
+			Type: websocket.TypeMessageDeleted,
+			Payload: map[string]any{
+				"message_id": message.ID.String(),
+				"contact_id": contact.ID.String(),
+			},
+		})
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"message_id": message.ID.String(),
+		"revoked":    revoked,
+		"message":    "Message deleted successfully",
+	})
+}
+
 // AssignContactRequest represents the request to assign a contact to a user
 type AssignContactRequest struct {
 	UserID *uuid.UUID `json:"user_id"` // nil to unassign
@@ -1094,8 +1429,15 @@ func (a *App) AssignContact(r *fastglue.Request) error {
 		}
 	}
 
-	// Update contact assignment
-	if err := a.DB.Model(contact).Update("assigned_user_id", req.UserID).Error; err != nil {
+	// Update contact assignment and record the handoff together
+	previousUserID := contact.AssignedUserID
+	err = a.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(contact).Update("assigned_user_id", req.UserID).Error; err != nil {
+			return err
+		}
+		return recordContactAssignment(tx, orgID, contact.ID, previousUserID, req.UserID, userID)
+	})
+	if err != nil {
 		a.Log.Error("Failed to assign contact", "error", err)
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to assign contact", nil, "")
 	}
@@ -1106,6 +1448,73 @@ func (a *App) AssignContact(r *fastglue.Request) error {
 	})
 }
 
+// GetContactAssignmentHistory returns a contact's assignment changes, newest first
+func (a *App) GetContactAssignmentHistory(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	// Users without full read permission can only see their assigned contacts
+	var contact models.Contact
+	query := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID)
+	if err := query.First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	pg := parsePagination(r)
+	historyQuery := a.DB.Model(&models.ContactAssignment{}).
+		Where("contact_id = ? AND organization_id = ?", contactID, orgID)
+
+	var total int64
+	historyQuery.Count(&total)
+
+	var assignments []models.ContactAssignment
+	if err := pg.Apply(historyQuery.
+		Preload("FromUser", selectAssignedUser).
+		Preload("ToUser", selectAssignedUser).
+		Preload("AssignedBy", selectAssignedUser).
+		Order("created_at DESC")).
+		Find(&assignments).Error; err != nil {
+		a.Log.Error("Failed to list contact assignments", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list assignment history", nil, "")
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"assignments": assignments,
+		"total":       total,
+		"page":        pg.Page,
+		"limit":       pg.Limit,
+	})
+}
+
+// recordContactAssignment adds a history entry for a change of a contact's
+// assigned user. Nothing is recorded when the assignee is unchanged.
+func recordContactAssignment(tx *gorm.DB, orgID, contactID uuid.UUID, from, to *uuid.UUID, byUserID uuid.UUID) error {
+	if uuidPtrEqual(from, to) {
+		return nil
+	}
+	return tx.Create(&models.ContactAssignment{
+		OrganizationID: orgID,
+		ContactID:      contactID,
+		FromUserID:     from,
+		ToUserID:       to,
+		AssignedByID:   byUserID,
+	}).Error
+}
+
+// uuidPtrEqual reports whether two optional IDs are both unset or equal
+func uuidPtrEqual(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // ContactSessionDataResponse represents the session data for a contact's info panel
 type ContactSessionDataResponse struct {
 	SessionID   *uuid.UUID     `json:"session_id,omitempty"`
@@ -1116,7 +1525,8 @@ type ContactSessionDataResponse struct {
 }
 
 // GetContactSessionData returns session data and panel configuration for a contact
-// Used by the contact info panel in the chat view
+// Used by the contact info panel in the chat view. Values stored by sensitive
+// steps are masked unless the user has pii:read.
 func (a *App) GetContactSessionData(r *fastglue.Request) error {
 	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
@@ -1129,10 +1539,7 @@ func (a *App) GetContactSessionData(r *fastglue.Request) error {
 
 	// Verify contact belongs to org (users without full read permission can only access assigned contacts)
 	var contact models.Contact
-	query := a.DB.Where("id = ? AND organization_id = ?", contactID, orgID)
-	if !a.HasPermission(userID, models.ResourceContacts, models.ActionRead, orgID) {
-		query = query.Where("assigned_user_id = ?", userID)
-	}
+	query := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID)
 	if err := query.First(&contact).Error; err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
 	}
@@ -1199,6 +1606,9 @@ func (a *App) GetContactSessionData(r *fastglue.Request) error {
 								response.SessionData[key] = val
 							}
 						}
+						if !a.HasPermission(userID, models.ResourcePII, models.ActionRead, orgID) {
+							response.SessionData = redactSessionData(response.SessionData, a.sensitiveSessionKeys(*flowID))
+						}
 					}
 				}
 			}
@@ -1304,15 +1714,15 @@ func (a *App) CreateContact(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "phone_number is required", nil, "")
 	}
 
-	// Normalize phone number
-	normalizedPhone := req.PhoneNumber
-	if len(normalizedPhone) > 0 && normalizedPhone[0] == '+' {
-		normalizedPhone = normalizedPhone[1:]
+	// Normalize to E.164 digits so differently formatted numbers map to one contact
+	normalizedPhone, err := contactutil.ValidatePhone(req.PhoneNumber, a.Config.WhatsApp.DefaultPhoneRegion)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
 	}
 
-	// Check if contact exists (including soft-deleted)
-	var existingContact models.Contact
-	if err := a.DB.Unscoped().Where("organization_id = ? AND phone_number = ?", orgID, normalizedPhone).First(&existingContact).Error; err == nil {
+	// Check if contact exists (including soft-deleted) in any stored format
+	if found, err := contactutil.FindContactByPhone(a.DB, orgID, normalizedPhone); err == nil {
+		existingContact := *found
 		// Contact exists
 		if existingContact.DeletedAt.Valid {
 			// Restore soft-deleted contact
@@ -1489,6 +1899,287 @@ func (a *App) DeleteContact(r *fastglue.Request) error {
 	})
 }
 
+// filterContactsFromQueryArgs applies the contact list's search, tags,
+// custom field and conversation status query parameters
+func filterContactsFromQueryArgs(query *gorm.DB, args *fasthttp.Args) *gorm.DB {
+	search := string(args.Peek("search"))
+	tagsParam := string(args.Peek("tags"))
+	customField := string(args.Peek("custom_field"))
+	customValue := string(args.Peek("custom_value"))
+	statusParam := string(args.Peek("conversation_status"))
+	blockedParam := string(args.Peek("blocked"))
+
+	query = searchContacts(query, search)
+
+	// Filter by tags (comma-separated, matches contacts that have ANY of the specified tags)
+	if tagsParam != "" {
+		query = filterContactsByAnyTag(query, strings.Split(tagsParam, ","))
+	}
+
+	// Filter by a custom field value, compared as text (e.g. custom_field=plan&custom_value=pro)
+	if customField != "" {
+		query = query.Where("custom_fields ->> ? = ?", customField, customValue)
+	}
+
+	// Filter by conversation status (comma-separated, matches ANY)
+	if statusParam != "" {
+		query = filterContactsByConversationStatus(query, strings.Split(statusParam, ","), time.Now())
+	}
+
+	// blocked=true lists only blocked contacts, blocked=false leaves them out
+	if blocked, err := strconv.ParseBool(blockedParam); err == nil {
+		query = query.Where("blocked = ?", blocked)
+	}
+	return query
+}
+
+// contactSortColumns are the columns ListContacts can sort by
+var contactSortColumns = map[string]bool{
+	"last_message_at": true,
+	"last_inbound_at": true,
+	"created_at":      true,
+}
+
+// contactsOrder returns the ORDER BY clause for a contacts sort column and
+// direction, defaulting to the newest conversation first. Contacts with no
+// value sort last either way, and created_at breaks ties.
+func contactsOrder(sort, order string) (string, bool) {
+	if sort == "" {
+		sort = "last_message_at"
+	}
+	if order == "" {
+		order = "desc"
+	}
+	order = strings.ToLower(order)
+	if !contactSortColumns[sort] || (order != "asc" && order != "desc") {
+		return "", false
+	}
+	if sort == "created_at" {
+		return "created_at " + strings.ToUpper(order), true
+	}
+	return fmt.Sprintf("%s %s NULLS LAST, created_at DESC", sort, strings.ToUpper(order)), true
+}
+
+// filterContactsByConversationStatus restricts a contacts query to the given
+// conversation statuses. Snoozes that have run out count as open, matching
+// what the contact response reports before the SLA processor reopens them.
+func filterContactsByConversationStatus(query *gorm.DB, statuses []string, now time.Time) *gorm.DB {
+	conditions := make([]string, 0, len(statuses))
+	args := make([]any, 0, len(statuses))
+	for _, status := range statuses {
+		switch models.ConversationStatus(strings.TrimSpace(status)) {
+		case models.ConversationStatusOpen:
+			conditions = append(conditions, "conversation_status = ? OR conversation_status = '' OR (conversation_status = ? AND (snooze_until IS NULL OR snooze_until <= ?))")
+			args = append(args, models.ConversationStatusOpen, models.ConversationStatusSnoozed, now)
+		case models.ConversationStatusSnoozed:
+			conditions = append(conditions, "(conversation_status = ? AND snooze_until > ?)")
+			args = append(args, models.ConversationStatusSnoozed, now)
+		case models.ConversationStatusPending, models.ConversationStatusResolved:
+			conditions = append(conditions, "conversation_status = ?")
+			args = append(args, strings.TrimSpace(status))
+		}
+	}
+	if len(conditions) == 0 {
+		// Only unknown statuses were requested, so nothing can match
+		return query.Where("1 = 0")
+	}
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// searchContacts matches contacts by phone number or profile name. Searches
+// that look like a phone number also match regardless of formatting, so
+// "+1 (555) 010" finds a contact stored as "15550102030".
+func searchContacts(query *gorm.DB, search string) *gorm.DB {
+	if search == "" {
+		return query
+	}
+	// Limit search string length to prevent abuse
+	if len(search) > 1000 {
+		search = search[:1000]
+	}
+	searchPattern := "%" + search + "%"
+	if digits := contactutil.PhoneSearchDigits(search); digits != "" {
+		return query.Where("phone_number LIKE ? OR "+contactutil.PhoneDigitsExpr+" LIKE ? OR profile_name ILIKE ?",
+			searchPattern, "%"+digits+"%", searchPattern)
+	}
+	// Use ILIKE for case-insensitive search on profile_name
+	return query.Where("phone_number LIKE ? OR profile_name ILIKE ?", searchPattern, searchPattern)
+}
+
+// filterContactsByAnyTag restricts a contacts query to contacts having ANY of the given tags
+func filterContactsByAnyTag(query *gorm.DB, tagList []string) *gorm.DB {
+	// Trim whitespace from each tag and build OR conditions
+	// Using @> operator which leverages the GIN index on tags
+	conditions := make([]string, 0, len(tagList))
+	args := make([]any, 0, len(tagList))
+	for _, tag := range tagList {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			// Use proper JSONB containment with explicit cast
+			conditions = append(conditions, "tags @> ?::jsonb")
+			tagJSON, _ := json.Marshal([]string{tag})
+			args = append(args, string(tagJSON))
+		}
+	}
+	if len(conditions) > 0 {
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+	return query
+}
+
+// MaxBulkDeleteContacts is the maximum number of contacts a single bulk delete may remove
+const MaxBulkDeleteContacts = 500
+
+var errBulkDeleteCapExceeded = errors.New("bulk delete cap exceeded")
+
+// BulkDeleteContactsRequest selects contacts to delete either by ID or by filter
+type BulkDeleteContactsRequest struct {
+	ContactIDs []uuid.UUID         `json:"contact_ids"`
+	Filter     *BulkContactsFilter `json:"filter"`
+	Confirm    bool                `json:"confirm"` // Must be true, guards against accidental deletes
+}
+
+// BulkContactsFilter matches contacts for bulk operations
+type BulkContactsFilter struct {
+	Search             string     `json:"search"`
+	Tags               []string   `json:"tags"`
+	WhatsAppAccount    string     `json:"whatsapp_account"`
+	AssignedUserID     *uuid.UUID `json:"assigned_user_id,omitempty"`
+	ConversationStatus []string   `json:"conversation_status,omitempty"` // Matches ANY of the statuses
+}
+
+// isEmpty reports whether the filter would match every contact in the organization
+func (f *BulkContactsFilter) isEmpty() bool {
+	if f == nil {
+		return true
+	}
+	for _, tag := range f.Tags {
+		if strings.TrimSpace(tag) != "" {
+			return false
+		}
+	}
+	for _, status := range f.ConversationStatus {
+		if strings.TrimSpace(status) != "" {
+			return false
+		}
+	}
+	return strings.TrimSpace(f.Search) == "" && f.WhatsAppAccount == "" && f.AssignedUserID == nil
+}
+
+// apply adds the filter's conditions to a contacts query
+func (f *BulkContactsFilter) apply(db *gorm.DB) *gorm.DB {
+	db = searchContacts(db, strings.TrimSpace(f.Search))
+	if len(f.Tags) > 0 {
+		db = filterContactsByAnyTag(db, f.Tags)
+	}
+	if f.WhatsAppAccount != "" {
+		db = db.Where("whats_app_account = ?", f.WhatsAppAccount)
+	}
+	if f.AssignedUserID != nil {
+		db = db.Where("assigned_user_id = ?", *f.AssignedUserID)
+	}
+	if len(f.ConversationStatus) > 0 {
+		db = filterContactsByConversationStatus(db, f.ConversationStatus, time.Now())
+	}
+	return db
+}
+
+// BulkDeleteContacts soft-deletes contacts matched by ID list or filter
+func (a *App) BulkDeleteContacts(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceContacts, models.ActionDelete, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "You do not have permission to delete contacts", nil, "")
+	}
+
+	var req BulkDeleteContactsRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	hasIDs := len(req.ContactIDs) > 0
+	hasFilter := !req.Filter.isEmpty()
+	if hasIDs == hasFilter {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Provide either contact_ids or a non-empty filter", nil, "")
+	}
+	if len(req.ContactIDs) > MaxBulkDeleteContacts {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			fmt.Sprintf("At most %d contacts can be deleted at once", MaxBulkDeleteContacts), nil, "")
+	}
+
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("organization_id = ?", orgID)
+		if hasIDs {
+			return db.Where("id IN ?", req.ContactIDs)
+		}
+		return req.Filter.apply(db)
+	}
+
+	var matched int64
+	if err := a.DB.Model(&models.Contact{}).Scopes(scope).Count(&matched).Error; err != nil {
+		a.Log.Error("Failed to count contacts for bulk delete", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete contacts", nil, "")
+	}
+	if matched > MaxBulkDeleteContacts {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			fmt.Sprintf("%d contacts matched; at most %d can be deleted at once", matched, MaxBulkDeleteContacts), nil, "")
+	}
+
+	// Without confirmation, only report how many contacts would be deleted
+	if !req.Confirm {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			fmt.Sprintf("Confirmation required to delete %d contacts", matched), map[string]any{"matched": matched}, "")
+	}
+
+	var deleted int64
+	err = a.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Scopes(scope).Delete(&models.Contact{})
+		if result.Error != nil {
+			return result.Error
+		}
+		// Contacts may have been added since the count; never exceed the cap
+		if result.RowsAffected > MaxBulkDeleteContacts {
+			return errBulkDeleteCapExceeded
+		}
+		deleted = result.RowsAffected
+		return nil
+	})
+	if errors.Is(err, errBulkDeleteCapExceeded) {
+		return r.SendErrorEnvelope(fasthttp.StatusConflict,
+			fmt.Sprintf("More than %d contacts matched; nothing was deleted", MaxBulkDeleteContacts), nil, "")
+	}
+	if err != nil {
+		a.Log.Error("Failed to bulk delete contacts", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete contacts", nil, "")
+	}
+
+	a.Log.Info("Contacts bulk deleted", "org_id", orgID, "user_id", userID, "deleted", deleted)
+
+	return r.SendEnvelope(map[string]any{
+		"deleted": deleted,
+		"message": fmt.Sprintf("%d contacts deleted successfully", deleted),
+	})
+}
+
+// selectAssignedUser limits the assigned user preload to the fields shown on contacts
+func selectAssignedUser(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "full_name", "is_available")
+}
+
+// assignedUserSummary returns the assigned agent's name and availability,
+// or nils when the contact is unassigned or the user could not be loaded
+func assignedUserSummary(contact *models.Contact) (*string, *bool) {
+	if contact.AssignedUserID == nil || contact.AssignedUser == nil {
+		return nil, nil
+	}
+	name := contact.AssignedUser.FullName
+	available := contact.AssignedUser.IsAvailable
+	return &name, &available
+}
+
 // buildContactResponse creates a ContactResponse from a Contact model
 func (a *App) buildContactResponse(contact *models.Contact, orgID uuid.UUID) ContactResponse {
 	// Count unread messages
@@ -1517,22 +2208,85 @@ func (a *App) buildContactResponse(contact *models.Contact, orgID uuid.UUID) Con
 	// 24-hour service window: open if customer messaged within the last 24 hours.
 	serviceWindowOpen := contact.LastInboundAt != nil && time.Since(*contact.LastInboundAt) < 24*time.Hour
 
+	if contact.AssignedUserID != nil && (contact.AssignedUser == nil || contact.AssignedUser.ID != *contact.AssignedUserID) {
+		var user models.User
+		if err := selectAssignedUser(a.DB).Where("id = ?", *contact.AssignedUserID).First(&user).Error; err == nil {
+			contact.AssignedUser = &user
+		}
+	}
+	assignedName, assignedAvail := assignedUserSummary(contact)
+
+	// An expired pause reads as resumed even before inbound processing clears it
+	botPaused := isBotPaused(contact, time.Now())
+	var botResumeAt *time.Time
+	if botPaused {
+		botResumeAt = contact.BotResumeAt
+	}
+	convStatus, snoozeUntil := conversationStatus(contact, time.Now())
+
 	return ContactResponse{
-		ID:                 contact.ID,
-		PhoneNumber:        phoneNumber,
-		Name:               profileName,
-		ProfileName:        profileName,
-		Status:             "active",
-		Tags:               tags,
-		Metadata:           contact.Metadata,
-		LastMessageAt:      contact.LastMessageAt,
-		LastMessagePreview: contact.LastMessagePreview,
-		UnreadCount:        int(unreadCount),
-		AssignedUserID:     contact.AssignedUserID,
-		WhatsAppAccount:    contact.WhatsAppAccount,
-		LastInboundAt:      contact.LastInboundAt,
-		ServiceWindowOpen:  serviceWindowOpen,
-		CreatedAt:          contact.CreatedAt,
-		UpdatedAt:          contact.UpdatedAt,
+		ID:                   contact.ID,
+		PhoneNumber:          phoneNumber,
+		Name:                 profileName,
+		ProfileName:          profileName,
+		Status:               "active",
+		Tags:                 tags,
+		Metadata:             contact.Metadata,
+		CustomFields:         contactCustomFields(contact),
+		LastMessageAt:        contact.LastMessageAt,
+		LastMessagePreview:   contact.LastMessagePreview,
+		LastMessageDirection: contact.LastMessageDirection,
+		UnreadCount:          int(unreadCount),
+		AssignedUserID:       contact.AssignedUserID,
+		AssignedUserName:     assignedName,
+		AssignedUserAvail:    assignedAvail,
+		WhatsAppAccount:      contact.WhatsAppAccount,
+		LastInboundAt:        contact.LastInboundAt,
+		ServiceWindowOpen:    serviceWindowOpen,
+		BotPaused:            botPaused,
+		BotResumeAt:          botResumeAt,
+		ConversationStatus:   convStatus,
+		SnoozeUntil:          snoozeUntil,
+		OptedOut:             contact.OptedOut,
+		OptedOutAt:           contact.OptedOutAt,
+		OptInAt:              contact.OptInAt,
+		OptInSource:          contact.OptInSource,
+		OptInMethod:          contact.OptInMethod,
+		Blocked:              contact.Blocked,
+		BlockedAt:            contact.BlockedAt,
+		FloodFlaggedAt:       contact.FloodFlaggedAt,
+		CreatedAt:            contact.CreatedAt,
+		UpdatedAt:            contact.UpdatedAt,
+	}
+}
+
+// contactVisibility reports whether the user only sees their own contacts in
+// the organization and, if so, whether unassigned contacts are visible too.
+// Users without contacts:read and users whose role sets contacts_own_only are
+// limited; super admins never are.
+func (a *App) contactVisibility(userID, orgID uuid.UUID) (ownOnly, includeUnassigned bool) {
+	perms, err := a.getUserPermissionsCached(userID, orgID)
+	if err != nil {
+		a.Log.Error("Failed to get user permissions", "error", err, "user_id", userID)
+		return true, false
+	}
+	if perms.IsSuperAdmin {
+		return false, false
+	}
+	if perms.ContactsOwnOnly || !a.HasPermission(userID, models.ResourceContacts, models.ActionRead, orgID) {
+		return true, perms.ContactsIncludeUnassigned
+	}
+	return false, false
+}
+
+// scopeContactsQuery limits a contacts query to the contacts the user may see
+func (a *App) scopeContactsQuery(query *gorm.DB, userID, orgID uuid.UUID) *gorm.DB {
+	ownOnly, includeUnassigned := a.contactVisibility(userID, orgID)
+	if !ownOnly {
+		return query
+	}
+	if includeUnassigned {
+		return query.Where("(assigned_user_id = ? OR assigned_user_id IS NULL)", userID)
 	}
+	return query.Where("assigned_user_id = ?", userID)
 }
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// bulkStatusBatchSize is the number of contacts updated per transaction
+const bulkStatusBatchSize = 500
+
+// MaxBulkStatusContactIDs is the maximum number of contact IDs a bulk
+// conversation status request may list
+const MaxBulkStatusContactIDs = 10000
+
+// BulkUpdateConversationStatusRequest moves the conversations of contacts
+// selected either by ID or by filter to a new status
+type BulkUpdateConversationStatusRequest struct {
+	ContactIDs  []uuid.UUID               `json:"contact_ids"`
+	Filter      *BulkContactsFilter       `json:"filter"`
+	Status      models.ConversationStatus `json:"status"`
+	SnoozeUntil *time.Time                `json:"snooze_until"`
+}
+
+// BulkUpdateConversationStatusResponse reports the outcome of a bulk
+// conversation status change
+type BulkUpdateConversationStatusResponse struct {
+	Matched    int64 `json:"matched"`
+	Updated    int64 `json:"updated"`
+	Unassigned int64 `json:"unassigned"`
+}
+
+// BulkUpdateConversationStatus changes the conversation status of many
+// contacts at once, for example to resolve everything left over after a
+// campaign. Contacts are processed in batches so a large selection doesn't
+// hold one long transaction. Users limited to their own contacts only
+// change those.
+func (a *App) BulkUpdateConversationStatus(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceChat, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	var req BulkUpdateConversationStatusRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	hasIDs := len(req.ContactIDs) > 0
+	hasFilter := !req.Filter.isEmpty()
+	if hasIDs == hasFilter {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Provide either contact_ids or a non-empty filter", nil, "")
+	}
+	if len(req.ContactIDs) > MaxBulkStatusContactIDs {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			fmt.Sprintf("At most %d contact_ids can be updated at once", MaxBulkStatusContactIDs), nil, "")
+	}
+	if errMsg := validateConversationStatus(req.Status, req.SnoozeUntil, time.Now()); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = a.scopeContactsQuery(db.Where("organization_id = ?", orgID), userID, orgID)
+		if hasIDs {
+			return db.Where("id IN ?", req.ContactIDs)
+		}
+		return req.Filter.apply(db)
+	}
+
+	// Whether resolving unassigns depends on each account's chatbot settings
+	unassignByAccount := map[string]bool{}
+	unassigns := func(account string) bool {
+		if req.Status != models.ConversationStatusResolved {
+			return false
+		}
+		v, ok := unassignByAccount[account]
+		if !ok {
+			v = a.unassignsOnResolve(orgID, account)
+			unassignByAccount[account] = v
+		}
+		return v
+	}
+
+	var resp BulkUpdateConversationStatusResponse
+	var lastID uuid.UUID
+	for {
+		// Keyset pagination on id, since filtering by status means updated
+		// contacts drop out of the selection
+		query := a.DB.Model(&models.Contact{}).Select("id", "assigned_user_id", "whats_app_account").
+			Scopes(scope).Order("id ASC").Limit(bulkStatusBatchSize)
+		if lastID != uuid.Nil {
+			query = query.Where("id > ?", lastID)
+		}
+		var batch []models.Contact
+		if err := query.Find(&batch).Error; err != nil {
+			a.Log.Error("Failed to load contacts for bulk status update", "error", err)
+			return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update conversation status", nil, "")
+		}
+		if len(batch) == 0 {
+			break
+		}
+		lastID = batch[len(batch)-1].ID
+
+		ids := make([]uuid.UUID, len(batch))
+		for i, contact := range batch {
+			ids[i] = contact.ID
+		}
+
+		var updated, unassigned int64
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&models.Contact{}).
+				Where("id IN ?", ids).
+				Where("conversation_status IS DISTINCT FROM ? OR snooze_until IS DISTINCT FROM ?", req.Status, req.SnoozeUntil).
+				Updates(map[string]any{
+					"conversation_status": req.Status,
+					"snooze_until":        req.SnoozeUntil,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			updated = result.RowsAffected
+
+			for _, contact := range batch {
+				if contact.AssignedUserID == nil || !unassigns(contact.WhatsAppAccount) {
+					continue
+				}
+				if err := tx.Model(&models.Contact{}).Where("id = ?", contact.ID).
+					Update("assigned_user_id", nil).Error; err != nil {
+					return err
+				}
+				if err := recordContactAssignment(tx, orgID, contact.ID, contact.AssignedUserID, nil, userID); err != nil {
+					return err
+				}
+				unassigned++
+			}
+			return nil
+		})
+		if err != nil {
+			// Earlier batches are already committed
+			a.Log.Error("Failed to bulk update conversation status", "error", err, "updated", resp.Updated)
+			return r.SendErrorEnvelope(fasthttp.StatusInternalServerError,
+				fmt.Sprintf("Failed to update conversation status after %d contacts were updated", resp.Updated), nil, "")
+		}
+		resp.Matched += int64(len(batch))
+		resp.Updated += updated
+		resp.Unassigned += unassigned
+
+		if len(batch) < bulkStatusBatchSize {
+			break
+		}
+	}
+
+	a.Log.Info("Conversation status bulk updated", "org_id", orgID, "user_id", userID, "status", req.Status,
+		"matched", resp.Matched, "updated", resp.Updated, "unassigned", resp.Unassigned)
+
+	return r.SendEnvelope(resp)
+}
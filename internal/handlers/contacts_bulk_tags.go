@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// bulkTagBatchSize is the number of contacts updated per transaction
+const bulkTagBatchSize = 500
+
+// MaxBulkTagContactIDs is the maximum number of contact IDs a bulk tag request may list
+const MaxBulkTagContactIDs = 10000
+
+// BulkTagContactsRequest adds and removes tags on contacts selected either by ID or by filter
+type BulkTagContactsRequest struct {
+	ContactIDs []uuid.UUID         `json:"contact_ids"`
+	Filter     *BulkContactsFilter `json:"filter"`
+	Add        []string            `json:"add"`
+	Remove     []string            `json:"remove"`
+}
+
+// BulkTagContactsResponse reports the outcome of a bulk tag operation
+type BulkTagContactsResponse struct {
+	OperationID uuid.UUID `json:"operation_id"`
+	Matched     int64     `json:"matched"`
+	Affected    int64     `json:"affected"`
+}
+
+// BulkTagContacts adds and removes tags across many contacts. Contacts are
+// processed in batches so a large segment doesn't hold one long transaction,
+// and the operation is recorded for later review.
+func (a *App) BulkTagContacts(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceContacts, models.ActionWrite, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "You do not have permission to update contact tags", nil, "")
+	}
+
+	var req BulkTagContactsRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	hasIDs := len(req.ContactIDs) > 0
+	hasFilter := !req.Filter.isEmpty()
+	if hasIDs == hasFilter {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Provide either contact_ids or a non-empty filter", nil, "")
+	}
+	if len(req.ContactIDs) > MaxBulkTagContactIDs {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			fmt.Sprintf("At most %d contact_ids can be tagged at once", MaxBulkTagContactIDs), nil, "")
+	}
+
+	add := normalizeTagList(req.Add)
+	remove := normalizeTagList(req.Remove)
+	if len(add) == 0 && len(remove) == 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Provide at least one tag to add or remove", nil, "")
+	}
+	for _, tag := range add {
+		for _, other := range remove {
+			if tag == other {
+				return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+					fmt.Sprintf("Tag %q cannot be both added and removed", tag), nil, "")
+			}
+		}
+	}
+
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("organization_id = ?", orgID)
+		if hasIDs {
+			return db.Where("id IN ?", req.ContactIDs)
+		}
+		return req.Filter.apply(db)
+	}
+
+	var matched, affected int64
+	var lastID uuid.UUID
+	for {
+		// Keyset pagination on id keeps batches stable while tags change underneath
+		query := a.DB.Model(&models.Contact{}).Select("id", "tags").Scopes(scope).Order("id ASC").Limit(bulkTagBatchSize)
+		if lastID != uuid.Nil {
+			query = query.Where("id > ?", lastID)
+		}
+		var batch []models.Contact
+		if err := query.Find(&batch).Error; err != nil {
+			a.Log.Error("Failed to load contacts for bulk tag", "error", err)
+			return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update contact tags", nil, "")
+		}
+		if len(batch) == 0 {
+			break
+		}
+		matched += int64(len(batch))
+		lastID = batch[len(batch)-1].ID
+
+		var changed int64
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			for _, contact := range batch {
+				tags, ok := applyTagChanges(contact.Tags, add, remove)
+				if !ok {
+					continue
+				}
+				if err := tx.Model(&models.Contact{}).Where("id = ?", contact.ID).Update("tags", tags).Error; err != nil {
+					return err
+				}
+				changed++
+			}
+			return nil
+		})
+		if err != nil {
+			// Earlier batches are already committed, so record what was done
+			a.Log.Error("Failed to bulk tag contacts", "error", err, "affected", affected)
+			a.recordContactTagOperation(orgID, userID, &req, add, remove, matched, affected)
+			return r.SendErrorEnvelope(fasthttp.StatusInternalServerError,
+				fmt.Sprintf("Failed to update contact tags after %d contacts were updated", affected), nil, "")
+		}
+		affected += changed
+
+		if len(batch) < bulkTagBatchSize {
+			break
+		}
+	}
+
+	op := a.recordContactTagOperation(orgID, userID, &req, add, remove, matched, affected)
+
+	a.Log.Info("Contacts bulk tagged", "org_id", orgID, "user_id", userID, "matched", matched, "affected", affected)
+
+	return r.SendEnvelope(BulkTagContactsResponse{
+		OperationID: op.ID,
+		Matched:     matched,
+		Affected:    affected,
+	})
+}
+
+// ListContactTagOperations returns the organization's bulk tag operations, newest first
+func (a *App) ListContactTagOperations(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceContacts, models.ActionRead, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "You do not have permission to view contacts", nil, "")
+	}
+
+	pg := parsePagination(r)
+	query := a.DB.Model(&models.ContactTagOperation{}).Where("organization_id = ?", orgID)
+
+	var total int64
+	query.Count(&total)
+
+	var operations []models.ContactTagOperation
+	if err := pg.Apply(query.Preload("User", selectAssignedUser).Order("created_at DESC")).
+		Find(&operations).Error; err != nil {
+		a.Log.Error("Failed to list contact tag operations", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list tag operations", nil, "")
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"operations": operations,
+		"total":      total,
+		"page":       pg.Page,
+		"limit":      pg.Limit,
+	})
+}
+
+// recordContactTagOperation saves an audit record of a bulk tag operation.
+// Failures are logged rather than returned since the tags are already changed.
+func (a *App) recordContactTagOperation(orgID, userID uuid.UUID, req *BulkTagContactsRequest, add, remove []string, matched, affected int64) *models.ContactTagOperation {
+	op := &models.ContactTagOperation{
+		OrganizationID: orgID,
+		UserID:         userID,
+		AddTags:        add,
+		RemoveTags:     remove,
+		ContactIDs:     models.StringArray{},
+		Matched:        matched,
+		Affected:       affected,
+	}
+	for _, id := range req.ContactIDs {
+		op.ContactIDs = append(op.ContactIDs, id.String())
+	}
+	if len(req.ContactIDs) == 0 && req.Filter != nil {
+		if raw, err := json.Marshal(req.Filter); err == nil {
+			_ = json.Unmarshal(raw, &op.Filter)
+		}
+	}
+	if err := a.DB.Create(op).Error; err != nil {
+		a.Log.Error("Failed to record contact tag operation", "error", err, "org_id", orgID)
+	}
+	return op
+}
+
+// normalizeTagList trims tags and drops blanks and duplicates, keeping order
+func normalizeTagList(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// applyTagChanges returns the contact's tags with add appended and remove
+// dropped, and whether anything changed
+func applyTagChanges(current models.JSONBArray, add, remove []string) (models.JSONBArray, bool) {
+	removeSet := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		removeSet[tag] = true
+	}
+
+	changed := false
+	result := make(models.JSONBArray, 0, len(current)+len(add))
+	present := make(map[string]bool, len(current))
+	for _, t := range current {
+		if s, ok := t.(string); ok {
+			if removeSet[s] {
+				changed = true
+				continue
+			}
+			present[s] = true
+		}
+		result = append(result, t)
+	}
+	for _, tag := range add {
+		if !present[tag] {
+			present[tag] = true
+			result = append(result, tag)
+			changed = true
+		}
+	}
+	return result, changed
+}
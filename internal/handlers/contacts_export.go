@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// contactExportBatchSize is the number of contacts loaded and written per batch
+const contactExportBatchSize = 1000
+
+// contactExportHeader is the header row of a contacts CSV export
+var contactExportHeader = []string{"phone_number", "profile_name", "status", "tags", "assigned_agent", "created_at"}
+
+// ExportContacts streams the organization's contacts as CSV. It accepts the
+// same filters as ListContacts and only includes contacts the user can see.
+func (a *App) ExportContacts(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceContacts, models.ActionExport, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "You do not have permission to export contacts", nil, "")
+	}
+
+	query := a.ScopeToOrg(a.DB.Model(&models.Contact{}), userID, orgID)
+	query = a.scopeContactsQuery(query, userID, orgID)
+	query = filterContactsFromQueryArgs(query, r.RequestCtx.QueryArgs())
+	query = query.Preload("AssignedUser", selectAssignedUser)
+
+	shouldMask := a.ShouldMaskPhoneNumbers(orgID)
+
+	filename := fmt.Sprintf("contacts_export_%s.csv", time.Now().Format("20060102_150405"))
+	r.RequestCtx.Response.Header.Set("Content-Type", "text/csv")
+	r.RequestCtx.Response.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+
+	// Rows are written as each batch is loaded so large organizations aren't
+	// buffered in memory. Headers are already sent, so errors can only be logged.
+	r.RequestCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		writer := csv.NewWriter(w)
+		_ = writer.Write(contactExportHeader)
+
+		var batch []models.Contact
+		var exported int
+		result := query.FindInBatches(&batch, contactExportBatchSize, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				_ = writer.Write(contactExportRow(&batch[i], shouldMask))
+			}
+			exported += len(batch)
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			// Flush the buffered writer so the client receives the batch now
+			return w.Flush()
+		})
+		if result.Error != nil {
+			a.Log.Error("Failed to export contacts", "error", result.Error, "org_id", orgID, "exported", exported)
+		}
+		writer.Flush()
+	})
+
+	return nil
+}
+
+// contactExportRow converts a contact to a CSV row matching contactExportHeader
+func contactExportRow(contact *models.Contact, mask bool) []string {
+	phoneNumber := contact.PhoneNumber
+	profileName := contact.ProfileName
+	if mask {
+		phoneNumber = MaskPhoneNumber(phoneNumber)
+		profileName = MaskIfPhoneNumber(profileName)
+	}
+
+	tags := make([]string, 0, len(contact.Tags))
+	for _, t := range contact.Tags {
+		if s, ok := t.(string); ok {
+			tags = append(tags, s)
+		}
+	}
+
+	assignedAgent := ""
+	if name, _ := assignedUserSummary(contact); name != nil {
+		assignedAgent = *name
+	}
+
+	return []string{
+		escapeCSVCell(phoneNumber),
+		escapeCSVCell(profileName),
+		"active",
+		escapeCSVCell(strings.Join(tags, ",")),
+		escapeCSVCell(assignedAgent),
+		contact.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
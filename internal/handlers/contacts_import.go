@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/contactutil"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// Limits for contact CSV imports
+const (
+	maxContactImportSize = 10 << 20 // 10MB
+	maxContactImportRows = 10000
+)
+
+// contactImportColumns maps accepted CSV headers (lowercased) to contact fields
+var contactImportColumns = map[string]string{
+	"phone_number": "phone_number",
+	"phone number": "phone_number",
+	"phone":        "phone_number",
+	"profile_name": "profile_name",
+	"profile name": "profile_name",
+	"name":         "profile_name",
+	"tags":         "tags",
+}
+
+// ContactImportRowError describes why a CSV row was not imported
+type ContactImportRowError struct {
+	Row         int    `json:"row"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+	Error       string `json:"error"`
+}
+
+// ContactImportResult summarizes a contact import
+type ContactImportResult struct {
+	Created   int                     `json:"created"`
+	Updated   int                     `json:"updated"`
+	Errors    int                     `json:"errors"`
+	RowErrors []ContactImportRowError `json:"row_errors"`
+}
+
+// ImportContacts creates or updates contacts from an uploaded CSV. Rows are
+// matched to existing contacts by phone number within the organization:
+// matches get their profile name and tags updated, others are created.
+func (a *App) ImportContacts(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceContacts, models.ActionImport, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "You do not have permission to import contacts", nil, "")
+	}
+
+	form, err := r.RequestCtx.MultipartForm()
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid multipart form", nil, "")
+	}
+
+	// Optional WhatsApp account assigned to newly created contacts
+	whatsAppAccount := ""
+	if values := form.Value["whatsapp_account"]; len(values) > 0 {
+		whatsAppAccount = strings.TrimSpace(values[0])
+	}
+	if whatsAppAccount != "" {
+		var count int64
+		a.DB.Model(&models.WhatsAppAccount{}).Where("organization_id = ? AND name = ?", orgID, whatsAppAccount).Count(&count)
+		if count == 0 {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "WhatsApp account not found", nil, "")
+		}
+	}
+
+	files := form.File["file"]
+	if len(files) == 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "file is required", nil, "")
+	}
+	if files[0].Size > maxContactImportSize {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "File exceeds the 10MB limit", nil, "")
+	}
+	file, err := files[0].Open()
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Failed to read file", nil, "")
+	}
+	defer file.Close() //nolint:errcheck
+
+	reader := csv.NewReader(io.LimitReader(file, maxContactImportSize))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Failed to read CSV header", nil, "")
+	}
+	colIndex := make(map[string]int)
+	for i, h := range header {
+		// Spreadsheet exports often start with a UTF-8 byte order mark
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if field, ok := contactImportColumns[h]; ok {
+			if _, seen := colIndex[field]; !seen {
+				colIndex[field] = i
+			}
+		}
+	}
+	if _, ok := colIndex["phone_number"]; !ok {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Required column 'phone_number' not found in CSV", nil, "")
+	}
+
+	cell := func(record []string, field string) (string, bool) {
+		idx, ok := colIndex[field]
+		if !ok || idx >= len(record) {
+			return "", false
+		}
+		return strings.TrimSpace(record[idx]), true
+	}
+
+	result := ContactImportResult{RowErrors: []ContactImportRowError{}}
+	rowErr := func(row int, phone, msg string) {
+		result.Errors++
+		result.RowErrors = append(result.RowErrors, ContactImportRowError{Row: row, PhoneNumber: phone, Error: msg})
+	}
+
+	rowNum := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		rowNum++
+		if rowNum > maxContactImportRows+1 { // +1 for header row
+			rowErr(rowNum, "", fmt.Sprintf("import is limited to %d rows; remaining rows were skipped", maxContactImportRows))
+			break
+		}
+		if err != nil {
+			rowErr(rowNum, "", "failed to parse row")
+			continue
+		}
+
+		rawPhone, _ := cell(record, "phone_number")
+		if rawPhone == "" && len(strings.Join(record, "")) == 0 {
+			// Blank line
+			continue
+		}
+		phone, err := contactutil.ValidatePhone(rawPhone, a.Config.WhatsApp.DefaultPhoneRegion)
+		if err != nil {
+			rowErr(rowNum, rawPhone, err.Error())
+			continue
+		}
+
+		profileName, _ := cell(record, "profile_name")
+		if len(profileName) > 255 {
+			rowErr(rowNum, rawPhone, "profile_name must be at most 255 characters")
+			continue
+		}
+		var tags models.JSONBArray
+		if rawTags, ok := cell(record, "tags"); ok && rawTags != "" {
+			for _, tag := range normalizeTagList(strings.Split(rawTags, ",")) {
+				tags = append(tags, tag)
+			}
+		}
+
+		created, err := a.upsertImportedContact(orgID, phone, profileName, tags, whatsAppAccount)
+		if err != nil {
+			a.Log.Error("Failed to import contact", "error", err, "row", rowNum)
+			rowErr(rowNum, rawPhone, "failed to save contact")
+			continue
+		}
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+
+	a.Log.Info("Contacts imported", "org_id", orgID, "user_id", userID,
+		"created", result.Created, "updated", result.Updated, "errors", result.Errors)
+
+	return r.SendEnvelope(result)
+}
+
+// upsertImportedContact updates the contact with the given phone number or
+// creates it, reporting whether a new contact was created. Soft-deleted
+// contacts are restored and count as created.
+func (a *App) upsertImportedContact(orgID uuid.UUID, phone, profileName string, tags models.JSONBArray, whatsAppAccount string) (bool, error) {
+	existing, err := contactutil.FindContactByPhone(a.DB, orgID, phone)
+	if err == nil {
+		updates := map[string]any{}
+		if profileName != "" {
+			updates["profile_name"] = profileName
+		}
+		if tags != nil {
+			updates["tags"] = tags
+		}
+		restored := existing.DeletedAt.Valid
+		if restored {
+			updates["deleted_at"] = nil
+		}
+		if len(updates) > 0 {
+			if err := a.DB.Unscoped().Model(existing).Updates(updates).Error; err != nil {
+				return false, err
+			}
+		}
+		return restored, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	contact := models.Contact{
+		OrganizationID:  orgID,
+		PhoneNumber:     phone,
+		ProfileName:     profileName,
+		WhatsAppAccount: whatsAppAccount,
+		Tags:            tags,
+	}
+	if contact.Tags == nil {
+		contact.Tags = models.JSONBArray{}
+	}
+	if err := a.DB.Create(&contact).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
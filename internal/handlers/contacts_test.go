@@ -1,14 +1,21 @@
 package handlers_test
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shridarpatil/whatomate/internal/handlers"
 	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/pkg/whatsapp"
 	"github.com/shridarpatil/whatomate/test/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -200,6 +207,126 @@ func TestApp_ListContacts(t *testing.T) {
 	})
 }
 
+func TestApp_ListContacts_AssignedUser(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	agent := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithFullName("Agent Smith"))
+	require.NoError(t, app.DB.Model(agent).Update("is_available", false).Error)
+
+	assigned := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(assigned).Update("assigned_user_id", agent.ID).Error)
+	unassigned := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.ListContacts(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data struct {
+			Contacts []handlers.ContactResponse `json:"contacts"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+
+	byID := make(map[uuid.UUID]handlers.ContactResponse)
+	for _, c := range resp.Data.Contacts {
+		byID[c.ID] = c
+	}
+
+	got := byID[assigned.ID]
+	require.NotNil(t, got.AssignedUserName)
+	assert.Equal(t, "Agent Smith", *got.AssignedUserName)
+	require.NotNil(t, got.AssignedUserAvail)
+	assert.False(t, *got.AssignedUserAvail)
+
+	got = byID[unassigned.ID]
+	assert.Nil(t, got.AssignedUserName)
+	assert.Nil(t, got.AssignedUserAvail)
+
+	// GetContact returns the same fields
+	req = testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", assigned.ID.String())
+
+	require.NoError(t, app.GetContact(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var single struct {
+		Data handlers.ContactResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &single))
+	require.NotNil(t, single.Data.AssignedUserName)
+	assert.Equal(t, "Agent Smith", *single.Data.AssignedUserName)
+}
+
+func TestApp_ContactsOwnOnly(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+
+	// The role has contacts:read but is limited to the user's own contacts
+	role := testutil.CreateTestRoleWithKeys(t, app.DB, org.ID, "own-only", []string{"contacts:read", "chat:read"})
+	require.NoError(t, app.DB.Model(role).Update("contacts_own_only", true).Error)
+	agent := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+	other := testutil.CreateTestUser(t, app.DB, org.ID)
+
+	mine := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(mine).Update("assigned_user_id", agent.ID).Error)
+	theirs := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(theirs).Update("assigned_user_id", other.ID).Error)
+	unassigned := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	listIDs := func(t *testing.T) []uuid.UUID {
+		t.Helper()
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, agent.ID)
+		require.NoError(t, app.ListContacts(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Contacts []handlers.ContactResponse `json:"contacts"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		ids := make([]uuid.UUID, len(resp.Data.Contacts))
+		for i, c := range resp.Data.Contacts {
+			ids[i] = c.ID
+		}
+		return ids
+	}
+	getStatus := func(t *testing.T, contactID uuid.UUID, handler func(*fastglue.Request) error) int {
+		t.Helper()
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, agent.ID)
+		testutil.SetPathParam(req, "id", contactID.String())
+		require.NoError(t, handler(req))
+		return testutil.GetResponseStatusCode(req)
+	}
+
+	assert.ElementsMatch(t, []uuid.UUID{mine.ID}, listIDs(t))
+	assert.Equal(t, fasthttp.StatusOK, getStatus(t, mine.ID, app.GetContact))
+	assert.Equal(t, fasthttp.StatusNotFound, getStatus(t, theirs.ID, app.GetContact))
+	assert.Equal(t, fasthttp.StatusNotFound, getStatus(t, unassigned.ID, app.GetContact))
+	assert.Equal(t, fasthttp.StatusOK, getStatus(t, mine.ID, app.GetMessages))
+	assert.Equal(t, fasthttp.StatusNotFound, getStatus(t, theirs.ID, app.GetMessages))
+
+	// Allowing unassigned contacts widens the scope but still hides other agents' contacts
+	require.NoError(t, app.DB.Model(role).Update("contacts_include_unassigned", true).Error)
+	app.InvalidateRolePermissionsCache(role.ID)
+
+	assert.ElementsMatch(t, []uuid.UUID{mine.ID, unassigned.ID}, listIDs(t))
+	assert.Equal(t, fasthttp.StatusOK, getStatus(t, unassigned.ID, app.GetContact))
+	assert.Equal(t, fasthttp.StatusNotFound, getStatus(t, theirs.ID, app.GetContact))
+}
+
 // --- GetContact Tests ---
 
 func TestApp_GetContact(t *testing.T) {
@@ -907,6 +1034,208 @@ func TestApp_GetMessages(t *testing.T) {
 		assert.Equal(t, "wamid.test123", m.WAMID)
 		assert.NotNil(t, m.Content)
 	})
+
+	t.Run("identical timestamps order stably", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+		// Simulate a bulk import where every message shares one timestamp,
+		// inserted out of wamid order
+		importedAt := time.Now().Truncate(time.Second)
+		for _, wamid := range []string{"wamid.C", "wamid.A", "wamid.E", "wamid.B", "wamid.D"} {
+			msg := &models.Message{
+				BaseModel:         models.BaseModel{ID: uuid.New(), CreatedAt: importedAt},
+				OrganizationID:    org.ID,
+				WhatsAppAccount:   account.Name,
+				ContactID:         contact.ID,
+				WhatsAppMessageID: wamid,
+				Direction:         models.DirectionIncoming,
+				MessageType:       models.MessageTypeText,
+				Content:           wamid,
+				Status:            models.MessageStatusDelivered,
+			}
+			require.NoError(t, app.DB.Create(msg).Error)
+		}
+
+		fetch := func(params map[string]any) []handlers.MessageResponse {
+			req := testutil.NewGETRequest(t)
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			testutil.SetPathParam(req, "id", contact.ID.String())
+			for k, v := range params {
+				testutil.SetQueryParam(req, k, v)
+			}
+			require.NoError(t, app.GetMessages(req))
+			require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+			var resp struct {
+				Data struct {
+					Messages []handlers.MessageResponse `json:"messages"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+			return resp.Data.Messages
+		}
+		wamids := func(msgs []handlers.MessageResponse) []string {
+			out := make([]string, len(msgs))
+			for i, m := range msgs {
+				out[i] = m.WAMID
+			}
+			return out
+		}
+
+		want := []string{"wamid.A", "wamid.B", "wamid.C", "wamid.D", "wamid.E"}
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, want, wamids(fetch(map[string]any{"limit": 50})))
+		}
+
+		// Paging with before_id walks the same order without skipping ties
+		all := fetch(map[string]any{"limit": 50})
+		older := fetch(map[string]any{"limit": 2, "before_id": all[3].ID.String()})
+		assert.Equal(t, []string{"wamid.B", "wamid.C"}, wamids(older))
+	})
+}
+
+func TestApp_GetMessages_ReadReceipts(t *testing.T) {
+	t.Parallel()
+
+	// setup creates a contact with one unread incoming message on an account with
+	// auto read receipts, backed by a mock WhatsApp API that answers with the given
+	// statuses in turn (the last one repeats) and counts receipt attempts
+	setup := func(t *testing.T, statuses ...int) (*handlers.App, *fastglue.Request, *atomic.Int32) {
+		t.Helper()
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := int(calls.Add(1))
+			status := statuses[min(n, len(statuses))-1]
+			w.WriteHeader(status)
+			if status == http.StatusOK {
+				_, _ = w.Write([]byte(`{"success":true}`))
+			} else {
+				_, _ = w.Write([]byte(`{"error":{"message":"temporarily unavailable","code":2}}`))
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		app := newTestApp(t)
+		app.WhatsApp = whatsapp.NewWithBaseURL(app.Log, server.URL)
+		app.Config.WhatsApp.ReadReceiptMaxAttempts = 3
+		app.Config.WhatsApp.ReadReceiptRetryDelayMs = 1
+
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(account).Update("auto_read_receipt", true).Error)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+		require.NoError(t, app.DB.Create(&models.Message{
+			BaseModel:         models.BaseModel{ID: uuid.New()},
+			OrganizationID:    org.ID,
+			WhatsAppAccount:   account.Name,
+			ContactID:         contact.ID,
+			Direction:         models.DirectionIncoming,
+			MessageType:       models.MessageTypeText,
+			Content:           "Hello",
+			Status:            models.MessageStatusDelivered,
+			WhatsAppMessageID: "wamid.receipt_" + uuid.New().String()[:8],
+		}).Error)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		return app, req, &calls
+	}
+
+	assertMessagesReturned := func(t *testing.T, req *fastglue.Request) {
+		t.Helper()
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		var resp struct {
+			Data struct {
+				Messages []handlers.MessageResponse `json:"messages"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Len(t, resp.Data.Messages, 1)
+	}
+
+	t.Run("failing receipt does not affect response", func(t *testing.T) {
+		t.Parallel()
+		app, req, calls := setup(t, http.StatusInternalServerError)
+
+		require.NoError(t, app.GetMessages(req))
+		assertMessagesReturned(t, req)
+
+		app.WaitForBackgroundTasks()
+		assert.Equal(t, int32(3), calls.Load(), "receipt should be retried up to the configured attempts")
+	})
+
+	t.Run("transient failure is retried", func(t *testing.T) {
+		t.Parallel()
+		app, req, calls := setup(t, http.StatusServiceUnavailable, http.StatusOK)
+
+		require.NoError(t, app.GetMessages(req))
+		assertMessagesReturned(t, req)
+
+		app.WaitForBackgroundTasks()
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+func TestApp_GetMessages_OrgReadReceiptsBatched(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var receipts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		receipts = append(receipts, body["message_id"].(string))
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	app := newTestApp(t)
+	app.WhatsApp = whatsapp.NewWithBaseURL(app.Log, server.URL)
+
+	// The account doesn't send receipts itself; the organization setting does
+	org := testutil.CreateTestOrganization(t, app.DB)
+	require.NoError(t, app.DB.Model(org).Update("settings", models.JSONB{"send_read_receipts": true}).Error)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+	base := time.Now().Add(-time.Hour)
+	for i, wamid := range []string{"wamid.first", "wamid.second", "wamid.third"} {
+		require.NoError(t, app.DB.Create(&models.Message{
+			BaseModel:         models.BaseModel{ID: uuid.New(), CreatedAt: base.Add(time.Duration(i) * time.Minute)},
+			OrganizationID:    org.ID,
+			WhatsAppAccount:   account.Name,
+			ContactID:         contact.ID,
+			Direction:         models.DirectionIncoming,
+			MessageType:       models.MessageTypeText,
+			Content:           "Hello",
+			Status:            models.MessageStatusDelivered,
+			WhatsAppMessageID: wamid,
+		}).Error)
+	}
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+	require.NoError(t, app.GetMessages(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	app.WaitForBackgroundTasks()
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"wamid.third"}, receipts, "one receipt for the newest unread message")
 }
 
 // --- SendMessage Tests ---
@@ -1119,102 +1448,377 @@ func TestApp_SendMessage(t *testing.T) {
 	})
 }
 
-// --- SendReaction Tests ---
-
-func TestApp_SendReaction(t *testing.T) {
+func TestApp_SendMessage_ContactAccount(t *testing.T) {
 	t.Parallel()
 
-	t.Run("success - add reaction", func(t *testing.T) {
+	// send posts a text message to the contact, optionally naming an account,
+	// and returns the account the reply went out on
+	send := func(t *testing.T, app *handlers.App, orgID, userID, contactID uuid.UUID, accountName string) string {
+		t.Helper()
+		body := map[string]interface{}{
+			"type":    "text",
+			"content": map[string]string{"body": "Thanks for reaching out"},
+		}
+		if accountName != "" {
+			body["whatsapp_account"] = accountName
+		}
+		req := testutil.NewJSONRequest(t, body)
+		testutil.SetAuthContext(req, orgID, userID)
+		testutil.SetPathParam(req, "id", contactID.String())
+
+		require.NoError(t, app.SendMessage(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.MessageResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+
+		var saved models.Message
+		require.NoError(t, app.DB.First(&saved, "id = ?", resp.Data.ID).Error)
+		assert.Equal(t, resp.Data.WhatsAppAccount, saved.WhatsAppAccount)
+		return saved.WhatsAppAccount
+	}
+
+	t.Run("defaults to the contact's account over the org default", func(t *testing.T) {
 		t.Parallel()
-		app := newTestApp(t, withHTTPClient(&http.Client{}))
+		mockServer := newMockWhatsAppServer()
+		defer mockServer.close()
+
+		app := newMsgTestApp(t, mockServer)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
 		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
-		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
-		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+		defaultAccount := createTestAccount(t, app, org.ID)
+		require.NoError(t, app.DB.Model(defaultAccount).Update("is_default_outgoing", true).Error)
+		contactAccount := createTestAccount(t, app, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(contactAccount.Name))
 
-		msg := &models.Message{
-			BaseModel:         models.BaseModel{ID: uuid.New()},
-			OrganizationID:    org.ID,
-			WhatsAppAccount:   account.Name,
-			ContactID:         contact.ID,
-			WhatsAppMessageID: "wamid.react123",
-			Direction:         models.DirectionIncoming,
-			MessageType:       models.MessageTypeText,
-			Content:           "Hello",
-			Status:            models.MessageStatusDelivered,
-		}
-		require.NoError(t, app.DB.Create(msg).Error)
+		assert.Equal(t, contactAccount.Name, send(t, app, org.ID, user.ID, contact.ID, ""))
+	})
 
-		req := testutil.NewJSONRequest(t, map[string]interface{}{
-			"emoji": "\U0001F44D",
-		})
-		testutil.SetAuthContext(req, org.ID, user.ID)
-		testutil.SetPathParam(req, "id", contact.ID.String())
-		testutil.SetPathParam(req, "message_id", msg.ID.String())
+	t.Run("explicit account overrides the contact's account", func(t *testing.T) {
+		t.Parallel()
+		mockServer := newMockWhatsAppServer()
+		defer mockServer.close()
 
-		err := app.SendReaction(req)
-		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		app := newMsgTestApp(t, mockServer)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contactAccount := createTestAccount(t, app, org.ID)
+		otherAccount := createTestAccount(t, app, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(contactAccount.Name))
 
-		var resp struct {
-			Data struct {
-				MessageID string `json:"message_id"`
-				Reactions []struct {
-					Emoji    string `json:"emoji"`
-					FromUser string `json:"from_user"`
-				} `json:"reactions"`
-			} `json:"data"`
-		}
-		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
-		assert.Equal(t, msg.ID.String(), resp.Data.MessageID)
-		require.Len(t, resp.Data.Reactions, 1)
-		assert.Equal(t, "\U0001F44D", resp.Data.Reactions[0].Emoji)
-		assert.Equal(t, user.ID.String(), resp.Data.Reactions[0].FromUser)
+		assert.Equal(t, otherAccount.Name, send(t, app, org.ID, user.ID, contact.ID, otherAccount.Name))
 	})
 
-	t.Run("success - remove reaction", func(t *testing.T) {
+	t.Run("falls back to the org default without a contact account", func(t *testing.T) {
 		t.Parallel()
-		app := newTestApp(t, withHTTPClient(&http.Client{}))
+		mockServer := newMockWhatsAppServer()
+		defer mockServer.close()
+
+		app := newMsgTestApp(t, mockServer)
 		org := testutil.CreateTestOrganization(t, app.DB)
 		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
 		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
-		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
-		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+		createTestAccount(t, app, org.ID)
+		defaultAccount := createTestAccount(t, app, org.ID)
+		require.NoError(t, app.DB.Model(defaultAccount).Update("is_default_outgoing", true).Error)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
 
-		// Create message with an existing reaction
-		msg := &models.Message{
-			BaseModel:         models.BaseModel{ID: uuid.New()},
-			OrganizationID:    org.ID,
-			WhatsAppAccount:   account.Name,
-			ContactID:         contact.ID,
-			WhatsAppMessageID: "wamid.remove-react",
-			Direction:         models.DirectionIncoming,
-			MessageType:       models.MessageTypeText,
-			Content:           "Hello",
-			Status:            models.MessageStatusDelivered,
-			Metadata: models.JSONB{
-				"reactions": []interface{}{
-					map[string]interface{}{
-						"emoji":     "\U0001F44D",
-						"from_user": user.ID.String(),
-					},
-				},
-			},
-		}
-		require.NoError(t, app.DB.Create(msg).Error)
+		assert.Equal(t, defaultAccount.Name, send(t, app, org.ID, user.ID, contact.ID, ""))
+	})
+}
 
-		// Send empty emoji to remove reaction
+func TestApp_SendMessage_AccountSelection(t *testing.T) {
+	t.Parallel()
+
+	// send posts a text message to a contact without an account, returning the
+	// status and either the account used or the error message
+	send := func(t *testing.T, app *handlers.App, orgID, userID uuid.UUID) (int, string) {
+		t.Helper()
+		contact := testutil.CreateTestContact(t, app.DB, orgID)
 		req := testutil.NewJSONRequest(t, map[string]interface{}{
-			"emoji": "",
+			"type":    "text",
+			"content": map[string]string{"body": "Hello"},
 		})
-		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetAuthContext(req, orgID, userID)
 		testutil.SetPathParam(req, "id", contact.ID.String())
-		testutil.SetPathParam(req, "message_id", msg.ID.String())
-
-		err := app.SendReaction(req)
-		require.NoError(t, err)
-		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		require.NoError(t, app.SendMessage(req))
+
+		var resp struct {
+			Message string                   `json:"message"`
+			Data    handlers.MessageResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		if status := testutil.GetResponseStatusCode(req); status != fasthttp.StatusOK {
+			return status, resp.Message
+		}
+		return fasthttp.StatusOK, resp.Data.WhatsAppAccount
+	}
+
+	setup := func(t *testing.T) (*handlers.App, uuid.UUID, uuid.UUID) {
+		t.Helper()
+		mockServer := newMockWhatsAppServer()
+		t.Cleanup(mockServer.close)
+		app := newMsgTestApp(t, mockServer)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		return app, org.ID, user.ID
+	}
+
+	setDefault := func(t *testing.T, app *handlers.App, orgID uuid.UUID, name string) {
+		t.Helper()
+		require.NoError(t, app.DB.Model(&models.Organization{}).Where("id = ?", orgID).
+			Update("settings", models.JSONB{"default_whatsapp_account": name}).Error)
+	}
+
+	t.Run("org default setting wins over the default outgoing flag", func(t *testing.T) {
+		t.Parallel()
+		app, orgID, userID := setup(t)
+		flagged := createTestAccount(t, app, orgID)
+		require.NoError(t, app.DB.Model(flagged).Update("is_default_outgoing", true).Error)
+		chosen := createTestAccount(t, app, orgID)
+		setDefault(t, app, orgID, chosen.Name)
+
+		status, account := send(t, app, orgID, userID)
+		assert.Equal(t, fasthttp.StatusOK, status)
+		assert.Equal(t, chosen.Name, account)
+	})
+
+	t.Run("single account is used without a default", func(t *testing.T) {
+		t.Parallel()
+		app, orgID, userID := setup(t)
+		only := createTestAccount(t, app, orgID)
+
+		status, account := send(t, app, orgID, userID)
+		assert.Equal(t, fasthttp.StatusOK, status)
+		assert.Equal(t, only.Name, account)
+	})
+
+	t.Run("several accounts without a default", func(t *testing.T) {
+		t.Parallel()
+		app, orgID, userID := setup(t)
+		createTestAccount(t, app, orgID)
+		createTestAccount(t, app, orgID)
+
+		status, msg := send(t, app, orgID, userID)
+		assert.Equal(t, fasthttp.StatusBadRequest, status)
+		assert.Contains(t, msg, "no default WhatsApp account")
+	})
+
+	t.Run("no accounts", func(t *testing.T) {
+		t.Parallel()
+		app, orgID, userID := setup(t)
+
+		status, msg := send(t, app, orgID, userID)
+		assert.Equal(t, fasthttp.StatusBadRequest, status)
+		assert.Equal(t, "no WhatsApp account configured", msg)
+	})
+
+	t.Run("default naming a missing account", func(t *testing.T) {
+		t.Parallel()
+		app, orgID, userID := setup(t)
+		createTestAccount(t, app, orgID)
+		setDefault(t, app, orgID, "retired-line")
+
+		status, msg := send(t, app, orgID, userID)
+		assert.Equal(t, fasthttp.StatusBadRequest, status)
+		assert.Contains(t, msg, `"retired-line" no longer exists`)
+	})
+}
+
+func TestApp_SendMessage_Media(t *testing.T) {
+	t.Parallel()
+
+	pngData := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+
+	// setup returns an app backed by a mock WhatsApp API and a contact to message
+	setup := func(t *testing.T) (*handlers.App, *mockWhatsAppServer, uuid.UUID, uuid.UUID, uuid.UUID) {
+		t.Helper()
+		mockServer := newMockWhatsAppServer()
+		t.Cleanup(mockServer.close)
+
+		app := newMsgTestApp(t, mockServer)
+		app.Config.Storage.LocalPath = t.TempDir()
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := createTestAccount(t, app, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+		return app, mockServer, org.ID, user.ID, contact.ID
+	}
+
+	t.Run("uploaded file is sent as media", func(t *testing.T) {
+		t.Parallel()
+		app, mockServer, orgID, userID, contactID := setup(t)
+
+		req := testutil.NewMultipartRequest(t, map[string]string{"type": "image", "caption": "Receipt"}, "file", "receipt.png", pngData)
+		testutil.SetAuthContext(req, orgID, userID)
+		testutil.SetPathParam(req, "id", contactID.String())
+		require.NoError(t, app.SendMessage(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.MessageResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, models.MessageTypeImage, resp.Data.MessageType)
+		assert.Equal(t, "image/png", resp.Data.MediaMimeType)
+		assert.Equal(t, "receipt.png", resp.Data.MediaFilename)
+		assert.NotEmpty(t, resp.Data.MediaURL)
+
+		app.WaitForBackgroundTasks()
+		require.Len(t, mockServer.uploadedMedia, 1)
+		require.Len(t, mockServer.sentMessages, 1)
+		assert.Equal(t, "image", mockServer.sentMessages[0]["type"])
+
+		var saved models.Message
+		require.NoError(t, app.DB.First(&saved, "id = ?", resp.Data.ID).Error)
+		assert.Equal(t, "Receipt", saved.Content)
+		assert.Equal(t, "image/png", saved.MediaMimeType)
+	})
+
+	t.Run("unsupported media is rejected", func(t *testing.T) {
+		t.Parallel()
+		app, mockServer, orgID, userID, contactID := setup(t)
+
+		tests := []struct {
+			name      string
+			mediaType string
+			content   []byte
+		}{
+			{name: "text file as image", mediaType: "image", content: []byte("not an image")},
+			{name: "unknown type", mediaType: "sticker", content: pngData},
+			{name: "empty file", mediaType: "document", content: nil},
+		}
+		for _, tt := range tests {
+			req := testutil.NewMultipartRequest(t, map[string]string{"type": tt.mediaType}, "file", "upload", tt.content)
+			testutil.SetAuthContext(req, orgID, userID)
+			testutil.SetPathParam(req, "id", contactID.String())
+			require.NoError(t, app.SendMessage(req))
+			assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req), tt.name)
+		}
+		assert.Empty(t, mockServer.sentMessages)
+	})
+
+	t.Run("media URL is validated", func(t *testing.T) {
+		t.Parallel()
+		app, mockServer, orgID, userID, contactID := setup(t)
+
+		for _, media := range []map[string]string{
+			{},
+			{"url": "http://127.0.0.1/secret.png"},
+			{"url": "ftp://example.com/file.pdf"},
+		} {
+			req := testutil.NewJSONRequest(t, map[string]any{"type": "document", "media": media})
+			testutil.SetAuthContext(req, orgID, userID)
+			testutil.SetPathParam(req, "id", contactID.String())
+			require.NoError(t, app.SendMessage(req))
+			assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req), media["url"])
+		}
+		assert.Empty(t, mockServer.sentMessages)
+	})
+}
+
+// --- SendReaction Tests ---
+
+func TestApp_SendReaction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - add reaction", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, withHTTPClient(&http.Client{}))
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+		msg := &models.Message{
+			BaseModel:         models.BaseModel{ID: uuid.New()},
+			OrganizationID:    org.ID,
+			WhatsAppAccount:   account.Name,
+			ContactID:         contact.ID,
+			WhatsAppMessageID: "wamid.react123",
+			Direction:         models.DirectionIncoming,
+			MessageType:       models.MessageTypeText,
+			Content:           "Hello",
+			Status:            models.MessageStatusDelivered,
+		}
+		require.NoError(t, app.DB.Create(msg).Error)
+
+		req := testutil.NewJSONRequest(t, map[string]interface{}{
+			"emoji": "\U0001F44D",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		testutil.SetPathParam(req, "message_id", msg.ID.String())
+
+		err := app.SendReaction(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				MessageID string `json:"message_id"`
+				Reactions []struct {
+					Emoji    string `json:"emoji"`
+					FromUser string `json:"from_user"`
+				} `json:"reactions"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, msg.ID.String(), resp.Data.MessageID)
+		require.Len(t, resp.Data.Reactions, 1)
+		assert.Equal(t, "\U0001F44D", resp.Data.Reactions[0].Emoji)
+		assert.Equal(t, user.ID.String(), resp.Data.Reactions[0].FromUser)
+	})
+
+	t.Run("success - remove reaction", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, withHTTPClient(&http.Client{}))
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+		// Create message with an existing reaction
+		msg := &models.Message{
+			BaseModel:         models.BaseModel{ID: uuid.New()},
+			OrganizationID:    org.ID,
+			WhatsAppAccount:   account.Name,
+			ContactID:         contact.ID,
+			WhatsAppMessageID: "wamid.remove-react",
+			Direction:         models.DirectionIncoming,
+			MessageType:       models.MessageTypeText,
+			Content:           "Hello",
+			Status:            models.MessageStatusDelivered,
+			Metadata: models.JSONB{
+				"reactions": []interface{}{
+					map[string]interface{}{
+						"emoji":     "\U0001F44D",
+						"from_user": user.ID.String(),
+					},
+				},
+			},
+		}
+		require.NoError(t, app.DB.Create(msg).Error)
+
+		// Send empty emoji to remove reaction
+		req := testutil.NewJSONRequest(t, map[string]interface{}{
+			"emoji": "",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		testutil.SetPathParam(req, "message_id", msg.ID.String())
+
+		err := app.SendReaction(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 		var resp struct {
 			Data struct {
@@ -1341,345 +1945,1931 @@ func TestApp_SendReaction(t *testing.T) {
 	})
 }
 
-// --- ListContacts additional tests ---
-
-func TestApp_ListContacts_SearchByProfileName(t *testing.T) {
+func TestApp_SendReaction_DispatchesWebhook(t *testing.T) {
 	t.Parallel()
 
-	app := newTestApp(t)
-	org := testutil.CreateTestOrganization(t, app.DB)
-	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	// sendReaction reacts to a message and returns the reaction webhook payload
+	sendReaction := func(t *testing.T, emoji string) (handlers.OutboundWebhookPayload, handlers.ReactionEventData, *models.User, *models.Message) {
+		t.Helper()
+		app := newTestApp(t, withHTTPClient(&http.Client{}))
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
 
-	// Create contact with a unique profile name
-	contact := &models.Contact{
-		BaseModel:      models.BaseModel{ID: uuid.New()},
-		OrganizationID: org.ID,
-		PhoneNumber:    "+1111111111",
-		ProfileName:    "UniqueAlphaName",
-	}
-	require.NoError(t, app.DB.Create(contact).Error)
+		clearWebhookCache(t, app.Redis, org.ID)
+		t.Cleanup(func() { clearWebhookCache(t, app.Redis, org.ID) })
+
+		payloads := make(chan []byte, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			payloads <- body
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+
+		require.NoError(t, app.DB.Create(&models.Webhook{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			OrganizationID: org.ID,
+			Name:           "reactions",
+			URL:            server.URL,
+			Events:         models.StringArray{string(models.WebhookEventMessageReaction)},
+			IsActive:       true,
+		}).Error)
 
-	// Create another contact with a different name
-	testutil.CreateTestContact(t, app.DB, org.ID)
+		msg := &models.Message{
+			BaseModel:       models.BaseModel{ID: uuid.New()},
+			OrganizationID:  org.ID,
+			WhatsAppAccount: account.Name,
+			ContactID:       contact.ID,
+			Direction:       models.DirectionIncoming,
+			MessageType:     models.MessageTypeText,
+			Content:         "Hello",
+			Status:          models.MessageStatusDelivered,
+			Metadata: models.JSONB{
+				"reactions": []interface{}{
+					map[string]interface{}{"emoji": "\U0001F44D", "from_user": user.ID.String()},
+				},
+			},
+		}
+		require.NoError(t, app.DB.Create(msg).Error)
 
-	req := testutil.NewGETRequest(t)
-	testutil.SetAuthContext(req, org.ID, user.ID)
-	testutil.SetQueryParam(req, "search", "UniqueAlpha")
+		req := testutil.NewJSONRequest(t, map[string]interface{}{"emoji": emoji})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		testutil.SetPathParam(req, "message_id", msg.ID.String())
 
-	err := app.ListContacts(req)
-	require.NoError(t, err)
-	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		require.NoError(t, app.SendReaction(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		app.WaitForBackgroundTasks()
 
-	var resp struct {
-		Data struct {
-			Contacts []handlers.ContactResponse `json:"contacts"`
-			Total    int64                      `json:"total"`
-		} `json:"data"`
+		var body []byte
+		select {
+		case body = <-payloads:
+		default:
+			t.Fatal("expected message.reaction webhook to be delivered")
+		}
+
+		var payload handlers.OutboundWebhookPayload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		var data handlers.ReactionEventData
+		raw, err := json.Marshal(payload.Data)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(raw, &data))
+		return payload, data, user, msg
 	}
-	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
-	assert.Equal(t, int64(1), resp.Data.Total)
-	require.Len(t, resp.Data.Contacts, 1)
-	assert.Equal(t, "UniqueAlphaName", resp.Data.Contacts[0].ProfileName)
+
+	t.Run("adding a reaction dispatches the emoji", func(t *testing.T) {
+		t.Parallel()
+		payload, data, user, msg := sendReaction(t, "\u2764\ufe0f")
+
+		assert.Equal(t, string(models.WebhookEventMessageReaction), payload.Event)
+		assert.Equal(t, msg.ID.String(), data.MessageID)
+		assert.Equal(t, "\u2764\ufe0f", data.Emoji)
+		assert.Equal(t, user.ID.String(), data.ActorUserID)
+	})
+
+	t.Run("removing a reaction dispatches an empty emoji", func(t *testing.T) {
+		t.Parallel()
+		payload, data, user, msg := sendReaction(t, "")
+
+		assert.Equal(t, string(models.WebhookEventMessageReaction), payload.Event)
+		assert.Equal(t, msg.ID.String(), data.MessageID)
+		assert.Empty(t, data.Emoji)
+		assert.Equal(t, user.ID.String(), data.ActorUserID)
+	})
 }
 
-func TestApp_ListContacts_Page2(t *testing.T) {
+func TestApp_DeleteMessage(t *testing.T) {
 	t.Parallel()
 
-	app := newTestApp(t)
-	org := testutil.CreateTestOrganization(t, app.DB)
-	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
-
-	// Create 5 contacts
-	for i := 0; i < 5; i++ {
-		testutil.CreateTestContact(t, app.DB, org.ID)
+	createMessage := func(t *testing.T, app *handlers.App, orgID, contactID uuid.UUID, accountName string, direction models.Direction) *models.Message {
+		t.Helper()
+		msg := &models.Message{
+			BaseModel:         models.BaseModel{ID: uuid.New()},
+			OrganizationID:    orgID,
+			WhatsAppAccount:   accountName,
+			ContactID:         contactID,
+			WhatsAppMessageID: "wamid." + uuid.New().String()[:8],
+			Direction:         direction,
+			MessageType:       models.MessageTypeText,
+			Content:           "Oops",
+			Status:            models.MessageStatusSent,
+		}
+		require.NoError(t, app.DB.Create(msg).Error)
+		return msg
 	}
 
-	req := testutil.NewGETRequest(t)
-	testutil.SetAuthContext(req, org.ID, user.ID)
-	testutil.SetQueryParam(req, "page", 2)
-	testutil.SetQueryParam(req, "limit", 2)
+	t.Run("success - soft deletes and hides from GetMessages", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+		kept := createMessage(t, app, org.ID, contact.ID, account.Name, models.DirectionIncoming)
+		deleted := createMessage(t, app, org.ID, contact.ID, account.Name, models.DirectionOutgoing)
+
+		req := testutil.NewRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		testutil.SetPathParam(req, "message_id", deleted.ID.String())
+
+		err := app.DeleteMessage(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				MessageID string `json:"message_id"`
+				Revoked   bool   `json:"revoked"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, deleted.ID.String(), resp.Data.MessageID)
+		assert.False(t, resp.Data.Revoked)
+
+		// Row is soft-deleted, not removed
+		var stored models.Message
+		require.NoError(t, app.DB.Unscoped().Where("id = ?", deleted.ID).First(&stored).Error)
+		assert.True(t, stored.DeletedAt.Valid)
+
+		listReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(listReq, org.ID, user.ID)
+		testutil.SetPathParam(listReq, "id", contact.ID.String())
+
+		require.NoError(t, app.GetMessages(listReq))
+		var listResp struct {
+			Data struct {
+				Messages []handlers.MessageResponse `json:"messages"`
+				Total    int64                      `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(listReq), &listResp))
+		assert.Equal(t, int64(1), listResp.Data.Total)
+		require.Len(t, listResp.Data.Messages, 1)
+		assert.Equal(t, kept.ID, listResp.Data.Messages[0].ID)
+	})
+
+	t.Run("success - revoke calls WhatsApp for recent outgoing message", func(t *testing.T) {
+		t.Parallel()
+
+		revokedPaths := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodDelete {
+				revokedPaths <- r.URL.Path
+			}
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(map[string]bool{"success": true})
+		}))
+		t.Cleanup(server.Close)
+
+		app := newTestApp(t, withWhatsApp(whatsapp.NewWithBaseURL(testutil.NopLogger(), server.URL)))
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+		msg := createMessage(t, app, org.ID, contact.ID, account.Name, models.DirectionOutgoing)
+
+		req := testutil.NewRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		testutil.SetPathParam(req, "message_id", msg.ID.String())
+		testutil.SetQueryParam(req, "revoke", "true")
+
+		err := app.DeleteMessage(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Revoked bool `json:"revoked"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.True(t, resp.Data.Revoked)
+		require.Len(t, revokedPaths, 1)
+		assert.Contains(t, <-revokedPaths, msg.WhatsAppMessageID)
+	})
+
+	t.Run("revoke skipped for old message", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+		msg := createMessage(t, app, org.ID, contact.ID, account.Name, models.DirectionOutgoing)
+		require.NoError(t, app.DB.Model(msg).UpdateColumn("created_at", time.Now().Add(-72*time.Hour)).Error)
+
+		req := testutil.NewRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		testutil.SetPathParam(req, "message_id", msg.ID.String())
+		testutil.SetQueryParam(req, "revoke", "true")
+
+		err := app.DeleteMessage(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Revoked bool `json:"revoked"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.False(t, resp.Data.Revoked)
+	})
+
+	t.Run("message not found", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		req := testutil.NewRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		testutil.SetPathParam(req, "message_id", uuid.New().String())
+
+		err := app.DeleteMessage(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("cross-org isolation", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org1 := testutil.CreateTestOrganization(t, app.DB)
+		org2 := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org1.ID)
+		user1 := testutil.CreateTestUser(t, app.DB, org1.ID, testutil.WithRoleID(&adminRole.ID))
+
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org2.ID)
+		contact := testutil.CreateTestContact(t, app.DB, org2.ID)
+		msg := createMessage(t, app, org2.ID, contact.ID, account.Name, models.DirectionOutgoing)
+
+		req := testutil.NewRequest(t)
+		testutil.SetAuthContext(req, org1.ID, user1.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		testutil.SetPathParam(req, "message_id", msg.ID.String())
+
+		err := app.DeleteMessage(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+
+		// Message must still be visible to its own org
+		var stored models.Message
+		require.NoError(t, app.DB.Where("id = ?", msg.ID).First(&stored).Error)
+	})
+}
+
+func TestApp_BulkDeleteContacts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - deletes contacts by id", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		c1 := testutil.CreateTestContact(t, app.DB, org.ID)
+		c2 := testutil.CreateTestContact(t, app.DB, org.ID)
+		keep := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"contact_ids": []string{c1.ID.String(), c2.ID.String()},
+			"confirm":     true,
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.BulkDeleteContacts(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Deleted int64 `json:"deleted"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, int64(2), resp.Data.Deleted)
+
+		var remaining []models.Contact
+		require.NoError(t, app.DB.Where("organization_id = ?", org.ID).Find(&remaining).Error)
+		require.Len(t, remaining, 1)
+		assert.Equal(t, keep.ID, remaining[0].ID)
+
+		// Soft-deleted, not removed
+		var count int64
+		app.DB.Unscoped().Model(&models.Contact{}).Where("id IN ?", []uuid.UUID{c1.ID, c2.ID}).Count(&count)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("success - deletes contacts by filter", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		tagged := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(tagged).Update("tags", models.JSONBArray{"test-data"}).Error)
+		untagged := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"filter":  map[string]any{"tags": []string{"test-data"}},
+			"confirm": true,
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.BulkDeleteContacts(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var remaining []models.Contact
+		require.NoError(t, app.DB.Where("organization_id = ?", org.ID).Find(&remaining).Error)
+		require.Len(t, remaining, 1)
+		assert.Equal(t, untagged.ID, remaining[0].ID)
+	})
+
+	t.Run("confirmation required", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"contact_ids": []string{contact.ID.String()},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.BulkDeleteContacts(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+		// Nothing was deleted
+		var stored models.Contact
+		require.NoError(t, app.DB.Where("id = ?", contact.ID).First(&stored).Error)
+	})
+
+	t.Run("capped batch", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		ids := []string{contact.ID.String()}
+		for len(ids) <= handlers.MaxBulkDeleteContacts {
+			ids = append(ids, uuid.New().String())
+		}
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"contact_ids": ids,
+			"confirm":     true,
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.BulkDeleteContacts(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+		var stored models.Contact
+		require.NoError(t, app.DB.Where("id = ?", contact.ID).First(&stored).Error)
+	})
+
+	t.Run("empty filter rejected", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"filter":  map[string]any{},
+			"confirm": true,
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.BulkDeleteContacts(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"contact_ids": []string{contact.ID.String()},
+			"confirm":     true,
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.BulkDeleteContacts(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("cross-org contacts are not deleted", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org1 := testutil.CreateTestOrganization(t, app.DB)
+		org2 := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org1.ID)
+		user1 := testutil.CreateTestUser(t, app.DB, org1.ID, testutil.WithRoleID(&adminRole.ID))
+		other := testutil.CreateTestContact(t, app.DB, org2.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"contact_ids": []string{other.ID.String()},
+			"confirm":     true,
+		})
+		testutil.SetAuthContext(req, org1.ID, user1.ID)
+
+		err := app.BulkDeleteContacts(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var stored models.Contact
+		require.NoError(t, app.DB.Where("id = ?", other.ID).First(&stored).Error)
+	})
+}
+
+func TestApp_BulkTagContacts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - adds and removes tags by id", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		c1 := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(c1).Update("tags", models.JSONBArray{"lead", "cold"}).Error)
+		c2 := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(c2).Update("tags", models.JSONBArray{"vip"}).Error)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"contact_ids": []string{c1.ID.String(), c2.ID.String()},
+			"add":         []string{" vip ", "vip"},
+			"remove":      []string{"cold"},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.BulkTagContacts(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.BulkTagContactsResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, int64(2), resp.Data.Matched)
+		// c2 already had vip and no cold tag
+		assert.Equal(t, int64(1), resp.Data.Affected)
+
+		var updated models.Contact
+		require.NoError(t, app.DB.Where("id = ?", c1.ID).First(&updated).Error)
+		assert.Equal(t, models.JSONBArray{"lead", "vip"}, updated.Tags)
+
+		var op models.ContactTagOperation
+		require.NoError(t, app.DB.Where("id = ?", resp.Data.OperationID).First(&op).Error)
+		assert.Equal(t, user.ID, op.UserID)
+		assert.Equal(t, models.StringArray{"vip"}, op.AddTags)
+		assert.Equal(t, models.StringArray{"cold"}, op.RemoveTags)
+		assert.Len(t, op.ContactIDs, 2)
+		assert.Equal(t, int64(1), op.Affected)
+	})
+
+	t.Run("success - tags contacts by assigned user filter", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		assigned := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(assigned).Update("assigned_user_id", user.ID).Error)
+		unassigned := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"filter": map[string]any{"assigned_user_id": user.ID.String()},
+			"add":    []string{"segment-a"},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		err := app.BulkTagContacts(req)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var stored models.Contact
+		require.NoError(t, app.DB.Where("id = ?", assigned.ID).First(&stored).Error)
+		assert.Equal(t, models.JSONBArray{"segment-a"}, stored.Tags)
+		require.NoError(t, app.DB.Where("id = ?", unassigned.ID).First(&stored).Error)
+		assert.Empty(t, stored.Tags)
+
+		listReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(listReq, org.ID, user.ID)
+		require.NoError(t, app.ListContactTagOperations(listReq))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(listReq))
+
+		var listResp struct {
+			Data struct {
+				Operations []models.ContactTagOperation `json:"operations"`
+				Total      int64                        `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(listReq), &listResp))
+		require.Len(t, listResp.Data.Operations, 1)
+		assert.Equal(t, int64(1), listResp.Data.Operations[0].Affected)
+		assert.Equal(t, user.ID.String(), listResp.Data.Operations[0].Filter["assigned_user_id"])
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		bodies := []map[string]any{
+			{"add": []string{"vip"}},
+			{"contact_ids": []string{contact.ID.String()}, "add": []string{" "}},
+			{"contact_ids": []string{contact.ID.String()}, "add": []string{"vip"}, "remove": []string{"vip"}},
+		}
+		for _, body := range bodies {
+			req := testutil.NewJSONRequest(t, body)
+			testutil.SetAuthContext(req, org.ID, user.ID)
+
+			require.NoError(t, app.BulkTagContacts(req))
+			assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+		}
+	})
+
+	t.Run("cross-org contacts are not tagged", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org1 := testutil.CreateTestOrganization(t, app.DB)
+		org2 := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org1.ID)
+		user1 := testutil.CreateTestUser(t, app.DB, org1.ID, testutil.WithRoleID(&adminRole.ID))
+		other := testutil.CreateTestContact(t, app.DB, org2.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"contact_ids": []string{other.ID.String()},
+			"add":         []string{"vip"},
+		})
+		testutil.SetAuthContext(req, org1.ID, user1.ID)
+
+		require.NoError(t, app.BulkTagContacts(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var stored models.Contact
+		require.NoError(t, app.DB.Where("id = ?", other.ID).First(&stored).Error)
+		assert.Empty(t, stored.Tags)
+	})
+}
+
+// --- ListContacts additional tests ---
+
+func TestApp_ListContacts_SearchByProfileName(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+	// Create contact with a unique profile name
+	contact := &models.Contact{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		PhoneNumber:    "+1111111111",
+		ProfileName:    "UniqueAlphaName",
+	}
+	require.NoError(t, app.DB.Create(contact).Error)
+
+	// Create another contact with a different name
+	testutil.CreateTestContact(t, app.DB, org.ID)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetQueryParam(req, "search", "UniqueAlpha")
 
 	err := app.ListContacts(req)
 	require.NoError(t, err)
 	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
 	var resp struct {
-		Data struct {
-			Contacts []handlers.ContactResponse `json:"contacts"`
-			Total    int64                      `json:"total"`
-			Page     int                        `json:"page"`
-			Limit    int                        `json:"limit"`
-		} `json:"data"`
+		Data struct {
+			Contacts []handlers.ContactResponse `json:"contacts"`
+			Total    int64                      `json:"total"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, int64(1), resp.Data.Total)
+	require.Len(t, resp.Data.Contacts, 1)
+	assert.Equal(t, "UniqueAlphaName", resp.Data.Contacts[0].ProfileName)
+}
+
+func TestApp_ListContacts_Page2(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+	// Create 5 contacts
+	for i := 0; i < 5; i++ {
+		testutil.CreateTestContact(t, app.DB, org.ID)
+	}
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetQueryParam(req, "page", 2)
+	testutil.SetQueryParam(req, "limit", 2)
+
+	err := app.ListContacts(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data struct {
+			Contacts []handlers.ContactResponse `json:"contacts"`
+			Total    int64                      `json:"total"`
+			Page     int                        `json:"page"`
+			Limit    int                        `json:"limit"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, int64(5), resp.Data.Total)
+	assert.Len(t, resp.Data.Contacts, 2)
+	assert.Equal(t, 2, resp.Data.Page)
+	assert.Equal(t, 2, resp.Data.Limit)
+}
+
+func TestApp_ListContacts_Sort(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+	now := time.Now().UTC().Truncate(time.Second)
+	older := testutil.CreateTestContact(t, app.DB, org.ID)
+	newer := testutil.CreateTestContact(t, app.DB, org.ID)
+	silent := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(older).Updates(map[string]any{
+		"last_message_at":        now.Add(-time.Hour),
+		"last_message_direction": models.DirectionIncoming,
+		"last_inbound_at":        now.Add(-time.Hour),
+	}).Error)
+	require.NoError(t, app.DB.Model(newer).Updates(map[string]any{
+		"last_message_at":        now,
+		"last_message_direction": models.DirectionOutgoing,
+		"last_inbound_at":        now.Add(-2 * time.Hour),
+	}).Error)
+
+	list := func(t *testing.T, query map[string]string) (int, []handlers.ContactResponse) {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		for k, v := range query {
+			testutil.SetQueryParam(req, k, v)
+		}
+		require.NoError(t, app.ListContacts(req))
+
+		var resp struct {
+			Data struct {
+				Contacts []handlers.ContactResponse `json:"contacts"`
+			} `json:"data"`
+		}
+		status := testutil.GetResponseStatusCode(req)
+		if status == fasthttp.StatusOK {
+			require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		}
+		return status, resp.Data.Contacts
+	}
+	ids := func(contacts []handlers.ContactResponse) []uuid.UUID {
+		out := make([]uuid.UUID, len(contacts))
+		for i, c := range contacts {
+			out[i] = c.ID
+		}
+		return out
+	}
+
+	t.Run("defaults to most recent message first", func(t *testing.T) {
+		status, contacts := list(t, nil)
+		require.Equal(t, fasthttp.StatusOK, status)
+		assert.Equal(t, []uuid.UUID{newer.ID, older.ID, silent.ID}, ids(contacts))
+		assert.Equal(t, models.DirectionOutgoing, contacts[0].LastMessageDirection)
+		assert.Equal(t, models.DirectionIncoming, contacts[1].LastMessageDirection)
+		assert.Empty(t, contacts[2].LastMessageDirection)
+	})
+
+	t.Run("oldest message first keeps contacts without messages last", func(t *testing.T) {
+		status, contacts := list(t, map[string]string{"order": "asc"})
+		require.Equal(t, fasthttp.StatusOK, status)
+		assert.Equal(t, []uuid.UUID{older.ID, newer.ID, silent.ID}, ids(contacts))
+	})
+
+	t.Run("by last inbound message", func(t *testing.T) {
+		status, contacts := list(t, map[string]string{"sort": "last_inbound_at"})
+		require.Equal(t, fasthttp.StatusOK, status)
+		assert.Equal(t, []uuid.UUID{older.ID, newer.ID, silent.ID}, ids(contacts))
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		status, _ := list(t, map[string]string{"sort": "phone_number"})
+		assert.Equal(t, fasthttp.StatusBadRequest, status)
+
+		status, _ = list(t, map[string]string{"order": "sideways"})
+		assert.Equal(t, fasthttp.StatusBadRequest, status)
+	})
+}
+
+// --- GetContact additional tests ---
+
+func TestApp_GetContact_WithAssignedUser(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	assignee := testutil.CreateTestUser(t, app.DB, org.ID)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	// Assign the contact
+	require.NoError(t, app.DB.Model(&contact).Update("assigned_user_id", assignee.ID).Error)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+
+	err := app.GetContact(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.ContactResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.NotNil(t, resp.Data.AssignedUserID)
+	assert.Equal(t, assignee.ID, *resp.Data.AssignedUserID)
+}
+
+func TestApp_GetContact_MultipleUnreadMessages(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	// Create 3 unread incoming messages
+	for i := 0; i < 3; i++ {
+		msg := &models.Message{
+			BaseModel:       models.BaseModel{ID: uuid.New()},
+			OrganizationID:  org.ID,
+			WhatsAppAccount: account.Name,
+			ContactID:       contact.ID,
+			Direction:       models.DirectionIncoming,
+			MessageType:     models.MessageTypeText,
+			Content:         "Unread message",
+			Status:          models.MessageStatusDelivered,
+		}
+		require.NoError(t, app.DB.Create(msg).Error)
+	}
+
+	// Create 1 read incoming message
+	readMsg := &models.Message{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		ContactID:       contact.ID,
+		Direction:       models.DirectionIncoming,
+		MessageType:     models.MessageTypeText,
+		Content:         "Read message",
+		Status:          models.MessageStatusRead,
+	}
+	require.NoError(t, app.DB.Create(readMsg).Error)
+
+	// Create 1 outgoing message (should not count as unread)
+	outMsg := &models.Message{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		ContactID:       contact.ID,
+		Direction:       models.DirectionOutgoing,
+		MessageType:     models.MessageTypeText,
+		Content:         "Outgoing message",
+		Status:          models.MessageStatusSent,
+	}
+	require.NoError(t, app.DB.Create(outMsg).Error)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+
+	err := app.GetContact(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.ContactResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	// Only 3 incoming delivered (not read) messages should be counted
+	assert.Equal(t, 3, resp.Data.UnreadCount)
+}
+
+// --- GetContactSessionData additional tests ---
+
+func TestApp_GetContactSessionData_CompletedSession(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	completedAt := time.Now()
+	session := &models.ChatbotSession{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		ContactID:       contact.ID,
+		WhatsAppAccount: account.Name,
+		PhoneNumber:     contact.PhoneNumber,
+		Status:          models.SessionStatusCompleted,
+		SessionData:     models.JSONB{"order_id": "ORD-123", "amount": 99.99},
+		StartedAt:       time.Now().Add(-1 * time.Hour),
+		LastActivityAt:  time.Now(),
+		CompletedAt:     &completedAt,
+	}
+	require.NoError(t, app.DB.Create(session).Error)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+
+	err := app.GetContactSessionData(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.ContactSessionDataResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.NotNil(t, resp.Data.SessionID)
+	assert.Equal(t, session.ID, *resp.Data.SessionID)
+}
+
+func TestApp_GetContactSessionData_MostRecentSessionReturned(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	// Create an older completed session
+	oldSession := &models.ChatbotSession{
+		BaseModel:       models.BaseModel{ID: uuid.New(), CreatedAt: time.Now().Add(-2 * time.Hour)},
+		OrganizationID:  org.ID,
+		ContactID:       contact.ID,
+		WhatsAppAccount: account.Name,
+		PhoneNumber:     contact.PhoneNumber,
+		Status:          models.SessionStatusCompleted,
+		SessionData:     models.JSONB{"key": "old"},
+		StartedAt:       time.Now().Add(-2 * time.Hour),
+		LastActivityAt:  time.Now().Add(-2 * time.Hour),
+	}
+	require.NoError(t, app.DB.Create(oldSession).Error)
+
+	// Create a newer active session
+	newSession := &models.ChatbotSession{
+		BaseModel:       models.BaseModel{ID: uuid.New(), CreatedAt: time.Now()},
+		OrganizationID:  org.ID,
+		ContactID:       contact.ID,
+		WhatsAppAccount: account.Name,
+		PhoneNumber:     contact.PhoneNumber,
+		Status:          models.SessionStatusActive,
+		SessionData:     models.JSONB{"key": "new"},
+		StartedAt:       time.Now(),
+		LastActivityAt:  time.Now(),
+	}
+	require.NoError(t, app.DB.Create(newSession).Error)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+
+	err := app.GetContactSessionData(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.ContactSessionDataResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	// Should return the most recent session
+	require.NotNil(t, resp.Data.SessionID)
+	assert.Equal(t, newSession.ID, *resp.Data.SessionID)
+}
+
+// --- AssignContact additional tests ---
+
+func TestApp_AssignContact_ReassignToAnotherUser(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	assignee1 := testutil.CreateTestUser(t, app.DB, org.ID)
+	assignee2 := testutil.CreateTestUser(t, app.DB, org.ID)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	// Pre-assign to assignee1
+	require.NoError(t, app.DB.Model(&contact).Update("assigned_user_id", assignee1.ID).Error)
+
+	// Reassign to assignee2
+	req := testutil.NewJSONRequest(t, map[string]interface{}{
+		"user_id": assignee2.ID.String(),
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+
+	err := app.AssignContact(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data struct {
+			AssignedUserID *uuid.UUID `json:"assigned_user_id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	require.NotNil(t, resp.Data.AssignedUserID)
+	assert.Equal(t, assignee2.ID, *resp.Data.AssignedUserID)
+
+	// Verify in database
+	var updatedContact models.Contact
+	require.NoError(t, app.DB.Where("id = ?", contact.ID).First(&updatedContact).Error)
+	require.NotNil(t, updatedContact.AssignedUserID)
+	assert.Equal(t, assignee2.ID, *updatedContact.AssignedUserID)
+}
+
+func TestApp_GetContactAssignmentHistory(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	assignee1 := testutil.CreateTestUser(t, app.DB, org.ID)
+	assignee2 := testutil.CreateTestUser(t, app.DB, org.ID)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	assign := func(userID *uuid.UUID) {
+		body := map[string]any{"user_id": nil}
+		if userID != nil {
+			body["user_id"] = userID.String()
+		}
+		req := testutil.NewJSONRequest(t, body)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		require.NoError(t, app.AssignContact(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+	}
+	assign(&assignee1.ID)
+	assign(&assignee2.ID)
+	assign(&assignee2.ID) // Unchanged, not recorded
+	assign(nil)
+
+	type historyResponse struct {
+		Data struct {
+			Assignments []models.ContactAssignment `json:"assignments"`
+			Total       int64                      `json:"total"`
+		} `json:"data"`
+	}
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+	require.NoError(t, app.GetContactAssignmentHistory(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp historyResponse
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, int64(3), resp.Data.Total)
+	require.Len(t, resp.Data.Assignments, 3)
+
+	// Newest first: assignee2 -> nobody, assignee1 -> assignee2, nobody -> assignee1
+	latest := resp.Data.Assignments[0]
+	require.NotNil(t, latest.FromUserID)
+	assert.Equal(t, assignee2.ID, *latest.FromUserID)
+	assert.Nil(t, latest.ToUserID)
+	assert.Equal(t, user.ID, latest.AssignedByID)
+	require.NotNil(t, latest.AssignedBy)
+	assert.Equal(t, user.FullName, latest.AssignedBy.FullName)
+
+	handoff := resp.Data.Assignments[1]
+	require.NotNil(t, handoff.FromUserID)
+	require.NotNil(t, handoff.ToUserID)
+	assert.Equal(t, assignee1.ID, *handoff.FromUserID)
+	assert.Equal(t, assignee2.ID, *handoff.ToUserID)
+
+	first := resp.Data.Assignments[2]
+	assert.Nil(t, first.FromUserID)
+	require.NotNil(t, first.ToUserID)
+	assert.Equal(t, assignee1.ID, *first.ToUserID)
+
+	t.Run("paginated", func(t *testing.T) {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		testutil.SetQueryParam(req, "limit", 2)
+		testutil.SetQueryParam(req, "page", 2)
+		require.NoError(t, app.GetContactAssignmentHistory(req))
+
+		var resp historyResponse
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, int64(3), resp.Data.Total)
+		require.Len(t, resp.Data.Assignments, 1)
+		assert.Equal(t, first.ID, resp.Data.Assignments[0].ID)
+	})
+
+	t.Run("other organization", func(t *testing.T) {
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		otherRole := testutil.CreateAdminRole(t, app.DB, otherOrg.ID)
+		otherUser := testutil.CreateTestUser(t, app.DB, otherOrg.ID, testutil.WithRoleID(&otherRole.ID))
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, otherOrg.ID, otherUser.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+		require.NoError(t, app.GetContactAssignmentHistory(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_AssignContact_AssignUserFromDifferentOrg(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org1 := testutil.CreateTestOrganization(t, app.DB)
+	org2 := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org1.ID)
+	user := testutil.CreateTestUser(t, app.DB, org1.ID, testutil.WithRoleID(&adminRole.ID))
+	contact := testutil.CreateTestContact(t, app.DB, org1.ID)
+
+	// Create a user in a different org
+	otherOrgUser := testutil.CreateTestUser(t, app.DB, org2.ID)
+
+	req := testutil.NewJSONRequest(t, map[string]interface{}{
+		"user_id": otherOrgUser.ID.String(),
+	})
+	testutil.SetAuthContext(req, org1.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+
+	err := app.AssignContact(req)
+	require.NoError(t, err)
+	// User from a different org should not be found
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+}
+
+func TestApp_GetUnreadSummary(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	agent := testutil.CreateTestUser(t, app.DB, org.ID)
+	otherAgent := testutil.CreateTestUser(t, app.DB, org.ID)
+
+	// newContact creates a contact with an optional assignee and one incoming message in the given status
+	newContact := func(assignee *uuid.UUID, status models.MessageStatus) *models.Contact {
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+		if assignee != nil {
+			require.NoError(t, app.DB.Model(contact).Update("assigned_user_id", *assignee).Error)
+		}
+		msg := &models.Message{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			OrganizationID: org.ID,
+			ContactID:      contact.ID,
+			Direction:      models.DirectionIncoming,
+			MessageType:    models.MessageTypeText,
+			Content:        "Hi",
+			Status:         status,
+		}
+		require.NoError(t, app.DB.Create(msg).Error)
+		return contact
+	}
+
+	// Two unread conversations for the agent, one of them with several unread messages
+	busy := newContact(&agent.ID, models.MessageStatusDelivered)
+	require.NoError(t, app.DB.Create(&models.Message{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		ContactID:      busy.ID,
+		Direction:      models.DirectionIncoming,
+		MessageType:    models.MessageTypeText,
+		Content:        "Hello?",
+		Status:         models.MessageStatusReceived,
+	}).Error)
+	newContact(&agent.ID, models.MessageStatusReceived)
+	newContact(&agent.ID, models.MessageStatusRead)
+	newContact(&otherAgent.ID, models.MessageStatusDelivered)
+	newContact(nil, models.MessageStatusDelivered)
+	newContact(nil, models.MessageStatusRead)
+
+	// Unread messages in another org are not counted
+	otherOrg := testutil.CreateTestOrganization(t, app.DB)
+	otherContact := testutil.CreateTestContact(t, app.DB, otherOrg.ID)
+	require.NoError(t, app.DB.Create(&models.Message{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: otherOrg.ID,
+		ContactID:      otherContact.ID,
+		Direction:      models.DirectionIncoming,
+		MessageType:    models.MessageTypeText,
+		Content:        "Hi",
+		Status:         models.MessageStatusDelivered,
+	}).Error)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, agent.ID)
+
+	require.NoError(t, app.GetUnreadSummary(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.UnreadSummary `json:"data"`
 	}
 	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
-	assert.Equal(t, int64(5), resp.Data.Total)
-	assert.Len(t, resp.Data.Contacts, 2)
-	assert.Equal(t, 2, resp.Data.Page)
-	assert.Equal(t, 2, resp.Data.Limit)
+	assert.Equal(t, int64(2), resp.Data.AssignedToMe)
+	assert.Equal(t, int64(1), resp.Data.Unassigned)
 }
 
-// --- GetContact additional tests ---
+// --- Chatbot Pause Tests ---
 
-func TestApp_GetContact_WithAssignedUser(t *testing.T) {
+func TestApp_PauseChatbotForContact(t *testing.T) {
 	t.Parallel()
 
-	app := newTestApp(t)
-	org := testutil.CreateTestOrganization(t, app.DB)
-	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
-	assignee := testutil.CreateTestUser(t, app.DB, org.ID)
-	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	t.Run("pause with auto-resume then resume", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
 
-	// Assign the contact
-	require.NoError(t, app.DB.Model(&contact).Update("assigned_user_id", assignee.ID).Error)
+		req := testutil.NewJSONRequest(t, map[string]any{"minutes": 30})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+
+		require.NoError(t, app.PauseChatbotForContact(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.ChatbotPauseResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.True(t, resp.Data.BotPaused)
+		require.NotNil(t, resp.Data.BotResumeAt)
+		assert.WithinDuration(t, time.Now().Add(30*time.Minute), *resp.Data.BotResumeAt, time.Minute)
+
+		var paused models.Contact
+		require.NoError(t, app.DB.First(&paused, contact.ID).Error)
+		assert.True(t, paused.BotPaused)
+		require.NotNil(t, paused.BotResumeAt)
+
+		resumeReq := testutil.NewJSONRequest(t, map[string]any{})
+		testutil.SetAuthContext(resumeReq, org.ID, user.ID)
+		testutil.SetPathParam(resumeReq, "id", contact.ID.String())
+
+		require.NoError(t, app.ResumeChatbotForContact(resumeReq))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(resumeReq))
+
+		var resumed models.Contact
+		require.NoError(t, app.DB.First(&resumed, contact.ID).Error)
+		assert.False(t, resumed.BotPaused)
+		assert.Nil(t, resumed.BotResumeAt)
+	})
+
+	t.Run("pause until resumed", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+
+		require.NoError(t, app.PauseChatbotForContact(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.ChatbotPauseResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.True(t, resp.Data.BotPaused)
+		assert.Nil(t, resp.Data.BotResumeAt)
+	})
+
+	t.Run("invalid minutes", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"minutes": -5})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+
+		require.NoError(t, app.PauseChatbotForContact(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("contact in another org", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, otherOrg.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"minutes": 10})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+
+		require.NoError(t, app.PauseChatbotForContact(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+
+		var unchanged models.Contact
+		require.NoError(t, app.DB.First(&unchanged, contact.ID).Error)
+		assert.False(t, unchanged.BotPaused)
+	})
+}
+
+// --- Conversation Status Tests ---
+
+func TestApp_UpdateConversationStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("snooze then list by status", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		snoozed := testutil.CreateTestContact(t, app.DB, org.ID)
+		open := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		until := time.Now().Add(2 * time.Hour).UTC()
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"status":       "snoozed",
+			"snooze_until": until.Format(time.RFC3339),
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", snoozed.ID.String())
+
+		require.NoError(t, app.UpdateConversationStatus(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.ConversationStatusResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, models.ConversationStatusSnoozed, resp.Data.ConversationStatus)
+		require.NotNil(t, resp.Data.SnoozeUntil)
+		assert.WithinDuration(t, until, *resp.Data.SnoozeUntil, time.Second)
+
+		listReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(listReq, org.ID, user.ID)
+		testutil.SetQueryParam(listReq, "conversation_status", "snoozed")
+
+		require.NoError(t, app.ListContacts(listReq))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(listReq))
+
+		var listResp struct {
+			Data struct {
+				Contacts []handlers.ContactResponse `json:"contacts"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(listReq), &listResp))
+		require.Len(t, listResp.Data.Contacts, 1)
+		assert.Equal(t, snoozed.ID, listResp.Data.Contacts[0].ID)
+		assert.Equal(t, models.ConversationStatusSnoozed, listResp.Data.Contacts[0].ConversationStatus)
+
+		openReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(openReq, org.ID, user.ID)
+		testutil.SetQueryParam(openReq, "conversation_status", "open")
+
+		require.NoError(t, app.ListContacts(openReq))
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(openReq), &listResp))
+		require.Len(t, listResp.Data.Contacts, 1)
+		assert.Equal(t, open.ID, listResp.Data.Contacts[0].ID)
+	})
+
+	t.Run("expired snooze reads as open", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(contact).Updates(map[string]any{
+			"conversation_status": models.ConversationStatusSnoozed,
+			"snooze_until":        time.Now().Add(-time.Minute),
+		}).Error)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "conversation_status", "open")
+
+		require.NoError(t, app.ListContacts(req))
+		var resp struct {
+			Data struct {
+				Contacts []handlers.ContactResponse `json:"contacts"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		require.Len(t, resp.Data.Contacts, 1)
+		assert.Equal(t, models.ConversationStatusOpen, resp.Data.Contacts[0].ConversationStatus)
+		assert.Nil(t, resp.Data.Contacts[0].SnoozeUntil)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		cases := []map[string]any{
+			{"status": "archived"},
+			{"status": "snoozed"},
+			{"status": "snoozed", "snooze_until": time.Now().Add(-time.Hour).Format(time.RFC3339)},
+			{"status": "snoozed", "snooze_until": time.Now().Add(400 * 24 * time.Hour).Format(time.RFC3339)},
+			{"status": "resolved", "snooze_until": time.Now().Add(time.Hour).Format(time.RFC3339)},
+		}
+		for _, body := range cases {
+			req := testutil.NewJSONRequest(t, body)
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			testutil.SetPathParam(req, "id", contact.ID.String())
+
+			require.NoError(t, app.UpdateConversationStatus(req))
+			assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req), "body: %v", body)
+		}
+
+		var unchanged models.Contact
+		require.NoError(t, app.DB.First(&unchanged, contact.ID).Error)
+		assert.Equal(t, models.ConversationStatusOpen, unchanged.ConversationStatus)
+	})
+
+	t.Run("resolve unassigns when enabled", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		agent := testutil.CreateTestUser(t, app.DB, org.ID)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(contact).Update("assigned_user_id", agent.ID).Error)
+
+		setStatus := func(status string) handlers.ConversationStatusResponse {
+			req := testutil.NewJSONRequest(t, map[string]any{"status": status})
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			testutil.SetPathParam(req, "id", contact.ID.String())
+			require.NoError(t, app.UpdateConversationStatus(req))
+			require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+			var resp struct {
+				Data handlers.ConversationStatusResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+			return resp.Data
+		}
+
+		// Off by default: resolving keeps the assignee
+		resp := setStatus("resolved")
+		require.NotNil(t, resp.AssignedUserID)
+		assert.Equal(t, agent.ID, *resp.AssignedUserID)
+
+		settingsReq := testutil.NewJSONRequest(t, map[string]any{"unassign_on_resolve": true})
+		testutil.SetAuthContext(settingsReq, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(settingsReq))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(settingsReq))
+
+		// Other statuses leave the assignee alone
+		resp = setStatus("pending")
+		require.NotNil(t, resp.AssignedUserID)
+
+		resp = setStatus("resolved")
+		assert.Nil(t, resp.AssignedUserID)
+
+		var updated models.Contact
+		require.NoError(t, app.DB.First(&updated, contact.ID).Error)
+		assert.Equal(t, models.ConversationStatusResolved, updated.ConversationStatus)
+		assert.Nil(t, updated.AssignedUserID)
+
+		// The unassignment shows up in the assignment history
+		var history []models.ContactAssignment
+		require.NoError(t, app.DB.Where("contact_id = ?", contact.ID).Find(&history).Error)
+		require.Len(t, history, 1)
+		require.NotNil(t, history[0].FromUserID)
+		assert.Equal(t, agent.ID, *history[0].FromUserID)
+		assert.Nil(t, history[0].ToUserID)
+		assert.Equal(t, user.ID, history[0].AssignedByID)
+
+		// A contact reassigned after resolving stays with the new assignee until resolved again
+		require.NoError(t, app.DB.Model(contact).Update("assigned_user_id", agent.ID).Error)
+		resp = setStatus("open")
+		require.NotNil(t, resp.AssignedUserID)
+		assert.Equal(t, agent.ID, *resp.AssignedUserID)
+	})
+
+	t.Run("contact in another org", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, otherOrg.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"status": "resolved"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
+
+		require.NoError(t, app.UpdateConversationStatus(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_BulkUpdateConversationStatus(t *testing.T) {
+	t.Parallel()
+
+	bulkUpdate := func(t *testing.T, app *handlers.App, orgID, userID uuid.UUID, body map[string]any) (int, handlers.BulkUpdateConversationStatusResponse) {
+		req := testutil.NewJSONRequest(t, body)
+		testutil.SetAuthContext(req, orgID, userID)
+		require.NoError(t, app.BulkUpdateConversationStatus(req))
+
+		var resp struct {
+			Data handlers.BulkUpdateConversationStatusResponse `json:"data"`
+		}
+		status := testutil.GetResponseStatusCode(req)
+		if status == fasthttp.StatusOK {
+			require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		}
+		return status, resp.Data
+	}
+
+	t.Run("resolves contacts matched by filter", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		openA := testutil.CreateTestContact(t, app.DB, org.ID)
+		openB := testutil.CreateTestContact(t, app.DB, org.ID)
+		pending := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(pending).Update("conversation_status", models.ConversationStatusPending).Error)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		foreign := testutil.CreateTestContact(t, app.DB, otherOrg.ID)
+
+		status, resp := bulkUpdate(t, app, org.ID, user.ID, map[string]any{
+			"filter": map[string]any{"conversation_status": []string{"open"}},
+			"status": "resolved",
+		})
+		require.Equal(t, fasthttp.StatusOK, status)
+		assert.Equal(t, int64(2), resp.Matched)
+		assert.Equal(t, int64(2), resp.Updated)
+		assert.Equal(t, int64(0), resp.Unassigned)
+
+		for id, want := range map[uuid.UUID]models.ConversationStatus{
+			openA.ID:   models.ConversationStatusResolved,
+			openB.ID:   models.ConversationStatusResolved,
+			pending.ID: models.ConversationStatusPending,
+			foreign.ID: models.ConversationStatusOpen,
+		} {
+			var c models.Contact
+			require.NoError(t, app.DB.First(&c, id).Error)
+			assert.Equal(t, want, c.ConversationStatus)
+		}
+	})
+
+	t.Run("counts only contacts that change and unassigns when enabled", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		agent := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		assigned := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(assigned).Update("assigned_user_id", agent.ID).Error)
+		resolved := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(resolved).Update("conversation_status", models.ConversationStatusResolved).Error)
+
+		settingsReq := testutil.NewJSONRequest(t, map[string]any{"unassign_on_resolve": true})
+		testutil.SetAuthContext(settingsReq, org.ID, user.ID)
+		require.NoError(t, app.UpdateChatbotSettings(settingsReq))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(settingsReq))
+
+		status, resp := bulkUpdate(t, app, org.ID, user.ID, map[string]any{
+			"contact_ids": []uuid.UUID{assigned.ID, resolved.ID},
+			"status":      "resolved",
+		})
+		require.Equal(t, fasthttp.StatusOK, status)
+		assert.Equal(t, int64(2), resp.Matched)
+		assert.Equal(t, int64(1), resp.Updated)
+		assert.Equal(t, int64(1), resp.Unassigned)
+
+		var updated models.Contact
+		require.NoError(t, app.DB.First(&updated, assigned.ID).Error)
+		assert.Equal(t, models.ConversationStatusResolved, updated.ConversationStatus)
+		assert.Nil(t, updated.AssignedUserID)
+
+		var history []models.ContactAssignment
+		require.NoError(t, app.DB.Where("contact_id = ?", assigned.ID).Find(&history).Error)
+		require.Len(t, history, 1)
+		assert.Nil(t, history[0].ToUserID)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		cases := []map[string]any{
+			{"status": "resolved"},
+			{"contact_ids": []uuid.UUID{contact.ID}, "filter": map[string]any{"search": "x"}, "status": "resolved"},
+			{"contact_ids": []uuid.UUID{contact.ID}, "status": "archived"},
+			{"contact_ids": []uuid.UUID{contact.ID}, "status": "snoozed"},
+		}
+		for _, body := range cases {
+			status, _ := bulkUpdate(t, app, org.ID, user.ID, body)
+			assert.Equal(t, fasthttp.StatusBadRequest, status, "body: %v", body)
+		}
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateTestRoleWithKeys(t, app.DB, org.ID, "chat-reader", []string{"chat:read"})
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+		status, _ := bulkUpdate(t, app, org.ID, user.ID, map[string]any{
+			"contact_ids": []uuid.UUID{contact.ID},
+			"status":      "resolved",
+		})
+		assert.Equal(t, fasthttp.StatusForbidden, status)
+	})
+}
+
+func TestApp_ExportContacts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - streams filtered contacts as csv", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID), testutil.WithFullName("Jane Agent"))
+		vip := testutil.CreateTestContact(t, app.DB, org.ID)
+		require.NoError(t, app.DB.Model(vip).Updates(map[string]any{
+			"tags":             models.JSONBArray{"vip", "lead"},
+			"assigned_user_id": user.ID,
+		}).Error)
+		testutil.CreateTestContact(t, app.DB, org.ID)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetQueryParam(req, "tags", "vip")
+
+		require.NoError(t, app.ExportContacts(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		assert.Equal(t, "text/csv", string(req.RequestCtx.Response.Header.ContentType()))
+		assert.Contains(t, string(req.RequestCtx.Response.Header.Peek("Content-Disposition")), "attachment; filename=contacts_export_")
+
+		records, err := csv.NewReader(bytes.NewReader(testutil.GetResponseBody(req))).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, []string{"phone_number", "profile_name", "status", "tags", "assigned_agent", "created_at"}, records[0])
+		assert.Equal(t, vip.PhoneNumber, records[1][0])
+		assert.Equal(t, "active", records[1][2])
+		assert.Equal(t, "vip,lead", records[1][3])
+		assert.Equal(t, "Jane Agent", records[1][4])
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateTestRoleWithKeys(t, app.DB, org.ID, "contacts-reader", []string{"contacts:read"})
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.ExportContacts(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_ImportContacts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success - upserts by phone number", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		existing := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithPhoneNumber("14155550199"))
+
+		csvData := "Phone Number,Name,Tags\n" +
+			"+14155550199,Updated Name,\"vip, lead\"\n" +
+			"+1 (415) 555-0100,New Contact,\n" +
+			"12ab,Bad Phone,\n" +
+			"0123456789,No Country Code,\n"
+		req := testutil.NewMultipartRequest(t, nil, "file", "contacts.csv", []byte(csvData))
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.ImportContacts(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.ContactImportResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, 1, resp.Data.Created)
+		assert.Equal(t, 1, resp.Data.Updated)
+		assert.Equal(t, 2, resp.Data.Errors)
+		require.Len(t, resp.Data.RowErrors, 2)
+		assert.Equal(t, 4, resp.Data.RowErrors[0].Row)
+		assert.Equal(t, "12ab", resp.Data.RowErrors[0].PhoneNumber)
+		assert.Equal(t, 5, resp.Data.RowErrors[1].Row)
+
+		var updated models.Contact
+		require.NoError(t, app.DB.Where("id = ?", existing.ID).First(&updated).Error)
+		assert.Equal(t, "Updated Name", updated.ProfileName)
+		assert.Equal(t, models.JSONBArray{"vip", "lead"}, updated.Tags)
+
+		var created models.Contact
+		require.NoError(t, app.DB.Where("organization_id = ? AND phone_number = ?", org.ID, "14155550100").First(&created).Error)
+		assert.Equal(t, "New Contact", created.ProfileName)
+	})
+
+	t.Run("missing phone column", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		req := testutil.NewMultipartRequest(t, nil, "file", "contacts.csv", []byte("name,tags\nJohn,vip\n"))
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.ImportContacts(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
 
-	req := testutil.NewGETRequest(t)
-	testutil.SetAuthContext(req, org.ID, user.ID)
-	testutil.SetPathParam(req, "id", contact.ID.String())
+	t.Run("permission denied", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		role := testutil.CreateTestRoleWithKeys(t, app.DB, org.ID, "contacts-writer", []string{"contacts:read", "contacts:write"})
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
 
-	err := app.GetContact(req)
-	require.NoError(t, err)
-	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		req := testutil.NewMultipartRequest(t, nil, "file", "contacts.csv", []byte("phone_number\n14155550100\n"))
+		testutil.SetAuthContext(req, org.ID, user.ID)
 
-	var resp struct {
-		Data handlers.ContactResponse `json:"data"`
-	}
-	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
-	assert.NotNil(t, resp.Data.AssignedUserID)
-	assert.Equal(t, assignee.ID, *resp.Data.AssignedUserID)
+		require.NoError(t, app.ImportContacts(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
 }
 
-func TestApp_GetContact_MultipleUnreadMessages(t *testing.T) {
+func TestApp_CreateContact_NormalizesPhoneNumber(t *testing.T) {
 	t.Parallel()
 
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
 	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
 	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
-	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
-	contact := testutil.CreateTestContact(t, app.DB, org.ID)
 
-	// Create 3 unread incoming messages
-	for i := 0; i < 3; i++ {
-		msg := &models.Message{
-			BaseModel:       models.BaseModel{ID: uuid.New()},
-			OrganizationID:  org.ID,
-			WhatsAppAccount: account.Name,
-			ContactID:       contact.ID,
-			Direction:       models.DirectionIncoming,
-			MessageType:     models.MessageTypeText,
-			Content:         "Unread message",
-			Status:          models.MessageStatusDelivered,
-		}
-		require.NoError(t, app.DB.Create(msg).Error)
-	}
+	req := testutil.NewJSONRequest(t, map[string]any{"phone_number": "+1 (202) 555-0176"})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	require.NoError(t, app.CreateContact(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-	// Create 1 read incoming message
-	readMsg := &models.Message{
-		BaseModel:       models.BaseModel{ID: uuid.New()},
-		OrganizationID:  org.ID,
-		WhatsAppAccount: account.Name,
-		ContactID:       contact.ID,
-		Direction:       models.DirectionIncoming,
-		MessageType:     models.MessageTypeText,
-		Content:         "Read message",
-		Status:          models.MessageStatusRead,
-	}
-	require.NoError(t, app.DB.Create(readMsg).Error)
+	var stored models.Contact
+	require.NoError(t, app.DB.Where("organization_id = ?", org.ID).First(&stored).Error)
+	assert.Equal(t, "12025550176", stored.PhoneNumber)
 
-	// Create 1 outgoing message (should not count as unread)
-	outMsg := &models.Message{
-		BaseModel:       models.BaseModel{ID: uuid.New()},
-		OrganizationID:  org.ID,
-		WhatsAppAccount: account.Name,
-		ContactID:       contact.ID,
-		Direction:       models.DirectionOutgoing,
-		MessageType:     models.MessageTypeText,
-		Content:         "Outgoing message",
-		Status:          models.MessageStatusSent,
-	}
-	require.NoError(t, app.DB.Create(outMsg).Error)
+	// The same number in another format is a duplicate
+	req = testutil.NewJSONRequest(t, map[string]any{"phone_number": "12025550176"})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	require.NoError(t, app.CreateContact(req))
+	assert.Equal(t, fasthttp.StatusConflict, testutil.GetResponseStatusCode(req))
 
-	req := testutil.NewGETRequest(t)
+	req = testutil.NewJSONRequest(t, map[string]any{"phone_number": "call me"})
 	testutil.SetAuthContext(req, org.ID, user.ID)
-	testutil.SetPathParam(req, "id", contact.ID.String())
+	require.NoError(t, app.CreateContact(req))
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 
-	err := app.GetContact(req)
-	require.NoError(t, err)
-	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+	// Numbers outside any numbering plan are rejected
+	req = testutil.NewJSONRequest(t, map[string]any{"phone_number": "+1 555 010 2030"})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	require.NoError(t, app.CreateContact(req))
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+	// Search ignores formatting
+	listReq := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(listReq, org.ID, user.ID)
+	testutil.SetQueryParam(listReq, "search", "+1 (202) 555")
+	require.NoError(t, app.ListContacts(listReq))
 
 	var resp struct {
-		Data handlers.ContactResponse `json:"data"`
+		Data struct {
+			Contacts []handlers.ContactResponse `json:"contacts"`
+		} `json:"data"`
 	}
-	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
-	// Only 3 incoming delivered (not read) messages should be counted
-	assert.Equal(t, 3, resp.Data.UnreadCount)
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(listReq), &resp))
+	require.Len(t, resp.Data.Contacts, 1)
+	assert.Equal(t, stored.ID, resp.Data.Contacts[0].ID)
 }
 
-// --- GetContactSessionData additional tests ---
-
-func TestApp_GetContactSessionData_CompletedSession(t *testing.T) {
+func TestApp_CreateContact_DefaultPhoneRegion(t *testing.T) {
 	t.Parallel()
 
 	app := newTestApp(t)
+	app.Config.WhatsApp.DefaultPhoneRegion = "IN"
 	org := testutil.CreateTestOrganization(t, app.DB)
 	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
 	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
-	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
-	contact := testutil.CreateTestContact(t, app.DB, org.ID)
-
-	completedAt := time.Now()
-	session := &models.ChatbotSession{
-		BaseModel:       models.BaseModel{ID: uuid.New()},
-		OrganizationID:  org.ID,
-		ContactID:       contact.ID,
-		WhatsAppAccount: account.Name,
-		PhoneNumber:     contact.PhoneNumber,
-		Status:          models.SessionStatusCompleted,
-		SessionData:     models.JSONB{"order_id": "ORD-123", "amount": 99.99},
-		StartedAt:       time.Now().Add(-1 * time.Hour),
-		LastActivityAt:  time.Now(),
-		CompletedAt:     &completedAt,
-	}
-	require.NoError(t, app.DB.Create(session).Error)
 
-	req := testutil.NewGETRequest(t)
+	req := testutil.NewJSONRequest(t, map[string]any{"phone_number": "098765 43210"})
 	testutil.SetAuthContext(req, org.ID, user.ID)
-	testutil.SetPathParam(req, "id", contact.ID.String())
-
-	err := app.GetContactSessionData(req)
-	require.NoError(t, err)
+	require.NoError(t, app.CreateContact(req))
 	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-	var resp struct {
-		Data handlers.ContactSessionDataResponse `json:"data"`
-	}
-	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
-	assert.NotNil(t, resp.Data.SessionID)
-	assert.Equal(t, session.ID, *resp.Data.SessionID)
+	var stored models.Contact
+	require.NoError(t, app.DB.Where("organization_id = ?", org.ID).First(&stored).Error)
+	assert.Equal(t, "919876543210", stored.PhoneNumber)
 }
 
-func TestApp_GetContactSessionData_MostRecentSessionReturned(t *testing.T) {
+func TestApp_ScheduledMessages(t *testing.T) {
 	t.Parallel()
 
-	app := newTestApp(t)
-	org := testutil.CreateTestOrganization(t, app.DB)
-	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
-	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
-	contact := testutil.CreateTestContact(t, app.DB, org.ID)
-
-	// Create an older completed session
-	oldSession := &models.ChatbotSession{
-		BaseModel:       models.BaseModel{ID: uuid.New(), CreatedAt: time.Now().Add(-2 * time.Hour)},
-		OrganizationID:  org.ID,
-		ContactID:       contact.ID,
-		WhatsAppAccount: account.Name,
-		PhoneNumber:     contact.PhoneNumber,
-		Status:          models.SessionStatusCompleted,
-		SessionData:     models.JSONB{"key": "old"},
-		StartedAt:       time.Now().Add(-2 * time.Hour),
-		LastActivityAt:  time.Now().Add(-2 * time.Hour),
-	}
-	require.NoError(t, app.DB.Create(oldSession).Error)
+	t.Run("schedule, list and cancel", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
 
-	// Create a newer active session
-	newSession := &models.ChatbotSession{
-		BaseModel:       models.BaseModel{ID: uuid.New(), CreatedAt: time.Now()},
-		OrganizationID:  org.ID,
-		ContactID:       contact.ID,
-		WhatsAppAccount: account.Name,
-		PhoneNumber:     contact.PhoneNumber,
-		Status:          models.SessionStatusActive,
-		SessionData:     models.JSONB{"key": "new"},
-		StartedAt:       time.Now(),
-		LastActivityAt:  time.Now(),
-	}
-	require.NoError(t, app.DB.Create(newSession).Error)
+		sendAt := time.Now().Add(time.Hour).UTC()
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"type":    "text",
+			"content": map[string]string{"body": "Reminder: your appointment is tomorrow"},
+			"send_at": sendAt.Format(time.RFC3339),
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
 
-	req := testutil.NewGETRequest(t)
-	testutil.SetAuthContext(req, org.ID, user.ID)
-	testutil.SetPathParam(req, "id", contact.ID.String())
+		require.NoError(t, app.ScheduleMessage(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
 
-	err := app.GetContactSessionData(req)
-	require.NoError(t, err)
-	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		var resp struct {
+			Data handlers.ScheduledMessageResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, models.ScheduledMessageStatusPending, resp.Data.Status)
+		assert.Equal(t, "Reminder: your appointment is tomorrow", resp.Data.Content["body"])
+		assert.WithinDuration(t, sendAt, resp.Data.SendAt, time.Second)
 
-	var resp struct {
-		Data handlers.ContactSessionDataResponse `json:"data"`
-	}
-	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
-	// Should return the most recent session
-	require.NotNil(t, resp.Data.SessionID)
-	assert.Equal(t, newSession.ID, *resp.Data.SessionID)
-}
+		listReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(listReq, org.ID, user.ID)
+		testutil.SetPathParam(listReq, "id", contact.ID.String())
 
-// --- AssignContact additional tests ---
+		require.NoError(t, app.ListScheduledMessages(listReq))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(listReq))
 
-func TestApp_AssignContact_ReassignToAnotherUser(t *testing.T) {
-	t.Parallel()
+		var listResp struct {
+			Data struct {
+				ScheduledMessages []handlers.ScheduledMessageResponse `json:"scheduled_messages"`
+				Total             int64                               `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(listReq), &listResp))
+		require.Len(t, listResp.Data.ScheduledMessages, 1)
+		assert.Equal(t, resp.Data.ID, listResp.Data.ScheduledMessages[0].ID)
+
+		cancelReq := testutil.NewRequest(t)
+		testutil.SetAuthContext(cancelReq, org.ID, user.ID)
+		testutil.SetPathParam(cancelReq, "id", contact.ID.String())
+		testutil.SetPathParam(cancelReq, "scheduled_id", resp.Data.ID.String())
+
+		require.NoError(t, app.CancelScheduledMessage(cancelReq))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(cancelReq))
+
+		var stored models.ScheduledMessage
+		require.NoError(t, app.DB.Where("id = ?", resp.Data.ID).First(&stored).Error)
+		assert.Equal(t, models.ScheduledMessageStatusCancelled, stored.Status)
+
+		// Cancelling again conflicts
+		againReq := testutil.NewRequest(t)
+		testutil.SetAuthContext(againReq, org.ID, user.ID)
+		testutil.SetPathParam(againReq, "id", contact.ID.String())
+		testutil.SetPathParam(againReq, "scheduled_id", resp.Data.ID.String())
+
+		require.NoError(t, app.CancelScheduledMessage(againReq))
+		assert.Equal(t, fasthttp.StatusConflict, testutil.GetResponseStatusCode(againReq))
+	})
 
-	app := newTestApp(t)
-	org := testutil.CreateTestOrganization(t, app.DB)
-	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
-	assignee1 := testutil.CreateTestUser(t, app.DB, org.ID)
-	assignee2 := testutil.CreateTestUser(t, app.DB, org.ID)
-	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	t.Run("rejects send_at in the past", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
 
-	// Pre-assign to assignee1
-	require.NoError(t, app.DB.Model(&contact).Update("assigned_user_id", assignee1.ID).Error)
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"type":    "text",
+			"content": map[string]string{"body": "Too late"},
+			"send_at": time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
 
-	// Reassign to assignee2
-	req := testutil.NewJSONRequest(t, map[string]interface{}{
-		"user_id": assignee2.ID.String(),
+		require.NoError(t, app.ScheduleMessage(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 	})
-	testutil.SetAuthContext(req, org.ID, user.ID)
-	testutil.SetPathParam(req, "id", contact.ID.String())
-
-	err := app.AssignContact(req)
-	require.NoError(t, err)
-	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
-
-	var resp struct {
-		Data struct {
-			AssignedUserID *uuid.UUID `json:"assigned_user_id"`
-		} `json:"data"`
-	}
-	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
-	require.NotNil(t, resp.Data.AssignedUserID)
-	assert.Equal(t, assignee2.ID, *resp.Data.AssignedUserID)
 
-	// Verify in database
-	var updatedContact models.Contact
-	require.NoError(t, app.DB.Where("id = ?", contact.ID).First(&updatedContact).Error)
-	require.NotNil(t, updatedContact.AssignedUserID)
-	assert.Equal(t, assignee2.ID, *updatedContact.AssignedUserID)
-}
+	t.Run("rejects unknown WhatsApp account", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		contact := testutil.CreateTestContact(t, app.DB, org.ID)
 
-func TestApp_AssignContact_AssignUserFromDifferentOrg(t *testing.T) {
-	t.Parallel()
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"type":             "text",
+			"content":          map[string]string{"body": "Hello"},
+			"whatsapp_account": "missing-account",
+			"send_at":          time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", contact.ID.String())
 
-	app := newTestApp(t)
-	org1 := testutil.CreateTestOrganization(t, app.DB)
-	org2 := testutil.CreateTestOrganization(t, app.DB)
-	adminRole := testutil.CreateAdminRole(t, app.DB, org1.ID)
-	user := testutil.CreateTestUser(t, app.DB, org1.ID, testutil.WithRoleID(&adminRole.ID))
-	contact := testutil.CreateTestContact(t, app.DB, org1.ID)
+		require.NoError(t, app.ScheduleMessage(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
 
-	// Create a user in a different org
-	otherOrgUser := testutil.CreateTestUser(t, app.DB, org2.ID)
+	t.Run("cannot schedule for another organization's contact", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		otherContact := testutil.CreateTestContact(t, app.DB, otherOrg.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"type":    "text",
+			"content": map[string]string{"body": "Hello"},
+			"send_at": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", otherContact.ID.String())
 
-	req := testutil.NewJSONRequest(t, map[string]interface{}{
-		"user_id": otherOrgUser.ID.String(),
+		require.NoError(t, app.ScheduleMessage(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
 	})
-	testutil.SetAuthContext(req, org1.ID, user.ID)
-	testutil.SetPathParam(req, "id", contact.ID.String())
-
-	err := app.AssignContact(req)
-	require.NoError(t, err)
-	// User from a different org should not be found
-	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 }
@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// Transcript export formats
+const (
+	transcriptFormatText = "text"
+	transcriptFormatPDF  = "pdf"
+)
+
+// transcriptTimeLayout is how message timestamps appear in a transcript
+const transcriptTimeLayout = "2006-01-02 15:04:05 UTC"
+
+// ExportConversation returns a transcript of every message exchanged with a
+// contact, oldest first, as plain text or PDF. Optional from/to dates
+// (YYYY-MM-DD, inclusive) limit the period covered.
+func (a *App) ExportConversation(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceContacts, models.ActionExport, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "You do not have permission to export conversations", nil, "")
+	}
+
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	format := string(r.RequestCtx.QueryArgs().Peek("format"))
+	if format == "" {
+		format = transcriptFormatText
+	}
+	if format != transcriptFormatText && format != transcriptFormatPDF {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid format. Use text or pdf", nil, "")
+	}
+
+	from, to, errMsg := parseTranscriptPeriod(r)
+	if errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+
+	var contact models.Contact
+	query := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID)
+	if err := query.First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	msgQuery := a.DB.Where("organization_id = ? AND contact_id = ?", orgID, contactID)
+	if !from.IsZero() {
+		msgQuery = msgQuery.Where("created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		msgQuery = msgQuery.Where("created_at <= ?", to)
+	}
+
+	var messages []models.Message
+	if err := msgQuery.Preload("SentByUser", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "full_name")
+	}).Order(messageOrderAsc).Find(&messages).Error; err != nil {
+		a.Log.Error("Failed to load messages for transcript", "error", err, "contact_id", contactID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to export conversation", nil, "")
+	}
+
+	lines := buildTranscript(&contact, messages, from, to, a.ShouldMaskPhoneNumbers(orgID), time.Now())
+
+	filename := fmt.Sprintf("conversation_%s_%s", contact.ID, time.Now().Format("20060102_150405"))
+	if format == transcriptFormatPDF {
+		r.RequestCtx.Response.Header.Set("Content-Type", "application/pdf")
+		r.RequestCtx.Response.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.pdf", filename))
+		r.RequestCtx.SetBody(renderTranscriptPDF(lines))
+		return nil
+	}
+
+	r.RequestCtx.Response.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	r.RequestCtx.Response.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.txt", filename))
+	r.RequestCtx.SetBodyString(strings.Join(lines, "\n") + "\n")
+	return nil
+}
+
+// parseTranscriptPeriod reads the optional "from" and "to" query parameters.
+// Either may be left out for an open-ended period; a zero time means no bound.
+func parseTranscriptPeriod(r *fastglue.Request) (from, to time.Time, errMsg string) {
+	fromStr := string(r.RequestCtx.QueryArgs().Peek("from"))
+	toStr := string(r.RequestCtx.QueryArgs().Peek("to"))
+
+	var err error
+	if fromStr != "" {
+		if from, err = time.Parse("2006-01-02", fromStr); err != nil {
+			return time.Time{}, time.Time{}, "Invalid from date format. Use YYYY-MM-DD"
+		}
+	}
+	if toStr != "" {
+		if to, err = time.Parse("2006-01-02", toStr); err != nil {
+			return time.Time{}, time.Time{}, "Invalid to date format. Use YYYY-MM-DD"
+		}
+		to = endOfDay(to)
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return time.Time{}, time.Time{}, "from date must be on or before to date"
+	}
+	return from, to, ""
+}
+
+// buildTranscript returns the lines of a conversation transcript: a short
+// header followed by one entry per message. Continuation lines of multi-line
+// messages are indented so each entry stays readable.
+func buildTranscript(contact *models.Contact, messages []models.Message, from, to time.Time, mask bool, now time.Time) []string {
+	phoneNumber := contact.PhoneNumber
+	contactName := contact.ProfileName
+	if mask {
+		phoneNumber = MaskPhoneNumber(phoneNumber)
+		contactName = MaskIfPhoneNumber(contactName)
+	}
+	if contactName == "" {
+		contactName = phoneNumber
+	}
+
+	period := "all messages"
+	switch {
+	case !from.IsZero() && !to.IsZero():
+		period = fmt.Sprintf("%s to %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	case !from.IsZero():
+		period = "from " + from.Format("2006-01-02")
+	case !to.IsZero():
+		period = "up to " + to.Format("2006-01-02")
+	}
+
+	lines := []string{
+		fmt.Sprintf("Conversation with %s (%s)", contactName, phoneNumber),
+		"Period: " + period,
+		"Exported: " + now.UTC().Format(transcriptTimeLayout),
+		fmt.Sprintf("Messages: %d", len(messages)),
+		"",
+	}
+
+	for i := range messages {
+		m := &messages[i]
+		direction, sender := "IN", contactName
+		if m.Direction == models.DirectionOutgoing {
+			direction, sender = "OUT", "Business"
+			if m.SentByUser != nil && m.SentByUser.FullName != "" {
+				sender = m.SentByUser.FullName
+			}
+		}
+
+		body := strings.Split(transcriptMessageText(m), "\n")
+		lines = append(lines, fmt.Sprintf("[%s] %s %s: %s",
+			m.CreatedAt.UTC().Format(transcriptTimeLayout), direction, sender, body[0]))
+		for _, line := range body[1:] {
+			lines = append(lines, "    "+line)
+		}
+	}
+	return lines
+}
+
+// transcriptMessageText is the text shown for a message in a transcript.
+// Non-text messages are labelled with their type, keeping any caption.
+func transcriptMessageText(m *models.Message) string {
+	content := strings.TrimRight(m.Content, "\n")
+	switch m.MessageType {
+	case models.MessageTypeText, "":
+		return content
+	case models.MessageTypeTemplate:
+		if content == "" {
+			return fmt.Sprintf("[template: %s]", m.TemplateName)
+		}
+		return content
+	}
+
+	label := "[" + string(m.MessageType)
+	if m.MediaFilename != "" {
+		label += ": " + m.MediaFilename
+	}
+	label += "]"
+	if content == "" {
+		return label
+	}
+	return label + " " + content
+}
+
+// PDF page layout for transcripts: A4 in points, 10pt Helvetica
+const (
+	pdfPageWidth    = 595
+	pdfPageHeight   = 842
+	pdfMargin       = 40
+	pdfFontSize     = 10
+	pdfLineHeight   = 12
+	pdfLineChars    = 95
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLineHeight
+)
+
+// renderTranscriptPDF lays the transcript lines out as a minimal PDF using
+// the standard Helvetica font. Long lines are wrapped. Characters outside
+// Latin-1 can't be shown by the standard fonts and are replaced with "?";
+// the text export keeps the full content.
+func renderTranscriptPDF(lines []string) []byte {
+	var wrapped []string
+	for _, line := range lines {
+		wrapped = append(wrapped, wrapTranscriptLine(line, pdfLineChars)...)
+	}
+
+	var pages [][]string
+	for len(wrapped) > pdfLinesPerPage {
+		pages = append(pages, wrapped[:pdfLinesPerPage])
+		wrapped = wrapped[pdfLinesPerPage:]
+	}
+	pages = append(pages, wrapped)
+
+	// Objects: 1 catalog, 2 page tree, 3 font, then a page and its content
+	// stream for each page
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	)
+	for i, page := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", pdfFontSize, pdfLineHeight, pdfMargin, pdfPageHeight-pdfMargin)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) '\n", pdfEscape(line))
+		}
+		content.WriteString("ET")
+
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// wrapTranscriptLine splits a line into pieces of at most width characters,
+// breaking at spaces where possible. Continuations are indented.
+func wrapTranscriptLine(line string, width int) []string {
+	var out []string
+	for utf8.RuneCountInString(line) > width {
+		runes := []rune(line)
+		cut := width
+		for i := width; i > width/2; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		out = append(out, string(runes[:cut]))
+		line = "    " + strings.TrimLeft(string(runes[cut:]), " ")
+	}
+	return append(out, line)
+}
+
+// pdfEscape encodes s as the contents of a PDF literal string in WinAnsi
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteByte(' ')
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			// Latin-1 matches WinAnsi here; octal escapes keep the output ASCII
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package handlers_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+func TestApp_ExportConversation(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	admin := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID), testutil.WithFullName("Jane Agent"))
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	day := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	addMessage := func(at time.Time, direction models.Direction, content string, sentBy *uuid.UUID) {
+		require.NoError(t, app.DB.Create(&models.Message{
+			BaseModel:       models.BaseModel{ID: uuid.New(), CreatedAt: at},
+			OrganizationID:  org.ID,
+			WhatsAppAccount: account.Name,
+			ContactID:       contact.ID,
+			Direction:       direction,
+			MessageType:     models.MessageTypeText,
+			Content:         content,
+			Status:          models.MessageStatusDelivered,
+			SentByUserID:    sentBy,
+		}).Error)
+	}
+	addMessage(day.AddDate(0, 0, -5), models.DirectionIncoming, "Too early", nil)
+	addMessage(day, models.DirectionIncoming, "Where is my order?", nil)
+	addMessage(day.Add(2*time.Minute), models.DirectionOutgoing, "On its way\nArrives tomorrow", &admin.ID)
+	addMessage(day.Add(3*time.Minute), models.DirectionOutgoing, "Thanks for waiting", nil)
+
+	exportReq := func(userID, contactID uuid.UUID, query map[string]string) *fastglue.Request {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, userID)
+		testutil.SetPathParam(req, "id", contactID.String())
+		for k, v := range query {
+			testutil.SetQueryParam(req, k, v)
+		}
+		return req
+	}
+
+	t.Run("text transcript within date range", func(t *testing.T) {
+		req := exportReq(admin.ID, contact.ID, map[string]string{"from": "2024-03-10", "to": "2024-03-10"})
+
+		require.NoError(t, app.ExportConversation(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		assert.Equal(t, "text/plain; charset=utf-8", string(req.RequestCtx.Response.Header.ContentType()))
+		assert.Contains(t, string(req.RequestCtx.Response.Header.Peek("Content-Disposition")), ".txt")
+
+		body := string(testutil.GetResponseBody(req))
+		assert.NotContains(t, body, "Too early")
+		assert.Contains(t, body, "Period: 2024-03-10 to 2024-03-10")
+		assert.Contains(t, body, "Messages: 3")
+
+		first := "[2024-03-10 09:00:00 UTC] IN " + contact.ProfileName + ": Where is my order?"
+		second := "[2024-03-10 09:02:00 UTC] OUT Jane Agent: On its way\n    Arrives tomorrow"
+		third := "[2024-03-10 09:03:00 UTC] OUT Business: Thanks for waiting"
+		assert.Contains(t, body, first)
+		assert.Contains(t, body, second)
+		assert.Contains(t, body, third)
+		assert.Less(t, strings.Index(body, first), strings.Index(body, second))
+		assert.Less(t, strings.Index(body, second), strings.Index(body, third))
+	})
+
+	t.Run("pdf transcript", func(t *testing.T) {
+		req := exportReq(admin.ID, contact.ID, map[string]string{"format": "pdf"})
+
+		require.NoError(t, app.ExportConversation(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		assert.Equal(t, "application/pdf", string(req.RequestCtx.Response.Header.ContentType()))
+
+		body := testutil.GetResponseBody(req)
+		assert.True(t, bytes.HasPrefix(body, []byte("%PDF-1.4")))
+		assert.True(t, bytes.HasSuffix(body, []byte("%%EOF\n")))
+		assert.Contains(t, string(body), "Thanks for waiting")
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		req := exportReq(admin.ID, contact.ID, map[string]string{"format": "docx"})
+		require.NoError(t, app.ExportConversation(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+		req = exportReq(admin.ID, contact.ID, map[string]string{"from": "2024-03-11", "to": "2024-03-10"})
+		require.NoError(t, app.ExportConversation(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("contact in another organization", func(t *testing.T) {
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		otherContact := testutil.CreateTestContact(t, app.DB, otherOrg.ID)
+
+		req := exportReq(admin.ID, otherContact.ID, nil)
+		require.NoError(t, app.ExportConversation(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		role := testutil.CreateTestRoleWithKeys(t, app.DB, org.ID, "transcript-reader", []string{"contacts:read"})
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&role.ID))
+
+		req := exportReq(user.ID, contact.ID, nil)
+		require.NoError(t, app.ExportConversation(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// flowAbandonedCondition matches flow sessions that ended without completing,
+// or are still active past the stale cutoff. Takes the active status and the cutoff.
+const flowAbandonedCondition = "NOT " + flowCompletedCondition + " AND (s.status <> ? OR s.last_activity_at <= ?)"
+
+// FlowAnalytics is the completion funnel of one flow
+type FlowAnalytics struct {
+	FlowID         string              `json:"flow_id"`
+	Name           string              `json:"name"`
+	Started        int64               `json:"started"`
+	Completed      int64               `json:"completed"`
+	Abandoned      int64               `json:"abandoned"`
+	InProgress     int64               `json:"in_progress"`
+	CompletionRate float64             `json:"completion_rate"` // Percent of started sessions
+	Steps          []FlowStepAnalytics `json:"steps"`
+}
+
+// FlowStepAnalytics reports how many sessions reached a step and how many
+// were abandoned while it was the last step sent
+type FlowStepAnalytics struct {
+	StepName    string  `json:"step_name"`
+	StepOrder   int     `json:"step_order"`
+	Reached     int64   `json:"reached"`
+	DropOffs    int64   `json:"drop_offs"`
+	DropOffRate float64 `json:"drop_off_rate"` // Percent of sessions that reached the step
+}
+
+// flowStepCountRow is a per-flow, per-step session count
+type flowStepCountRow struct {
+	FlowID   uuid.UUID
+	StepName string
+	Count    int64
+}
+
+// GetFlowAnalytics returns start, completion and abandon counts per flow,
+// with the number of sessions that reached and dropped off at each step, for
+// sessions started in the period. A session drops off at the last flow step
+// it was sent before it was abandoned.
+func (a *App) GetFlowAnalytics(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	if err := a.requirePermission(r, userID, models.ResourceAnalytics, models.ActionRead); err != nil {
+		return nil
+	}
+
+	var flowID *uuid.UUID
+	if raw := string(r.RequestCtx.QueryArgs().Peek("flow_id")); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid flow_id", nil, "")
+		}
+		flowID = &id
+	}
+
+	periodStart, periodEnd, errMsg := parseReportPeriod(r)
+	if errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if periodEnd.Before(periodStart) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "from must not be after to", nil, "")
+	}
+
+	query := a.DB.Where("organization_id = ?", orgID).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step_order ASC")
+		}).
+		Order("name ASC")
+	if flowID != nil {
+		query = query.Where("id = ?", *flowID)
+	}
+	var flows []models.ChatbotFlow
+	if err := query.Find(&flows).Error; err != nil {
+		a.Log.Error("Failed to load flows for analytics", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load flow analytics", nil, "")
+	}
+	if flowID != nil && len(flows) == 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Flow not found", nil, "")
+	}
+
+	now := time.Now()
+	counts, err := a.flowSessionCounts(orgID, periodStart, periodEnd, now, flowID)
+	if err != nil {
+		a.Log.Error("Failed to aggregate flow sessions", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load flow analytics", nil, "")
+	}
+	countsByFlow := make(map[string]flowReportRow, len(counts))
+	for _, row := range counts {
+		countsByFlow[row.ID] = row
+	}
+
+	reached, dropOffs, err := a.flowStepCounts(orgID, periodStart, periodEnd, now, flowID)
+	if err != nil {
+		a.Log.Error("Failed to aggregate flow step sessions", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load flow analytics", nil, "")
+	}
+
+	result := make([]FlowAnalytics, len(flows))
+	for i, flow := range flows {
+		row := countsByFlow[flow.ID.String()]
+		result[i] = FlowAnalytics{
+			FlowID:         flow.ID.String(),
+			Name:           flow.Name,
+			Started:        row.Started,
+			Completed:      row.Completed,
+			Abandoned:      row.Abandoned,
+			InProgress:     row.Started - row.Completed - row.Abandoned,
+			CompletionRate: percentOf(row.Completed, row.Started),
+			Steps:          make([]FlowStepAnalytics, len(flow.Steps)),
+		}
+		for j, step := range flow.Steps {
+			key := flowStepKey(flow.ID, step.StepName)
+			result[i].Steps[j] = FlowStepAnalytics{
+				StepName:    step.StepName,
+				StepOrder:   step.StepOrder,
+				Reached:     reached[key],
+				DropOffs:    dropOffs[key],
+				DropOffRate: percentOf(dropOffs[key], reached[key]),
+			}
+		}
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"flows": result,
+		"from":  periodStart.Format(time.RFC3339),
+		"to":    periodEnd.Format(time.RFC3339),
+	})
+}
+
+// flowStaleBefore returns the cutoff before which flow sessions still active
+// count as abandoned, based on the organization's session timeout
+func (a *App) flowStaleBefore(orgID uuid.UUID, now time.Time) time.Time {
+	timeoutMins := 30
+	var settings models.ChatbotSettings
+	if err := a.DB.Where("organization_id = ? AND whats_app_account = ?", orgID, "").First(&settings).Error; err == nil && settings.SessionTimeoutMins > 0 {
+		timeoutMins = settings.SessionTimeoutMins
+	}
+	return now.Add(-time.Duration(timeoutMins) * time.Minute)
+}
+
+// flowSessionCounts counts started, completed and abandoned sessions of each
+// of the organization's flows among sessions created in the period. Pass
+// flowID to limit the counts to one flow.
+func (a *App) flowSessionCounts(orgID uuid.UUID, periodStart, periodEnd, now time.Time, flowID *uuid.UUID) ([]flowReportRow, error) {
+	query := a.DB.Model(&models.ChatbotFlow{}).
+		Select(fmt.Sprintf("chatbot_flows.id, chatbot_flows.name, COUNT(s.id) AS started, "+
+			"COUNT(s.id) FILTER (WHERE %s) AS completed, "+
+			"COUNT(s.id) FILTER (WHERE %s) AS abandoned", flowCompletedCondition, flowAbandonedCondition),
+			models.SessionStatusActive, a.flowStaleBefore(orgID, now)).
+		Joins("LEFT JOIN chatbot_sessions s ON s.current_flow_id = chatbot_flows.id AND s.organization_id = chatbot_flows.organization_id AND s.deleted_at IS NULL AND s.created_at >= ? AND s.created_at <= ?", periodStart, periodEnd).
+		Where("chatbot_flows.organization_id = ?", orgID)
+	if flowID != nil {
+		query = query.Where("chatbot_flows.id = ?", *flowID)
+	}
+
+	var rows []flowReportRow
+	err := query.Group("chatbot_flows.id, chatbot_flows.name").
+		Order("chatbot_flows.name ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// flowStepCounts returns, keyed by flowStepKey, how many sessions created in
+// the period were sent each flow step and how many were abandoned with it as
+// the last step sent
+func (a *App) flowStepCounts(orgID uuid.UUID, periodStart, periodEnd, now time.Time, flowID *uuid.UUID) (reached, dropOffs map[string]int64, err error) {
+	// Session messages logged for a step carry the step's name
+	sessionSteps := func() *gorm.DB {
+		query := a.DB.Table("chatbot_sessions s").
+			Joins("JOIN chatbot_flow_steps fs ON fs.flow_id = s.current_flow_id AND fs.deleted_at IS NULL").
+			Joins("JOIN chatbot_session_messages m ON m.session_id = s.id AND m.step_name = fs.step_name AND m.direction = ? AND m.deleted_at IS NULL", models.DirectionOutgoing).
+			Where("s.organization_id = ? AND s.deleted_at IS NULL AND s.created_at >= ? AND s.created_at <= ?", orgID, periodStart, periodEnd)
+		if flowID != nil {
+			query = query.Where("s.current_flow_id = ?", *flowID)
+		}
+		return query
+	}
+
+	var reachedRows []flowStepCountRow
+	if err := sessionSteps().
+		Select("s.current_flow_id AS flow_id, m.step_name, COUNT(DISTINCT s.id) AS count").
+		Group("s.current_flow_id, m.step_name").
+		Scan(&reachedRows).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var dropOffRows []flowStepCountRow
+	lastSteps := sessionSteps().
+		Select("DISTINCT ON (s.id) s.current_flow_id AS flow_id, m.step_name").
+		Where(flowAbandonedCondition, models.SessionStatusActive, a.flowStaleBefore(orgID, now)).
+		Order("s.id, m.created_at DESC")
+	if err := a.DB.Table("(?) AS last_steps", lastSteps).
+		Select("flow_id, step_name, COUNT(*) AS count").
+		Group("flow_id, step_name").
+		Scan(&dropOffRows).Error; err != nil {
+		return nil, nil, err
+	}
+
+	reached = make(map[string]int64, len(reachedRows))
+	for _, row := range reachedRows {
+		reached[flowStepKey(row.FlowID, row.StepName)] = row.Count
+	}
+	dropOffs = make(map[string]int64, len(dropOffRows))
+	for _, row := range dropOffRows {
+		dropOffs[flowStepKey(row.FlowID, row.StepName)] = row.Count
+	}
+	return reached, dropOffs, nil
+}
+
+// flowStepKey identifies a step within a flow in count maps
+func flowStepKey(flowID uuid.UUID, stepName string) string {
+	return flowID.String() + ":" + stepName
+}
+
+// percentOf returns part as a percentage of whole, rounded to one decimal
+func percentOf(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(whole)) / 10
+}
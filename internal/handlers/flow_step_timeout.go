@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+	"gorm.io/gorm"
+)
+
+// stepTimeoutKey is the SessionData key recording the last step timeout
+// handled for a session. A record older than the session's last activity
+// belongs to an earlier wait.
+const stepTimeoutKey = "_step_timeout"
+
+// stepTimeoutRecord is stored under stepTimeoutKey
+type stepTimeoutRecord struct {
+	Step   string                   `json:"step"` // Step that timed out
+	At     time.Time                `json:"at"`
+	Action models.StepTimeoutAction `json:"action"`
+}
+
+// processFlowStepTimeouts handles flow steps left unanswered past their step
+// timeout and returns how many were handled. Sessions are claimed with a
+// conditional update, so concurrent runs handle each timeout only once.
+func (a *App) processFlowStepTimeouts(now time.Time) int {
+	var flows []models.ChatbotFlow
+	if err := a.DB.Where("is_enabled = ? AND (step_timeout_minutes > 0 OR id IN (?))", true,
+		a.DB.Model(&models.ChatbotFlowStep{}).Select("flow_id").Where("step_timeout_minutes > 0")).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step_order ASC")
+		}).
+		Find(&flows).Error; err != nil {
+		a.Log.Error("Failed to load flows with step timeouts", "error", err)
+		return 0
+	}
+
+	handled := 0
+	for i := range flows {
+		handled += a.processFlowStepTimeoutsFor(&flows[i], now)
+	}
+	return handled
+}
+
+// processFlowStepTimeoutsFor handles the timed-out steps of one flow's active sessions
+func (a *App) processFlowStepTimeoutsFor(flow *models.ChatbotFlow, now time.Time) int {
+	shortest := 0
+	for i := range flow.Steps {
+		if mins := stepTimeoutMins(flow, &flow.Steps[i]); mins > 0 && (shortest == 0 || mins < shortest) {
+			shortest = mins
+		}
+	}
+	if shortest == 0 {
+		return 0
+	}
+
+	var sessions []models.ChatbotSession
+	if err := a.DB.Where("organization_id = ? AND current_flow_id = ? AND status = ? AND current_step <> '' AND last_activity_at <= ?",
+		flow.OrganizationID, flow.ID, models.SessionStatusActive, now.Add(-time.Duration(shortest)*time.Minute)).
+		Find(&sessions).Error; err != nil {
+		a.Log.Error("Failed to find sessions for step timeout", "error", err, "flow_id", flow.ID)
+		return 0
+	}
+
+	handled := 0
+	for _, session := range sessions {
+		step := findFlowStep(flow, session.CurrentStep)
+		if step == nil {
+			continue
+		}
+		mins := stepTimeoutMins(flow, step)
+		if mins == 0 {
+			continue
+		}
+		since, done := stepWaitingSince(session)
+		if done || since.After(now.Add(-time.Duration(mins)*time.Minute)) {
+			continue
+		}
+		if a.handleStepTimeout(flow, step, session, now) {
+			handled++
+		}
+	}
+	return handled
+}
+
+// stepTimeoutMins returns the step's own timeout, falling back to the flow's
+func stepTimeoutMins(flow *models.ChatbotFlow, step *models.ChatbotFlowStep) int {
+	if step.StepTimeoutMins > 0 {
+		return step.StepTimeoutMins
+	}
+	return flow.StepTimeoutMins
+}
+
+// findFlowStep returns the flow's step with the given name, or nil
+func findFlowStep(flow *models.ChatbotFlow, name string) *models.ChatbotFlowStep {
+	for i := range flow.Steps {
+		if flow.Steps[i].StepName == name {
+			return &flow.Steps[i]
+		}
+	}
+	return nil
+}
+
+// stepWaitingSince returns when the session's current step started waiting
+// for an answer, and whether its timeout has already been handled. A step
+// reached by advancing past a timed-out step waits from that timeout.
+func stepWaitingSince(session models.ChatbotSession) (time.Time, bool) {
+	since := session.LastActivityAt
+	raw, ok := session.SessionData[stepTimeoutKey].(map[string]any)
+	if !ok {
+		return since, false
+	}
+	at, err := time.Parse(time.RFC3339Nano, getStringFromMap(raw, "at"))
+	if err != nil || at.Before(session.LastActivityAt) {
+		return since, false
+	}
+	if getStringFromMap(raw, "step") == session.CurrentStep {
+		return since, true
+	}
+	return at, false
+}
+
+// handleStepTimeout sends the flow's timeout message for a session stuck on
+// step and applies the flow's timeout action, reporting whether it did so
+func (a *App) handleStepTimeout(flow *models.ChatbotFlow, step *models.ChatbotFlowStep, session models.ChatbotSession, now time.Time) bool {
+	// Skip if an agent has taken over the conversation
+	if a.hasActiveAgentTransfer(session.OrganizationID, session.ContactID) {
+		return false
+	}
+
+	var contact models.Contact
+	if err := a.DB.Where("id = ? AND organization_id = ?", session.ContactID, session.OrganizationID).First(&contact).Error; err != nil {
+		a.Log.Error("Failed to load contact for step timeout", "error", err, "session_id", session.ID)
+		return false
+	}
+	if isBotPaused(&contact, now) {
+		return false
+	}
+
+	// Nudges wait out quiet hours like other unprompted messages
+	if settings, err := a.getChatbotSettingsCached(session.OrganizationID, session.WhatsAppAccount); err == nil {
+		if _, quiet := settings.QuietHours.EndsAt(now); quiet {
+			return false
+		}
+	}
+
+	account, err := a.resolveWhatsAppAccount(session.OrganizationID, session.WhatsAppAccount)
+	if err != nil {
+		a.Log.Error("Failed to load WhatsApp account for step timeout", "error", err)
+		return false
+	}
+
+	action := flow.StepTimeoutAction
+	if action == "" {
+		action = models.StepTimeoutNudge
+	}
+	record := stepTimeoutRecord{Step: step.StepName, At: now.UTC(), Action: action}
+	recordJSON, _ := json.Marshal(record)
+
+	var previous any
+	if prev, ok := session.SessionData[stepTimeoutKey]; ok {
+		prevJSON, _ := json.Marshal(prev)
+		previous = string(prevJSON)
+	}
+
+	// Record the timeout first so a concurrent run or a slow send doesn't
+	// handle it twice. A reply moves last_activity_at, which re-arms it.
+	claim := a.DB.Model(&models.ChatbotSession{}).
+		Where("id = ? AND status = ? AND current_step = ? AND last_activity_at = ?",
+			session.ID, models.SessionStatusActive, session.CurrentStep, session.LastActivityAt).
+		Where("session_data->'"+stepTimeoutKey+"' IS NOT DISTINCT FROM ?::jsonb", previous).
+		Update("session_data", gorm.Expr("jsonb_set(COALESCE(session_data, '{}'::jsonb), '{"+stepTimeoutKey+"}', ?::jsonb)", string(recordJSON)))
+	if claim.Error != nil {
+		a.Log.Error("Failed to record step timeout", "error", claim.Error, "session_id", session.ID)
+		return false
+	}
+	if claim.RowsAffected == 0 {
+		return false
+	}
+	if session.SessionData == nil {
+		session.SessionData = models.JSONB{}
+	}
+	var stored map[string]any
+	_ = json.Unmarshal(recordJSON, &stored)
+	session.SessionData[stepTimeoutKey] = stored
+
+	if flow.TimeoutMessage != "" {
+		message := a.replaceVariables(flow.TimeoutMessage, session.SessionData)
+		if err := a.sendAndSaveTextMessage(account, &contact, message); err != nil {
+			a.Log.Error("Failed to send step timeout message", "error", err, "contact", contact.PhoneNumber)
+		}
+		a.logSessionMessage(session.ID, models.DirectionOutgoing, message, step.StepName+"_timeout")
+	}
+
+	a.Log.Info("Flow step timed out",
+		"session_id", session.ID,
+		"flow_id", flow.ID,
+		"step", step.StepName,
+		"action", action,
+	)
+
+	switch action {
+	case models.StepTimeoutAdvance:
+		a.advancePastStep(account, &session, &contact, flow, step)
+	case models.StepTimeoutAbandon:
+		a.exitFlow(&session)
+	}
+	return true
+}
+
+// advancePastStep moves a session on from a step that was never answered,
+// following the step's default route
+func (a *App) advancePastStep(account *models.WhatsAppAccount, session *models.ChatbotSession, contact *models.Contact, flow *models.ChatbotFlow, step *models.ChatbotFlowStep) {
+	nextStepName := step.NextStep
+	if nextStepName == "" {
+		for i := range flow.Steps {
+			if flow.Steps[i].StepName == step.StepName && i+1 < len(flow.Steps) {
+				nextStepName = flow.Steps[i+1].StepName
+				break
+			}
+		}
+	}
+	if defaultNext, ok := step.ConditionalNext["default"].(string); ok {
+		nextStepName = defaultNext
+	}
+
+	nextStep := findFlowStep(flow, nextStepName)
+	if nextStep == nil {
+		a.completeFlow(account, session, contact, flow)
+		return
+	}
+
+	session.CurrentStep = nextStep.StepName
+	session.StepRetries = 0
+	a.DB.Model(session).Updates(map[string]interface{}{
+		"current_step": nextStep.StepName,
+		"step_retries": 0,
+	})
+
+	a.sendStepWithSkipCheck(account, session, contact, nextStep, flow, nil)
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createStepTimeoutFlow creates an enabled two-step flow with a 15 minute step timeout
+func createStepTimeoutFlow(t *testing.T, app *App, orgID uuid.UUID, accountName string, action models.StepTimeoutAction) *models.ChatbotFlow {
+	t.Helper()
+	flow := &models.ChatbotFlow{
+		BaseModel:         models.BaseModel{ID: uuid.New()},
+		OrganizationID:    orgID,
+		WhatsAppAccount:   accountName,
+		Name:              "Signup " + uuid.NewString()[:8],
+		IsEnabled:         true,
+		TimeoutMessage:    "Still there?",
+		StepTimeoutMins:   15,
+		StepTimeoutAction: action,
+	}
+	require.NoError(t, app.DB.Create(flow).Error)
+
+	for i, name := range []string{"ask_name", "ask_email"} {
+		step := models.ChatbotFlowStep{
+			BaseModel:   models.BaseModel{ID: uuid.New()},
+			FlowID:      flow.ID,
+			StepName:    name,
+			StepOrder:   i + 1,
+			Message:     "Please send your " + name[4:],
+			MessageType: models.FlowStepTypeText,
+			InputType:   models.InputTypeText,
+			StoreAs:     name[4:],
+		}
+		require.NoError(t, app.DB.Create(&step).Error)
+	}
+	return flow
+}
+
+func createStepTimeoutSession(t *testing.T, app *App, flow *models.ChatbotFlow, contact *models.Contact, step string, lastActivity time.Time) *models.ChatbotSession {
+	t.Helper()
+	session := &models.ChatbotSession{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  flow.OrganizationID,
+		ContactID:       contact.ID,
+		WhatsAppAccount: flow.WhatsAppAccount,
+		PhoneNumber:     contact.PhoneNumber,
+		Status:          models.SessionStatusActive,
+		CurrentFlowID:   &flow.ID,
+		CurrentStep:     step,
+		SessionData:     models.JSONB{},
+		LastActivityAt:  lastActivity,
+	}
+	require.NoError(t, app.DB.Create(session).Error)
+	return session
+}
+
+func countOutgoingMessages(t *testing.T, app *App, contactID uuid.UUID, content string) int64 {
+	t.Helper()
+	var count int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("contact_id = ? AND direction = ? AND content = ?", contactID, models.DirectionOutgoing, content).
+		Count(&count).Error)
+	return count
+}
+
+func TestFlowStepTimeout_NudgeOnce(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	flow := createStepTimeoutFlow(t, app, org.ID, account.Name, models.StepTimeoutNudge)
+	session := createStepTimeoutSession(t, app, flow, contact, "ask_email", time.Now().Add(-20*time.Minute))
+
+	assert.Equal(t, 1, app.processFlowStepTimeouts(time.Now()))
+	assert.Equal(t, int64(1), countOutgoingMessages(t, app, contact.ID, "Still there?"))
+
+	var updated models.ChatbotSession
+	require.NoError(t, app.DB.First(&updated, session.ID).Error)
+	assert.Equal(t, models.SessionStatusActive, updated.Status)
+	assert.Equal(t, "ask_email", updated.CurrentStep)
+	record, ok := updated.SessionData[stepTimeoutKey].(map[string]any)
+	require.True(t, ok, "timeout should be recorded in session data")
+	assert.Equal(t, "ask_email", record["step"])
+	assert.Equal(t, "nudge", record["action"])
+
+	// The same wait is only nudged once
+	assert.Equal(t, 0, app.processFlowStepTimeouts(time.Now()))
+	assert.Equal(t, int64(1), countOutgoingMessages(t, app, contact.ID, "Still there?"))
+}
+
+func TestFlowStepTimeout_NotYetDue(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	flow := createStepTimeoutFlow(t, app, org.ID, account.Name, models.StepTimeoutNudge)
+	createStepTimeoutSession(t, app, flow, contact, "ask_email", time.Now().Add(-10*time.Minute))
+
+	// A longer step timeout overrides the flow's
+	other := createStepTimeoutSession(t, app, flow, testutil.CreateTestContact(t, app.DB, org.ID), "ask_name", time.Now().Add(-20*time.Minute))
+	require.NoError(t, app.DB.Model(&models.ChatbotFlowStep{}).
+		Where("flow_id = ? AND step_name = ?", flow.ID, "ask_name").
+		Update("step_timeout_minutes", 60).Error)
+
+	assert.Equal(t, 0, app.processFlowStepTimeouts(time.Now()))
+	assert.Equal(t, int64(0), countOutgoingMessages(t, app, contact.ID, "Still there?"))
+	assert.Equal(t, int64(0), countOutgoingMessages(t, app, other.ContactID, "Still there?"))
+}
+
+func TestFlowStepTimeout_Advance(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	flow := createStepTimeoutFlow(t, app, org.ID, account.Name, models.StepTimeoutAdvance)
+	session := createStepTimeoutSession(t, app, flow, contact, "ask_name", time.Now().Add(-20*time.Minute))
+
+	assert.Equal(t, 1, app.processFlowStepTimeouts(time.Now()))
+
+	var updated models.ChatbotSession
+	require.NoError(t, app.DB.First(&updated, session.ID).Error)
+	assert.Equal(t, models.SessionStatusActive, updated.Status)
+	assert.Equal(t, "ask_email", updated.CurrentStep)
+	assert.Equal(t, int64(1), countOutgoingMessages(t, app, contact.ID, "Please send your email"))
+
+	// The next step waits its own full timeout
+	assert.Equal(t, 0, app.processFlowStepTimeouts(time.Now()))
+
+	// Once that runs out too, the flow completes
+	assert.Equal(t, 1, app.processFlowStepTimeouts(time.Now().Add(16*time.Minute)))
+	require.NoError(t, app.DB.First(&updated, session.ID).Error)
+	assert.Equal(t, models.SessionStatusCompleted, updated.Status)
+}
+
+func TestFlowStepTimeout_Abandon(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	flow := createStepTimeoutFlow(t, app, org.ID, account.Name, models.StepTimeoutAbandon)
+	session := createStepTimeoutSession(t, app, flow, contact, "ask_name", time.Now().Add(-20*time.Minute))
+
+	assert.Equal(t, 1, app.processFlowStepTimeouts(time.Now()))
+	assert.Equal(t, int64(1), countOutgoingMessages(t, app, contact.ID, "Still there?"))
+
+	var updated models.ChatbotSession
+	require.NoError(t, app.DB.First(&updated, session.ID).Error)
+	assert.Equal(t, models.SessionStatusCompleted, updated.Status)
+	assert.Empty(t, updated.CurrentStep)
+}
@@ -84,6 +84,18 @@ func endOfDay(t time.Time) time.Time {
 	return t.Add(24*time.Hour - time.Nanosecond)
 }
 
+// parseReportPeriod reads the "from" and "to" query parameters as a date range,
+// defaulting to the current month when either is missing.
+func parseReportPeriod(r *fastglue.Request) (start, end time.Time, errMsg string) {
+	fromStr := string(r.RequestCtx.QueryArgs().Peek("from"))
+	toStr := string(r.RequestCtx.QueryArgs().Peek("to"))
+	if fromStr != "" && toStr != "" {
+		return parseDateRange(fromStr, toStr)
+	}
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), now, ""
+}
+
 // findByIDAndOrg fetches a single record scoped by ID and organization.
 // Sends a 404 error envelope on failure and returns the error.
 func findByIDAndOrg[T any](db *gorm.DB, r *fastglue.Request, id, orgID uuid.UUID, label string) (*T, error) {
@@ -171,6 +171,16 @@ type ExportRequest struct {
 	Format  string            `json:"format"` // csv (default), json
 }
 
+// escapeCSVCell guards against CSV injection by prefixing formula triggers with a single quote.
+// Only '=' and '@' are escaped. '+' and '-' are skipped because they appear
+// in legitimate data (phone numbers, negative values).
+func escapeCSVCell(cell string) string {
+	if len(cell) > 0 && (cell[0] == '=' || cell[0] == '@') {
+		return "'" + cell
+	}
+	return cell
+}
+
 // ExportData handles generic data export
 func (a *App) ExportData(r *fastglue.Request) error {
 	orgID, userID, err := a.getOrgAndUserID(r)
@@ -321,13 +331,8 @@ func (a *App) ExportData(r *fastglue.Request) error {
 			}
 		}
 
-		// Escape CSV injection: prefix dangerous first chars with a single quote
-		// Only escape '=' and '@' which trigger formulas. '+' and '-' are skipped
-		// because they appear in legitimate data (phone numbers, negative values).
 		for j, cell := range csvRow {
-			if len(cell) > 0 && (cell[0] == '=' || cell[0] == '@') {
-				csvRow[j] = "'" + cell
-			}
+			csvRow[j] = escapeCSVCell(cell)
 		}
 		_ = writer.Write(csvRow)
 	}
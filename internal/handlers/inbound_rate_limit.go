@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// maxInboundRateLimit caps the configurable messages per minute per contact
+const maxInboundRateLimit = 1000
+
+// inboundRateKey returns the Redis key counting a contact's incoming messages
+// in the minute starting at window.
+func inboundRateKey(contactID uuid.UUID, window time.Time) string {
+	return fmt.Sprintf("inbound_rate:%s:%d", contactID, window.Unix())
+}
+
+// inboundRateExceeded counts an incoming message from the contact and reports
+// whether the contact has sent more than maxPerMinute in the current minute.
+// It fails open on Redis errors.
+func (a *App) inboundRateExceeded(contactID uuid.UUID, maxPerMinute int, now time.Time) bool {
+	if maxPerMinute <= 0 || a.Redis == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	key := inboundRateKey(contactID, now.Truncate(time.Minute))
+	count, err := a.Redis.Incr(ctx, key).Result()
+	if err != nil {
+		a.Log.Error("Failed to count inbound message", "error", err, "contact_id", contactID)
+		return false
+	}
+	// Set expiry on first increment (new window)
+	if count == 1 {
+		if err := a.Redis.Expire(ctx, key, 2*time.Minute).Err(); err != nil {
+			a.Log.Error("Failed to expire inbound rate counter", "error", err, "key", key)
+		}
+	}
+	return count > int64(maxPerMinute)
+}
+
+// applyInboundRateLimit reports whether the contact is over the inbound rate
+// limit, in which case no automated response should be sent. Contacts going
+// over are flagged for review when the settings ask for it.
+func (a *App) applyInboundRateLimit(settings *models.ChatbotSettings, contact *models.Contact, now time.Time) bool {
+	if !a.inboundRateExceeded(contact.ID, settings.FloodControl.MaxPerMinute, now) {
+		return false
+	}
+
+	if settings.FloodControl.FlagContact && contact.FloodFlaggedAt == nil {
+		// Only the first message over the limit sets the flag
+		result := a.DB.Model(&models.Contact{}).
+			Where("id = ? AND flood_flagged_at IS NULL", contact.ID).
+			Update("flood_flagged_at", now)
+		if result.Error != nil {
+			a.Log.Error("Failed to flag contact for review", "error", result.Error, "contact_id", contact.ID)
+		} else if result.RowsAffected > 0 {
+			contact.FloodFlaggedAt = &now
+			a.Log.Warn("Contact flagged for review after exceeding inbound rate limit",
+				"contact_id", contact.ID,
+				"org_id", contact.OrganizationID,
+				"limit_per_minute", settings.FloodControl.MaxPerMinute)
+		}
+	}
+	return true
+}
+
+// ClearContactFloodFlag marks a contact flagged by the inbound rate limit as reviewed
+func (a *App) ClearContactFloodFlag(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceContacts, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	var contact models.Contact
+	if err := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID).
+		First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	if err := a.DB.Model(&contact).Update("flood_flagged_at", nil).Error; err != nil {
+		a.Log.Error("Failed to clear contact flood flag", "error", err, "contact_id", contact.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update contact", nil, "")
+	}
+
+	a.Log.Info("Contact flood flag cleared", "contact_id", contact.ID, "user_id", userID)
+	return r.SendEnvelope(map[string]any{
+		"contact_id":       contact.ID,
+		"flood_flagged_at": nil,
+	})
+}
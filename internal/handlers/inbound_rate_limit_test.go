@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboundRateExceeded_FixedWindow(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	contactID := uuid.New()
+	now := time.Now().Truncate(time.Minute)
+
+	assert.False(t, app.inboundRateExceeded(contactID, 2, now))
+	assert.False(t, app.inboundRateExceeded(contactID, 2, now.Add(10*time.Second)))
+	assert.True(t, app.inboundRateExceeded(contactID, 2, now.Add(20*time.Second)))
+
+	// The next minute starts a fresh count
+	assert.False(t, app.inboundRateExceeded(contactID, 2, now.Add(time.Minute)))
+
+	// A limit of 0 disables the check
+	assert.False(t, app.inboundRateExceeded(uuid.New(), 0, now))
+}
+
+func TestProcessIncomingMessage_InboundRateLimit(t *testing.T) {
+	app := newProcessorTestApp(t)
+	if app.Redis == nil {
+		t.Skip("TEST_REDIS_URL not set")
+	}
+	org, account := createProcessorTestOrg(t, app)
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		IsEnabled:      true,
+		FloodControl:   models.FloodControlConfig{MaxPerMinute: 2, FlagContact: true},
+	}).Error)
+	require.NoError(t, app.DB.Create(&models.KeywordRule{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		Name:            "hello",
+		Keywords:        models.StringArray{"hello"},
+		MatchType:       models.MatchTypeExact,
+		ResponseType:    models.ResponseTypeText,
+		ResponseContent: models.JSONB{"body": "Hi there"},
+		IsEnabled:       true,
+	}).Error)
+
+	// Keep the burst within one rate limit window
+	if sec := time.Now().Second(); sec >= 55 {
+		time.Sleep(time.Duration(61-sec) * time.Second)
+	}
+
+	phone := uniqueTestPhone()
+	for i := 0; i < 4; i++ {
+		app.processIncomingMessageFull(account.PhoneID, incomingText(phone, "hello"), "Customer")
+	}
+
+	var contact models.Contact
+	require.NoError(t, app.DB.Where("organization_id = ? AND phone_number = ?", org.ID, phone).First(&contact).Error)
+	require.NotNil(t, contact.FloodFlaggedAt, "contact should be flagged for review")
+
+	var incoming, replies int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("contact_id = ? AND direction = ?", contact.ID, models.DirectionIncoming).
+		Count(&incoming).Error)
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("contact_id = ? AND direction = ? AND content = ?", contact.ID, models.DirectionOutgoing, "Hi there").
+		Count(&replies).Error)
+	assert.Equal(t, int64(4), incoming, "messages over the limit are still stored")
+	assert.Equal(t, int64(2), replies, "only messages within the limit get a response")
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/pkg/whatsapp"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// WhatsApp limits for interactive messages
+const (
+	interactiveMaxBodyLength        = 1024
+	interactiveMaxButtons           = 3
+	interactiveMaxButtonTitleLength = 20
+	interactiveMaxListRows          = 10
+	interactiveMaxListSections      = 10
+	interactiveMaxRowTitleLength    = 24
+	interactiveMaxRowDescLength     = 72
+	interactiveMaxIDLength          = 200
+)
+
+// SendInteractiveMessageRequest is a button or list message to send to a contact
+type SendInteractiveMessageRequest struct {
+	Type            string               `json:"type"` // "button" or "list"
+	Body            string               `json:"body"`
+	Buttons         []ButtonContent      `json:"buttons,omitempty"`     // For button type, up to 3
+	ButtonText      string               `json:"button_text,omitempty"` // For list type, the button that opens the list
+	Sections        []ListSectionContent `json:"sections,omitempty"`    // For list type, up to 10 rows in total
+	WhatsAppAccount string               `json:"whatsapp_account,omitempty"`
+}
+
+// ListSectionContent is a titled group of list rows
+type ListSectionContent struct {
+	Title string           `json:"title"`
+	Rows  []ListRowContent `json:"rows"`
+}
+
+// ListRowContent is a row the contact can pick from a list
+type ListRowContent struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// SendInteractiveMessage sends a reply button or list message to a contact.
+// The contact's choice comes back as a button_reply message linked to it.
+func (a *App) SendInteractiveMessage(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	var req SendInteractiveMessageRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	if err := validateInteractiveMessage(&req); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	// Users without full read permission can only message their assigned contacts
+	var contact models.Contact
+	query := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID)
+	if err := query.First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	if a.isPhoneBlacklisted(orgID, contact.PhoneNumber) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Phone number is blacklisted", nil, "")
+	}
+	if contact.Blocked {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Contact is blocked", nil, "")
+	}
+
+	account, err := a.resolveSendingWhatsAppAccount(orgID, contactAccountName(&contact, req.WhatsAppAccount))
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	msgReq := OutgoingMessageRequest{
+		Account:         account,
+		Contact:         &contact,
+		Type:            models.MessageTypeInteractive,
+		InteractiveType: req.Type,
+		BodyText:        req.Body,
+		ButtonText:      req.ButtonText,
+	}
+	if req.Type == "button" {
+		msgReq.Buttons = make([]whatsapp.Button, len(req.Buttons))
+		for i, btn := range req.Buttons {
+			msgReq.Buttons[i] = whatsapp.Button{ID: btn.ID, Title: btn.Title}
+		}
+	} else {
+		msgReq.Sections = make([]whatsapp.ListSection, len(req.Sections))
+		for i, section := range req.Sections {
+			rows := make([]whatsapp.ListRow, len(section.Rows))
+			for j, row := range section.Rows {
+				rows[j] = whatsapp.ListRow{ID: row.ID, Title: row.Title, Description: row.Description}
+			}
+			msgReq.Sections[i] = whatsapp.ListSection{Title: section.Title, Rows: rows}
+		}
+	}
+
+	opts := DefaultSendOptions()
+	opts.SentByUserID = &userID
+
+	message, err := a.SendOutgoingMessage(context.Background(), msgReq, opts)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to send message", nil, "")
+	}
+
+	return r.SendEnvelope(MessageResponse{
+		ID:              message.ID,
+		ContactID:       message.ContactID,
+		Direction:       message.Direction,
+		MessageType:     message.MessageType,
+		Content:         map[string]string{"body": message.Content},
+		InteractiveData: message.InteractiveData,
+		Status:          message.Status,
+		WhatsAppAccount: message.WhatsAppAccount,
+		CreatedAt:       message.CreatedAt,
+		UpdatedAt:       message.UpdatedAt,
+	})
+}
+
+// validateInteractiveMessage checks a button or list message against
+// WhatsApp's limits so it isn't rejected after being stored
+func validateInteractiveMessage(req *SendInteractiveMessageRequest) error {
+	if req.Body == "" {
+		return fmt.Errorf("body is required")
+	}
+	if utf8.RuneCountInString(req.Body) > interactiveMaxBodyLength {
+		return fmt.Errorf("body must be at most %d characters", interactiveMaxBodyLength)
+	}
+
+	ids := make(map[string]bool)
+	checkID := func(id string) error {
+		if id == "" {
+			return fmt.Errorf("every option needs an id")
+		}
+		if utf8.RuneCountInString(id) > interactiveMaxIDLength {
+			return fmt.Errorf("option id %q must be at most %d characters", id, interactiveMaxIDLength)
+		}
+		if ids[id] {
+			return fmt.Errorf("option id %q is used more than once", id)
+		}
+		ids[id] = true
+		return nil
+	}
+
+	switch req.Type {
+	case "button":
+		if len(req.Buttons) == 0 || len(req.Buttons) > interactiveMaxButtons {
+			return fmt.Errorf("button messages need 1 to %d buttons", interactiveMaxButtons)
+		}
+		for _, btn := range req.Buttons {
+			if err := checkID(btn.ID); err != nil {
+				return err
+			}
+			if btn.Title == "" || utf8.RuneCountInString(btn.Title) > interactiveMaxButtonTitleLength {
+				return fmt.Errorf("button titles must be 1 to %d characters", interactiveMaxButtonTitleLength)
+			}
+		}
+
+	case "list":
+		if req.ButtonText == "" || utf8.RuneCountInString(req.ButtonText) > interactiveMaxButtonTitleLength {
+			return fmt.Errorf("button_text must be 1 to %d characters", interactiveMaxButtonTitleLength)
+		}
+		if len(req.Sections) == 0 || len(req.Sections) > interactiveMaxListSections {
+			return fmt.Errorf("list messages need 1 to %d sections", interactiveMaxListSections)
+		}
+		rowCount := 0
+		for _, section := range req.Sections {
+			if len(req.Sections) > 1 && section.Title == "" {
+				return fmt.Errorf("every section needs a title when there is more than one")
+			}
+			if utf8.RuneCountInString(section.Title) > interactiveMaxRowTitleLength {
+				return fmt.Errorf("section titles must be at most %d characters", interactiveMaxRowTitleLength)
+			}
+			if len(section.Rows) == 0 {
+				return fmt.Errorf("every section needs at least one row")
+			}
+			for _, row := range section.Rows {
+				if err := checkID(row.ID); err != nil {
+					return err
+				}
+				if row.Title == "" || utf8.RuneCountInString(row.Title) > interactiveMaxRowTitleLength {
+					return fmt.Errorf("row titles must be 1 to %d characters", interactiveMaxRowTitleLength)
+				}
+				if utf8.RuneCountInString(row.Description) > interactiveMaxRowDescLength {
+					return fmt.Errorf("row descriptions must be at most %d characters", interactiveMaxRowDescLength)
+				}
+			}
+			rowCount += len(section.Rows)
+		}
+		if rowCount > interactiveMaxListRows {
+			return fmt.Errorf("list messages can have at most %d rows", interactiveMaxListRows)
+		}
+
+	default:
+		return fmt.Errorf("type must be button or list")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/shridarpatil/whatomate/internal/handlers"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestApp_SendInteractiveMessage_List(t *testing.T) {
+	mockServer := newMockWhatsAppServer()
+	defer mockServer.close()
+
+	app := newMsgTestApp(t, mockServer)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := createTestAccount(t, app, org.ID)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+	req := testutil.NewJSONRequest(t, map[string]any{
+		"type":        "list",
+		"body":        "What would you like to order?",
+		"button_text": "View menu",
+		"sections": []map[string]any{
+			{"title": "Pizza", "rows": []map[string]string{{"id": "margherita", "title": "Margherita", "description": "Tomato and mozzarella"}}},
+			{"title": "Drinks", "rows": []map[string]string{{"id": "cola", "title": "Cola"}}},
+		},
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+	require.NoError(t, app.SendInteractiveMessage(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.MessageResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, models.MessageTypeInteractive, resp.Data.MessageType)
+	assert.Equal(t, "list", resp.Data.InteractiveData["type"])
+	assert.Equal(t, "View menu", resp.Data.InteractiveData["button_text"])
+
+	app.WaitForBackgroundTasks()
+	require.Len(t, mockServer.sentMessages, 1)
+	interactive := mockServer.sentMessages[0]["interactive"].(map[string]any)
+	assert.Equal(t, "list", interactive["type"])
+	sections := interactive["action"].(map[string]any)["sections"].([]any)
+	assert.Len(t, sections, 2)
+
+	var saved models.Message
+	require.NoError(t, app.DB.First(&saved, resp.Data.ID).Error)
+	assert.Equal(t, "What would you like to order?", saved.Content)
+	assert.Len(t, saved.InteractiveData["rows"], 2)
+}
+
+func TestApp_SendInteractiveMessage_Validation(t *testing.T) {
+	mockServer := newMockWhatsAppServer()
+	defer mockServer.close()
+
+	app := newMsgTestApp(t, mockServer)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := createTestAccount(t, app, org.ID)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+	button := func(id string) map[string]string { return map[string]string{"id": id, "title": "Option " + id} }
+	rows := func(n int) []map[string]string {
+		result := make([]map[string]string, n)
+		for i := range result {
+			result[i] = button(fmt.Sprint(i))
+		}
+		return result
+	}
+
+	tests := []struct {
+		name string
+		body map[string]any
+	}{
+		{name: "unknown type", body: map[string]any{"type": "carousel", "body": "Hi"}},
+		{name: "missing body", body: map[string]any{"type": "button", "buttons": rows(1)}},
+		{name: "too many buttons", body: map[string]any{"type": "button", "body": "Hi", "buttons": rows(4)}},
+		{name: "duplicate button ids", body: map[string]any{"type": "button", "body": "Hi", "buttons": []map[string]string{button("a"), button("a")}}},
+		{name: "long button title", body: map[string]any{"type": "button", "body": "Hi", "buttons": []map[string]string{{"id": "a", "title": "This title is far too long"}}}},
+		{name: "list without button text", body: map[string]any{"type": "list", "body": "Hi", "sections": []map[string]any{{"rows": rows(1)}}}},
+		{name: "too many rows", body: map[string]any{"type": "list", "body": "Hi", "button_text": "View", "sections": []map[string]any{{"title": "A", "rows": rows(11)}}}},
+		{name: "untitled section among several", body: map[string]any{"type": "list", "body": "Hi", "button_text": "View", "sections": []map[string]any{{"title": "A", "rows": rows(1)}, {"rows": []map[string]string{button("b")}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testutil.NewJSONRequest(t, tt.body)
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			testutil.SetPathParam(req, "id", contact.ID.String())
+			require.NoError(t, app.SendInteractiveMessage(req))
+			assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+		})
+	}
+
+	var count int64
+	app.DB.Model(&models.Message{}).Where("contact_id = ?", contact.ID).Count(&count)
+	assert.Zero(t, count, "rejected messages aren't stored")
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+const (
+	maxKeywordImportSize = 2 << 20 // 2MB
+	maxKeywordImportRows = 1000
+
+	// defaultKeywordRulePriority mirrors the column default on KeywordRule.Priority
+	defaultKeywordRulePriority = 10
+)
+
+// keywordImportColumns are the recognised CSV columns; keywords and response_text are required
+var keywordImportColumns = []string{"name", "keywords", "match_type", "response_text", "priority"}
+
+// KeywordRuleImportResult is the outcome of importing one CSV row
+type KeywordRuleImportResult struct {
+	Row    int    `json:"row"` // 1-based line number in the file, header included
+	Name   string `json:"name,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"` // created, error
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportKeywordRulesCSV creates keyword rules from an uploaded CSV with the
+// columns name, keywords (pipe-separated), match_type, response_text and
+// priority. Each row is imported independently; rows that fail validation are
+// reported in the results without affecting the others.
+func (a *App) ImportKeywordRulesCSV(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if !a.HasPermission(userID, models.ResourceChatbotKeywords, models.ActionWrite, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Permission denied", nil, "")
+	}
+
+	form, err := r.RequestCtx.MultipartForm()
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid multipart form", nil, "")
+	}
+	files := form.File["file"]
+	if len(files) == 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "file is required", nil, "")
+	}
+	if files[0].Size > maxKeywordImportSize {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "File too large. Maximum size is 2MB", nil, "")
+	}
+
+	file, err := files[0].Open()
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Failed to read file", nil, "")
+	}
+	defer file.Close() //nolint:errcheck
+
+	reader := csv.NewReader(io.LimitReader(file, maxKeywordImportSize))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Failed to read CSV header", nil, "")
+	}
+	colIndex := make(map[string]int, len(header))
+	for i, h := range header {
+		h = strings.TrimPrefix(h, "\ufeff") // Spreadsheet exports often start with a BOM
+		h = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(h)), " ", "_")
+		if slices.Contains(keywordImportColumns, h) {
+			colIndex[h] = i
+		}
+	}
+	for _, col := range []string{"keywords", "response_text"} {
+		if _, ok := colIndex[col]; !ok {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, fmt.Sprintf("Required column '%s' not found in CSV", col), nil, "")
+		}
+	}
+
+	results := []KeywordRuleImportResult{}
+	created := 0
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if row > maxKeywordImportRows+1 {
+			results = append(results, KeywordRuleImportResult{
+				Row:    row,
+				Status: "error",
+				Error:  fmt.Sprintf("Import limited to %d rows, remaining rows skipped", maxKeywordImportRows),
+			})
+			break
+		}
+		if err != nil {
+			results = append(results, KeywordRuleImportResult{Row: row, Status: "error", Error: "Failed to parse row"})
+			continue
+		}
+
+		field := func(col string) string {
+			if idx, ok := colIndex[col]; ok && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+
+		rule, errMsg := parseKeywordImportRow(field)
+		result := KeywordRuleImportResult{Row: row, Name: rule.Name}
+		if errMsg == "" {
+			rule.ID = uuid.New()
+			rule.OrganizationID = orgID
+			// Select all columns so an explicit priority of 0 isn't replaced by the column default
+			if err := a.DB.Select("*").Create(&rule).Error; err != nil {
+				a.Log.Error("Failed to import keyword rule", "error", err, "row", row)
+				errMsg = "Failed to create keyword rule"
+			}
+		}
+		if errMsg != "" {
+			result.Status = "error"
+			result.Error = errMsg
+		} else {
+			result.Status = "created"
+			result.ID = rule.ID.String()
+			created++
+		}
+		results = append(results, result)
+	}
+
+	if created > 0 {
+		a.InvalidateKeywordRulesCache(orgID)
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"results": results,
+		"created": created,
+		"failed":  len(results) - created,
+	})
+}
+
+// parseKeywordImportRow builds a keyword rule from one CSV row, returning an
+// error message when the row is invalid
+func parseKeywordImportRow(field func(col string) string) (models.KeywordRule, string) {
+	rule := models.KeywordRule{
+		Name:         field("name"),
+		MatchType:    models.MatchType(strings.ToLower(field("match_type"))),
+		ResponseType: models.ResponseTypeText,
+		Priority:     defaultKeywordRulePriority,
+		IsEnabled:    true,
+	}
+
+	for _, kw := range strings.Split(field("keywords"), "|") {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			rule.Keywords = append(rule.Keywords, kw)
+		}
+	}
+	if len(rule.Keywords) == 0 {
+		return rule, "At least one keyword is required"
+	}
+	if rule.Name == "" {
+		rule.Name = rule.Keywords[0]
+	}
+
+	switch rule.MatchType {
+	case "":
+		rule.MatchType = models.MatchTypeContains
+	case models.MatchTypeExact, models.MatchTypeContains, models.MatchTypeStartsWith:
+	case models.MatchTypeRegex:
+		for _, kw := range rule.Keywords {
+			if _, err := regexp.Compile(kw); err != nil {
+				return rule, fmt.Sprintf("Invalid regex %q", kw)
+			}
+		}
+	default:
+		return rule, fmt.Sprintf("Invalid match_type %q, use exact, contains, starts_with or regex", rule.MatchType)
+	}
+
+	body := field("response_text")
+	if body == "" {
+		return rule, "response_text is required"
+	}
+	rule.ResponseContent = models.JSONB{"body": body}
+
+	if p := field("priority"); p != "" {
+		priority, err := strconv.Atoi(p)
+		if err != nil {
+			return rule, fmt.Sprintf("Invalid priority %q", p)
+		}
+		rule.Priority = priority
+	}
+
+	return rule, ""
+}
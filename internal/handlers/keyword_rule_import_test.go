@@ -0,0 +1,95 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shridarpatil/whatomate/internal/handlers"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestApp_ImportKeywordRulesCSV(t *testing.T) {
+	t.Parallel()
+
+	t.Run("imports valid rows and reports invalid ones", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		csv := "name,keywords,match_type,response_text,priority\n" +
+			"Greeting,hi|hello,exact,Hello there!,5\n" +
+			"Bad type,price,fuzzy,See pricing,\n" +
+			"No keywords, | ,contains,Empty,\n" +
+			",refund,,We will process your refund,0\n"
+		req := testutil.NewMultipartRequest(t, nil, "file", "rules.csv", []byte(csv))
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.ImportKeywordRulesCSV(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Results []handlers.KeywordRuleImportResult `json:"results"`
+				Created int                                `json:"created"`
+				Failed  int                                `json:"failed"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, 2, resp.Data.Created)
+		assert.Equal(t, 2, resp.Data.Failed)
+		require.Len(t, resp.Data.Results, 4)
+
+		assert.Equal(t, "created", resp.Data.Results[0].Status)
+		assert.Equal(t, 2, resp.Data.Results[0].Row)
+		assert.Equal(t, "error", resp.Data.Results[1].Status)
+		assert.Contains(t, resp.Data.Results[1].Error, "match_type")
+		assert.Equal(t, "error", resp.Data.Results[2].Status)
+		assert.Contains(t, resp.Data.Results[2].Error, "keyword")
+		assert.Equal(t, "created", resp.Data.Results[3].Status)
+
+		var rules []models.KeywordRule
+		require.NoError(t, app.DB.Where("organization_id = ?", org.ID).Order("name").Find(&rules).Error)
+		require.Len(t, rules, 2)
+		assert.Equal(t, "Greeting", rules[0].Name)
+		assert.Equal(t, models.MatchTypeExact, rules[0].MatchType)
+		assert.Equal(t, 5, rules[0].Priority)
+		assert.Equal(t, "Hello there!", rules[0].ResponseContent["body"])
+		assert.Equal(t, "refund", rules[1].Name)
+		assert.Equal(t, models.MatchTypeContains, rules[1].MatchType)
+		assert.Equal(t, 0, rules[1].Priority)
+	})
+
+	t.Run("missing required column", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		req := testutil.NewMultipartRequest(t, nil, "file", "rules.csv", []byte("name,keywords\nGreeting,hi\n"))
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.ImportKeywordRulesCSV(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("forbidden without keyword write", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+
+		req := testutil.NewMultipartRequest(t, nil, "file", "rules.csv", []byte("keywords,response_text\nhi,hello\n"))
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.ImportKeywordRulesCSV(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
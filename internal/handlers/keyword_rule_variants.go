@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+)
+
+// Limits for keyword rule response variants
+const (
+	keywordMaxVariants      = 10
+	keywordMaxVariantWeight = 100
+)
+
+// keywordVariantWeight returns a variant's weight. Variants without a weight
+// count as 1; anything that isn't a number counts as 0.
+func keywordVariantWeight(variant map[string]interface{}) float64 {
+	v, ok := variant["weight"]
+	if !ok {
+		return 1
+	}
+	switch w := v.(type) {
+	case float64:
+		return w
+	case int:
+		return float64(w)
+	default:
+		return 0
+	}
+}
+
+// keywordVariants returns the response variants configured in content, if any
+func keywordVariants(content models.JSONB) []map[string]interface{} {
+	raw, ok := content["variants"].([]interface{})
+	if !ok {
+		return nil
+	}
+	variants := make([]map[string]interface{}, 0, len(raw))
+	for _, v := range raw {
+		if m, ok := v.(map[string]interface{}); ok {
+			variants = append(variants, m)
+		}
+	}
+	return variants
+}
+
+// selectKeywordContent picks the response content to send for a rule match.
+// Rules without variants return their content as is and a nil index. Otherwise
+// a variant is picked by weight, using roll in [0, 1), and its index returned.
+func selectKeywordContent(content models.JSONB, roll float64) (models.JSONB, *int) {
+	variants := keywordVariants(content)
+	var total float64
+	for _, v := range variants {
+		if w := keywordVariantWeight(v); w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 {
+		return content, nil
+	}
+
+	target := roll * total
+	last := -1
+	for i, v := range variants {
+		w := keywordVariantWeight(v)
+		if w <= 0 {
+			continue
+		}
+		last = i
+		if target < w {
+			break
+		}
+		target -= w
+	}
+	return models.JSONB(variants[last]), &last
+}
+
+// pickKeywordContent picks a rule's response content at random
+func pickKeywordContent(content models.JSONB) (models.JSONB, *int) {
+	return selectKeywordContent(content, rand.Float64())
+}
+
+// validateKeywordVariants checks response_content.variants and returns an
+// error message, or "" when valid or absent.
+func validateKeywordVariants(responseType models.ResponseType, content map[string]interface{}) string {
+	raw, ok := content["variants"]
+	if !ok {
+		return ""
+	}
+	if responseType == models.ResponseTypeTemplate {
+		return "Template responses don't support variants"
+	}
+	list, ok := raw.([]interface{})
+	if !ok || len(list) == 0 || len(list) > keywordMaxVariants {
+		return fmt.Sprintf("variants must be a list of 1 to %d responses", keywordMaxVariants)
+	}
+
+	var total float64
+	for i, v := range list {
+		variant, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Sprintf("variant %d must be an object", i+1)
+		}
+		if body, _ := variant["body"].(string); body == "" {
+			return fmt.Sprintf("variant %d needs a body", i+1)
+		}
+		if w, ok := variant["weight"]; ok {
+			if _, isNumber := w.(float64); !isNumber {
+				return fmt.Sprintf("variant %d weight must be a number", i+1)
+			}
+		}
+		weight := keywordVariantWeight(variant)
+		if weight < 0 || weight > keywordMaxVariantWeight {
+			return fmt.Sprintf("variant %d weight must be between 0 and %d", i+1, keywordMaxVariantWeight)
+		}
+		total += weight
+	}
+	if total <= 0 {
+		return "At least one variant needs a weight above 0"
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectKeywordContent(t *testing.T) {
+	content := models.JSONB{
+		"variants": []interface{}{
+			map[string]interface{}{"body": "A", "weight": float64(1)},
+			map[string]interface{}{"body": "Paused", "weight": float64(0)},
+			map[string]interface{}{"body": "B", "weight": float64(3)},
+		},
+	}
+
+	tests := []struct {
+		roll    float64
+		body    string
+		variant int
+	}{
+		{roll: 0, body: "A", variant: 0},
+		{roll: 0.24, body: "A", variant: 0},
+		{roll: 0.25, body: "B", variant: 2},
+		{roll: 0.999, body: "B", variant: 2},
+	}
+	for _, tt := range tests {
+		selected, variant := selectKeywordContent(content, tt.roll)
+		require.NotNil(t, variant)
+		assert.Equal(t, tt.variant, *variant, "roll %v", tt.roll)
+		assert.Equal(t, tt.body, selected["body"], "roll %v", tt.roll)
+	}
+
+	plain := models.JSONB{"body": "Hello"}
+	selected, variant := selectKeywordContent(plain, 0.5)
+	assert.Nil(t, variant, "rules without variants have no variant index")
+	assert.Equal(t, "Hello", selected["body"])
+}
+
+func TestValidateKeywordVariants(t *testing.T) {
+	variant := func(body string, weight any) map[string]interface{} {
+		v := map[string]interface{}{"body": body}
+		if weight != nil {
+			v["weight"] = weight
+		}
+		return v
+	}
+
+	tests := []struct {
+		name         string
+		responseType models.ResponseType
+		variants     any
+		valid        bool
+	}{
+		{name: "weighted", responseType: models.ResponseTypeText, variants: []interface{}{variant("A", 70.0), variant("B", 30.0)}, valid: true},
+		{name: "weights default to 1", responseType: models.ResponseTypeText, variants: []interface{}{variant("A", nil), variant("B", nil)}, valid: true},
+		{name: "paused variant", responseType: models.ResponseTypeTransfer, variants: []interface{}{variant("A", 1.0), variant("B", 0.0)}, valid: true},
+		{name: "template", responseType: models.ResponseTypeTemplate, variants: []interface{}{variant("A", nil)}},
+		{name: "empty", responseType: models.ResponseTypeText, variants: []interface{}{}},
+		{name: "not a list", responseType: models.ResponseTypeText, variants: "A"},
+		{name: "missing body", responseType: models.ResponseTypeText, variants: []interface{}{variant("", 1.0)}},
+		{name: "negative weight", responseType: models.ResponseTypeText, variants: []interface{}{variant("A", 2.0), variant("B", -1.0)}},
+		{name: "weight too large", responseType: models.ResponseTypeText, variants: []interface{}{variant("A", 101.0)}},
+		{name: "weight not a number", responseType: models.ResponseTypeText, variants: []interface{}{variant("A", "50")}},
+		{name: "all weights zero", responseType: models.ResponseTypeText, variants: []interface{}{variant("A", 0.0), variant("B", 0.0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := validateKeywordVariants(tt.responseType, map[string]interface{}{"variants": tt.variants})
+			if tt.valid {
+				assert.Empty(t, msg)
+			} else {
+				assert.NotEmpty(t, msg)
+			}
+		})
+	}
+
+	assert.Empty(t, validateKeywordVariants(models.ResponseTypeText, map[string]interface{}{"body": "Hi"}))
+}
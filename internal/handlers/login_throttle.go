@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/shridarpatil/whatomate/internal/middleware"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// loginIPThresholdFactor is how many times more failures an IP can have
+// across all emails than a single email from that IP before it's locked out
+const loginIPThresholdFactor = 4
+
+// loginThrottleKeys returns the identifiers failed logins are counted
+// against: the client IP, and the email from that IP. The email is never
+// counted on its own, so nobody can lock a user out from another address.
+// Nil when lockout is disabled.
+func (a *App) loginThrottleKeys(r *fastglue.Request, email string) []string {
+	if !a.Config.RateLimit.Enabled || a.Config.RateLimit.LoginLockoutThreshold <= 0 || a.Redis == nil {
+		return nil
+	}
+	ip := middleware.ClientIP(r, a.Config.RateLimit.TrustProxy)
+	keys := []string{"ip:" + ip}
+	if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+		keys = append(keys, "email:"+email+":ip:"+ip)
+	}
+	return keys
+}
+
+// loginLockoutThreshold returns the failures a key may have before lockout
+func (a *App) loginLockoutThreshold(key string) int64 {
+	threshold := int64(a.Config.RateLimit.LoginLockoutThreshold)
+	if strings.HasPrefix(key, "ip:") {
+		return threshold * loginIPThresholdFactor
+	}
+	return threshold
+}
+
+// loginFailuresKey returns the Redis key counting recent failed logins.
+func loginFailuresKey(key string) string {
+	return fmt.Sprintf("login_failures:%s", key)
+}
+
+// loginLockoutKey returns the Redis key that exists while logins are locked out.
+func loginLockoutKey(key string) string {
+	return fmt.Sprintf("login_lockout:%s", key)
+}
+
+// loginLockoutRemaining returns how long the longest lockout on any of the
+// keys has left, or 0 if none is locked. It fails open on Redis errors.
+func (a *App) loginLockoutRemaining(keys []string) time.Duration {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var remaining time.Duration
+	for _, key := range keys {
+		ttl, err := a.Redis.PTTL(ctx, loginLockoutKey(key)).Result()
+		if err != nil {
+			a.Log.Error("Failed to check login lockout", "error", err, "key", key)
+			continue
+		}
+		if ttl > remaining {
+			remaining = ttl
+		}
+	}
+	return remaining
+}
+
+// recordLoginFailure counts a failed login against each key and locks out
+// the ones that reached the threshold. The lockout starts at
+// LoginLockoutSeconds and doubles with each further failure.
+func (a *App) recordLoginFailure(keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	cfg := a.Config.RateLimit
+	maxLockout := time.Duration(cfg.LoginLockoutMaxSeconds) * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	for _, key := range keys {
+		failures, err := a.Redis.Incr(ctx, loginFailuresKey(key)).Result()
+		if err != nil {
+			a.Log.Error("Failed to record login failure", "error", err, "key", key)
+			continue
+		}
+		// Keep the count long enough for lockouts to keep escalating
+		a.Redis.Expire(ctx, loginFailuresKey(key), maxLockout)
+
+		over := failures - a.loginLockoutThreshold(key)
+		if over < 0 {
+			continue
+		}
+		lockout := time.Duration(float64(cfg.LoginLockoutSeconds)*math.Pow(2, float64(over))) * time.Second
+		if lockout <= 0 || lockout > maxLockout {
+			lockout = maxLockout
+		}
+		if err := a.Redis.Set(ctx, loginLockoutKey(key), failures, lockout).Err(); err != nil {
+			a.Log.Error("Failed to lock out logins", "error", err, "key", key)
+			continue
+		}
+		a.Log.Warn("Logins locked out after repeated failures", "key", key, "failures", failures, "lockout", lockout)
+	}
+}
+
+// clearLoginFailures resets the email's failure count from the client IP
+// after a successful login. The IP's own count is kept, since an attacker can
+// log in to their own account from the same address.
+func (a *App) clearLoginFailures(keys []string) {
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "email:") {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		if err := a.Redis.Del(ctx, loginFailuresKey(key), loginLockoutKey(key)).Err(); err != nil {
+			a.Log.Error("Failed to clear login failures", "error", err, "key", key)
+		}
+		cancel()
+	}
+}
+
+// sendLoginLockedOut responds with 429 and a Retry-After header.
+func sendLoginLockedOut(r *fastglue.Request, remaining time.Duration) error {
+	retryAfter := int(math.Ceil(remaining.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	r.RequestCtx.Response.Header.Set("Retry-After", fmt.Sprintf("%d", retryAfter))
+	return r.SendErrorEnvelope(fasthttp.StatusTooManyRequests,
+		"Too many failed login attempts. Please try again later.", nil, "")
+}
@@ -3,9 +3,16 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shridarpatil/whatomate/internal/models"
@@ -75,6 +82,111 @@ func getExtensionFromMimeType(mimeType string) string {
 	}
 }
 
+// Media types and sizes WhatsApp accepts in outgoing messages. Documents may
+// be any type.
+var (
+	outgoingMediaMimeTypes = map[models.MessageType][]string{
+		models.MessageTypeImage: {"image/jpeg", "image/png"},
+		models.MessageTypeVideo: {"video/mp4", "video/3gpp"},
+		models.MessageTypeAudio: {"audio/aac", "audio/amr", "audio/mpeg", "audio/mp4", "audio/ogg"},
+	}
+	outgoingMediaMaxBytes = map[models.MessageType]int64{
+		models.MessageTypeImage:    5 << 20,
+		models.MessageTypeVideo:    16 << 20,
+		models.MessageTypeAudio:    16 << 20,
+		models.MessageTypeDocument: 100 << 20,
+	}
+)
+
+// mediaFetchTimeout bounds downloading media sent by URL
+const mediaFetchTimeout = 30 * time.Second
+
+// isMediaMessageType reports whether t is sent as a media message
+func isMediaMessageType(t models.MessageType) bool {
+	_, ok := outgoingMediaMaxBytes[t]
+	return ok
+}
+
+// validateOutgoingMedia checks media against WhatsApp's type and size limits
+func validateOutgoingMedia(mediaType models.MessageType, mimeType string, size int64) error {
+	maxBytes, ok := outgoingMediaMaxBytes[mediaType]
+	if !ok {
+		return fmt.Errorf("unsupported media type: %s", mediaType)
+	}
+	if size == 0 {
+		return fmt.Errorf("media file is empty")
+	}
+	if size > maxBytes {
+		return fmt.Errorf("%s must be at most %d MB", mediaType, maxBytes>>20)
+	}
+	if allowed, ok := outgoingMediaMimeTypes[mediaType]; ok {
+		baseType, _, err := mime.ParseMediaType(mimeType)
+		if err != nil || !slices.Contains(allowed, baseType) {
+			return fmt.Errorf("unsupported %s format %q, expected one of: %s", mediaType, mimeType, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// fetchOutgoingMedia downloads media to send from a public URL and returns
+// its data and MIME type. Downloads larger than any media limit are refused.
+func (a *App) fetchOutgoingMedia(rawURL string) ([]byte, string, error) {
+	if err := validateWebhookURL(rawURL); err != nil {
+		return nil, "", fmt.Errorf("invalid media URL: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mediaFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid media URL: %w", err)
+	}
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		a.Log.Warn("Failed to fetch media", "error", err, "url", rawURL)
+		return nil, "", fmt.Errorf("failed to fetch media from URL")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("media URL returned status %d", resp.StatusCode)
+	}
+
+	maxBytes := outgoingMediaMaxBytes[models.MessageTypeDocument]
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch media from URL")
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, "", fmt.Errorf("media must be at most %d MB", maxBytes>>20)
+	}
+
+	return data, mediaMimeType(resp.Header.Get("Content-Type"), data), nil
+}
+
+// mediaMimeType returns the declared MIME type, sniffing it from the data
+// when none or only a generic one was given
+func mediaMimeType(declared string, data []byte) string {
+	if declared == "" || strings.HasPrefix(declared, "application/octet-stream") {
+		return http.DetectContentType(data)
+	}
+	return declared
+}
+
+// mediaFilenameFromURL returns the last segment of the URL path, or "file"
+func mediaFilenameFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "file"
+	}
+	name := path.Base(u.Path)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	return name
+}
+
 // DownloadAndSaveMedia downloads media from Meta and saves it locally
 // Returns the local file path (relative to media storage) or error
 func (a *App) DownloadAndSaveMedia(ctx context.Context, mediaID string, mimeType string, account *whatsapp.Account) (string, error) {
@@ -152,11 +264,11 @@ func (a *App) ServeMedia(r *fastglue.Request) error {
 		return nil
 	}
 
-	// Users without contacts:read permission can only access media from their assigned contacts
+	// Users limited to their own contacts can only access media from contacts visible to them
 	// or from contacts with an active team transfer where the user is a team member.
-	if !a.HasPermission(userID, models.ResourceContacts, models.ActionRead, orgID) {
+	if ownOnly, _ := a.contactVisibility(userID, orgID); ownOnly {
 		var contact models.Contact
-		if err := a.DB.Where("id = ? AND assigned_user_id = ?", message.ContactID, userID).First(&contact).Error; err != nil {
+		if err := a.scopeContactsQuery(a.DB.Where("id = ?", message.ContactID), userID, orgID).First(&contact).Error; err != nil {
 			// Not directly assigned — check team membership via active transfer
 			var transfer models.AgentTransfer
 			if err := a.DB.Where("contact_id = ? AND organization_id = ? AND status = ? AND team_id IS NOT NULL",
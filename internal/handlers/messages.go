@@ -3,10 +3,13 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/contactutil"
 	"github.com/shridarpatil/whatomate/internal/models"
 	"github.com/shridarpatil/whatomate/internal/templateutil"
 	"github.com/shridarpatil/whatomate/internal/websocket"
@@ -40,15 +43,17 @@ type OutgoingMessageRequest struct {
 	Caption       string
 
 	// Interactive messages
-	InteractiveType string            // "button", "list", "cta_url"
-	BodyText        string            // Body text for interactive messages
-	Buttons         []whatsapp.Button // For button/list messages
-	ButtonText      string            // For CTA URL button
-	URL             string            // For CTA URL button
+	InteractiveType string                 // "button", "list", "cta_url"
+	BodyText        string                 // Body text for interactive messages
+	Buttons         []whatsapp.Button      // For button/list messages
+	ButtonText      string                 // For CTA URL button, or the button that opens a list
+	URL             string                 // For CTA URL button
+	Sections        []whatsapp.ListSection // For list messages with sections
 
 	// Template messages
-	Template   *models.Template
-	BodyParams map[string]string // Parameter name -> value (supports both named and positional)
+	Template     *models.Template
+	BodyParams   map[string]string // Parameter name -> value (supports both named and positional)
+	HeaderParams map[string]string // Parameter values for a TEXT header, keyed the same way
 
 	// WhatsApp Flow messages
 	FlowID          string // Meta Flow ID
@@ -78,6 +83,9 @@ type MessageSendOptions struct {
 	// Async if true, sends in background goroutine and returns immediately
 	// Message is persisted before send, status updated after
 	Async bool
+
+	// SkipOptedOut refuses to send to contacts who have opted out (for automated sends)
+	SkipOptedOut bool
 }
 
 // DefaultSendOptions returns options suitable for agent UI sends
@@ -97,6 +105,7 @@ func ChatbotSendOptions() MessageSendOptions {
 		DispatchWebhook:    false,
 		TrackSLA:           true,
 		Async:              false,
+		SkipOptedOut:       true,
 	}
 }
 
@@ -117,12 +126,20 @@ func SLASendOptions() MessageSendOptions {
 		DispatchWebhook:    false,
 		TrackSLA:           false,
 		Async:              false, // Sync to ensure message is sent before continuing
+		SkipOptedOut:       true,
 	}
 }
 
 // SendOutgoingMessage is the unified method for sending all types of WhatsApp messages.
 // It handles: text, media (image/video/audio/document), interactive (buttons/list/cta_url), and template messages.
 func (a *App) SendOutgoingMessage(ctx context.Context, req OutgoingMessageRequest, opts MessageSendOptions) (*models.Message, error) {
+	if req.Contact != nil && req.Contact.Blocked {
+		return nil, errContactBlocked
+	}
+	if opts.SkipOptedOut && req.Contact != nil && req.Contact.OptedOut {
+		return nil, errContactOptedOut
+	}
+
 	// 1. Create message record
 	msg := a.createOutgoingMessage(req, opts)
 
@@ -172,7 +189,12 @@ func (a *App) SendOutgoingMessage(ctx context.Context, req OutgoingMessageReques
 			switch req.InteractiveType {
 			case "cta_url":
 				return a.WhatsApp.SendCTAURLButton(sendCtx, waAccount, req.Contact.PhoneNumber, req.BodyText, req.ButtonText, req.URL)
-			default: // "button" or "list"
+			case "list":
+				if len(req.Sections) > 0 {
+					return a.WhatsApp.SendInteractiveList(sendCtx, waAccount, req.Contact.PhoneNumber, req.BodyText, req.ButtonText, req.Sections)
+				}
+				return a.WhatsApp.SendInteractiveButtons(sendCtx, waAccount, req.Contact.PhoneNumber, req.BodyText, req.Buttons)
+			default: // "button"
 				return a.WhatsApp.SendInteractiveButtons(sendCtx, waAccount, req.Contact.PhoneNumber, req.BodyText, req.Buttons)
 			}
 
@@ -181,6 +203,9 @@ func (a *App) SendOutgoingMessage(ctx context.Context, req OutgoingMessageReques
 				return "", fmt.Errorf("template is required for template messages")
 			}
 			components := whatsapp.BodyParamsToComponents(req.BodyParams)
+			if header := whatsapp.HeaderParamsToComponent(req.HeaderParams); header != nil {
+				components = append([]map[string]interface{}{header}, components...)
+			}
 			return a.WhatsApp.SendTemplateMessage(sendCtx, waAccount, req.Contact.PhoneNumber, req.Template.Name, req.Template.Language, components)
 
 		case models.MessageTypeFlow:
@@ -208,6 +233,14 @@ func (a *App) SendOutgoingMessage(ctx context.Context, req OutgoingMessageReques
 	} else {
 		wamid, err := sendFn(ctx)
 		a.finalizeMessageSend(msg, req, opts, wamid, err)
+		// Sync callers own msg, so reflect the outcome for them to inspect
+		if err != nil {
+			msg.Status = models.MessageStatusFailed
+			msg.ErrorMessage = err.Error()
+		} else {
+			msg.Status = models.MessageStatusSent
+			msg.WhatsAppMessageID = wamid
+		}
 	}
 
 	// 4. Immediate actions (before send completes for async)
@@ -279,8 +312,15 @@ func (a *App) createOutgoingMessage(req OutgoingMessageRequest, opts MessageSend
 			msg.Content = content
 			msg.TemplateName = req.Template.Name
 			msg.Metadata = models.JSONB{
-				"template_name": req.Template.Name,
-				"template_id":   req.Template.ID.String(),
+				"template_name":     req.Template.Name,
+				"template_id":       req.Template.ID.String(),
+				"template_language": req.Template.Language,
+			}
+			if len(req.BodyParams) > 0 {
+				msg.TemplateParams = stringParamsToJSONB(req.BodyParams)
+			}
+			if req.Template.HeaderType == "TEXT" && req.Template.HeaderContent != "" {
+				msg.Metadata["header"] = templateutil.ReplaceWithStringParams(req.Template.HeaderContent, req.HeaderParams)
 			}
 			// Store template buttons so they render in the chat bubble
 			if len(req.Template.Buttons) > 0 {
@@ -318,6 +358,26 @@ func (a *App) buildInteractiveData(req OutgoingMessageRequest) models.JSONB {
 			"url":         req.URL,
 		}
 	case "list":
+		if len(req.Sections) > 0 {
+			// Rows are also flattened so list bubbles render the same either way
+			var rows []interface{}
+			sections := make([]interface{}, len(req.Sections))
+			for i, section := range req.Sections {
+				sectionRows := make([]interface{}, len(section.Rows))
+				for j, row := range section.Rows {
+					sectionRows[j] = map[string]string{"id": row.ID, "title": row.Title, "description": row.Description}
+				}
+				rows = append(rows, sectionRows...)
+				sections[i] = map[string]interface{}{"title": section.Title, "rows": sectionRows}
+			}
+			return models.JSONB{
+				"type":        "list",
+				"body":        req.BodyText,
+				"button_text": req.ButtonText,
+				"sections":    sections,
+				"rows":        rows,
+			}
+		}
 		rows := make([]interface{}, len(req.Buttons))
 		for i, btn := range req.Buttons {
 			rows[i] = map[string]string{"id": btn.ID, "title": btn.Title}
@@ -476,10 +536,12 @@ func (a *App) dispatchMessageSentWebhook(account *models.WhatsAppAccount, contac
 }
 
 // updateContactLastMessage updates contact's last_message_at and preview
+// for an outgoing message
 func (a *App) updateContactLastMessage(contact *models.Contact, preview string) {
 	a.DB.Model(contact).Updates(map[string]any{
-		"last_message_at":      time.Now(),
-		"last_message_preview": preview,
+		"last_message_at":        time.Now(),
+		"last_message_preview":   preview,
+		"last_message_direction": models.DirectionOutgoing,
 	})
 }
 
@@ -528,6 +590,8 @@ type SendTemplateMessageRequest struct {
 	TemplateName   string            `json:"template_name"`   // Template name
 	TemplateID     string            `json:"template_id"`     // Alternative: template UUID
 	TemplateParams map[string]string `json:"template_params"` // Named or positional params
+	HeaderParams   map[string]string `json:"header_params"`   // Params for a TEXT header, named or positional
+	Language       string            `json:"language"`        // Optional: template language when looking up by name
 	AccountName    string            `json:"account_name"`    // Optional: specific WhatsApp account
 }
 
@@ -566,10 +630,18 @@ func (a *App) SendTemplateMessage(r *fastglue.Request) error {
 		}
 		template = *t
 	} else {
-		if err := a.DB.Where("name = ? AND organization_id = ?", req.TemplateName, orgID).First(&template).Error; err != nil {
+		// A template name can exist in several languages
+		query := a.DB.Where("name = ? AND organization_id = ?", req.TemplateName, orgID)
+		if req.Language != "" {
+			query = query.Where("language = ?", req.Language)
+		}
+		if err := query.First(&template).Error; err != nil {
 			return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Template not found", nil, "")
 		}
 	}
+	if req.Language != "" && template.Language != req.Language {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, fmt.Sprintf("Template language is %s, not %s", template.Language, req.Language), nil, "")
+	}
 
 	// Check template is approved
 	if template.Status != "APPROVED" {
@@ -591,23 +663,25 @@ func (a *App) SendTemplateMessage(r *fastglue.Request) error {
 		contact = c
 	} else {
 		// Find or create contact from phone number
-		phoneNumber := req.PhoneNumber
-		var c models.Contact
-		err := a.DB.Where("phone_number = ? AND organization_id = ?", phoneNumber, orgID).First(&c).Error
+		phoneNumber, err := contactutil.ValidatePhone(req.PhoneNumber, a.Config.WhatsApp.DefaultPhoneRegion)
 		if err != nil {
-			// Contact not found, create new one
-			c = models.Contact{
-				BaseModel:      models.BaseModel{ID: uuid.New()},
-				OrganizationID: orgID,
-				PhoneNumber:    phoneNumber,
-			}
-			if err := a.DB.Create(&c).Error; err != nil {
-				a.Log.Error("Failed to create contact", "error", err, "phone", phoneNumber)
-				return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to create contact", nil, "")
-			}
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+		}
+		c, created, err := contactutil.GetOrCreateContact(a.DB, orgID, phoneNumber, "")
+		if err != nil {
+			a.Log.Error("Failed to create contact", "error", err, "phone", phoneNumber)
+			return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to create contact", nil, "")
+		}
+		if created {
 			a.Log.Info("Contact created from API", "contact_id", c.ID, "phone", phoneNumber)
 		}
-		contact = &c
+		contact = c
+	}
+	if a.isPhoneBlacklisted(orgID, contact.PhoneNumber) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Phone number is blacklisted", nil, "")
+	}
+	if contact.Blocked {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Contact is blocked", nil, "")
 	}
 
 	// Determine which WhatsApp account to use (explicit > template > contact > default)
@@ -619,37 +693,31 @@ func (a *App) SendTemplateMessage(r *fastglue.Request) error {
 		accountName = contact.WhatsAppAccount
 	}
 
-	account, err := a.resolveWhatsAppAccount(orgID, accountName)
+	account, err := a.resolveSendingWhatsAppAccount(orgID, accountName)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
 	}
 
-	// Extract parameter names and resolve values
-	paramNames := templateutil.ExtParamNames(template.BodyContent)
-	bodyParams := templateutil.ResolveParamsFromMap(paramNames, req.TemplateParams)
-
-	// Validate that all required parameters are provided
-	if len(paramNames) > 0 {
-		var missingParams []string
-		for i, name := range paramNames {
-			if i >= len(bodyParams) || bodyParams[i] == "" {
-				missingParams = append(missingParams, name)
-			}
-		}
-		if len(missingParams) > 0 {
-			return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
-				fmt.Sprintf("Missing template parameters: %s. Expected parameters: %v", strings.Join(missingParams, ", "), paramNames),
-				nil, "")
-		}
+	// Validate the parameters against the stored template definition
+	if errMsg := templateParamsError("template", templateutil.ExtParamNames(template.BodyContent), req.TemplateParams); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	var headerParamNames []string
+	if template.HeaderType == "TEXT" {
+		headerParamNames = templateutil.ExtParamNames(template.HeaderContent)
+	}
+	if errMsg := templateParamsError("header", headerParamNames, req.HeaderParams); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
 	}
 
 	// Send using unified message sender
 	msgReq := OutgoingMessageRequest{
-		Account:    account,
-		Contact:    contact,
-		Type:       models.MessageTypeTemplate,
-		Template:   &template,
-		BodyParams: req.TemplateParams,
+		Account:      account,
+		Contact:      contact,
+		Type:         models.MessageTypeTemplate,
+		Template:     &template,
+		BodyParams:   req.TemplateParams,
+		HeaderParams: req.HeaderParams,
 	}
 
 	opts := DefaultSendOptions()
@@ -678,3 +746,50 @@ func (a *App) SendTemplateMessage(r *fastglue.Request) error {
 	return r.SendEnvelope(response)
 }
 
+// templateParamsError checks the params supplied for one section of a
+// template against the parameter names it defines. Each parameter may be
+// given by name or by 1-based position. It returns a message describing any
+// missing, unknown or surplus parameters, or "" when they match.
+func templateParamsError(section string, paramNames []string, params map[string]string) string {
+	values := templateutil.ResolveParamsFromMap(paramNames, params)
+	var missing []string
+	for i, name := range paramNames {
+		if i >= len(values) || values[i] == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("Missing %s parameters: %s. Expected parameters: %v", section, strings.Join(missing, ", "), paramNames)
+	}
+
+	known := make(map[string]bool, 2*len(paramNames))
+	for i, name := range paramNames {
+		known[name] = true
+		known[strconv.Itoa(i+1)] = true
+	}
+	var unknown []string
+	for key := range params {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Sprintf("Unknown %s parameters: %s. Expected parameters: %v", section, strings.Join(unknown, ", "), paramNames)
+	}
+
+	// The same parameter given both by name and by position
+	if len(params) > len(paramNames) {
+		return fmt.Sprintf("Expected %d %s parameters, got %d", len(paramNames), section, len(params))
+	}
+	return ""
+}
+
+// stringParamsToJSONB stores template parameter values in a JSONB column
+func stringParamsToJSONB(params map[string]string) models.JSONB {
+	result := make(models.JSONB, len(params))
+	for k, v := range params {
+		result[k] = v
+	}
+	return result
+}
@@ -839,6 +839,7 @@ func TestApp_SendOutgoingMessage_ContactLastMessageUpdated(t *testing.T) {
 	require.NoError(t, app.DB.First(&updatedContact, contact.ID).Error)
 	assert.NotNil(t, updatedContact.LastMessageAt)
 	assert.Equal(t, "This is a test message for preview", updatedContact.LastMessagePreview)
+	assert.Equal(t, models.DirectionOutgoing, updatedContact.LastMessageDirection)
 }
 
 func TestApp_SendOutgoingMessage_MediaPreview(t *testing.T) {
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// Keywords used until the organization configures its own
+var (
+	defaultOptOutKeywords = []string{"STOP", "UNSUBSCRIBE"}
+	defaultOptInKeywords  = []string{"START"}
+)
+
+// optInKeywordSource is the opt-in source recorded when a contact sends an opt-in keyword
+const optInKeywordSource = "opt-in keyword"
+
+// errContactOptedOut is returned for automated sends to a contact who opted out
+var errContactOptedOut = errors.New("contact has opted out of messages")
+
+// OptedOutContactResponse describes a contact who opted out of messages
+type OptedOutContactResponse struct {
+	ContactID       uuid.UUID  `json:"contact_id"`
+	PhoneNumber     string     `json:"phone_number"`
+	ProfileName     string     `json:"profile_name"`
+	WhatsAppAccount string     `json:"whatsapp_account,omitempty"`
+	OptedOutAt      *time.Time `json:"opted_out_at"`
+}
+
+// RecordOptInRequest records a contact's consent to be messaged
+type RecordOptInRequest struct {
+	Source    string             `json:"source"` // Where consent was collected, e.g. "checkout page"
+	Method    models.OptInMethod `json:"method"`
+	OptedInAt *time.Time         `json:"opted_in_at"` // Defaults to now
+}
+
+// ContactOptInResponse reports a contact's consent status
+type ContactOptInResponse struct {
+	ContactID     uuid.UUID          `json:"contact_id"`
+	OptInAt       *time.Time         `json:"opt_in_at"`
+	OptInSource   string             `json:"opt_in_source"`
+	OptInMethod   models.OptInMethod `json:"opt_in_method"`
+	OptedOut      bool               `json:"opted_out"`
+	HasValidOptIn bool               `json:"has_valid_opt_in"`
+}
+
+// RecordOptIn records when, where and how a contact consented to messaging.
+// Consent given after the contact opted out opts them back in.
+func (a *App) RecordOptIn(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceContacts, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	var req RecordOptInRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	req.Source = strings.TrimSpace(req.Source)
+	if req.Source == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "source is required", nil, "")
+	}
+	if len(req.Source) > 255 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "source must be at most 255 characters", nil, "")
+	}
+	switch req.Method {
+	case models.OptInMethodWebForm, models.OptInMethodWhatsApp, models.OptInMethodPhone,
+		models.OptInMethodInPerson, models.OptInMethodImport, models.OptInMethodOther:
+	default:
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			"method must be one of: web_form, whatsapp, phone, in_person, import, other", nil, "")
+	}
+	now := time.Now()
+	optedInAt := now
+	if req.OptedInAt != nil {
+		if req.OptedInAt.After(now) {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "opted_in_at can't be in the future", nil, "")
+		}
+		optedInAt = *req.OptedInAt
+	}
+
+	var contact models.Contact
+	if err := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID).
+		First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	if contact.OptedOut && contact.OptedOutAt != nil && optedInAt.Before(*contact.OptedOutAt) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Contact opted out after this opt-in", nil, "")
+	}
+
+	updates := map[string]any{
+		"opt_in_at":     optedInAt,
+		"opt_in_source": req.Source,
+		"opt_in_method": req.Method,
+		"opted_out":     false,
+		"opted_out_at":  nil,
+	}
+	if err := a.DB.Model(&contact).Updates(updates).Error; err != nil {
+		a.Log.Error("Failed to record opt-in", "error", err, "contact_id", contact.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to record opt-in", nil, "")
+	}
+
+	a.Log.Info("Contact opt-in recorded", "contact_id", contact.ID, "method", req.Method, "user_id", userID)
+
+	return r.SendEnvelope(ContactOptInResponse{
+		ContactID:     contact.ID,
+		OptInAt:       &optedInAt,
+		OptInSource:   req.Source,
+		OptInMethod:   req.Method,
+		OptedOut:      false,
+		HasValidOptIn: true,
+	})
+}
+
+// ListOptedOutContacts returns the organization's opted-out contacts, most
+// recent first
+func (a *App) ListOptedOutContacts(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceContacts, models.ActionRead); err != nil {
+		return nil
+	}
+
+	pg := parsePagination(r)
+	query := a.DB.Model(&models.Contact{}).Where("organization_id = ? AND opted_out = ?", orgID, true)
+
+	var total int64
+	query.Count(&total)
+
+	var contacts []models.Contact
+	if err := pg.Apply(query.Order("opted_out_at DESC")).Find(&contacts).Error; err != nil {
+		a.Log.Error("Failed to list opted-out contacts", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list opted-out contacts", nil, "")
+	}
+
+	shouldMask := a.ShouldMaskPhoneNumbers(orgID)
+	result := make([]OptedOutContactResponse, len(contacts))
+	for i, c := range contacts {
+		phoneNumber := c.PhoneNumber
+		profileName := c.ProfileName
+		if shouldMask {
+			phoneNumber = MaskPhoneNumber(phoneNumber)
+			profileName = MaskIfPhoneNumber(profileName)
+		}
+		result[i] = OptedOutContactResponse{
+			ContactID:       c.ID,
+			PhoneNumber:     phoneNumber,
+			ProfileName:     profileName,
+			WhatsAppAccount: c.WhatsAppAccount,
+			OptedOutAt:      c.OptedOutAt,
+		}
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"contacts": result,
+		"total":    total,
+		"page":     pg.Page,
+		"limit":    pg.Limit,
+	})
+}
+
+// applyOptOutKeywords opts the contact out, or back in, when an incoming
+// message is one of the organization's keywords. An opt-in keyword also
+// records the contact's consent if they had none. It reports whether the
+// contact is opted out afterwards.
+func (a *App) applyOptOutKeywords(orgID uuid.UUID, contact *models.Contact, text string) bool {
+	if strings.TrimSpace(text) == "" {
+		return contact.OptedOut
+	}
+
+	var org models.Organization
+	if err := a.DB.Select("id", "settings").Where("id = ?", orgID).First(&org).Error; err != nil {
+		a.Log.Error("Failed to load organization for opt-out keywords", "error", err, "org_id", orgID)
+		return contact.OptedOut
+	}
+
+	if !contact.OptedOut && matchesKeyword(text, keywordSetting(org.Settings, "opt_out_keywords", defaultOptOutKeywords)) {
+		now := time.Now()
+		if err := a.DB.Model(contact).Updates(map[string]any{
+			"opted_out":    true,
+			"opted_out_at": now,
+		}).Error; err != nil {
+			a.Log.Error("Failed to opt out contact", "error", err, "contact_id", contact.ID)
+			return false
+		}
+		contact.OptedOut = true
+		contact.OptedOutAt = &now
+		a.Log.Info("Contact opted out", "contact_id", contact.ID, "org_id", orgID)
+	} else if !contact.HasValidOptIn() && matchesKeyword(text, keywordSetting(org.Settings, "opt_in_keywords", defaultOptInKeywords)) {
+		now := time.Now()
+		if err := a.DB.Model(contact).Updates(map[string]any{
+			"opted_out":     false,
+			"opted_out_at":  nil,
+			"opt_in_at":     now,
+			"opt_in_source": optInKeywordSource,
+			"opt_in_method": models.OptInMethodWhatsApp,
+		}).Error; err != nil {
+			a.Log.Error("Failed to opt contact in", "error", err, "contact_id", contact.ID)
+			return contact.OptedOut
+		}
+		contact.OptedOut = false
+		contact.OptedOutAt = nil
+		contact.OptInAt = &now
+		contact.OptInSource = optInKeywordSource
+		contact.OptInMethod = models.OptInMethodWhatsApp
+		a.Log.Info("Contact opted in", "contact_id", contact.ID, "org_id", orgID)
+	}
+	return contact.OptedOut
+}
+
+// keywordSetting returns the keyword list stored under key in the organization
+// settings, or the defaults when it was never set. An empty list disables it.
+func keywordSetting(settings models.JSONB, key string, defaults []string) []string {
+	switch v := settings[key].(type) {
+	case []any:
+		keywords := make([]string, 0, len(v))
+		for _, k := range v {
+			if s, ok := k.(string); ok {
+				keywords = append(keywords, s)
+			}
+		}
+		return keywords
+	case []string:
+		return v
+	}
+	return defaults
+}
+
+// normalizeKeywords trims keywords, dropping blanks and case-insensitive duplicates
+func normalizeKeywords(keywords []string) []string {
+	seen := make(map[string]bool, len(keywords))
+	result := make([]string, 0, len(keywords))
+	for _, k := range keywords {
+		k = strings.TrimSpace(k)
+		if k == "" || seen[strings.ToLower(k)] {
+			continue
+		}
+		seen[strings.ToLower(k)] = true
+		result = append(result, k)
+	}
+	return result
+}
+
+// matchesKeyword reports whether the whole message is one of the keywords,
+// ignoring case and surrounding whitespace
+func matchesKeyword(text string, keywords []string) bool {
+	text = strings.TrimSpace(text)
+	for _, k := range keywords {
+		if strings.EqualFold(text, strings.TrimSpace(k)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shridarpatil/whatomate/internal/handlers"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestApp_ListOptedOutContacts(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	otherOrg := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+	earlier := time.Now().Add(-time.Hour)
+	later := time.Now()
+	first := testutil.CreateTestContact(t, app.DB, org.ID)
+	second := testutil.CreateTestContact(t, app.DB, org.ID)
+	testutil.CreateTestContact(t, app.DB, org.ID) // still subscribed
+	other := testutil.CreateTestContact(t, app.DB, otherOrg.ID)
+	require.NoError(t, app.DB.Model(first).Updates(map[string]any{"opted_out": true, "opted_out_at": earlier}).Error)
+	require.NoError(t, app.DB.Model(second).Updates(map[string]any{"opted_out": true, "opted_out_at": later}).Error)
+	require.NoError(t, app.DB.Model(other).Updates(map[string]any{"opted_out": true, "opted_out_at": later}).Error)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.ListOptedOutContacts(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data struct {
+			Contacts []handlers.OptedOutContactResponse `json:"contacts"`
+			Total    int64                              `json:"total"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, int64(2), resp.Data.Total)
+	require.Len(t, resp.Data.Contacts, 2)
+	assert.Equal(t, second.ID, resp.Data.Contacts[0].ContactID, "most recent opt-out first")
+	assert.Equal(t, first.ID, resp.Data.Contacts[1].ContactID)
+}
+
+func TestApp_ListOptedOutContacts_RequiresPermission(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.ListOptedOutContacts(req))
+	assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+}
+
+func TestApp_UpdateOrganizationSettings_OptOutKeywords(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+	req := testutil.NewJSONRequest(t, map[string]any{
+		"opt_out_keywords": []string{" PARAR ", "parar", "", "BAJA"},
+		"opt_in_keywords":  []string{},
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	require.NoError(t, app.UpdateOrganizationSettings(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	getReq := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(getReq, org.ID, user.ID)
+	require.NoError(t, app.GetOrganizationSettings(getReq))
+
+	var resp struct {
+		Data struct {
+			Settings handlers.OrganizationSettings `json:"settings"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(getReq), &resp))
+	assert.Equal(t, []string{"PARAR", "BAJA"}, resp.Data.Settings.OptOutKeywords)
+	assert.Empty(t, resp.Data.Settings.OptInKeywords)
+}
+
+func TestApp_GetOrganizationSettings_DefaultOptOutKeywords(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	require.NoError(t, app.GetOrganizationSettings(req))
+
+	var resp struct {
+		Data struct {
+			Settings handlers.OrganizationSettings `json:"settings"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, []string{"STOP", "UNSUBSCRIBE"}, resp.Data.Settings.OptOutKeywords)
+	assert.Equal(t, []string{"START"}, resp.Data.Settings.OptInKeywords)
+}
+
+func TestApp_GetContact_ShowsOptOut(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(contact).Updates(map[string]any{"opted_out": true, "opted_out_at": time.Now()}).Error)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+
+	require.NoError(t, app.GetContact(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.ContactResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.True(t, resp.Data.OptedOut)
+	assert.NotNil(t, resp.Data.OptedOutAt)
+}
+
+func TestApp_RecordOptIn(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	optedInAt := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
+
+	req := testutil.NewJSONRequest(t, map[string]any{
+		"source":      "checkout page",
+		"method":      "web_form",
+		"opted_in_at": optedInAt,
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+
+	require.NoError(t, app.RecordOptIn(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.ContactOptInResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.True(t, resp.Data.HasValidOptIn)
+
+	var stored models.Contact
+	require.NoError(t, app.DB.First(&stored, contact.ID).Error)
+	require.NotNil(t, stored.OptInAt)
+	assert.True(t, optedInAt.Equal(*stored.OptInAt))
+	assert.Equal(t, "checkout page", stored.OptInSource)
+	assert.Equal(t, models.OptInMethodWebForm, stored.OptInMethod)
+	assert.True(t, stored.HasValidOptIn())
+}
+
+func TestApp_RecordOptIn_Validation(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	tests := []struct {
+		name string
+		body map[string]any
+	}{
+		{name: "missing source", body: map[string]any{"method": "web_form"}},
+		{name: "unknown method", body: map[string]any{"source": "form", "method": "carrier_pigeon"}},
+		{name: "future opt-in", body: map[string]any{"source": "form", "method": "web_form", "opted_in_at": time.Now().Add(time.Hour)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testutil.NewJSONRequest(t, tt.body)
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			testutil.SetPathParam(req, "id", contact.ID.String())
+
+			require.NoError(t, app.RecordOptIn(req))
+			assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+		})
+	}
+}
+
+func TestApp_RecordOptIn_OptedOutContact(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	optedOutAt := time.Now().Add(-time.Hour)
+	require.NoError(t, app.DB.Model(contact).Updates(map[string]any{"opted_out": true, "opted_out_at": optedOutAt}).Error)
+
+	// Consent older than the opt-out doesn't override it
+	req := testutil.NewJSONRequest(t, map[string]any{
+		"source":      "paper form",
+		"method":      "in_person",
+		"opted_in_at": optedOutAt.Add(-time.Hour),
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+	require.NoError(t, app.RecordOptIn(req))
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+	// New consent opts the contact back in
+	req = testutil.NewJSONRequest(t, map[string]any{"source": "support call", "method": "phone"})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+	require.NoError(t, app.RecordOptIn(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var stored models.Contact
+	require.NoError(t, app.DB.First(&stored, contact.ID).Error)
+	assert.False(t, stored.OptedOut)
+	assert.Nil(t, stored.OptedOutAt)
+	assert.True(t, stored.HasValidOptIn())
+}
+
+func TestApp_RecordOptIn_RequiresPermission(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	req := testutil.NewJSONRequest(t, map[string]any{"source": "form", "method": "web_form"})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+
+	require.NoError(t, app.RecordOptIn(req))
+	assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+}
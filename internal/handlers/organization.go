@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,14 +15,22 @@ import (
 
 // OrganizationSettings represents the settings structure
 type OrganizationSettings struct {
-	MaskPhoneNumbers    bool   `json:"mask_phone_numbers"`
-	Timezone            string `json:"timezone"`
-	DateFormat          string `json:"date_format"`
-	CallingEnabled      bool   `json:"calling_enabled"`
-	MaxCallDuration     int    `json:"max_call_duration"`
-	TransferTimeoutSecs int    `json:"transfer_timeout_secs"`
-	HoldMusicFile       string `json:"hold_music_file"`
-	RingbackFile        string `json:"ringback_file"`
+	MaskPhoneNumbers          bool     `json:"mask_phone_numbers"`
+	Timezone                  string   `json:"timezone"`
+	DateFormat                string   `json:"date_format"`
+	CallingEnabled            bool     `json:"calling_enabled"`
+	MaxCallDuration           int      `json:"max_call_duration"`
+	TransferTimeoutSecs       int      `json:"transfer_timeout_secs"`
+	HoldMusicFile             string   `json:"hold_music_file"`
+	RingbackFile              string   `json:"ringback_file"`
+	UniqueCannedShortcuts     bool     `json:"unique_canned_shortcuts"`      // Reject duplicate canned response shortcuts
+	HasFlowWebhookSecret      bool     `json:"has_flow_webhook_secret"`      // Flow completion webhooks are signed; the secret itself is never returned
+	CampaignMessagesPerSecond int      `json:"campaign_messages_per_second"` // Max campaign send rate across all workers
+	OptOutKeywords            []string `json:"opt_out_keywords"`             // Incoming messages that opt a contact out
+	OptInKeywords             []string `json:"opt_in_keywords"`              // Incoming messages that opt a contact back in
+	CampaignRequireOptIn      bool     `json:"campaign_require_opt_in"`      // Campaigns skip contacts without a valid opt-in
+	SendReadReceipts          bool     `json:"send_read_receipts"`           // Mark incoming messages read on WhatsApp when read in the inbox
+	DefaultWhatsAppAccount    string   `json:"default_whatsapp_account"`     // Account to send from when none is chosen (empty = automatic)
 }
 
 // GetOrganizationSettings returns the organization settings
@@ -37,14 +47,20 @@ func (a *App) GetOrganizationSettings(r *fastglue.Request) error {
 
 	// Parse settings from JSONB
 	settings := OrganizationSettings{
-		MaskPhoneNumbers:    false,
-		Timezone:            "UTC",
-		DateFormat:          "YYYY-MM-DD",
-		CallingEnabled:      false,
-		MaxCallDuration:     callingConfigDefault(a.Config.Calling.MaxCallDuration, 3600),
-		TransferTimeoutSecs: callingConfigDefault(a.Config.Calling.TransferTimeoutSecs, 60),
-		HoldMusicFile:       a.Config.Calling.HoldMusicFile,
-		RingbackFile:        a.Config.Calling.RingbackFile,
+		MaskPhoneNumbers:          false,
+		Timezone:                  "UTC",
+		DateFormat:                "YYYY-MM-DD",
+		CallingEnabled:            false,
+		MaxCallDuration:           callingConfigDefault(a.Config.Calling.MaxCallDuration, 3600),
+		TransferTimeoutSecs:       callingConfigDefault(a.Config.Calling.TransferTimeoutSecs, 60),
+		HoldMusicFile:             a.Config.Calling.HoldMusicFile,
+		RingbackFile:              a.Config.Calling.RingbackFile,
+		CampaignMessagesPerSecond: org.CampaignMessagesPerSecond(),
+		OptOutKeywords:            keywordSetting(org.Settings, "opt_out_keywords", defaultOptOutKeywords),
+		OptInKeywords:             keywordSetting(org.Settings, "opt_in_keywords", defaultOptInKeywords),
+		CampaignRequireOptIn:      org.CampaignRequiresOptIn(),
+		SendReadReceipts:          org.SendsReadReceipts(),
+		DefaultWhatsAppAccount:    org.DefaultWhatsAppAccount(),
 	}
 
 	if org.Settings != nil {
@@ -72,6 +88,12 @@ func (a *App) GetOrganizationSettings(r *fastglue.Request) error {
 		if v, ok := org.Settings["ringback_file"].(string); ok && v != "" {
 			settings.RingbackFile = v
 		}
+		if v, ok := org.Settings["unique_canned_shortcuts"].(bool); ok {
+			settings.UniqueCannedShortcuts = v
+		}
+		if v, ok := org.Settings["flow_webhook_secret"].(string); ok && v != "" {
+			settings.HasFlowWebhookSecret = true
+		}
 	}
 
 	return r.SendEnvelope(map[string]interface{}{
@@ -88,21 +110,47 @@ func (a *App) UpdateOrganizationSettings(r *fastglue.Request) error {
 	}
 
 	var req struct {
-		MaskPhoneNumbers    *bool   `json:"mask_phone_numbers"`
-		Timezone            *string `json:"timezone"`
-		DateFormat          *string `json:"date_format"`
-		Name                *string `json:"name"`
-		CallingEnabled      *bool   `json:"calling_enabled"`
-		MaxCallDuration     *int    `json:"max_call_duration"`
-		TransferTimeoutSecs *int    `json:"transfer_timeout_secs"`
-		HoldMusicFile       *string `json:"hold_music_file"`
-		RingbackFile        *string `json:"ringback_file"`
+		MaskPhoneNumbers          *bool     `json:"mask_phone_numbers"`
+		Timezone                  *string   `json:"timezone"`
+		DateFormat                *string   `json:"date_format"`
+		Name                      *string   `json:"name"`
+		CallingEnabled            *bool     `json:"calling_enabled"`
+		MaxCallDuration           *int      `json:"max_call_duration"`
+		TransferTimeoutSecs       *int      `json:"transfer_timeout_secs"`
+		HoldMusicFile             *string   `json:"hold_music_file"`
+		RingbackFile              *string   `json:"ringback_file"`
+		UniqueCannedShortcuts     *bool     `json:"unique_canned_shortcuts"`
+		FlowWebhookSecret         *string   `json:"flow_webhook_secret"`
+		CampaignMessagesPerSecond *int      `json:"campaign_messages_per_second"`
+		OptOutKeywords            *[]string `json:"opt_out_keywords"`
+		OptInKeywords             *[]string `json:"opt_in_keywords"`
+		CampaignRequireOptIn      *bool     `json:"campaign_require_opt_in"`
+		SendReadReceipts          *bool     `json:"send_read_receipts"`
+		DefaultWhatsAppAccount    *string   `json:"default_whatsapp_account"`
 	}
 
 	if err := json.Unmarshal(r.RequestCtx.PostBody(), &req); err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid request body", nil, "")
 	}
 
+	if req.CampaignMessagesPerSecond != nil &&
+		(*req.CampaignMessagesPerSecond < 1 || *req.CampaignMessagesPerSecond > models.MaxCampaignMessagesPerSecond) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			fmt.Sprintf("campaign_messages_per_second must be between 1 and %d", models.MaxCampaignMessagesPerSecond), nil, "")
+	}
+
+	if req.DefaultWhatsAppAccount != nil {
+		name := strings.TrimSpace(*req.DefaultWhatsAppAccount)
+		if name != "" {
+			var count int64
+			a.DB.Model(&models.WhatsAppAccount{}).Where("organization_id = ? AND name = ?", orgID, name).Count(&count)
+			if count == 0 {
+				return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "default_whatsapp_account must name one of the organization's WhatsApp accounts", nil, "")
+			}
+		}
+		req.DefaultWhatsAppAccount = &name
+	}
+
 	var org models.Organization
 	if err := a.DB.Where("id = ?", orgID).First(&org).Error; err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Organization not found", nil, "")
@@ -137,6 +185,33 @@ func (a *App) UpdateOrganizationSettings(r *fastglue.Request) error {
 	if req.RingbackFile != nil {
 		org.Settings["ringback_file"] = *req.RingbackFile
 	}
+	if req.UniqueCannedShortcuts != nil {
+		org.Settings["unique_canned_shortcuts"] = *req.UniqueCannedShortcuts
+	}
+	if req.FlowWebhookSecret != nil {
+		// An empty string clears the secret and disables signing
+		org.Settings["flow_webhook_secret"] = *req.FlowWebhookSecret
+	}
+	if req.CampaignMessagesPerSecond != nil {
+		org.Settings["campaign_messages_per_second"] = *req.CampaignMessagesPerSecond
+	}
+	if req.OptOutKeywords != nil {
+		// An empty list turns off keyword opt-out
+		org.Settings["opt_out_keywords"] = normalizeKeywords(*req.OptOutKeywords)
+	}
+	if req.OptInKeywords != nil {
+		org.Settings["opt_in_keywords"] = normalizeKeywords(*req.OptInKeywords)
+	}
+	if req.CampaignRequireOptIn != nil {
+		org.Settings["campaign_require_opt_in"] = *req.CampaignRequireOptIn
+	}
+	if req.SendReadReceipts != nil {
+		org.Settings["send_read_receipts"] = *req.SendReadReceipts
+	}
+	if req.DefaultWhatsAppAccount != nil {
+		// An empty name goes back to picking the account automatically
+		org.Settings["default_whatsapp_account"] = *req.DefaultWhatsAppAccount
+	}
 	if req.Name != nil && *req.Name != "" {
 		org.Name = *req.Name
 	}
@@ -168,6 +243,16 @@ func (a *App) IsCallingEnabledForOrg(orgID interface{}) bool {
 	return false
 }
 
+// isUniqueCannedShortcutsEnabled reports whether the organization requires unique canned response shortcuts
+func (a *App) isUniqueCannedShortcutsEnabled(orgID uuid.UUID) bool {
+	var org models.Organization
+	if err := a.DB.Where("id = ?", orgID).First(&org).Error; err != nil {
+		return false
+	}
+	v, _ := org.Settings["unique_canned_shortcuts"].(bool)
+	return v
+}
+
 // GetOrgCallingConfig returns org-level calling config values, falling back to global defaults.
 func (a *App) GetOrgCallingConfig(orgID interface{}) (maxDuration, transferTimeout int) {
 	maxDuration = callingConfigDefault(a.Config.Calling.MaxCallDuration, 3600)
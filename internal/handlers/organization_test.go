@@ -234,6 +234,50 @@ func TestApp_UpdateOrganizationSettings_InvalidJSON(t *testing.T) {
 	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 }
 
+func TestApp_UpdateOrganizationSettings_CampaignMessagesPerSecond(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("campaign-rate")))
+
+	req := testutil.NewJSONRequest(t, map[string]any{"campaign_messages_per_second": 5})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	require.NoError(t, app.UpdateOrganizationSettings(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	getReq := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(getReq, org.ID, user.ID)
+	require.NoError(t, app.GetOrganizationSettings(getReq))
+
+	var resp struct {
+		Data struct {
+			Settings handlers.OrganizationSettings `json:"settings"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(getReq), &resp))
+	assert.Equal(t, 5, resp.Data.Settings.CampaignMessagesPerSecond)
+}
+
+func TestApp_UpdateOrganizationSettings_CampaignMessagesPerSecondOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("campaign-rate-range")))
+
+	for _, rate := range []int{0, -1, models.MaxCampaignMessagesPerSecond + 1} {
+		req := testutil.NewJSONRequest(t, map[string]any{"campaign_messages_per_second": rate})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateOrganizationSettings(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req), "rate %d", rate)
+	}
+
+	var updatedOrg models.Organization
+	require.NoError(t, app.DB.Where("id = ?", org.ID).First(&updatedOrg).Error)
+	assert.NotContains(t, updatedOrg.Settings, "campaign_messages_per_second")
+}
+
 // --- GetCurrentOrganization Tests ---
 
 func TestApp_GetCurrentOrganization_Success(t *testing.T) {
@@ -636,3 +680,32 @@ func TestApp_GetOrgCallingConfig_NonExistentOrg(t *testing.T) {
 	assert.Equal(t, 3600, maxDuration)
 	assert.Equal(t, 60, transferTimeout)
 }
+
+func TestApp_UpdateOrganizationSettings_DefaultWhatsAppAccount(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+	otherOrgAccount := testutil.CreateTestWhatsAppAccount(t, app.DB, testutil.CreateTestOrganization(t, app.DB).ID)
+
+	update := func(name string) int {
+		req := testutil.NewJSONRequest(t, map[string]any{"default_whatsapp_account": name})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.UpdateOrganizationSettings(req))
+		return testutil.GetResponseStatusCode(req)
+	}
+
+	assert.Equal(t, fasthttp.StatusOK, update(" "+account.Name+" "))
+	var updated models.Organization
+	require.NoError(t, app.DB.Where("id = ?", org.ID).First(&updated).Error)
+	assert.Equal(t, account.Name, updated.DefaultWhatsAppAccount())
+
+	assert.Equal(t, fasthttp.StatusBadRequest, update(otherOrgAccount.Name), "accounts of other organizations are rejected")
+	assert.Equal(t, fasthttp.StatusBadRequest, update("no-such-account"))
+
+	assert.Equal(t, fasthttp.StatusOK, update(""))
+	require.NoError(t, app.DB.Where("id = ?", org.ID).First(&updated).Error)
+	assert.Empty(t, updated.DefaultWhatsAppAccount())
+}
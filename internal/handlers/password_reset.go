@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordResetTokenTTL is how long a password reset token stays valid
+const passwordResetTokenTTL = time.Hour
+
+// passwordResetRequestedMessage is returned whether or not the account exists
+const passwordResetRequestedMessage = "If the account exists, a password reset has been requested"
+
+// PasswordResetRequest represents a request to reset a forgotten password
+type PasswordResetRequest struct {
+	Email          string    `json:"email"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+}
+
+// ResetPasswordRequest consumes a reset token and sets a new password
+type ResetPasswordRequest struct {
+	Token          string    `json:"token"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	NewPassword    string    `json:"new_password"`
+}
+
+// PasswordResetEventData represents data for password reset webhook events.
+// The reset token is never included; it only goes to the configured delivery URL.
+type PasswordResetEventData struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	FullName  string    `json:"full_name"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// passwordResetDeliveryData is posted to the server's password reset delivery URL
+type passwordResetDeliveryData struct {
+	PasswordResetEventData
+	OrganizationID string `json:"organization_id"`
+	Token          string `json:"token"`
+}
+
+// RequestPasswordReset issues a time-limited reset token for a user whose home
+// organization is the one given. The token is posted to the server's configured
+// password reset delivery URL, never to organization webhooks. The response is
+// the same whether or not the email exists.
+func (a *App) RequestPasswordReset(r *fastglue.Request) error {
+	var req PasswordResetRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	if a.Config.PasswordReset.DeliveryURL == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusServiceUnavailable, "Password reset is not configured", nil, "")
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if email == "" || req.OrganizationID == uuid.Nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "email and organization_id are required", nil, "")
+	}
+
+	// The password is shared across every organization the user belongs to, so
+	// only the home organization may reset it.
+	var user models.User
+	if err := a.DB.
+		Where("email = ? AND organization_id = ? AND is_active = ?", email, req.OrganizationID, true).
+		First(&user).Error; err != nil {
+		return r.SendEnvelope(map[string]string{"message": passwordResetRequestedMessage})
+	}
+
+	token, err := generatePasswordResetToken()
+	if err != nil {
+		a.Log.Error("Failed to generate password reset token", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to request password reset", nil, "")
+	}
+
+	// Only the latest token for this user and organization stays valid
+	a.DB.Where("user_id = ? AND organization_id = ? AND used_at IS NULL", user.ID, req.OrganizationID).
+		Delete(&models.PasswordResetToken{})
+
+	resetToken := models.PasswordResetToken{
+		OrganizationID: req.OrganizationID,
+		UserID:         user.ID,
+		TokenHash:      hashPasswordResetToken(token),
+		ExpiresAt:      time.Now().Add(passwordResetTokenTTL),
+	}
+	if err := a.DB.Create(&resetToken).Error; err != nil {
+		a.Log.Error("Failed to save password reset token", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to request password reset", nil, "")
+	}
+
+	a.Log.Info("Password reset requested", "user_id", user.ID, "org_id", req.OrganizationID)
+	event := PasswordResetEventData{
+		UserID:    user.ID.String(),
+		Email:     user.Email,
+		FullName:  user.FullName,
+		ExpiresAt: resetToken.ExpiresAt,
+	}
+	a.deliverPasswordResetToken(passwordResetDeliveryData{
+		PasswordResetEventData: event,
+		OrganizationID:         req.OrganizationID.String(),
+		Token:                  token,
+	})
+	a.DispatchWebhook(req.OrganizationID, models.WebhookEventPasswordReset, event)
+
+	return r.SendEnvelope(map[string]string{"message": passwordResetRequestedMessage})
+}
+
+// ResetPassword consumes a password reset token and sets a new password.
+// Tokens are single-use and only valid in the organization they were issued for.
+func (a *App) ResetPassword(r *fastglue.Request) error {
+	var req ResetPasswordRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	if req.Token == "" || req.OrganizationID == uuid.Nil || req.NewPassword == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "token, organization_id and new_password are required", nil, "")
+	}
+	if len(req.NewPassword) < 6 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "New password must be at least 6 characters", nil, "")
+	}
+
+	var resetToken models.PasswordResetToken
+	if err := a.DB.Where("token_hash = ? AND organization_id = ? AND used_at IS NULL AND expires_at > ?",
+		hashPasswordResetToken(req.Token), req.OrganizationID, time.Now()).
+		First(&resetToken).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid or expired reset token", nil, "")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		a.Log.Error("Failed to hash password", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to reset password", nil, "")
+	}
+
+	// Mark the token used first so concurrent requests can't both consume it
+	result := a.DB.Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", resetToken.ID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		a.Log.Error("Failed to consume password reset token", "error", result.Error)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to reset password", nil, "")
+	}
+	if result.RowsAffected == 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid or expired reset token", nil, "")
+	}
+
+	if err := a.DB.Model(&models.User{}).Where("id = ?", resetToken.UserID).
+		Update("password_hash", string(hashedPassword)).Error; err != nil {
+		a.Log.Error("Failed to reset password", "error", err, "user_id", resetToken.UserID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to reset password", nil, "")
+	}
+
+	// Sign out everywhere so a stolen session doesn't outlive the reset
+	a.revokeUserSessions(resetToken.UserID)
+
+	a.Log.Info("Password reset", "user_id", resetToken.UserID, "org_id", resetToken.OrganizationID)
+	return r.SendEnvelope(map[string]string{"message": "Password reset successfully"})
+}
+
+// deliverPasswordResetToken posts the reset token to the configured delivery URL
+// in the background. The request is signed like outbound webhooks but nothing is
+// recorded in webhook deliveries, so the token is never stored.
+func (a *App) deliverPasswordResetToken(data passwordResetDeliveryData) {
+	cfg := a.Config.PasswordReset
+	payload := OutboundWebhookPayload{
+		ID:        uuid.New().String(),
+		Event:     string(models.WebhookEventPasswordReset),
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		a.Log.Error("Failed to marshal password reset delivery", "error", err)
+		return
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		target := models.Webhook{URL: cfg.DeliveryURL, Secret: cfg.DeliverySecret}
+		if err := a.sendWebhookRequest(ctx, target, payload.ID, jsonData); err != nil {
+			a.Log.Error("Failed to deliver password reset token", "error", err, "user_id", data.UserID)
+		}
+	}()
+}
+
+// generatePasswordResetToken returns a random hex token
+func generatePasswordResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashPasswordResetToken returns the hex SHA-256 of a reset token for storage and lookup
+func hashPasswordResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,260 @@
+package handlers_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// storeResetToken saves a reset token directly and returns the raw token
+func storeResetToken(t *testing.T, db *gorm.DB, orgID, userID uuid.UUID, expiresAt time.Time) string {
+	t.Helper()
+	token := uuid.New().String()
+	sum := sha256.Sum256([]byte(token))
+	require.NoError(t, db.Create(&models.PasswordResetToken{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: orgID,
+		UserID:         userID,
+		TokenHash:      hex.EncodeToString(sum[:]),
+		ExpiresAt:      expiresAt,
+	}).Error)
+	return token
+}
+
+func TestApp_PasswordReset(t *testing.T) {
+	t.Parallel()
+
+	// deliveryServer records the payloads posted to the password reset delivery URL
+	type deliveredReset struct {
+		Event string `json:"event"`
+		Data  struct {
+			UserID         string `json:"user_id"`
+			OrganizationID string `json:"organization_id"`
+			Token          string `json:"token"`
+		} `json:"data"`
+	}
+	deliveryServer := func(t *testing.T) (*httptest.Server, func() []deliveredReset) {
+		var (
+			mu       sync.Mutex
+			payloads []deliveredReset
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			var p deliveredReset
+			_ = json.Unmarshal(body, &p)
+			mu.Lock()
+			payloads = append(payloads, p)
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+		return server, func() []deliveredReset {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]deliveredReset(nil), payloads...)
+		}
+	}
+
+	t.Run("request and reset via delivered token", func(t *testing.T) {
+		t.Parallel()
+		server, delivered := deliveryServer(t)
+		app := newTestApp(t, withPasswordResetDelivery(server.URL))
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("reset")))
+
+		clearWebhookCache(t, app.Redis, org.ID)
+		t.Cleanup(func() { clearWebhookCache(t, app.Redis, org.ID) })
+
+		// The organization's webhook is notified but never sees the token
+		var (
+			mu         sync.Mutex
+			webhookRaw []byte
+		)
+		orgWebhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			webhookRaw = body
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer orgWebhook.Close()
+
+		require.NoError(t, app.DB.Create(&models.Webhook{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			OrganizationID: org.ID,
+			Name:           "reset-webhook",
+			URL:            orgWebhook.URL,
+			Events:         models.StringArray{string(models.WebhookEventPasswordReset)},
+			IsActive:       true,
+		}).Error)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"email":           user.Email,
+			"organization_id": org.ID.String(),
+		})
+		require.NoError(t, app.RequestPasswordReset(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		app.WaitForBackgroundTasks()
+
+		payloads := delivered()
+		require.Len(t, payloads, 1)
+		token := payloads[0].Data.Token
+		assert.Equal(t, string(models.WebhookEventPasswordReset), payloads[0].Event)
+		assert.Equal(t, user.ID.String(), payloads[0].Data.UserID)
+		assert.Equal(t, org.ID.String(), payloads[0].Data.OrganizationID)
+		require.NotEmpty(t, token)
+
+		mu.Lock()
+		assert.Contains(t, string(webhookRaw), user.ID.String())
+		assert.NotContains(t, string(webhookRaw), token)
+		mu.Unlock()
+
+		// Only the hash is stored
+		var stored models.PasswordResetToken
+		require.NoError(t, app.DB.Where("user_id = ?", user.ID).First(&stored).Error)
+		assert.NotEqual(t, token, stored.TokenHash)
+
+		resetReq := testutil.NewJSONRequest(t, map[string]any{
+			"token":           token,
+			"organization_id": org.ID.String(),
+			"new_password":    "a-brand-new-password",
+		})
+		require.NoError(t, app.ResetPassword(resetReq))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(resetReq))
+
+		var updated models.User
+		require.NoError(t, app.DB.Where("id = ?", user.ID).First(&updated).Error)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(updated.PasswordHash), []byte("a-brand-new-password")))
+
+		// Tokens are single-use
+		reuseReq := testutil.NewJSONRequest(t, map[string]any{
+			"token":           token,
+			"organization_id": org.ID.String(),
+			"new_password":    "another-new-password",
+		})
+		require.NoError(t, app.ResetPassword(reuseReq))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(reuseReq))
+	})
+
+	t.Run("unknown email gets the same response", func(t *testing.T) {
+		t.Parallel()
+		server, delivered := deliveryServer(t)
+		app := newTestApp(t, withPasswordResetDelivery(server.URL))
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("reset-known")))
+
+		known := testutil.NewJSONRequest(t, map[string]any{"email": user.Email, "organization_id": org.ID.String()})
+		require.NoError(t, app.RequestPasswordReset(known))
+		unknown := testutil.NewJSONRequest(t, map[string]any{"email": testutil.UniqueEmail("reset-unknown"), "organization_id": org.ID.String()})
+		require.NoError(t, app.RequestPasswordReset(unknown))
+		app.WaitForBackgroundTasks()
+
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(unknown))
+		assert.Equal(t, testutil.GetResponseStatusCode(known), testutil.GetResponseStatusCode(unknown))
+		assert.JSONEq(t, string(testutil.GetResponseBody(known)), string(testutil.GetResponseBody(unknown)))
+		assert.Len(t, delivered(), 1)
+	})
+
+	t.Run("no token for members of another home organization", func(t *testing.T) {
+		t.Parallel()
+		server, delivered := deliveryServer(t)
+		app := newTestApp(t, withPasswordResetDelivery(server.URL))
+		homeOrg := testutil.CreateTestOrganization(t, app.DB)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, homeOrg.ID)
+		require.NoError(t, app.DB.Create(&models.UserOrganization{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			UserID:         user.ID,
+			OrganizationID: org.ID,
+		}).Error)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"email": user.Email, "organization_id": org.ID.String()})
+		require.NoError(t, app.RequestPasswordReset(req))
+		app.WaitForBackgroundTasks()
+
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		assert.Empty(t, delivered())
+		var count int64
+		require.NoError(t, app.DB.Model(&models.PasswordResetToken{}).Where("user_id = ?", user.ID).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("disabled without a delivery URL", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"email": user.Email, "organization_id": org.ID.String()})
+		require.NoError(t, app.RequestPasswordReset(req))
+		assert.Equal(t, fasthttp.StatusServiceUnavailable, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("token is scoped to its organization", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		token := storeResetToken(t, app.DB, org.ID, user.ID, time.Now().Add(time.Hour))
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"token":           token,
+			"organization_id": otherOrg.ID.String(),
+			"new_password":    "a-brand-new-password",
+		})
+		require.NoError(t, app.ResetPassword(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		token := storeResetToken(t, app.DB, org.ID, user.ID, time.Now().Add(-time.Minute))
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"token":           token,
+			"organization_id": org.ID.String(),
+			"new_password":    "a-brand-new-password",
+		})
+		require.NoError(t, app.ResetPassword(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+		var unchanged models.User
+		require.NoError(t, app.DB.Where("id = ?", user.ID).First(&unchanged).Error)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(unchanged.PasswordHash), []byte("password123")))
+	})
+
+	t.Run("short password is rejected", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+		token := storeResetToken(t, app.DB, org.ID, user.ID, time.Now().Add(time.Hour))
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"token":           token,
+			"organization_id": org.ID.String(),
+			"new_password":    "short",
+		})
+		require.NoError(t, app.ResetPassword(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+}
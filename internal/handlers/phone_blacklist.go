@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/contactutil"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// PhoneBlacklistRequest represents the request body for blacklisting a phone number
+type PhoneBlacklistRequest struct {
+	PhoneNumber string `json:"phone_number"`
+	Reason      string `json:"reason"`
+}
+
+// PhoneBlacklistResponse represents the API response for a blacklisted phone number
+type PhoneBlacklistResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	PhoneNumber string     `json:"phone_number"`
+	Reason      string     `json:"reason"`
+	CreatedByID *uuid.UUID `json:"created_by_id,omitempty"`
+	CreatedAt   string     `json:"created_at"`
+}
+
+// ListPhoneBlacklist returns the organization's blacklisted phone numbers
+func (a *App) ListPhoneBlacklist(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceContacts, models.ActionRead); err != nil {
+		return nil
+	}
+
+	pg := parsePagination(r)
+	search := string(r.RequestCtx.QueryArgs().Peek("search"))
+
+	query := a.DB.Where("organization_id = ?", orgID)
+	if search != "" {
+		searchPattern := "%" + search + "%"
+		query = query.Where("phone_number ILIKE ? OR reason ILIKE ?", searchPattern, searchPattern)
+	}
+
+	var total int64
+	query.Model(&models.PhoneBlacklist{}).Count(&total)
+
+	var entries []models.PhoneBlacklist
+	if err := pg.Apply(query.Order("created_at DESC")).Find(&entries).Error; err != nil {
+		a.Log.Error("Failed to list phone blacklist", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list phone blacklist", nil, "")
+	}
+
+	result := make([]PhoneBlacklistResponse, len(entries))
+	for i, entry := range entries {
+		result[i] = phoneBlacklistToResponse(entry)
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"entries": result,
+		"total":   total,
+		"page":    pg.Page,
+		"limit":   pg.Limit,
+	})
+}
+
+// CreatePhoneBlacklist blacklists a phone number for the organization
+func (a *App) CreatePhoneBlacklist(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceContacts, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	var req PhoneBlacklistRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	phone := contactutil.NormalizePhone(req.PhoneNumber)
+	if phone == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "phone_number is required", nil, "")
+	}
+
+	if a.isPhoneBlacklisted(orgID, phone) {
+		return r.SendErrorEnvelope(fasthttp.StatusConflict, "Phone number is already blacklisted", nil, "")
+	}
+
+	entry := models.PhoneBlacklist{
+		OrganizationID: orgID,
+		PhoneNumber:    phone,
+		Reason:         req.Reason,
+		CreatedByID:    &userID,
+	}
+
+	if err := a.DB.Create(&entry).Error; err != nil {
+		a.Log.Error("Failed to blacklist phone number", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to blacklist phone number", nil, "")
+	}
+
+	return r.SendEnvelope(phoneBlacklistToResponse(entry))
+}
+
+// DeletePhoneBlacklist removes a phone number from the organization's blacklist
+func (a *App) DeletePhoneBlacklist(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceContacts, models.ActionDelete); err != nil {
+		return nil
+	}
+
+	id, err := parsePathUUID(r, "id", "blacklist entry")
+	if err != nil {
+		return nil
+	}
+
+	entry, err := findByIDAndOrg[models.PhoneBlacklist](a.DB, r, id, orgID, "Blacklist entry")
+	if err != nil {
+		return nil
+	}
+
+	// Hard delete so the same number can be blacklisted again later
+	if err := a.DB.Unscoped().Delete(entry).Error; err != nil {
+		a.Log.Error("Failed to delete blacklist entry", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete blacklist entry", nil, "")
+	}
+
+	return r.SendEnvelope(map[string]string{"message": "Blacklist entry deleted"})
+}
+
+// isPhoneBlacklisted reports whether the organization has blacklisted the phone number
+func (a *App) isPhoneBlacklisted(orgID uuid.UUID, phone string) bool {
+	blacklisted, err := contactutil.IsPhoneBlacklisted(a.DB, orgID, phone)
+	if err != nil {
+		a.Log.Error("Failed to check phone blacklist", "error", err, "org_id", orgID)
+	}
+	return blacklisted
+}
+
+func phoneBlacklistToResponse(entry models.PhoneBlacklist) PhoneBlacklistResponse {
+	return PhoneBlacklistResponse{
+		ID:          entry.ID,
+		PhoneNumber: entry.PhoneNumber,
+		Reason:      entry.Reason,
+		CreatedByID: entry.CreatedByID,
+		CreatedAt:   entry.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,224 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/shridarpatil/whatomate/internal/handlers"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestApp_CreatePhoneBlacklist(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success normalizes phone number", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"phone_number": " +15551234567 ",
+			"reason":       "abusive",
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.CreatePhoneBlacklist(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.PhoneBlacklistResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Equal(t, "15551234567", resp.Data.PhoneNumber)
+		assert.Equal(t, "abusive", resp.Data.Reason)
+		require.NotNil(t, resp.Data.CreatedByID)
+		assert.Equal(t, user.ID, *resp.Data.CreatedByID)
+	})
+
+	t.Run("duplicate number conflicts", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		require.NoError(t, app.DB.Create(&models.PhoneBlacklist{OrganizationID: org.ID, PhoneNumber: "15550000001"}).Error)
+
+		req := testutil.NewJSONRequest(t, map[string]any{"phone_number": "+15550000001"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.CreatePhoneBlacklist(req))
+		assert.Equal(t, fasthttp.StatusConflict, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("missing phone number", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+		req := testutil.NewJSONRequest(t, map[string]any{"phone_number": "  "})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.CreatePhoneBlacklist(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("forbidden without contacts write", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+
+		req := testutil.NewJSONRequest(t, map[string]any{"phone_number": "15550000002"})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.CreatePhoneBlacklist(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_ListPhoneBlacklist(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	otherOrg := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+
+	require.NoError(t, app.DB.Create(&models.PhoneBlacklist{OrganizationID: org.ID, PhoneNumber: "15550000010"}).Error)
+	require.NoError(t, app.DB.Create(&models.PhoneBlacklist{OrganizationID: org.ID, PhoneNumber: "15550000011"}).Error)
+	require.NoError(t, app.DB.Create(&models.PhoneBlacklist{OrganizationID: otherOrg.ID, PhoneNumber: "15550000012"}).Error)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.ListPhoneBlacklist(req))
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data struct {
+			Entries []handlers.PhoneBlacklistResponse `json:"entries"`
+			Total   int64                             `json:"total"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, int64(2), resp.Data.Total)
+	assert.Len(t, resp.Data.Entries, 2)
+}
+
+func TestApp_DeletePhoneBlacklist(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success allows re-adding", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		entry := &models.PhoneBlacklist{OrganizationID: org.ID, PhoneNumber: "15550000020"}
+		require.NoError(t, app.DB.Create(entry).Error)
+
+		req := testutil.NewJSONRequest(t, nil)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", entry.ID.String())
+
+		require.NoError(t, app.DeletePhoneBlacklist(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		createReq := testutil.NewJSONRequest(t, map[string]any{"phone_number": "15550000020"})
+		testutil.SetAuthContext(createReq, org.ID, user.ID)
+		require.NoError(t, app.CreatePhoneBlacklist(createReq))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(createReq))
+	})
+
+	t.Run("not found in other org", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		entry := &models.PhoneBlacklist{OrganizationID: otherOrg.ID, PhoneNumber: "15550000021"}
+		require.NoError(t, app.DB.Create(entry).Error)
+
+		req := testutil.NewJSONRequest(t, nil)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", entry.ID.String())
+
+		require.NoError(t, app.DeletePhoneBlacklist(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_SendMessage_BlacklistedNumber(t *testing.T) {
+	t.Parallel()
+
+	mockServer := newMockWhatsAppServer()
+	defer mockServer.close()
+
+	app := newMsgTestApp(t, mockServer)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := createTestAccount(t, app, org.ID)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+	require.NoError(t, app.DB.Create(&models.PhoneBlacklist{
+		OrganizationID: org.ID,
+		PhoneNumber:    strings.TrimPrefix(contact.PhoneNumber, "+"),
+	}).Error)
+
+	req := testutil.NewJSONRequest(t, map[string]any{
+		"type":    "text",
+		"content": map[string]string{"body": "Hello"},
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", contact.ID.String())
+
+	require.NoError(t, app.SendMessage(req))
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+	var sent int64
+	require.NoError(t, app.DB.Model(&models.Message{}).Where("contact_id = ?", contact.ID).Count(&sent).Error)
+	assert.Zero(t, sent)
+}
+
+func TestApp_SendTemplateMessage_BlacklistedNumber(t *testing.T) {
+	t.Parallel()
+
+	mockServer := newMockWhatsAppServer()
+	defer mockServer.close()
+
+	app := newMsgTestApp(t, mockServer)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	account := createTestAccount(t, app, org.ID)
+	tpl := createTestTemplate(t, app, org.ID, account.Name)
+	// Stored with formatting, as entries created before normalization were
+	require.NoError(t, app.DB.Create(&models.PhoneBlacklist{
+		OrganizationID: org.ID,
+		PhoneNumber:    "+1 202-555-0101",
+	}).Error)
+
+	req := testutil.NewJSONRequest(t, map[string]any{
+		"phone_number":    "12025550101",
+		"template_name":   tpl.Name,
+		"template_params": map[string]string{"name": "Alice", "order_id": "ORD-1"},
+	})
+	testutil.SetAuthContext(req, org.ID, user.ID)
+
+	require.NoError(t, app.SendTemplateMessage(req))
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	app.WaitForBackgroundTasks()
+	assert.Empty(t, mockServer.sentMessages)
+}
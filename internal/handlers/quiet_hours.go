@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/internal/queue"
+)
+
+// deferredRecipientBatchSize caps how many quiet-hours recipients are re-queued per run
+const deferredRecipientBatchSize = 1000
+
+// quietHoursEnd reports whether automated messages for the account are held
+// back by quiet hours at now and, if so, when the window closes.
+// Organizations without chatbot settings have no quiet hours.
+func (a *App) quietHoursEnd(orgID uuid.UUID, accountName string, now time.Time) (time.Time, bool) {
+	settings, err := a.getChatbotSettingsCached(orgID, accountName)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return settings.QuietHours.EndsAt(now)
+}
+
+// requeueDeferredRecipients re-queues campaign recipients the worker held back
+// during quiet hours once their window has closed, and returns how many were
+// queued. Recipients are claimed by clearing deferred_until, so concurrent
+// runs queue each one only once.
+func (a *App) requeueDeferredRecipients(now time.Time) int {
+	var recipients []models.BulkMessageRecipient
+	if err := a.DB.Preload("Campaign").
+		Where("status = ? AND deferred_until IS NOT NULL AND deferred_until <= ?", models.MessageStatusPending, now).
+		Order("deferred_until ASC").
+		Limit(deferredRecipientBatchSize).
+		Find(&recipients).Error; err != nil {
+		a.Log.Error("Failed to load deferred campaign recipients", "error", err)
+		return 0
+	}
+
+	var jobs []*queue.RecipientJob
+	for _, recipient := range recipients {
+		claim := a.DB.Model(&models.BulkMessageRecipient{}).
+			Where("id = ? AND deferred_until IS NOT NULL", recipient.ID).
+			Update("deferred_until", nil)
+		if claim.Error != nil {
+			a.Log.Error("Failed to claim deferred recipient", "error", claim.Error, "recipient_id", recipient.ID)
+			continue
+		}
+		if claim.RowsAffected == 0 || recipient.Campaign == nil {
+			continue
+		}
+		jobs = append(jobs, &queue.RecipientJob{
+			CampaignID:     recipient.CampaignID,
+			RecipientID:    recipient.ID,
+			OrganizationID: recipient.Campaign.OrganizationID,
+			PhoneNumber:    recipient.PhoneNumber,
+			RecipientName:  recipient.RecipientName,
+			TemplateParams: recipient.TemplateParams,
+		})
+	}
+	if len(jobs) == 0 {
+		return 0
+	}
+
+	if err := a.Queue.EnqueueRecipients(context.Background(), jobs); err != nil {
+		a.Log.Error("Failed to re-queue deferred campaign recipients", "error", err)
+		// Put them back so the next run retries
+		ids := make([]uuid.UUID, len(jobs))
+		for i, job := range jobs {
+			ids[i] = job.RecipientID
+		}
+		a.DB.Model(&models.BulkMessageRecipient{}).Where("id IN ?", ids).Update("deferred_until", now)
+		return 0
+	}
+
+	a.Log.Info("Re-queued campaign recipients after quiet hours", "count", len(jobs))
+	return len(jobs)
+}
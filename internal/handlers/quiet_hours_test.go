@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createQuietHoursSettings creates org-level chatbot settings whose quiet
+// window, in UTC, runs from an hour before now to an hour after
+func createQuietHoursSettings(t *testing.T, app *App, orgID uuid.UUID, now time.Time) {
+	t.Helper()
+	settings := &models.ChatbotSettings{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: orgID,
+		QuietHours: models.QuietHoursConfig{
+			Enabled:  true,
+			Start:    now.UTC().Add(-time.Hour).Format("15:04"),
+			End:      now.UTC().Add(time.Hour).Format("15:04"),
+			Timezone: "UTC",
+		},
+	}
+	require.NoError(t, app.DB.Create(settings).Error)
+}
+
+func TestDispatchScheduledMessages_DefersDuringQuietHours(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+	now := time.Now()
+	createQuietHoursSettings(t, app, org.ID, now)
+	scheduled := createTestScheduledMessage(t, app, org.ID, contact.ID, user.ID, "Good morning", now.Add(-time.Minute))
+
+	assert.Equal(t, 0, app.dispatchScheduledMessages(now))
+
+	var deferred models.ScheduledMessage
+	require.NoError(t, app.DB.Where("id = ?", scheduled.ID).First(&deferred).Error)
+	assert.Equal(t, models.ScheduledMessageStatusPending, deferred.Status)
+	assert.True(t, deferred.SendAt.After(now), "send_at moves to the end of the window")
+	assert.WithinDuration(t, now.Add(time.Hour), deferred.SendAt, time.Minute)
+
+	var count int64
+	require.NoError(t, app.DB.Model(&models.Message{}).Where("contact_id = ?", contact.ID).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestRequeueDeferredRecipients(t *testing.T) {
+	app := newProcessorTestApp(t)
+	mockQueue := testutil.NewMockQueue()
+	app.Queue = mockQueue
+	org, account := createProcessorTestOrg(t, app)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	template := testutil.CreateTestTemplate(t, app.DB, org.ID, account.Name)
+
+	campaign := &models.BulkMessageCampaign{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		Name:            "Quiet hours campaign",
+		WhatsAppAccount: account.Name,
+		TemplateID:      template.ID,
+		Status:          models.CampaignStatusProcessing,
+		CreatedBy:       user.ID,
+	}
+	require.NoError(t, app.DB.Create(campaign).Error)
+
+	now := time.Now()
+	createRecipient := func(phone string, deferredUntil *time.Time) *models.BulkMessageRecipient {
+		recipient := &models.BulkMessageRecipient{
+			BaseModel:     models.BaseModel{ID: uuid.New()},
+			CampaignID:    campaign.ID,
+			PhoneNumber:   phone,
+			Status:        models.MessageStatusPending,
+			DeferredUntil: deferredUntil,
+		}
+		require.NoError(t, app.DB.Create(recipient).Error)
+		return recipient
+	}
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+	due := createRecipient("15550000001", &past)
+	createRecipient("15550000002", &future)
+	createRecipient("15550000003", nil)
+
+	assert.Equal(t, 1, app.requeueDeferredRecipients(now))
+	require.Len(t, mockQueue.Jobs, 1)
+	assert.Equal(t, due.ID, mockQueue.Jobs[0].RecipientID)
+	assert.Equal(t, org.ID, mockQueue.Jobs[0].OrganizationID)
+
+	var requeued models.BulkMessageRecipient
+	require.NoError(t, app.DB.Where("id = ?", due.ID).First(&requeued).Error)
+	assert.Nil(t, requeued.DeferredUntil)
+
+	// A second run must not queue it again
+	assert.Equal(t, 0, app.requeueDeferredRecipients(now))
+	assert.Len(t, mockQueue.Jobs, 1)
+}
+
+func TestProcessInactiveClients_DefersCloseDuringQuietHours(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+
+	now := time.Now()
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		ClientInactivity: models.ClientInactivityConfig{
+			ReminderEnabled:  true,
+			AutoCloseMinutes: 60,
+			AutoCloseMessage: "Closing this chat due to inactivity.",
+		},
+		QuietHours: models.QuietHoursConfig{
+			Enabled:  true,
+			Start:    now.UTC().Add(-time.Hour).Format("15:04"),
+			End:      now.UTC().Add(time.Hour).Format("15:04"),
+			Timezone: "UTC",
+		},
+	}).Error)
+
+	waiting := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(waiting).Update("chatbot_last_message_at", now.Add(-90*time.Minute)).Error)
+	waitingSession := createWarningTestSession(t, app, org.ID, waiting, account.Name, now.Add(-90*time.Minute))
+	idle := testutil.CreateTestContact(t, app.DB, org.ID)
+	idleSession := createWarningTestSession(t, app, org.ID, idle, account.Name, now.Add(-90*time.Minute))
+
+	proc := NewSLAProcessor(app, time.Minute)
+	result := proc.processInactiveClients(now, nil)
+	assert.Zero(t, result.SessionsClosed)
+	assert.Zero(t, result.MessagesSent)
+	for _, id := range []uuid.UUID{waitingSession.ID, idleSession.ID} {
+		var session models.ChatbotSession
+		require.NoError(t, app.DB.Where("id = ?", id).First(&session).Error)
+		assert.Equal(t, models.SessionStatusActive, session.Status, "closing waits for quiet hours to end")
+	}
+	assert.Zero(t, countAutoCloseMessages(t, app, waiting.ID))
+	assert.Zero(t, countAutoCloseMessages(t, app, idle.ID))
+
+	// Once the window has closed the conversations are closed and messaged
+	result = proc.processInactiveClients(now.Add(2*time.Hour), nil)
+	assert.Equal(t, 2, result.SessionsClosed)
+	assert.Equal(t, 2, result.MessagesSent)
+	assert.Equal(t, int64(1), countAutoCloseMessages(t, app, waiting.ID))
+	assert.Equal(t, int64(1), countAutoCloseMessages(t, app, idle.ID))
+}
+
+func TestAutoCloseExpiredTransfers_DefersDuringQuietHours(t *testing.T) {
+	app := newSLATestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	agent := testutil.CreateTestUser(t, app.DB, org.ID)
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+
+	expiresAt := time.Now().Add(-time.Hour)
+	transfer := createSLATestTransfer(t, app, org.ID, contact.ID, agent.ID, account.Name, models.SLATracking{
+		ExpiresAt: &expiresAt,
+	})
+
+	now := time.Now()
+	settings := models.ChatbotSettings{
+		OrganizationID: org.ID,
+		SLA: models.SLAConfig{
+			Enabled:          true,
+			AutoCloseHours:   2,
+			AutoCloseMessage: "We're closing this conversation.",
+		},
+		QuietHours: models.QuietHoursConfig{
+			Enabled:  true,
+			Start:    now.UTC().Add(-time.Hour).Format("15:04"),
+			End:      now.UTC().Add(time.Hour).Format("15:04"),
+			Timezone: "UTC",
+		},
+	}
+
+	proc := NewSLAProcessor(app, time.Minute)
+	proc.autoCloseExpiredTransfers(org.ID, settings, now)
+
+	var updated models.AgentTransfer
+	require.NoError(t, app.DB.Where("id = ?", transfer.ID).First(&updated).Error)
+	assert.Equal(t, models.TransferStatusActive, updated.Status, "closing waits for quiet hours to end")
+
+	proc.autoCloseExpiredTransfers(org.ID, settings, now.Add(2*time.Hour))
+	require.NoError(t, app.DB.Where("id = ?", transfer.ID).First(&updated).Error)
+	assert.Equal(t, models.TransferStatusExpired, updated.Status)
+}
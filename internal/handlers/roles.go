@@ -14,6 +14,10 @@ type RoleRequest struct {
 	Description string   `json:"description"`
 	IsDefault   bool     `json:"is_default"`
 	Permissions []string `json:"permissions"` // Format: ["resource:action", ...]
+
+	// Contact visibility flags; omitted values are left unchanged on update
+	ContactsOwnOnly           *bool `json:"contacts_own_only"`
+	ContactsIncludeUnassigned *bool `json:"contacts_include_unassigned"`
 }
 
 // RoleResponse represents the response for a role
@@ -27,6 +31,9 @@ type RoleResponse struct {
 	UserCount   int64     `json:"user_count"`
 	CreatedAt   string    `json:"created_at"`
 	UpdatedAt   string    `json:"updated_at"`
+
+	ContactsOwnOnly           bool `json:"contacts_own_only"`
+	ContactsIncludeUnassigned bool `json:"contacts_include_unassigned"`
 }
 
 // PermissionResponse represents a permission in the API
@@ -38,6 +45,12 @@ type PermissionResponse struct {
 	Key         string    `json:"key"` // "resource:action"
 }
 
+// PermissionGroupResponse represents the permissions of one resource in the API
+type PermissionGroupResponse struct {
+	Resource    string               `json:"resource"`
+	Permissions []PermissionResponse `json:"permissions"`
+}
+
 // ListRoles returns all roles for the organization
 func (a *App) ListRoles(r *fastglue.Request) error {
 	orgID, userID, err := a.getOrgAndUserID(r)
@@ -45,6 +58,10 @@ func (a *App) ListRoles(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
 
+	if err := a.requirePermission(r, userID, models.ResourceRoles, models.ActionRead); err != nil {
+		return nil
+	}
+
 	pg := parsePagination(r)
 	search := string(r.RequestCtx.QueryArgs().Peek("search"))
 
@@ -78,9 +95,7 @@ func (a *App) ListRoles(r *fastglue.Request) error {
 	// Convert to response format with user counts
 	response := make([]RoleResponse, len(roles))
 	for i, role := range roles {
-		var userCount int64
-		a.DB.Model(&models.User{}).Where("role_id = ?", role.ID).Count(&userCount)
-		response[i] = roleToResponse(role, userCount)
+		response[i] = roleToResponse(role, a.roleUserCount(role.ID))
 	}
 
 	return r.SendEnvelope(map[string]interface{}{
@@ -93,11 +108,15 @@ func (a *App) ListRoles(r *fastglue.Request) error {
 
 // GetRole returns a single role
 func (a *App) GetRole(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
 
+	if err := a.requirePermission(r, userID, models.ResourceRoles, models.ActionRead); err != nil {
+		return nil
+	}
+
 	id, err := parsePathUUID(r, "id", "role")
 	if err != nil {
 		return nil
@@ -114,19 +133,20 @@ func (a *App) GetRole(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to get role", nil, "")
 	}
 
-	var userCount int64
-	a.DB.Model(&models.User{}).Where("role_id = ?", role.ID).Count(&userCount)
-
-	return r.SendEnvelope(roleToResponse(role, userCount))
+	return r.SendEnvelope(roleToResponse(role, a.roleUserCount(role.ID)))
 }
 
 // CreateRole creates a new custom role
 func (a *App) CreateRole(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
 
+	if err := a.requirePermission(r, userID, models.ResourceRoles, models.ActionWrite); err != nil {
+		return nil
+	}
+
 	var req RoleRequest
 	if err := a.decodeRequest(r, &req); err != nil {
 		return nil
@@ -159,6 +179,7 @@ func (a *App) CreateRole(r *fastglue.Request) error {
 		IsDefault:      req.IsDefault,
 		Permissions:    permissions,
 	}
+	applyContactVisibility(&role, req)
 
 	// If setting as default, unset other defaults (in a transaction)
 	if req.IsDefault {
@@ -184,11 +205,15 @@ func (a *App) CreateRole(r *fastglue.Request) error {
 
 // UpdateRole updates a custom role
 func (a *App) UpdateRole(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
 
+	if err := a.requirePermission(r, userID, models.ResourceRoles, models.ActionWrite); err != nil {
+		return nil
+	}
+
 	id, err := parsePathUUID(r, "id", "role")
 	if err != nil {
 		return nil
@@ -215,10 +240,11 @@ func (a *App) UpdateRole(r *fastglue.Request) error {
 		// Check if user is super admin
 		isSuperAdmin, _ := r.RequestCtx.UserValue("is_super_admin").(bool)
 
-		// Only allow description updates for non-super admins
+		// Only allow description and contact visibility updates for non-super admins
 		if req.Description != "" {
 			role.Description = req.Description
 		}
+		visibilityChanged := applyContactVisibility(&role, req)
 
 		// Super admins can update permissions for system roles
 		if isSuperAdmin && len(req.Permissions) > 0 {
@@ -227,6 +253,9 @@ func (a *App) UpdateRole(r *fastglue.Request) error {
 				a.Log.Error("Failed to fetch permissions", "error", err)
 				return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update role", nil, "")
 			}
+			if !grantsRoleManagement(permissions) && a.isLastAdminRole(&role) {
+				return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Cannot remove roles:write from the last admin role", nil, "")
+			}
 			if err := a.DB.Model(&role).Association("Permissions").Replace(permissions); err != nil {
 				a.Log.Error("Failed to update role permissions", "error", err)
 				return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update role", nil, "")
@@ -240,13 +269,11 @@ func (a *App) UpdateRole(r *fastglue.Request) error {
 		}
 
 		// Invalidate permissions cache for all users with this role
-		if isSuperAdmin && len(req.Permissions) > 0 {
+		if (isSuperAdmin && len(req.Permissions) > 0) || visibilityChanged {
 			a.InvalidateRolePermissionsCache(role.ID)
 		}
 
-		var userCount int64
-		a.DB.Model(&models.User{}).Where("role_id = ?", role.ID).Count(&userCount)
-		return r.SendEnvelope(roleToResponse(role, userCount))
+		return r.SendEnvelope(roleToResponse(role, a.roleUserCount(role.ID)))
 	}
 
 	// For custom roles, allow full updates
@@ -261,6 +288,7 @@ func (a *App) UpdateRole(r *fastglue.Request) error {
 	if req.Description != "" {
 		role.Description = req.Description
 	}
+	applyContactVisibility(&role, req)
 
 	// Update permissions if provided
 	if len(req.Permissions) > 0 {
@@ -269,6 +297,9 @@ func (a *App) UpdateRole(r *fastglue.Request) error {
 			a.Log.Error("Failed to fetch permissions", "error", err)
 			return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update role", nil, "")
 		}
+		if !grantsRoleManagement(permissions) && a.isLastAdminRole(&role) {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Cannot remove roles:write from the last admin role", nil, "")
+		}
 		// Replace associations
 		if err := a.DB.Model(&role).Association("Permissions").Replace(permissions); err != nil {
 			a.Log.Error("Failed to update role permissions", "error", err)
@@ -302,18 +333,20 @@ func (a *App) UpdateRole(r *fastglue.Request) error {
 	// Invalidate permissions cache for all users with this role
 	a.InvalidateRolePermissionsCache(role.ID)
 
-	var userCount int64
-	a.DB.Model(&models.User{}).Where("role_id = ?", role.ID).Count(&userCount)
-	return r.SendEnvelope(roleToResponse(role, userCount))
+	return r.SendEnvelope(roleToResponse(role, a.roleUserCount(role.ID)))
 }
 
 // DeleteRole deletes a custom role
 func (a *App) DeleteRole(r *fastglue.Request) error {
-	orgID, err := a.getOrgID(r)
+	orgID, userID, err := a.getOrgAndUserID(r)
 	if err != nil {
 		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
 	}
 
+	if err := a.requirePermission(r, userID, models.ResourceRoles, models.ActionDelete); err != nil {
+		return nil
+	}
+
 	id, err := parsePathUUID(r, "id", "role")
 	if err != nil {
 		return nil
@@ -329,13 +362,19 @@ func (a *App) DeleteRole(r *fastglue.Request) error {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Cannot delete system roles", nil, "")
 	}
 
-	// Check if any users have this role
-	var userCount int64
-	a.DB.Model(&models.User{}).Where("role_id = ?", id).Count(&userCount)
-	if userCount > 0 {
+	// Check if any organization members have this role
+	if a.roleUserCount(id) > 0 {
 		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Cannot delete role with assigned users", nil, "")
 	}
 
+	if err := a.loadRolePermissions(role); err != nil {
+		a.Log.Error("Failed to load role permissions", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete role", nil, "")
+	}
+	if a.isLastAdminRole(role) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Cannot delete the last admin role", nil, "")
+	}
+
 	// Delete the role (permissions associations will be cleared automatically)
 	if err := a.DB.Delete(role).Error; err != nil {
 		a.Log.Error("Failed to delete role", "error", err)
@@ -345,8 +384,84 @@ func (a *App) DeleteRole(r *fastglue.Request) error {
 	return r.SendEnvelope(map[string]string{"message": "Role deleted successfully"})
 }
 
-// ListPermissions returns all available permissions
+// CloneRole creates a new custom role with the same permissions and contact
+// visibility as an existing role in the organization
+func (a *App) CloneRole(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceRoles, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	id, err := parsePathUUID(r, "id", "role")
+	if err != nil {
+		return nil
+	}
+
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	if req.Name == "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Name is required", nil, "")
+	}
+
+	var source models.CustomRole
+	if err := a.DB.Where("id = ? AND organization_id = ?", id, orgID).
+		First(&source).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Role not found", nil, "")
+	}
+	if err := a.loadRolePermissions(&source); err != nil {
+		a.Log.Error("Failed to load role permissions", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to clone role", nil, "")
+	}
+
+	var existingRole models.CustomRole
+	if err := a.DB.Where("organization_id = ? AND name = ?", orgID, req.Name).First(&existingRole).Error; err == nil {
+		return r.SendErrorEnvelope(fasthttp.StatusConflict, "Role with this name already exists", nil, "")
+	}
+
+	description := req.Description
+	if description == "" {
+		description = source.Description
+	}
+
+	role := models.CustomRole{
+		BaseModel:                 models.BaseModel{ID: uuid.New()},
+		OrganizationID:            orgID,
+		Name:                      req.Name,
+		Description:               description,
+		IsSystem:                  false,
+		IsDefault:                 false,
+		Permissions:               source.Permissions,
+		ContactsOwnOnly:           source.ContactsOwnOnly,
+		ContactsIncludeUnassigned: source.ContactsIncludeUnassigned,
+	}
+	if err := a.DB.Create(&role).Error; err != nil {
+		a.Log.Error("Failed to clone role", "error", err, "source_role_id", source.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to clone role", nil, "")
+	}
+
+	return r.SendEnvelope(roleToResponse(role, 0))
+}
+
+// ListPermissions returns all available permissions, both as a flat list and grouped by resource
 func (a *App) ListPermissions(r *fastglue.Request) error {
+	_, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceRoles, models.ActionRead); err != nil {
+		return nil
+	}
+
 	var permissions []models.Permission
 	if err := a.DB.Order("resource ASC, action ASC").Find(&permissions).Error; err != nil {
 		a.Log.Error("Failed to list permissions", "error", err)
@@ -354,6 +469,7 @@ func (a *App) ListPermissions(r *fastglue.Request) error {
 	}
 
 	response := make([]PermissionResponse, len(permissions))
+	groups := []PermissionGroupResponse{}
 	for i, p := range permissions {
 		response[i] = PermissionResponse{
 			ID:          p.ID,
@@ -362,10 +478,17 @@ func (a *App) ListPermissions(r *fastglue.Request) error {
 			Description: p.Description,
 			Key:         p.Resource + ":" + p.Action,
 		}
+		// Permissions are ordered by resource, so each group is contiguous
+		if len(groups) == 0 || groups[len(groups)-1].Resource != p.Resource {
+			groups = append(groups, PermissionGroupResponse{Resource: p.Resource})
+		}
+		last := &groups[len(groups)-1]
+		last.Permissions = append(last.Permissions, response[i])
 	}
 
 	return r.SendEnvelope(map[string]interface{}{
 		"permissions": response,
+		"groups":      groups,
 	})
 }
 
@@ -386,9 +509,61 @@ func roleToResponse(role models.CustomRole, userCount int64) RoleResponse {
 		UserCount:   userCount,
 		CreatedAt:   role.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:   role.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+
+		ContactsOwnOnly:           role.ContactsOwnOnly,
+		ContactsIncludeUnassigned: role.ContactsIncludeUnassigned,
 	}
 }
 
+// applyContactVisibility copies the contact visibility flags present in the
+// request onto the role and reports whether anything changed
+func applyContactVisibility(role *models.CustomRole, req RoleRequest) bool {
+	changed := false
+	if req.ContactsOwnOnly != nil && *req.ContactsOwnOnly != role.ContactsOwnOnly {
+		role.ContactsOwnOnly = *req.ContactsOwnOnly
+		changed = true
+	}
+	if req.ContactsIncludeUnassigned != nil && *req.ContactsIncludeUnassigned != role.ContactsIncludeUnassigned {
+		role.ContactsIncludeUnassigned = *req.ContactsIncludeUnassigned
+		changed = true
+	}
+	return changed
+}
+
+// roleUserCount returns how many organization members have the role
+func (a *App) roleUserCount(roleID uuid.UUID) int64 {
+	var count int64
+	a.DB.Model(&models.UserOrganization{}).Where("role_id = ?", roleID).Count(&count)
+	return count
+}
+
+// grantsRoleManagement reports whether the permissions include roles:write
+func grantsRoleManagement(permissions []models.Permission) bool {
+	for _, p := range permissions {
+		if p.Resource == models.ResourceRoles && p.Action == models.ActionWrite {
+			return true
+		}
+	}
+	return false
+}
+
+// isLastAdminRole reports whether the role is the only one in its organization
+// that grants roles:write. Removing it would leave nobody able to manage roles.
+// The role's permissions must be loaded.
+func (a *App) isLastAdminRole(role *models.CustomRole) bool {
+	if !grantsRoleManagement(role.Permissions) {
+		return false
+	}
+	var count int64
+	a.DB.Table("custom_roles").
+		Joins("JOIN role_permissions ON role_permissions.custom_role_id = custom_roles.id").
+		Joins("JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Where("custom_roles.organization_id = ? AND custom_roles.id != ? AND custom_roles.deleted_at IS NULL", role.OrganizationID, role.ID).
+		Where("permissions.resource = ? AND permissions.action = ?", models.ResourceRoles, models.ActionWrite).
+		Count(&count)
+	return count == 0
+}
+
 // Helper function to get permissions by their keys
 func (a *App) getPermissionsByKeys(keys []string) ([]models.Permission, error) {
 	if len(keys) == 0 {
@@ -13,6 +13,13 @@ import (
 	"github.com/valyala/fasthttp"
 )
 
+// createRoleManager creates a user whose role can read, write and delete roles.
+func createRoleManager(t *testing.T, app *handlers.App, orgID uuid.UUID, emailPrefix string) *models.User {
+	t.Helper()
+	role := testutil.CreateTestRoleWithKeys(t, app.DB, orgID, "role-manager", []string{"roles:read", "roles:write", "roles:delete"})
+	return testutil.CreateTestUser(t, app.DB, orgID, testutil.WithEmail(testutil.UniqueEmail(emailPrefix)), testutil.WithRoleID(&role.ID))
+}
+
 func TestApp_ListRoles_Success(t *testing.T) {
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
@@ -58,7 +65,7 @@ func TestApp_GetRole_Success(t *testing.T) {
 	permissions := testutil.GetOrCreateTestPermissions(t, app.DB)
 
 	role := testutil.CreateTestRoleExact(t, app.DB, org.ID, "Test Role", false, false, permissions[:2])
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("get-role")), testutil.WithRoleID(&role.ID))
+	user := createRoleManager(t, app, org.ID, "get-role")
 
 	req := testutil.NewGETRequest(t)
 	req.RequestCtx.SetUserValue("user_id", user.ID)
@@ -85,7 +92,7 @@ func TestApp_GetRole_Success(t *testing.T) {
 func TestApp_GetRole_NotFound(t *testing.T) {
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("get-role-404")))
+	user := createRoleManager(t, app, org.ID, "get-role-404")
 
 	req := testutil.NewGETRequest(t)
 	req.RequestCtx.SetUserValue("user_id", user.ID)
@@ -101,7 +108,7 @@ func TestApp_CreateRole_Success(t *testing.T) {
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
 	permissions := testutil.GetOrCreateTestPermissions(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("create-role")))
+	user := createRoleManager(t, app, org.ID, "create-role")
 
 	reqBody := handlers.RoleRequest{
 		Name:        "New Role",
@@ -146,7 +153,7 @@ func TestApp_CreateRole_DuplicateName(t *testing.T) {
 	_ = testutil.GetOrCreateTestPermissions(t, app.DB)
 
 	testutil.CreateTestRoleExact(t, app.DB, org.ID, "Existing Role", false, false, nil)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("create-dup-role")))
+	user := createRoleManager(t, app, org.ID, "create-dup-role")
 
 	reqBody := handlers.RoleRequest{
 		Name:        "Existing Role",
@@ -166,7 +173,7 @@ func TestApp_CreateRole_DuplicateName(t *testing.T) {
 func TestApp_CreateRole_MissingName(t *testing.T) {
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("create-no-name")))
+	user := createRoleManager(t, app, org.ID, "create-no-name")
 
 	reqBody := handlers.RoleRequest{
 		Name:        "",
@@ -190,7 +197,7 @@ func TestApp_CreateRole_WithDefaultFlag(t *testing.T) {
 
 	// Create an existing default role
 	existingDefault := testutil.CreateTestRoleExact(t, app.DB, org.ID, "Old Default", false, true, nil)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("create-default")))
+	user := createRoleManager(t, app, org.ID, "create-default")
 
 	reqBody := handlers.RoleRequest{
 		Name:        "New Default Role",
@@ -219,7 +226,7 @@ func TestApp_UpdateRole_Success(t *testing.T) {
 	permissions := testutil.GetOrCreateTestPermissions(t, app.DB)
 
 	role := testutil.CreateTestRoleExact(t, app.DB, org.ID, "Editable Role", false, false, permissions[:1])
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("update-role")))
+	user := createRoleManager(t, app, org.ID, "update-role")
 
 	reqBody := handlers.RoleRequest{
 		Name:        "Updated Role Name",
@@ -255,7 +262,7 @@ func TestApp_UpdateRole_SystemRoleOnlyDescription(t *testing.T) {
 
 	// Create a system role
 	systemRole := testutil.CreateTestRoleExact(t, app.DB, org.ID, "System Admin", true, false, permissions)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("update-sys-role")))
+	user := createRoleManager(t, app, org.ID, "update-sys-role")
 
 	reqBody := handlers.RoleRequest{
 		Name:        "Changed Name",        // Should be ignored for system roles
@@ -286,10 +293,45 @@ func TestApp_UpdateRole_SystemRoleOnlyDescription(t *testing.T) {
 	assert.Len(t, resp.Data.Permissions, len(permissions))
 }
 
+func TestApp_UpdateRole_ContactVisibility(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	permissions := testutil.GetOrCreateTestPermissions(t, app.DB)
+
+	// Visibility flags can be set on system roles too
+	agentRole := testutil.CreateTestRoleExact(t, app.DB, org.ID, "Agent", true, false, permissions[:1])
+	user := createRoleManager(t, app, org.ID, "role-visibility")
+
+	update := func(t *testing.T, body any) handlers.RoleResponse {
+		t.Helper()
+		req := testutil.NewJSONRequest(t, body)
+		req.RequestCtx.SetUserValue("user_id", user.ID)
+		req.RequestCtx.SetUserValue("organization_id", org.ID)
+		req.RequestCtx.SetUserValue("id", agentRole.ID.String())
+		require.NoError(t, app.UpdateRole(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data handlers.RoleResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		return resp.Data
+	}
+
+	got := update(t, map[string]any{"contacts_own_only": true, "contacts_include_unassigned": true})
+	assert.True(t, got.ContactsOwnOnly)
+	assert.True(t, got.ContactsIncludeUnassigned)
+
+	// Omitted flags are left unchanged
+	got = update(t, map[string]any{"description": "Front line", "contacts_include_unassigned": false})
+	assert.True(t, got.ContactsOwnOnly)
+	assert.False(t, got.ContactsIncludeUnassigned)
+}
+
 func TestApp_UpdateRole_NotFound(t *testing.T) {
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("update-404")))
+	user := createRoleManager(t, app, org.ID, "update-404")
 
 	reqBody := handlers.RoleRequest{
 		Name: "Updated Name",
@@ -310,7 +352,7 @@ func TestApp_DeleteRole_Success(t *testing.T) {
 	org := testutil.CreateTestOrganization(t, app.DB)
 
 	role := testutil.CreateTestRoleExact(t, app.DB, org.ID, "Deletable Role", false, false, nil)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("delete-role")))
+	user := createRoleManager(t, app, org.ID, "delete-role")
 
 	req := testutil.NewGETRequest(t)
 	req.RequestCtx.Request.Header.SetMethod("DELETE")
@@ -333,7 +375,7 @@ func TestApp_DeleteRole_SystemRole(t *testing.T) {
 	org := testutil.CreateTestOrganization(t, app.DB)
 
 	systemRole := testutil.CreateTestRoleExact(t, app.DB, org.ID, "System Role", true, false, nil)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("delete-sys")))
+	user := createRoleManager(t, app, org.ID, "delete-sys")
 
 	req := testutil.NewGETRequest(t)
 	req.RequestCtx.Request.Header.SetMethod("DELETE")
@@ -357,7 +399,7 @@ func TestApp_DeleteRole_WithAssignedUsers(t *testing.T) {
 	role := testutil.CreateTestRoleExact(t, app.DB, org.ID, "Role With Users", false, false, nil)
 	// Create a user with this role
 	testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("assigned-user")), testutil.WithRoleID(&role.ID))
-	adminUser := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("delete-used-role")))
+	adminUser := createRoleManager(t, app, org.ID, "delete-used-role")
 
 	req := testutil.NewGETRequest(t)
 	req.RequestCtx.Request.Header.SetMethod("DELETE")
@@ -370,11 +412,151 @@ func TestApp_DeleteRole_WithAssignedUsers(t *testing.T) {
 	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
 }
 
+func TestApp_CreateRole_Forbidden(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+
+	readOnly := testutil.CreateTestRoleWithKeys(t, app.DB, org.ID, "role-reader", []string{"roles:read"})
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("create-forbidden")), testutil.WithRoleID(&readOnly.ID))
+
+	req := testutil.NewJSONRequest(t, handlers.RoleRequest{Name: "Not Allowed"})
+	req.RequestCtx.SetUserValue("user_id", user.ID)
+	req.RequestCtx.SetUserValue("organization_id", org.ID)
+
+	err := app.CreateRole(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+
+	var count int64
+	app.DB.Model(&models.CustomRole{}).Where("organization_id = ? AND name = ?", org.ID, "Not Allowed").Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestApp_UpdateRole_LastAdminRole(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+
+	// The manager's role is the only one that grants roles:write
+	user := createRoleManager(t, app, org.ID, "update-last-admin")
+
+	req := testutil.NewJSONRequest(t, handlers.RoleRequest{
+		Name:        "Role Manager",
+		Permissions: []string{"roles:read", "roles:delete"},
+	})
+	req.RequestCtx.SetUserValue("user_id", user.ID)
+	req.RequestCtx.SetUserValue("organization_id", org.ID)
+	req.RequestCtx.SetUserValue("id", user.RoleID.String())
+
+	err := app.UpdateRole(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+}
+
+func TestApp_DeleteRole_LastAdminRole(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+
+	adminRole := testutil.CreateTestRoleWithKeys(t, app.DB, org.ID, "admins", []string{"roles:read", "roles:write"})
+	deleter := testutil.CreateTestRoleWithKeys(t, app.DB, org.ID, "deleter", []string{"roles:read", "roles:delete"})
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("delete-last-admin")), testutil.WithRoleID(&deleter.ID))
+
+	req := testutil.NewGETRequest(t)
+	req.RequestCtx.Request.Header.SetMethod("DELETE")
+	req.RequestCtx.SetUserValue("user_id", user.ID)
+	req.RequestCtx.SetUserValue("organization_id", org.ID)
+	req.RequestCtx.SetUserValue("id", adminRole.ID.String())
+
+	err := app.DeleteRole(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+
+	var dbRole models.CustomRole
+	require.NoError(t, app.DB.First(&dbRole, "id = ?", adminRole.ID).Error)
+}
+
+func TestApp_GetRole_UserCount(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+
+	role := testutil.CreateTestRoleExact(t, app.DB, org.ID, "Counted Role", false, false, nil)
+	testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("counted-1")), testutil.WithRoleID(&role.ID))
+	testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("counted-2")), testutil.WithRoleID(&role.ID))
+	user := createRoleManager(t, app, org.ID, "get-role-count")
+
+	req := testutil.NewGETRequest(t)
+	req.RequestCtx.SetUserValue("user_id", user.ID)
+	req.RequestCtx.SetUserValue("organization_id", org.ID)
+	req.RequestCtx.SetUserValue("id", role.ID.String())
+
+	err := app.GetRole(req)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.RoleResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, int64(2), resp.Data.UserCount)
+}
+
+func TestApp_CloneRole_Success(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+
+	source := testutil.CreateTestRoleWithKeys(t, app.DB, org.ID, "agent-tier-1", []string{"chat:read", "contacts:read"})
+	require.NoError(t, app.DB.Model(source).Update("contacts_own_only", true).Error)
+	user := createRoleManager(t, app, org.ID, "clone-role")
+
+	req := testutil.NewJSONRequest(t, map[string]any{"name": "Agent Tier 2"})
+	req.RequestCtx.SetUserValue("user_id", user.ID)
+	req.RequestCtx.SetUserValue("organization_id", org.ID)
+	req.RequestCtx.SetUserValue("id", source.ID.String())
+
+	err := app.CloneRole(req)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data handlers.RoleResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+
+	assert.NotEqual(t, source.ID, resp.Data.ID)
+	assert.Equal(t, "Agent Tier 2", resp.Data.Name)
+	assert.False(t, resp.Data.IsSystem)
+	assert.False(t, resp.Data.IsDefault)
+	assert.True(t, resp.Data.ContactsOwnOnly)
+	assert.ElementsMatch(t, []string{"chat:read", "contacts:read"}, resp.Data.Permissions)
+
+	// Permissions are stored on the new role
+	var count int64
+	app.DB.Model(&models.RolePermission{}).Where("custom_role_id = ?", resp.Data.ID).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestApp_CloneRole_OtherOrganization(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	otherOrg := testutil.CreateTestOrganization(t, app.DB)
+
+	source := testutil.CreateTestRoleWithKeys(t, app.DB, otherOrg.ID, "foreign-role", []string{"chat:read"})
+	user := createRoleManager(t, app, org.ID, "clone-foreign")
+
+	req := testutil.NewJSONRequest(t, map[string]any{"name": "Stolen Role"})
+	req.RequestCtx.SetUserValue("user_id", user.ID)
+	req.RequestCtx.SetUserValue("organization_id", org.ID)
+	req.RequestCtx.SetUserValue("id", source.ID.String())
+
+	err := app.CloneRole(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+}
+
 func TestApp_ListPermissions_Success(t *testing.T) {
 	app := newTestApp(t)
 	org := testutil.CreateTestOrganization(t, app.DB)
 	permissions := testutil.GetOrCreateTestPermissions(t, app.DB)
-	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("list-perms")))
+	user := createRoleManager(t, app, org.ID, "list-perms")
 
 	req := testutil.NewGETRequest(t)
 	req.RequestCtx.SetUserValue("user_id", user.ID)
@@ -387,7 +569,8 @@ func TestApp_ListPermissions_Success(t *testing.T) {
 	var resp struct {
 		Status string `json:"status"`
 		Data   struct {
-			Permissions []handlers.PermissionResponse `json:"permissions"`
+			Permissions []handlers.PermissionResponse      `json:"permissions"`
+			Groups      []handlers.PermissionGroupResponse `json:"groups"`
 		} `json:"data"`
 	}
 	err = json.Unmarshal(testutil.GetResponseBody(req), &resp)
@@ -402,4 +585,31 @@ func TestApp_ListPermissions_Success(t *testing.T) {
 		assert.NotEmpty(t, perm.Action)
 		assert.Equal(t, perm.Resource+":"+perm.Action, perm.Key)
 	}
+
+	// Every permission appears once, under its own resource
+	grouped := 0
+	seen := make(map[string]bool)
+	for _, group := range resp.Data.Groups {
+		assert.False(t, seen[group.Resource], "resource %s grouped twice", group.Resource)
+		seen[group.Resource] = true
+		for _, perm := range group.Permissions {
+			assert.Equal(t, group.Resource, perm.Resource)
+			grouped++
+		}
+	}
+	assert.Equal(t, len(resp.Data.Permissions), grouped)
+}
+
+func TestApp_ListPermissions_Forbidden(t *testing.T) {
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("list-perms-forbidden")))
+
+	req := testutil.NewGETRequest(t)
+	req.RequestCtx.SetUserValue("user_id", user.ID)
+	req.RequestCtx.SetUserValue("organization_id", org.ID)
+
+	err := app.ListPermissions(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
 }
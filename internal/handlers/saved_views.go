@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+	"gorm.io/gorm"
+)
+
+// maxSavedViewsPerUser caps how many views a user can save in an organization
+const maxSavedViewsPerUser = 50
+
+// Assignment values a saved view can filter by, besides a user ID
+const (
+	savedViewAssignedToMe = "me"
+	savedViewUnassigned   = "unassigned"
+)
+
+// SavedViewFilters is the inbox filter spec stored in a saved view. Empty
+// fields don't filter.
+type SavedViewFilters struct {
+	ConversationStatus []string `json:"conversation_status,omitempty"` // Matches ANY of the statuses
+	Tags               []string `json:"tags,omitempty"`                // Matches ANY of the tags
+	Assignment         string   `json:"assignment,omitempty"`          // "me", "unassigned" or a user ID
+	Search             string   `json:"search,omitempty"`
+	WhatsAppAccount    string   `json:"whatsapp_account,omitempty"`
+}
+
+// SavedViewRequest represents the request body for creating/updating a saved view
+type SavedViewRequest struct {
+	Name    string           `json:"name"`
+	Filters SavedViewFilters `json:"filters"`
+}
+
+// SavedViewResponse represents the API response for a saved view
+type SavedViewResponse struct {
+	ID        uuid.UUID        `json:"id"`
+	Name      string           `json:"name"`
+	Filters   SavedViewFilters `json:"filters"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// ListSavedViews returns the current user's saved views, by name
+func (a *App) ListSavedViews(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	var views []models.SavedView
+	if err := a.DB.Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Order("name ASC").Find(&views).Error; err != nil {
+		a.Log.Error("Failed to list saved views", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list saved views", nil, "")
+	}
+
+	result := make([]SavedViewResponse, len(views))
+	for i := range views {
+		result[i] = savedViewToResponse(&views[i])
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"views": result,
+	})
+}
+
+// CreateSavedView saves a named set of inbox filters for the current user
+func (a *App) CreateSavedView(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	var req SavedViewRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	if errMsg := a.validateSavedViewRequest(&req, orgID); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+
+	var count int64
+	a.DB.Model(&models.SavedView{}).Where("organization_id = ? AND user_id = ?", orgID, userID).Count(&count)
+	if count >= maxSavedViewsPerUser {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			fmt.Sprintf("You can save at most %d views", maxSavedViewsPerUser), nil, "")
+	}
+	if a.savedViewNameTaken(orgID, userID, req.Name, uuid.Nil) {
+		return r.SendErrorEnvelope(fasthttp.StatusConflict, "You already have a view with this name", nil, "")
+	}
+
+	view := models.SavedView{
+		OrganizationID: orgID,
+		UserID:         userID,
+		Name:           req.Name,
+		Filters:        savedViewFiltersToJSONB(req.Filters),
+	}
+	if err := a.DB.Create(&view).Error; err != nil {
+		a.Log.Error("Failed to create saved view", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to create saved view", nil, "")
+	}
+
+	return r.SendEnvelope(savedViewToResponse(&view))
+}
+
+// UpdateSavedView renames a saved view and replaces its filters
+func (a *App) UpdateSavedView(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	view, ok := a.findSavedView(r, orgID, userID)
+	if !ok {
+		return nil
+	}
+
+	var req SavedViewRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	if errMsg := a.validateSavedViewRequest(&req, orgID); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+	if a.savedViewNameTaken(orgID, userID, req.Name, view.ID) {
+		return r.SendErrorEnvelope(fasthttp.StatusConflict, "You already have a view with this name", nil, "")
+	}
+
+	view.Name = req.Name
+	view.Filters = savedViewFiltersToJSONB(req.Filters)
+	if err := a.DB.Model(view).Updates(map[string]any{
+		"name":    view.Name,
+		"filters": view.Filters,
+	}).Error; err != nil {
+		a.Log.Error("Failed to update saved view", "error", err, "view_id", view.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to update saved view", nil, "")
+	}
+
+	return r.SendEnvelope(savedViewToResponse(view))
+}
+
+// DeleteSavedView deletes one of the current user's saved views
+func (a *App) DeleteSavedView(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	view, ok := a.findSavedView(r, orgID, userID)
+	if !ok {
+		return nil
+	}
+
+	if err := a.DB.Delete(view).Error; err != nil {
+		a.Log.Error("Failed to delete saved view", "error", err, "view_id", view.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to delete saved view", nil, "")
+	}
+
+	return r.SendEnvelope(map[string]string{"message": "Saved view deleted successfully"})
+}
+
+// ApplySavedView returns the contacts matching a saved view, paginated and
+// ordered like ListContacts. Only contacts the user can see are included.
+func (a *App) ApplySavedView(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	view, ok := a.findSavedView(r, orgID, userID)
+	if !ok {
+		return nil
+	}
+	filters := savedViewFiltersFromJSONB(view.Filters)
+
+	pg := parsePagination(r)
+
+	query := a.ScopeToOrg(a.DB, userID, orgID)
+	query = a.scopeContactsQuery(query, userID, orgID)
+	query = filters.apply(query, userID, time.Now())
+
+	var total int64
+	query.Model(&models.Contact{}).Count(&total)
+
+	var contacts []models.Contact
+	if err := query.Order("last_message_at DESC NULLS LAST, created_at DESC").
+		Offset(pg.Offset).Limit(pg.Limit).
+		Preload("AssignedUser", selectAssignedUser).
+		Find(&contacts).Error; err != nil {
+		a.Log.Error("Failed to apply saved view", "error", err, "view_id", view.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list contacts", nil, "")
+	}
+
+	response := make([]ContactResponse, len(contacts))
+	for i := range contacts {
+		response[i] = a.buildContactResponse(&contacts[i], orgID)
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"view":     savedViewToResponse(view),
+		"contacts": response,
+		"total":    total,
+		"page":     pg.Page,
+		"limit":    pg.Limit,
+	})
+}
+
+// findSavedView loads the saved view named in the path, sending a 404 unless
+// it belongs to the user in this organization
+func (a *App) findSavedView(r *fastglue.Request, orgID, userID uuid.UUID) (*models.SavedView, bool) {
+	id, err := parsePathUUID(r, "id", "saved view")
+	if err != nil {
+		return nil, false
+	}
+	var view models.SavedView
+	if err := a.DB.Where("id = ? AND organization_id = ? AND user_id = ?", id, orgID, userID).
+		First(&view).Error; err != nil {
+		_ = r.SendErrorEnvelope(fasthttp.StatusNotFound, "Saved view not found", nil, "")
+		return nil, false
+	}
+	return &view, true
+}
+
+// savedViewNameTaken reports whether the user has another view with this name
+func (a *App) savedViewNameTaken(orgID, userID uuid.UUID, name string, excludeID uuid.UUID) bool {
+	var count int64
+	a.DB.Model(&models.SavedView{}).
+		Where("organization_id = ? AND user_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", orgID, userID, name, excludeID).
+		Count(&count)
+	return count > 0
+}
+
+// validateSavedViewRequest normalizes a saved view request in place and
+// returns an error message, or "" when valid
+func (a *App) validateSavedViewRequest(req *SavedViewRequest, orgID uuid.UUID) string {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return "name is required"
+	}
+	if len(req.Name) > 100 {
+		return "name must be at most 100 characters"
+	}
+
+	f := &req.Filters
+	f.Search = strings.TrimSpace(f.Search)
+	f.WhatsAppAccount = strings.TrimSpace(f.WhatsAppAccount)
+	f.Assignment = strings.TrimSpace(f.Assignment)
+	f.Tags = normalizeTagList(f.Tags)
+
+	statuses := make([]string, 0, len(f.ConversationStatus))
+	for _, status := range f.ConversationStatus {
+		status = strings.TrimSpace(status)
+		switch models.ConversationStatus(status) {
+		case models.ConversationStatusOpen, models.ConversationStatusPending,
+			models.ConversationStatusResolved, models.ConversationStatusSnoozed:
+			statuses = append(statuses, status)
+		default:
+			return fmt.Sprintf("Invalid conversation status %q", status)
+		}
+	}
+	f.ConversationStatus = statuses
+
+	switch f.Assignment {
+	case "", savedViewAssignedToMe, savedViewUnassigned:
+	default:
+		assigneeID, err := uuid.Parse(f.Assignment)
+		if err != nil || !a.isOrgMember(orgID, assigneeID) {
+			return "assignment must be me, unassigned or the ID of a user in the organization"
+		}
+	}
+	return ""
+}
+
+// apply adds the view's filters to a contacts query. userID resolves the
+// "me" assignment to the user applying the view.
+func (f *SavedViewFilters) apply(db *gorm.DB, userID uuid.UUID, now time.Time) *gorm.DB {
+	db = searchContacts(db, f.Search)
+	if len(f.Tags) > 0 {
+		db = filterContactsByAnyTag(db, f.Tags)
+	}
+	if len(f.ConversationStatus) > 0 {
+		db = filterContactsByConversationStatus(db, f.ConversationStatus, now)
+	}
+	if f.WhatsAppAccount != "" {
+		db = db.Where("whats_app_account = ?", f.WhatsAppAccount)
+	}
+	switch f.Assignment {
+	case "":
+	case savedViewAssignedToMe:
+		db = db.Where("assigned_user_id = ?", userID)
+	case savedViewUnassigned:
+		db = db.Where("assigned_user_id IS NULL")
+	default:
+		db = db.Where("assigned_user_id = ?", f.Assignment)
+	}
+	return db
+}
+
+// savedViewFiltersToJSONB converts a filter spec for storage
+func savedViewFiltersToJSONB(f SavedViewFilters) models.JSONB {
+	result := models.JSONB{}
+	if raw, err := json.Marshal(f); err == nil {
+		_ = json.Unmarshal(raw, &result)
+	}
+	return result
+}
+
+// savedViewFiltersFromJSONB is the inverse of savedViewFiltersToJSONB
+func savedViewFiltersFromJSONB(j models.JSONB) SavedViewFilters {
+	var f SavedViewFilters
+	if raw, err := json.Marshal(j); err == nil {
+		_ = json.Unmarshal(raw, &f)
+	}
+	return f
+}
+
+// savedViewToResponse converts a SavedView model to its API response
+func savedViewToResponse(view *models.SavedView) SavedViewResponse {
+	return SavedViewResponse{
+		ID:        view.ID,
+		Name:      view.Name,
+		Filters:   savedViewFiltersFromJSONB(view.Filters),
+		CreatedAt: view.CreatedAt,
+		UpdatedAt: view.UpdatedAt,
+	}
+}
@@ -0,0 +1,189 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/handlers"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func createSavedView(t *testing.T, app *handlers.App, orgID, userID uuid.UUID, body map[string]any) (int, handlers.SavedViewResponse) {
+	t.Helper()
+
+	req := testutil.NewJSONRequest(t, body)
+	testutil.SetAuthContext(req, orgID, userID)
+	require.NoError(t, app.CreateSavedView(req))
+
+	var resp struct {
+		Data handlers.SavedViewResponse `json:"data"`
+	}
+	status := testutil.GetResponseStatusCode(req)
+	if status == fasthttp.StatusOK {
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	}
+	return status, resp.Data
+}
+
+func TestApp_SavedViews_CRUD(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	other := testutil.CreateTestUser(t, app.DB, org.ID)
+
+	status, view := createSavedView(t, app, org.ID, user.ID, map[string]any{
+		"name": "  My open VIPs ",
+		"filters": map[string]any{
+			"conversation_status": []string{"open", " pending"},
+			"tags":                []string{"vip", "vip", " "},
+			"assignment":          "me",
+		},
+	})
+	require.Equal(t, fasthttp.StatusOK, status)
+	assert.Equal(t, "My open VIPs", view.Name)
+	assert.Equal(t, []string{"open", "pending"}, view.Filters.ConversationStatus)
+	assert.Equal(t, []string{"vip"}, view.Filters.Tags)
+	assert.Equal(t, "me", view.Filters.Assignment)
+
+	t.Run("duplicate name", func(t *testing.T) {
+		status, _ := createSavedView(t, app, org.ID, user.ID, map[string]any{"name": "my open vips"})
+		assert.Equal(t, fasthttp.StatusConflict, status)
+
+		// Another user can reuse the name
+		status, _ = createSavedView(t, app, org.ID, other.ID, map[string]any{"name": "My open VIPs"})
+		assert.Equal(t, fasthttp.StatusOK, status)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		for _, body := range []map[string]any{
+			{"name": " "},
+			{"name": "Bad status", "filters": map[string]any{"conversation_status": []string{"archived"}}},
+			{"name": "Bad assignee", "filters": map[string]any{"assignment": "someone"}},
+			{"name": "Foreign assignee", "filters": map[string]any{"assignment": uuid.New().String()}},
+		} {
+			status, _ := createSavedView(t, app, org.ID, user.ID, body)
+			assert.Equal(t, fasthttp.StatusBadRequest, status, "body: %v", body)
+		}
+	})
+
+	t.Run("list only shows own views", func(t *testing.T) {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		require.NoError(t, app.ListSavedViews(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Views []handlers.SavedViewResponse `json:"views"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		require.Len(t, resp.Data.Views, 1)
+		assert.Equal(t, view.ID, resp.Data.Views[0].ID)
+	})
+
+	t.Run("other users cannot update or delete", func(t *testing.T) {
+		req := testutil.NewJSONRequest(t, map[string]any{"name": "Hijacked"})
+		testutil.SetAuthContext(req, org.ID, other.ID)
+		testutil.SetPathParam(req, "id", view.ID.String())
+		require.NoError(t, app.UpdateSavedView(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+
+		req = testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, other.ID)
+		testutil.SetPathParam(req, "id", view.ID.String())
+		require.NoError(t, app.DeleteSavedView(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("update then delete", func(t *testing.T) {
+		status, v := createSavedView(t, app, org.ID, user.ID, map[string]any{"name": "Scratch"})
+		require.Equal(t, fasthttp.StatusOK, status)
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"name":    "Unassigned",
+			"filters": map[string]any{"assignment": "unassigned"},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", v.ID.String())
+		require.NoError(t, app.UpdateSavedView(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var stored models.SavedView
+		require.NoError(t, app.DB.First(&stored, v.ID).Error)
+		assert.Equal(t, "Unassigned", stored.Name)
+		assert.Equal(t, "unassigned", stored.Filters["assignment"])
+
+		req = testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", v.ID.String())
+		require.NoError(t, app.DeleteSavedView(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		assert.Error(t, app.DB.First(&models.SavedView{}, v.ID).Error)
+	})
+}
+
+func TestApp_ApplySavedView(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+	user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+	teammate := testutil.CreateTestUser(t, app.DB, org.ID)
+
+	mineVIP := testutil.CreateTestContact(t, app.DB, org.ID)
+	mineResolved := testutil.CreateTestContact(t, app.DB, org.ID)
+	theirsVIP := testutil.CreateTestContact(t, app.DB, org.ID)
+	mineNoTag := testutil.CreateTestContact(t, app.DB, org.ID)
+	require.NoError(t, app.DB.Model(mineVIP).Updates(map[string]any{"assigned_user_id": user.ID, "tags": models.JSONBArray{"vip"}}).Error)
+	require.NoError(t, app.DB.Model(mineResolved).Updates(map[string]any{
+		"assigned_user_id": user.ID, "tags": models.JSONBArray{"vip"}, "conversation_status": models.ConversationStatusResolved,
+	}).Error)
+	require.NoError(t, app.DB.Model(theirsVIP).Updates(map[string]any{"assigned_user_id": teammate.ID, "tags": models.JSONBArray{"vip"}}).Error)
+	require.NoError(t, app.DB.Model(mineNoTag).Update("assigned_user_id", user.ID).Error)
+
+	status, view := createSavedView(t, app, org.ID, user.ID, map[string]any{
+		"name": "My open VIPs",
+		"filters": map[string]any{
+			"conversation_status": []string{"open"},
+			"tags":                []string{"vip"},
+			"assignment":          "me",
+		},
+	})
+	require.Equal(t, fasthttp.StatusOK, status)
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetPathParam(req, "id", view.ID.String())
+	require.NoError(t, app.ApplySavedView(req))
+	require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data struct {
+			View     handlers.SavedViewResponse `json:"view"`
+			Contacts []handlers.ContactResponse `json:"contacts"`
+			Total    int64                      `json:"total"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	assert.Equal(t, view.ID, resp.Data.View.ID)
+	assert.Equal(t, int64(1), resp.Data.Total)
+	require.Len(t, resp.Data.Contacts, 1)
+	assert.Equal(t, mineVIP.ID, resp.Data.Contacts[0].ID)
+
+	t.Run("other users cannot apply", func(t *testing.T) {
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, teammate.ID)
+		testutil.SetPathParam(req, "id", view.ID.String())
+		require.NoError(t, app.ApplySavedView(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+}
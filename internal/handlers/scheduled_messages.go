@@ -0,0 +1,428 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// Limits for scheduled messages
+const (
+	maxScheduleAhead          = 90 * 24 * time.Hour
+	scheduledMessageBatchSize = 100
+)
+
+// ScheduleMessageRequest schedules a message to a contact. It takes the same
+// message fields as SendMessageRequest plus the time to send it.
+type ScheduleMessageRequest struct {
+	Type    models.MessageType `json:"type"`
+	Content struct {
+		Body string `json:"body"`
+	} `json:"content"`
+	WhatsAppAccount string              `json:"whatsapp_account,omitempty"`
+	Interactive     *InteractiveContent `json:"interactive,omitempty"`
+	SendAt          *time.Time          `json:"send_at"`
+}
+
+// ScheduledMessageResponse represents a scheduled message in API responses
+type ScheduledMessageResponse struct {
+	ID              uuid.UUID                     `json:"id"`
+	ContactID       uuid.UUID                     `json:"contact_id"`
+	CreatedByID     uuid.UUID                     `json:"created_by_id"`
+	WhatsAppAccount string                        `json:"whatsapp_account"`
+	Type            models.MessageType            `json:"type"`
+	Content         map[string]string             `json:"content"`
+	Interactive     *InteractiveContent           `json:"interactive,omitempty"`
+	SendAt          time.Time                     `json:"send_at"`
+	Status          models.ScheduledMessageStatus `json:"status"`
+	SentAt          *time.Time                    `json:"sent_at,omitempty"`
+	MessageID       *uuid.UUID                    `json:"message_id,omitempty"`
+	ErrorMessage    string                        `json:"error_message,omitempty"`
+	CreatedAt       time.Time                     `json:"created_at"`
+}
+
+// ScheduleMessage queues a message to a contact for sending at send_at.
+// Agents can only schedule messages to their assigned contacts.
+func (a *App) ScheduleMessage(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceChat, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	var req ScheduleMessageRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	if req.SendAt == nil || !req.SendAt.After(now) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "send_at must be in the future", nil, "")
+	}
+	if req.SendAt.After(now.Add(maxScheduleAhead)) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "send_at must be within 90 days", nil, "")
+	}
+
+	scheduled := models.ScheduledMessage{
+		OrganizationID:  orgID,
+		ContactID:       contactID,
+		CreatedByID:     userID,
+		WhatsAppAccount: strings.TrimSpace(req.WhatsAppAccount),
+		MessageType:     req.Type,
+		SendAt:          *req.SendAt,
+		Status:          models.ScheduledMessageStatusPending,
+	}
+	switch req.Type {
+	case models.MessageTypeText:
+		if strings.TrimSpace(req.Content.Body) == "" {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "content.body is required", nil, "")
+		}
+		scheduled.Content = req.Content.Body
+	case models.MessageTypeInteractive:
+		if req.Interactive == nil || strings.TrimSpace(req.Interactive.Body) == "" {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "interactive.body is required", nil, "")
+		}
+		scheduled.Content = req.Interactive.Body
+		scheduled.InteractiveData = interactiveToJSONB(req.Interactive)
+	default:
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "type must be one of: text, interactive", nil, "")
+	}
+
+	var contact models.Contact
+	if err := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID).
+		First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	if a.isPhoneBlacklisted(orgID, contact.PhoneNumber) {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Phone number is blacklisted", nil, "")
+	}
+	if contact.OptedOut {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Contact has opted out of messages", nil, "")
+	}
+	if contact.Blocked {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Contact is blocked", nil, "")
+	}
+
+	// Check the account now so a typo isn't discovered at send time. The
+	// account is resolved again when sending, as SendMessage would then.
+	if _, err := a.resolveSendingWhatsAppAccount(orgID, contactAccountName(&contact, scheduled.WhatsAppAccount)); err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	if err := a.DB.Create(&scheduled).Error; err != nil {
+		a.Log.Error("Failed to schedule message", "error", err, "contact_id", contactID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to schedule message", nil, "")
+	}
+
+	a.Log.Info("Message scheduled", "scheduled_message_id", scheduled.ID, "contact_id", contactID,
+		"user_id", userID, "send_at", scheduled.SendAt)
+	return r.SendEnvelope(scheduledMessageToResponse(&scheduled))
+}
+
+// ListScheduledMessages returns a contact's scheduled messages, soonest first.
+// An optional status query parameter filters by status.
+func (a *App) ListScheduledMessages(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceChat, models.ActionRead); err != nil {
+		return nil
+	}
+
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	var contact models.Contact
+	if err := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID).
+		First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	query := a.DB.Model(&models.ScheduledMessage{}).Where("organization_id = ? AND contact_id = ?", orgID, contactID)
+	if status := string(r.RequestCtx.QueryArgs().Peek("status")); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	pg := parsePagination(r)
+
+	var total int64
+	query.Count(&total)
+
+	var scheduled []models.ScheduledMessage
+	if err := pg.Apply(query.Order("send_at ASC")).Find(&scheduled).Error; err != nil {
+		a.Log.Error("Failed to list scheduled messages", "error", err, "contact_id", contactID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list scheduled messages", nil, "")
+	}
+
+	result := make([]ScheduledMessageResponse, len(scheduled))
+	for i := range scheduled {
+		result[i] = scheduledMessageToResponse(&scheduled[i])
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"scheduled_messages": result,
+		"total":              total,
+		"page":               pg.Page,
+		"limit":              pg.Limit,
+	})
+}
+
+// CancelScheduledMessage cancels a scheduled message that hasn't been sent yet
+func (a *App) CancelScheduledMessage(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceChat, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	contactID, err := parsePathUUID(r, "id", "contact")
+	if err != nil {
+		return nil
+	}
+
+	scheduledID, err := parsePathUUID(r, "scheduled_id", "scheduled message")
+	if err != nil {
+		return nil
+	}
+
+	var contact models.Contact
+	if err := a.scopeContactsQuery(a.DB.Where("id = ? AND organization_id = ?", contactID, orgID), userID, orgID).
+		First(&contact).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Contact not found", nil, "")
+	}
+
+	var scheduled models.ScheduledMessage
+	if err := a.DB.Where("id = ? AND organization_id = ? AND contact_id = ?", scheduledID, orgID, contactID).
+		First(&scheduled).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Scheduled message not found", nil, "")
+	}
+
+	// Only cancel while still pending; the dispatcher may be sending it right now
+	result := a.DB.Model(&models.ScheduledMessage{}).
+		Where("id = ? AND status = ?", scheduled.ID, models.ScheduledMessageStatusPending).
+		Update("status", models.ScheduledMessageStatusCancelled)
+	if result.Error != nil {
+		a.Log.Error("Failed to cancel scheduled message", "error", result.Error, "scheduled_message_id", scheduled.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to cancel scheduled message", nil, "")
+	}
+	if result.RowsAffected == 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusConflict, "Only pending scheduled messages can be cancelled", nil, "")
+	}
+
+	a.Log.Info("Scheduled message cancelled", "scheduled_message_id", scheduled.ID, "user_id", userID)
+	scheduled.Status = models.ScheduledMessageStatusCancelled
+	return r.SendEnvelope(scheduledMessageToResponse(&scheduled))
+}
+
+// dispatchScheduledMessages sends pending scheduled messages that are due,
+// oldest first, and returns how many were sent. Messages are claimed with a
+// conditional update, so concurrent runs send each message only once.
+func (a *App) dispatchScheduledMessages(now time.Time) int {
+	var due []models.ScheduledMessage
+	if err := a.DB.Where("status = ? AND send_at <= ?", models.ScheduledMessageStatusPending, now).
+		Order("send_at ASC").
+		Limit(scheduledMessageBatchSize).
+		Find(&due).Error; err != nil {
+		a.Log.Error("Failed to load due scheduled messages", "error", err)
+		return 0
+	}
+
+	sent := 0
+	for i := range due {
+		if a.deferScheduledMessageForQuietHours(&due[i], now) {
+			continue
+		}
+		if a.sendScheduledMessage(&due[i]) {
+			sent++
+		}
+	}
+	return sent
+}
+
+// deferScheduledMessageForQuietHours moves a due message to the end of the
+// quiet window when its account is in quiet hours, reporting whether it did
+func (a *App) deferScheduledMessageForQuietHours(scheduled *models.ScheduledMessage, now time.Time) bool {
+	accountName := scheduled.WhatsAppAccount
+	if accountName == "" {
+		var contact models.Contact
+		if err := a.DB.Select("id", "whats_app_account").Where("id = ?", scheduled.ContactID).First(&contact).Error; err == nil {
+			accountName = contact.WhatsAppAccount
+		}
+	}
+
+	end, quiet := a.quietHoursEnd(scheduled.OrganizationID, accountName, now)
+	if !quiet {
+		return false
+	}
+	if err := a.DB.Model(&models.ScheduledMessage{}).
+		Where("id = ? AND status = ?", scheduled.ID, models.ScheduledMessageStatusPending).
+		Update("send_at", end).Error; err != nil {
+		a.Log.Error("Failed to defer scheduled message", "error", err, "scheduled_message_id", scheduled.ID)
+	}
+	a.Log.Info("Scheduled message deferred for quiet hours", "scheduled_message_id", scheduled.ID, "send_at", end)
+	return true
+}
+
+// sendScheduledMessage claims and sends one scheduled message, recording the
+// outcome on it. It reports whether the message was sent.
+func (a *App) sendScheduledMessage(scheduled *models.ScheduledMessage) bool {
+	claim := a.DB.Model(&models.ScheduledMessage{}).
+		Where("id = ? AND status = ?", scheduled.ID, models.ScheduledMessageStatusPending).
+		Update("status", models.ScheduledMessageStatusSending)
+	if claim.Error != nil {
+		a.Log.Error("Failed to claim scheduled message", "error", claim.Error, "scheduled_message_id", scheduled.ID)
+		return false
+	}
+	if claim.RowsAffected == 0 {
+		// Cancelled or picked up by another run
+		return false
+	}
+
+	message, err := a.deliverScheduledMessage(scheduled)
+	if err == nil && message.Status == models.MessageStatusFailed {
+		err = errors.New(message.ErrorMessage)
+	}
+
+	updates := map[string]any{"status": models.ScheduledMessageStatusSent}
+	if message != nil {
+		updates["message_id"] = message.ID
+	}
+	if err != nil {
+		updates["status"] = models.ScheduledMessageStatusFailed
+		updates["error_message"] = err.Error()
+	} else {
+		updates["sent_at"] = time.Now()
+	}
+	if dbErr := a.DB.Model(scheduled).Updates(updates).Error; dbErr != nil {
+		a.Log.Error("Failed to update scheduled message", "error", dbErr, "scheduled_message_id", scheduled.ID)
+	}
+
+	if err != nil {
+		a.Log.Warn("Scheduled message failed", "error", err, "scheduled_message_id", scheduled.ID, "contact_id", scheduled.ContactID)
+		return false
+	}
+	a.Log.Info("Scheduled message sent", "scheduled_message_id", scheduled.ID, "contact_id", scheduled.ContactID)
+	return true
+}
+
+// deliverScheduledMessage sends a scheduled message the way SendMessage
+// would, on behalf of the user who scheduled it
+func (a *App) deliverScheduledMessage(scheduled *models.ScheduledMessage) (*models.Message, error) {
+	var contact models.Contact
+	if err := a.DB.Where("id = ? AND organization_id = ?", scheduled.ContactID, scheduled.OrganizationID).
+		First(&contact).Error; err != nil {
+		return nil, fmt.Errorf("contact not found")
+	}
+
+	if a.isPhoneBlacklisted(scheduled.OrganizationID, contact.PhoneNumber) {
+		return nil, fmt.Errorf("phone number is blacklisted")
+	}
+	if contact.OptedOut {
+		return nil, errContactOptedOut
+	}
+	if contact.Blocked {
+		return nil, errContactBlocked
+	}
+
+	account, err := a.resolveSendingWhatsAppAccount(scheduled.OrganizationID, contactAccountName(&contact, scheduled.WhatsAppAccount))
+	if err != nil {
+		return nil, err
+	}
+
+	msgReq := OutgoingMessageRequest{
+		Account: account,
+		Contact: &contact,
+		Type:    scheduled.MessageType,
+		Content: scheduled.Content,
+	}
+	if scheduled.MessageType == models.MessageTypeInteractive {
+		if interactive := interactiveFromJSONB(scheduled.InteractiveData); interactive != nil {
+			applyInteractiveContent(&msgReq, interactive)
+		}
+	}
+
+	// Send synchronously so the outcome can be recorded
+	opts := DefaultSendOptions()
+	opts.Async = false
+	opts.SentByUserID = &scheduled.CreatedByID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return a.SendOutgoingMessage(ctx, msgReq, opts)
+}
+
+// scheduledMessageToResponse converts a scheduled message to its API response
+func scheduledMessageToResponse(scheduled *models.ScheduledMessage) ScheduledMessageResponse {
+	resp := ScheduledMessageResponse{
+		ID:              scheduled.ID,
+		ContactID:       scheduled.ContactID,
+		CreatedByID:     scheduled.CreatedByID,
+		WhatsAppAccount: scheduled.WhatsAppAccount,
+		Type:            scheduled.MessageType,
+		Content:         map[string]string{"body": scheduled.Content},
+		SendAt:          scheduled.SendAt,
+		Status:          scheduled.Status,
+		SentAt:          scheduled.SentAt,
+		MessageID:       scheduled.MessageID,
+		ErrorMessage:    scheduled.ErrorMessage,
+		CreatedAt:       scheduled.CreatedAt,
+	}
+	if scheduled.MessageType == models.MessageTypeInteractive {
+		resp.Interactive = interactiveFromJSONB(scheduled.InteractiveData)
+	}
+	return resp
+}
+
+// interactiveToJSONB stores interactive message content in a JSONB column
+func interactiveToJSONB(interactive *InteractiveContent) models.JSONB {
+	data, err := json.Marshal(interactive)
+	if err != nil {
+		return nil
+	}
+	var result models.JSONB
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil
+	}
+	return result
+}
+
+// interactiveFromJSONB reads interactive message content stored by interactiveToJSONB
+func interactiveFromJSONB(data models.JSONB) *InteractiveContent {
+	if len(data) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	var interactive InteractiveContent
+	if err := json.Unmarshal(raw, &interactive); err != nil {
+		return nil
+	}
+	return &interactive
+}
@@ -635,4 +635,151 @@ func TestApp_SendTemplateMessage(t *testing.T) {
 		_, hasInteractive := data["interactive_data"]
 		assert.False(t, hasInteractive, "interactive_data should not be present for template without buttons")
 	})
+	t.Run("unknown template param", func(t *testing.T) {
+		t.Parallel()
+		mockServer := newMockWhatsAppServer()
+		defer mockServer.close()
+
+		app := newMsgTestApp(t, mockServer)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := createTestAccount(t, app, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+		tpl := createTestTemplate(t, app, org.ID, account.Name)
+
+		req := testutil.NewJSONRequest(t, map[string]interface{}{
+			"contact_id":    contact.ID.String(),
+			"template_name": tpl.Name,
+			"template_params": map[string]string{
+				"name":     "Alice",
+				"order_id": "ORD-42",
+				"coupon":   "SAVE10",
+			},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.SendTemplateMessage(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+		assert.Contains(t, string(testutil.GetResponseBody(req)), "coupon")
+		assert.Empty(t, mockServer.sentMessages)
+	})
+
+	t.Run("param given by name and position", func(t *testing.T) {
+		t.Parallel()
+		mockServer := newMockWhatsAppServer()
+		defer mockServer.close()
+
+		app := newMsgTestApp(t, mockServer)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := createTestAccount(t, app, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+		tpl := createTestTemplate(t, app, org.ID, account.Name)
+
+		req := testutil.NewJSONRequest(t, map[string]interface{}{
+			"contact_id":    contact.ID.String(),
+			"template_name": tpl.Name,
+			"template_params": map[string]string{
+				"name":     "Alice",
+				"1":        "Alice",
+				"order_id": "ORD-42",
+			},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.SendTemplateMessage(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("header params and language", func(t *testing.T) {
+		t.Parallel()
+		mockServer := newMockWhatsAppServer()
+		defer mockServer.close()
+
+		app := newMsgTestApp(t, mockServer)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := createTestAccount(t, app, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+		name := "shipping_update_" + uuid.New().String()[:8]
+		for _, lang := range []string{"en", "es"} {
+			require.NoError(t, app.DB.Create(&models.Template{
+				BaseModel:       models.BaseModel{ID: uuid.New()},
+				OrganizationID:  org.ID,
+				WhatsAppAccount: account.Name,
+				Name:            name,
+				Language:        lang,
+				Status:          string(models.TemplateStatusApproved),
+				HeaderType:      "TEXT",
+				HeaderContent:   "Order {{1}}",
+				BodyContent:     "[" + lang + "] Your package ships {{1}}.",
+			}).Error)
+		}
+
+		req := testutil.NewJSONRequest(t, map[string]interface{}{
+			"contact_id":      contact.ID.String(),
+			"template_name":   name,
+			"language":        "es",
+			"template_params": map[string]string{"1": "mañana"},
+			"header_params":   map[string]string{"1": "ORD-7"},
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.SendTemplateMessage(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+		app.WaitForBackgroundTasks()
+
+		require.Len(t, mockServer.sentMessages, 1)
+		template := mockServer.sentMessages[0]["template"].(map[string]interface{})
+		assert.Equal(t, "es", template["language"].(map[string]interface{})["code"])
+		components := template["components"].([]interface{})
+		require.Len(t, components, 2)
+		assert.Equal(t, "header", components[0].(map[string]interface{})["type"])
+		assert.Equal(t, "body", components[1].(map[string]interface{})["type"])
+
+		var dbMsg models.Message
+		require.NoError(t, app.DB.Where("contact_id = ? AND message_type = ?", contact.ID, models.MessageTypeTemplate).First(&dbMsg).Error)
+		assert.Equal(t, "[es] Your package ships mañana.", dbMsg.Content)
+		assert.Equal(t, "Order ORD-7", dbMsg.Metadata["header"])
+		assert.Equal(t, "es", dbMsg.Metadata["template_language"])
+		assert.Equal(t, "mañana", dbMsg.TemplateParams["1"])
+	})
+
+	t.Run("missing header params", func(t *testing.T) {
+		t.Parallel()
+		mockServer := newMockWhatsAppServer()
+		defer mockServer.close()
+
+		app := newMsgTestApp(t, mockServer)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		user := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		account := createTestAccount(t, app, org.ID)
+		contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+		tpl := &models.Template{
+			BaseModel:       models.BaseModel{ID: uuid.New()},
+			OrganizationID:  org.ID,
+			WhatsAppAccount: account.Name,
+			Name:            "header_tpl_" + uuid.New().String()[:8],
+			Language:        "en",
+			Status:          string(models.TemplateStatusApproved),
+			HeaderType:      "TEXT",
+			HeaderContent:   "Order {{1}}",
+			BodyContent:     "Your order is on its way.",
+		}
+		require.NoError(t, app.DB.Create(tpl).Error)
+
+		req := testutil.NewJSONRequest(t, map[string]interface{}{
+			"contact_id":    contact.ID.String(),
+			"template_name": tpl.Name,
+		})
+		testutil.SetAuthContext(req, org.ID, user.ID)
+
+		require.NoError(t, app.SendTemplateMessage(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
 }
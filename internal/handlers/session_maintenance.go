@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// sessionReminderKey is the SessionData key recording when the client
+// inactivity reminder was last sent during the session
+const sessionReminderKey = "_inactivity_reminder_at"
+
+// SessionMaintenanceResult summarizes a session maintenance run
+type SessionMaintenanceResult struct {
+	SessionsClosed int `json:"sessions_closed"`
+	MessagesSent   int `json:"messages_sent"`
+	RemindersSent  int `json:"reminders_sent"`
+}
+
+// RunSessionMaintenance runs the SLA processor's client inactivity handling
+// (reminders and auto-close) for the organization immediately instead of
+// waiting for the next tick
+func (a *App) RunSessionMaintenance(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceSettingsChatbot, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	result := NewSLAProcessor(a, 0).processInactiveClients(time.Now(), &orgID)
+
+	a.Log.Info("Session maintenance run", "org_id", orgID, "user_id", userID,
+		"sessions_closed", result.SessionsClosed, "messages_sent", result.MessagesSent,
+		"reminders_sent", result.RemindersSent)
+
+	return r.SendEnvelope(result)
+}
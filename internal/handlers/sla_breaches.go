@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// SLABreachResponse describes an agent transfer that exceeded the
+// organization's first-response or resolution SLA
+type SLABreachResponse struct {
+	TransferID         uuid.UUID             `json:"transfer_id"`
+	ContactID          uuid.UUID             `json:"contact_id"`
+	ContactName        string                `json:"contact_name"`
+	PhoneNumber        string                `json:"phone_number"`
+	WhatsAppAccount    string                `json:"whatsapp_account"`
+	Status             models.TransferStatus `json:"status"`
+	AgentID            *uuid.UUID            `json:"agent_id,omitempty"`
+	AgentName          *string               `json:"agent_name"` // nil when unassigned
+	TransferredAt      time.Time             `json:"transferred_at"`
+	FirstResponseAt    *time.Time            `json:"first_response_at,omitempty"`
+	ResumedAt          *time.Time            `json:"resumed_at,omitempty"`
+	ResponseMins       float64               `json:"response_mins"`   // Time to first response, or elapsed so far
+	ResolutionMins     float64               `json:"resolution_mins"` // Time to resolution, or elapsed so far
+	ResponseSLAMins    int                   `json:"response_sla_mins"`
+	ResolutionSLAMins  int                   `json:"resolution_sla_mins"`
+	ResponseBreached   bool                  `json:"response_breached"`
+	ResolutionBreached bool                  `json:"resolution_breached"`
+}
+
+// ListSLABreaches returns agent transfers whose first response or resolution
+// took longer than the SLA configured in chatbot settings. Transfers still
+// waiting are measured up to now. Account-specific settings override the
+// organization defaults.
+func (a *App) ListSLABreaches(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceAnalytics, models.ActionRead); err != nil {
+		return nil
+	}
+
+	fromStr := string(r.RequestCtx.QueryArgs().Peek("from"))
+	toStr := string(r.RequestCtx.QueryArgs().Peek("to"))
+	agentIDStr := string(r.RequestCtx.QueryArgs().Peek("agent_id"))
+
+	now := time.Now()
+	var periodStart, periodEnd time.Time
+	if fromStr != "" && toStr != "" {
+		var errMsg string
+		periodStart, periodEnd, errMsg = parseDateRange(fromStr, toStr)
+		if errMsg != "" {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+		}
+	} else {
+		// Default to current month
+		periodStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		periodEnd = now
+	}
+
+	pg := parsePagination(r)
+	response := map[string]any{
+		"breaches": []SLABreachResponse{},
+		"total":    int64(0),
+		"page":     pg.Page,
+		"limit":    pg.Limit,
+	}
+
+	var settings []models.ChatbotSettings
+	if err := a.DB.Where("organization_id = ?", orgID).Find(&settings).Error; err != nil {
+		a.Log.Error("Failed to load SLA settings", "error", err, "org_id", orgID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load SLA breaches", nil, "")
+	}
+	slaByAccount := make(map[string]models.SLAConfig, len(settings))
+	for _, s := range settings {
+		slaByAccount[s.WhatsAppAccount] = s.SLA
+	}
+	breachCond, breachArgs := slaBreachCondition(slaByAccount, now)
+	if breachCond == "" {
+		// SLA tracking is disabled, so nothing can be breached
+		return r.SendEnvelope(response)
+	}
+
+	query := a.DB.Model(&models.AgentTransfer{}).
+		Where("organization_id = ? AND transferred_at >= ? AND transferred_at <= ?", orgID, periodStart, periodEnd).
+		Where(breachCond, breachArgs...)
+	if agentIDStr != "" {
+		agentID, err := uuid.Parse(agentIDStr)
+		if err != nil {
+			return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Invalid agent_id", nil, "")
+		}
+		query = query.Where("agent_id = ?", agentID)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var transfers []models.AgentTransfer
+	if err := pg.Apply(query.Preload("Contact").Preload("Agent", selectAssignedUser).Order("transferred_at DESC")).
+		Find(&transfers).Error; err != nil {
+		a.Log.Error("Failed to list SLA breaches", "error", err, "org_id", orgID)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to load SLA breaches", nil, "")
+	}
+
+	shouldMask := a.ShouldMaskPhoneNumbers(orgID)
+	breaches := make([]SLABreachResponse, 0, len(transfers))
+	for i := range transfers {
+		sla, ok := slaByAccount[transfers[i].WhatsAppAccount]
+		if !ok {
+			sla = slaByAccount[""]
+		}
+		breaches = append(breaches, buildSLABreachResponse(&transfers[i], sla, now, shouldMask))
+	}
+
+	response["breaches"] = breaches
+	response["total"] = total
+	return r.SendEnvelope(response)
+}
+
+// slaBreachCondition builds a WHERE clause matching transfers that breached
+// the SLA for their WhatsApp account, falling back to the organization
+// default (keyed by ""). It returns an empty condition when no SLA applies.
+func slaBreachCondition(slaByAccount map[string]models.SLAConfig, now time.Time) (string, []any) {
+	var conditions []string
+	var args []any
+	var accounts []string
+	for account, sla := range slaByAccount {
+		if account == "" {
+			continue
+		}
+		// Account-specific settings replace the default even with SLA disabled
+		accounts = append(accounts, account)
+		if cond, condArgs := slaExceededCondition(sla, now); cond != "" {
+			conditions = append(conditions, "(whats_app_account = ? AND "+cond+")")
+			args = append(append(args, account), condArgs...)
+		}
+	}
+	if cond, condArgs := slaExceededCondition(slaByAccount[""], now); cond != "" {
+		if len(accounts) > 0 {
+			conditions = append(conditions, "(whats_app_account NOT IN ? AND "+cond+")")
+			args = append(args, accounts)
+		} else {
+			conditions = append(conditions, cond)
+		}
+		args = append(args, condArgs...)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// slaExceededCondition matches transfers whose response or resolution time
+// exceeds the given SLA. Unanswered or unresolved transfers count up to now.
+func slaExceededCondition(sla models.SLAConfig, now time.Time) (string, []any) {
+	if !sla.Enabled {
+		return "", nil
+	}
+	var conditions []string
+	var args []any
+	if sla.ResponseMinutes > 0 {
+		conditions = append(conditions, "COALESCE(first_response_at, resumed_at, ?) - transferred_at > ? * INTERVAL '1 minute'")
+		args = append(args, now, sla.ResponseMinutes)
+	}
+	if sla.ResolutionMinutes > 0 {
+		conditions = append(conditions, "COALESCE(resumed_at, ?) - transferred_at > ? * INTERVAL '1 minute'")
+		args = append(args, now, sla.ResolutionMinutes)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// buildSLABreachResponse reports how long a transfer took against its SLA
+func buildSLABreachResponse(transfer *models.AgentTransfer, sla models.SLAConfig, now time.Time, mask bool) SLABreachResponse {
+	responseEnd := now
+	if transfer.SLA.FirstResponseAt != nil {
+		responseEnd = *transfer.SLA.FirstResponseAt
+	} else if transfer.ResumedAt != nil {
+		responseEnd = *transfer.ResumedAt
+	}
+	resolutionEnd := now
+	if transfer.ResumedAt != nil {
+		resolutionEnd = *transfer.ResumedAt
+	}
+	responseMins := responseEnd.Sub(transfer.TransferredAt).Minutes()
+	resolutionMins := resolutionEnd.Sub(transfer.TransferredAt).Minutes()
+
+	phoneNumber := transfer.PhoneNumber
+	contactName := ""
+	if transfer.Contact != nil {
+		contactName = transfer.Contact.ProfileName
+	}
+	if mask {
+		phoneNumber = MaskPhoneNumber(phoneNumber)
+		contactName = MaskIfPhoneNumber(contactName)
+	}
+
+	var agentName *string
+	if transfer.Agent != nil {
+		agentName = &transfer.Agent.FullName
+	}
+
+	return SLABreachResponse{
+		TransferID:         transfer.ID,
+		ContactID:          transfer.ContactID,
+		ContactName:        contactName,
+		PhoneNumber:        phoneNumber,
+		WhatsAppAccount:    transfer.WhatsAppAccount,
+		Status:             transfer.Status,
+		AgentID:            transfer.AgentID,
+		AgentName:          agentName,
+		TransferredAt:      transfer.TransferredAt,
+		FirstResponseAt:    transfer.SLA.FirstResponseAt,
+		ResumedAt:          transfer.ResumedAt,
+		ResponseMins:       responseMins,
+		ResolutionMins:     resolutionMins,
+		ResponseSLAMins:    sla.ResponseMinutes,
+		ResolutionSLAMins:  sla.ResolutionMinutes,
+		ResponseBreached:   sla.ResponseMinutes > 0 && responseMins > float64(sla.ResponseMinutes),
+		ResolutionBreached: sla.ResolutionMinutes > 0 && resolutionMins > float64(sla.ResolutionMinutes),
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/shridarpatil/whatomate/internal/models"
 	"github.com/shridarpatil/whatomate/internal/websocket"
+	"gorm.io/gorm"
 )
 
 // SLAProcessor handles periodic SLA checks and escalations
@@ -14,6 +15,8 @@ type SLAProcessor struct {
 	app      *App
 	interval time.Duration
 	stopCh   chan struct{}
+
+	lastDeliveryPurge time.Time
 }
 
 // NewSLAProcessor creates a new SLA processor
@@ -42,6 +45,13 @@ func (p *SLAProcessor) Start(ctx context.Context) {
 			return
 		case <-ticker.C:
 			p.processStaleTransfers()
+			p.processSessionTimeoutWarnings(time.Now())
+			p.processInactiveClients(time.Now(), nil)
+			p.app.processFlowStepTimeouts(time.Now())
+			p.reopenSnoozedConversations(time.Now())
+			p.app.dispatchScheduledMessages(time.Now())
+			p.app.requeueDeferredRecipients(time.Now())
+			p.purgeWebhookDeliveries(time.Now())
 		}
 	}
 }
@@ -51,6 +61,19 @@ func (p *SLAProcessor) Stop() {
 	close(p.stopCh)
 }
 
+// webhookDeliveryPurgeInterval is how often old webhook delivery logs are purged
+const webhookDeliveryPurgeInterval = time.Hour
+
+// purgeWebhookDeliveries purges old webhook delivery logs, at most once per
+// webhookDeliveryPurgeInterval
+func (p *SLAProcessor) purgeWebhookDeliveries(now time.Time) {
+	if now.Sub(p.lastDeliveryPurge) < webhookDeliveryPurgeInterval {
+		return
+	}
+	p.lastDeliveryPurge = now
+	p.app.purgeWebhookDeliveries(now)
+}
+
 // processStaleTransfers checks for transfers that need escalation or auto-close
 func (p *SLAProcessor) processStaleTransfers() {
 	now := time.Now()
@@ -85,11 +108,6 @@ func (p *SLAProcessor) processOrganizationSLA(settings models.ChatbotSettings, n
 	if settings.SLA.ResponseMinutes > 0 {
 		p.markSLABreached(orgID, settings, now)
 	}
-
-	// 4. Handle client inactivity (reminders and auto-close)
-	if settings.ClientInactivity.ReminderEnabled {
-		p.processClientInactivity(orgID, settings, now)
-	}
 }
 
 // autoCloseExpiredTransfers closes transfers that have exceeded their expiry time
@@ -103,6 +121,10 @@ func (p *SLAProcessor) autoCloseExpiredTransfers(orgID uuid.UUID, settings model
 		return
 	}
 
+	// Transfers that would message the customer stay open until quiet hours end
+	_, quiet := settings.QuietHours.EndsAt(now)
+	deferClose := quiet && settings.SLA.AutoCloseMessage != ""
+
 	closedCount := 0
 	for _, transfer := range transfers {
 		// Check if the assigned agent has been actively responding.
@@ -125,6 +147,9 @@ func (p *SLAProcessor) autoCloseExpiredTransfers(orgID uuid.UUID, settings model
 			}
 			continue
 		}
+		if deferClose {
+			continue
+		}
 
 		// Send auto-close message to customer if configured
 		if settings.SLA.AutoCloseMessage != "" {
@@ -481,21 +506,54 @@ func (a *App) UpdateSLAOnFirstResponse(transfer *models.AgentTransfer) {
 	transfer.SLA.FirstResponseAt = &now
 }
 
-// processClientInactivity handles client inactivity reminders and auto-close for chatbot conversations only
-func (p *SLAProcessor) processClientInactivity(orgID uuid.UUID, settings models.ChatbotSettings, now time.Time) {
+// processInactiveClients runs client inactivity handling for every settings
+// row with it enabled, independently of SLA. Pass orgID to limit the run to
+// one organization.
+func (p *SLAProcessor) processInactiveClients(now time.Time, orgID *uuid.UUID) SessionMaintenanceResult {
+	var result SessionMaintenanceResult
+
+	query := p.app.DB.Where("client_reminder_enabled = ?", true)
+	if orgID != nil {
+		query = query.Where("organization_id = ?", *orgID)
+	}
+	var settings []models.ChatbotSettings
+	if err := query.Find(&settings).Error; err != nil {
+		p.app.Log.Error("Failed to load client inactivity settings", "error", err)
+		return result
+	}
+
+	for _, s := range settings {
+		r := p.processClientInactivity(s.OrganizationID, s, now)
+		result.SessionsClosed += r.SessionsClosed
+		result.MessagesSent += r.MessagesSent
+		result.RemindersSent += r.RemindersSent
+	}
+	return result
+}
+
+// processClientInactivity handles client inactivity reminders and auto-close
+// for the chatbot conversations covered by one settings row. Conversations
+// are claimed with conditional updates, so concurrent runs close and message
+// each one only once.
+func (p *SLAProcessor) processClientInactivity(orgID uuid.UUID, settings models.ChatbotSettings, now time.Time) SessionMaintenanceResult {
+	var result SessionMaintenanceResult
+
 	// Find contacts where chatbot has sent a message and is waiting for client response
 	var contacts []models.Contact
-	if err := p.app.DB.Where(
-		"organization_id = ? AND chatbot_last_message_at IS NOT NULL",
-		orgID,
-	).Find(&contacts).Error; err != nil {
+	if err := p.app.contactsCoveredBy(settings).
+		Where("chatbot_last_message_at IS NOT NULL").
+		Find(&contacts).Error; err != nil {
 		p.app.Log.Error("Failed to find contacts for client inactivity check", "error", err, "org_id", orgID)
-		return
+		return result
 	}
 
+	// Reminders wait out quiet hours, and so do closes that would message the
+	// client; conversations still idle are handled once the window closes
+	_, quiet := settings.QuietHours.EndsAt(now)
+	deferClose := quiet && settings.ClientInactivity.AutoCloseMessage != ""
 	for _, contact := range contacts {
-		// Skip if contact has an active agent transfer
-		if p.app.hasActiveAgentTransfer(orgID, contact.ID) {
+		// Skip if contact has an active agent transfer or the chatbot is paused for them
+		if p.app.hasActiveAgentTransfer(orgID, contact.ID) || isBotPaused(&contact, now) {
 			continue
 		}
 
@@ -506,32 +564,60 @@ func (p *SLAProcessor) processClientInactivity(orgID uuid.UUID, settings models.
 		if settings.ClientInactivity.AutoCloseMinutes > 0 {
 			autoCloseThreshold := time.Duration(settings.ClientInactivity.AutoCloseMinutes) * time.Minute
 			if timeSinceChatbotMsg >= autoCloseThreshold {
-				p.autoCloseChatbotSession(contact, settings)
+				if deferClose {
+					continue
+				}
+				closed, messaged := p.autoCloseChatbotSession(contact, settings, now)
+				result.SessionsClosed += closed
+				if messaged {
+					result.MessagesSent++
+				}
 				continue
 			}
 		}
 
 		// Check if we should send reminder
-		if settings.ClientInactivity.ReminderMinutes > 0 && !contact.ChatbotReminderSent {
+		if !quiet && settings.ClientInactivity.ReminderMinutes > 0 && !contact.ChatbotReminderSent {
 			reminderThreshold := time.Duration(settings.ClientInactivity.ReminderMinutes) * time.Minute
-			if timeSinceChatbotMsg >= reminderThreshold {
-				p.sendChatbotReminder(contact, settings)
+			if timeSinceChatbotMsg >= reminderThreshold && p.sendChatbotReminder(contact, settings, now) {
+				result.RemindersSent++
 			}
 		}
 	}
+
+	// Sessions can sit idle without the chatbot waiting on the client, e.g.
+	// when the client wrote last; close those past the threshold too
+	if settings.ClientInactivity.AutoCloseMinutes > 0 && !deferClose {
+		p.closeIdleSessions(settings, now, &result)
+	}
+	return result
 }
 
-// sendChatbotReminder sends a reminder message to an inactive client during chatbot conversation
-func (p *SLAProcessor) sendChatbotReminder(contact models.Contact, settings models.ChatbotSettings) {
+// sendChatbotReminder sends a reminder message to an inactive client during
+// chatbot conversation, reporting whether it was sent. The reminder goes out
+// once per idle period: a new chatbot message re-arms it.
+func (p *SLAProcessor) sendChatbotReminder(contact models.Contact, settings models.ChatbotSettings, now time.Time) bool {
 	if settings.ClientInactivity.ReminderMessage == "" {
-		return
+		return false
 	}
 
 	// Get WhatsApp account
 	account, err := p.app.resolveWhatsAppAccount(contact.OrganizationID, contact.WhatsAppAccount)
 	if err != nil {
 		p.app.Log.Error("Failed to load WhatsApp account for chatbot reminder", "error", err)
-		return
+		return false
+	}
+
+	// Mark reminder as sent first so a concurrent run or a slow send doesn't remind twice
+	claim := p.app.DB.Model(&models.Contact{}).
+		Where("id = ? AND chatbot_reminder_sent = ?", contact.ID, false).
+		Update("chatbot_reminder_sent", true)
+	if claim.Error != nil {
+		p.app.Log.Error("Failed to update chatbot_reminder_sent", "error", claim.Error, "contact_id", contact.ID)
+		return false
+	}
+	if claim.RowsAffected == 0 {
+		return false
 	}
 
 	// Send using unified message sender
@@ -547,12 +633,15 @@ func (p *SLAProcessor) sendChatbotReminder(contact models.Contact, settings mode
 
 	if err != nil {
 		p.app.Log.Error("Failed to send chatbot reminder message", "error", err, "phone", contact.PhoneNumber)
-		return
+		return false
 	}
 
-	// Mark reminder as sent
-	if err := p.app.DB.Model(&contact).Update("chatbot_reminder_sent", true).Error; err != nil {
-		p.app.Log.Error("Failed to update chatbot_reminder_sent", "error", err, "contact_id", contact.ID)
+	// Record the reminder in the data of the contact's active sessions
+	if err := p.app.DB.Model(&models.ChatbotSession{}).
+		Where("contact_id = ? AND status = ?", contact.ID, models.SessionStatusActive).
+		Update("session_data", gorm.Expr("jsonb_set(COALESCE(session_data, '{}'::jsonb), '{"+sessionReminderKey+"}', to_jsonb(?::text))",
+			now.UTC().Format(time.RFC3339Nano))).Error; err != nil {
+		p.app.Log.Error("Failed to record inactivity reminder on sessions", "error", err, "contact_id", contact.ID)
 	}
 
 	p.app.Log.Info("Chatbot reminder sent",
@@ -560,51 +649,273 @@ func (p *SLAProcessor) sendChatbotReminder(contact models.Contact, settings mode
 		"phone", contact.PhoneNumber,
 		"inactive_since", contact.ChatbotLastMessageAt,
 	)
+	return true
+}
+
+// autoCloseChatbotSession closes a contact's chatbot conversation due to
+// client inactivity, returning how many sessions it closed and whether the
+// auto-close message was sent
+func (p *SLAProcessor) autoCloseChatbotSession(contact models.Contact, settings models.ChatbotSettings, now time.Time) (int, bool) {
+	// Claim the conversation by clearing its tracking; another run or a new
+	// chatbot message may have got there first
+	claim := p.app.DB.Model(&models.Contact{}).
+		Where("id = ? AND chatbot_last_message_at = ?", contact.ID, contact.ChatbotLastMessageAt).
+		Updates(map[string]any{
+			"chatbot_last_message_at": nil,
+			"chatbot_reminder_sent":   false,
+		})
+	if claim.Error != nil {
+		p.app.Log.Error("Failed to close chatbot session for client inactivity", "error", claim.Error, "contact_id", contact.ID)
+		return 0, false
+	}
+	if claim.RowsAffected == 0 {
+		return 0, false
+	}
+
+	// Close the contact's active sessions too
+	closed := p.app.DB.Model(&models.ChatbotSession{}).
+		Where("contact_id = ? AND status = ?", contact.ID, models.SessionStatusActive).
+		Updates(map[string]any{
+			"status":       models.SessionStatusTimeout,
+			"completed_at": now,
+		})
+	if closed.Error != nil {
+		p.app.Log.Error("Failed to close chatbot sessions for client inactivity", "error", closed.Error, "contact_id", contact.ID)
+	}
+
+	p.app.Log.Info("Chatbot session closed due to client inactivity",
+		"contact_id", contact.ID,
+		"phone", contact.PhoneNumber,
+		"inactive_since", contact.ChatbotLastMessageAt,
+	)
+
+	messaged := p.sendClientAutoCloseMessage(contact, contact.WhatsAppAccount, settings.ClientInactivity.AutoCloseMessage)
+	return int(closed.RowsAffected), messaged
 }
 
-// autoCloseChatbotSession closes a chatbot session due to client inactivity
-func (p *SLAProcessor) autoCloseChatbotSession(contact models.Contact, settings models.ChatbotSettings) {
-	// Save the timestamp before clearing for logging
-	var inactiveSince time.Time
-	if contact.ChatbotLastMessageAt != nil {
-		inactiveSince = *contact.ChatbotLastMessageAt
+// closeIdleSessions closes the active sessions covered by the settings that
+// have been idle past the client auto-close threshold
+func (p *SLAProcessor) closeIdleSessions(settings models.ChatbotSettings, now time.Time, result *SessionMaintenanceResult) {
+	cutoff := now.Add(-time.Duration(settings.ClientInactivity.AutoCloseMinutes) * time.Minute)
+
+	var sessions []models.ChatbotSession
+	if err := p.app.sessionsCoveredBy(settings).
+		Where("status = ? AND last_activity_at <= ?", models.SessionStatusActive, cutoff).
+		Find(&sessions).Error; err != nil {
+		p.app.Log.Error("Failed to find inactive sessions", "error", err, "org_id", settings.OrganizationID)
+		return
 	}
 
-	// Send auto-close message if configured
-	if settings.ClientInactivity.AutoCloseMessage != "" {
-		if account, err := p.app.resolveWhatsAppAccount(contact.OrganizationID, contact.WhatsAppAccount); err == nil {
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			defer cancel()
+	for _, session := range sessions {
+		// Claim the session; another run or a new reply may have got there first
+		claim := p.app.DB.Model(&models.ChatbotSession{}).
+			Where("id = ? AND status = ? AND last_activity_at <= ?", session.ID, models.SessionStatusActive, cutoff).
+			Updates(map[string]any{
+				"status":       models.SessionStatusTimeout,
+				"completed_at": now,
+			})
+		if claim.Error != nil {
+			p.app.Log.Error("Failed to close inactive session", "error", claim.Error, "session_id", session.ID)
+			continue
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+		result.SessionsClosed++
 
-			_, err := p.app.SendOutgoingMessage(ctx, OutgoingMessageRequest{
-				Account: account,
-				Contact: &contact,
-				Type:    models.MessageTypeText,
-				Content: settings.ClientInactivity.AutoCloseMessage,
-			}, SLASendOptions())
+		p.app.Log.Info("Chatbot session closed due to inactivity",
+			"session_id", session.ID,
+			"contact_id", session.ContactID,
+			"org_id", session.OrganizationID,
+			"last_activity_at", session.LastActivityAt,
+		)
 
-			if err != nil {
-				p.app.Log.Error("Failed to send chatbot auto-close message", "error", err, "phone", contact.PhoneNumber)
-			}
+		var contact models.Contact
+		if err := p.app.DB.Where("id = ? AND organization_id = ?", session.ContactID, session.OrganizationID).First(&contact).Error; err != nil {
+			p.app.Log.Error("Failed to load contact for closed session", "error", err, "session_id", session.ID)
+			continue
+		}
+
+		// Reset the contact's inactivity tracking so the conversation isn't closed again
+		p.app.ClearContactChatbotTracking(contact.ID)
+
+		if isBotPaused(&contact, now) || p.app.hasActiveAgentTransfer(session.OrganizationID, session.ContactID) {
+			continue
 		}
+		if p.sendClientAutoCloseMessage(contact, session.WhatsAppAccount, settings.ClientInactivity.AutoCloseMessage) {
+			result.MessagesSent++
+		}
+	}
+}
+
+// sendClientAutoCloseMessage sends the client inactivity auto-close message,
+// if one is configured, reporting whether it was sent
+func (p *SLAProcessor) sendClientAutoCloseMessage(contact models.Contact, accountName, message string) bool {
+	if message == "" {
+		return false
+	}
+
+	account, err := p.app.resolveWhatsAppAccount(contact.OrganizationID, accountName)
+	if err != nil {
+		p.app.Log.Error("Failed to load WhatsApp account for chatbot auto-close message", "error", err)
+		return false
 	}
 
-	// Clear chatbot tracking fields to close the session
-	if err := p.app.DB.Model(&contact).Updates(map[string]any{
-		"chatbot_last_message_at": nil,
-		"chatbot_reminder_sent":   false,
-	}).Error; err != nil {
-		p.app.Log.Error("Failed to close chatbot session for client inactivity", "error", err, "contact_id", contact.ID)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := p.app.SendOutgoingMessage(ctx, OutgoingMessageRequest{
+		Account: account,
+		Contact: &contact,
+		Type:    models.MessageTypeText,
+		Content: message,
+	}, SLASendOptions()); err != nil {
+		p.app.Log.Error("Failed to send chatbot auto-close message", "error", err, "phone", contact.PhoneNumber)
+		return false
+	}
+	return true
+}
+
+// processSessionTimeoutWarnings warns users whose chatbot session is about to time out
+func (p *SLAProcessor) processSessionTimeoutWarnings(now time.Time) {
+	var settings []models.ChatbotSettings
+	if err := p.app.DB.Where("is_enabled = ? AND session_warning_percent > 0 AND session_warning_message <> ''", true).
+		Find(&settings).Error; err != nil {
+		p.app.Log.Error("Failed to load session warning settings", "error", err)
 		return
 	}
 
-	p.app.Log.Info("Chatbot session closed due to client inactivity",
+	for _, s := range settings {
+		p.warnExpiringSessions(s, now)
+	}
+}
+
+// warnExpiringSessions sends the pre-timeout warning for sessions covered by the given settings
+func (p *SLAProcessor) warnExpiringSessions(settings models.ChatbotSettings, now time.Time) {
+	if settings.SessionTimeoutMins <= 0 || settings.SessionWarningPercent <= 0 || settings.SessionWarningPercent >= 100 {
+		return
+	}
+	if _, quiet := settings.QuietHours.EndsAt(now); quiet {
+		return
+	}
+
+	timeout := time.Duration(settings.SessionTimeoutMins) * time.Minute
+	warnAfter := timeout * time.Duration(settings.SessionWarningPercent) / 100
+
+	// Sessions idle past the warning threshold but not yet timed out
+	query := p.app.sessionsCoveredBy(settings).Where(
+		"status = ? AND warning_sent_at IS NULL AND last_activity_at <= ? AND last_activity_at > ?",
+		models.SessionStatusActive, now.Add(-warnAfter), now.Add(-timeout),
+	)
+
+	var sessions []models.ChatbotSession
+	if err := query.Find(&sessions).Error; err != nil {
+		p.app.Log.Error("Failed to find sessions for timeout warning", "error", err, "org_id", settings.OrganizationID)
+		return
+	}
+
+	for _, session := range sessions {
+		p.sendSessionTimeoutWarning(session, settings.SessionWarningMessage, now)
+	}
+}
+
+// sessionsCoveredBy returns a chatbot session query limited to the sessions
+// the given settings apply to
+func (a *App) sessionsCoveredBy(settings models.ChatbotSettings) *gorm.DB {
+	return a.coveredBySettings(a.DB.Model(&models.ChatbotSession{}), settings)
+}
+
+// contactsCoveredBy returns a contact query limited to the contacts the given
+// settings apply to
+func (a *App) contactsCoveredBy(settings models.ChatbotSettings) *gorm.DB {
+	return a.coveredBySettings(a.DB.Model(&models.Contact{}), settings)
+}
+
+// coveredBySettings limits a query on a table with organization_id and
+// whats_app_account columns to the rows the given settings apply to
+func (a *App) coveredBySettings(query *gorm.DB, settings models.ChatbotSettings) *gorm.DB {
+	query = query.Where("organization_id = ?", settings.OrganizationID)
+	if settings.WhatsAppAccount != "" {
+		return query.Where("whats_app_account = ?", settings.WhatsAppAccount)
+	}
+	// Org-level defaults don't apply to accounts with their own settings
+	return query.Where("COALESCE(whats_app_account, '') NOT IN (?)",
+		a.DB.Model(&models.ChatbotSettings{}).
+			Select("whats_app_account").
+			Where("organization_id = ? AND whats_app_account <> ''", settings.OrganizationID))
+}
+
+// sendSessionTimeoutWarning sends the timeout warning to the session's contact and marks it as sent
+func (p *SLAProcessor) sendSessionTimeoutWarning(session models.ChatbotSession, message string, now time.Time) {
+	// Skip if an agent has taken over the conversation
+	if p.app.hasActiveAgentTransfer(session.OrganizationID, session.ContactID) {
+		return
+	}
+
+	var contact models.Contact
+	if err := p.app.DB.Where("id = ? AND organization_id = ?", session.ContactID, session.OrganizationID).First(&contact).Error; err != nil {
+		p.app.Log.Error("Failed to load contact for session warning", "error", err, "session_id", session.ID)
+		return
+	}
+	if isBotPaused(&contact, now) {
+		return
+	}
+
+	account, err := p.app.resolveWhatsAppAccount(session.OrganizationID, session.WhatsAppAccount)
+	if err != nil {
+		p.app.Log.Error("Failed to load WhatsApp account for session warning", "error", err)
+		return
+	}
+
+	// Mark as sent first so a slow or failed send isn't retried every tick
+	result := p.app.DB.Model(&models.ChatbotSession{}).
+		Where("id = ? AND warning_sent_at IS NULL", session.ID).
+		Update("warning_sent_at", now)
+	if result.Error != nil {
+		p.app.Log.Error("Failed to update warning_sent_at", "error", result.Error, "session_id", session.ID)
+		return
+	}
+	if result.RowsAffected == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := p.app.SendOutgoingMessage(ctx, OutgoingMessageRequest{
+		Account: account,
+		Contact: &contact,
+		Type:    models.MessageTypeText,
+		Content: message,
+	}, SLASendOptions()); err != nil {
+		p.app.Log.Error("Failed to send session timeout warning", "error", err, "phone", contact.PhoneNumber)
+		return
+	}
+
+	p.app.Log.Info("Session timeout warning sent",
+		"session_id", session.ID,
 		"contact_id", contact.ID,
-		"phone", contact.PhoneNumber,
-		"inactive_since", inactiveSince,
+		"last_activity_at", session.LastActivityAt,
 	)
 }
 
+// reopenSnoozedConversations reopens conversations whose snooze has run out
+func (p *SLAProcessor) reopenSnoozedConversations(now time.Time) {
+	result := p.app.DB.Model(&models.Contact{}).
+		Where("conversation_status = ? AND snooze_until <= ?", models.ConversationStatusSnoozed, now).
+		Updates(map[string]any{
+			"conversation_status": models.ConversationStatusOpen,
+			"snooze_until":        nil,
+		})
+	if result.Error != nil {
+		p.app.Log.Error("Failed to reopen snoozed conversations", "error", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		p.app.Log.Info("Reopened snoozed conversations", "count", result.RowsAffected)
+	}
+}
+
 // UpdateContactChatbotMessage updates the chatbot last message timestamp for a contact
 func (a *App) UpdateContactChatbotMessage(contactID uuid.UUID) {
 	now := time.Now()
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/contactutil"
 	"github.com/shridarpatil/whatomate/internal/models"
 	"github.com/shridarpatil/whatomate/internal/websocket"
 	"github.com/shridarpatil/whatomate/test/testutil"
@@ -282,3 +283,466 @@ func TestSLAEscalationFiresWhenNoAgentResponse(t *testing.T) {
 	assert.Equal(t, 1, updated.SLA.EscalationLevel, "escalation level should increase to 1")
 	require.NotNil(t, updated.SLA.EscalatedAt)
 }
+
+// --- processSessionTimeoutWarnings ---
+
+// createWarningTestSession creates an active chatbot session last active at the given time.
+func createWarningTestSession(t *testing.T, app *App, orgID uuid.UUID, contact *models.Contact, accountName string, lastActivity time.Time) *models.ChatbotSession {
+	t.Helper()
+	session := &models.ChatbotSession{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  orgID,
+		ContactID:       contact.ID,
+		WhatsAppAccount: accountName,
+		PhoneNumber:     contact.PhoneNumber,
+		Status:          models.SessionStatusActive,
+		SessionData:     models.JSONB{},
+		LastActivityAt:  lastActivity,
+	}
+	require.NoError(t, app.DB.Create(session).Error)
+	return session
+}
+
+// createWarningTestSettings creates enabled chatbot settings with a session timeout warning.
+func createWarningTestSettings(t *testing.T, app *App, orgID uuid.UUID) {
+	t.Helper()
+	settings := &models.ChatbotSettings{
+		BaseModel:             models.BaseModel{ID: uuid.New()},
+		OrganizationID:        orgID,
+		IsEnabled:             true,
+		SessionTimeoutMins:    30,
+		SessionWarningPercent: 80,
+		SessionWarningMessage: "Are you still there? This chat will close soon.",
+	}
+	require.NoError(t, app.DB.Create(settings).Error)
+}
+
+func countWarningMessages(t *testing.T, app *App, contactID uuid.UUID) int64 {
+	t.Helper()
+	var count int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("contact_id = ? AND direction = ? AND content = ?", contactID, models.DirectionOutgoing,
+			"Are you still there? This chat will close soon.").
+		Count(&count).Error)
+	return count
+}
+
+func TestSessionTimeoutWarning_SentNearTimeout(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	createWarningTestSettings(t, app, org.ID)
+
+	// 25 of 30 minutes idle: past the 80% (24 min) threshold
+	session := createWarningTestSession(t, app, org.ID, contact, account.Name, time.Now().Add(-25*time.Minute))
+
+	proc := NewSLAProcessor(app, time.Minute)
+	proc.processSessionTimeoutWarnings(time.Now())
+
+	var updated models.ChatbotSession
+	require.NoError(t, app.DB.Where("id = ?", session.ID).First(&updated).Error)
+	assert.NotNil(t, updated.WarningSentAt, "warning_sent_at should be set")
+	assert.Equal(t, int64(1), countWarningMessages(t, app, contact.ID))
+
+	// A second pass must not warn again
+	proc.processSessionTimeoutWarnings(time.Now())
+	assert.Equal(t, int64(1), countWarningMessages(t, app, contact.ID))
+}
+
+func TestSessionTimeoutWarning_NotSentBeforeThreshold(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	createWarningTestSettings(t, app, org.ID)
+
+	session := createWarningTestSession(t, app, org.ID, contact, account.Name, time.Now().Add(-10*time.Minute))
+
+	proc := NewSLAProcessor(app, time.Minute)
+	proc.processSessionTimeoutWarnings(time.Now())
+
+	var updated models.ChatbotSession
+	require.NoError(t, app.DB.Where("id = ?", session.ID).First(&updated).Error)
+	assert.Nil(t, updated.WarningSentAt)
+	assert.Equal(t, int64(0), countWarningMessages(t, app, contact.ID))
+}
+
+func TestSessionTimeoutWarning_SuppressedAfterReply(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	createWarningTestSettings(t, app, org.ID)
+
+	session := createWarningTestSession(t, app, org.ID, contact, account.Name, time.Now().Add(-25*time.Minute))
+
+	// User replies just before the processor runs
+	reused, isNew := app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30, 0)
+	require.False(t, isNew)
+	require.Equal(t, session.ID, reused.ID)
+
+	proc := NewSLAProcessor(app, time.Minute)
+	proc.processSessionTimeoutWarnings(time.Now())
+
+	var updated models.ChatbotSession
+	require.NoError(t, app.DB.Where("id = ?", session.ID).First(&updated).Error)
+	assert.Nil(t, updated.WarningSentAt)
+	assert.Equal(t, int64(0), countWarningMessages(t, app, contact.ID))
+}
+
+func TestSessionTimeoutWarning_ReplyRearmsWarning(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	createWarningTestSettings(t, app, org.ID)
+
+	session := createWarningTestSession(t, app, org.ID, contact, account.Name, time.Now().Add(-25*time.Minute))
+	warnedAt := time.Now().Add(-time.Minute)
+	require.NoError(t, app.DB.Model(session).Update("warning_sent_at", warnedAt).Error)
+
+	app.getOrCreateSession(org.ID, contact.ID, account.Name, contact.PhoneNumber, 30, 0)
+
+	var updated models.ChatbotSession
+	require.NoError(t, app.DB.Where("id = ?", session.ID).First(&updated).Error)
+	assert.Nil(t, updated.WarningSentAt, "reply should clear warning_sent_at")
+}
+
+// --- reopenSnoozedConversations ---
+
+func TestReopenSnoozedConversations_ReopensExpiredSnoozes(t *testing.T) {
+	app := newSLATestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	expired := testutil.CreateTestContact(t, app.DB, org.ID)
+	active := testutil.CreateTestContact(t, app.DB, org.ID)
+
+	now := time.Now()
+	require.NoError(t, app.DB.Model(expired).Updates(map[string]any{
+		"conversation_status": models.ConversationStatusSnoozed,
+		"snooze_until":        now.Add(-time.Minute),
+	}).Error)
+	require.NoError(t, app.DB.Model(active).Updates(map[string]any{
+		"conversation_status": models.ConversationStatusSnoozed,
+		"snooze_until":        now.Add(time.Hour),
+	}).Error)
+
+	proc := NewSLAProcessor(app, time.Minute)
+	proc.reopenSnoozedConversations(now)
+
+	var reopened models.Contact
+	require.NoError(t, app.DB.First(&reopened, expired.ID).Error)
+	assert.Equal(t, models.ConversationStatusOpen, reopened.ConversationStatus)
+	assert.Nil(t, reopened.SnoozeUntil)
+
+	var stillSnoozed models.Contact
+	require.NoError(t, app.DB.First(&stillSnoozed, active.ID).Error)
+	assert.Equal(t, models.ConversationStatusSnoozed, stillSnoozed.ConversationStatus)
+	assert.NotNil(t, stillSnoozed.SnoozeUntil)
+}
+
+// --- processInactiveClients ---
+
+// createAutoCloseTestSettings creates chatbot settings that close sessions idle for autoCloseMins.
+func createAutoCloseTestSettings(t *testing.T, app *App, orgID uuid.UUID, autoCloseMins int) {
+	t.Helper()
+	settings := &models.ChatbotSettings{
+		BaseModel:          models.BaseModel{ID: uuid.New()},
+		OrganizationID:     orgID,
+		IsEnabled:          true,
+		SessionTimeoutMins: 30,
+		ClientInactivity: models.ClientInactivityConfig{
+			ReminderEnabled:  true,
+			ReminderMinutes:  15,
+			AutoCloseMinutes: autoCloseMins,
+			AutoCloseMessage: "Closing this chat due to inactivity.",
+		},
+	}
+	require.NoError(t, app.DB.Create(settings).Error)
+}
+
+func countAutoCloseMessages(t *testing.T, app *App, contactID uuid.UUID) int64 {
+	t.Helper()
+	var count int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("contact_id = ? AND direction = ? AND content = ?", contactID, models.DirectionOutgoing,
+			"Closing this chat due to inactivity.").
+		Count(&count).Error)
+	return count
+}
+
+func TestCloseInactiveSessions_ClosesIdleSessionsOnce(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	createAutoCloseTestSettings(t, app, org.ID, 60)
+
+	idleContact := testutil.CreateTestContact(t, app.DB, org.ID)
+	idle := createWarningTestSession(t, app, org.ID, idleContact, account.Name, time.Now().Add(-90*time.Minute))
+	freshContact := testutil.CreateTestContact(t, app.DB, org.ID)
+	fresh := createWarningTestSession(t, app, org.ID, freshContact, account.Name, time.Now().Add(-10*time.Minute))
+
+	result := NewSLAProcessor(app, time.Minute).processInactiveClients(time.Now(), nil)
+	assert.Equal(t, 1, result.SessionsClosed)
+	assert.Equal(t, 1, result.MessagesSent)
+
+	var closed models.ChatbotSession
+	require.NoError(t, app.DB.Where("id = ?", idle.ID).First(&closed).Error)
+	assert.Equal(t, models.SessionStatusTimeout, closed.Status)
+	assert.NotNil(t, closed.CompletedAt)
+	assert.Equal(t, int64(1), countAutoCloseMessages(t, app, idleContact.ID))
+
+	var stillActive models.ChatbotSession
+	require.NoError(t, app.DB.Where("id = ?", fresh.ID).First(&stillActive).Error)
+	assert.Equal(t, models.SessionStatusActive, stillActive.Status)
+
+	// A second run finds nothing left to close
+	result = NewSLAProcessor(app, time.Minute).processInactiveClients(time.Now(), nil)
+	assert.Equal(t, 0, result.SessionsClosed)
+	assert.Equal(t, int64(1), countAutoCloseMessages(t, app, idleContact.ID))
+}
+
+func TestCloseInactiveSessions_UsesEachOrgsThreshold(t *testing.T) {
+	app := newProcessorTestApp(t)
+	strictOrg, strictAccount := createProcessorTestOrg(t, app)
+	relaxedOrg, relaxedAccount := createProcessorTestOrg(t, app)
+	createAutoCloseTestSettings(t, app, strictOrg.ID, 60)
+	createAutoCloseTestSettings(t, app, relaxedOrg.ID, 180)
+
+	idleFor := time.Now().Add(-90 * time.Minute)
+	strictContact := testutil.CreateTestContact(t, app.DB, strictOrg.ID)
+	strictSession := createWarningTestSession(t, app, strictOrg.ID, strictContact, strictAccount.Name, idleFor)
+	relaxedContact := testutil.CreateTestContact(t, app.DB, relaxedOrg.ID)
+	relaxedSession := createWarningTestSession(t, app, relaxedOrg.ID, relaxedContact, relaxedAccount.Name, idleFor)
+
+	NewSLAProcessor(app, time.Minute).processInactiveClients(time.Now(), nil)
+
+	var strict, relaxed models.ChatbotSession
+	require.NoError(t, app.DB.Where("id = ?", strictSession.ID).First(&strict).Error)
+	require.NoError(t, app.DB.Where("id = ?", relaxedSession.ID).First(&relaxed).Error)
+	assert.Equal(t, models.SessionStatusTimeout, strict.Status)
+	assert.Equal(t, models.SessionStatusActive, relaxed.Status)
+}
+
+func TestCloseInactiveSessions_LimitedToOrg(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	otherOrg, otherAccount := createProcessorTestOrg(t, app)
+	createAutoCloseTestSettings(t, app, org.ID, 60)
+	createAutoCloseTestSettings(t, app, otherOrg.ID, 60)
+
+	idleFor := time.Now().Add(-90 * time.Minute)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	createWarningTestSession(t, app, org.ID, contact, account.Name, idleFor)
+	otherContact := testutil.CreateTestContact(t, app.DB, otherOrg.ID)
+	otherSession := createWarningTestSession(t, app, otherOrg.ID, otherContact, otherAccount.Name, idleFor)
+
+	result := NewSLAProcessor(app, time.Minute).processInactiveClients(time.Now(), &org.ID)
+	assert.Equal(t, 1, result.SessionsClosed)
+
+	var untouched models.ChatbotSession
+	require.NoError(t, app.DB.Where("id = ?", otherSession.ID).First(&untouched).Error)
+	assert.Equal(t, models.SessionStatusActive, untouched.Status)
+}
+
+func countReminderMessages(t *testing.T, app *App, contactID uuid.UUID) int64 {
+	t.Helper()
+	var count int64
+	require.NoError(t, app.DB.Model(&models.Message{}).
+		Where("contact_id = ? AND direction = ? AND content = ?", contactID, models.DirectionOutgoing, "Still there?").
+		Count(&count).Error)
+	return count
+}
+
+func TestProcessInactiveClients_RemindsOncePerIdlePeriod(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	require.NoError(t, app.DB.Create(&models.ChatbotSettings{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		IsEnabled:      true,
+		ClientInactivity: models.ClientInactivityConfig{
+			ReminderEnabled:  true,
+			ReminderMinutes:  15,
+			ReminderMessage:  "Still there?",
+			AutoCloseMinutes: 60,
+		},
+	}).Error)
+
+	now := time.Now()
+	// The chatbot is waiting on the contact's reply
+	waitingSince := func(contact *models.Contact, at time.Time) {
+		require.NoError(t, app.DB.Model(contact).Updates(map[string]any{
+			"chatbot_last_message_at": at,
+			"chatbot_reminder_sent":   false,
+		}).Error)
+	}
+
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	session := createWarningTestSession(t, app, org.ID, contact, account.Name, now.Add(-20*time.Minute))
+	waitingSince(contact, now.Add(-20*time.Minute))
+	dueForClose := testutil.CreateTestContact(t, app.DB, org.ID)
+	createWarningTestSession(t, app, org.ID, dueForClose, account.Name, now.Add(-90*time.Minute))
+	waitingSince(dueForClose, now.Add(-90*time.Minute))
+
+	proc := NewSLAProcessor(app, time.Minute)
+	result := proc.processInactiveClients(now, nil)
+	assert.Equal(t, 1, result.RemindersSent)
+	assert.Equal(t, 1, result.SessionsClosed)
+	assert.Equal(t, int64(1), countReminderMessages(t, app, contact.ID))
+	assert.Equal(t, int64(0), countReminderMessages(t, app, dueForClose.ID), "conversations due for auto-close are closed, not reminded")
+
+	var reminded models.ChatbotSession
+	require.NoError(t, app.DB.Where("id = ?", session.ID).First(&reminded).Error)
+	assert.NotEmpty(t, reminded.SessionData[sessionReminderKey])
+	assert.Equal(t, models.SessionStatusActive, reminded.Status)
+
+	// Still idle: no second reminder
+	result = proc.processInactiveClients(now.Add(5*time.Minute), nil)
+	assert.Equal(t, 0, result.RemindersSent)
+	assert.Equal(t, int64(1), countReminderMessages(t, app, contact.ID))
+
+	// The user replies, the chatbot answers, then the user goes idle again
+	app.ClearContactChatbotTracking(contact.ID)
+	require.NoError(t, app.DB.Model(&models.ChatbotSession{}).Where("id = ?", session.ID).
+		Update("last_activity_at", now.Add(time.Minute)).Error)
+	waitingSince(contact, now.Add(time.Minute))
+
+	result = proc.processInactiveClients(now.Add(20*time.Minute), nil)
+	assert.Equal(t, 1, result.RemindersSent)
+	assert.Equal(t, int64(2), countReminderMessages(t, app, contact.ID))
+}
+
+func createTestScheduledMessage(t *testing.T, app *App, orgID, contactID, userID uuid.UUID, body string, sendAt time.Time) *models.ScheduledMessage {
+	t.Helper()
+	scheduled := &models.ScheduledMessage{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: orgID,
+		ContactID:      contactID,
+		CreatedByID:    userID,
+		MessageType:    models.MessageTypeText,
+		Content:        body,
+		SendAt:         sendAt,
+		Status:         models.ScheduledMessageStatusPending,
+	}
+	require.NoError(t, app.DB.Create(scheduled).Error)
+	return scheduled
+}
+
+func TestDispatchScheduledMessages_SendsDueMessagesOnce(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, account := createProcessorTestOrg(t, app)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	contact := testutil.CreateTestContactWith(t, app.DB, org.ID, testutil.WithContactAccount(account.Name))
+
+	now := time.Now()
+	due := createTestScheduledMessage(t, app, org.ID, contact.ID, user.ID, "Your order has shipped", now.Add(-time.Minute))
+	future := createTestScheduledMessage(t, app, org.ID, contact.ID, user.ID, "Later", now.Add(time.Hour))
+	cancelled := createTestScheduledMessage(t, app, org.ID, contact.ID, user.ID, "Never", now.Add(-time.Minute))
+	require.NoError(t, app.DB.Model(cancelled).Update("status", models.ScheduledMessageStatusCancelled).Error)
+
+	assert.Equal(t, 1, app.dispatchScheduledMessages(now))
+
+	var sent models.ScheduledMessage
+	require.NoError(t, app.DB.Where("id = ?", due.ID).First(&sent).Error)
+	assert.Equal(t, models.ScheduledMessageStatusSent, sent.Status)
+	require.NotNil(t, sent.SentAt)
+	require.NotNil(t, sent.MessageID)
+
+	var message models.Message
+	require.NoError(t, app.DB.Where("id = ?", *sent.MessageID).First(&message).Error)
+	assert.Equal(t, "Your order has shipped", message.Content)
+	assert.Equal(t, account.Name, message.WhatsAppAccount)
+	require.NotNil(t, message.SentByUserID)
+	assert.Equal(t, user.ID, *message.SentByUserID)
+
+	var pending models.ScheduledMessage
+	require.NoError(t, app.DB.Where("id = ?", future.ID).First(&pending).Error)
+	assert.Equal(t, models.ScheduledMessageStatusPending, pending.Status)
+
+	// A second run must not resend
+	assert.Equal(t, 0, app.dispatchScheduledMessages(now.Add(time.Minute)))
+	var count int64
+	require.NoError(t, app.DB.Model(&models.Message{}).Where("contact_id = ?", contact.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestDispatchScheduledMessages_BlacklistedContactFails(t *testing.T) {
+	app := newProcessorTestApp(t)
+	org, _ := createProcessorTestOrg(t, app)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	contact := testutil.CreateTestContact(t, app.DB, org.ID)
+	scheduled := createTestScheduledMessage(t, app, org.ID, contact.ID, user.ID, "Hello", time.Now().Add(-time.Minute))
+
+	// Blacklisted after scheduling
+	require.NoError(t, app.DB.Create(&models.PhoneBlacklist{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: org.ID,
+		PhoneNumber:    contactutil.NormalizePhone(contact.PhoneNumber),
+	}).Error)
+
+	assert.Equal(t, 0, app.dispatchScheduledMessages(time.Now()))
+
+	var failed models.ScheduledMessage
+	require.NoError(t, app.DB.Where("id = ?", scheduled.ID).First(&failed).Error)
+	assert.Equal(t, models.ScheduledMessageStatusFailed, failed.Status)
+	assert.Equal(t, "phone number is blacklisted", failed.ErrorMessage)
+	assert.Nil(t, failed.MessageID)
+}
+
+func TestPurgeWebhookDeliveries_DeletesPastRetention(t *testing.T) {
+	app := newProcessorTestApp(t)
+	app.Config.App.WebhookDeliveryRetentionDays = 30
+	org, _ := createProcessorTestOrg(t, app)
+	now := time.Now()
+
+	newDelivery := func(createdAt time.Time) *models.WebhookDelivery {
+		delivery := &models.WebhookDelivery{
+			BaseModel:      models.BaseModel{ID: uuid.New(), CreatedAt: createdAt},
+			OrganizationID: org.ID,
+			WebhookID:      uuid.New(),
+			Event:          string(models.WebhookEventMessageIncoming),
+			Payload:        `{"event":"message.incoming"}`,
+			Status:         models.WebhookDeliveryDelivered,
+		}
+		require.NoError(t, app.DB.Create(delivery).Error)
+		return delivery
+	}
+	old := newDelivery(now.AddDate(0, 0, -31))
+	recent := newDelivery(now.AddDate(0, 0, -29))
+
+	assert.Equal(t, int64(1), app.purgeWebhookDeliveries(now))
+
+	var remaining []models.WebhookDelivery
+	require.NoError(t, app.DB.Unscoped().Where("id IN ?", []uuid.UUID{old.ID, recent.ID}).Find(&remaining).Error)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, recent.ID, remaining[0].ID)
+
+	// A negative retention keeps everything
+	app.Config.App.WebhookDeliveryRetentionDays = -1
+	newDelivery(now.AddDate(-1, 0, 0))
+	assert.Zero(t, app.purgeWebhookDeliveries(now))
+}
+
+func TestSLAProcessor_PurgesWebhookDeliveriesHourly(t *testing.T) {
+	app := newProcessorTestApp(t)
+	app.Config.App.WebhookDeliveryRetentionDays = 1
+	org, _ := createProcessorTestOrg(t, app)
+	p := NewSLAProcessor(app, time.Minute)
+	now := time.Now()
+
+	p.purgeWebhookDeliveries(now)
+	require.NoError(t, app.DB.Create(&models.WebhookDelivery{
+		BaseModel:      models.BaseModel{ID: uuid.New(), CreatedAt: now.AddDate(0, 0, -2)},
+		OrganizationID: org.ID,
+		WebhookID:      uuid.New(),
+		Status:         models.WebhookDeliveryDelivered,
+	}).Error)
+
+	countDeliveries := func() int64 {
+		var count int64
+		require.NoError(t, app.DB.Model(&models.WebhookDelivery{}).Where("organization_id = ?", org.ID).Count(&count).Error)
+		return count
+	}
+
+	p.purgeWebhookDeliveries(now.Add(30 * time.Minute))
+	assert.Equal(t, int64(1), countDeliveries(), "purge runs at most once an hour")
+
+	p.purgeWebhookDeliveries(now.Add(time.Hour))
+	assert.Zero(t, countDeliveries())
+}
@@ -58,8 +58,9 @@ func (a *App) ListTemplates(r *fastglue.Request) error {
 
 	// Optional filters
 	accountName := string(r.RequestCtx.QueryArgs().Peek("account")) // Filter by account name
-	status := string(r.RequestCtx.QueryArgs().Peek("status"))
-	category := string(r.RequestCtx.QueryArgs().Peek("category"))
+	// Statuses and categories are stored uppercase, as Meta reports them
+	status := strings.ToUpper(string(r.RequestCtx.QueryArgs().Peek("status")))
+	category := strings.ToUpper(string(r.RequestCtx.QueryArgs().Peek("category")))
 	search := string(r.RequestCtx.QueryArgs().Peek("search"))
 
 	query := a.DB.Where("organization_id = ?", orgID)
@@ -298,6 +298,36 @@ func TestApp_ListTemplates_FilterByCategory(t *testing.T) {
 	assert.Equal(t, "UTILITY", resp.Data.Templates[0].Category)
 }
 
+func TestApp_ListTemplates_FiltersIgnoreCase(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	account := testutil.CreateTestWhatsAppAccount(t, app.DB, org.ID)
+
+	createTestTemplateInDB(t, app, org.ID, account.Name, "approved_tmpl", "APPROVED")
+	createTestTemplateInDB(t, app, org.ID, account.Name, "pending_tmpl", "PENDING")
+
+	req := testutil.NewGETRequest(t)
+	testutil.SetAuthContext(req, org.ID, user.ID)
+	testutil.SetQueryParam(req, "status", "approved")
+	testutil.SetQueryParam(req, "category", "marketing")
+
+	err := app.ListTemplates(req)
+	require.NoError(t, err)
+	assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+	var resp struct {
+		Data struct {
+			Templates []handlers.TemplateResponse `json:"templates"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+	require.Len(t, resp.Data.Templates, 1)
+	assert.Equal(t, "approved_tmpl", resp.Data.Templates[0].Name)
+}
+
 func TestApp_ListTemplates_CrossOrgIsolation(t *testing.T) {
 	t.Parallel()
 
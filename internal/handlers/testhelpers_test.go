@@ -36,6 +36,26 @@ func withHTTPClient(client *http.Client) appOption {
 	}
 }
 
+// withPasswordResetDelivery sets the URL password reset tokens are posted to.
+func withPasswordResetDelivery(url string) appOption {
+	return func(a *handlers.App) {
+		a.Config.PasswordReset = config.PasswordResetConfig{DeliveryURL: url}
+	}
+}
+
+// withLoginLockout enables login lockout after threshold failures.
+func withLoginLockout(threshold int) appOption {
+	return func(a *handlers.App) {
+		a.Config.RateLimit = config.RateLimitConfig{
+			Enabled:                true,
+			TrustProxy:             true,
+			LoginLockoutThreshold:  threshold,
+			LoginLockoutSeconds:    60,
+			LoginLockoutMaxSeconds: 600,
+		}
+	}
+}
+
 // newTestApp creates an App instance for testing with a test database, Redis, and default config.
 // Skips the test if TEST_REDIS_URL is not set.
 func newTestApp(t *testing.T, opts ...appOption) *handlers.App {
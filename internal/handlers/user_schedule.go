@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/valyala/fasthttp"
+	"github.com/zerodha/fastglue"
+)
+
+// ScheduleHours is one weekday's working hours in a user schedule
+type ScheduleHours struct {
+	Day       int    `json:"day"` // 0 = Sunday ... 6 = Saturday
+	Enabled   bool   `json:"enabled"`
+	StartTime string `json:"start_time"` // HH:MM
+	EndTime   string `json:"end_time"`   // HH:MM
+}
+
+// UserScheduleResponse represents a user's working hours
+type UserScheduleResponse struct {
+	UserID  uuid.UUID       `json:"user_id"`
+	Hours   []ScheduleHours `json:"hours"`
+	OnShift bool            `json:"on_shift"`
+}
+
+// GetUserSchedule returns a user's working hours in the current organization
+func (a *App) GetUserSchedule(r *fastglue.Request) error {
+	orgID, currentUserID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	id, err := parsePathUUID(r, "id", "user")
+	if err != nil {
+		return nil
+	}
+
+	// Users can view their own schedule, others need users:read permission
+	if currentUserID != id && !a.HasPermission(currentUserID, models.ResourceUsers, models.ActionRead, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Insufficient permissions", nil, "")
+	}
+	if !a.isOrgMember(orgID, id) {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "User not found", nil, "")
+	}
+
+	var schedule models.UserSchedule
+	if err := a.DB.Where("user_id = ? AND organization_id = ?", id, orgID).First(&schedule).Error; err != nil {
+		// No schedule means always on shift
+		schedule = models.UserSchedule{UserID: id, OrganizationID: orgID}
+	}
+
+	return r.SendEnvelope(scheduleToResponse(&schedule, time.Now()))
+}
+
+// UpdateUserSchedule replaces a user's working hours in the current organization.
+// An empty hours list removes the schedule so the user is always on shift.
+func (a *App) UpdateUserSchedule(r *fastglue.Request) error {
+	orgID, currentUserID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	id, err := parsePathUUID(r, "id", "user")
+	if err != nil {
+		return nil
+	}
+
+	// Users can update their own schedule, others need users:write permission
+	if currentUserID != id && !a.HasPermission(currentUserID, models.ResourceUsers, models.ActionWrite, orgID) {
+		return r.SendErrorEnvelope(fasthttp.StatusForbidden, "Insufficient permissions", nil, "")
+	}
+	if !a.isOrgMember(orgID, id) {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "User not found", nil, "")
+	}
+
+	var req struct {
+		Hours []ScheduleHours `json:"hours"`
+	}
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	if errMsg := validateScheduleHours(req.Hours); errMsg != "" {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, errMsg, nil, "")
+	}
+
+	hours := make(models.JSONBArray, 0, len(req.Hours))
+	for _, h := range req.Hours {
+		hours = append(hours, map[string]interface{}{
+			"day":        h.Day,
+			"enabled":    h.Enabled,
+			"start_time": h.StartTime,
+			"end_time":   h.EndTime,
+		})
+	}
+
+	var schedule models.UserSchedule
+	if err := a.DB.Where("user_id = ? AND organization_id = ?", id, orgID).First(&schedule).Error; err != nil {
+		schedule = models.UserSchedule{
+			BaseModel:      models.BaseModel{ID: uuid.New()},
+			UserID:         id,
+			OrganizationID: orgID,
+		}
+	}
+	schedule.Hours = hours
+	if err := a.DB.Save(&schedule).Error; err != nil {
+		a.Log.Error("Failed to save user schedule", "error", err, "user_id", id)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to save schedule", nil, "")
+	}
+
+	return r.SendEnvelope(scheduleToResponse(&schedule, time.Now()))
+}
+
+// validateScheduleHours checks days are unique weekdays and enabled days have a valid time range
+func validateScheduleHours(hours []ScheduleHours) string {
+	seen := make(map[int]bool, len(hours))
+	for _, h := range hours {
+		if h.Day < 0 || h.Day > 6 {
+			return "day must be between 0 (Sunday) and 6 (Saturday)"
+		}
+		if seen[h.Day] {
+			return fmt.Sprintf("day %d is listed more than once", h.Day)
+		}
+		seen[h.Day] = true
+		if !h.Enabled {
+			continue
+		}
+		start, err := time.Parse("15:04", h.StartTime)
+		if err != nil {
+			return "start_time must be HH:MM"
+		}
+		end, err := time.Parse("15:04", h.EndTime)
+		if err != nil {
+			return "end_time must be HH:MM"
+		}
+		if !end.After(start) {
+			return "end_time must be after start_time"
+		}
+	}
+	return ""
+}
+
+// scheduleToResponse converts a stored schedule for the API
+func scheduleToResponse(schedule *models.UserSchedule, now time.Time) UserScheduleResponse {
+	hours := make([]ScheduleHours, 0, len(schedule.Hours))
+	if raw, err := json.Marshal(schedule.Hours); err == nil {
+		_ = json.Unmarshal(raw, &hours)
+	}
+	return UserScheduleResponse{
+		UserID:  schedule.UserID,
+		Hours:   hours,
+		OnShift: len(schedule.Hours) == 0 || models.IsWithinWeeklyHours(normalizeScheduleHours(schedule.Hours), now),
+	}
+}
+
+// normalizeScheduleHours round-trips hours through JSON so day numbers are
+// float64 as models.IsWithinWeeklyHours expects, whether or not they came from the database
+func normalizeScheduleHours(hours models.JSONBArray) models.JSONBArray {
+	raw, err := json.Marshal(hours)
+	if err != nil {
+		return hours
+	}
+	var out models.JSONBArray
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return hours
+	}
+	return out
+}
+
+// isOrgMember reports whether the user belongs to the organization
+func (a *App) isOrgMember(orgID, userID uuid.UUID) bool {
+	var count int64
+	a.DB.Model(&models.UserOrganization{}).
+		Where("user_id = ? AND organization_id = ?", userID, orgID).
+		Count(&count)
+	return count > 0
+}
+
+// offShiftUsers returns which of the given users are outside their working
+// hours at now. Users without a schedule are always on shift.
+func (a *App) offShiftUsers(orgID uuid.UUID, userIDs []uuid.UUID, now time.Time) map[uuid.UUID]bool {
+	offShift := make(map[uuid.UUID]bool)
+	if len(userIDs) == 0 {
+		return offShift
+	}
+
+	var schedules []models.UserSchedule
+	if err := a.DB.Where("organization_id = ? AND user_id IN ?", orgID, userIDs).Find(&schedules).Error; err != nil {
+		a.Log.Error("Failed to load user schedules", "error", err, "org_id", orgID)
+		return offShift
+	}
+	for _, s := range schedules {
+		if len(s.Hours) > 0 && !models.IsWithinWeeklyHours(s.Hours, now) {
+			offShift[s.UserID] = true
+		}
+	}
+	return offShift
+}
+
+// isOnShift reports whether the user is within their working hours right now
+func (a *App) isOnShift(orgID, userID uuid.UUID) bool {
+	return !a.offShiftUsers(orgID, []uuid.UUID{userID}, time.Now())[userID]
+}
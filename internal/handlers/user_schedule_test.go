@@ -0,0 +1,119 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shridarpatil/whatomate/internal/handlers"
+	"github.com/shridarpatil/whatomate/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestApp_UserSchedule(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user updates and reads own schedule", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		agent := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+
+		req := testutil.NewJSONRequest(t, map[string]any{
+			"hours": []map[string]any{
+				{"day": 1, "enabled": true, "start_time": "09:00", "end_time": "17:00"},
+				{"day": 0, "enabled": false},
+			},
+		})
+		testutil.SetAuthContext(req, org.ID, agent.ID)
+		testutil.SetPathParam(req, "id", agent.ID.String())
+		require.NoError(t, app.UpdateUserSchedule(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		getReq := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(getReq, org.ID, agent.ID)
+		testutil.SetPathParam(getReq, "id", agent.ID.String())
+		require.NoError(t, app.GetUserSchedule(getReq))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(getReq))
+
+		var resp struct {
+			Data handlers.UserScheduleResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(getReq), &resp))
+		assert.Equal(t, agent.ID, resp.Data.UserID)
+		require.Len(t, resp.Data.Hours, 2)
+		assert.Equal(t, handlers.ScheduleHours{Day: 1, Enabled: true, StartTime: "09:00", EndTime: "17:00"}, resp.Data.Hours[0])
+	})
+
+	t.Run("no schedule means always on shift", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, user.ID)
+		testutil.SetPathParam(req, "id", user.ID.String())
+		require.NoError(t, app.GetUserSchedule(req))
+
+		var resp struct {
+			Data handlers.UserScheduleResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		assert.Empty(t, resp.Data.Hours)
+		assert.True(t, resp.Data.OnShift)
+	})
+
+	t.Run("invalid hours rejected", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		user := testutil.CreateTestUser(t, app.DB, org.ID)
+
+		for _, hours := range [][]map[string]any{
+			{{"day": 7, "enabled": true, "start_time": "09:00", "end_time": "17:00"}},
+			{{"day": 1, "enabled": true, "start_time": "18:00", "end_time": "09:00"}},
+			{{"day": 1, "enabled": true, "start_time": "9am", "end_time": "17:00"}},
+			{{"day": 2, "enabled": false}, {"day": 2, "enabled": false}},
+		} {
+			req := testutil.NewJSONRequest(t, map[string]any{"hours": hours})
+			testutil.SetAuthContext(req, org.ID, user.ID)
+			testutil.SetPathParam(req, "id", user.ID.String())
+			require.NoError(t, app.UpdateUserSchedule(req))
+			assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req), "hours: %v", hours)
+		}
+	})
+
+	t.Run("agent cannot edit someone else's schedule", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		agent := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&agentRole.ID))
+		other := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("schedule-other")))
+
+		req := testutil.NewJSONRequest(t, map[string]any{"hours": []map[string]any{}})
+		testutil.SetAuthContext(req, org.ID, agent.ID)
+		testutil.SetPathParam(req, "id", other.ID.String())
+		require.NoError(t, app.UpdateUserSchedule(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("user from another org not found", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		admin := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithRoleID(&adminRole.ID))
+		outsider := testutil.CreateTestUser(t, app.DB, otherOrg.ID, testutil.WithEmail(testutil.UniqueEmail("schedule-outsider")))
+
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, org.ID, admin.ID)
+		testutil.SetPathParam(req, "id", outsider.ID.String())
+		require.NoError(t, app.GetUserSchedule(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -83,6 +85,11 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"new_password"`
 }
 
+// SetUserPasswordRequest represents the request body for an admin setting a user's password
+type SetUserPasswordRequest struct {
+	NewPassword string `json:"new_password"`
+}
+
 // ListUsers returns all users for the organization
 func (a *App) ListUsers(r *fastglue.Request) error {
 	orgID, userID, err := a.getOrgAndUserID(r)
@@ -247,7 +254,7 @@ func (a *App) CreateUser(r *fastglue.Request) error {
 
 	// Check if email already exists (including soft-deleted users)
 	var existingUser models.User
-	if err := a.DB.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
+	if err := a.DB.Where("LOWER(email) = LOWER(?)", req.Email).First(&existingUser).Error; err == nil {
 		return r.SendErrorEnvelope(fasthttp.StatusConflict, "Email already exists", nil, "")
 	}
 
@@ -266,21 +273,32 @@ func (a *App) CreateUser(r *fastglue.Request) error {
 		isSuperAdmin = true
 	}
 
+	user, err := a.createOrRestoreUser(orgID, req.Email, req.FullName, string(hashedPassword), roleID, isSuperAdmin)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to create user", nil, "")
+	}
+
+	return r.SendEnvelope(userToResponse(user))
+}
+
+// createOrRestoreUser creates a user with a default membership in the organization.
+// A soft-deleted user with the same email is restored with the new details instead.
+func (a *App) createOrRestoreUser(orgID uuid.UUID, email, fullName, passwordHash string, roleID *uuid.UUID, isSuperAdmin bool) (models.User, error) {
 	// Check for soft-deleted user with same email and restore them
 	var softDeleted models.User
-	if err := a.DB.Unscoped().Where("email = ? AND deleted_at IS NOT NULL", req.Email).First(&softDeleted).Error; err == nil {
+	if err := a.DB.Unscoped().Where("email = ? AND deleted_at IS NOT NULL", email).First(&softDeleted).Error; err == nil {
 		// Restore the soft-deleted user with new details
 		if err := a.DB.Unscoped().Model(&softDeleted).Updates(map[string]interface{}{
 			"deleted_at":      nil,
 			"organization_id": orgID,
-			"password_hash":   string(hashedPassword),
-			"full_name":       req.FullName,
+			"password_hash":   passwordHash,
+			"full_name":       fullName,
 			"role_id":         roleID,
 			"is_active":       true,
 			"is_super_admin":  isSuperAdmin,
 		}).Error; err != nil {
 			a.Log.Error("Failed to restore user", "error", err)
-			return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to create user", nil, "")
+			return models.User{}, err
 		}
 
 		// Restore or create UserOrganization entry
@@ -308,20 +326,20 @@ func (a *App) CreateUser(r *fastglue.Request) error {
 			}
 		}
 		softDeleted.OrganizationID = orgID
-		softDeleted.PasswordHash = string(hashedPassword)
-		softDeleted.FullName = req.FullName
+		softDeleted.PasswordHash = passwordHash
+		softDeleted.FullName = fullName
 		softDeleted.RoleID = roleID
 		softDeleted.IsActive = true
 		softDeleted.IsSuperAdmin = isSuperAdmin
 
-		return r.SendEnvelope(userToResponse(softDeleted))
+		return softDeleted, nil
 	}
 
 	user := models.User{
 		OrganizationID: orgID,
-		Email:          req.Email,
-		PasswordHash:   string(hashedPassword),
-		FullName:       req.FullName,
+		Email:          email,
+		PasswordHash:   passwordHash,
+		FullName:       fullName,
 		RoleID:         roleID,
 		IsActive:       true,
 		IsSuperAdmin:   isSuperAdmin,
@@ -329,7 +347,7 @@ func (a *App) CreateUser(r *fastglue.Request) error {
 
 	if err := a.DB.Create(&user).Error; err != nil {
 		a.Log.Error("Failed to create user", "error", err)
-		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to create user", nil, "")
+		return models.User{}, err
 	}
 
 	// Create UserOrganization entry
@@ -347,7 +365,144 @@ func (a *App) CreateUser(r *fastglue.Request) error {
 	// Load role for response
 	a.DB.Preload("Role").First(&user, user.ID)
 
-	return r.SendEnvelope(userToResponse(user))
+	return user, nil
+}
+
+// MaxBulkCreateUsers is the maximum number of users a single bulk create may invite
+const MaxBulkCreateUsers = 100
+
+// BulkCreateUserEntry is one invitee in a bulk create request
+type BulkCreateUserEntry struct {
+	Email    string     `json:"email"`
+	FullName string     `json:"full_name"`
+	RoleID   *uuid.UUID `json:"role_id"`
+}
+
+// BulkCreateUserResult reports the outcome for one invitee.
+// Password is the generated initial password and is only returned once.
+type BulkCreateUserResult struct {
+	Index    int        `json:"index"`
+	Email    string     `json:"email"`
+	Status   string     `json:"status"` // created, skipped or failed
+	Reason   string     `json:"reason,omitempty"`
+	UserID   *uuid.UUID `json:"user_id,omitempty"`
+	Password string     `json:"password,omitempty"`
+}
+
+// BulkCreateUsers creates users from an invite list. Each user gets a random
+// initial password. Entries that can't be created are reported per entry so
+// the rest of the list still goes through.
+func (a *App) BulkCreateUsers(r *fastglue.Request) error {
+	orgID, userID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, userID, models.ResourceUsers, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	var req struct {
+		Users []BulkCreateUserEntry `json:"users"`
+	}
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+	if len(req.Users) == 0 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "users is required", nil, "")
+	}
+	if len(req.Users) > MaxBulkCreateUsers {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest,
+			fmt.Sprintf("At most %d users can be created at once", MaxBulkCreateUsers), nil, "")
+	}
+
+	// Resolve the default role once for entries without a role_id
+	var defaultRoleID *uuid.UUID
+	var defaultRole models.CustomRole
+	if err := a.DB.Where("organization_id = ? AND is_default = ?", orgID, true).First(&defaultRole).Error; err == nil {
+		defaultRoleID = &defaultRole.ID
+	}
+
+	results := make([]BulkCreateUserResult, len(req.Users))
+	seen := make(map[string]bool, len(req.Users))
+	created, skipped, failed := 0, 0, 0
+	for i, entry := range req.Users {
+		email := strings.TrimSpace(entry.Email)
+		fullName := strings.TrimSpace(entry.FullName)
+		results[i] = a.bulkCreateUser(orgID, i, email, fullName, entry.RoleID, defaultRoleID, seen)
+		if email != "" {
+			seen[strings.ToLower(email)] = true
+		}
+		switch results[i].Status {
+		case "created":
+			created++
+		case "skipped":
+			skipped++
+		default:
+			failed++
+		}
+	}
+
+	return r.SendEnvelope(map[string]interface{}{
+		"results": results,
+		"created": created,
+		"skipped": skipped,
+		"failed":  failed,
+	})
+}
+
+// bulkCreateUser creates one invite list entry with a random initial password
+func (a *App) bulkCreateUser(orgID uuid.UUID, index int, email, fullName string, roleID, defaultRoleID *uuid.UUID, seen map[string]bool) BulkCreateUserResult {
+	result := BulkCreateUserResult{Index: index, Email: email}
+	if reason := a.checkBulkUserEntry(orgID, email, fullName, roleID, seen); reason != "" {
+		result.Status = "skipped"
+		result.Reason = reason
+		return result
+	}
+	if roleID == nil {
+		roleID = defaultRoleID
+	}
+
+	password := generateRandomString(16)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		a.Log.Error("Failed to hash password", "error", err)
+		result.Status = "failed"
+		result.Reason = "Failed to create user"
+		return result
+	}
+	user, err := a.createOrRestoreUser(orgID, email, fullName, string(hashedPassword), roleID, false)
+	if err != nil {
+		result.Status = "failed"
+		result.Reason = "Failed to create user"
+		return result
+	}
+
+	result.Status = "created"
+	result.UserID = &user.ID
+	result.Password = password
+	return result
+}
+
+// checkBulkUserEntry returns why an invite list entry should be skipped, or "" if it can be created
+func (a *App) checkBulkUserEntry(orgID uuid.UUID, email, fullName string, roleID *uuid.UUID, seen map[string]bool) string {
+	if email == "" || fullName == "" {
+		return "Email and full_name are required"
+	}
+	if seen[strings.ToLower(email)] {
+		return "Duplicate email in request"
+	}
+	var existing models.User
+	if err := a.DB.Where("LOWER(email) = LOWER(?)", email).First(&existing).Error; err == nil {
+		return "Email already exists"
+	}
+	if roleID != nil {
+		var role models.CustomRole
+		if err := a.DB.Where("id = ? AND organization_id = ?", roleID, orgID).First(&role).Error; err != nil {
+			return "Invalid role"
+		}
+	}
+	return ""
 }
 
 // UpdateUser updates a user
@@ -736,6 +891,68 @@ func (a *App) ChangePassword(r *fastglue.Request) error {
 	return r.SendEnvelope(map[string]string{"message": "Password changed successfully"})
 }
 
+// SetUserPassword sets another user's password without the current one (admin only).
+// The user's existing sessions are revoked.
+func (a *App) SetUserPassword(r *fastglue.Request) error {
+	orgID, currentUserID, err := a.getOrgAndUserID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	if err := a.requirePermission(r, currentUserID, models.ResourceUsers, models.ActionWrite); err != nil {
+		return nil
+	}
+
+	id, err := parsePathUUID(r, "id", "user")
+	if err != nil {
+		return nil
+	}
+
+	if id == currentUserID {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Use change password to update your own password", nil, "")
+	}
+
+	var req SetUserPasswordRequest
+	if err := a.decodeRequest(r, &req); err != nil {
+		return nil
+	}
+
+	// Validate new password length
+	if len(req.NewPassword) < 6 {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "New password must be at least 6 characters", nil, "")
+	}
+
+	var user models.User
+	if err := a.DB.
+		Select("users.*").
+		Joins("JOIN user_organizations ON user_organizations.user_id = users.id AND user_organizations.organization_id = ? AND user_organizations.deleted_at IS NULL", orgID).
+		Where("users.id = ? AND users.deleted_at IS NULL", id).
+		First(&user).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "User not found", nil, "")
+	}
+
+	// Cross-org members' accounts are managed by their home organization
+	if user.OrganizationID != orgID {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Password can't be set for organization members", nil, "")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		a.Log.Error("Failed to hash password", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to set password", nil, "")
+	}
+
+	if err := a.DB.Model(&models.User{}).Where("id = ?", id).Update("password_hash", string(hashedPassword)).Error; err != nil {
+		a.Log.Error("Failed to set password", "error", err, "user_id", id)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to set password", nil, "")
+	}
+
+	a.revokeUserSessions(id)
+	a.Log.Info("Password set by admin", "user_id", id, "admin_id", currentUserID, "org_id", orgID)
+
+	return r.SendEnvelope(map[string]string{"message": "Password updated successfully"})
+}
+
 // Helper function to convert User to UserResponse
 func userToResponse(user models.User) UserResponse {
 	resp := UserResponse{
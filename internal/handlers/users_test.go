@@ -2,6 +2,7 @@ package handlers_test
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -264,6 +265,27 @@ func TestApp_CreateUser(t *testing.T) {
 		assert.Equal(t, fasthttp.StatusConflict, testutil.GetResponseStatusCode(req))
 	})
 
+	t.Run("duplicate email with different case", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		existingEmail := testutil.UniqueEmail("create-dup-case")
+		admin := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(existingEmail),
+			testutil.WithRoleID(&adminRole.ID),
+		)
+
+		req := testutil.NewJSONRequest(t, map[string]interface{}{
+			"email":     strings.ToUpper(existingEmail),
+			"password":  "securePass123",
+			"full_name": "Duplicate User",
+		})
+		testutil.SetAuthContext(req, org.ID, admin.ID)
+
+		require.NoError(t, app.CreateUser(req))
+		assert.Equal(t, fasthttp.StatusConflict, testutil.GetResponseStatusCode(req))
+	})
+
 	t.Run("missing required fields", func(t *testing.T) {
 		app := newTestApp(t)
 		org := testutil.CreateTestOrganization(t, app.DB)
@@ -1689,3 +1711,224 @@ func TestApp_CreateUser_CreatedUserIsActive(t *testing.T) {
 	assert.NotEqual(t, "securePass123", dbUser.PasswordHash)
 	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(dbUser.PasswordHash), []byte("securePass123")))
 }
+
+func TestApp_BulkCreateUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("partial success reports each entry", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		admin := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("bulk-admin")),
+			testutil.WithRoleID(&adminRole.ID),
+		)
+
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		foreignRole := testutil.CreateAgentRole(t, app.DB, otherOrg.ID)
+
+		newEmail := testutil.UniqueEmail("bulk-new")
+		req := testutil.NewJSONRequest(t, map[string]interface{}{
+			"users": []map[string]interface{}{
+				{"email": newEmail, "full_name": "New Agent", "role_id": agentRole.ID.String()},
+				{"email": strings.ToUpper(admin.Email), "full_name": "Existing"},
+				{"email": newEmail, "full_name": "Repeated"},
+				{"email": testutil.UniqueEmail("bulk-foreign"), "full_name": "Foreign Role", "role_id": foreignRole.ID.String()},
+				{"email": "", "full_name": "No Email"},
+			},
+		})
+		testutil.SetAuthContext(req, org.ID, admin.ID)
+
+		require.NoError(t, app.BulkCreateUsers(req))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var resp struct {
+			Data struct {
+				Results []handlers.BulkCreateUserResult `json:"results"`
+				Created int                             `json:"created"`
+				Skipped int                             `json:"skipped"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(testutil.GetResponseBody(req), &resp))
+		require.Len(t, resp.Data.Results, 5)
+		assert.Equal(t, 1, resp.Data.Created)
+		assert.Equal(t, 4, resp.Data.Skipped)
+
+		created := resp.Data.Results[0]
+		assert.Equal(t, "created", created.Status)
+		require.NotNil(t, created.UserID)
+		assert.NotEmpty(t, created.Password)
+
+		assert.Equal(t, "Email already exists", resp.Data.Results[1].Reason)
+		assert.Equal(t, "Duplicate email in request", resp.Data.Results[2].Reason)
+		assert.Equal(t, "Invalid role", resp.Data.Results[3].Reason)
+		assert.Equal(t, "skipped", resp.Data.Results[4].Status)
+		for _, res := range resp.Data.Results[1:] {
+			assert.Empty(t, res.Password)
+			assert.Nil(t, res.UserID)
+		}
+
+		// The generated password works and the role is applied
+		var user models.User
+		require.NoError(t, app.DB.Where("id = ?", *created.UserID).First(&user).Error)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(created.Password)))
+		require.NotNil(t, user.RoleID)
+		assert.Equal(t, agentRole.ID, *user.RoleID)
+
+		var memberships int64
+		app.DB.Model(&models.UserOrganization{}).Where("user_id = ? AND organization_id = ?", user.ID, org.ID).Count(&memberships)
+		assert.Equal(t, int64(1), memberships)
+	})
+
+	t.Run("forbidden without users write", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		agent := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("bulk-agent")),
+			testutil.WithRoleID(&agentRole.ID),
+		)
+
+		email := testutil.UniqueEmail("bulk-denied")
+		req := testutil.NewJSONRequest(t, map[string]interface{}{
+			"users": []map[string]interface{}{{"email": email, "full_name": "Denied"}},
+		})
+		testutil.SetAuthContext(req, org.ID, agent.ID)
+
+		require.NoError(t, app.BulkCreateUsers(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+
+		var count int64
+		app.DB.Model(&models.User{}).Where("email = ?", email).Count(&count)
+		assert.Zero(t, count)
+	})
+
+	t.Run("rejects empty list", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		admin := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("bulk-empty-admin")),
+			testutil.WithRoleID(&adminRole.ID),
+		)
+
+		req := testutil.NewJSONRequest(t, map[string]interface{}{"users": []interface{}{}})
+		testutil.SetAuthContext(req, org.ID, admin.ID)
+
+		require.NoError(t, app.BulkCreateUsers(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+}
+
+func TestApp_SetUserPassword(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets password and revokes sessions", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		admin := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("setpw-admin")),
+			testutil.WithRoleID(&adminRole.ID),
+		)
+		target := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("setpw-target")),
+			testutil.WithPassword("password123"),
+		)
+
+		// Log the target in to get a live refresh token
+		loginReq := testutil.NewJSONRequest(t, map[string]string{"email": target.Email, "password": "password123"})
+		require.NoError(t, app.Login(loginReq))
+		require.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(loginReq))
+		refreshToken := testutil.GetResponseCookie(loginReq, "whm_refresh")
+		require.NotEmpty(t, refreshToken)
+
+		req := testutil.NewJSONRequest(t, map[string]string{"new_password": "newSecurePass456"})
+		testutil.SetAuthContext(req, org.ID, admin.ID)
+		testutil.SetPathParam(req, "id", target.ID.String())
+
+		require.NoError(t, app.SetUserPassword(req))
+		assert.Equal(t, fasthttp.StatusOK, testutil.GetResponseStatusCode(req))
+
+		var dbUser models.User
+		require.NoError(t, app.DB.Where("id = ?", target.ID).First(&dbUser).Error)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(dbUser.PasswordHash), []byte("newSecurePass456")))
+
+		refreshReq := testutil.NewJSONRequest(t, map[string]string{"refresh_token": refreshToken})
+		require.NoError(t, app.RefreshToken(refreshReq))
+		testutil.AssertErrorResponse(t, refreshReq, fasthttp.StatusUnauthorized, "Refresh token has been revoked")
+	})
+
+	t.Run("rejects own password", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		admin := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("setpw-self")),
+			testutil.WithRoleID(&adminRole.ID),
+		)
+
+		req := testutil.NewJSONRequest(t, map[string]string{"new_password": "newSecurePass456"})
+		testutil.SetAuthContext(req, org.ID, admin.ID)
+		testutil.SetPathParam(req, "id", admin.ID.String())
+
+		require.NoError(t, app.SetUserPassword(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("rejects short password", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		admin := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("setpw-short-admin")),
+			testutil.WithRoleID(&adminRole.ID),
+		)
+		target := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("setpw-short")))
+
+		req := testutil.NewJSONRequest(t, map[string]string{"new_password": "abc"})
+		testutil.SetAuthContext(req, org.ID, admin.ID)
+		testutil.SetPathParam(req, "id", target.ID.String())
+
+		require.NoError(t, app.SetUserPassword(req))
+		assert.Equal(t, fasthttp.StatusBadRequest, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("user in another org is not found", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		otherOrg := testutil.CreateTestOrganization(t, app.DB)
+		adminRole := testutil.CreateAdminRole(t, app.DB, org.ID)
+		admin := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("setpw-iso-admin")),
+			testutil.WithRoleID(&adminRole.ID),
+		)
+		outsider := testutil.CreateTestUser(t, app.DB, otherOrg.ID, testutil.WithEmail(testutil.UniqueEmail("setpw-outsider")))
+
+		req := testutil.NewJSONRequest(t, map[string]string{"new_password": "newSecurePass456"})
+		testutil.SetAuthContext(req, org.ID, admin.ID)
+		testutil.SetPathParam(req, "id", outsider.ID.String())
+
+		require.NoError(t, app.SetUserPassword(req))
+		assert.Equal(t, fasthttp.StatusNotFound, testutil.GetResponseStatusCode(req))
+	})
+
+	t.Run("forbidden without users write", func(t *testing.T) {
+		app := newTestApp(t)
+		org := testutil.CreateTestOrganization(t, app.DB)
+		agentRole := testutil.CreateAgentRole(t, app.DB, org.ID)
+		agent := testutil.CreateTestUser(t, app.DB, org.ID,
+			testutil.WithEmail(testutil.UniqueEmail("setpw-agent")),
+			testutil.WithRoleID(&agentRole.ID),
+		)
+		target := testutil.CreateTestUser(t, app.DB, org.ID, testutil.WithEmail(testutil.UniqueEmail("setpw-victim")))
+
+		req := testutil.NewJSONRequest(t, map[string]string{"new_password": "newSecurePass456"})
+		testutil.SetAuthContext(req, org.ID, agent.ID)
+		testutil.SetPathParam(req, "id", target.ID.String())
+
+		require.NoError(t, app.SetUserPassword(req))
+		assert.Equal(t, fasthttp.StatusForbidden, testutil.GetResponseStatusCode(req))
+	})
+}
@@ -397,13 +397,33 @@ func statusPriority(status models.MessageStatus) int {
 	case models.MessageStatusRead:
 		return 3
 	case models.MessageStatusFailed:
-		return 4 // Failed can override any status
+		return 4 // Failed can override any other status
 	default:
 		return -1
 	}
 }
 
-// updateMessageStatus updates the status of a regular message in the messages table
+// statusesAtOrAbove returns the known statuses a message must not move from to reach status
+func statusesAtOrAbove(status models.MessageStatus) []models.MessageStatus {
+	priority := statusPriority(status)
+	var result []models.MessageStatus
+	for _, s := range []models.MessageStatus{
+		models.MessageStatusPending,
+		models.MessageStatusSent,
+		models.MessageStatusDelivered,
+		models.MessageStatusRead,
+		models.MessageStatusFailed,
+	} {
+		if statusPriority(s) >= priority {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// updateMessageStatus updates the status of a regular message in the messages
+// table. Statuses only move forward (sent → delivered → read → failed), so
+// duplicate and out-of-order receipts are ignored.
 func (a *App) updateMessageStatus(whatsappMsgID, statusValue string, errors []WebhookStatusError) {
 	// Find the message by WhatsApp message ID
 	var message models.Message
@@ -414,11 +434,15 @@ func (a *App) updateMessageStatus(whatsappMsgID, statusValue string, errors []We
 	}
 
 	newStatus := models.MessageStatus(statusValue)
-	currentPriority := statusPriority(message.Status)
-	newPriority := statusPriority(newStatus)
+	switch newStatus {
+	case models.MessageStatusSent, models.MessageStatusDelivered, models.MessageStatusRead, models.MessageStatusFailed:
+	default:
+		a.Log.Debug("Ignoring message status update", "status", statusValue)
+		return
+	}
 
-	// Only update if new status is a progression (higher priority) or if it's failed
-	if newPriority <= currentPriority && newStatus != models.MessageStatusFailed {
+	// Only update if new status is a progression (higher priority)
+	if statusPriority(newStatus) <= statusPriority(message.Status) {
 		a.Log.Debug("Ignoring status update - not a progression",
 			"message_id", message.ID,
 			"current_status", message.Status,
@@ -426,36 +450,46 @@ func (a *App) updateMessageStatus(whatsappMsgID, statusValue string, errors []We
 		return
 	}
 
-	updates := map[string]interface{}{}
+	updates := map[string]interface{}{
+		"status": newStatus,
+	}
+	if newStatus == models.MessageStatusFailed && len(errors) > 0 {
+		// Prefer error_data.details (most descriptive), then Message, then Title.
+		errText := errors[0].ErrorData.Details
+		if errText == "" {
+			errText = errors[0].Message
+		}
+		if errText == "" || errText == errors[0].Title {
+			errText = errors[0].Title
+		}
 
-	switch newStatus {
-	case models.MessageStatusSent:
-		updates["status"] = models.MessageStatusSent
-	case models.MessageStatusDelivered:
-		updates["status"] = models.MessageStatusDelivered
-	case models.MessageStatusRead:
-		updates["status"] = models.MessageStatusRead
-	case models.MessageStatusFailed:
-		updates["status"] = models.MessageStatusFailed
-		if len(errors) > 0 {
-			// Prefer error_data.details (most descriptive), then Message, then Title.
-			errText := errors[0].ErrorData.Details
-			if errText == "" {
-				errText = errors[0].Message
-			}
-			if errText == "" || errText == errors[0].Title {
-				errText = errors[0].Title
-			}
+		updates["error_message"] = errText
 
-			updates["error_message"] = errText
+		// Keep the full failure reason for later inspection
+		metadata := models.JSONB{}
+		for k, v := range message.Metadata {
+			metadata[k] = v
 		}
-	default:
-		a.Log.Debug("Ignoring message status update", "status", statusValue)
-		return
+		metadata["failure"] = map[string]any{
+			"code":    errors[0].Code,
+			"title":   errors[0].Title,
+			"message": errors[0].Message,
+			"details": errors[0].ErrorData.Details,
+		}
+		updates["metadata"] = metadata
 	}
 
-	if err := a.DB.Model(&message).Updates(updates).Error; err != nil {
-		a.Log.Error("Failed to update message status", "error", err, "message_id", message.ID)
+	// Guard on the current status so concurrent receipts for the same message
+	// can't move it backwards or apply the same transition twice
+	updated := a.DB.Model(&models.Message{}).
+		Where("id = ? AND status NOT IN ?", message.ID, statusesAtOrAbove(newStatus)).
+		Updates(updates)
+	if updated.Error != nil {
+		a.Log.Error("Failed to update message status", "error", updated.Error, "message_id", message.ID)
+		return
+	}
+	if updated.RowsAffected == 0 {
+		a.Log.Debug("Ignoring status update - already progressed", "message_id", message.ID, "new_status", statusValue)
 		return
 	}
 
@@ -7,6 +7,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"sync"
 	"time"
@@ -16,8 +17,10 @@ import (
 )
 
 
-// OutboundWebhookPayload represents the structure sent to external webhook endpoints
+// OutboundWebhookPayload represents the structure sent to external webhook endpoints.
+// ID stays the same across retries so receivers can drop duplicate deliveries.
 type OutboundWebhookPayload struct {
+	ID        string      `json:"id"`
 	Event     string      `json:"event"`
 	Timestamp time.Time   `json:"timestamp"`
 	Data      interface{} `json:"data"`
@@ -34,6 +37,23 @@ type MessageEventData struct {
 	WhatsAppAccount string             `json:"whatsapp_account"`
 	Direction       models.Direction   `json:"direction,omitempty"`
 	SentByUserID    string             `json:"sent_by_user_id,omitempty"`
+	WAMID           string             `json:"wamid,omitempty"`
+	MediaURL        string             `json:"media_url,omitempty"`
+	MediaMimeType   string             `json:"media_mime_type,omitempty"`
+	MediaFilename   string             `json:"media_filename,omitempty"`
+	ReplyToID       string             `json:"reply_to_message_id,omitempty"`
+	CreatedAt       *time.Time         `json:"created_at,omitempty"`
+}
+
+// ReactionEventData represents data for reaction events.
+// Emoji is empty when the reaction was removed.
+type ReactionEventData struct {
+	MessageID       string `json:"message_id"`
+	ContactID       string `json:"contact_id"`
+	ContactPhone    string `json:"contact_phone"`
+	Emoji           string `json:"emoji"`
+	ActorUserID     string `json:"actor_user_id"`
+	WhatsAppAccount string `json:"whatsapp_account"`
 }
 
 // ContactEventData represents data for contact events
@@ -120,6 +140,7 @@ func containsEvent(events models.StringArray, event string) bool {
 
 func (a *App) sendWebhook(ctx context.Context, webhook models.Webhook, eventType string, data interface{}) {
 	payload := OutboundWebhookPayload{
+		ID:        uuid.New().String(),
 		Event:     eventType,
 		Timestamp: time.Now().UTC(),
 		Data:      data,
@@ -131,12 +152,27 @@ func (a *App) sendWebhook(ctx context.Context, webhook models.Webhook, eventType
 		return
 	}
 
+	delivery := models.WebhookDelivery{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: webhook.OrganizationID,
+		WebhookID:      webhook.ID,
+		EventID:        payload.ID,
+		Event:          eventType,
+		URL:            webhook.URL,
+		Payload:        string(jsonData),
+		Status:         models.WebhookDeliveryPending,
+	}
+	if err := a.DB.Create(&delivery).Error; err != nil {
+		a.Log.Error("failed to record webhook delivery", "error", err, "webhook_id", webhook.ID)
+	}
+
 	// Retry logic with exponential backoff
 	maxRetries := 3
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		// Check if context was cancelled before retry
 		if ctx.Err() != nil {
 			a.Log.Warn("webhook delivery cancelled", "reason", ctx.Err(), "webhook_id", webhook.ID)
+			a.markWebhookDeliveryFailed(&delivery, ctx.Err())
 			return
 		}
 
@@ -145,12 +181,15 @@ func (a *App) sendWebhook(ctx context.Context, webhook models.Webhook, eventType
 			select {
 			case <-ctx.Done():
 				a.Log.Warn("webhook delivery cancelled during backoff", "reason", ctx.Err(), "webhook_id", webhook.ID)
+				a.markWebhookDeliveryFailed(&delivery, ctx.Err())
 				return
 			case <-time.After(time.Duration(1<<attempt) * time.Second):
 			}
 		}
 
-		if err := a.sendWebhookRequest(ctx, webhook, jsonData); err != nil {
+		err := a.sendWebhookRequest(ctx, webhook, payload.ID, jsonData)
+		a.recordWebhookAttempt(&delivery, attempt+1, err, attempt+1 == maxRetries)
+		if err != nil {
 			a.Log.Warn("webhook delivery failed",
 				"error", err,
 				"webhook_id", webhook.ID,
@@ -176,7 +215,65 @@ func (a *App) sendWebhook(ctx context.Context, webhook models.Webhook, eventType
 	)
 }
 
-func (a *App) sendWebhookRequest(ctx context.Context, webhook models.Webhook, jsonData []byte) error {
+// recordWebhookAttempt stores the outcome of a delivery attempt. The delivery
+// stays pending after a failed attempt unless it was the last one.
+func (a *App) recordWebhookAttempt(delivery *models.WebhookDelivery, attempts int, sendErr error, last bool) {
+	updates := map[string]interface{}{
+		"attempts":         attempts,
+		"last_status_code": 0,
+		"last_error":       "",
+	}
+	var whErr *WebhookError
+	if errors.As(sendErr, &whErr) {
+		updates["last_status_code"] = whErr.StatusCode
+	}
+	switch {
+	case sendErr == nil:
+		updates["status"] = models.WebhookDeliveryDelivered
+		updates["delivered_at"] = time.Now()
+	case last:
+		updates["status"] = models.WebhookDeliveryFailed
+	}
+	if sendErr != nil {
+		updates["last_error"] = sendErr.Error()
+	}
+	if err := a.DB.Model(delivery).Updates(updates).Error; err != nil {
+		a.Log.Error("failed to update webhook delivery", "error", err, "delivery_id", delivery.ID)
+	}
+}
+
+// markWebhookDeliveryFailed marks a delivery failed without another attempt
+func (a *App) markWebhookDeliveryFailed(delivery *models.WebhookDelivery, reason error) {
+	if err := a.DB.Model(delivery).Updates(map[string]interface{}{
+		"status":     models.WebhookDeliveryFailed,
+		"last_error": reason.Error(),
+	}).Error; err != nil {
+		a.Log.Error("failed to update webhook delivery", "error", err, "delivery_id", delivery.ID)
+	}
+}
+
+// purgeWebhookDeliveries deletes delivery logs older than the configured
+// retention, since each one keeps a copy of the payload sent. Returns how
+// many were deleted.
+func (a *App) purgeWebhookDeliveries(now time.Time) int64 {
+	days := a.Config.App.WebhookDeliveryRetentionDays
+	if days <= 0 {
+		return 0
+	}
+	result := a.DB.Unscoped().
+		Where("created_at < ?", now.AddDate(0, 0, -days)).
+		Delete(&models.WebhookDelivery{})
+	if result.Error != nil {
+		a.Log.Error("Failed to purge webhook deliveries", "error", result.Error)
+		return 0
+	}
+	if result.RowsAffected > 0 {
+		a.Log.Info("Purged old webhook deliveries", "count", result.RowsAffected, "retention_days", days)
+	}
+	return result.RowsAffected
+}
+
+func (a *App) sendWebhookRequest(ctx context.Context, webhook models.Webhook, deliveryID string, jsonData []byte) error {
 	req, err := http.NewRequestWithContext(ctx, "POST", webhook.URL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return err
@@ -185,6 +282,7 @@ func (a *App) sendWebhookRequest(ctx context.Context, webhook models.Webhook, js
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "Whatomate-Webhook/1.0")
+	req.Header.Set("X-Webhook-ID", deliveryID)
 
 	// Add custom headers from webhook config
 	if webhook.Headers != nil {
@@ -361,6 +361,57 @@ func TestUpdateMessageStatus_FailedUpdatesMessage(t *testing.T) {
 	assert.Equal(t, 1, updatedCampaign.FailedCount)
 }
 
+func TestUpdateMessageStatus_FailedRecordsReasonInMetadata(t *testing.T) {
+	app := webhookTestApp(t)
+	_, msg, campaign, _ := webhookTestData(t, app, models.MessageStatusDelivered)
+
+	errors := []WebhookStatusError{
+		{Code: 131026, Title: "Message undeliverable", Message: "Message undeliverable"},
+	}
+	errors[0].ErrorData.Details = "Receiver is incapable of receiving this message"
+	app.updateMessageStatus(msg.WhatsAppMessageID, "failed", errors)
+
+	var updated models.Message
+	require.NoError(t, app.DB.First(&updated, msg.ID).Error)
+	assert.Equal(t, models.MessageStatusFailed, updated.Status)
+	assert.Equal(t, campaign.ID.String(), updated.Metadata["campaign_id"], "existing metadata is kept")
+	failure, ok := updated.Metadata["failure"].(map[string]any)
+	require.True(t, ok, "failure should be recorded in metadata")
+	assert.Equal(t, float64(131026), failure["code"])
+	assert.Equal(t, "Message undeliverable", failure["title"])
+	assert.Equal(t, "Receiver is incapable of receiving this message", failure["details"])
+}
+
+func TestUpdateMessageStatus_DuplicateReceiptsAreIdempotent(t *testing.T) {
+	app := webhookTestApp(t)
+	_, msg, campaign, _ := webhookTestData(t, app, models.MessageStatusSent)
+
+	app.updateMessageStatus(msg.WhatsAppMessageID, "delivered", nil)
+	app.updateMessageStatus(msg.WhatsAppMessageID, "delivered", nil)
+	errors := []WebhookStatusError{{Code: 131047, Title: "Re-engagement message"}}
+	app.updateMessageStatus(msg.WhatsAppMessageID, "failed", errors)
+	app.updateMessageStatus(msg.WhatsAppMessageID, "failed", errors)
+
+	var updatedCampaign models.BulkMessageCampaign
+	require.NoError(t, app.DB.First(&updatedCampaign, campaign.ID).Error)
+	assert.Equal(t, 1, updatedCampaign.DeliveredCount)
+	assert.Equal(t, 1, updatedCampaign.FailedCount)
+}
+
+func TestUpdateMessageStatus_ReadBeforeDeliveredKeepsRead(t *testing.T) {
+	app := webhookTestApp(t)
+	_, msg, _, _ := webhookTestData(t, app, models.MessageStatusSent)
+
+	// Receipts can arrive out of order
+	app.updateMessageStatus(msg.WhatsAppMessageID, "read", nil)
+	app.updateMessageStatus(msg.WhatsAppMessageID, "delivered", nil)
+	app.updateMessageStatus(msg.WhatsAppMessageID, "sent", nil)
+
+	var updated models.Message
+	require.NoError(t, app.DB.First(&updated, msg.ID).Error)
+	assert.Equal(t, models.MessageStatusRead, updated.Status)
+}
+
 func TestUpdateMessageStatus_FailedBroadcastsErrorMessageViaWebSocket(t *testing.T) {
 	// Create app with a real WebSocket hub
 	db := testutil.SetupTestDB(t)
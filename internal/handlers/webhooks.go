@@ -110,10 +110,12 @@ type WebhookResponse struct {
 var AvailableWebhookEvents = []map[string]string{
 	{"value": string(models.WebhookEventMessageIncoming), "label": "Message Incoming", "description": "When a new message is received from a contact"},
 	{"value": string(models.WebhookEventMessageSent), "label": "Message Sent", "description": "When an agent sends a message"},
+	{"value": string(models.WebhookEventMessageReaction), "label": "Message Reaction", "description": "When an agent adds or removes a reaction"},
 	{"value": string(models.WebhookEventContactCreated), "label": "Contact Created", "description": "When a new contact is created"},
 	{"value": string(models.WebhookEventTransferCreated), "label": "Transfer Created", "description": "When a transfer to human agent is requested"},
 	{"value": string(models.WebhookEventTransferAssigned), "label": "Transfer Assigned", "description": "When a transfer is assigned to an agent"},
 	{"value": string(models.WebhookEventTransferResumed), "label": "Transfer Resumed", "description": "When chatbot is resumed (transfer closed)"},
+	{"value": string(models.WebhookEventPasswordReset), "label": "Password Reset Requested", "description": "When a user requests a password reset (includes the reset token)"},
 }
 
 // ListWebhooks returns all webhooks for the organization
@@ -349,6 +351,7 @@ func (a *App) TestWebhook(r *fastglue.Request) error {
 	}
 
 	payload := OutboundWebhookPayload{
+		ID:        uuid.New().String(),
 		Event:     "test",
 		Timestamp: time.Now().UTC(),
 		Data:      testData,
@@ -363,7 +366,7 @@ func (a *App) TestWebhook(r *fastglue.Request) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	if err := a.sendWebhookRequest(ctx, *webhook, jsonData); err != nil {
+	if err := a.sendWebhookRequest(ctx, *webhook, payload.ID, jsonData); err != nil {
 		a.Log.Error("Webhook test failed", "error", err, "webhook_id", webhook.ID)
 		return r.SendErrorEnvelope(fasthttp.StatusBadGateway, "Webhook test failed", nil, "")
 	}
@@ -396,3 +399,137 @@ func webhookToResponse(wh models.Webhook) WebhookResponse {
 		UpdatedAt: wh.UpdatedAt.Format(time.RFC3339),
 	}
 }
+
+// WebhookDeliveryResponse represents a webhook delivery attempt record in API responses
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID                    `json:"id"`
+	WebhookID      uuid.UUID                    `json:"webhook_id"`
+	EventID        string                       `json:"event_id"`
+	Event          string                       `json:"event"`
+	URL            string                       `json:"url"`
+	Status         models.WebhookDeliveryStatus `json:"status"`
+	Attempts       int                          `json:"attempts"`
+	LastStatusCode int                          `json:"last_status_code,omitempty"`
+	LastError      string                       `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time                   `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time                    `json:"created_at"`
+	UpdatedAt      time.Time                    `json:"updated_at"`
+}
+
+// ListWebhookDeliveries returns recent deliveries for a webhook, newest first
+func (a *App) ListWebhookDeliveries(r *fastglue.Request) error {
+	orgID, err := a.getOrgID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	webhookID, err := parsePathUUID(r, "id", "webhook")
+	if err != nil {
+		return nil
+	}
+
+	if _, err := findByIDAndOrg[models.Webhook](a.DB, r, webhookID, orgID, "Webhook"); err != nil {
+		return nil
+	}
+
+	pg := parsePagination(r)
+	query := a.DB.Model(&models.WebhookDelivery{}).Where("webhook_id = ? AND organization_id = ?", webhookID, orgID)
+	if status := string(r.RequestCtx.QueryArgs().Peek("status")); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if event := string(r.RequestCtx.QueryArgs().Peek("event")); event != "" {
+		query = query.Where("event = ?", event)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var deliveries []models.WebhookDelivery
+	if err := pg.Apply(query.Order("created_at DESC")).Find(&deliveries).Error; err != nil {
+		a.Log.Error("Failed to list webhook deliveries", "error", err)
+		return r.SendErrorEnvelope(fasthttp.StatusInternalServerError, "Failed to list webhook deliveries", nil, "")
+	}
+
+	result := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = webhookDeliveryToResponse(d)
+	}
+
+	return r.SendEnvelope(map[string]any{
+		"deliveries": result,
+		"total":      total,
+		"page":       pg.Page,
+		"limit":      pg.Limit,
+	})
+}
+
+// RedeliverWebhook resends a failed delivery's original payload to the
+// webhook's current URL. The payload keeps its event id so receivers can
+// still drop duplicates.
+func (a *App) RedeliverWebhook(r *fastglue.Request) error {
+	orgID, err := a.getOrgID(r)
+	if err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusUnauthorized, "Unauthorized", nil, "")
+	}
+
+	webhookID, err := parsePathUUID(r, "id", "webhook")
+	if err != nil {
+		return nil
+	}
+	deliveryID, err := parsePathUUID(r, "delivery_id", "delivery")
+	if err != nil {
+		return nil
+	}
+
+	webhook, err := findByIDAndOrg[models.Webhook](a.DB, r, webhookID, orgID, "Webhook")
+	if err != nil {
+		return nil
+	}
+
+	var delivery models.WebhookDelivery
+	if err := a.DB.Where("id = ? AND webhook_id = ? AND organization_id = ?", deliveryID, webhookID, orgID).
+		First(&delivery).Error; err != nil {
+		return r.SendErrorEnvelope(fasthttp.StatusNotFound, "Delivery not found", nil, "")
+	}
+	if delivery.Status != models.WebhookDeliveryFailed {
+		return r.SendErrorEnvelope(fasthttp.StatusBadRequest, "Only failed deliveries can be redelivered", nil, "")
+	}
+
+	// The webhook's URL may have been fixed since the original attempt
+	if delivery.URL != webhook.URL {
+		a.DB.Model(&delivery).Update("url", webhook.URL)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	sendErr := a.sendWebhookRequest(ctx, *webhook, delivery.EventID, []byte(delivery.Payload))
+	a.recordWebhookAttempt(&delivery, delivery.Attempts+1, sendErr, true)
+	if err := a.DB.Where("id = ?", delivery.ID).First(&delivery).Error; err != nil {
+		a.Log.Error("Failed to reload webhook delivery", "error", err, "delivery_id", delivery.ID)
+	}
+
+	if sendErr != nil {
+		a.Log.Error("Webhook redelivery failed", "error", sendErr, "webhook_id", webhook.ID, "delivery_id", delivery.ID)
+		return r.SendErrorEnvelope(fasthttp.StatusBadGateway, "Webhook redelivery failed", nil, "")
+	}
+
+	return r.SendEnvelope(webhookDeliveryToResponse(delivery))
+}
+
+func webhookDeliveryToResponse(d models.WebhookDelivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventID:        d.EventID,
+		Event:          d.Event,
+		URL:            d.URL,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
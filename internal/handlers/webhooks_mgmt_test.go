@@ -3,6 +3,7 @@ package handlers_test
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -742,3 +743,153 @@ func TestWebhookToResponse_HasSecretFalse(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, resp.Data.HasSecret, "webhook without secret should have has_secret=false")
 }
+
+// --- Webhook Delivery Tests ---
+
+// createTestWebhookDelivery inserts a delivery record for a webhook directly into the DB.
+func createTestWebhookDelivery(t *testing.T, app *handlers.App, wh *models.Webhook, status models.WebhookDeliveryStatus) *models.WebhookDelivery {
+	t.Helper()
+	eventID := uuid.New().String()
+	d := &models.WebhookDelivery{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		OrganizationID: wh.OrganizationID,
+		WebhookID:      wh.ID,
+		EventID:        eventID,
+		Event:          "message.incoming",
+		URL:            wh.URL,
+		Payload:        fmt.Sprintf(`{"id":%q,"event":"message.incoming","data":{}}`, eventID),
+		Status:         status,
+		Attempts:       3,
+		LastStatusCode: http.StatusServiceUnavailable,
+		LastError:      "webhook returned non-2xx status: Service Unavailable",
+	}
+	require.NoError(t, app.DB.Create(d).Error)
+	return d
+}
+
+func TestApp_DispatchWebhook_RecordsDelivery(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	app := newTestApp(t, withHTTPClient(&http.Client{Timeout: 5 * time.Second}))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	clearWebhookCache(t, app.Redis, org.ID)
+	t.Cleanup(func() { clearWebhookCache(t, app.Redis, org.ID) })
+	wh := createTestWebhook(t, app, org.ID, "Recorded Hook", server.URL, []string{"contact.created"})
+
+	app.DispatchWebhook(org.ID, models.WebhookEventContactCreated, map[string]string{"contact_id": "c1"})
+	app.WaitForBackgroundTasks()
+
+	var delivery models.WebhookDelivery
+	require.NoError(t, app.DB.Where("webhook_id = ?", wh.ID).First(&delivery).Error)
+	assert.Equal(t, models.WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, "contact.created", delivery.Event)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.NotEmpty(t, delivery.EventID)
+	assert.NotNil(t, delivery.DeliveredAt)
+}
+
+func TestApp_ListWebhookDeliveries(t *testing.T) {
+	t.Parallel()
+
+	app := newTestApp(t)
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	wh := createTestWebhook(t, app, org.ID, "Hook", "https://example.com/hook", []string{"message.incoming"})
+	createTestWebhookDelivery(t, app, wh, models.WebhookDeliveryDelivered)
+	failed := createTestWebhookDelivery(t, app, wh, models.WebhookDeliveryFailed)
+
+	list := func(t *testing.T, orgID, userID uuid.UUID, status string) (int, []handlers.WebhookDeliveryResponse) {
+		t.Helper()
+		req := testutil.NewGETRequest(t)
+		testutil.SetAuthContext(req, orgID, userID)
+		testutil.SetPathParam(req, "id", wh.ID.String())
+		if status != "" {
+			testutil.SetQueryParam(req, "status", status)
+		}
+		require.NoError(t, app.ListWebhookDeliveries(req))
+
+		var resp struct {
+			Data struct {
+				Deliveries []handlers.WebhookDeliveryResponse `json:"deliveries"`
+			} `json:"data"`
+		}
+		_ = json.Unmarshal(testutil.GetResponseBody(req), &resp)
+		return testutil.GetResponseStatusCode(req), resp.Data.Deliveries
+	}
+
+	status, deliveries := list(t, org.ID, user.ID, "")
+	assert.Equal(t, fasthttp.StatusOK, status)
+	assert.Len(t, deliveries, 2)
+
+	status, deliveries = list(t, org.ID, user.ID, "failed")
+	assert.Equal(t, fasthttp.StatusOK, status)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, failed.ID, deliveries[0].ID)
+	assert.Equal(t, http.StatusServiceUnavailable, deliveries[0].LastStatusCode)
+	assert.NotEmpty(t, deliveries[0].LastError)
+
+	// Another org can't see the webhook's deliveries
+	org2 := testutil.CreateTestOrganization(t, app.DB)
+	user2 := testutil.CreateTestUser(t, app.DB, org2.ID)
+	status, _ = list(t, org2.ID, user2.ID, "")
+	assert.Equal(t, fasthttp.StatusNotFound, status)
+}
+
+func TestApp_RedeliverWebhook(t *testing.T) {
+	t.Parallel()
+
+	var receivedID string
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedID = r.Header.Get("X-Webhook-ID")
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	app := newTestApp(t, withHTTPClient(&http.Client{Timeout: 5 * time.Second}))
+	org := testutil.CreateTestOrganization(t, app.DB)
+	user := testutil.CreateTestUser(t, app.DB, org.ID)
+	wh := createTestWebhook(t, app, org.ID, "Hook", server.URL, []string{"message.incoming"})
+
+	redeliver := func(t *testing.T, orgID, userID uuid.UUID, deliveryID uuid.UUID) int {
+		t.Helper()
+		req := testutil.NewJSONRequest(t, nil)
+		testutil.SetAuthContext(req, orgID, userID)
+		testutil.SetPathParam(req, "id", wh.ID.String())
+		testutil.SetPathParam(req, "delivery_id", deliveryID.String())
+		require.NoError(t, app.RedeliverWebhook(req))
+		return testutil.GetResponseStatusCode(req)
+	}
+
+	t.Run("failed delivery is resent with its original payload", func(t *testing.T) {
+		d := createTestWebhookDelivery(t, app, wh, models.WebhookDeliveryFailed)
+
+		assert.Equal(t, fasthttp.StatusOK, redeliver(t, org.ID, user.ID, d.ID))
+		assert.Equal(t, d.EventID, receivedID)
+		assert.JSONEq(t, d.Payload, string(receivedBody))
+
+		var updated models.WebhookDelivery
+		require.NoError(t, app.DB.Where("id = ?", d.ID).First(&updated).Error)
+		assert.Equal(t, models.WebhookDeliveryDelivered, updated.Status)
+		assert.Equal(t, 4, updated.Attempts)
+		assert.Empty(t, updated.LastError)
+	})
+
+	t.Run("delivered delivery is rejected", func(t *testing.T) {
+		d := createTestWebhookDelivery(t, app, wh, models.WebhookDeliveryDelivered)
+		assert.Equal(t, fasthttp.StatusBadRequest, redeliver(t, org.ID, user.ID, d.ID))
+	})
+
+	t.Run("cross org is not found", func(t *testing.T) {
+		d := createTestWebhookDelivery(t, app, wh, models.WebhookDeliveryFailed)
+		org2 := testutil.CreateTestOrganization(t, app.DB)
+		user2 := testutil.CreateTestUser(t, app.DB, org2.ID)
+		assert.Equal(t, fasthttp.StatusNotFound, redeliver(t, org2.ID, user2.ID, d.ID))
+	})
+}
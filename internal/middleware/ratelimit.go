@@ -28,7 +28,7 @@ type RateLimitOpts struct {
 // It fails open: if Redis is unavailable the request is allowed through.
 func RateLimit(opts RateLimitOpts) fastglue.FastMiddleware {
 	return func(r *fastglue.Request) *fastglue.Request {
-		ip := extractClientIP(r, opts.TrustProxy)
+		ip := ClientIP(r, opts.TrustProxy)
 		key := fmt.Sprintf("ratelimit:%s:%s", opts.KeyPrefix, ip)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -69,9 +69,9 @@ func RateLimit(opts RateLimitOpts) fastglue.FastMiddleware {
 	}
 }
 
-// extractClientIP returns the client IP address from the request.
+// ClientIP returns the client IP address from the request.
 // When trustProxy is true, it checks X-Forwarded-For and X-Real-IP headers first.
-func extractClientIP(r *fastglue.Request, trustProxy bool) string {
+func ClientIP(r *fastglue.Request, trustProxy bool) string {
 	if trustProxy {
 		// X-Forwarded-For may contain a chain: "client, proxy1, proxy2"
 		if xff := string(r.RequestCtx.Request.Header.Peek("X-Forwarded-For")); xff != "" {
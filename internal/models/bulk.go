@@ -23,6 +23,7 @@ type BulkMessageCampaign struct {
 	DeliveredCount  int        `gorm:"default:0" json:"delivered_count"`
 	ReadCount       int        `gorm:"default:0" json:"read_count"`
 	FailedCount     int        `gorm:"default:0" json:"failed_count"`
+	SkippedCount    int        `gorm:"default:0" json:"skipped_count"` // Recipients not messaged for lack of consent
 	ScheduledAt     *time.Time `json:"scheduled_at,omitempty"`
 	StartedAt       *time.Time `json:"started_at,omitempty"`
 	CompletedAt     *time.Time `json:"completed_at,omitempty"`
@@ -46,13 +47,14 @@ type BulkMessageRecipient struct {
 	PhoneNumber        string     `gorm:"size:50;not null" json:"phone_number"`
 	RecipientName      string     `gorm:"size:255" json:"recipient_name"`
 	TemplateParams     JSONB      `gorm:"type:jsonb;default:'{}'" json:"template_params"`
-	Status             MessageStatus `gorm:"size:20;default:'pending'" json:"status"` // pending, sent, delivered, read, failed
+	Status             MessageStatus `gorm:"size:20;default:'pending'" json:"status"` // pending, sent, delivered, read, failed, skipped
 	WhatsAppMessageID  string     `gorm:"column:whats_app_message_id;size:100;index" json:"whatsapp_message_id,omitempty"`
 	MessageID          *uuid.UUID `gorm:"type:uuid" json:"message_id,omitempty"`
 	ErrorMessage       string     `gorm:"type:text" json:"error_message"`
 	SentAt             *time.Time `json:"sent_at,omitempty"`
 	DeliveredAt        *time.Time `json:"delivered_at,omitempty"`
 	ReadAt             *time.Time `json:"read_at,omitempty"`
+	DeferredUntil      *time.Time `gorm:"index" json:"deferred_until,omitempty"` // Held back by quiet hours, re-queued once this passes
 
 	// Relations
 	Campaign *BulkMessageCampaign `gorm:"foreignKey:CampaignID" json:"campaign,omitempty"`
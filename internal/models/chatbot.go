@@ -12,6 +12,143 @@ type BusinessHoursConfig struct {
 	Hours                JSONBArray `gorm:"column:business_hours;type:jsonb;default:'[]'" json:"business_hours"` // [{day, enabled, start_time, end_time}]
 	OutOfHoursMessage    string     `gorm:"column:out_of_hours_message;type:text" json:"out_of_hours_message"`
 	AllowAutomatedOutside bool      `gorm:"column:allow_automated_outside_hours;default:true" json:"allow_automated_outside_hours"` // Allow flows/keywords/AI outside business hours
+	Timezone             string     `gorm:"column:business_hours_timezone;size:64" json:"business_hours_timezone"`                  // IANA name, e.g. Asia/Kolkata (empty = server time)
+}
+
+// Location returns the timezone business hours are kept in. An empty or
+// unknown timezone falls back to the server's local time.
+func (c BusinessHoursConfig) Location() *time.Location {
+	return loadLocationOrLocal(c.Timezone)
+}
+
+// loadLocationOrLocal loads an IANA timezone, falling back to the server's
+// local time when name is empty or unknown
+func loadLocationOrLocal(name string) *time.Location {
+	if name == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// IsWithinBusinessHours reports whether now falls inside the configured
+// hours, read in the business hours timezone
+func (c BusinessHoursConfig) IsWithinBusinessHours(now time.Time) bool {
+	return IsWithinWeeklyHours(c.Hours, now.In(c.Location()))
+}
+
+// IsWithinWeeklyHours checks now against weekly hours ([{day, enabled, start_time, end_time}])
+func IsWithinWeeklyHours(hours JSONBArray, now time.Time) bool {
+	currentDay := int(now.Weekday()) // 0 = Sunday, 1 = Monday, etc.
+	currentTime := now.Format("15:04")
+
+	for _, bh := range hours {
+		bhMap, ok := bh.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// Get day (0-6, Sunday-Saturday)
+		day, ok := bhMap["day"].(float64)
+		if !ok {
+			continue
+		}
+
+		if int(day) != currentDay {
+			continue
+		}
+
+		// Check if enabled for this day
+		enabled, ok := bhMap["enabled"].(bool)
+		if !ok || !enabled {
+			return false // Day exists but is disabled
+		}
+
+		// Get start and end times
+		startTime, ok := bhMap["start_time"].(string)
+		if !ok {
+			continue
+		}
+		endTime, ok := bhMap["end_time"].(string)
+		if !ok {
+			continue
+		}
+
+		// Compare times (simple string comparison works for HH:MM format)
+		if currentTime >= startTime && currentTime <= endTime {
+			return true
+		}
+		return false // Found the day but outside hours
+	}
+
+	// If no matching day found, assume outside business hours
+	return false
+}
+
+// QuietHoursConfig holds a nightly window, independent of business hours, in
+// which no automated or campaign messages are sent
+type QuietHoursConfig struct {
+	Enabled  bool   `gorm:"column:quiet_hours_enabled;default:false" json:"quiet_hours_enabled"`
+	Start    string `gorm:"column:quiet_hours_start;size:5" json:"quiet_hours_start"`        // HH:MM
+	End      string `gorm:"column:quiet_hours_end;size:5" json:"quiet_hours_end"`            // HH:MM, before Start when the window spans midnight
+	Timezone string `gorm:"column:quiet_hours_timezone;size:64" json:"quiet_hours_timezone"` // IANA name (empty = server time)
+}
+
+// Location returns the timezone quiet hours are kept in. An empty or unknown
+// timezone falls back to the server's local time.
+func (c QuietHoursConfig) Location() *time.Location {
+	return loadLocationOrLocal(c.Timezone)
+}
+
+// EndsAt reports whether now falls inside the quiet window and, if so, when
+// the window closes. Disabled or malformed configs are never quiet.
+func (c QuietHoursConfig) EndsAt(now time.Time) (time.Time, bool) {
+	if !c.Enabled {
+		return time.Time{}, false
+	}
+	start, err := time.Parse("15:04", c.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := time.Parse("15:04", c.End)
+	if err != nil {
+		return time.Time{}, false
+	}
+	startMin := start.Hour()*60 + start.Minute()
+	endMin := end.Hour()*60 + end.Minute()
+	if startMin == endMin {
+		return time.Time{}, false
+	}
+
+	local := now.In(c.Location())
+	current := local.Hour()*60 + local.Minute()
+	endToday := time.Date(local.Year(), local.Month(), local.Day(), end.Hour(), end.Minute(), 0, 0, local.Location())
+
+	if startMin < endMin {
+		// Same-day window, e.g. 13:00-15:00
+		if current >= startMin && current < endMin {
+			return endToday, true
+		}
+		return time.Time{}, false
+	}
+	// Window spans midnight, e.g. 22:00-07:00
+	if current >= startMin {
+		return endToday.AddDate(0, 0, 1), true
+	}
+	if current < endMin {
+		return endToday, true
+	}
+	return time.Time{}, false
+}
+
+// FloodControlConfig limits automated responses to a contact sending too many
+// messages. Messages over the limit are still stored.
+type FloodControlConfig struct {
+	MaxPerMinute int  `gorm:"column:inbound_rate_limit_per_minute;default:0" json:"inbound_rate_limit_per_minute"`         // 0 = disabled
+	FlagContact  bool `gorm:"column:inbound_rate_limit_flag_contact;default:false" json:"inbound_rate_limit_flag_contact"` // Flag contacts over the limit for review
 }
 
 // AgentAssignmentConfig holds agent assignment and queue settings
@@ -19,6 +156,15 @@ type AgentAssignmentConfig struct {
 	AllowQueuePickup        bool `gorm:"column:allow_agent_queue_pickup;default:true" json:"allow_agent_queue_pickup"`           // Allow agents to pick transfers from queue
 	AssignToSameAgent       bool `gorm:"column:assign_to_same_agent;default:true" json:"assign_to_same_agent"`                   // Auto-assign transfers to contact's existing agent
 	CurrentConversationOnly bool `gorm:"column:agent_current_conversation_only;default:false" json:"agent_current_conversation_only"` // Agents see only current session messages
+	UnassignOnResolve       bool `gorm:"column:unassign_on_resolve;default:false" json:"unassign_on_resolve"`                         // Return contacts to the pool when their conversation is resolved
+
+	// How new conversations and general-queue transfers are assigned to agents
+	Strategy AssignmentStrategy `gorm:"column:assignment_strategy;size:20;default:'manual'" json:"assignment_strategy"` // manual, round_robin, least_busy
+
+	// Fallback used when no agent is available to take a transfer
+	FallbackType   AssignmentFallback `gorm:"column:assignment_fallback_type;size:20" json:"assignment_fallback_type"` // user, team, queue (empty = disabled)
+	FallbackUserID *uuid.UUID         `gorm:"column:assignment_fallback_user_id;type:uuid" json:"assignment_fallback_user_id,omitempty"`
+	FallbackTeamID *uuid.UUID         `gorm:"column:assignment_fallback_team_id;type:uuid" json:"assignment_fallback_team_id,omitempty"`
 }
 
 // SLAConfig holds SLA tracking settings
@@ -47,12 +193,19 @@ type AIConfig struct {
 	Enabled        bool    `gorm:"column:ai_enabled;default:false" json:"ai_enabled"`
 	Provider       AIProvider `gorm:"column:ai_provider;size:20" json:"ai_provider"`                     // openai, anthropic, google
 	APIKey         string  `gorm:"column:ai_api_key;type:text" json:"-"`                                 // encrypted
+	APIKeyUpdatedAt *time.Time `gorm:"column:ai_api_key_updated_at" json:"ai_api_key_updated_at,omitempty"`
 	Model          string  `gorm:"column:ai_model;size:100" json:"ai_model"`
 	MaxTokens      int     `gorm:"column:ai_max_tokens;default:500" json:"ai_max_tokens"`
 	Temperature    float64 `gorm:"column:ai_temperature;type:decimal(3,2);default:0.7" json:"ai_temperature"`
 	SystemPrompt   string  `gorm:"column:ai_system_prompt;type:text" json:"ai_system_prompt"`
 	IncludeHistory bool    `gorm:"column:ai_include_history;default:true" json:"ai_include_history"`
 	HistoryLimit   int     `gorm:"column:ai_history_limit;default:4" json:"ai_history_limit"`
+	MaxConcurrency  int        `gorm:"column:ai_max_concurrency;default:0" json:"ai_max_concurrency"` // 0 = unlimited
+	QueueSize       int        `gorm:"column:ai_queue_size;default:0" json:"ai_queue_size"`           // Requests allowed to wait for a free slot
+	ContextLimit    int        `gorm:"column:ai_context_limit;default:0" json:"ai_context_limit"`         // Matching AI contexts added to the prompt (0 = all)
+	ContextMaxChars int        `gorm:"column:ai_context_max_chars;default:0" json:"ai_context_max_chars"` // Character budget for AI context (0 = unlimited)
+	TimeoutSecs     int             `gorm:"column:ai_timeout_seconds;default:0" json:"ai_timeout_seconds"` // Provider request timeout (0 = default)
+	FailureAction   AIFailureAction `gorm:"column:ai_failure_action;size:20" json:"ai_failure_action"`     // fallback_message (default), transfer
 }
 
 // PanelFieldConfig defines a field to display in the contact info panel
@@ -96,14 +249,19 @@ type ChatbotSettings struct {
 
 	// Embedded configs (all fields stored in same table)
 	BusinessHours    BusinessHoursConfig    `gorm:"embedded"`
+	QuietHours       QuietHoursConfig       `gorm:"embedded"`
 	AgentAssignment  AgentAssignmentConfig  `gorm:"embedded"`
 	SLA              SLAConfig              `gorm:"embedded"`
 	ClientInactivity ClientInactivityConfig `gorm:"embedded"`
 	AI               AIConfig               `gorm:"embedded"`
+	FloodControl     FloodControlConfig     `gorm:"embedded"`
 
 	// Session settings
-	SessionTimeoutMins int        `gorm:"default:30" json:"session_timeout_minutes"`
-	ExcludedNumbers    JSONBArray `gorm:"type:jsonb;default:'[]'" json:"excluded_numbers"`
+	SessionTimeoutMins    int        `gorm:"default:30" json:"session_timeout_minutes"`
+	SessionWarningPercent int        `gorm:"default:0" json:"session_warning_percent"` // Warn at this % of the timeout (0 = disabled)
+	SessionWarningMessage string     `gorm:"type:text" json:"session_warning_message"`
+	SessionGraceMins      int        `gorm:"default:0" json:"session_resume_grace_minutes"` // Resume an ended session on a reply within this many minutes (0 = disabled)
+	ExcludedNumbers       JSONBArray `gorm:"type:jsonb;default:'[]'" json:"excluded_numbers"`
 
 	// Relations
 	Organization *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
@@ -123,12 +281,15 @@ type KeywordRule struct {
 	Priority        int         `gorm:"default:10" json:"priority"`
 	Keywords        StringArray `gorm:"type:jsonb;not null" json:"keywords"`
 	MatchType       MatchType    `gorm:"size:20;default:'contains'" json:"match_type"` // exact, contains, starts_with, regex
-	CaseSensitive   bool         `gorm:"default:false" json:"case_sensitive"`
+	CaseSensitive   bool         `gorm:"default:false" json:"case_sensitive"` // ignored by regex, which has its own (?i) flag
+	WordBoundary    bool         `gorm:"default:false" json:"word_boundary"` // contains matches whole words only
 	ResponseType    ResponseType `gorm:"size:20;not null" json:"response_type"` // text, template, media, flow, script
 	ResponseContent JSONB       `gorm:"type:jsonb;not null" json:"response_content"`
 	Conditions      string      `gorm:"type:text" json:"conditions"`
 	ActiveFrom      *time.Time  `json:"active_from,omitempty"`
 	ActiveUntil     *time.Time  `json:"active_until,omitempty"`
+	HitCount        int64        `gorm:"default:0" json:"hit_count"`
+	LastHitAt       *time.Time   `json:"last_hit_at,omitempty"`
 
 	// Relations
 	Organization *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
@@ -138,6 +299,19 @@ func (KeywordRule) TableName() string {
 	return "keyword_rules"
 }
 
+// KeywordRuleHit records a keyword rule matching an inbound message
+type KeywordRuleHit struct {
+	BaseModel
+	OrganizationID uuid.UUID `gorm:"type:uuid;index;not null" json:"organization_id"`
+	KeywordRuleID  uuid.UUID `gorm:"type:uuid;index;not null" json:"keyword_rule_id"`
+	ContactID      uuid.UUID `gorm:"type:uuid;not null" json:"contact_id"`
+	Variant        *int      `json:"variant,omitempty"` // Index into response_content.variants, nil when the rule has none
+}
+
+func (KeywordRuleHit) TableName() string {
+	return "keyword_rule_hits"
+}
+
 // ChatbotFlow defines multi-step conversation flows
 type ChatbotFlow struct {
 	BaseModel
@@ -146,6 +320,7 @@ type ChatbotFlow struct {
 	Name               string      `gorm:"size:255;not null" json:"name"`
 	IsEnabled          bool        `gorm:"default:true" json:"is_enabled"`
 	Description        string      `gorm:"type:text" json:"description"`
+	TriggerType        FlowTriggerType `gorm:"size:20;default:'keyword'" json:"trigger_type"` // keyword, first_inbound, after_hours, button
 	TriggerKeywords    StringArray `gorm:"type:jsonb" json:"trigger_keywords"`
 	TriggerButtonID    string      `gorm:"size:100" json:"trigger_button_id"`
 	InitialMessage     string       `gorm:"type:text" json:"initial_message"`
@@ -155,8 +330,12 @@ type ChatbotFlow struct {
 	OnCompleteAction   string      `gorm:"size:20" json:"on_complete_action"` // none, webhook, create_record
 	CompletionConfig   JSONB       `gorm:"type:jsonb" json:"completion_config"`
 	TimeoutMessage     string      `gorm:"type:text" json:"timeout_message"`
+	StepTimeoutMins    int         `gorm:"default:0" json:"step_timeout_minutes"` // Handle a step left unanswered this long (0 = disabled)
+	StepTimeoutAction  StepTimeoutAction `gorm:"size:20;default:'nudge'" json:"step_timeout_action"` // nudge, advance, abandon
 	CancelKeywords     StringArray `gorm:"type:jsonb" json:"cancel_keywords"`
 	PanelConfig        JSONB       `gorm:"type:jsonb;default:'{}'" json:"panel_config"` // Contact info panel configuration
+	ActiveFrom         *time.Time   `json:"active_from,omitempty"`
+	ActiveUntil        *time.Time   `json:"active_until,omitempty"`
 
 	// Relations
 	Organization    *Organization     `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
@@ -185,11 +364,13 @@ type ChatbotFlowStep struct {
 	ValidationRegex string     `gorm:"size:255" json:"validation_regex"`
 	ValidationError string     `gorm:"type:text" json:"validation_error"`
 	StoreAs         string     `gorm:"size:100" json:"store_as"`
+	Sensitive       bool       `gorm:"default:false" json:"sensitive"` // Mask the stored value for users without pii:read
 	NextStep        string     `gorm:"size:100" json:"next_step"`
 	ConditionalNext JSONB      `gorm:"type:jsonb" json:"conditional_next"` // {"option1": "step_a", "default": "step_b"}
 	SkipCondition   string     `gorm:"type:text" json:"skip_condition"`
 	RetryOnInvalid  bool       `gorm:"default:true" json:"retry_on_invalid"`
 	MaxRetries      int        `gorm:"default:3" json:"max_retries"`
+	StepTimeoutMins int        `gorm:"default:0" json:"step_timeout_minutes"` // Overrides the flow's step timeout (0 = use the flow's)
 
 	// Relations
 	Flow     *ChatbotFlow `gorm:"foreignKey:FlowID" json:"flow,omitempty"`
@@ -207,7 +388,7 @@ type ChatbotSession struct {
 	ContactID       uuid.UUID  `gorm:"type:uuid;index;not null" json:"contact_id"`
 	WhatsAppAccount string     `gorm:"size:100;index;not null" json:"whatsapp_account"` // References WhatsAppAccount.Name
 	PhoneNumber     string     `gorm:"size:50;not null" json:"phone_number"`
-	Status          SessionStatus `gorm:"size:20;default:'active'" json:"status"` // active, completed, cancelled, timeout
+	Status          SessionStatus `gorm:"size:20;default:'active'" json:"status"` // active, completed, cancelled, timeout, transferred
 	CurrentFlowID   *uuid.UUID `gorm:"type:uuid" json:"current_flow_id,omitempty"`
 	CurrentStep     string     `gorm:"size:100" json:"current_step"`
 	StepRetries     int        `gorm:"default:0" json:"step_retries"`
@@ -215,6 +396,10 @@ type ChatbotSession struct {
 	StartedAt       time.Time  `gorm:"autoCreateTime" json:"started_at"`
 	LastActivityAt  time.Time  `json:"last_activity_at"`
 	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	FlowCompletedAt *time.Time `json:"flow_completed_at,omitempty"` // Reached the end of its flow; unset when the flow was exited early
+	WarningSentAt   *time.Time `json:"warning_sent_at,omitempty"` // Pre-timeout warning sent; cleared on user reply
+	ResumedAt       *time.Time `json:"resumed_at,omitempty"`       // Last time a reply within the grace window reopened the session
+	ResumeCount     int        `gorm:"default:0" json:"resume_count"`
 
 	// Relations
 	Organization *Organization           `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
@@ -243,6 +428,24 @@ func (ChatbotSessionMessage) TableName() string {
 	return "chatbot_session_messages"
 }
 
+// FlowWebhookDelivery records delivery of a flow's completion webhook
+type FlowWebhookDelivery struct {
+	BaseModel
+	OrganizationID uuid.UUID             `gorm:"type:uuid;index;not null" json:"organization_id"`
+	FlowID         uuid.UUID             `gorm:"type:uuid;index;not null" json:"flow_id"`
+	SessionID      uuid.UUID             `gorm:"type:uuid;index" json:"session_id"`
+	URL            string                `gorm:"type:text" json:"url"`
+	Status         WebhookDeliveryStatus `gorm:"size:20;default:'pending'" json:"status"` // pending, delivered, failed
+	Attempts       int                   `gorm:"default:0" json:"attempts"`
+	LastStatusCode int                   `json:"last_status_code"`
+	LastError      string                `gorm:"type:text" json:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
+
+func (FlowWebhookDelivery) TableName() string {
+	return "flow_webhook_deliveries"
+}
+
 // AIContext provides context data for AI responses
 type AIContext struct {
 	BaseModel
@@ -264,6 +467,22 @@ func (AIContext) TableName() string {
 	return "ai_contexts"
 }
 
+// AIUsage records the tokens consumed by one AI provider request
+type AIUsage struct {
+	BaseModel
+	OrganizationID   uuid.UUID  `gorm:"type:uuid;index;not null" json:"organization_id"`
+	WhatsAppAccount  string     `gorm:"size:100" json:"whatsapp_account"`
+	Provider         AIProvider `gorm:"size:20" json:"provider"`
+	Model            string     `gorm:"size:100" json:"model"`
+	PromptTokens     int        `gorm:"default:0" json:"prompt_tokens"`
+	CompletionTokens int        `gorm:"default:0" json:"completion_tokens"`
+	EstimatedCost    float64    `gorm:"type:decimal(12,6);default:0" json:"estimated_cost"` // USD, 0 for models without known pricing
+}
+
+func (AIUsage) TableName() string {
+	return "ai_usage"
+}
+
 // SLATracking holds SLA-related tracking fields for agent transfers
 type SLATracking struct {
 	ResponseDeadline   *time.Time `gorm:"column:sla_response_deadline;index" json:"sla_response_deadline,omitempty"`   // When pickup is due
@@ -286,7 +505,7 @@ type AgentTransfer struct {
 	WhatsAppAccount     string     `gorm:"size:100;index;not null" json:"whatsapp_account"` // References WhatsAppAccount.Name
 	PhoneNumber         string     `gorm:"size:50;not null" json:"phone_number"`
 	Status              TransferStatus `gorm:"size:20;default:'active'" json:"status"` // active, resumed
-	Source              TransferSource `gorm:"size:20;default:'manual'" json:"source"` // manual, flow, keyword, chatbot_disabled
+	Source              TransferSource `gorm:"size:20;default:'manual'" json:"source"` // manual, flow, keyword, chatbot_disabled, ai_failure
 	AgentID             *uuid.UUID `gorm:"type:uuid" json:"agent_id,omitempty"`
 	TeamID              *uuid.UUID `gorm:"type:uuid;index" json:"team_id,omitempty"` // Team queue (null = general queue)
 	TransferredByUserID *uuid.UUID `gorm:"type:uuid" json:"transferred_by_user_id,omitempty"` // User who initiated the transfer (null for system)
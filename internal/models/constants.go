@@ -43,6 +43,7 @@ const (
 	MessageStatusRead      MessageStatus = "read"
 	MessageStatusFailed    MessageStatus = "failed"
 	MessageStatusReceived  MessageStatus = "received"
+	MessageStatusSkipped   MessageStatus = "skipped" // Campaign recipient not messaged for lack of consent
 )
 
 // AIProvider represents supported AI providers
@@ -89,14 +90,76 @@ const (
 	FlowStepTypeWhatsAppFlow FlowStepType = "whatsapp_flow"
 )
 
+// FlowTriggerType represents what starts a chatbot flow
+type FlowTriggerType string
+
+const (
+	FlowTriggerKeyword      FlowTriggerType = "keyword"
+	FlowTriggerFirstInbound FlowTriggerType = "first_inbound"
+	FlowTriggerAfterHours   FlowTriggerType = "after_hours"
+	FlowTriggerButton       FlowTriggerType = "button"
+)
+
+// StepTimeoutAction is what happens when a flow step goes unanswered past its timeout
+type StepTimeoutAction string
+
+const (
+	StepTimeoutNudge   StepTimeoutAction = "nudge"   // Send the timeout message and keep waiting
+	StepTimeoutAdvance StepTimeoutAction = "advance" // Move on to the next step
+	StepTimeoutAbandon StepTimeoutAction = "abandon" // End the flow
+)
+
 // SessionStatus represents chatbot session states
 type SessionStatus string
 
 const (
-	SessionStatusActive    SessionStatus = "active"
-	SessionStatusCompleted SessionStatus = "completed"
-	SessionStatusCancelled SessionStatus = "cancelled"
-	SessionStatusTimeout   SessionStatus = "timeout"
+	SessionStatusActive      SessionStatus = "active"
+	SessionStatusCompleted   SessionStatus = "completed"
+	SessionStatusCancelled   SessionStatus = "cancelled"
+	SessionStatusTimeout     SessionStatus = "timeout"
+	SessionStatusTransferred SessionStatus = "transferred"
+)
+
+// ConversationStatus represents a contact's conversation workflow state
+type ConversationStatus string
+
+const (
+	ConversationStatusOpen     ConversationStatus = "open"
+	ConversationStatusPending  ConversationStatus = "pending"
+	ConversationStatusResolved ConversationStatus = "resolved"
+	ConversationStatusSnoozed  ConversationStatus = "snoozed"
+)
+
+// OptInMethod represents how a contact gave consent to be messaged
+type OptInMethod string
+
+const (
+	OptInMethodWebForm  OptInMethod = "web_form"
+	OptInMethodWhatsApp OptInMethod = "whatsapp"
+	OptInMethodPhone    OptInMethod = "phone"
+	OptInMethodInPerson OptInMethod = "in_person"
+	OptInMethodImport   OptInMethod = "import"
+	OptInMethodOther    OptInMethod = "other"
+)
+
+// ScheduledMessageStatus represents the states of a scheduled outgoing message
+type ScheduledMessageStatus string
+
+const (
+	ScheduledMessageStatusPending   ScheduledMessageStatus = "pending"
+	ScheduledMessageStatusSending   ScheduledMessageStatus = "sending"
+	ScheduledMessageStatusSent      ScheduledMessageStatus = "sent"
+	ScheduledMessageStatusFailed    ScheduledMessageStatus = "failed"
+	ScheduledMessageStatusCancelled ScheduledMessageStatus = "cancelled"
+)
+
+// WebhookDeliveryStatus represents outbound and flow completion webhook delivery states
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
 )
 
 // TransferStatus represents agent transfer states
@@ -116,6 +179,7 @@ const (
 	TransferSourceFlow            TransferSource = "flow"
 	TransferSourceKeyword         TransferSource = "keyword"
 	TransferSourceChatbotDisabled TransferSource = "chatbot_disabled"
+	TransferSourceAIFailure       TransferSource = "ai_failure"
 )
 
 // CampaignStatus represents bulk message campaign states
@@ -173,15 +237,33 @@ const (
 	InputTypeWhatsAppFlow InputType = "whatsapp_flow"
 )
 
-// AssignmentStrategy represents team assignment strategies
+// AssignmentStrategy represents team and organization assignment strategies
 type AssignmentStrategy string
 
 const (
 	AssignmentStrategyRoundRobin   AssignmentStrategy = "round_robin"
-	AssignmentStrategyLoadBalanced AssignmentStrategy = "load_balanced"
+	AssignmentStrategyLoadBalanced AssignmentStrategy = "load_balanced" // Teams only
+	AssignmentStrategyLeastBusy    AssignmentStrategy = "least_busy"    // Organization only
 	AssignmentStrategyManual       AssignmentStrategy = "manual"
 )
 
+// AIFailureAction represents what the chatbot does when an AI reply can't be generated
+type AIFailureAction string
+
+const (
+	AIFailureFallbackMessage AIFailureAction = "fallback_message"
+	AIFailureTransfer        AIFailureAction = "transfer"
+)
+
+// AssignmentFallback represents where transfers go when no agent is available
+type AssignmentFallback string
+
+const (
+	AssignmentFallbackUser  AssignmentFallback = "user"
+	AssignmentFallbackTeam  AssignmentFallback = "team"
+	AssignmentFallbackQueue AssignmentFallback = "queue"
+)
+
 // SSOProviderType represents supported SSO providers
 type SSOProviderType string
 
@@ -200,10 +282,12 @@ const (
 	WebhookEventMessageIncoming  WebhookEvent = "message.incoming"
 	WebhookEventMessageOutgoing  WebhookEvent = "message.outgoing"
 	WebhookEventMessageSent      WebhookEvent = "message.sent"
+	WebhookEventMessageReaction  WebhookEvent = "message.reaction"
 	WebhookEventContactCreated   WebhookEvent = "contact.created"
 	WebhookEventTransferCreated  WebhookEvent = "transfer.created"
 	WebhookEventTransferResumed  WebhookEvent = "transfer.resumed"
 	WebhookEventTransferAssigned WebhookEvent = "transfer.assigned"
+	WebhookEventPasswordReset    WebhookEvent = "user.password_reset_requested"
 )
 
 // ActionType represents custom action types
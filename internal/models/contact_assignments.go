@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// ContactAssignment records a change of a contact's assigned user
+type ContactAssignment struct {
+	BaseModel
+	OrganizationID uuid.UUID  `gorm:"type:uuid;index;not null" json:"organization_id"`
+	ContactID      uuid.UUID  `gorm:"type:uuid;index;not null" json:"contact_id"`
+	FromUserID     *uuid.UUID `gorm:"type:uuid" json:"from_user_id"` // nil when the contact was unassigned
+	ToUserID       *uuid.UUID `gorm:"type:uuid" json:"to_user_id"`   // nil when the contact was unassigned
+	AssignedByID   uuid.UUID  `gorm:"type:uuid;not null" json:"assigned_by_id"`
+
+	// Relations
+	Organization *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	Contact      *Contact      `gorm:"foreignKey:ContactID" json:"contact,omitempty"`
+	FromUser     *User         `gorm:"foreignKey:FromUserID" json:"from_user,omitempty"`
+	ToUser       *User         `gorm:"foreignKey:ToUserID" json:"to_user,omitempty"`
+	AssignedBy   *User         `gorm:"foreignKey:AssignedByID" json:"assigned_by,omitempty"`
+}
+
+func (ContactAssignment) TableName() string {
+	return "contact_assignments"
+}
@@ -0,0 +1,45 @@
+package models
+
+import "github.com/google/uuid"
+
+// Contact custom field types
+const (
+	CustomFieldTypeText    = "text"
+	CustomFieldTypeNumber  = "number"
+	CustomFieldTypeBoolean = "boolean"
+	CustomFieldTypeDate    = "date"   // YYYY-MM-DD
+	CustomFieldTypeSelect  = "select" // One of Options
+)
+
+// ValidCustomFieldTypes defines the allowed custom field types
+var ValidCustomFieldTypes = []string{
+	CustomFieldTypeText, CustomFieldTypeNumber, CustomFieldTypeBoolean, CustomFieldTypeDate, CustomFieldTypeSelect,
+}
+
+// IsValidCustomFieldType checks if a custom field type is valid
+func IsValidCustomFieldType(fieldType string) bool {
+	for _, t := range ValidCustomFieldTypes {
+		if t == fieldType {
+			return true
+		}
+	}
+	return false
+}
+
+// ContactCustomField defines a structured attribute the organization stores on
+// contacts. Values live in Contact.CustomFields under Key.
+type ContactCustomField struct {
+	BaseModel
+	OrganizationID uuid.UUID   `gorm:"type:uuid;index;not null" json:"organization_id"`
+	Key            string      `gorm:"size:50;not null" json:"key"`
+	Label          string      `gorm:"size:100;not null" json:"label"`
+	FieldType      string      `gorm:"size:20;not null" json:"type"`
+	Options        StringArray `gorm:"type:jsonb;default:'[]'" json:"options"` // Allowed values for select fields
+
+	// Relations
+	Organization *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+}
+
+func (ContactCustomField) TableName() string {
+	return "contact_custom_fields"
+}
@@ -101,6 +101,49 @@ func (Organization) TableName() string {
 	return "organizations"
 }
 
+// Campaign send rate limits, in messages per second
+const (
+	DefaultCampaignMessagesPerSecond = 20
+	MaxCampaignMessagesPerSecond     = 1000
+)
+
+// CampaignMessagesPerSecond returns the rate campaign messages are sent at,
+// from the campaign_messages_per_second setting or the default
+func (o *Organization) CampaignMessagesPerSecond() int {
+	var rate int
+	switch v := o.Settings["campaign_messages_per_second"].(type) {
+	case float64:
+		rate = int(v)
+	case int:
+		rate = v
+	}
+	if rate <= 0 {
+		return DefaultCampaignMessagesPerSecond
+	}
+	return min(rate, MaxCampaignMessagesPerSecond)
+}
+
+// CampaignRequiresOptIn reports whether campaigns skip contacts without a
+// valid opt-in. It's on unless the campaign_require_opt_in setting is false.
+func (o *Organization) CampaignRequiresOptIn() bool {
+	v, ok := o.Settings["campaign_require_opt_in"].(bool)
+	return v || !ok
+}
+
+// SendsReadReceipts reports whether messages read in the inbox are marked read
+// on WhatsApp for every account, from the send_read_receipts setting
+func (o *Organization) SendsReadReceipts() bool {
+	v, _ := o.Settings["send_read_receipts"].(bool)
+	return v
+}
+
+// DefaultWhatsAppAccount returns the name of the account messages are sent
+// from when none is chosen, from the default_whatsapp_account setting
+func (o *Organization) DefaultWhatsAppAccount() string {
+	v, _ := o.Settings["default_whatsapp_account"].(string)
+	return v
+}
+
 // User represents a user in the system
 type User struct {
 	BaseModel
@@ -135,6 +178,7 @@ type UserOrganization struct {
 	OrganizationID uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_user_org;not null" json:"organization_id"`
 	RoleID         *uuid.UUID `gorm:"type:uuid;index" json:"role_id,omitempty"`
 	IsDefault      bool       `gorm:"default:false" json:"is_default"`
+	LastAssignedAt *time.Time `json:"last_assigned_at,omitempty"` // For organization-level round-robin tracking
 
 	// Relations
 	User         *User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
@@ -163,6 +207,37 @@ func (UserAvailabilityLog) TableName() string {
 	return "user_availability_logs"
 }
 
+// UserSchedule holds a user's working hours in an organization.
+// Outside these hours the user is treated as away for automatic assignment.
+type UserSchedule struct {
+	BaseModel
+	UserID         uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_user_schedule;not null" json:"user_id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_user_schedule;not null" json:"organization_id"`
+	Hours          JSONBArray `gorm:"type:jsonb;default:'[]'" json:"hours"` // [{day, enabled, start_time, end_time}] in server time, empty means always on shift
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (UserSchedule) TableName() string {
+	return "user_schedules"
+}
+
+// PasswordResetToken is a single-use token for resetting a forgotten password.
+// Only the SHA-256 hash of the token is stored.
+type PasswordResetToken struct {
+	BaseModel
+	OrganizationID uuid.UUID  `gorm:"type:uuid;index;not null" json:"organization_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;index;not null" json:"user_id"`
+	TokenHash      string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt         *time.Time `json:"used_at,omitempty"`
+}
+
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
+
 // Team represents a group of agents handling specific types of chats
 type Team struct {
 	BaseModel
@@ -263,6 +338,26 @@ func (Webhook) TableName() string {
 	return "webhooks"
 }
 
+// WebhookDelivery records delivery of one event to an outbound webhook
+type WebhookDelivery struct {
+	BaseModel
+	OrganizationID uuid.UUID             `gorm:"type:uuid;index;not null" json:"organization_id"`
+	WebhookID      uuid.UUID             `gorm:"type:uuid;index;not null" json:"webhook_id"`
+	EventID        string                `gorm:"size:36;index" json:"event_id"` // Payload id, stable across retries
+	Event          string                `gorm:"size:100;index" json:"event"`
+	URL            string                `gorm:"type:text" json:"url"`
+	Payload        string                `gorm:"type:text" json:"-"`                      // Raw JSON body, resent on redelivery
+	Status         WebhookDeliveryStatus `gorm:"size:20;default:'pending'" json:"status"` // pending, delivered, failed
+	Attempts       int                   `gorm:"default:0" json:"attempts"`
+	LastStatusCode int                   `json:"last_status_code"`
+	LastError      string                `gorm:"type:text" json:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
 // CustomAction represents a custom action button for chat integrations
 type CustomAction struct {
 	BaseModel
@@ -299,6 +394,15 @@ type WhatsAppAccount struct {
 	AutoReadReceipt    bool      `gorm:"default:false" json:"auto_read_receipt"`
 	Status             string    `gorm:"size:20;default:'active'" json:"status"`
 
+	TokenUpdatedAt *time.Time `json:"access_token_updated_at,omitempty"` // When the access token was last set
+
+	// Health, recorded by the last connection test
+	PhoneNumber    string     `gorm:"size:50" json:"phone_number"`  // Display phone number reported by Meta
+	DisplayName    string     `gorm:"size:255" json:"display_name"` // Verified business name reported by Meta
+	TokenValid     *bool      `json:"token_valid,omitempty"`        // nil until the account is tested
+	LastCheckedAt  *time.Time `json:"last_checked_at,omitempty"`
+	LastCheckError string     `gorm:"type:text" json:"last_check_error"`
+
 	// Relations
 	Organization *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
 }
@@ -310,22 +414,50 @@ func (WhatsAppAccount) TableName() string {
 // Contact represents a WhatsApp contact/profile
 type Contact struct {
 	BaseModel
-	OrganizationID     uuid.UUID  `gorm:"type:uuid;index;not null" json:"organization_id"`
+	OrganizationID     uuid.UUID  `gorm:"type:uuid;index;index:idx_contacts_org_last_message,priority:1;not null" json:"organization_id"`
 	PhoneNumber        string     `gorm:"size:50;not null" json:"phone_number"`
 	ProfileName        string     `gorm:"size:255" json:"profile_name"`
 	WhatsAppAccount    string     `gorm:"size:100;index" json:"whatsapp_account"` // References WhatsAppAccount.Name
 	AssignedUserID     *uuid.UUID `gorm:"type:uuid;index" json:"assigned_user_id,omitempty"`
-	LastMessageAt      *time.Time `json:"last_message_at,omitempty"`
+	LastMessageAt      *time.Time `gorm:"index:idx_contacts_org_last_message,priority:2" json:"last_message_at,omitempty"`
 	LastMessagePreview string     `gorm:"type:text" json:"last_message_preview"`
 	IsRead             bool       `gorm:"default:true" json:"is_read"`
 	Tags               JSONBArray `gorm:"type:jsonb;default:'[]'" json:"tags"`
 	Metadata           JSONB      `gorm:"type:jsonb;default:'{}'" json:"metadata"`
+	CustomFields       JSONB      `gorm:"type:jsonb;default:'{}'" json:"custom_fields"`
 	LastInboundAt      *time.Time `json:"last_inbound_at,omitempty"` // When customer last sent a message (for 24h window tracking)
 
+	// Direction of the message at LastMessageAt, so the inbox can show who spoke last
+	LastMessageDirection Direction `gorm:"size:10" json:"last_message_direction,omitempty"`
+
 	// Chatbot SLA tracking
 	ChatbotLastMessageAt *time.Time `json:"chatbot_last_message_at,omitempty"` // When chatbot last sent a message
 	ChatbotReminderSent  bool       `gorm:"default:false" json:"chatbot_reminder_sent"`
 
+	// Chatbot pause: no automated responses while paused. BotResumeAt, if set, ends the pause.
+	BotPaused   bool       `gorm:"default:false" json:"bot_paused"`
+	BotResumeAt *time.Time `json:"bot_resume_at,omitempty"`
+
+	// Conversation workflow state. A snoozed conversation reopens at SnoozeUntil.
+	ConversationStatus ConversationStatus `gorm:"size:20;default:'open';index" json:"conversation_status"`
+	SnoozeUntil        *time.Time         `json:"snooze_until,omitempty"`
+
+	// Opt-out: no automated or campaign messages while opted out
+	OptedOut   bool       `gorm:"default:false;index" json:"opted_out"`
+	OptedOutAt *time.Time `json:"opted_out_at,omitempty"`
+
+	// Consent to be messaged: when it was given, where and how
+	OptInAt     *time.Time  `json:"opt_in_at,omitempty"`
+	OptInSource string      `gorm:"size:255" json:"opt_in_source,omitempty"`
+	OptInMethod OptInMethod `gorm:"size:20" json:"opt_in_method,omitempty"`
+
+	// Blocked: incoming messages are ignored and nothing is sent to the contact
+	Blocked   bool       `gorm:"default:false;index" json:"blocked"`
+	BlockedAt *time.Time `json:"blocked_at,omitempty"`
+
+	// Set when the contact went over the inbound rate limit, until an agent clears it
+	FloodFlaggedAt *time.Time `gorm:"index" json:"flood_flagged_at,omitempty"`
+
 	// Relations
 	Organization *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
 	AssignedUser *User         `gorm:"foreignKey:AssignedUserID" json:"assigned_user,omitempty"`
@@ -336,6 +468,12 @@ func (Contact) TableName() string {
 	return "contacts"
 }
 
+// HasValidOptIn reports whether the contact consented to messaging and
+// hasn't opted out since
+func (c *Contact) HasValidOptIn() bool {
+	return c.OptInAt != nil && !c.OptedOut
+}
+
 // Message represents a WhatsApp message
 type Message struct {
 	BaseModel
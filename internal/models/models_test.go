@@ -2,6 +2,7 @@ package models_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/shridarpatil/whatomate/internal/models"
 	"github.com/stretchr/testify/assert"
@@ -318,3 +319,140 @@ func TestJSONBArray_Scan(t *testing.T) {
 		})
 	}
 }
+
+func TestOrganization_CampaignMessagesPerSecond(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings models.JSONB
+		want     int
+	}{
+		{name: "nil settings use default", settings: nil, want: models.DefaultCampaignMessagesPerSecond},
+		{name: "stored as JSON number", settings: models.JSONB{"campaign_messages_per_second": float64(5)}, want: 5},
+		{name: "stored as int", settings: models.JSONB{"campaign_messages_per_second": 50}, want: 50},
+		{name: "zero uses default", settings: models.JSONB{"campaign_messages_per_second": float64(0)}, want: models.DefaultCampaignMessagesPerSecond},
+		{name: "capped at maximum", settings: models.JSONB{"campaign_messages_per_second": float64(5000)}, want: models.MaxCampaignMessagesPerSecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			org := models.Organization{Settings: tt.settings}
+			assert.Equal(t, tt.want, org.CampaignMessagesPerSecond())
+		})
+	}
+}
+
+func TestOrganization_CampaignRequiresOptIn(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings models.JSONB
+		want     bool
+	}{
+		{name: "nil settings require opt-in", settings: nil, want: true},
+		{name: "unset requires opt-in", settings: models.JSONB{"send_read_receipts": true}, want: true},
+		{name: "explicitly on", settings: models.JSONB{"campaign_require_opt_in": true}, want: true},
+		{name: "explicitly off", settings: models.JSONB{"campaign_require_opt_in": false}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			org := models.Organization{Settings: tt.settings}
+			assert.Equal(t, tt.want, org.CampaignRequiresOptIn())
+		})
+	}
+}
+
+func TestIsWithinWeeklyHours(t *testing.T) {
+	t.Parallel()
+
+	monday := time.Date(2026, time.March, 2, 10, 30, 0, 0, time.Local)
+	hours := models.JSONBArray{
+		map[string]interface{}{"day": float64(1), "enabled": true, "start_time": "09:00", "end_time": "17:00"},
+	}
+
+	assert.True(t, models.IsWithinWeeklyHours(hours, monday))
+	assert.False(t, models.IsWithinWeeklyHours(hours, monday.Add(8*time.Hour)))
+	assert.False(t, models.IsWithinWeeklyHours(hours, monday.AddDate(0, 0, 1)))
+}
+
+func TestBusinessHoursConfig_IsWithinBusinessHours(t *testing.T) {
+	t.Parallel()
+
+	hours := models.JSONBArray{
+		map[string]interface{}{"day": float64(1), "enabled": true, "start_time": "09:00", "end_time": "17:00"},
+	}
+	// Monday 04:00 UTC is Monday 09:30 in Kolkata and Sunday 23:00 in New York
+	now := time.Date(2026, time.March, 2, 4, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		timezone string
+		want     bool
+	}{
+		{name: "inside hours in configured timezone", timezone: "Asia/Kolkata", want: true},
+		{name: "previous day in configured timezone", timezone: "America/New_York", want: false},
+		{name: "UTC is before opening", timezone: "UTC", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := models.BusinessHoursConfig{Hours: hours, Timezone: tt.timezone}
+			require.NotNil(t, cfg.Location())
+			assert.Equal(t, tt.want, cfg.IsWithinBusinessHours(now))
+		})
+	}
+}
+
+func TestBusinessHoursConfig_Location(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, time.Local, models.BusinessHoursConfig{}.Location())
+	assert.Equal(t, time.Local, models.BusinessHoursConfig{Timezone: "Not/AZone"}.Location())
+	assert.Equal(t, "Europe/Berlin", models.BusinessHoursConfig{Timezone: "Europe/Berlin"}.Location().String())
+}
+
+func TestQuietHoursConfig_EndsAt(t *testing.T) {
+	t.Parallel()
+
+	overnight := models.QuietHoursConfig{Enabled: true, Start: "22:00", End: "07:00", Timezone: "UTC"}
+	daytime := models.QuietHoursConfig{Enabled: true, Start: "13:00", End: "14:30", Timezone: "UTC"}
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 3, day, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		cfg    models.QuietHoursConfig
+		now    time.Time
+		quiet  bool
+		endsAt time.Time
+	}{
+		{name: "before overnight window", cfg: overnight, now: at(10, 21, 59)},
+		{name: "overnight window start", cfg: overnight, now: at(10, 22, 0), quiet: true, endsAt: at(11, 7, 0)},
+		{name: "after midnight", cfg: overnight, now: at(11, 3, 15), quiet: true, endsAt: at(11, 7, 0)},
+		{name: "overnight window end", cfg: overnight, now: at(11, 7, 0)},
+		{name: "inside daytime window", cfg: daytime, now: at(10, 14, 0), quiet: true, endsAt: at(10, 14, 30)},
+		{name: "outside daytime window", cfg: daytime, now: at(10, 15, 0)},
+		{name: "disabled", cfg: models.QuietHoursConfig{Start: "22:00", End: "07:00"}, now: at(10, 23, 0)},
+		{name: "malformed", cfg: models.QuietHoursConfig{Enabled: true, Start: "late", End: "07:00"}, now: at(10, 23, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endsAt, quiet := tt.cfg.EndsAt(tt.now)
+			assert.Equal(t, tt.quiet, quiet)
+			if tt.quiet {
+				assert.True(t, tt.endsAt.Equal(endsAt), "ends at %v, want %v", endsAt, tt.endsAt)
+			}
+		})
+	}
+
+	// The window is read in its own timezone: 23:00 in Kolkata is 17:30 UTC
+	kolkata := models.QuietHoursConfig{Enabled: true, Start: "22:00", End: "07:00", Timezone: "Asia/Kolkata"}
+	endsAt, quiet := kolkata.EndsAt(at(10, 17, 30))
+	assert.True(t, quiet)
+	assert.True(t, at(11, 1, 30).Equal(endsAt), "07:00 in Kolkata is 01:30 UTC, got %v", endsAt)
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// PhoneBlacklist is a phone number an organization never messages or accepts messages from
+type PhoneBlacklist struct {
+	BaseModel
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_phone_blacklist_org_phone" json:"organization_id"`
+	PhoneNumber    string     `gorm:"size:50;not null;uniqueIndex:idx_phone_blacklist_org_phone" json:"phone_number"` // Stored without leading "+"
+	Reason         string     `gorm:"type:text" json:"reason"`
+	CreatedByID    *uuid.UUID `gorm:"type:uuid" json:"created_by_id,omitempty"`
+
+	// Relations
+	Organization *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+}
+
+func (PhoneBlacklist) TableName() string {
+	return "phone_blacklist"
+}
@@ -26,6 +26,11 @@ type CustomRole struct {
 	IsDefault      bool         `gorm:"default:false" json:"is_default"` // default role for new users in org
 	Permissions    []Permission `gorm:"many2many:role_permissions;" json:"permissions"`
 
+	// Contact visibility: when ContactsOwnOnly is set, members only see contacts
+	// assigned to them (plus unassigned ones if ContactsIncludeUnassigned is set)
+	ContactsOwnOnly           bool `gorm:"default:false" json:"contacts_own_only"`
+	ContactsIncludeUnassigned bool `gorm:"default:false" json:"contacts_include_unassigned"`
+
 	// Relations
 	Organization *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
 }
@@ -75,6 +80,7 @@ const (
 	ResourceIVRFlows        = "ivr_flows"
 	ResourceCallTransfers   = "call_transfers"
 	ResourceOutgoingCalls   = "outgoing_calls"
+	ResourcePII             = "pii"
 )
 
 // PermissionAction constants for available actions
@@ -173,6 +179,7 @@ func DefaultPermissions() []Permission {
 		{Resource: ResourceAnalytics, Action: ActionRead, Description: "View analytics dashboard"},
 		{Resource: ResourceAnalytics, Action: ActionWrite, Description: "Create and edit dashboard widgets"},
 		{Resource: ResourceAnalytics, Action: ActionDelete, Description: "Delete dashboard widgets"},
+		{Resource: ResourceAnalytics, Action: ActionExport, Description: "Export analytics reports"},
 		{Resource: ResourceAnalyticsAgents, Action: ActionRead, Description: "View agent analytics"},
 
 		// Transfers
@@ -221,6 +228,9 @@ func DefaultPermissions() []Permission {
 		// Outgoing Calls
 		{Resource: ResourceOutgoingCalls, Action: ActionRead, Description: "View outgoing call status"},
 		{Resource: ResourceOutgoingCalls, Action: ActionWrite, Description: "Initiate outgoing calls"},
+
+		// PII
+		{Resource: ResourcePII, Action: ActionRead, Description: "View sensitive session data unmasked"},
 	}
 }
 
@@ -257,7 +267,7 @@ func SystemRolePermissions() map[string][]string {
 		// Tags
 		"tags:read", "tags:write", "tags:delete",
 		// Analytics
-		"analytics:read", "analytics.agents:read",
+		"analytics:read", "analytics:export", "analytics.agents:read",
 		// Transfers
 		"transfers:read", "transfers:write", "transfers:pickup",
 		// Webhooks
@@ -273,6 +283,8 @@ func SystemRolePermissions() map[string][]string {
 		"ivr_flows:read", "ivr_flows:write", "ivr_flows:delete",
 		"call_transfers:read", "call_transfers:write",
 		"outgoing_calls:read", "outgoing_calls:write",
+		// PII
+		"pii:read",
 	}
 
 	agentPermissions := []string{
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// SavedView is a named set of inbox filters a user can reapply. Views are
+// private to the user who saved them within an organization.
+type SavedView struct {
+	BaseModel
+	OrganizationID uuid.UUID `gorm:"type:uuid;index;not null" json:"organization_id"`
+	UserID         uuid.UUID `gorm:"type:uuid;index;not null" json:"user_id"`
+	Name           string    `gorm:"size:100;not null" json:"name"`
+	Filters        JSONB     `gorm:"type:jsonb;default:'{}'" json:"filters"`
+}
+
+func (SavedView) TableName() string {
+	return "saved_views"
+}
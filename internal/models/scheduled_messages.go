@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledMessage is an outgoing message to a contact that is held until SendAt
+type ScheduledMessage struct {
+	BaseModel
+	OrganizationID  uuid.UUID              `gorm:"type:uuid;index;not null" json:"organization_id"`
+	ContactID       uuid.UUID              `gorm:"type:uuid;index;not null" json:"contact_id"`
+	CreatedByID     uuid.UUID              `gorm:"type:uuid;not null" json:"created_by_id"`
+	WhatsAppAccount string                 `gorm:"size:100" json:"whatsapp_account"` // Requested account; empty uses the contact's account
+	MessageType     MessageType            `gorm:"size:20;not null" json:"message_type"`
+	Content         string                 `gorm:"type:text" json:"content"`
+	InteractiveData JSONB                  `gorm:"type:jsonb" json:"interactive_data,omitempty"`
+	SendAt          time.Time              `gorm:"index;not null" json:"send_at"`
+	Status          ScheduledMessageStatus `gorm:"size:20;index;not null;default:'pending'" json:"status"`
+	SentAt          *time.Time             `json:"sent_at,omitempty"`
+	MessageID       *uuid.UUID             `gorm:"type:uuid" json:"message_id,omitempty"` // Message created when sent
+	ErrorMessage    string                 `gorm:"type:text" json:"error_message,omitempty"`
+
+	// Relations
+	Organization *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	Contact      *Contact      `gorm:"foreignKey:ContactID" json:"contact,omitempty"`
+	CreatedBy    *User         `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+}
+
+func (ScheduledMessage) TableName() string {
+	return "scheduled_messages"
+}
@@ -38,3 +38,23 @@ func IsValidTagColor(color string) bool {
 	}
 	return false
 }
+
+// ContactTagOperation records a bulk tag change so it can be reviewed later
+type ContactTagOperation struct {
+	BaseModel
+	OrganizationID uuid.UUID   `gorm:"type:uuid;index;not null" json:"organization_id"`
+	UserID         uuid.UUID   `gorm:"type:uuid;index;not null" json:"user_id"`
+	AddTags        StringArray `gorm:"type:jsonb;default:'[]'" json:"add_tags"`
+	RemoveTags     StringArray `gorm:"type:jsonb;default:'[]'" json:"remove_tags"`
+	ContactIDs     StringArray `gorm:"type:jsonb;default:'[]'" json:"contact_ids"` // Set when contacts were selected by ID
+	Filter         JSONB       `gorm:"type:jsonb" json:"filter"`                   // Set when contacts were selected by filter
+	Matched        int64       `json:"matched"`
+	Affected       int64       `json:"affected"` // Contacts whose tags actually changed
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (ContactTagOperation) TableName() string {
+	return "contact_tag_operations"
+}
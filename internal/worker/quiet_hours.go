@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shridarpatil/whatomate/internal/models"
+)
+
+// quietHoursEnd reports whether campaign sends for the account are held back
+// by quiet hours at now and, if so, when the window closes. Account settings
+// take precedence over the organization defaults.
+func (w *Worker) quietHoursEnd(orgID uuid.UUID, accountName string, now time.Time) (time.Time, bool) {
+	var settings models.ChatbotSettings
+	if err := w.DB.Select("id", "whats_app_account", "quiet_hours_enabled", "quiet_hours_start", "quiet_hours_end", "quiet_hours_timezone").
+		Where("organization_id = ? AND (whats_app_account = ? OR whats_app_account = '')", orgID, accountName).
+		Order("CASE WHEN whats_app_account = '' THEN 1 ELSE 0 END").
+		First(&settings).Error; err != nil {
+		return time.Time{}, false
+	}
+	return settings.QuietHours.EndsAt(now)
+}
+
+// deferRecipient holds a recipient back until quiet hours end. The recipient
+// stays pending and the server re-queues it once deferred_until passes.
+func (w *Worker) deferRecipient(recipientID uuid.UUID, until time.Time) {
+	if err := w.DB.Model(&models.BulkMessageRecipient{}).
+		Where("id = ? AND status = ?", recipientID, models.MessageStatusPending).
+		Update("deferred_until", until).Error; err != nil {
+		w.Log.Error("Failed to defer recipient", "error", err, "recipient_id", recipientID)
+	}
+}
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/shridarpatil/whatomate/internal/models"
+)
+
+// sendSlotKeyPrefix prefixes the Redis key holding an organization's next free send slot
+const sendSlotKeyPrefix = "whatomate:campaign:send_slot:"
+
+// sendSlotScript reserves the next campaign send slot for an organization.
+// Slots are spaced ARGV[2] microseconds apart and shared by every worker, so
+// bursts are smoothed into an even rate. It returns how many microseconds the
+// caller must wait before its slot.
+var sendSlotScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local slot = tonumber(redis.call('GET', KEYS[1]) or '0')
+if slot < now then
+	slot = now
+end
+local nextSlot = slot + interval
+redis.call('SET', KEYS[1], string.format('%d', nextSlot), 'PX', math.ceil((nextSlot - now) / 1000) + 1000)
+return slot - now
+`)
+
+// waitForSendSlot blocks until the organization's campaign send rate allows
+// another message. Throttling is skipped when Redis is unavailable.
+func (w *Worker) waitForSendSlot(ctx context.Context, orgID uuid.UUID) error {
+	if w.Redis == nil {
+		return nil
+	}
+
+	var org models.Organization
+	if err := w.DB.Select("id", "settings").Where("id = ?", orgID).First(&org).Error; err != nil {
+		return fmt.Errorf("failed to load organization: %w", err)
+	}
+	interval := time.Second / time.Duration(org.CampaignMessagesPerSecond())
+
+	wait, err := sendSlotScript.Run(ctx, w.Redis, []string{sendSlotKeyPrefix + orgID.String()},
+		time.Now().UnixMicro(), interval.Microseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to reserve send slot: %w", err)
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(wait) * time.Microsecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shridarpatil/whatomate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorker_waitForSendSlot_SpacesSends(t *testing.T) {
+	w := testWorker(t)
+	if w.Redis == nil {
+		t.Skip("Redis not available, skipping test")
+	}
+	org, _, _, _ := createMinimalCampaignData(t, w, models.CampaignStatusProcessing)
+	require.NoError(t, w.DB.Model(org).Update("settings", models.JSONB{"campaign_messages_per_second": 10}).Error)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, w.waitForSendSlot(context.Background(), org.ID))
+	}
+
+	// The first send goes immediately, the next three wait 100ms each
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+}
+
+func TestWorker_waitForSendSlot_ContextCancelled(t *testing.T) {
+	w := testWorker(t)
+	if w.Redis == nil {
+		t.Skip("Redis not available, skipping test")
+	}
+	org, _, _, _ := createMinimalCampaignData(t, w, models.CampaignStatusProcessing)
+	require.NoError(t, w.DB.Model(org).Update("settings", models.JSONB{"campaign_messages_per_second": 1}).Error)
+
+	// Take the only slot this second so the next caller has to wait
+	require.NoError(t, w.waitForSendSlot(context.Background(), org.ID))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, w.waitForSendSlot(ctx, org.ID), context.DeadlineExceeded)
+}
+
+func TestWorker_waitForSendSlot_NoRedis(t *testing.T) {
+	w := testWorker(t)
+	w.Redis = nil
+	org, _, _, _ := createMinimalCampaignData(t, w, models.CampaignStatusProcessing)
+
+	assert.NoError(t, w.waitForSendSlot(context.Background(), org.ID))
+}
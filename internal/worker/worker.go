@@ -91,6 +91,13 @@ func (w *Worker) HandleRecipientJob(ctx context.Context, job *queue.RecipientJob
 	}
 	w.decryptAccountSecrets(&account)
 
+	// Hold the message back while the account is in quiet hours
+	if until, quiet := w.quietHoursEnd(job.OrganizationID, campaign.WhatsAppAccount, time.Now()); quiet {
+		w.Log.Info("Quiet hours, deferring recipient", "campaign_id", job.CampaignID, "recipient_id", job.RecipientID, "until", until)
+		w.deferRecipient(job.RecipientID, until)
+		return nil
+	}
+
 	// Get or create contact for this recipient
 	contact, _, err := contactutil.GetOrCreateContact(w.DB, job.OrganizationID, job.PhoneNumber, job.RecipientName)
 	if err != nil || contact == nil {
@@ -100,6 +107,20 @@ func (w *Worker) HandleRecipientJob(ctx context.Context, job *queue.RecipientJob
 		return nil // Don't retry
 	}
 
+	// Only message contacts who consented and aren't blocked
+	reason, err := w.consentSkipReason(job.OrganizationID, contact)
+	if err != nil {
+		w.Log.Error("Failed to check contact consent", "error", err, "contact_id", contact.ID)
+		return err
+	}
+	if reason != "" {
+		w.Log.Info("Skipping recipient", "reason", reason, "campaign_id", job.CampaignID, "contact_id", contact.ID)
+		w.updateRecipientStatus(job.RecipientID, models.MessageStatusSkipped, "", reason)
+		w.incrementCampaignCount(job.CampaignID, "skipped_count")
+		w.checkCampaignCompletion(ctx, job.CampaignID, job.OrganizationID)
+		return nil
+	}
+
 	// Build recipient for sending
 	recipient := &models.BulkMessageRecipient{
 		PhoneNumber:    job.PhoneNumber,
@@ -107,6 +128,14 @@ func (w *Worker) HandleRecipientJob(ctx context.Context, job *queue.RecipientJob
 		TemplateParams: job.TemplateParams,
 	}
 
+	// Honor the organization's send rate so bursts don't trip WhatsApp limits
+	if err := w.waitForSendSlot(ctx, job.OrganizationID); err != nil {
+		if ctx.Err() != nil {
+			return err // Shutting down, leave the job for redelivery
+		}
+		w.Log.Warn("Send throttling unavailable", "error", err, "campaign_id", job.CampaignID)
+	}
+
 	// Send template message
 	waMessageID, err := w.sendTemplateMessage(ctx, &account, campaign.Template, recipient, campaign.HeaderMediaID)
 
@@ -146,6 +175,16 @@ func (w *Worker) HandleRecipientJob(ctx context.Context, job *queue.RecipientJob
 	// Save message record
 	if err := w.DB.Create(&message).Error; err != nil {
 		w.Log.Error("Failed to save message", "error", err, "recipient", job.PhoneNumber)
+	} else {
+		preview := "[Template]"
+		if campaign.Template != nil {
+			preview = fmt.Sprintf("[Template: %s]", campaign.Template.DisplayName)
+		}
+		w.DB.Model(contact).Updates(map[string]any{
+			"last_message_at":        message.CreatedAt,
+			"last_message_preview":   preview,
+			"last_message_direction": models.DirectionOutgoing,
+		})
 	}
 
 	// Check if campaign is complete (all recipients processed)
@@ -154,6 +193,37 @@ func (w *Worker) HandleRecipientJob(ctx context.Context, job *queue.RecipientJob
 	return nil
 }
 
+// consentSkipReason returns why a contact must not receive campaign messages,
+// or "" if they may: their number is blacklisted, they are blocked, they opted
+// out, or the organization requires an opt-in they haven't given
+func (w *Worker) consentSkipReason(orgID uuid.UUID, contact *models.Contact) (string, error) {
+	blacklisted, err := contactutil.IsPhoneBlacklisted(w.DB, orgID, contact.PhoneNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to check phone blacklist: %w", err)
+	}
+	if blacklisted {
+		return "Phone number is blacklisted", nil
+	}
+	if contact.Blocked {
+		return "Contact is blocked", nil
+	}
+	if contact.OptedOut {
+		return "Contact has opted out", nil
+	}
+	if contact.HasValidOptIn() {
+		return "", nil
+	}
+
+	var org models.Organization
+	if err := w.DB.Select("id", "settings").Where("id = ?", orgID).First(&org).Error; err != nil {
+		return "", fmt.Errorf("failed to load organization: %w", err)
+	}
+	if org.CampaignRequiresOptIn() {
+		return "No valid opt-in", nil
+	}
+	return "", nil
+}
+
 // updateRecipientStatus updates the recipient's status in the database
 func (w *Worker) updateRecipientStatus(recipientID uuid.UUID, status models.MessageStatus, waMessageID, errorMsg string) {
 	updates := map[string]interface{}{
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shridarpatil/whatomate/internal/config"
@@ -83,9 +84,11 @@ func createTestCampaignData(t *testing.T, w *Worker) (*models.Organization, *mod
 	uniqueID := uuid.New().String()[:8]
 
 	// Create organization
+	// Campaigns here go to recipients without recorded consent
 	org := &models.Organization{
-		Name: "Test Org " + uniqueID,
-		Slug: "test-org-" + uniqueID,
+		Name:     "Test Org " + uniqueID,
+		Slug:     "test-org-" + uniqueID,
+		Settings: models.JSONB{"campaign_require_opt_in": false},
 	}
 	require.NoError(t, w.DB.Create(org).Error)
 
@@ -181,6 +184,52 @@ func TestWorker_HandleRecipientJob_CampaignPaused(t *testing.T) {
 	assert.Equal(t, models.MessageStatusPending, updatedRecipient.Status)
 }
 
+func TestWorker_HandleRecipientJob_DeferredDuringQuietHours(t *testing.T) {
+	w := testWorker(t)
+	org, account, _, campaign, recipient := createTestCampaignData(t, w)
+
+	// Quiet hours for the campaign's account, from an hour ago until an hour from now
+	now := time.Now().UTC()
+	require.NoError(t, w.DB.Create(&models.ChatbotSettings{
+		BaseModel:       models.BaseModel{ID: uuid.New()},
+		OrganizationID:  org.ID,
+		WhatsAppAccount: account.Name,
+		QuietHours: models.QuietHoursConfig{
+			Enabled:  true,
+			Start:    now.Add(-time.Hour).Format("15:04"),
+			End:      now.Add(time.Hour).Format("15:04"),
+			Timezone: "UTC",
+		},
+	}).Error)
+
+	// Any send would fail the test
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		t.Error("no message should be sent during quiet hours")
+	}))
+	defer server.Close()
+	w.WhatsApp = whatsapp.NewWithBaseURL(w.Log, server.URL)
+
+	job := &queue.RecipientJob{
+		CampaignID:     campaign.ID,
+		RecipientID:    recipient.ID,
+		OrganizationID: org.ID,
+		PhoneNumber:    recipient.PhoneNumber,
+		RecipientName:  recipient.RecipientName,
+	}
+	require.NoError(t, w.HandleRecipientJob(context.Background(), job))
+
+	var deferred models.BulkMessageRecipient
+	require.NoError(t, w.DB.First(&deferred, recipient.ID).Error)
+	assert.Equal(t, models.MessageStatusPending, deferred.Status)
+	require.NotNil(t, deferred.DeferredUntil)
+	assert.WithinDuration(t, now.Add(time.Hour), *deferred.DeferredUntil, time.Minute)
+
+	var updatedCampaign models.BulkMessageCampaign
+	require.NoError(t, w.DB.First(&updatedCampaign, campaign.ID).Error)
+	assert.Equal(t, models.CampaignStatusProcessing, updatedCampaign.Status)
+	assert.Zero(t, updatedCampaign.SentCount)
+}
+
 func TestWorker_HandleRecipientJob_CampaignCancelled(t *testing.T) {
 	w := testWorker(t)
 	org, _, _, campaign, recipient := createTestCampaignData(t, w)
@@ -253,9 +302,11 @@ func createMinimalCampaignData(t *testing.T, w *Worker, status models.CampaignSt
 	t.Helper()
 	uniqueID := uuid.New().String()[:8]
 
+	// Campaigns here go to recipients without recorded consent
 	org := &models.Organization{
-		Name: "Test Org " + uniqueID,
-		Slug: "test-org-" + uniqueID,
+		Name:     "Test Org " + uniqueID,
+		Slug:     "test-org-" + uniqueID,
+		Settings: models.JSONB{"campaign_require_opt_in": false},
 	}
 	require.NoError(t, w.DB.Create(org).Error)
 
@@ -663,6 +714,137 @@ func TestWorker_HandleRecipientJob_WhatsAppError(t *testing.T) {
 	assert.Equal(t, 1, updatedCampaign.FailedCount)
 }
 
+func TestWorker_HandleRecipientJob_OptedOutContactSkipped(t *testing.T) {
+	w := testWorker(t)
+	org, _, _, campaign, recipient := createTestCampaignData(t, w)
+
+	contact := &models.Contact{
+		OrganizationID: org.ID,
+		PhoneNumber:    recipient.PhoneNumber,
+		OptedOut:       true,
+	}
+	require.NoError(t, w.DB.Create(contact).Error)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	w.WhatsApp = whatsapp.NewWithBaseURL(w.Log, server.URL)
+
+	job := &queue.RecipientJob{
+		CampaignID:     campaign.ID,
+		RecipientID:    recipient.ID,
+		OrganizationID: org.ID,
+		PhoneNumber:    recipient.PhoneNumber,
+		RecipientName:  recipient.RecipientName,
+		TemplateParams: recipient.TemplateParams,
+	}
+	require.NoError(t, w.HandleRecipientJob(context.Background(), job))
+
+	assert.Zero(t, requests, "nothing should be sent to an opted-out contact")
+
+	var updatedRecipient models.BulkMessageRecipient
+	require.NoError(t, w.DB.First(&updatedRecipient, recipient.ID).Error)
+	assert.Equal(t, models.MessageStatusSkipped, updatedRecipient.Status)
+	assert.Equal(t, "Contact has opted out", updatedRecipient.ErrorMessage)
+
+	var updatedCampaign models.BulkMessageCampaign
+	require.NoError(t, w.DB.First(&updatedCampaign, campaign.ID).Error)
+	assert.Equal(t, 1, updatedCampaign.SkippedCount)
+	assert.Zero(t, updatedCampaign.FailedCount)
+	assert.Equal(t, models.CampaignStatusCompleted, updatedCampaign.Status)
+}
+
+func TestWorker_HandleRecipientJob_RequireOptIn(t *testing.T) {
+	w := testWorker(t)
+	org, account, _, campaign, recipient := createTestCampaignData(t, w)
+	require.NoError(t, w.DB.Model(org).Update("settings", models.JSONB{"campaign_require_opt_in": true}).Error)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+			"messages": []map[string]interface{}{{"id": "wamid.optin123"}},
+		})
+	}))
+	defer server.Close()
+	require.NoError(t, w.DB.Model(account).Update("api_version", "v21.0").Error)
+	w.WhatsApp = whatsapp.NewWithBaseURL(w.Log, server.URL)
+
+	consented := &models.BulkMessageRecipient{
+		CampaignID:  campaign.ID,
+		PhoneNumber: "1112224444",
+		Status:      models.MessageStatusPending,
+	}
+	require.NoError(t, w.DB.Create(consented).Error)
+	optInAt := time.Now().Add(-24 * time.Hour)
+	require.NoError(t, w.DB.Create(&models.Contact{
+		OrganizationID: org.ID,
+		PhoneNumber:    consented.PhoneNumber,
+		OptInAt:        &optInAt,
+		OptInSource:    "signup form",
+		OptInMethod:    models.OptInMethodWebForm,
+	}).Error)
+
+	for _, r := range []*models.BulkMessageRecipient{recipient, consented} {
+		require.NoError(t, w.HandleRecipientJob(context.Background(), &queue.RecipientJob{
+			CampaignID:     campaign.ID,
+			RecipientID:    r.ID,
+			OrganizationID: org.ID,
+			PhoneNumber:    r.PhoneNumber,
+			RecipientName:  r.RecipientName,
+			TemplateParams: r.TemplateParams,
+		}))
+	}
+
+	var skipped, sent models.BulkMessageRecipient
+	require.NoError(t, w.DB.First(&skipped, recipient.ID).Error)
+	assert.Equal(t, models.MessageStatusSkipped, skipped.Status)
+	assert.Equal(t, "No valid opt-in", skipped.ErrorMessage)
+	require.NoError(t, w.DB.First(&sent, consented.ID).Error)
+	assert.Equal(t, models.MessageStatusSent, sent.Status)
+
+	var updatedCampaign models.BulkMessageCampaign
+	require.NoError(t, w.DB.First(&updatedCampaign, campaign.ID).Error)
+	assert.Equal(t, 1, updatedCampaign.SkippedCount)
+	assert.Equal(t, 1, updatedCampaign.SentCount)
+}
+
+func TestWorker_HandleRecipientJob_BlacklistedNumberSkipped(t *testing.T) {
+	w := testWorker(t)
+	org, _, _, campaign, recipient := createTestCampaignData(t, w)
+	require.NoError(t, w.DB.Create(&models.PhoneBlacklist{
+		OrganizationID: org.ID,
+		PhoneNumber:    "+" + recipient.PhoneNumber,
+	}).Error)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	w.WhatsApp = whatsapp.NewWithBaseURL(w.Log, server.URL)
+
+	job := &queue.RecipientJob{
+		CampaignID:     campaign.ID,
+		RecipientID:    recipient.ID,
+		OrganizationID: org.ID,
+		PhoneNumber:    recipient.PhoneNumber,
+		RecipientName:  recipient.RecipientName,
+		TemplateParams: recipient.TemplateParams,
+	}
+	require.NoError(t, w.HandleRecipientJob(context.Background(), job))
+
+	assert.Zero(t, requests, "nothing should be sent to a blacklisted number")
+
+	var updatedRecipient models.BulkMessageRecipient
+	require.NoError(t, w.DB.First(&updatedRecipient, recipient.ID).Error)
+	assert.Equal(t, models.MessageStatusSkipped, updatedRecipient.Status)
+	assert.Equal(t, "Phone number is blacklisted", updatedRecipient.ErrorMessage)
+}
+
 func TestWorker_HandleRecipientJob_CreatesContact(t *testing.T) {
 	w := testWorker(t)
 	org, account, _, campaign, recipient := createTestCampaignData(t, w)
@@ -415,6 +415,45 @@ func (c *Client) MarkMessageRead(ctx context.Context, account *Account, messageI
 	return nil
 }
 
+// SendTypingIndicator shows a typing indicator to the sender of messageID and
+// marks that message read. WhatsApp clears the indicator once a reply is sent
+// or after 25 seconds, whichever comes first.
+func (c *Client) SendTypingIndicator(ctx context.Context, account *Account, messageID string) error {
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"status":            "read",
+		"message_id":        messageID,
+		"typing_indicator": map[string]string{
+			"type": "text",
+		},
+	}
+
+	url := c.buildMessagesURL(account)
+	c.Log.Debug("Sending typing indicator", "message_id", messageID)
+
+	_, err := c.doRequest(ctx, "POST", url, payload, account.AccessToken)
+	if err != nil {
+		return fmt.Errorf("failed to send typing indicator: %w", err)
+	}
+
+	c.Log.Debug("Typing indicator sent", "message_id", messageID)
+	return nil
+}
+
+// DeleteMessage revokes a previously sent message so it is removed for the recipient as well
+func (c *Client) DeleteMessage(ctx context.Context, account *Account, messageID string) error {
+	url := fmt.Sprintf("%s/%s/%s", c.getBaseURL(), account.APIVersion, messageID)
+	c.Log.Debug("Deleting message", "message_id", messageID)
+
+	_, err := c.doRequest(ctx, http.MethodDelete, url, nil, account.AccessToken)
+	if err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+
+	c.Log.Info("Message deleted", "message_id", messageID)
+	return nil
+}
+
 // ResumableUploadResponse represents response from creating upload session
 type ResumableUploadResponse struct {
 	ID string `json:"id"` // Upload session ID
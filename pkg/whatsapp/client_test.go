@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -395,6 +396,133 @@ func TestClient_MarkMessageRead(t *testing.T) {
 	}
 }
 
+func TestClient_SendTypingIndicator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		serverResponse func(t *testing.T, w http.ResponseWriter, r *http.Request)
+		wantErr        bool
+	}{
+		{
+			name: "successful typing indicator",
+			serverResponse: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Contains(t, r.URL.Path, "/messages")
+
+				var body map[string]interface{}
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				assert.Equal(t, "read", body["status"])
+				assert.Equal(t, "wamid.test123", body["message_id"])
+				typing, ok := body["typing_indicator"].(map[string]interface{})
+				require.True(t, ok)
+				assert.Equal(t, "text", typing["type"])
+
+				w.WriteHeader(http.StatusOK)
+				_ = json.NewEncoder(w).Encode(map[string]bool{"success": true})
+			},
+			wantErr: false,
+		},
+		{
+			name: "api error",
+			serverResponse: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.serverResponse(t, w, r)
+			}))
+			defer server.Close()
+
+			log := testutil.NopLogger()
+			client := whatsapp.NewWithTimeout(log, 5*time.Second)
+			client.HTTPClient = &http.Client{
+				Transport: &testServerTransport{serverURL: server.URL},
+			}
+
+			account := testAccount(server.URL)
+			ctx := testutil.TestContext(t)
+
+			err := client.SendTypingIndicator(ctx, account, "wamid.test123")
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_DeleteMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		messageID      string
+		serverResponse func(t *testing.T, w http.ResponseWriter, r *http.Request)
+		wantErr        bool
+	}{
+		{
+			name:      "successful delete",
+			messageID: "wamid.delete123",
+			serverResponse: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodDelete, r.Method)
+				assert.True(t, strings.HasSuffix(r.URL.Path, "/wamid.delete123"))
+
+				w.WriteHeader(http.StatusOK)
+				_ = json.NewEncoder(w).Encode(map[string]bool{"success": true})
+			},
+			wantErr: false,
+		},
+		{
+			name:      "message not found",
+			messageID: "wamid.invalid",
+			serverResponse: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.serverResponse(t, w, r)
+			}))
+			defer server.Close()
+
+			log := testutil.NopLogger()
+			client := whatsapp.NewWithTimeout(log, 5*time.Second)
+			client.HTTPClient = &http.Client{
+				Transport: &testServerTransport{serverURL: server.URL},
+			}
+
+			account := testAccount(server.URL)
+			ctx := testutil.TestContext(t)
+
+			err := client.DeleteMessage(ctx, account, tt.messageID)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestClient_SendImageMessage(t *testing.T) {
 	t.Parallel()
 
@@ -152,6 +152,86 @@ func (c *Client) SendInteractiveButtons(ctx context.Context, account *Account, p
 	return messageID, nil
 }
 
+// SendInteractiveList sends an interactive list message. The recipient opens
+// the list with buttonText and picks one row.
+func (c *Client) SendInteractiveList(ctx context.Context, account *Account, phoneNumber, bodyText, buttonText string, sections []ListSection) (string, error) {
+	if buttonText == "" {
+		return "", fmt.Errorf("button text is required")
+	}
+	if len(sections) == 0 {
+		return "", fmt.Errorf("at least one section is required")
+	}
+	rowCount := 0
+	for _, section := range sections {
+		rowCount += len(section.Rows)
+	}
+	if rowCount == 0 {
+		return "", fmt.Errorf("at least one row is required")
+	}
+	if rowCount > 10 {
+		return "", fmt.Errorf("maximum 10 rows allowed")
+	}
+
+	sectionList := make([]map[string]interface{}, 0, len(sections))
+	for _, section := range sections {
+		rows := make([]map[string]interface{}, 0, len(section.Rows))
+		for _, row := range section.Rows {
+			r := map[string]interface{}{
+				"id":    row.ID,
+				"title": row.Title,
+			}
+			if row.Description != "" {
+				r["description"] = row.Description
+			}
+			rows = append(rows, r)
+		}
+		s := map[string]interface{}{"rows": rows}
+		if section.Title != "" {
+			s["title"] = section.Title
+		}
+		sectionList = append(sectionList, s)
+	}
+
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                phoneNumber,
+		"type":              "interactive",
+		"interactive": map[string]interface{}{
+			"type": "list",
+			"body": map[string]interface{}{
+				"text": bodyText,
+			},
+			"action": map[string]interface{}{
+				"button":   buttonText,
+				"sections": sectionList,
+			},
+		},
+	}
+
+	url := c.buildMessagesURL(account)
+	c.Log.Debug("Sending interactive list message", "phone", phoneNumber, "row_count", rowCount)
+
+	respBody, err := c.doRequest(ctx, "POST", url, payload, account.AccessToken)
+	if err != nil {
+		c.Log.Error("Failed to send interactive list message", "error", err, "phone", phoneNumber)
+		return "", fmt.Errorf("failed to send interactive list message: %w", err)
+	}
+
+	var resp MetaAPIResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(resp.Messages) == 0 {
+		return "", fmt.Errorf("no message ID in response")
+	}
+
+	messageID := resp.Messages[0].ID
+	c.Log.Info("Interactive list message sent", "message_id", messageID, "phone", phoneNumber)
+	return messageID, nil
+}
+
 // SendCTAURLButton sends an interactive message with a CTA URL button
 // This opens a URL when clicked instead of sending a reply
 func (c *Client) SendCTAURLButton(ctx context.Context, account *Account, phoneNumber, bodyText, buttonText, url string) (string, error) {
@@ -233,9 +313,33 @@ func BodyParamsToComponents(bodyParams map[string]string) []map[string]interface
 		return nil
 	}
 
+	return []map[string]interface{}{
+		{
+			"type":       "body",
+			"parameters": textParameters(bodyParams),
+		},
+	}
+}
+
+// HeaderParamsToComponent converts the parameters of a text header into a
+// WhatsApp template header component. Returns nil when there are none.
+func HeaderParamsToComponent(headerParams map[string]string) map[string]interface{} {
+	if len(headerParams) == 0 {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"type":       "header",
+		"parameters": textParameters(headerParams),
+	}
+}
+
+// textParameters converts a params map into text template parameters in key
+// order, naming them when the keys aren't positional
+func textParameters(values map[string]string) []map[string]interface{} {
 	// Check if using named parameters (non-numeric keys like "name", "order_id")
 	isNamedParams := false
-	for key := range bodyParams {
+	for key := range values {
 		if _, err := strconv.Atoi(key); err != nil {
 			isNamedParams = true
 			break
@@ -243,30 +347,24 @@ func BodyParamsToComponents(bodyParams map[string]string) []map[string]interface
 	}
 
 	// Get sorted keys for deterministic ordering
-	keys := make([]string, 0, len(bodyParams))
-	for k := range bodyParams {
+	keys := make([]string, 0, len(values))
+	for k := range values {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 
-	params := make([]map[string]interface{}, 0, len(bodyParams))
+	params := make([]map[string]interface{}, 0, len(values))
 	for _, key := range keys {
 		param := map[string]interface{}{
 			"type": "text",
-			"text": bodyParams[key],
+			"text": values[key],
 		}
 		if isNamedParams {
 			param["parameter_name"] = key
 		}
 		params = append(params, param)
 	}
-
-	return []map[string]interface{}{
-		{
-			"type":       "body",
-			"parameters": params,
-		},
-	}
+	return params
 }
 
 // SendFlowMessage sends an interactive WhatsApp Flow message
@@ -2,6 +2,7 @@ package whatsapp_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -406,6 +407,110 @@ func TestClient_SendCTAURLButton(t *testing.T) {
 	}
 }
 
+func TestClient_SendInteractiveList(t *testing.T) {
+	t.Parallel()
+
+	rows := func(n int) []whatsapp.ListRow {
+		result := make([]whatsapp.ListRow, n)
+		for i := range result {
+			result[i] = whatsapp.ListRow{ID: fmt.Sprintf("row_%d", i), Title: fmt.Sprintf("Row %d", i)}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name            string
+		buttonText      string
+		sections        []whatsapp.ListSection
+		wantErr         bool
+		wantErrContains string
+	}{
+		{
+			name:       "valid list",
+			buttonText: "View menu",
+			sections: []whatsapp.ListSection{
+				{Title: "Pizza", Rows: []whatsapp.ListRow{{ID: "margherita", Title: "Margherita", Description: "Tomato and mozzarella"}}},
+				{Title: "Drinks", Rows: []whatsapp.ListRow{{ID: "cola", Title: "Cola"}}},
+			},
+		},
+		{
+			name:            "missing button text",
+			sections:        []whatsapp.ListSection{{Rows: rows(1)}},
+			wantErr:         true,
+			wantErrContains: "button text is required",
+		},
+		{
+			name:            "no rows",
+			buttonText:      "View",
+			sections:        []whatsapp.ListSection{{Title: "Empty"}},
+			wantErr:         true,
+			wantErrContains: "at least one row is required",
+		},
+		{
+			name:            "too many rows",
+			buttonText:      "View",
+			sections:        []whatsapp.ListSection{{Title: "A", Rows: rows(6)}, {Title: "B", Rows: rows(5)}},
+			wantErr:         true,
+			wantErrContains: "maximum 10 rows allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var capturedBody map[string]interface{}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&capturedBody)
+				w.WriteHeader(http.StatusOK)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"messages": []map[string]string{{"id": "wamid.list123"}},
+				})
+			}))
+			defer server.Close()
+
+			log := testutil.NopLogger()
+			client := whatsapp.NewWithTimeout(log, 5*time.Second)
+			client.HTTPClient = &http.Client{
+				Transport: &testServerTransport{serverURL: server.URL},
+			}
+
+			account := &whatsapp.Account{
+				PhoneID:     "123456789",
+				BusinessID:  "987654321",
+				APIVersion:  "v21.0",
+				AccessToken: "test-token",
+			}
+			ctx := testutil.TestContext(t)
+
+			msgID, err := client.SendInteractiveList(ctx, account, "1234567890", "What would you like?", tt.buttonText, tt.sections)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "wamid.list123", msgID)
+
+			interactive := capturedBody["interactive"].(map[string]interface{})
+			assert.Equal(t, "list", interactive["type"])
+
+			action := interactive["action"].(map[string]interface{})
+			assert.Equal(t, tt.buttonText, action["button"])
+			sections := action["sections"].([]interface{})
+			require.Len(t, sections, 2)
+			first := sections[0].(map[string]interface{})
+			assert.Equal(t, "Pizza", first["title"])
+			row := first["rows"].([]interface{})[0].(map[string]interface{})
+			assert.Equal(t, "margherita", row["id"])
+			assert.Equal(t, "Tomato and mozzarella", row["description"])
+		})
+	}
+}
+
 func TestClient_SendTemplateMessage_WithComponents(t *testing.T) {
 	t.Parallel()
 
@@ -462,3 +567,19 @@ func TestClient_SendTemplateMessage_WithComponents(t *testing.T) {
 	assert.Len(t, sentComponents, 2)
 }
 
+
+func TestHeaderParamsToComponent(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, whatsapp.HeaderParamsToComponent(nil))
+
+	component := whatsapp.HeaderParamsToComponent(map[string]string{"order_id": "ORD-42"})
+	require.NotNil(t, component)
+	assert.Equal(t, "header", component["type"])
+
+	params := component["parameters"].([]map[string]interface{})
+	require.Len(t, params, 1)
+	assert.Equal(t, "text", params[0]["type"])
+	assert.Equal(t, "ORD-42", params[0]["text"])
+	assert.Equal(t, "order_id", params[0]["parameter_name"])
+}
@@ -19,6 +19,19 @@ type Button struct {
 	URL   string `json:"url,omitempty"`  // URL for type="url" buttons
 }
 
+// ListSection represents a group of rows in an interactive list message
+type ListSection struct {
+	Title string    `json:"title,omitempty"` // Required when there is more than one section
+	Rows  []ListRow `json:"rows"`
+}
+
+// ListRow represents a selectable row in an interactive list message
+type ListRow struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
 // MetaAPIResponse represents a successful API response from Meta
 type MetaAPIResponse struct {
 	Messages []struct {
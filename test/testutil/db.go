@@ -90,23 +90,34 @@ func runMigrations(db *gorm.DB) error {
 		&models.APIKey{},
 		&models.SSOProvider{},
 		&models.Webhook{},
+		&models.WebhookDelivery{},
 		&models.CustomAction{},
 		&models.UserAvailabilityLog{},
+		&models.UserSchedule{},
+		&models.PasswordResetToken{},
 		// WhatsApp models
 		&models.WhatsAppAccount{},
 		&models.Contact{},
+		&models.ContactAssignment{},
 		&models.Tag{},
+		&models.ContactTagOperation{},
+		&models.ContactCustomField{},
+		&models.PhoneBlacklist{},
 		&models.Message{},
+		&models.ScheduledMessage{},
 		&models.Template{},
 		&models.WhatsAppFlow{},
 		// Chatbot models
 		&models.ChatbotSettings{},
 		&models.KeywordRule{},
+		&models.KeywordRuleHit{},
 		&models.ChatbotFlow{},
 		&models.ChatbotFlowStep{},
 		&models.ChatbotSession{},
 		&models.ChatbotSessionMessage{},
+		&models.FlowWebhookDelivery{},
 		&models.AIContext{},
+		&models.AIUsage{},
 		&models.AgentTransfer{},
 		// Bulk message models
 		&models.BulkMessageCampaign{},
@@ -117,6 +128,8 @@ func runMigrations(db *gorm.DB) error {
 		&models.CatalogProduct{},
 		// Canned responses
 		&models.CannedResponse{},
+		// Saved inbox views
+		&models.SavedView{},
 		// Dashboard
 		&models.Widget{},
 	)
@@ -131,6 +144,8 @@ func cleanupTables(db *gorm.DB) {
 		// Catalog tables
 		"catalog_products",
 		"catalogs",
+		// Saved inbox views
+		"saved_views",
 		// Canned responses
 		"canned_responses",
 		// Bulk message tables
@@ -138,17 +153,26 @@ func cleanupTables(db *gorm.DB) {
 		"bulk_message_campaigns",
 		"notification_rules",
 		// Chatbot tables
+		"flow_webhook_deliveries",
 		"chatbot_session_messages",
 		"chatbot_sessions",
 		"chatbot_flow_steps",
 		"chatbot_flows",
+		"keyword_rule_hits",
 		"keyword_rules",
+		"chatbot_settings_overrides",
 		"chatbot_settings",
+		"ai_usage",
 		"ai_contexts",
 		"agent_transfers",
 		// WhatsApp tables
+		"scheduled_messages",
 		"messages",
+		"contact_assignments",
 		"tags",
+		"contact_tag_operations",
+		"contact_custom_fields",
+		"phone_blacklist",
 		"contacts",
 		"templates",
 		"whatsapp_flows",
@@ -162,9 +186,12 @@ func cleanupTables(db *gorm.DB) {
 		"teams",
 		"api_keys",
 		"sso_providers",
+		"webhook_deliveries",
 		"webhooks",
 		"custom_actions",
 		"user_availability_logs",
+		"user_schedules",
+		"password_reset_tokens",
 		"user_organizations",
 		"users",
 		"organizations",
@@ -181,20 +208,29 @@ func TruncateTables(db *gorm.DB) {
 		"widgets",
 		"catalog_products",
 		"catalogs",
+		"saved_views",
 		"canned_responses",
 		"bulk_message_recipients",
 		"bulk_message_campaigns",
 		"notification_rules",
+		"flow_webhook_deliveries",
 		"chatbot_session_messages",
 		"chatbot_sessions",
 		"chatbot_flow_steps",
 		"chatbot_flows",
+		"keyword_rule_hits",
 		"keyword_rules",
+		"chatbot_settings_overrides",
 		"chatbot_settings",
+		"ai_usage",
 		"ai_contexts",
 		"agent_transfers",
 		"messages",
+		"contact_assignments",
 		"tags",
+		"contact_tag_operations",
+		"contact_custom_fields",
+		"phone_blacklist",
 		"contacts",
 		"templates",
 		"whatsapp_flows",
@@ -206,9 +242,12 @@ func TruncateTables(db *gorm.DB) {
 		"teams",
 		"api_keys",
 		"sso_providers",
+		"webhook_deliveries",
 		"webhooks",
 		"custom_actions",
 		"user_availability_logs",
+		"user_schedules",
+		"password_reset_tokens",
 		"user_organizations",
 		"users",
 		"organizations",
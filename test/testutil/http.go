@@ -1,8 +1,10 @@
 package testutil
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -27,6 +29,30 @@ func NewJSONRequest(t *testing.T, body any) *fastglue.Request {
 	return &fastglue.Request{RequestCtx: ctx}
 }
 
+// NewMultipartRequest creates a fastglue POST request with a multipart form
+// body holding the given fields and a single file under fileField.
+func NewMultipartRequest(t *testing.T, fields map[string]string, fileField, fileName string, content []byte) *fastglue.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		require.NoError(t, w.WriteField(k, v), "failed to write form field")
+	}
+	part, err := w.CreateFormFile(fileField, fileName)
+	require.NoError(t, err, "failed to create form file")
+	_, err = part.Write(content)
+	require.NoError(t, err, "failed to write form file")
+	require.NoError(t, w.Close(), "failed to close multipart writer")
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod("POST")
+	ctx.Request.Header.SetContentType(w.FormDataContentType())
+	ctx.Request.SetBody(buf.Bytes())
+
+	return &fastglue.Request{RequestCtx: ctx}
+}
+
 // NewGETRequest creates a fastglue GET request for testing.
 func NewGETRequest(t *testing.T) *fastglue.Request {
 	t.Helper()